@@ -5,12 +5,25 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/config"
+	"github.com/feiyuluoye/Go-Model/pkg/gomodel"
+)
+
+// Command-line flags, all registered before flag.Parse so that every mode accepts them
+var (
+	mode       = flag.String("mode", "cli", "Execution mode: cli or grpc")
+	configFile = flag.String("config", "configs/config.yaml", "Configuration file path")
+	modelType  = flag.String("model", "ols", "Model type: ols, ridge, lasso, logistic")
+	dataFile   = flag.String("data", "", "Data file path")
+	action     = flag.String("action", "train", "Action to perform: train, predict, evaluate, info")
+	target     = flag.String("target", "target", "Name of the target column in the data file")
 )
 
 func main() {
-	mode := flag.String("mode", "cli", "Execution mode: cli or grpc")
 	flag.Parse()
 
 	switch *mode {
@@ -35,14 +48,6 @@ func runServer() {
 }
 
 func runCLI() {
-	// Command-line argument parsing
-	configFile := flag.String("config", "configs/config.yaml", "Configuration file path")
-	modelType := flag.String("model", "ols", "Model type: ols, ridge, lasso, logistic")
-	dataFile := flag.String("data", "", "Data file path")
-	action := flag.String("action", "train", "Action to perform: train, predict, evaluate, info")
-
-	flag.Parse()
-
 	// Load configuration
 	cfg, err := config.Load(*configFile)
 	if err != nil {
@@ -61,7 +66,7 @@ func runCLI() {
 	case "predict":
 		runPredict(ctx, cfg, *modelType, *dataFile)
 	case "evaluate":
-		runEvaluate(ctx, cfg, *modelType, *dataFile)
+		runEvaluate(ctx, cfg, *modelType, *dataFile, *target)
 	case "info":
 		runModelInfo(ctx, cfg, *modelType)
 	default:
@@ -81,9 +86,78 @@ func runPredict(ctx context.Context, cfg *config.Config, modelType, dataFile str
 	fmt.Println("Prediction functionality not implemented")
 }
 
-func runEvaluate(ctx context.Context, cfg *config.Config, modelType, dataFile string) {
+// runEvaluate trains the model on 80% of the data and evaluates it on the remaining 20%;
+// classifiers additionally print the confusion matrix with per-class metrics
+func runEvaluate(ctx context.Context, cfg *config.Config, modelType, dataFile, target string) {
 	fmt.Printf("Evaluating %s model...\n", modelType)
-	fmt.Println("Evaluation functionality not implemented")
+	if dataFile == "" {
+		fmt.Println("A data file is required: -data data.csv")
+		os.Exit(1)
+	}
+
+	dataUtils := gomodel.NewDataUtils(42)
+	data, err := dataUtils.LoadFromCSV(dataFile, target, true)
+	if err != nil {
+		log.Fatalf("Failed to load data: %v", err)
+	}
+	algorithm := gomodel.AlgorithmType(modelType)
+	trainData, testData, err := dataUtils.Split(data, gomodel.SplitOptions{
+		TestSize: 0.2,
+		Shuffle:  true,
+		Stratify: algorithm == gomodel.Logistic,
+	})
+	if err != nil {
+		log.Fatalf("Failed to split data: %v", err)
+	}
+
+	client := gomodel.NewClient(nil)
+	result, err := client.Train(trainData, gomodel.GetDefaultConfig(algorithm))
+	if err != nil {
+		log.Fatalf("Training failed: %v", err)
+	}
+	fmt.Printf("Training score: %.4f\n", result.TrainingScore)
+
+	if algorithm != gomodel.Logistic {
+		prediction, err := client.Predict(result.ModelID, testData.Features)
+		if err != nil {
+			log.Fatalf("Evaluation failed: %v", err)
+		}
+		var sse, sst, mean float64
+		n := testData.Target.Len()
+		for i := 0; i < n; i++ {
+			mean += testData.Target.AtVec(i) / float64(n)
+		}
+		for i, p := range prediction.Predictions {
+			y := testData.Target.AtVec(i)
+			sse += (y - p) * (y - p)
+			sst += (y - mean) * (y - mean)
+		}
+		fmt.Printf("Test R2: %.4f  RMSE: %.4f\n", 1-sse/sst, math.Sqrt(sse/float64(n)))
+		return
+	}
+
+	matrix, err := client.ConfusionMatrix(result.ModelID, testData, 0.5)
+	if err != nil {
+		log.Fatalf("Evaluation failed: %v", err)
+	}
+	fmt.Printf("Confusion matrix on %d test samples:\n", testData.Target.Len())
+	fmt.Print(matrix)
+
+	roc, err := client.ROCCurve(result.ModelID, testData)
+	if err != nil {
+		log.Printf("ROC curve unavailable: %v", err)
+		return
+	}
+	threshold, tpr, fpr := roc.YoudenThreshold()
+	fmt.Printf("ROC AUC: %.4f  best threshold: %.4f (TPR %.4f, FPR %.4f)\n", roc.AUC, threshold, tpr, fpr)
+
+	pr, err := client.PrecisionRecallCurve(result.ModelID, testData)
+	if err != nil {
+		log.Printf("Precision-recall curve unavailable: %v", err)
+		return
+	}
+	threshold, precision, recall := pr.BestF1Threshold()
+	fmt.Printf("Average precision: %.4f  best F1 threshold: %.4f (precision %.4f, recall %.4f)\n", pr.AveragePrecision, threshold, precision, recall)
 }
 
 func runModelInfo(ctx context.Context, cfg *config.Config, modelType string) {
@@ -104,9 +178,11 @@ func printUsage() {
 	fmt.Println("  -model string     Model type: ols, ridge, lasso, logistic (default \"ols\")")
 	fmt.Println("  -data string      Data file path")
 	fmt.Println("  -action string    Action to perform: train, predict, evaluate, info (default \"train\")")
+	fmt.Println("  -target string    Name of the target column in the data file (default \"target\")")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  Start server: go run cmd/main.go -mode grpc")
 	fmt.Println("  Train model: go run cmd/main.go -model ols -data data.csv -action train")
 	fmt.Println("  Make prediction: go run cmd/main.go -model ols -data test.csv -action predict")
+	fmt.Println("  Evaluate classifier: go run cmd/main.go -model logistic -data data.csv -target label -action evaluate")
 }
@@ -19,16 +19,16 @@ func main() {
 	XData := make([]float64, n)
 	yData := make([]float64, n)
 
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	
 	for i := 0; i < n; i++ {
-		x := rand.Float64()*4 - 1 // [-1, 3] 避免指数增长过快
+		x := rng.Float64()*4 - 1 // [-1, 3] 避免指数增长过快
 		XData[i] = x
 		
 		// 真实指数关系: y = 2 * exp(0.5 * x)
 		y_true := 2 * math.Exp(0.5*x)
 		// 添加相对噪声（避免绝对噪声在大值时影响过大）
-		noise := rand.NormFloat64() * y_true * 0.1
+		noise := rng.NormFloat64() * y_true * 0.1
 		yData[i] = y_true + noise
 		
 		// 确保y值为正（指数回归要求）
@@ -19,20 +19,20 @@ func main() {
 	XData := make([]float64, n*p)
 	yData := make([]float64, n)
 
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// 真实系数：只有前3个特征有用，其余为0
 	trueCoeffs := []float64{2.0, -1.5, 3.0, 0, 0, 0, 0, 0, 0, 0}
 
 	for i := 0; i < n; i++ {
 		var y_true float64
 		for j := 0; j < p; j++ {
-			x := rand.NormFloat64()
+			x := rng.NormFloat64()
 			XData[i*p+j] = x
 			y_true += trueCoeffs[j] * x
 		}
 		
 		// 添加噪声
-		noise := rand.NormFloat64() * 0.5
+		noise := rng.NormFloat64() * 0.5
 		yData[i] = y_true + noise
 	}
 
@@ -19,15 +19,15 @@ func main() {
 	XData := make([]float64, n)
 	yData := make([]float64, n)
 
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	
 	for i := 0; i < n; i++ {
-		x := rand.Float64()*9 + 1 // [1, 10] 确保x > 0
+		x := rng.Float64()*9 + 1 // [1, 10] 确保x > 0
 		XData[i] = x
 		
 		// 真实对数关系: y = 3 * ln(x) + 2
 		y_true := 3*math.Log(x) + 2
-		noise := rand.NormFloat64() * 0.5
+		noise := rng.NormFloat64() * 0.5
 		yData[i] = y_true + noise
 	}
 
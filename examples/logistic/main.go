@@ -21,11 +21,11 @@ func main() {
 	XData := make([]float64, n*p)
 	yData := make([]float64, n)
 
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	
 	for i := 0; i < n; i++ {
-		x1 := rand.Float64()*6 - 3 // [-3, 3]
-		x2 := rand.Float64()*6 - 3 // [-3, 3]
+		x1 := rng.Float64()*6 - 3 // [-3, 3]
+		x2 := rng.Float64()*6 - 3 // [-3, 3]
 		
 		XData[i*p] = x1
 		XData[i*p+1] = x2
@@ -35,7 +35,7 @@ func main() {
 		prob := 1.0 / (1.0 + math.Exp(-logit))
 		
 		// 根据概率生成标签
-		if rand.Float64() < prob {
+		if rng.Float64() < prob {
 			yData[i] = 1.0
 		} else {
 			yData[i] = 0.0
@@ -20,16 +20,16 @@ func main() {
 	XData := make([]float64, n*p)
 	yData := make([]float64, n)
 
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	for i := 0; i < n; i++ {
-		x1 := rand.Float64()*10 - 5 // [-5, 5]
-		x2 := rand.Float64()*10 - 5 // [-5, 5]
+		x1 := rng.Float64()*10 - 5 // [-5, 5]
+		x2 := rng.Float64()*10 - 5 // [-5, 5]
 		
 		XData[i*p] = x1
 		XData[i*p+1] = x2
 		
 		// 真实关系：y = 2*x1 + 3*x2 + 1 + noise
-		noise := rand.NormFloat64() * 0.5
+		noise := rng.NormFloat64() * 0.5
 		yData[i] = 2*x1 + 3*x2 + 1 + noise
 	}
 
@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -253,7 +255,7 @@ func classificationExample() {
 	}
 
 	// 分割训练测试集
-	trainData, testData, err := dataUtils.SplitTrainTest(normalizedData, 0.3, true)
+	trainData, testData, err := dataUtils.SplitTrainTest(normalizedData, 0.3, true, false)
 	if err != nil {
 		log.Printf("数据分割失败: %v", err)
 		return
@@ -298,6 +300,6 @@ func classificationExample() {
 		trainData.Target.Len(), testData.Target.Len())
 
 	// 显示数据摘要
-	summary := dataUtils.GetDataSummary(classData)
-	fmt.Printf("数据摘要: %d样本, %d特征\n", summary["samples"], summary["features"])
+	summary, _ := dataUtils.GetDataSummary(classData)
+	fmt.Printf("数据摘要: %d样本, %d特征\n", summary.Samples, summary.Features)
 }
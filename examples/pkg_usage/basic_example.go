@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -95,7 +97,7 @@ func fullAPIExample() {
 	}
 
 	// 分割训练测试集
-	trainData, testData, err := dataUtils.SplitTrainTest(normalizedData, 0.2, true)
+	trainData, testData, err := dataUtils.SplitTrainTest(normalizedData, 0.2, true, false)
 	if err != nil {
 		log.Printf("数据分割失败: %v", err)
 		return
@@ -158,8 +160,8 @@ func dataProcessingExample() {
 	}
 
 	// 获取数据摘要
-	summary := dataUtils.GetDataSummary(data)
-	fmt.Printf("数据形状: %d样本, %d特征\n", summary["samples"], summary["features"])
+	summary, _ := dataUtils.GetDataSummary(data)
+	fmt.Printf("数据形状: %d样本, %d特征\n", summary.Samples, summary.Features)
 
 	// 标准化数据
 	normalizedData, err := dataUtils.Normalize(data)
@@ -19,28 +19,28 @@ func main() {
 	XData := make([]float64, n*p)
 	yData := make([]float64, n)
 
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	
 	// 创建潜在变量
 	for i := 0; i < n; i++ {
 		// 两个潜在因子
-		factor1 := rand.NormFloat64()
-		factor2 := rand.NormFloat64()
+		factor1 := rng.NormFloat64()
+		factor2 := rng.NormFloat64()
 		
 		// y主要由这两个因子决定
-		yData[i] = 2*factor1 + 1.5*factor2 + rand.NormFloat64()*0.3
+		yData[i] = 2*factor1 + 1.5*factor2 + rng.NormFloat64()*0.3
 		
 		// X的特征是这些因子的线性组合加噪声
 		for j := 0; j < p; j++ {
 			if j < 8 {
 				// 前8个特征主要与factor1相关
-				XData[i*p+j] = factor1 + rand.NormFloat64()*0.5
+				XData[i*p+j] = factor1 + rng.NormFloat64()*0.5
 			} else if j < 16 {
 				// 中间8个特征主要与factor2相关
-				XData[i*p+j] = factor2 + rand.NormFloat64()*0.5
+				XData[i*p+j] = factor2 + rng.NormFloat64()*0.5
 			} else {
 				// 最后4个特征是噪声
-				XData[i*p+j] = rand.NormFloat64()
+				XData[i*p+j] = rng.NormFloat64()
 			}
 		}
 	}
@@ -79,7 +79,7 @@ func main() {
 	
 	// 创建测试数据
 	testData := make([]float64, 3*p)
-	rand.Seed(123)
+	rng = rand.New(rand.NewSource(123))
 	
 	for i := 0; i < 3; i++ {
 		factor1 := float64(i-1) // -1, 0, 1
@@ -87,11 +87,11 @@ func main() {
 		
 		for j := 0; j < p; j++ {
 			if j < 8 {
-				testData[i*p+j] = factor1 + rand.NormFloat64()*0.1
+				testData[i*p+j] = factor1 + rng.NormFloat64()*0.1
 			} else if j < 16 {
-				testData[i*p+j] = factor2 + rand.NormFloat64()*0.1
+				testData[i*p+j] = factor2 + rng.NormFloat64()*0.1
 			} else {
-				testData[i*p+j] = rand.NormFloat64()*0.1
+				testData[i*p+j] = rng.NormFloat64()*0.1
 			}
 		}
 	}
@@ -19,15 +19,15 @@ func main() {
 	XData := make([]float64, n)
 	yData := make([]float64, n)
 
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	
 	for i := 0; i < n; i++ {
-		x := rand.Float64()*6 - 3 // [-3, 3]
+		x := rng.Float64()*6 - 3 // [-3, 3]
 		XData[i] = x
 		
 		// 真实多项式: y = 2 + 3*x - 0.5*x^2 + 0.1*x^3
 		y_true := 2 + 3*x - 0.5*x*x + 0.1*x*x*x
-		noise := rand.NormFloat64() * 0.5
+		noise := rng.NormFloat64() * 0.5
 		yData[i] = y_true + noise
 	}
 
@@ -19,16 +19,16 @@ func main() {
 	XData := make([]float64, n)
 	yData := make([]float64, n)
 
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	
 	for i := 0; i < n; i++ {
-		x := rand.Float64()*4 + 0.5 // [0.5, 4.5] 确保x > 0
+		x := rng.Float64()*4 + 0.5 // [0.5, 4.5] 确保x > 0
 		XData[i] = x
 		
 		// 真实幂函数关系: y = 2 * x^1.5
 		y_true := 2 * math.Pow(x, 1.5)
 		// 添加相对噪声
-		noise := rand.NormFloat64() * y_true * 0.1
+		noise := rng.NormFloat64() * y_true * 0.1
 		yData[i] = y_true + noise
 		
 		// 确保y值为正（幂回归要求）
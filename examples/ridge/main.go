@@ -19,14 +19,14 @@ func main() {
 	XData := make([]float64, n*p)
 	yData := make([]float64, n)
 
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	for i := 0; i < n; i++ {
 		// 创建相关特征来演示Ridge的正则化效果
-		x1 := rand.Float64()*4 - 2
-		x2 := x1 + rand.NormFloat64()*0.1 // x2与x1高度相关
-		x3 := rand.Float64()*4 - 2
-		x4 := x3 + rand.NormFloat64()*0.1 // x4与x3高度相关
-		x5 := rand.Float64()*4 - 2
+		x1 := rng.Float64()*4 - 2
+		x2 := x1 + rng.NormFloat64()*0.1 // x2与x1高度相关
+		x3 := rng.Float64()*4 - 2
+		x4 := x3 + rng.NormFloat64()*0.1 // x4与x3高度相关
+		x5 := rng.Float64()*4 - 2
 
 		XData[i*p] = x1
 		XData[i*p+1] = x2
@@ -35,7 +35,7 @@ func main() {
 		XData[i*p+4] = x5
 
 		// 真实关系：y = 1*x1 + 1*x2 + 2*x3 + 2*x4 + 0.5*x5 + noise
-		noise := rand.NormFloat64() * 0.5
+		noise := rng.NormFloat64() * 0.5
 		yData[i] = x1 + x2 + 2*x3 + 2*x4 + 0.5*x5 + noise
 	}
 
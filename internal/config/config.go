@@ -0,0 +1,190 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Config 命令行工具和gRPC服务的配置，对应configs/config.yaml
+type Config struct {
+	GRPC          GRPCConfig
+	Database      DatabaseConfig
+	Logging       LoggingConfig
+	ModelDefaults map[string]map[string]string // 各模型的默认参数，键为模型类型
+}
+
+// GRPCConfig gRPC服务配置
+type GRPCConfig struct {
+	Address string
+	Port    int
+	Timeout int // 秒
+}
+
+// DatabaseConfig 数据库配置
+type DatabaseConfig struct {
+	Host     string
+	Port     int
+	Name     string
+	User     string
+	Password string
+}
+
+// LoggingConfig 日志配置
+type LoggingConfig struct {
+	Level string
+	File  string
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		GRPC:          GRPCConfig{Address: "localhost", Port: 50051, Timeout: 30},
+		Database:      DatabaseConfig{Host: "localhost", Port: 5432},
+		Logging:       LoggingConfig{Level: "info"},
+		ModelDefaults: map[string]map[string]string{},
+	}
+}
+
+// Load 读取配置文件，文件中未出现的字段保留默认值。
+// 只支持配置文件用到的YAML子集：以空格缩进的嵌套映射、标量值（可加引号）和#注释
+func Load(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values, err := parse(bufio.NewScanner(file))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	cfg := DefaultConfig()
+	for key, value := range values {
+		section, field, _ := strings.Cut(key, ".")
+		switch section {
+		case "grpc":
+			err = setField(map[string]interface{}{
+				"address": &cfg.GRPC.Address, "port": &cfg.GRPC.Port, "timeout": &cfg.GRPC.Timeout,
+			}, field, key, value)
+		case "database":
+			err = setField(map[string]interface{}{
+				"host": &cfg.Database.Host, "port": &cfg.Database.Port, "name": &cfg.Database.Name,
+				"user": &cfg.Database.User, "password": &cfg.Database.Password,
+			}, field, key, value)
+		case "logging":
+			err = setField(map[string]interface{}{
+				"level": &cfg.Logging.Level, "file": &cfg.Logging.File,
+			}, field, key, value)
+		case "model_defaults":
+			model, param, ok := strings.Cut(field, ".")
+			if !ok || strings.Contains(param, ".") {
+				return nil, fmt.Errorf("%s: invalid model default %q", path, key)
+			}
+			if cfg.ModelDefaults[model] == nil {
+				cfg.ModelDefaults[model] = map[string]string{}
+			}
+			cfg.ModelDefaults[model][param] = value
+		}
+		// 未知的配置节忽略，便于新旧版本共用配置文件
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// setField 将配置项key的值写入字段表中名为field的字段，未知字段忽略
+func setField(fields map[string]interface{}, field, key, value string) error {
+	switch target := fields[field].(type) {
+	case *string:
+		*target = value
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer, got %q", key, value)
+		}
+		*target = n
+	}
+	return nil
+}
+
+// parse 将嵌套映射展开为以"."连接的键到标量值的映射
+func parse(scanner *bufio.Scanner) (map[string]string, error) {
+	type level struct {
+		indent int
+		key    string
+	}
+	values := make(map[string]string)
+	var stack []level
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := stripComment(scanner.Text())
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNo)
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		path := key
+		if len(stack) > 0 {
+			path = stack[len(stack)-1].key + "." + key
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			stack = append(stack, level{indent: indent, key: path})
+			continue
+		}
+		unquoted, err := unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		values[path] = unquoted
+	}
+	return values, scanner.Err()
+}
+
+// stripComment 去掉引号外的#注释
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(value string) (string, error) {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if value[len(value)-1] != value[0] {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		if value[0] == '"' {
+			return strconv.Unquote(value)
+		}
+		return value[1 : len(value)-1], nil
+	}
+	return value, nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRepositoryConfig(t *testing.T) {
+	cfg, err := Load("../../configs/config.yaml")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GRPC.Address != "localhost" || cfg.GRPC.Port != 50051 || cfg.GRPC.Timeout != 30 {
+		t.Errorf("grpc = %+v", cfg.GRPC)
+	}
+	if cfg.Database.Name != "regression_db" || cfg.Database.Port != 5432 {
+		t.Errorf("database = %+v", cfg.Database)
+	}
+	if cfg.Logging.File != "logs/app.log" {
+		t.Errorf("logging = %+v", cfg.Logging)
+	}
+	if cfg.ModelDefaults["ridge"]["alpha"] != "1.0" || cfg.ModelDefaults["logistic"]["max_iter"] != "100" {
+		t.Errorf("model defaults = %v", cfg.ModelDefaults)
+	}
+}
+
+func TestLoadKeepsDefaultsAndStripsComments(t *testing.T) {
+	path := writeConfig(t, `
+# comment
+grpc:
+  port: 9000   # trailing comment
+logging:
+  file: "logs/#1.log"
+unknown:
+  key: value
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GRPC.Port != 9000 || cfg.GRPC.Address != "localhost" || cfg.GRPC.Timeout != 30 {
+		t.Errorf("grpc = %+v", cfg.GRPC)
+	}
+	if cfg.Logging.File != "logs/#1.log" || cfg.Logging.Level != "info" {
+		t.Errorf("logging = %+v", cfg.Logging)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := map[string]string{
+		"non-integer port": "grpc:\n  port: abc\n",
+		"missing colon":    "grpc\n",
+		"unterminated":     "logging:\n  file: \"logs\n",
+		"nested default":   "model_defaults:\n  ridge:\n    solver:\n      name: x\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, content)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "missing.yaml") {
+		t.Errorf("missing file error = %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package data
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// LoadCSVCategorical 从CSV文件加载包含类别（字符串）特征的数据。
+// 类别特征按标签编码为整数码（即标签在类别表中的下标），空白值和无效的数值特征记为NaN；
+// 目标列含有非数值内容时同样按标签编码（用于分类），类别表以目标列名为键，空白目标值的行被跳过
+// columns: 类别特征列名，为空时自动识别含有非数值内容的列
+// categories: 已有的类别表（如训练数据的类别表），已有标签沿用原编码、新标签追加在末尾，
+// 使训练数据和预测数据的编码保持一致；为nil时从头建立
+// 返回数据集和每个类别特征（及类别目标）的类别表
+func LoadCSVCategorical(filePath string, hasHeader bool, targetColumn interface{}, columns []string, categories map[string][]string) (*types.Dataset, map[string][]string, error) {
+	file, err := openDataFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取CSV文件失败: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("CSV文件为空")
+	}
+
+	header := records[0]
+	targetIndex, err := targetColumnIndex(header, hasHeader, targetColumn)
+	if err != nil {
+		return nil, nil, err
+	}
+	start := 0
+	if hasHeader {
+		start = 1
+	}
+	targetName := "target"
+	if hasHeader {
+		targetName = strings.TrimSpace(header[targetIndex])
+	}
+	categoricalTarget := false
+	for _, record := range records[start:] {
+		value := strings.TrimSpace(record[targetIndex])
+		if _, err := strconv.ParseFloat(value, 64); value != "" && err != nil {
+			categoricalTarget = true
+			break
+		}
+	}
+
+	// 特征列在记录中的位置和名称
+	var positions []int
+	var featureNames []string
+	for i, name := range header {
+		if i == targetIndex {
+			continue
+		}
+		if hasHeader {
+			name = strings.TrimSpace(name)
+		} else {
+			name = fmt.Sprintf("feature_%d", len(featureNames))
+		}
+		positions = append(positions, i)
+		featureNames = append(featureNames, name)
+	}
+
+	categorical := make([]bool, len(featureNames))
+	if len(columns) > 0 {
+		index := make(map[string]int, len(featureNames))
+		for j, name := range featureNames {
+			index[name] = j
+		}
+		for _, column := range columns {
+			j, ok := index[column]
+			if !ok {
+				return nil, nil, fmt.Errorf("未找到类别列: %s", column)
+			}
+			categorical[j] = true
+		}
+	} else {
+		for _, record := range records[start:] {
+			for j, pos := range positions {
+				if categorical[j] || pos >= len(record) {
+					continue
+				}
+				value := strings.TrimSpace(record[pos])
+				if _, err := strconv.ParseFloat(value, 64); value != "" && err != nil {
+					categorical[j] = true
+				}
+			}
+		}
+	}
+
+	// 复制已有类别表，新标签追加在末尾
+	labels := make(map[string][]string)
+	codes := make(map[string]map[string]int)
+	for j, name := range featureNames {
+		if !categorical[j] {
+			continue
+		}
+		labels[name] = append([]string(nil), categories[name]...)
+		codes[name] = make(map[string]int, len(labels[name]))
+		for code, label := range labels[name] {
+			codes[name][label] = code
+		}
+	}
+	if categoricalTarget {
+		labels[targetName] = append([]string(nil), categories[targetName]...)
+		codes[targetName] = make(map[string]int, len(labels[targetName]))
+		for code, label := range labels[targetName] {
+			codes[targetName][label] = code
+		}
+	}
+	// encode 返回标签的类别码，新标签追加到类别表末尾
+	encode := func(name, label string) float64 {
+		code, ok := codes[name][label]
+		if !ok {
+			code = len(labels[name])
+			codes[name][label] = code
+			labels[name] = append(labels[name], label)
+		}
+		return float64(code)
+	}
+
+	features := make([][]float64, 0, len(records)-start)
+	target := make([]float64, 0, len(records)-start)
+	for i, record := range records[start:] {
+		var value float64
+		if cell := strings.TrimSpace(record[targetIndex]); categoricalTarget {
+			if cell == "" {
+				log.Printf("警告: 行 %d 的目标值为空，跳过此行", i)
+				continue
+			}
+			value = encode(targetName, cell)
+		} else if value, err = strconv.ParseFloat(cell, 64); err != nil {
+			log.Printf("警告: 行 %d 的目标值 '%s' 不是有效数字，跳过此行", i, record[targetIndex])
+			continue
+		}
+
+		row := make([]float64, len(positions))
+		for j, pos := range positions {
+			cell := ""
+			if pos < len(record) {
+				cell = strings.TrimSpace(record[pos])
+			}
+			if cell == "" {
+				row[j] = math.NaN()
+				continue
+			}
+			if !categorical[j] {
+				if row[j], err = strconv.ParseFloat(cell, 64); err != nil {
+					row[j] = math.NaN()
+				}
+				continue
+			}
+			row[j] = encode(featureNames[j], cell)
+		}
+		features = append(features, row)
+		target = append(target, value)
+	}
+
+	if len(features) == 0 {
+		return nil, nil, errors.New("CSV文件中没有有效的数据行")
+	}
+	dataset := types.NewDataset(features, target, featureNames)
+	dataset.TargetName = targetName
+	return dataset, labels, nil
+}
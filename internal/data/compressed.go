@@ -0,0 +1,85 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// compressionExtensions 压缩文件扩展名对应的压缩格式
+var compressionExtensions = map[string]string{
+	".gz":   "gzip",
+	".gzip": "gzip",
+	".zst":  "zstd",
+	".zstd": "zstd",
+	".bz2":  "bzip2",
+}
+
+// compressedFile 解压后的文件内容，Close时关闭底层文件
+type compressedFile struct {
+	io.Reader
+	file *os.File
+}
+
+// Close 关闭底层文件
+func (f *compressedFile) Close() error {
+	return f.file.Close()
+}
+
+// openDataFile 打开数据文件，根据文件头的魔数识别gzip、zstd和bzip2压缩并透明解压，
+// 未压缩的文件按原样读取；扩展名表明是压缩文件但内容不匹配时返回错误
+func openDataFile(filePath string) (io.ReadCloser, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开文件: %w", err)
+	}
+
+	reader, err := decompressReader(file, filePath)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &compressedFile{Reader: reader, file: file}, nil
+}
+
+// decompressReader 按魔数识别压缩格式并返回解压后的reader，name用于按扩展名校验格式
+func decompressReader(r io.Reader, name string) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("读取数据失败: %w", err)
+	}
+
+	format := ""
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		format = "gzip"
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		format = "zstd"
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		format = "bzip2"
+	}
+	if expected, ok := compressionExtensions[strings.ToLower(path.Ext(name))]; ok && expected != format {
+		return nil, fmt.Errorf("文件扩展名表明是%s压缩，但内容不是有效的%s数据", expected, expected)
+	}
+
+	switch format {
+	case "gzip":
+		reader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("解压gzip数据失败: %w", err)
+		}
+		return reader, nil
+	case "zstd":
+		return newZstdReader(buffered), nil
+	case "bzip2":
+		return bzip2.NewReader(buffered), nil
+	}
+	return buffered, nil
+}
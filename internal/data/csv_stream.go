@@ -0,0 +1,255 @@
+package data
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// DefaultChunkSize 流式读取时每批的默认行数
+const DefaultChunkSize = 10000
+
+// CSVStream 逐行解析CSV并按批返回数据，不在内存中保留原始记录，适用于无法一次读入内存的大文件
+type CSVStream struct {
+	reader       *csv.Reader
+	closer       io.Closer
+	hasHeader    bool
+	targetColumn interface{}
+	chunkSize    int
+	targetIndex  int
+	featureNames []string
+	line         int      // 已读取的行数（含表头），用于告警信息
+	pending      []string // 解析表头时预读的首条数据记录
+}
+
+// NewCSVStream 从reader创建流式CSV解析器
+// hasHeader: 是否包含表头
+// targetColumn: 目标变量列名或索引
+// chunkSize: 每批的行数，不大于0时使用DefaultChunkSize
+func NewCSVStream(r io.Reader, hasHeader bool, targetColumn interface{}, chunkSize int) (*CSVStream, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	s := &CSVStream{
+		reader:       reader,
+		hasHeader:    hasHeader,
+		targetColumn: targetColumn,
+		chunkSize:    chunkSize,
+	}
+	if err := s.readHeader(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// OpenCSVStream 打开CSV文件并创建流式解析器，使用完毕后需要调用Close
+func OpenCSVStream(filePath string, hasHeader bool, targetColumn interface{}, chunkSize int) (*CSVStream, error) {
+	file, err := openDataFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := NewCSVStream(file, hasHeader, targetColumn, chunkSize)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	s.closer = file
+	return s, nil
+}
+
+// readHeader 读取表头（或首行）并确定目标列的索引和特征名
+func (s *CSVStream) readHeader() error {
+	first, err := s.reader.Read()
+	if err == io.EOF {
+		return errors.New("CSV文件为空")
+	}
+	if err != nil {
+		return fmt.Errorf("读取CSV文件失败: %w", err)
+	}
+	s.line = 1
+	columns := append([]string(nil), first...)
+
+	s.targetIndex = -1
+	switch v := s.targetColumn.(type) {
+	case string:
+		if !s.hasHeader {
+			return errors.New("当目标列是名称时，文件必须包含表头")
+		}
+		for i, name := range columns {
+			if name == v {
+				s.targetIndex = i
+				break
+			}
+		}
+		if s.targetIndex == -1 {
+			return fmt.Errorf("未找到目标列: %s", v)
+		}
+	case int:
+		if v < 0 || v >= len(columns) {
+			return errors.New("目标列索引超出范围")
+		}
+		s.targetIndex = v
+	default:
+		return errors.New("目标列参数类型必须是string或int")
+	}
+
+	s.featureNames = make([]string, 0, len(columns)-1)
+	for i, name := range columns {
+		if i == s.targetIndex {
+			continue
+		}
+		if !s.hasHeader {
+			name = fmt.Sprintf("feature_%d", len(s.featureNames))
+		}
+		s.featureNames = append(s.featureNames, name)
+	}
+	if !s.hasHeader {
+		s.pending = columns
+	}
+	return nil
+}
+
+// FeatureNames 返回特征名（不含目标列）
+func (s *CSVStream) FeatureNames() []string {
+	return s.featureNames
+}
+
+// Next 解析并返回下一批数据，最多chunkSize行；没有更多数据时返回io.EOF。
+// 目标值无效的行会被跳过，空白或无效的特征值记为NaN，与LoadCSVWithMissing的处理方式一致
+func (s *CSVStream) Next() (*types.Dataset, error) {
+	features := make([]float64, 0, s.chunkSize*len(s.featureNames))
+	target := make([]float64, 0, s.chunkSize)
+
+	for len(target) < s.chunkSize {
+		var err error
+		features, target, err = s.appendRow(features, target)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(target) == 0 {
+		return nil, io.EOF
+	}
+	return types.NewDataset(splitRows(features, len(target), len(s.featureNames)), target, s.featureNames), nil
+}
+
+// readRecord 读取下一条记录，没有更多记录时返回io.EOF
+func (s *CSVStream) readRecord() ([]string, error) {
+	if s.pending != nil {
+		record := s.pending
+		s.pending = nil
+		return record, nil
+	}
+	record, err := s.reader.Read()
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取CSV文件失败: %w", err)
+	}
+	s.line++
+	return record, nil
+}
+
+// appendRow 解析下一条有效记录，把特征值按顺序追加到features、目标值追加到target；
+// 目标值无效的行被跳过，没有更多数据时返回io.EOF
+func (s *CSVStream) appendRow(features, target []float64) ([]float64, []float64, error) {
+	for {
+		record, err := s.readRecord()
+		if err != nil {
+			return features, target, err
+		}
+
+		value, err := strconv.ParseFloat(record[s.targetIndex], 64)
+		if err != nil {
+			log.Printf("警告: 第 %d 行的目标值 '%s' 不是有效数字，跳过此行", s.line, record[s.targetIndex])
+			continue
+		}
+
+		for j, field := range record {
+			if j == s.targetIndex {
+				continue
+			}
+			if strings.TrimSpace(field) == "" {
+				features = append(features, math.NaN())
+				continue
+			}
+			val, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				log.Printf("警告: 第 %d 行列 %d 的值 '%s' 不是有效数字，记为缺失值", s.line, j, field)
+				val = math.NaN()
+			}
+			features = append(features, val)
+		}
+		return features, append(target, value), nil
+	}
+}
+
+// splitRows 把按行优先存储的连续切片切分为rows行，各行共享底层数组且容量不越过行边界
+func splitRows(values []float64, rows, cols int) [][]float64 {
+	out := make([][]float64, rows)
+	for i := range out {
+		out[i] = values[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return out
+}
+
+// Close 关闭由OpenCSVStream打开的文件
+func (s *CSVStream) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// LoadCSVStreaming 分批解析CSV文件并构建数据集，解析规则与LoadCSVWithMissing一致，
+// 但只保留解析后的数值而不保留原始字符串记录，内存占用显著降低。
+// 所有特征值存放在一个连续数组中，数据集的各行共享该数组
+func LoadCSVStreaming(filePath string, hasHeader bool, targetColumn interface{}, chunkSize int) (*types.Dataset, error) {
+	features, target, featureNames, err := LoadCSVStreamingFlat(filePath, hasHeader, targetColumn, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewDataset(splitRows(features, len(target), len(featureNames)), target, featureNames), nil
+}
+
+// LoadCSVStreamingFlat 与LoadCSVStreaming相同，但按行优先顺序返回存放所有特征值的连续切片，
+// 可以直接交给mat.NewDense而无需再复制一份
+func LoadCSVStreamingFlat(filePath string, hasHeader bool, targetColumn interface{}, chunkSize int) ([]float64, []float64, []string, error) {
+	stream, err := OpenCSVStream(filePath, hasHeader, targetColumn, chunkSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer stream.Close()
+
+	var features, target []float64
+	for {
+		features, target, err = stream.appendRow(features, target)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if len(target) == 0 {
+		return nil, nil, nil, errors.New("CSV文件中没有有效的数据行")
+	}
+	return features, target, stream.FeatureNames(), nil
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const streamTestCSV = "x1,y,x2\n1,10,2\n3,bad,4\n,30,6\n7,40,x\n9,50,10\n"
+
+func TestCSVStreamBatches(t *testing.T) {
+	stream, err := NewCSVStream(strings.NewReader(streamTestCSV), true, "y", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sizes []int
+	var target []float64
+	for {
+		batch, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, batch.NumSamples())
+		target = append(target, batch.Target...)
+		for i, row := range batch.Features {
+			if len(row) != 2 || cap(row) != 2 {
+				t.Fatalf("row %d: len = %d, cap = %d, want 2", i, len(row), cap(row))
+			}
+		}
+	}
+	// 目标值无效的行被跳过，批大小按有效行计算
+	if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 2 {
+		t.Errorf("batch sizes = %v, want [2 2]", sizes)
+	}
+	if len(target) != 4 || target[0] != 10 || target[3] != 50 {
+		t.Errorf("target = %v, want [10 30 40 50]", target)
+	}
+}
+
+func TestLoadCSVStreaming(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte(streamTestCSV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	features, target, names, err := LoadCSVStreamingFlat(path, true, "y", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{1, 2, math.NaN(), 6, 7, math.NaN(), 9, 10}
+	if len(features) != len(want) || len(target) != 4 || len(names) != 2 || names[1] != "x2" {
+		t.Fatalf("features = %v, target = %v, names = %v", features, target, names)
+	}
+	for i := range want {
+		if features[i] != want[i] && !(math.IsNaN(features[i]) && math.IsNaN(want[i])) {
+			t.Errorf("features[%d] = %v, want %v", i, features[i], want[i])
+		}
+	}
+
+	dataset, err := LoadCSVStreaming(path, true, "y", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dataset.NumSamples() != 4 || dataset.Features[3][1] != 10 || !math.IsNaN(dataset.Features[1][0]) {
+		t.Errorf("features = %v", dataset.Features)
+	}
+}
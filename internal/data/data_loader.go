@@ -8,8 +8,9 @@ import (
 	"github.com/feiyuluoye/Go-Model/internal/types"
 	"io"
 	"log"
-	"os"
+	"math"
 	"strconv"
+	"strings"
 )
 
 // LoadCSV 从CSV文件加载数据
@@ -17,13 +18,36 @@ import (
 // hasHeader: 是否包含表头
 // targetColumn: 目标变量列名或索引
 func LoadCSV(filePath string, hasHeader bool, targetColumn interface{}) (*types.Dataset, error) {
-	file, err := os.Open(filePath)
+	file, err := openDataFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("无法打开文件: %w", err)
+		return nil, err
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
+	return LoadCSVFromReader(file, hasHeader, targetColumn)
+}
+
+// LoadCSVFromReader 从reader加载CSV数据，解析规则与LoadCSV一致，
+// 适用于嵌入的测试数据、网络响应等非本地文件的数据源
+func LoadCSVFromReader(r io.Reader, hasHeader bool, targetColumn interface{}) (*types.Dataset, error) {
+	return readCSV(r, hasHeader, targetColumn, false)
+}
+
+// LoadCSVWithMissing 从CSV文件加载数据，空白或非数值的特征值保留为NaN而不是以0代替，
+// 以便后续用Imputer填充；目标值无效的行仍会被跳过
+func LoadCSVWithMissing(filePath string, hasHeader bool, targetColumn interface{}) (*types.Dataset, error) {
+	file, err := openDataFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readCSV(file, hasHeader, targetColumn, true)
+}
+
+// readCSV 解析CSV数据，keepMissing为true时无效的特征值记为NaN
+func readCSV(r io.Reader, hasHeader bool, targetColumn interface{}, keepMissing bool) (*types.Dataset, error) {
+	reader := csv.NewReader(r)
 
 	// 读取所有记录
 	records, err := reader.ReadAll()
@@ -99,7 +123,9 @@ func LoadCSV(filePath string, hasHeader bool, targetColumn interface{}) (*types.
 			} else {
 				// 处理特征
 				val, err := strconv.ParseFloat(row[j], 64)
-				if err != nil {
+				if err != nil && keepMissing {
+					val = math.NaN()
+				} else if err != nil {
 					log.Printf("警告: 行 %d 列 %d 的值 '%s' 不是有效数字，使用0代替", i, j, row[j])
 					val = 0.0
 				}
@@ -134,9 +160,9 @@ func LoadCSV(filePath string, hasHeader bool, targetColumn interface{}) (*types.
 // featureColumns: 特征列名称列表
 // targetColumn: 目标变量列名称
 func LoadJSON(filePath string, featureColumns []string, targetColumn string) (*types.Dataset, error) {
-	file, err := os.Open(filePath)
+	file, err := openDataFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("无法打开文件: %w", err)
+		return nil, err
 	}
 	defer file.Close()
 
@@ -220,3 +246,26 @@ func toFloat64(val interface{}) (float64, error) {
 		return 0, fmt.Errorf("无法将类型 %T 转换为float64", val)
 	}
 }
+
+// targetColumnIndex 按列名或索引确定目标列在记录中的位置，列名匹配时忽略首尾空白
+func targetColumnIndex(header []string, hasHeader bool, targetColumn interface{}) (int, error) {
+	switch v := targetColumn.(type) {
+	case string:
+		if !hasHeader {
+			return -1, errors.New("当目标列是名称时，文件必须包含表头")
+		}
+		for i, name := range header {
+			if strings.TrimSpace(name) == v {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("未找到目标列: %s", v)
+	case int:
+		if v < 0 || v >= len(header) {
+			return -1, errors.New("目标列索引超出范围")
+		}
+		return v, nil
+	default:
+		return -1, errors.New("目标列参数类型必须是string或int")
+	}
+}
@@ -0,0 +1,191 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+	"gonum.org/v1/gonum/mat"
+)
+
+// 多重共线性的经验阈值
+const (
+	VIFModerate            = 5.0  // VIF超过该值时存在中度共线性
+	VIFSevere              = 10.0 // VIF超过该值时存在严重共线性
+	ConditionNumberSevere  = 30.0 // 条件数超过该值时系数估计不稳定（Belsley准则）
+	DefaultCorrelationWarn = 0.9  // 默认的两两相关系数告警阈值
+)
+
+// CorrelatedPair 一对高度相关的特征
+type CorrelatedPair struct {
+	Feature1    string  `json:"feature1"`
+	Feature2    string  `json:"feature2"`
+	Correlation float64 `json:"correlation"`
+}
+
+// CollinearityReport 多重共线性诊断结果
+type CollinearityReport struct {
+	FeatureNames     []string         `json:"feature_names"`
+	VIF              []float64        `json:"vif"`              // 各特征的方差膨胀因子，完全共线或常数特征为+Inf
+	ConditionNumber  float64          `json:"condition_number"` // 标准化特征矩阵的条件数
+	Correlations     [][]float64      `json:"correlations"`     // 特征间的皮尔逊相关系数矩阵
+	HighCorrelations []CorrelatedPair `json:"high_correlations"`
+	Warnings         []string         `json:"warnings"`
+	Collinear        bool             `json:"collinear"` // 是否存在严重共线性，此时OLS系数不稳定，建议使用Ridge或PLS
+}
+
+// DiagnoseCollinearity 计算数据集特征的方差膨胀因子、条件数和两两相关系数，
+// 相关系数绝对值不小于threshold的特征对会被列出；threshold不大于0时使用DefaultCorrelationWarn
+func DiagnoseCollinearity(data *types.Dataset, threshold float64) (*CollinearityReport, error) {
+	if data == nil || !data.IsValid() {
+		return nil, errors.New("无效的数据集")
+	}
+	if threshold <= 0 {
+		threshold = DefaultCorrelationWarn
+	}
+
+	nSamples := data.NumSamples()
+	nFeatures := data.NumFeatures()
+	if nSamples < 2 {
+		return nil, errors.New("至少需要2个样本才能计算相关系数")
+	}
+	names := data.FeatureNames
+	if len(names) != nFeatures {
+		names = make([]string, nFeatures)
+		for j := range names {
+			names[j] = fmt.Sprintf("feature_%d", j)
+		}
+	}
+
+	// 标准化各特征，常数特征单独标记
+	z := mat.NewDense(nSamples, nFeatures, nil)
+	constant := make([]bool, nFeatures)
+	for j := 0; j < nFeatures; j++ {
+		mean := 0.0
+		for i := 0; i < nSamples; i++ {
+			mean += data.Features[i][j]
+		}
+		mean /= float64(nSamples)
+
+		ss := 0.0
+		for i := 0; i < nSamples; i++ {
+			diff := data.Features[i][j] - mean
+			ss += diff * diff
+		}
+		if ss == 0 {
+			constant[j] = true
+			continue
+		}
+		norm := math.Sqrt(ss)
+		for i := 0; i < nSamples; i++ {
+			z.Set(i, j, (data.Features[i][j]-mean)/norm)
+		}
+	}
+
+	// 相关系数矩阵 R = Z^T Z
+	var corr mat.SymDense
+	corr.SymOuterK(1, z.T())
+	report := &CollinearityReport{
+		FeatureNames: names,
+		VIF:          make([]float64, nFeatures),
+		Correlations: make([][]float64, nFeatures),
+	}
+	for j := 0; j < nFeatures; j++ {
+		report.Correlations[j] = make([]float64, nFeatures)
+		for k := 0; k < nFeatures; k++ {
+			report.Correlations[j][k] = corr.At(j, k)
+		}
+		if !constant[j] {
+			report.Correlations[j][j] = 1
+		}
+	}
+
+	for j := 0; j < nFeatures; j++ {
+		for k := j + 1; k < nFeatures; k++ {
+			if r := report.Correlations[j][k]; math.Abs(r) >= threshold {
+				report.HighCorrelations = append(report.HighCorrelations, CorrelatedPair{
+					Feature1:    names[j],
+					Feature2:    names[k],
+					Correlation: r,
+				})
+			}
+		}
+	}
+	sort.SliceStable(report.HighCorrelations, func(a, b int) bool {
+		return math.Abs(report.HighCorrelations[a].Correlation) > math.Abs(report.HighCorrelations[b].Correlation)
+	})
+
+	// VIF_j = 1/(1-R²_j) 等于相关系数矩阵逆的对角元；条件数为其特征值之比的平方根
+	active := make([]int, 0, nFeatures)
+	for j := 0; j < nFeatures; j++ {
+		if constant[j] {
+			report.VIF[j] = math.Inf(1)
+		} else {
+			active = append(active, j)
+		}
+	}
+	report.ConditionNumber = math.Inf(1)
+	if len(active) > 0 {
+		sub := mat.NewSymDense(len(active), nil)
+		for a, j := range active {
+			for b, k := range active {
+				sub.SetSym(a, b, report.Correlations[j][k])
+			}
+		}
+
+		var eig mat.EigenSym
+		if eig.Factorize(sub, false) {
+			values := eig.Values(nil)
+			minValue, maxValue := values[0], values[len(values)-1]
+			if minValue > maxValue*1e-12 {
+				report.ConditionNumber = math.Sqrt(maxValue / minValue)
+			}
+		}
+
+		var inv mat.Dense
+		singular := math.IsInf(report.ConditionNumber, 1) || inv.Inverse(sub) != nil
+		for a, j := range active {
+			if singular {
+				report.VIF[j] = math.Inf(1)
+			} else {
+				report.VIF[j] = math.Max(inv.At(a, a), 1)
+			}
+		}
+		if len(active) < nFeatures {
+			report.ConditionNumber = math.Inf(1)
+		}
+	}
+
+	for j, vif := range report.VIF {
+		switch {
+		case constant[j]:
+			report.Warnings = append(report.Warnings, fmt.Sprintf("特征%s为常数，与截距项完全共线", names[j]))
+		case math.IsInf(vif, 1):
+			report.Warnings = append(report.Warnings, fmt.Sprintf("特征%s与其他特征完全共线", names[j]))
+		case vif > VIFSevere:
+			report.Warnings = append(report.Warnings, fmt.Sprintf("特征%s的VIF为%.2f，存在严重多重共线性", names[j], vif))
+		case vif > VIFModerate:
+			report.Warnings = append(report.Warnings, fmt.Sprintf("特征%s的VIF为%.2f，存在中度多重共线性", names[j], vif))
+		}
+	}
+	for _, pair := range report.HighCorrelations {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("特征%s与%s高度相关(r=%.3f)", pair.Feature1, pair.Feature2, pair.Correlation))
+	}
+	if report.ConditionNumber > ConditionNumberSevere {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("条件数为%.2f，最小二乘系数估计不稳定", report.ConditionNumber))
+	}
+
+	report.Collinear = report.ConditionNumber > ConditionNumberSevere
+	for _, vif := range report.VIF {
+		if vif > VIFSevere {
+			report.Collinear = true
+		}
+	}
+	if report.Collinear {
+		report.Warnings = append(report.Warnings, "建议使用Ridge或PLS回归代替OLS")
+	}
+
+	return report, nil
+}
@@ -4,12 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"github.com/feiyuluoye/Go-Model/internal/types"
-	"math"
 )
 
 // PolynomialFeatures 生成多项式特征
 type PolynomialFeatures struct {
-	Degree int
+	Degree          int
+	InteractionOnly bool // 只生成不同特征的乘积（如x1*x2），不含x1^2等幂次项
+	IncludeBias     bool // 在首列添加常数项1
 }
 
 // NewPolynomialFeatures 创建一个新的PolynomialFeatures实例
@@ -23,6 +24,8 @@ func NewPolynomialFeatures(degree int) (*PolynomialFeatures, error) {
 }
 
 // Transform 将原始特征转换为多项式特征
+// 输出包含原始特征以及2到Degree次的所有单项式（有放回组合，如x1^2、x1*x2），共C(n+Degree, Degree)-1个；
+// IncludeBias时首列为常数项，InteractionOnly时只保留无重复特征的组合
 func (pf *PolynomialFeatures) Transform(data *types.Dataset) (*types.Dataset, error) {
 	if data == nil || !data.IsValid() {
 		return nil, errors.New("无效的数据集")
@@ -31,93 +34,80 @@ func (pf *PolynomialFeatures) Transform(data *types.Dataset) (*types.Dataset, er
 	nSamples := data.NumSamples()
 	nFeatures := data.NumFeatures()
 
-	// 计算多项式特征的数量
-	// 对于degree次多项式，特征数量为 (n_features + degree) choose degree
-	// 这里我们简化计算，只考虑交互项
-	newFeatureCount := 0
+	// 对于degree次多项式，每个单项式由一组不减（InteractionOnly时严格递增）的特征索引表示，常数项为空组合
+	var terms [][]int
+	if pf.IncludeBias {
+		terms = append(terms, nil)
+	}
 	for d := 1; d <= pf.Degree; d++ {
-		newFeatureCount += int(math.Pow(float64(nFeatures), float64(d)))
+		terms = generateCombinations(nFeatures, d, 0, nil, terms, pf.InteractionOnly)
 	}
 
 	// 创建新的特征矩阵
 	newFeatures := make([][]float64, nSamples)
 	for i := 0; i < nSamples; i++ {
-		newFeatures[i] = make([]float64, newFeatureCount)
-		featureIndex := 0
-
-		// 添加原始特征 (degree=1)
-		for j := 0; j < nFeatures; j++ {
-			newFeatures[i][featureIndex] = data.Features[i][j]
-			featureIndex++
-		}
-
-		// 添加高阶特征
-		for d := 2; d <= pf.Degree; d++ {
-			// 生成所有可能的d次组合
-			featureIndex = generateCombinations(data.Features[i], d, newFeatures[i], featureIndex)
+		newFeatures[i] = make([]float64, len(terms))
+		for k, term := range terms {
+			value := 1.0
+			for _, j := range term {
+				value *= data.Features[i][j]
+			}
+			newFeatures[i][k] = value
 		}
 	}
 
 	// 生成新的特征名称
-	newFeatureNames := make([]string, newFeatureCount)
-	featureIndex := 0
-
-	// 添加原始特征名称
-	for j := 0; j < nFeatures; j++ {
-		newFeatureNames[featureIndex] = data.FeatureNames[j]
-		featureIndex++
-	}
-
-	// 添加高阶特征名称
-	for d := 2; d <= pf.Degree; d++ {
-		featureIndex = generateCombinationNames(data.FeatureNames, d, newFeatureNames, featureIndex)
+	newFeatureNames := make([]string, len(terms))
+	for k, term := range terms {
+		newFeatureNames[k] = generateCombinationName(data.FeatureNames, term)
 	}
 
 	// 创建新的数据集
 	return types.NewDataset(newFeatures, data.Target, newFeatureNames), nil
 }
 
-// generateCombinations 生成所有可能的特征组合的乘积
-func generateCombinations(features []float64, degree int, result []float64, startIndex int) int {
-	if degree == 1 {
-		for i := 0; i < len(features); i++ {
-			result[startIndex+i] = features[i]
-		}
-		return startIndex + len(features)
+// generateCombinations 生成从start开始、长度为degree的所有不减特征索引组合，追加到result；
+// distinct为true时组合内的索引严格递增
+func generateCombinations(nFeatures, degree, start int, prefix []int, result [][]int, distinct bool) [][]int {
+	if degree == 0 {
+		return append(result, append([]int(nil), prefix...))
 	}
-
-	index := startIndex
-	for i := 0; i < len(features); i++ {
-		// 对于每个特征，递归地生成其与其他特征的组合
-		remainingFeatures := features[i:]
-		temp := make([]float64, len(remainingFeatures))
-		for j := 0; j < len(remainingFeatures); j++ {
-			temp[j] = features[i] * remainingFeatures[j]
+	for j := start; j < nFeatures; j++ {
+		next := j
+		if distinct {
+			next = j + 1
 		}
-		index = generateCombinations(temp, degree-1, result, index)
+		result = generateCombinations(nFeatures, degree-1, next, append(prefix, j), result, distinct)
 	}
-	return index
+	return result
 }
 
-// generateCombinationNames 生成组合特征的名称
-func generateCombinationNames(featureNames []string, degree int, result []string, startIndex int) int {
-	if degree == 1 {
-		for i := 0; i < len(featureNames); i++ {
-			result[startIndex+i] = featureNames[i]
+// generateCombinationName 生成组合特征的名称，重复的特征写为幂次，如x1^2*x2；常数项为"1"
+func generateCombinationName(featureNames []string, term []int) string {
+	if len(term) == 0 {
+		return "1"
+	}
+	name := ""
+	for k := 0; k < len(term); {
+		j := term[k]
+		power := 1
+		for k+power < len(term) && term[k+power] == j {
+			power++
 		}
-		return startIndex + len(featureNames)
-	}
-
-	index := startIndex
-	for i := 0; i < len(featureNames); i++ {
-		remainingNames := featureNames[i:]
-		tempNames := make([]string, len(remainingNames))
-		for j := 0; j < len(remainingNames); j++ {
-			tempNames[j] = fmt.Sprintf("%s*%s", featureNames[i], remainingNames[j])
+		featureName := fmt.Sprintf("feature_%d", j)
+		if j < len(featureNames) {
+			featureName = featureNames[j]
+		}
+		if k > 0 {
+			name += "*"
+		}
+		name += featureName
+		if power > 1 {
+			name += fmt.Sprintf("^%d", power)
 		}
-		index = generateCombinationNames(tempNames, degree-1, result, index)
+		k += power
 	}
-	return index
+	return name
 }
 
 // AddPolynomialFeatures 向数据集添加多项式特征
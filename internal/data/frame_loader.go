@@ -0,0 +1,140 @@
+package data
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// frameTimeLayouts 识别时间列时尝试的格式
+var frameTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// LoadCSVFrame 从CSV文件加载类型化的列式数据集，逐列推断类型：
+// 全部非空值都是整数时为整数列，都是数字时为浮点列，都是true/false时为布尔列，
+// 都是RFC3339或2006-01-02 (15:04:05)格式的时间时为时间列，否则为字符串列。空白值记为缺失
+func LoadCSVFrame(filePath string, hasHeader bool) (*types.Frame, error) {
+	file, err := openDataFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("读取CSV文件失败: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV文件为空")
+	}
+
+	header := records[0]
+	rows := records
+	if hasHeader {
+		rows = records[1:]
+	}
+
+	frame, err := types.NewFrame()
+	if err != nil {
+		return nil, err
+	}
+	for j := range header {
+		name := fmt.Sprintf("column_%d", j)
+		if hasHeader {
+			name = strings.TrimSpace(header[j])
+		}
+		cells := make([]string, len(rows))
+		for i, record := range rows {
+			if j < len(record) {
+				cells[i] = strings.TrimSpace(record[j])
+			}
+		}
+		if err := frame.AddColumn(parseFrameColumn(name, cells)); err != nil {
+			return nil, err
+		}
+	}
+	return frame, nil
+}
+
+// parseFrameColumn 推断一列的类型并解析其值
+func parseFrameColumn(name string, cells []string) *types.Column {
+	missing := make([]bool, len(cells))
+	for i, cell := range cells {
+		missing[i] = cell == ""
+	}
+	// all 检查全部非空值能否被parse解析
+	all := func(parse func(string) bool) bool {
+		for i, cell := range cells {
+			if !missing[i] && !parse(cell) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var column *types.Column
+	switch {
+	case all(func(s string) bool { _, err := strconv.ParseInt(s, 10, 64); return err == nil }):
+		values := make([]int64, len(cells))
+		for i, cell := range cells {
+			values[i], _ = strconv.ParseInt(cell, 10, 64)
+		}
+		column = types.NewIntColumn(name, values)
+	case all(func(s string) bool { _, err := strconv.ParseFloat(s, 64); return err == nil }):
+		values := make([]float64, len(cells))
+		for i, cell := range cells {
+			if values[i], _ = strconv.ParseFloat(cell, 64); missing[i] {
+				values[i] = math.NaN()
+			}
+		}
+		column = types.NewFloatColumn(name, values)
+	case all(func(s string) bool { _, err := parseFrameBool(s); return err == nil }):
+		values := make([]bool, len(cells))
+		for i, cell := range cells {
+			values[i], _ = parseFrameBool(cell)
+		}
+		column = types.NewBoolColumn(name, values)
+	case all(func(s string) bool { _, err := parseFrameTime(s); return err == nil }):
+		values := make([]time.Time, len(cells))
+		for i, cell := range cells {
+			values[i], _ = parseFrameTime(cell)
+		}
+		column = types.NewTimeColumn(name, values)
+	default:
+		column = types.NewStringColumn(name, cells)
+	}
+	column.Missing = missing
+	return column
+}
+
+// parseFrameBool 解析true/false（不区分大小写）
+func parseFrameBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("无效的布尔值: %s", s)
+}
+
+// parseFrameTime 按frameTimeLayouts中的格式解析时间
+func parseFrameTime(s string) (time.Time, error) {
+	for _, layout := range frameTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无效的时间: %s", s)
+}
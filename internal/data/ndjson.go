@@ -0,0 +1,134 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// LoadNDJSON 从JSON Lines(NDJSON)文件加载数据，每行一个JSON对象
+// filePath: 文件路径
+// featureColumns: 特征字段列表，嵌套字段使用点号分隔的路径（如"request.latency_ms"）
+// targetColumn: 目标变量字段，同样支持点号路径
+func LoadNDJSON(filePath string, featureColumns []string, targetColumn string) (*types.Dataset, error) {
+	file, err := openDataFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadNDJSON(file, featureColumns, targetColumn)
+}
+
+// ReadNDJSON 从reader逐行解码JSON对象并构建数据集，不会一次读入全部内容。
+// 空行被忽略；无法解析的行和缺少目标值或目标值无效的行会被跳过，
+// 缺失、null或无效的特征值记为NaN，与LoadCSVWithMissing一致；布尔值转换为0/1
+func ReadNDJSON(r io.Reader, featureColumns []string, targetColumn string) (*types.Dataset, error) {
+	if len(featureColumns) == 0 {
+		return nil, errors.New("特征列不能为空")
+	}
+	if targetColumn == "" {
+		return nil, errors.New("目标列不能为空")
+	}
+
+	featurePaths := make([][]string, len(featureColumns))
+	for i, column := range featureColumns {
+		featurePaths[i] = strings.Split(column, ".")
+	}
+	targetPath := strings.Split(targetColumn, ".")
+
+	reader := bufio.NewReader(r)
+	var features [][]float64
+	var target []float64
+	for line := 1; ; line++ {
+		raw, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, fmt.Errorf("读取NDJSON数据失败: %w", readErr)
+		}
+
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 {
+			var record map[string]interface{}
+			if err := json.Unmarshal(raw, &record); err != nil {
+				log.Printf("警告: 第 %d 行不是有效的JSON对象，跳过此行: %v", line, err)
+			} else if row, value, ok := parseNDJSONRecord(record, line, featureColumns, featurePaths, targetColumn, targetPath); ok {
+				features = append(features, row)
+				target = append(target, value)
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	if len(features) == 0 {
+		return nil, errors.New("NDJSON数据中没有有效的数据行")
+	}
+	return types.NewDataset(features, target, featureColumns), nil
+}
+
+// parseNDJSONRecord 按字段路径提取一条记录的特征和目标值，目标值缺失或无效时返回false
+func parseNDJSONRecord(record map[string]interface{}, line int, featureColumns []string, featurePaths [][]string, targetColumn string, targetPath []string) ([]float64, float64, bool) {
+	targetVal, ok := lookupJSONPath(record, targetPath)
+	if !ok || targetVal == nil {
+		log.Printf("警告: 第 %d 行缺少目标列 %s，跳过此行", line, targetColumn)
+		return nil, 0, false
+	}
+	value, err := jsonToFloat64(targetVal)
+	if err != nil {
+		log.Printf("警告: 第 %d 行的目标值不是有效数字，跳过此行: %v", line, err)
+		return nil, 0, false
+	}
+
+	row := make([]float64, len(featurePaths))
+	for j, path := range featurePaths {
+		val, ok := lookupJSONPath(record, path)
+		if !ok || val == nil {
+			row[j] = math.NaN()
+			continue
+		}
+		floatVal, err := jsonToFloat64(val)
+		if err != nil {
+			log.Printf("警告: 第 %d 行的特征列 %s 值不是有效数字，记为缺失值", line, featureColumns[j])
+			row[j] = math.NaN()
+			continue
+		}
+		row[j] = floatVal
+	}
+	return row, value, true
+}
+
+// lookupJSONPath 按路径逐层查找嵌套对象中的字段
+func lookupJSONPath(record map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = record
+	for _, key := range path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// jsonToFloat64 在toFloat64的基础上支持布尔值
+func jsonToFloat64(val interface{}) (float64, error) {
+	if b, ok := val.(bool); ok {
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return toFloat64(val)
+}
@@ -0,0 +1,864 @@
+package data
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// parquetColumn Parquet文件中的一个叶子列
+type parquetColumn struct {
+	name       string
+	physical   int64
+	repetition int64
+	converted  int64 // 转换类型，没有时为-1
+	scale      int64 // DECIMAL类型的小数位数
+}
+
+// columnType 返回读取该列时使用的类型化列类型：整数存储的DECIMAL读为浮点列，
+// 带TIMESTAMP_MILLIS/TIMESTAMP_MICROS转换类型的INT64读为时间列
+func (c parquetColumn) columnType() (types.ColumnType, error) {
+	switch c.physical {
+	case parquetBoolean:
+		return types.BoolColumn, nil
+	case parquetInt32, parquetInt64:
+		if c.scale > 0 {
+			return types.FloatColumn, nil
+		}
+		if c.physical == parquetInt64 && (c.converted == parquetConvertedTimestampMillis || c.converted == parquetConvertedTimestampMicros) {
+			return types.TimeColumn, nil
+		}
+		return types.IntColumn, nil
+	case parquetFloat, parquetDouble:
+		return types.FloatColumn, nil
+	case parquetByteArray:
+		return types.StringColumn, nil
+	}
+	return 0, fmt.Errorf("不支持的Parquet类型: %d", c.physical)
+}
+
+// LoadParquet 从Parquet文件加载数据
+// filePath: Parquet文件路径
+// featureColumns: 特征列名称列表，为空时使用除目标列外的所有列
+// targetColumn: 目标变量列名称
+// 支持扁平的schema，数值列（BOOLEAN、INT32、INT64、FLOAT、DOUBLE及整数存储的DECIMAL）、
+// 时间戳列（转换为Unix秒）以及可解析为数字的字符串列；压缩格式支持UNCOMPRESSED、SNAPPY和GZIP。
+// 特征为空值或无法解析为数字时记为NaN，目标为空值的行会被跳过；需要保留列类型时使用LoadParquetFrame
+func LoadParquet(filePath string, featureColumns []string, targetColumn string) (*types.Dataset, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开文件: %w", err)
+	}
+	defer file.Close()
+
+	metadata, err := readParquetFooter(file)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := parquetSchema(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	position := make(map[string]int, len(columns))
+	for i, column := range columns {
+		position[column.name] = i
+	}
+	targetIndex, ok := position[targetColumn]
+	if !ok {
+		return nil, fmt.Errorf("未找到目标列: %s", targetColumn)
+	}
+	if len(featureColumns) == 0 {
+		for _, column := range columns {
+			if column.name != targetColumn {
+				featureColumns = append(featureColumns, column.name)
+			}
+		}
+	}
+	featureIndices := make([]int, len(featureColumns))
+	for j, name := range featureColumns {
+		index, ok := position[name]
+		if !ok {
+			return nil, fmt.Errorf("未找到特征列: %s", name)
+		}
+		featureIndices[j] = index
+	}
+
+	var features [][]float64
+	var target []float64
+	nullFeatures := make([]int, len(featureColumns))
+	skipped := 0
+	err = eachParquetRowGroup(file, metadata, columns, func(numRows int, read func(index int) (*types.Column, error)) error {
+		targetValues, err := read(targetIndex)
+		if err != nil {
+			return err
+		}
+		featureValues := make([]*types.Column, len(featureIndices))
+		for j, index := range featureIndices {
+			if featureValues[j], err = read(index); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < numRows; i++ {
+			value, ok := parquetNumber(targetValues, i)
+			if !ok {
+				skipped++
+				continue
+			}
+			row := make([]float64, len(featureIndices))
+			for j, column := range featureValues {
+				if row[j], ok = parquetNumber(column, i); !ok {
+					nullFeatures[j]++
+				}
+			}
+			features = append(features, row)
+			target = append(target, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if skipped > 0 {
+		log.Printf("警告: %d 行的目标值为空，已跳过", skipped)
+	}
+	for j, count := range nullFeatures {
+		if count > 0 {
+			log.Printf("警告: 特征列 %s 有 %d 个空值或无效值，记为缺失值(NaN)", featureColumns[j], count)
+		}
+	}
+	if len(features) == 0 {
+		return nil, errors.New("Parquet文件中没有有效的数据行")
+	}
+
+	return types.NewDataset(features, target, append([]string(nil), featureColumns...)), nil
+}
+
+// LoadParquetFrame 从Parquet文件加载类型化的列式数据集，保留各列的类型：
+// BOOLEAN为布尔列，INT32/INT64为整数列，FLOAT/DOUBLE和整数存储的DECIMAL为浮点列，
+// BYTE_ARRAY为字符串列，带时间戳转换类型的INT64为时间列（UTC）；空值标记为缺失
+func LoadParquetFrame(filePath string) (*types.Frame, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开文件: %w", err)
+	}
+	defer file.Close()
+
+	metadata, err := readParquetFooter(file)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := parquetSchema(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]*types.Column, len(columns))
+	for j, column := range columns {
+		typ, err := column.columnType()
+		if err != nil {
+			return nil, err
+		}
+		values[j] = &types.Column{Name: column.name, Type: typ, Missing: []bool{}}
+	}
+	err = eachParquetRowGroup(file, metadata, columns, func(numRows int, read func(index int) (*types.Column, error)) error {
+		for j := range columns {
+			chunk, err := read(j)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < numRows; i++ {
+				appendColumnValue(values[j], chunk, i)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return types.NewFrame(values...)
+}
+
+// eachParquetRowGroup 依次处理每个行组，read按schema中的下标读取该行组的一个列块
+func eachParquetRowGroup(file *os.File, metadata thriftFields, columns []parquetColumn, process func(numRows int, read func(index int) (*types.Column, error)) error) error {
+	for _, rowGroup := range metadata.structs(4) {
+		chunks := rowGroup.structs(1)
+		if len(chunks) != len(columns) {
+			return errors.New("行组的列数与schema不一致")
+		}
+		numRows := int(rowGroup.int(3))
+		if numRows < 0 {
+			return errors.New("行组的行数无效")
+		}
+		read := func(index int) (*types.Column, error) {
+			return readParquetColumn(file, chunks[index], columns[index], numRows)
+		}
+		if err := process(numRows, read); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parquetNumber 返回列c第i个值的数值：布尔值为0/1，时间为Unix秒，字符串按数字解析；
+// 空值或无法解析的字符串返回NaN和false
+func parquetNumber(c *types.Column, i int) (float64, bool) {
+	if c.Missing[i] {
+		return math.NaN(), false
+	}
+	if c.Type == types.StringColumn {
+		v, err := strconv.ParseFloat(c.Strings[i], 64)
+		if err != nil {
+			return math.NaN(), false
+		}
+		return v, true
+	}
+	return c.Float64(i), true
+}
+
+// SaveParquet 将数据集保存为Parquet文件，特征列和目标列均为DOUBLE类型，NaN写为空值；
+// 需要写出整数、布尔、字符串或时间列时使用SaveParquetFrame
+// targetName: 目标列的列名，为空时使用"target"
+func SaveParquet(data *types.Dataset, filePath string, targetName string) error {
+	if data == nil || !data.IsValid() {
+		return errors.New("无效的数据集")
+	}
+	names, err := outputColumns(data, targetName)
+	if err != nil {
+		return err
+	}
+
+	nFeatures := data.NumFeatures()
+	columns := make([]*types.Column, len(names))
+	for j, name := range names {
+		values := make([]float64, data.NumSamples())
+		for i := range values {
+			if j < nFeatures {
+				values[i] = data.Features[i][j]
+			} else {
+				values[i] = data.Target[i]
+			}
+		}
+		columns[j] = types.NewFloatColumn(name, values)
+	}
+	frame, err := types.NewFrame(columns...)
+	if err != nil {
+		return err
+	}
+	return SaveParquetFrame(frame, filePath)
+}
+
+// SaveParquetFrame 将类型化的数据集保存为Parquet文件，以不压缩的PLAIN编码写入单个行组。
+// 浮点列写为DOUBLE，整数列写为INT64，布尔列写为BOOLEAN，字符串列写为UTF8的BYTE_ARRAY，
+// 时间列写为TIMESTAMP_MICROS的INT64；含缺失值的列写为OPTIONAL，缺失值写为空值
+func SaveParquetFrame(frame *types.Frame, filePath string) error {
+	if frame == nil || frame.NumColumns() == 0 {
+		return errors.New("数据集为空")
+	}
+	nRows := frame.NumRows()
+
+	var out bytes.Buffer
+	out.Write(parquetMagic)
+
+	type chunkInfo struct {
+		offset     int64
+		size       int64
+		physical   int32
+		converted  int32
+		repetition int32
+	}
+	chunks := make([]chunkInfo, frame.NumColumns())
+	for j, column := range frame.Columns() {
+		chunk := &chunks[j]
+		chunk.physical, chunk.converted = parquetPhysicalType(column.Type)
+		chunk.repetition = parquetRequired
+
+		defined := make([]bool, nRows)
+		for i := range defined {
+			defined[i] = !column.IsMissing(i)
+			if !defined[i] {
+				chunk.repetition = parquetOptional
+			}
+		}
+
+		var page bytes.Buffer
+		if chunk.repetition == parquetOptional {
+			levels := encodeDefinitionLevels(defined)
+			var length [4]byte
+			binary.LittleEndian.PutUint32(length[:], uint32(len(levels)))
+			page.Write(length[:])
+			page.Write(levels)
+		}
+		encodePlain(&page, column, defined)
+		if page.Len() > math.MaxInt32 {
+			return fmt.Errorf("列 %s 的数据超过Parquet页的大小上限", column.Name)
+		}
+
+		header := &thriftWriter{}
+		header.beginStruct()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(page.Len()))
+		header.i32(3, int32(page.Len()))
+		header.structField(5, func() {
+			header.i32(1, int32(nRows))
+			header.i32(2, parquetEncodingPlain)
+			header.i32(3, parquetEncodingRLE)
+			header.i32(4, parquetEncodingRLE)
+		})
+		header.endStruct()
+
+		chunk.offset = int64(out.Len())
+		out.Write(header.buf.Bytes())
+		out.Write(page.Bytes())
+		chunk.size = int64(out.Len()) - chunk.offset
+	}
+
+	names := frame.Names()
+	footer := &thriftWriter{}
+	footer.beginStruct()
+	footer.i32(1, 1)
+	footer.listHeader(2, thriftStruct, len(names)+1)
+	footer.listStruct(func() {
+		footer.binary(4, "schema")
+		footer.i32(5, int32(len(names)))
+	})
+	for j, name := range names {
+		chunk := chunks[j]
+		footer.listStruct(func() {
+			footer.i32(1, chunk.physical)
+			footer.i32(3, chunk.repetition)
+			footer.binary(4, name)
+			if chunk.converted >= 0 {
+				footer.i32(6, chunk.converted)
+			}
+		})
+	}
+	footer.i64(3, int64(nRows))
+	footer.listHeader(4, thriftStruct, 1)
+	footer.listStruct(func() {
+		footer.listHeader(1, thriftStruct, len(names))
+		total := int64(0)
+		for j, name := range names {
+			chunk := chunks[j]
+			total += chunk.size
+			footer.listStruct(func() {
+				footer.i64(2, chunk.offset)
+				footer.structField(3, func() {
+					footer.i32(1, chunk.physical)
+					footer.listHeader(2, thriftI32, 2)
+					footer.zigzag(parquetEncodingPlain)
+					footer.zigzag(parquetEncodingRLE)
+					footer.listHeader(3, thriftBinary, 1)
+					footer.varint(uint64(len(name)))
+					footer.buf.WriteString(name)
+					footer.i32(4, parquetCodecUncompressed)
+					footer.i64(5, int64(nRows))
+					footer.i64(6, chunk.size)
+					footer.i64(7, chunk.size)
+					footer.i64(9, chunk.offset)
+				})
+			})
+		}
+		footer.i64(2, total)
+		footer.i64(3, int64(nRows))
+	})
+	footer.binary(6, "Go-Model")
+	footer.endStruct()
+
+	out.Write(footer.buf.Bytes())
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(footer.buf.Len()))
+	out.Write(length[:])
+	out.Write(parquetMagic)
+
+	if err := os.WriteFile(filePath, out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("写入Parquet文件失败: %w", err)
+	}
+	return nil
+}
+
+// parquetPhysicalType 返回写出列类型时使用的物理类型和转换类型（没有时为-1）
+func parquetPhysicalType(typ types.ColumnType) (int32, int32) {
+	switch typ {
+	case types.IntColumn:
+		return parquetInt64, -1
+	case types.BoolColumn:
+		return parquetBoolean, -1
+	case types.StringColumn:
+		return parquetByteArray, parquetConvertedUTF8
+	case types.TimeColumn:
+		return parquetInt64, parquetConvertedTimestampMicros
+	}
+	return parquetDouble, -1
+}
+
+// encodePlain 以PLAIN编码写出列中非空的值
+func encodePlain(out *bytes.Buffer, column *types.Column, defined []bool) {
+	var buf [8]byte
+	var bits byte
+	nBits := 0
+	for i, ok := range defined {
+		if !ok {
+			continue
+		}
+		switch column.Type {
+		case types.IntColumn:
+			binary.LittleEndian.PutUint64(buf[:], uint64(column.Ints[i]))
+			out.Write(buf[:8])
+		case types.TimeColumn:
+			binary.LittleEndian.PutUint64(buf[:], uint64(column.Times[i].UnixMicro()))
+			out.Write(buf[:8])
+		case types.StringColumn:
+			binary.LittleEndian.PutUint32(buf[:], uint32(len(column.Strings[i])))
+			out.Write(buf[:4])
+			out.WriteString(column.Strings[i])
+		case types.BoolColumn:
+			// 布尔值按位打包，低位在前
+			if column.Bools[i] {
+				bits |= 1 << nBits
+			}
+			if nBits++; nBits == 8 {
+				out.WriteByte(bits)
+				bits, nBits = 0, 0
+			}
+		default:
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(column.Floats[i]))
+			out.Write(buf[:8])
+		}
+	}
+	if nBits > 0 {
+		out.WriteByte(bits)
+	}
+}
+
+// encodeDefinitionLevels 将定义级别编码为位宽为1的位打包混合编码
+func encodeDefinitionLevels(defined []bool) []byte {
+	groups := (len(defined) + 7) / 8
+	levels := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+	packed := make([]byte, groups)
+	for i, ok := range defined {
+		if ok {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return append(levels, packed...)
+}
+
+// readParquetFooter 校验魔数并解析文件尾部的元数据
+func readParquetFooter(file *os.File) (thriftFields, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("读取Parquet文件失败: %w", err)
+	}
+	size := info.Size()
+	if size < 12 {
+		return nil, errors.New("不是有效的Parquet文件")
+	}
+
+	tail := make([]byte, 8)
+	if _, err := file.ReadAt(tail, size-8); err != nil {
+		return nil, fmt.Errorf("读取Parquet文件失败: %w", err)
+	}
+	head := make([]byte, 4)
+	if _, err := file.ReadAt(head, 0); err != nil {
+		return nil, fmt.Errorf("读取Parquet文件失败: %w", err)
+	}
+	if !bytes.Equal(tail[4:], parquetMagic) || !bytes.Equal(head, parquetMagic) {
+		return nil, errors.New("不是有效的Parquet文件")
+	}
+
+	footerLength := int64(binary.LittleEndian.Uint32(tail))
+	if footerLength > size-12 {
+		return nil, errors.New("Parquet文件元数据长度无效")
+	}
+	footer := make([]byte, footerLength)
+	if _, err := file.ReadAt(footer, size-8-footerLength); err != nil {
+		return nil, fmt.Errorf("读取Parquet文件失败: %w", err)
+	}
+
+	metadata, err := (&thriftReader{buf: footer}).readStruct()
+	if err != nil {
+		return nil, fmt.Errorf("解析Parquet元数据失败: %w", err)
+	}
+	return metadata, nil
+}
+
+// parquetSchema 解析扁平schema的叶子列，嵌套或重复字段不受支持
+func parquetSchema(metadata thriftFields) ([]parquetColumn, error) {
+	elements := metadata.structs(2)
+	if len(elements) < 2 {
+		return nil, errors.New("Parquet文件没有数据列")
+	}
+
+	columns := make([]parquetColumn, 0, len(elements)-1)
+	for _, element := range elements[1:] {
+		if element.int(5) > 0 {
+			return nil, fmt.Errorf("不支持嵌套结构的Parquet列: %s", element.str(4))
+		}
+		column := parquetColumn{
+			name:       element.str(4),
+			physical:   element.int(1),
+			repetition: element.int(3),
+			converted:  -1,
+		}
+		if column.repetition == parquetRepeated {
+			return nil, fmt.Errorf("不支持重复字段的Parquet列: %s", column.name)
+		}
+		if element.has(6) {
+			column.converted = element.int(6)
+		}
+		if column.converted == parquetConvertedDecimal {
+			column.scale = element.int(7)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// readParquetColumn 读取一个列块中的全部值，返回类型化的列，空值标记为缺失
+func readParquetColumn(file *os.File, chunk thriftFields, column parquetColumn, numRows int) (*types.Column, error) {
+	typ, err := column.columnType()
+	if err != nil {
+		return nil, err
+	}
+	meta := chunk.child(3)
+	if meta == nil {
+		return nil, fmt.Errorf("列 %s 缺少元数据", column.name)
+	}
+	codec := meta.int(4)
+	start := meta.int(9)
+	if meta.has(11) && meta.int(11) > 0 && meta.int(11) < start {
+		start = meta.int(11)
+	}
+	if start < 0 || meta.int(7) < 0 || meta.int(7) > math.MaxInt32 {
+		return nil, fmt.Errorf("列 %s 的元数据无效", column.name)
+	}
+	buf := make([]byte, meta.int(7))
+	if _, err := file.ReadAt(buf, start); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("读取列 %s 失败: %w", column.name, err)
+	}
+
+	values := &types.Column{Name: column.name, Type: typ, Missing: make([]bool, 0, numRows)}
+	var dictionary *types.Column
+	reader := &thriftReader{buf: buf}
+	for len(values.Missing) < numRows && reader.pos < len(buf) {
+		header, err := reader.readStruct()
+		if err != nil {
+			return nil, fmt.Errorf("解析列 %s 的页头失败: %w", column.name, err)
+		}
+		compressedSize := int(header.int(3))
+		uncompressedSize := int(header.int(2))
+		if compressedSize < 0 || uncompressedSize < 0 {
+			return nil, fmt.Errorf("列 %s 的页头无效", column.name)
+		}
+		if reader.pos+compressedSize > len(buf) {
+			return nil, fmt.Errorf("列 %s 的页数据不完整", column.name)
+		}
+		page := buf[reader.pos : reader.pos+compressedSize]
+		reader.pos += compressedSize
+		// 值的数量不能超过行组中剩余的行数，字典的每个值至少占1位
+		remaining := numRows - len(values.Missing)
+
+		switch header.int(1) {
+		case parquetDictionaryPage:
+			data, err := decompressPage(codec, page, uncompressedSize)
+			if err != nil {
+				return nil, fmt.Errorf("解压列 %s 失败: %w", column.name, err)
+			}
+			count := int(header.child(7).int(1))
+			if count < 0 || count > 8*len(data) {
+				return nil, fmt.Errorf("列 %s 的页头无效", column.name)
+			}
+			if dictionary, err = decodePlain(data, column, typ, count); err != nil {
+				return nil, fmt.Errorf("解码列 %s 的字典失败: %w", column.name, err)
+			}
+		case parquetDataPage:
+			data, err := decompressPage(codec, page, uncompressedSize)
+			if err != nil {
+				return nil, fmt.Errorf("解压列 %s 失败: %w", column.name, err)
+			}
+			pageHeader := header.child(5)
+			count := int(pageHeader.int(1))
+			if count < 0 || count > remaining {
+				return nil, fmt.Errorf("列 %s 的页头无效", column.name)
+			}
+			defined, data, err := decodeDefinitionLevels(data, column, count, true)
+			if err != nil {
+				return nil, fmt.Errorf("解码列 %s 的定义级别失败: %w", column.name, err)
+			}
+			if err := appendPageValues(values, data, column, pageHeader.int(2), defined, dictionary); err != nil {
+				return nil, fmt.Errorf("解码列 %s 失败: %w", column.name, err)
+			}
+		case parquetDataPageV2:
+			pageHeader := header.child(8)
+			count := int(pageHeader.int(1))
+			repLength := int(pageHeader.int(6))
+			defLength := int(pageHeader.int(5))
+			if count < 0 || count > remaining || repLength < 0 || defLength < 0 || defLength > uncompressedSize {
+				return nil, fmt.Errorf("列 %s 的页头无效", column.name)
+			}
+			if repLength+defLength > len(page) {
+				return nil, fmt.Errorf("列 %s 的页数据不完整", column.name)
+			}
+			if repLength > 0 {
+				return nil, fmt.Errorf("不支持重复字段的Parquet列: %s", column.name)
+			}
+			defined, _, err := decodeDefinitionLevels(page[:defLength], column, count, false)
+			if err != nil {
+				return nil, fmt.Errorf("解码列 %s 的定义级别失败: %w", column.name, err)
+			}
+			data := page[defLength:]
+			if pageHeader.bool(7, true) {
+				if data, err = decompressPage(codec, data, uncompressedSize-defLength); err != nil {
+					return nil, fmt.Errorf("解压列 %s 失败: %w", column.name, err)
+				}
+			}
+			if err := appendPageValues(values, data, column, pageHeader.int(4), defined, dictionary); err != nil {
+				return nil, fmt.Errorf("解码列 %s 失败: %w", column.name, err)
+			}
+		case parquetIndexPage:
+			// 索引页不包含数据
+		default:
+			return nil, fmt.Errorf("列 %s 包含未知的页类型: %d", column.name, header.int(1))
+		}
+	}
+
+	if len(values.Missing) != numRows {
+		return nil, fmt.Errorf("列 %s 的值数量(%d)与行数(%d)不一致", column.name, len(values.Missing), numRows)
+	}
+	return values, nil
+}
+
+// decodeDefinitionLevels 解码页的定义级别，返回每个值是否非空以及剩余的值数据。
+// 必需列没有定义级别；v1页的定义级别带4字节长度前缀，v2页的长度记录在页头中
+func decodeDefinitionLevels(data []byte, column parquetColumn, count int, lengthPrefixed bool) ([]bool, []byte, error) {
+	defined := make([]bool, count)
+	if column.repetition == parquetRequired {
+		for i := range defined {
+			defined[i] = true
+		}
+		return defined, data, nil
+	}
+
+	levels := data
+	rest := data
+	if lengthPrefixed {
+		if len(data) < 4 {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		length := int(binary.LittleEndian.Uint32(data))
+		if 4+length > len(data) {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		levels, rest = data[4:4+length], data[4+length:]
+	}
+	decoded, err := decodeHybrid(levels, 1, count)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, level := range decoded {
+		defined[i] = level == 1
+	}
+	return defined, rest, nil
+}
+
+// appendPageValues 按编码解码页中的非空值，并按定义级别展开后追加到values，空值标记为缺失
+func appendPageValues(values *types.Column, data []byte, column parquetColumn, encoding int64, defined []bool, dictionary *types.Column) error {
+	nonNull := 0
+	for _, d := range defined {
+		if d {
+			nonNull++
+		}
+	}
+
+	var pageValues *types.Column
+	var indices []int
+	switch encoding {
+	case parquetEncodingPlain:
+		var err error
+		if pageValues, err = decodePlain(data, column, values.Type, nonNull); err != nil {
+			return err
+		}
+	case parquetEncodingPlainDictionary, parquetEncodingRLEDictionary:
+		if dictionary == nil {
+			return errors.New("字典编码的页缺少字典")
+		}
+		if len(data) == 0 {
+			if nonNull > 0 {
+				return io.ErrUnexpectedEOF
+			}
+			break
+		}
+		var err error
+		if indices, err = decodeHybrid(data[1:], int(data[0]), nonNull); err != nil {
+			return err
+		}
+		for _, index := range indices {
+			if index >= dictionary.Len() {
+				return errors.New("字典索引越界")
+			}
+		}
+		pageValues = dictionary
+	case parquetEncodingRLE:
+		if column.physical != parquetBoolean || len(data) < 4 {
+			return fmt.Errorf("不支持的编码: %d", encoding)
+		}
+		bits, err := decodeHybrid(data[4:], 1, nonNull)
+		if err != nil {
+			return err
+		}
+		pageValues = types.NewBoolColumn(column.name, make([]bool, nonNull))
+		for i, bit := range bits {
+			pageValues.Bools[i] = bit == 1
+		}
+	default:
+		return fmt.Errorf("不支持的编码: %d", encoding)
+	}
+
+	k := 0
+	for _, d := range defined {
+		switch {
+		case !d:
+			appendColumnValue(values, nil, 0)
+		case indices != nil:
+			appendColumnValue(values, pageValues, indices[k])
+			k++
+		default:
+			appendColumnValue(values, pageValues, k)
+			k++
+		}
+	}
+	return nil
+}
+
+// appendColumnValue 将src的第i个值追加到同类型的dst末尾并保留其缺失标记；
+// src为nil时追加零值（浮点列为NaN）并标记为缺失
+func appendColumnValue(dst, src *types.Column, i int) {
+	missing := src == nil || (src.Missing != nil && src.Missing[i])
+	switch dst.Type {
+	case types.IntColumn:
+		var v int64
+		if src != nil {
+			v = src.Ints[i]
+		}
+		dst.Ints = append(dst.Ints, v)
+	case types.StringColumn:
+		var v string
+		if src != nil {
+			v = src.Strings[i]
+		}
+		dst.Strings = append(dst.Strings, v)
+	case types.BoolColumn:
+		var v bool
+		if src != nil {
+			v = src.Bools[i]
+		}
+		dst.Bools = append(dst.Bools, v)
+	case types.TimeColumn:
+		var v time.Time
+		if src != nil {
+			v = src.Times[i]
+		}
+		dst.Times = append(dst.Times, v)
+	default:
+		v := math.NaN()
+		if src != nil && !missing {
+			v = src.Floats[i]
+		}
+		dst.Floats = append(dst.Floats, v)
+	}
+	dst.Missing = append(dst.Missing, missing)
+}
+
+// decodePlain 解码count个PLAIN编码的值，返回typ类型的列（见parquetColumn.columnType）
+func decodePlain(data []byte, column parquetColumn, typ types.ColumnType, count int) (*types.Column, error) {
+	values := &types.Column{Name: column.name, Type: typ}
+	pos := 0
+	need := func(n int) error {
+		if pos+n > len(data) {
+			return io.ErrUnexpectedEOF
+		}
+		return nil
+	}
+	// integer 读取一个INT32或INT64值
+	integer := func() (int64, error) {
+		if column.physical == parquetInt32 {
+			if err := need(4); err != nil {
+				return 0, err
+			}
+			pos += 4
+			return int64(int32(binary.LittleEndian.Uint32(data[pos-4:]))), nil
+		}
+		if err := need(8); err != nil {
+			return 0, err
+		}
+		pos += 8
+		return int64(binary.LittleEndian.Uint64(data[pos-8:])), nil
+	}
+	scale := math.Pow(10, float64(column.scale))
+	if column.physical == parquetBoolean && len(data) < (count+7)/8 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	for i := 0; i < count; i++ {
+		switch column.physical {
+		case parquetBoolean:
+			// 布尔值按位打包，低位在前
+			values.Bools = append(values.Bools, data[i/8]&(1<<(i%8)) != 0)
+		case parquetInt32, parquetInt64:
+			v, err := integer()
+			if err != nil {
+				return nil, err
+			}
+			switch typ {
+			case types.FloatColumn:
+				values.Floats = append(values.Floats, float64(v)/scale)
+			case types.TimeColumn:
+				if column.converted == parquetConvertedTimestampMillis {
+					values.Times = append(values.Times, time.UnixMilli(v).UTC())
+				} else {
+					values.Times = append(values.Times, time.UnixMicro(v).UTC())
+				}
+			default:
+				values.Ints = append(values.Ints, v)
+			}
+		case parquetFloat:
+			if err := need(4); err != nil {
+				return nil, err
+			}
+			values.Floats = append(values.Floats, float64(math.Float32frombits(binary.LittleEndian.Uint32(data[pos:]))))
+			pos += 4
+		case parquetDouble:
+			if err := need(8); err != nil {
+				return nil, err
+			}
+			values.Floats = append(values.Floats, math.Float64frombits(binary.LittleEndian.Uint64(data[pos:])))
+			pos += 8
+		case parquetByteArray:
+			if err := need(4); err != nil {
+				return nil, err
+			}
+			length := int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+			if err := need(length); err != nil {
+				return nil, err
+			}
+			values.Strings = append(values.Strings, string(data[pos:pos+length]))
+			pos += length
+		default:
+			return nil, fmt.Errorf("不支持的Parquet类型: %d", column.physical)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,491 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Parquet文件格式中使用的枚举值（见parquet-format的parquet.thrift）
+const (
+	parquetBoolean           = 0
+	parquetInt32             = 1
+	parquetInt64             = 2
+	parquetInt96             = 3
+	parquetFloat             = 4
+	parquetDouble            = 5
+	parquetByteArray         = 6
+	parquetFixedLenByteArray = 7
+
+	parquetRequired = 0
+	parquetOptional = 1
+	parquetRepeated = 2
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedDecimal         = 5
+	parquetConvertedTimestampMillis = 9
+	parquetConvertedTimestampMicros = 10
+
+	parquetEncodingPlain           = 0
+	parquetEncodingPlainDictionary = 2
+	parquetEncodingRLE             = 3
+	parquetEncodingRLEDictionary   = 8
+
+	parquetCodecUncompressed = 0
+	parquetCodecSnappy       = 1
+	parquetCodecGzip         = 2
+
+	parquetDataPage       = 0
+	parquetIndexPage      = 1
+	parquetDictionaryPage = 2
+	parquetDataPageV2     = 3
+)
+
+// parquetMagic Parquet文件首尾的魔数
+var parquetMagic = []byte("PAR1")
+
+// Thrift紧凑协议的字段类型
+const (
+	thriftStop       = 0
+	thriftTrue       = 1
+	thriftFalse      = 2
+	thriftByte       = 3
+	thriftI16        = 4
+	thriftI32        = 5
+	thriftI64        = 6
+	thriftDouble     = 7
+	thriftBinary     = 8
+	thriftList       = 9
+	thriftSet        = 10
+	thriftMap        = 11
+	thriftStruct     = 12
+	thriftListEscape = 15 // 列表长度不小于该值时改用varint编码
+)
+
+// thriftFields 解码后的Thrift结构体，键为字段编号。
+// 整数统一为int64，binary为[]byte，list为[]interface{}，嵌套结构体为thriftFields
+type thriftFields map[int16]interface{}
+
+func (f thriftFields) int(id int16) int64 {
+	v, _ := f[id].(int64)
+	return v
+}
+
+func (f thriftFields) has(id int16) bool {
+	_, ok := f[id]
+	return ok
+}
+
+func (f thriftFields) str(id int16) string {
+	v, _ := f[id].([]byte)
+	return string(v)
+}
+
+func (f thriftFields) bool(id int16, defaultValue bool) bool {
+	v, ok := f[id].(bool)
+	if !ok {
+		return defaultValue
+	}
+	return v
+}
+
+func (f thriftFields) list(id int16) []interface{} {
+	v, _ := f[id].([]interface{})
+	return v
+}
+
+func (f thriftFields) structs(id int16) []thriftFields {
+	items := f.list(id)
+	result := make([]thriftFields, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(thriftFields); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func (f thriftFields) child(id int16) thriftFields {
+	v, _ := f[id].(thriftFields)
+	return v
+}
+
+// thriftReader Thrift紧凑协议解码器
+type thriftReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *thriftReader) readByte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *thriftReader) readVarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errors.New("无效的varint编码")
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *thriftReader) readZigzag() (int64, error) {
+	v, err := r.readVarint()
+	if err != nil {
+		return 0, err
+	}
+	return int64(v>>1) ^ -int64(v&1), nil
+}
+
+// readStruct 解码一个结构体，直到遇到stop字段
+func (r *thriftReader) readStruct() (thriftFields, error) {
+	fields := make(thriftFields)
+	var lastID int16
+	for {
+		header, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		if header == thriftStop {
+			return fields, nil
+		}
+		typ := header & 0x0f
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			v, err := r.readZigzag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		lastID = id
+
+		value, err := r.readValue(typ)
+		if err != nil {
+			return nil, err
+		}
+		fields[id] = value
+	}
+}
+
+// readValue 按类型解码一个值
+func (r *thriftReader) readValue(typ byte) (interface{}, error) {
+	switch typ {
+	case thriftTrue:
+		return true, nil
+	case thriftFalse:
+		return false, nil
+	case thriftByte:
+		b, err := r.readByte()
+		return int64(int8(b)), err
+	case thriftI16, thriftI32, thriftI64:
+		return r.readZigzag()
+	case thriftDouble:
+		if r.pos+8 > len(r.buf) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.buf[r.pos:]))
+		r.pos += 8
+		return v, nil
+	case thriftBinary:
+		n, err := r.readVarint()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(r.buf)-r.pos) < n {
+			return nil, io.ErrUnexpectedEOF
+		}
+		v := r.buf[r.pos : r.pos+int(n)]
+		r.pos += int(n)
+		return v, nil
+	case thriftList, thriftSet:
+		header, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(header >> 4)
+		elemType := header & 0x0f
+		if size == thriftListEscape {
+			if size, err = r.readVarint(); err != nil {
+				return nil, err
+			}
+		}
+		if size > uint64(len(r.buf)) {
+			return nil, errors.New("无效的列表长度")
+		}
+		items := make([]interface{}, size)
+		for i := range items {
+			if elemType == thriftTrue || elemType == thriftFalse {
+				b, err := r.readByte()
+				if err != nil {
+					return nil, err
+				}
+				items[i] = b == thriftTrue
+				continue
+			}
+			if items[i], err = r.readValue(elemType); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case thriftMap:
+		size, err := r.readVarint()
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return nil, nil
+		}
+		types, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint64(0); i < size; i++ {
+			if _, err := r.readValue(types >> 4); err != nil {
+				return nil, err
+			}
+			if _, err := r.readValue(types & 0x0f); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case thriftStruct:
+		return r.readStruct()
+	default:
+		return nil, fmt.Errorf("未知的Thrift字段类型: %d", typ)
+	}
+}
+
+// thriftWriter Thrift紧凑协议编码器
+type thriftWriter struct {
+	buf    bytes.Buffer
+	lastID []int16
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	w.buf.Write(tmp[:n])
+}
+
+func (w *thriftWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := w.lastID[len(w.lastID)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta<<4) | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.zigzag(int64(id))
+	}
+	w.lastID[len(w.lastID)-1] = id
+}
+
+func (w *thriftWriter) beginStruct() {
+	w.lastID = append(w.lastID, 0)
+}
+
+func (w *thriftWriter) endStruct() {
+	w.buf.WriteByte(thriftStop)
+	w.lastID = w.lastID[:len(w.lastID)-1]
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.zigzag(int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.zigzag(v)
+}
+
+func (w *thriftWriter) binary(id int16, v string) {
+	w.fieldHeader(id, thriftBinary)
+	w.varint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+// listHeader 写入列表字段头，元素由调用方随后写入
+func (w *thriftWriter) listHeader(id int16, elemType byte, size int) {
+	w.fieldHeader(id, thriftList)
+	if size < thriftListEscape {
+		w.buf.WriteByte(byte(size<<4) | elemType)
+	} else {
+		w.buf.WriteByte(0xf0 | elemType)
+		w.varint(uint64(size))
+	}
+}
+
+// structField 写入嵌套结构体字段，body负责写入结构体的各字段
+func (w *thriftWriter) structField(id int16, body func()) {
+	w.fieldHeader(id, thriftStruct)
+	w.beginStruct()
+	body()
+	w.endStruct()
+}
+
+// listStruct 写入列表中的一个结构体元素
+func (w *thriftWriter) listStruct(body func()) {
+	w.beginStruct()
+	body()
+	w.endStruct()
+}
+
+// decodeHybrid 解码RLE/位打包混合编码，返回count个值
+func decodeHybrid(buf []byte, bitWidth, count int) ([]int, error) {
+	values := make([]int, 0, count)
+	byteWidth := (bitWidth + 7) / 8
+	pos := 0
+	for len(values) < count {
+		header, n := binary.Uvarint(buf[pos:])
+		if n <= 0 {
+			return nil, errors.New("无效的RLE编码")
+		}
+		pos += n
+
+		if header&1 == 0 {
+			// RLE游程：重复值按byteWidth字节小端存储
+			runLength := int(header >> 1)
+			if pos+byteWidth > len(buf) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			value := 0
+			for i := 0; i < byteWidth; i++ {
+				value |= int(buf[pos+i]) << (8 * i)
+			}
+			pos += byteWidth
+			for i := 0; i < runLength && len(values) < count; i++ {
+				values = append(values, value)
+			}
+			continue
+		}
+
+		// 位打包：每组8个值，低位在前
+		groups := int(header >> 1)
+		nBytes := groups * bitWidth
+		if pos+nBytes > len(buf) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		packed := buf[pos : pos+nBytes]
+		pos += nBytes
+		for i := 0; i < groups*8 && len(values) < count; i++ {
+			value := 0
+			for b := 0; b < bitWidth; b++ {
+				bit := i*bitWidth + b
+				if packed[bit/8]&(1<<(bit%8)) != 0 {
+					value |= 1 << b
+				}
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// decompressPage 按列的压缩格式解压页数据
+func decompressPage(codec int64, data []byte, uncompressedSize int) ([]byte, error) {
+	switch codec {
+	case parquetCodecUncompressed:
+		return data, nil
+	case parquetCodecSnappy:
+		return snappyDecode(data)
+	case parquetCodecGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		out := bytes.NewBuffer(make([]byte, 0, uncompressedSize))
+		if _, err := io.Copy(out, reader); err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("不支持的压缩格式: %d（支持UNCOMPRESSED、SNAPPY、GZIP）", codec)
+	}
+}
+
+// snappyDecode 解码Snappy块格式（Parquet使用不带分帧的原始块）
+func snappyDecode(src []byte) ([]byte, error) {
+	length, n := binary.Uvarint(src)
+	// 每个复制标签至少占2字节、最多展开为64字节，解压后的长度不会超过输入的32倍
+	if n <= 0 || length > 32*uint64(len(src)) {
+		return nil, errors.New("无效的Snappy数据")
+	}
+	dst := make([]byte, 0, length)
+	pos := n
+	for pos < len(src) {
+		tag := src[pos]
+		pos++
+
+		var literal, copyLength, offset int
+		switch tag & 0x03 {
+		case 0:
+			literal = int(tag>>2) + 1
+			if literal > 60 {
+				extra := literal - 60
+				if pos+extra > len(src) {
+					return nil, io.ErrUnexpectedEOF
+				}
+				literal = 0
+				for i := 0; i < extra; i++ {
+					literal |= int(src[pos+i]) << (8 * i)
+				}
+				literal++
+				pos += extra
+			}
+			if pos+literal > len(src) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			dst = append(dst, src[pos:pos+literal]...)
+			pos += literal
+			continue
+		case 1:
+			if pos >= len(src) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			copyLength = int(tag>>2&0x07) + 4
+			offset = int(tag>>5)<<8 | int(src[pos])
+			pos++
+		case 2:
+			if pos+2 > len(src) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			copyLength = int(tag>>2) + 1
+			offset = int(binary.LittleEndian.Uint16(src[pos:]))
+			pos += 2
+		case 3:
+			if pos+4 > len(src) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			copyLength = int(tag>>2) + 1
+			offset = int(binary.LittleEndian.Uint32(src[pos:]))
+			pos += 4
+		}
+
+		if offset <= 0 || offset > len(dst) {
+			return nil, errors.New("无效的Snappy回溯偏移")
+		}
+		// 回溯复制允许重叠，需要逐字节复制
+		start := len(dst) - offset
+		for i := 0; i < copyLength; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+	if uint64(len(dst)) != length {
+		return nil, errors.New("Snappy解压后的长度不匹配")
+	}
+	return dst, nil
+}
@@ -0,0 +1,755 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// testParquetColumn 手工构造的Parquet列，chunk为该列的全部页（页头加页数据）
+type testParquetColumn struct {
+	name       string
+	physical   int32
+	repetition int32
+	scale      int32 // 大于0时标记为DECIMAL
+	codec      int32
+	pages      [][]byte
+}
+
+// buildParquet 按列构造一个只有一个行组的Parquet文件
+func buildParquet(columns []testParquetColumn, numRows int) []byte {
+	var out bytes.Buffer
+	out.Write(parquetMagic)
+	offsets := make([]int64, len(columns))
+	sizes := make([]int64, len(columns))
+	for j, column := range columns {
+		offsets[j] = int64(out.Len())
+		for _, page := range column.pages {
+			out.Write(page)
+		}
+		sizes[j] = int64(out.Len()) - offsets[j]
+	}
+
+	footer := &thriftWriter{}
+	footer.beginStruct()
+	footer.i32(1, 1)
+	footer.listHeader(2, thriftStruct, len(columns)+1)
+	footer.listStruct(func() {
+		footer.binary(4, "schema")
+		footer.i32(5, int32(len(columns)))
+	})
+	for _, column := range columns {
+		footer.listStruct(func() {
+			footer.i32(1, column.physical)
+			footer.i32(3, column.repetition)
+			footer.binary(4, column.name)
+			if column.scale > 0 {
+				footer.i32(6, parquetConvertedDecimal)
+				footer.i32(7, column.scale)
+			}
+		})
+	}
+	footer.i64(3, int64(numRows))
+	footer.listHeader(4, thriftStruct, 1)
+	footer.listStruct(func() {
+		footer.listHeader(1, thriftStruct, len(columns))
+		for j, column := range columns {
+			footer.listStruct(func() {
+				footer.i64(2, offsets[j])
+				footer.structField(3, func() {
+					footer.i32(1, column.physical)
+					footer.listHeader(2, thriftI32, 1)
+					footer.zigzag(parquetEncodingPlain)
+					footer.listHeader(3, thriftBinary, 1)
+					footer.varint(uint64(len(column.name)))
+					footer.buf.WriteString(column.name)
+					footer.i32(4, column.codec)
+					footer.i64(5, int64(numRows))
+					footer.i64(6, sizes[j])
+					footer.i64(7, sizes[j])
+					footer.i64(9, offsets[j])
+				})
+			})
+		}
+		footer.i64(3, int64(numRows))
+	})
+	footer.endStruct()
+
+	out.Write(footer.buf.Bytes())
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(footer.buf.Len()))
+	out.Write(length[:])
+	out.Write(parquetMagic)
+	return out.Bytes()
+}
+
+// dataPage 构造v1数据页；optional列的data需以definitionLevels开头
+func dataPage(count int, encoding int32, data []byte, codec int32) []byte {
+	compressed := compressTestPage(data, codec)
+	header := &thriftWriter{}
+	header.beginStruct()
+	header.i32(1, parquetDataPage)
+	header.i32(2, int32(len(data)))
+	header.i32(3, int32(len(compressed)))
+	header.structField(5, func() {
+		header.i32(1, int32(count))
+		header.i32(2, encoding)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+	})
+	header.endStruct()
+	return append(header.buf.Bytes(), compressed...)
+}
+
+// dataPageV2 构造未压缩的v2数据页，定义级别不带长度前缀
+func dataPageV2(count, nulls int, encoding int32, levels, data []byte) []byte {
+	header := &thriftWriter{}
+	header.beginStruct()
+	header.i32(1, parquetDataPageV2)
+	header.i32(2, int32(len(levels)+len(data)))
+	header.i32(3, int32(len(levels)+len(data)))
+	header.structField(8, func() {
+		header.i32(1, int32(count))
+		header.i32(2, int32(nulls))
+		header.i32(3, int32(count))
+		header.i32(4, encoding)
+		header.i32(5, int32(len(levels)))
+		header.i32(6, 0)
+	})
+	header.endStruct()
+	page := append(header.buf.Bytes(), levels...)
+	return append(page, data...)
+}
+
+// dictionaryPage 构造PLAIN编码的字典页
+func dictionaryPage(count int, data []byte) []byte {
+	header := &thriftWriter{}
+	header.beginStruct()
+	header.i32(1, parquetDictionaryPage)
+	header.i32(2, int32(len(data)))
+	header.i32(3, int32(len(data)))
+	header.structField(7, func() {
+		header.i32(1, int32(count))
+		header.i32(2, parquetEncodingPlain)
+	})
+	header.endStruct()
+	return append(header.buf.Bytes(), data...)
+}
+
+func compressTestPage(data []byte, codec int32) []byte {
+	switch codec {
+	case parquetCodecSnappy:
+		return snappyLiteral(data)
+	case parquetCodecGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write(data)
+		w.Close()
+		return buf.Bytes()
+	}
+	return data
+}
+
+// snappyLiteral 将数据编码为只含一个字面量的Snappy块
+func snappyLiteral(data []byte) []byte {
+	out := binary.AppendUvarint(nil, uint64(len(data)))
+	if len(data) == 0 {
+		return out
+	}
+	n := len(data) - 1
+	if n < 60 {
+		out = append(out, byte(n<<2))
+	} else {
+		out = append(out, 62<<2, byte(n), byte(n>>8), byte(n>>16))
+	}
+	return append(out, data...)
+}
+
+// bitPacked 将0/1值按位打包为RLE/位打包混合编码
+func bitPacked(bits []int) []byte {
+	groups := (len(bits) + 7) / 8
+	out := binary.AppendUvarint(nil, uint64(groups<<1|1))
+	packed := make([]byte, groups)
+	for i, bit := range bits {
+		if bit != 0 {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return append(out, packed...)
+}
+
+// definitionLevels 返回带4字节长度前缀的v1定义级别
+func definitionLevels(defined []bool) []byte {
+	bits := make([]int, len(defined))
+	for i, d := range defined {
+		if d {
+			bits[i] = 1
+		}
+	}
+	levels := bitPacked(bits)
+	out := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+	return append(out, levels...)
+}
+
+func plainInt32(values ...int32) []byte {
+	var out []byte
+	for _, v := range values {
+		out = binary.LittleEndian.AppendUint32(out, uint32(v))
+	}
+	return out
+}
+
+func plainInt64(values ...int64) []byte {
+	var out []byte
+	for _, v := range values {
+		out = binary.LittleEndian.AppendUint64(out, uint64(v))
+	}
+	return out
+}
+
+func plainFloat(values ...float32) []byte {
+	var out []byte
+	for _, v := range values {
+		out = binary.LittleEndian.AppendUint32(out, math.Float32bits(v))
+	}
+	return out
+}
+
+func plainDouble(values ...float64) []byte {
+	var out []byte
+	for _, v := range values {
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(v))
+	}
+	return out
+}
+
+func plainStrings(values ...string) []byte {
+	var out []byte
+	for _, v := range values {
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(v)))
+		out = append(out, v...)
+	}
+	return out
+}
+
+func writeTempParquet(t *testing.T, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "data.parquet")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func assertDataset(t *testing.T, dataset *types.Dataset, features [][]float64, target []float64) {
+	t.Helper()
+	if dataset.NumSamples() != len(target) {
+		t.Fatalf("loaded %d rows, want %d", dataset.NumSamples(), len(target))
+	}
+	for i := range target {
+		if dataset.Target[i] != target[i] {
+			t.Errorf("target[%d] = %v, want %v", i, dataset.Target[i], target[i])
+		}
+		for j := range features[i] {
+			if got := dataset.Features[i][j]; got != features[i][j] && !(math.IsNaN(got) && math.IsNaN(features[i][j])) {
+				t.Errorf("features[%d][%d] = %v, want %v", i, j, dataset.Features[i][j], features[i][j])
+			}
+		}
+	}
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	features := [][]float64{
+		{1.5, -2, 0},
+		{math.MaxFloat64, math.SmallestNonzeroFloat64, -0.25},
+		{3, 1e-300, 42},
+	}
+	target := []float64{0.5, 1, -7}
+	dataset := types.NewDataset(features, target, []string{"a", "b", "c"})
+
+	path := filepath.Join(t.TempDir(), "data.parquet")
+	if err := SaveParquet(dataset, path, "y"); err != nil {
+		t.Fatalf("SaveParquet: %v", err)
+	}
+	loaded, err := LoadParquet(path, nil, "y")
+	if err != nil {
+		t.Fatalf("LoadParquet: %v", err)
+	}
+	if strings.Join(loaded.FeatureNames, ",") != "a,b,c" {
+		t.Errorf("feature names = %v", loaded.FeatureNames)
+	}
+	assertDataset(t, loaded, features, target)
+
+	// 指定特征列时按给定顺序读取
+	loaded, err = LoadParquet(path, []string{"c", "a"}, "y")
+	if err != nil {
+		t.Fatalf("LoadParquet with feature columns: %v", err)
+	}
+	assertDataset(t, loaded, [][]float64{{0, 1.5}, {-0.25, math.MaxFloat64}, {42, 3}}, target)
+
+	if _, err := LoadParquet(path, []string{"missing"}, "y"); err == nil || !strings.Contains(err.Error(), "未找到特征列") {
+		t.Errorf("missing feature column: error = %v", err)
+	}
+	if _, err := LoadParquet(path, nil, "missing"); err == nil || !strings.Contains(err.Error(), "未找到目标列") {
+		t.Errorf("missing target column: error = %v", err)
+	}
+}
+
+func TestParquetRoundTripNaN(t *testing.T) {
+	dataset := types.NewDataset([][]float64{{math.NaN()}, {math.Inf(-1)}}, []float64{1, 2}, []string{"x"})
+	path := filepath.Join(t.TempDir(), "data.parquet")
+	if err := SaveParquet(dataset, path, ""); err != nil {
+		t.Fatalf("SaveParquet: %v", err)
+	}
+	loaded, err := LoadParquet(path, nil, "target")
+	if err != nil {
+		t.Fatalf("LoadParquet: %v", err)
+	}
+	if !math.IsNaN(loaded.Features[0][0]) || !math.IsInf(loaded.Features[1][0], -1) {
+		t.Errorf("features = %v, want [NaN] [-Inf]", loaded.Features)
+	}
+}
+
+func TestParquetFrameRoundTrip(t *testing.T) {
+	when := time.Date(2024, 3, 1, 12, 30, 0, 123456000, time.UTC)
+	ints := types.NewIntColumn("count", []int64{-1 << 40, 0, 7})
+	flags := types.NewBoolColumn("flag", []bool{true, false, true})
+	flags.Missing = []bool{false, true, false}
+	names := types.NewStringColumn("city", []string{"北京", "", "x,y"})
+	names.Missing = []bool{false, true, false}
+	times := types.NewTimeColumn("at", []time.Time{when, when.Add(time.Hour), {}})
+	times.Missing = []bool{false, false, true}
+	floats := types.NewFloatColumn("y", []float64{0.5, math.NaN(), -2})
+	frame, err := types.NewFrame(ints, flags, names, times, floats)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "frame.parquet")
+	if err := SaveParquetFrame(frame, path); err != nil {
+		t.Fatalf("SaveParquetFrame: %v", err)
+	}
+
+	// 列按源类型写出，含缺失值的列为OPTIONAL
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	metadata, err := readParquetFooter(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := parquetSchema(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSchema := []parquetColumn{
+		{name: "count", physical: parquetInt64, repetition: parquetRequired, converted: -1},
+		{name: "flag", physical: parquetBoolean, repetition: parquetOptional, converted: -1},
+		{name: "city", physical: parquetByteArray, repetition: parquetOptional, converted: parquetConvertedUTF8},
+		{name: "at", physical: parquetInt64, repetition: parquetOptional, converted: parquetConvertedTimestampMicros},
+		{name: "y", physical: parquetDouble, repetition: parquetOptional, converted: -1},
+	}
+	if len(schema) != len(wantSchema) {
+		t.Fatalf("schema = %+v", schema)
+	}
+	for j := range wantSchema {
+		if schema[j] != wantSchema[j] {
+			t.Errorf("column %d = %+v, want %+v", j, schema[j], wantSchema[j])
+		}
+	}
+
+	loaded, err := LoadParquetFrame(path)
+	if err != nil {
+		t.Fatalf("LoadParquetFrame: %v", err)
+	}
+	if strings.Join(loaded.Names(), ",") != "count,flag,city,at,y" {
+		t.Fatalf("names = %v", loaded.Names())
+	}
+	for j, want := range frame.Columns() {
+		got := loaded.Columns()[j]
+		if got.Type != want.Type {
+			t.Errorf("%s: type = %v, want %v", want.Name, got.Type, want.Type)
+			continue
+		}
+		for i := 0; i < want.Len(); i++ {
+			if got.IsMissing(i) != want.IsMissing(i) || (!want.IsMissing(i) && got.Format(i) != want.Format(i)) {
+				t.Errorf("%s[%d] = %q (missing %v), want %q (missing %v)", want.Name, i, got.Format(i), got.IsMissing(i), want.Format(i), want.IsMissing(i))
+			}
+		}
+	}
+	if !loaded.Columns()[3].Times[0].Equal(when) {
+		t.Errorf("time = %v, want %v (microsecond precision)", loaded.Columns()[3].Times[0], when)
+	}
+
+	// 数值读取时布尔值为0/1，时间为Unix秒，空值为NaN
+	dataset, err := LoadParquet(path, []string{"count", "flag", "at"}, "y")
+	if err != nil {
+		t.Fatalf("LoadParquet: %v", err)
+	}
+	nan := math.NaN()
+	assertDataset(t, dataset, [][]float64{{-1 << 40, 1, float64(when.Unix())}, {7, 1, nan}}, []float64{0.5, -2})
+}
+
+func TestSaveParquetRejectsDuplicateNames(t *testing.T) {
+	dataset := types.NewDataset([][]float64{{1}}, []float64{2}, []string{"target"})
+	path := filepath.Join(t.TempDir(), "data.parquet")
+	if err := SaveParquet(dataset, path, ""); err == nil {
+		t.Fatal("a feature named like the target column should be rejected")
+	}
+}
+
+func TestParquetPhysicalTypes(t *testing.T) {
+	required := func(name string, physical int32, data []byte) testParquetColumn {
+		return testParquetColumn{name: name, physical: physical, pages: [][]byte{dataPage(3, parquetEncodingPlain, data, 0)}}
+	}
+	columns := []testParquetColumn{
+		required("bool", parquetBoolean, []byte{0b101}),
+		required("int32", parquetInt32, plainInt32(-3, 0, 2147483647)),
+		required("int64", parquetInt64, plainInt64(-1<<40, 7, 1<<50)),
+		required("float", parquetFloat, plainFloat(0.5, -1.25, 3)),
+		required("string", parquetByteArray, plainStrings("1.5", "-2e3", "42")),
+		{name: "decimal", physical: parquetInt32, scale: 2, pages: [][]byte{dataPage(3, parquetEncodingPlain, plainInt32(12345, -50, 0), 0)}},
+		{name: "decimal64", physical: parquetInt64, scale: 3, pages: [][]byte{dataPage(3, parquetEncodingPlain, plainInt64(1500, 2, -7000), 0)}},
+		{name: "bool_rle", physical: parquetBoolean, pages: [][]byte{dataPage(3, parquetEncodingRLE, append(plainInt32(2), bitPacked([]int{0, 1, 1})...), 0)}},
+		required("y", parquetDouble, plainDouble(1, 2, 3)),
+	}
+	path := writeTempParquet(t, buildParquet(columns, 3))
+
+	loaded, err := LoadParquet(path, nil, "y")
+	if err != nil {
+		t.Fatalf("LoadParquet: %v", err)
+	}
+	assertDataset(t, loaded, [][]float64{
+		{1, -3, -1 << 40, 0.5, 1.5, 123.45, 1.5, 0},
+		{0, 0, 7, -1.25, -2000, -0.5, 0.002, 1},
+		{1, 2147483647, 1 << 50, 3, 42, 0, -7, 1},
+	}, []float64{1, 2, 3})
+}
+
+func TestParquetUnsupportedPhysicalType(t *testing.T) {
+	for _, physical := range []int32{parquetInt96, parquetFixedLenByteArray} {
+		columns := []testParquetColumn{
+			{name: "x", physical: physical, pages: [][]byte{dataPage(1, parquetEncodingPlain, make([]byte, 12), 0)}},
+			{name: "y", physical: parquetDouble, pages: [][]byte{dataPage(1, parquetEncodingPlain, plainDouble(1), 0)}},
+		}
+		_, err := LoadParquet(writeTempParquet(t, buildParquet(columns, 1)), nil, "y")
+		if err == nil || !strings.Contains(err.Error(), "不支持的Parquet类型") {
+			t.Errorf("physical type %d: error = %v", physical, err)
+		}
+	}
+}
+
+func TestParquetNulls(t *testing.T) {
+	// x在第1、3行为空，y在第2行为空；s第1行的"n/a"无法解析为数字
+	x := testParquetColumn{name: "x", physical: parquetDouble, repetition: parquetOptional, pages: [][]byte{
+		dataPage(4, parquetEncodingPlain, append(definitionLevels([]bool{false, true, false, true}), plainDouble(2, 4)...), 0),
+	}}
+	s := testParquetColumn{name: "s", physical: parquetByteArray, repetition: parquetOptional, pages: [][]byte{
+		dataPage(4, parquetEncodingPlain, append(definitionLevels([]bool{true, true, true, true}), plainStrings("n/a", "2", "3", "4")...), 0),
+	}}
+	y := testParquetColumn{name: "y", physical: parquetInt64, repetition: parquetOptional, pages: [][]byte{
+		dataPage(4, parquetEncodingPlain, append(definitionLevels([]bool{true, false, true, true}), plainInt64(10, 30, 40)...), 0),
+	}}
+	path := writeTempParquet(t, buildParquet([]testParquetColumn{x, s, y}, 4))
+
+	loaded, err := LoadParquet(path, nil, "y")
+	if err != nil {
+		t.Fatalf("LoadParquet: %v", err)
+	}
+	// 目标为空的第2行被跳过，特征空值和无效值记为NaN
+	nan := math.NaN()
+	assertDataset(t, loaded, [][]float64{{nan, nan}, {nan, 3}, {4, 4}}, []float64{10, 30, 40})
+}
+
+func TestParquetAllTargetsNull(t *testing.T) {
+	columns := []testParquetColumn{
+		{name: "x", physical: parquetDouble, pages: [][]byte{dataPage(2, parquetEncodingPlain, plainDouble(1, 2), 0)}},
+		{name: "y", physical: parquetDouble, repetition: parquetOptional, pages: [][]byte{
+			dataPage(2, parquetEncodingPlain, definitionLevels([]bool{false, false}), 0),
+		}},
+	}
+	_, err := LoadParquet(writeTempParquet(t, buildParquet(columns, 2)), nil, "y")
+	if err == nil || !strings.Contains(err.Error(), "没有有效的数据行") {
+		t.Fatalf("error = %v, want no valid rows", err)
+	}
+}
+
+// encodedParquet 返回包含Snappy、GZIP、字典编码和v2页的5行文件，目标列为y
+func encodedParquet() []byte {
+	dictionary := dictionaryPage(3, plainDouble(1.5, 2.5, 3.5))
+	// 字典索引位宽为2：[2 0 1 2 0]
+	indices := append([]byte{2}, binary.AppendUvarint(nil, 1<<1|1)...)
+	indices = append(indices, 0b10_01_00_10, 0)
+	levels := bitPacked([]int{1, 0, 1, 1, 1})
+
+	columns := []testParquetColumn{
+		{name: "snappy", physical: parquetDouble, codec: parquetCodecSnappy, pages: [][]byte{
+			dataPage(2, parquetEncodingPlain, plainDouble(1, 2), parquetCodecSnappy),
+			dataPage(3, parquetEncodingPlain, plainDouble(3, 4, 5), parquetCodecSnappy),
+		}},
+		{name: "gzip", physical: parquetInt32, codec: parquetCodecGzip, pages: [][]byte{
+			dataPage(5, parquetEncodingPlain, plainInt32(10, 20, 30, 40, 50), parquetCodecGzip),
+		}},
+		{name: "dict", physical: parquetDouble, pages: [][]byte{
+			dictionary,
+			dataPage(5, parquetEncodingRLEDictionary, indices, 0),
+		}},
+		{name: "v2", physical: parquetDouble, repetition: parquetOptional, pages: [][]byte{
+			dataPageV2(5, 1, parquetEncodingPlain, levels, plainDouble(-1, -3, -4, -5)),
+		}},
+		{name: "y", physical: parquetDouble, pages: [][]byte{dataPage(5, parquetEncodingPlain, plainDouble(0, 1, 0, 1, 0), 0)}},
+	}
+	return buildParquet(columns, 5)
+}
+
+func TestParquetEncodingsAndCodecs(t *testing.T) {
+	loaded, err := LoadParquet(writeTempParquet(t, encodedParquet()), nil, "y")
+	if err != nil {
+		t.Fatalf("LoadParquet: %v", err)
+	}
+	assertDataset(t, loaded, [][]float64{
+		{1, 10, 3.5, -1},
+		{2, 20, 1.5, math.NaN()},
+		{3, 30, 2.5, -3},
+		{4, 40, 3.5, -4},
+		{5, 50, 1.5, -5},
+	}, []float64{0, 1, 0, 1, 0})
+}
+
+func TestParquetUnsupportedCodec(t *testing.T) {
+	columns := []testParquetColumn{
+		{name: "y", physical: parquetDouble, codec: 4, pages: [][]byte{dataPage(1, parquetEncodingPlain, plainDouble(1), 0)}},
+	}
+	_, err := LoadParquet(writeTempParquet(t, buildParquet(columns, 1)), nil, "y")
+	if err == nil || !strings.Contains(err.Error(), "不支持的压缩格式") {
+		t.Fatalf("error = %v, want an unsupported codec error", err)
+	}
+}
+
+// validParquet 返回SaveParquet写出的一个小文件的内容
+func validParquet(t *testing.T) []byte {
+	t.Helper()
+	dataset := types.NewDataset([][]float64{{1, 2}, {3, 4}, {5, 6}}, []float64{0, 1, 0}, []string{"a", "b"})
+	path := filepath.Join(t.TempDir(), "valid.parquet")
+	if err := SaveParquet(dataset, path, "y"); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestParquetMalformed(t *testing.T) {
+	valid := validParquet(t)
+	mutate := func(f func(d []byte) []byte) []byte {
+		return f(append([]byte(nil), valid...))
+	}
+	footerLength := func(d []byte, n uint32) []byte {
+		binary.LittleEndian.PutUint32(d[len(d)-8:], n)
+		return d
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"empty file", nil, "不是有效的Parquet文件"},
+		{"too short", []byte("PAR1PAR1"), "不是有效的Parquet文件"},
+		{"bad head magic", mutate(func(d []byte) []byte { d[0] = 'X'; return d }), "不是有效的Parquet文件"},
+		{"bad tail magic", mutate(func(d []byte) []byte { d[len(d)-1] = 'X'; return d }), "不是有效的Parquet文件"},
+		{"csv file", []byte("a,b,y\n1,2,3\n"), "不是有效的Parquet文件"},
+		{"footer length too large", mutate(func(d []byte) []byte { return footerLength(d, uint32(len(d))) }), "元数据长度无效"},
+		{"empty footer", mutate(func(d []byte) []byte { return footerLength(d, 0) }), "解析Parquet元数据失败"},
+		{"no columns", buildParquet(nil, 0), "没有数据列"},
+		{"repeated column", buildParquet([]testParquetColumn{
+			{name: "y", physical: parquetDouble, repetition: parquetRepeated},
+		}, 0), "不支持重复字段"},
+		{"too few values", buildParquet([]testParquetColumn{
+			{name: "y", physical: parquetDouble, pages: [][]byte{dataPage(1, parquetEncodingPlain, plainDouble(1), 0)}},
+		}, 2), "值数量"},
+		{"short plain values", buildParquet([]testParquetColumn{
+			{name: "y", physical: parquetDouble, pages: [][]byte{dataPage(2, parquetEncodingPlain, plainDouble(1), 0)}},
+		}, 2), "unexpected EOF"},
+		{"dictionary page missing", buildParquet([]testParquetColumn{
+			{name: "y", physical: parquetDouble, pages: [][]byte{dataPage(1, parquetEncodingRLEDictionary, []byte{1, 2, 0}, 0)}},
+		}, 1), "缺少字典"},
+		{"dictionary index out of range", buildParquet([]testParquetColumn{
+			{name: "y", physical: parquetDouble, pages: [][]byte{
+				dictionaryPage(1, plainDouble(1)),
+				dataPage(1, parquetEncodingRLEDictionary, []byte{1, 2, 1}, 0),
+			}},
+		}, 1), "字典索引越界"},
+		{"unknown page type", buildParquet([]testParquetColumn{
+			{name: "y", physical: parquetDouble, pages: [][]byte{{0x15, 0x0e, 0x00}}},
+		}, 1), "未知的页类型"},
+		{"negative page size", buildParquet([]testParquetColumn{
+			// 页头：类型0、未压缩大小1、压缩大小-1
+			{name: "y", physical: parquetDouble, pages: [][]byte{{0x15, 0x00, 0x15, 0x02, 0x15, 0x01, 0x00}}},
+		}, 1), "页头无效"},
+		{"too many values in page", buildParquet([]testParquetColumn{
+			{name: "y", physical: parquetDouble, pages: [][]byte{dataPage(3, parquetEncodingPlain, plainDouble(1, 2, 3), 0)}},
+		}, 2), "页头无效"},
+		{"corrupt snappy", buildParquet([]testParquetColumn{
+			{name: "y", physical: parquetDouble, codec: parquetCodecSnappy, pages: [][]byte{dataPage(1, parquetEncodingPlain, []byte{8, 0x09, 0x05}, 0)}},
+		}, 1), "解压列 y 失败"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadParquet(writeTempParquet(t, tc.data), nil, "y")
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	// 截断的文件都应返回错误
+	for n := 0; n < len(valid); n++ {
+		if _, err := LoadParquet(writeTempParquet(t, valid[:n]), nil, "y"); err == nil {
+			t.Fatalf("loading the first %d of %d bytes should fail", n, len(valid))
+		}
+	}
+}
+
+func TestParquetNestedSchema(t *testing.T) {
+	footer := &thriftWriter{}
+	footer.beginStruct()
+	footer.i32(1, 1)
+	footer.listHeader(2, thriftStruct, 3)
+	footer.listStruct(func() {
+		footer.binary(4, "schema")
+		footer.i32(5, 1)
+	})
+	footer.listStruct(func() {
+		footer.i32(3, parquetOptional)
+		footer.binary(4, "group")
+		footer.i32(5, 1)
+	})
+	footer.listStruct(func() {
+		footer.i32(1, parquetDouble)
+		footer.binary(4, "y")
+	})
+	footer.i64(3, 0)
+	footer.endStruct()
+
+	content := append([]byte(nil), parquetMagic...)
+	content = append(content, footer.buf.Bytes()...)
+	content = binary.LittleEndian.AppendUint32(content, uint32(footer.buf.Len()))
+	content = append(content, parquetMagic...)
+
+	_, err := LoadParquet(writeTempParquet(t, content), nil, "y")
+	if err == nil || !strings.Contains(err.Error(), "不支持嵌套结构") {
+		t.Fatalf("error = %v, want a nested schema error", err)
+	}
+}
+
+func TestParquetBitFlips(t *testing.T) {
+	// 翻转文件中的任意一位，加载可以失败，但不能panic
+	dir := t.TempDir()
+	for _, valid := range [][]byte{validParquet(t), encodedParquet()} {
+		for i := 0; i < len(valid); i++ {
+			for bit := 0; bit < 8; bit++ {
+				corrupted := append([]byte(nil), valid...)
+				corrupted[i] ^= 1 << bit
+				path := filepath.Join(dir, "flipped.parquet")
+				if err := os.WriteFile(path, corrupted, 0644); err != nil {
+					t.Fatal(err)
+				}
+				LoadParquet(path, nil, "y")
+			}
+		}
+	}
+}
+
+func TestDecodeHybrid(t *testing.T) {
+	tests := []struct {
+		name     string
+		buf      []byte
+		bitWidth int
+		count    int
+		want     []int
+	}{
+		{"rle run", []byte{5 << 1, 1}, 1, 5, []int{1, 1, 1, 1, 1}},
+		{"rle run truncated to count", []byte{10 << 1, 3}, 2, 3, []int{3, 3, 3}},
+		{"two byte rle value", []byte{2 << 1, 0x34, 0x12}, 13, 2, []int{0x1234, 0x1234}},
+		{"bit packed", []byte{1<<1 | 1, 0b10110010}, 1, 8, []int{0, 1, 0, 0, 1, 1, 0, 1}},
+		{"bit packed width 3", []byte{1<<1 | 1, 0b10001000, 0b11000110, 0b11111010}, 3, 8, []int{0, 1, 2, 3, 4, 5, 6, 7}},
+		{"rle then bit packed", []byte{2 << 1, 1, 1<<1 | 1, 0b00000010}, 1, 4, []int{1, 1, 0, 1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := decodeHybrid(tc.buf, tc.bitWidth, tc.count)
+			if err != nil {
+				t.Fatalf("decodeHybrid: %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+
+	for _, buf := range [][]byte{nil, {5 << 1}, {1<<1 | 1}, {0x80}} {
+		if _, err := decodeHybrid(buf, 1, 5); err == nil {
+			t.Errorf("decodeHybrid(%v) should fail", buf)
+		}
+	}
+}
+
+func TestSnappyDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		src  []byte
+		want string
+	}{
+		{"empty", []byte{0}, ""},
+		{"literal", []byte{5, 4 << 2, 'h', 'e', 'l', 'l', 'o'}, "hello"},
+		// 字面量"ab"后接1字节偏移的复制：长度6、偏移2，与已输出数据重叠
+		{"overlapping copy1", []byte{8, 1 << 2, 'a', 'b', (6-4)<<2 | 1, 2}, "abababab"},
+		{"copy2", []byte{7, 2 << 2, 'x', 'y', 'z', 2<<2 | 2, 3, 0, 0 << 2, '!'}, "xyzxyz!"},
+		{"copy4", []byte{6, 2 << 2, 'a', 'b', 'c', 2<<2 | 3, 3, 0, 0, 0}, "abcabc"},
+		{"long literal", append([]byte{70, 60 << 2, 69}, strings.Repeat("q", 70)...), strings.Repeat("q", 70)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := snappyDecode(tc.src)
+			if err != nil {
+				t.Fatalf("snappyDecode: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	malformed := map[string][]byte{
+		"empty input":       nil,
+		"truncated literal": {5, 4 << 2, 'h', 'e'},
+		"offset too large":  {6, 1 << 2, 'a', 'b', 0<<2 | 1, 3},
+		"zero offset":       {6, 1 << 2, 'a', 'b', 0<<2 | 1, 0},
+		"length mismatch":   {9, 1 << 2, 'a', 'b'},
+		"truncated copy2":   {4, 1 << 2, 'a', 'b', 1<<2 | 2, 2},
+		"truncated copy4":   {4, 1 << 2, 'a', 'b', 1<<2 | 3, 2, 0},
+		"truncated header":  {10, 61 << 2, 1},
+	}
+	for name, src := range malformed {
+		if _, err := snappyDecode(src); err == nil {
+			t.Errorf("%s: snappyDecode should fail", name)
+		}
+	}
+}
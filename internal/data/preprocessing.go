@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/feiyuluoye/Go-Model/internal/types"
 	"math"
+	"sort"
 )
 
 // StandardScaler 实现数据标准化（z-score标准化）
@@ -185,3 +186,229 @@ func (sc *MinMaxScaler) FitTransform(data *types.Dataset) (*types.Dataset, error
 	}
 	return sc.Transform(data)
 }
+
+// RobustScaler 实现基于中位数和四分位距的缩放，不受异常值影响
+type RobustScaler struct {
+	Center        []float64
+	Scale         []float64
+	QuantileLower float64 // 计算缩放尺度的下分位数，默认0.25
+	QuantileUpper float64 // 计算缩放尺度的上分位数，默认0.75
+	Fitted        bool
+}
+
+// NewRobustScaler 创建一个新的RobustScaler实例，按四分位距缩放
+func NewRobustScaler() *RobustScaler {
+	return &RobustScaler{
+		QuantileLower: 0.25,
+		QuantileUpper: 0.75,
+		Fitted:        false,
+	}
+}
+
+// Fit 计算特征的中位数和分位距，忽略NaN
+func (sc *RobustScaler) Fit(data *types.Dataset) error {
+	if data == nil || !data.IsValid() {
+		return errors.New("无效的数据集")
+	}
+	if sc.QuantileLower < 0 || sc.QuantileUpper > 1 || sc.QuantileLower >= sc.QuantileUpper {
+		return errors.New("分位数范围必须满足 0 <= 下分位数 < 上分位数 <= 1")
+	}
+
+	nSamples := data.NumSamples()
+	nFeatures := data.NumFeatures()
+
+	sc.Center = make([]float64, nFeatures)
+	sc.Scale = make([]float64, nFeatures)
+
+	column := make([]float64, 0, nSamples)
+	for i := 0; i < nFeatures; i++ {
+		column = column[:0]
+		for j := 0; j < nSamples; j++ {
+			if v := data.Features[j][i]; !math.IsNaN(v) {
+				column = append(column, v)
+			}
+		}
+		if len(column) == 0 {
+			return errors.New("存在没有观测值的特征")
+		}
+		sort.Float64s(column)
+		sc.Center[i] = quantileSorted(column, 0.5)
+		sc.Scale[i] = quantileSorted(column, sc.QuantileUpper) - quantileSorted(column, sc.QuantileLower)
+	}
+
+	sc.Fitted = true
+	return nil
+}
+
+// Transform 使用计算好的中位数和分位距对数据进行缩放
+func (sc *RobustScaler) Transform(data *types.Dataset) (*types.Dataset, error) {
+	if !sc.Fitted {
+		return nil, errors.New("scaler尚未拟合，请先调用Fit方法")
+	}
+
+	if data == nil || !data.IsValid() {
+		return nil, errors.New("无效的数据集")
+	}
+
+	nSamples := data.NumSamples()
+	nFeatures := data.NumFeatures()
+
+	if nFeatures != len(sc.Center) {
+		return nil, errors.New("特征数量不匹配")
+	}
+
+	scaledFeatures := make([][]float64, nSamples)
+	for i := 0; i < nSamples; i++ {
+		scaledFeatures[i] = make([]float64, nFeatures)
+		for j := 0; j < nFeatures; j++ {
+			if sc.Scale[j] > 0 {
+				scaledFeatures[i][j] = (data.Features[i][j] - sc.Center[j]) / sc.Scale[j]
+			} else {
+				scaledFeatures[i][j] = data.Features[i][j] - sc.Center[j] // 分位距为0时只做中心化
+			}
+		}
+	}
+
+	return types.NewDataset(scaledFeatures, data.Target, data.FeatureNames), nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (sc *RobustScaler) FitTransform(data *types.Dataset) (*types.Dataset, error) {
+	err := sc.Fit(data)
+	if err != nil {
+		return nil, err
+	}
+	return sc.Transform(data)
+}
+
+// quantileSorted 返回已排序数据的q分位数，在相邻两个值之间线性插值
+func quantileSorted(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	fraction := position - float64(lower)
+	return sorted[lower] + fraction*(sorted[lower+1]-sorted[lower])
+}
+
+// MaxAbsScaler 实现按最大绝对值缩放到[-1, 1]，不平移数据，因此保持稀疏矩阵的稀疏性
+type MaxAbsScaler struct {
+	MaxAbs []float64
+	Fitted bool
+}
+
+// NewMaxAbsScaler 创建一个新的MaxAbsScaler实例
+func NewMaxAbsScaler() *MaxAbsScaler {
+	return &MaxAbsScaler{
+		Fitted: false,
+	}
+}
+
+// Fit 计算特征的最大绝对值，忽略NaN
+func (sc *MaxAbsScaler) Fit(data *types.Dataset) error {
+	if data == nil || !data.IsValid() {
+		return errors.New("无效的数据集")
+	}
+
+	nSamples := data.NumSamples()
+	nFeatures := data.NumFeatures()
+
+	sc.MaxAbs = make([]float64, nFeatures)
+	for j := 0; j < nSamples; j++ {
+		for i := 0; i < nFeatures; i++ {
+			if v := math.Abs(data.Features[j][i]); v > sc.MaxAbs[i] {
+				sc.MaxAbs[i] = v
+			}
+		}
+	}
+
+	sc.Fitted = true
+	return nil
+}
+
+// Transform 使用计算好的最大绝对值对数据进行缩放
+func (sc *MaxAbsScaler) Transform(data *types.Dataset) (*types.Dataset, error) {
+	if !sc.Fitted {
+		return nil, errors.New("scaler尚未拟合，请先调用Fit方法")
+	}
+
+	if data == nil || !data.IsValid() {
+		return nil, errors.New("无效的数据集")
+	}
+
+	nSamples := data.NumSamples()
+	nFeatures := data.NumFeatures()
+
+	if nFeatures != len(sc.MaxAbs) {
+		return nil, errors.New("特征数量不匹配")
+	}
+
+	scaledFeatures := make([][]float64, nSamples)
+	for i := 0; i < nSamples; i++ {
+		scaledFeatures[i] = make([]float64, nFeatures)
+		for j := 0; j < nFeatures; j++ {
+			scaledFeatures[i][j] = sc.scale(data.Features[i][j], j)
+		}
+	}
+
+	return types.NewDataset(scaledFeatures, data.Target, data.FeatureNames), nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (sc *MaxAbsScaler) FitTransform(data *types.Dataset) (*types.Dataset, error) {
+	err := sc.Fit(data)
+	if err != nil {
+		return nil, err
+	}
+	return sc.Transform(data)
+}
+
+// FitSparse 在稀疏矩阵的非零元素上计算特征的最大绝对值
+func (sc *MaxAbsScaler) FitSparse(x *types.CSRMatrix) error {
+	if x == nil {
+		return errors.New("稀疏矩阵不能为空")
+	}
+
+	_, nFeatures := x.Dims()
+	sc.MaxAbs = make([]float64, nFeatures)
+	for k, j := range x.Indices {
+		if v := math.Abs(x.Data[k]); v > sc.MaxAbs[j] {
+			sc.MaxAbs[j] = v
+		}
+	}
+
+	sc.Fitted = true
+	return nil
+}
+
+// TransformSparse 缩放稀疏矩阵的非零元素，返回结构相同的新矩阵
+func (sc *MaxAbsScaler) TransformSparse(x *types.CSRMatrix) (*types.CSRMatrix, error) {
+	if !sc.Fitted {
+		return nil, errors.New("scaler尚未拟合，请先调用Fit方法")
+	}
+	if x == nil {
+		return nil, errors.New("稀疏矩阵不能为空")
+	}
+
+	rows, nFeatures := x.Dims()
+	if nFeatures != len(sc.MaxAbs) {
+		return nil, errors.New("特征数量不匹配")
+	}
+
+	scaled := make([]float64, len(x.Data))
+	for k, j := range x.Indices {
+		scaled[k] = sc.scale(x.Data[k], j)
+	}
+	indptr := append([]int(nil), x.Indptr...)
+	indices := append([]int(nil), x.Indices...)
+	return types.NewCSRMatrix(rows, nFeatures, indptr, indices, scaled)
+}
+
+// scale 缩放第j个特征的值，最大绝对值为0的特征保持不变
+func (sc *MaxAbsScaler) scale(v float64, j int) float64 {
+	if sc.MaxAbs[j] > 0 {
+		return v / sc.MaxAbs[j]
+	}
+	return v
+}
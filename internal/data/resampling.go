@@ -0,0 +1,287 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// RandomOverSample 随机有放回地复制少数类样本，使每个类别的样本数至少达到最多类别样本数的ratio倍。
+// 复制的样本追加在原样本之后
+func RandomOverSample(data *types.Dataset, ratio float64, rng *rand.Rand) (*types.Dataset, error) {
+	labels, groups, err := classGroups(data, ratio)
+	if err != nil {
+		return nil, err
+	}
+
+	result := copyDataset(data)
+	for _, label := range labels {
+		members := groups[label]
+		for n := oversampleCount(groups, label, ratio); n > 0; n-- {
+			appendSample(result, data, members[rng.Intn(len(members))])
+		}
+	}
+	return result, nil
+}
+
+// SMOTE 合成少数类过采样：每个合成样本由随机选取的少数类样本与其k个同类最近邻之一线性插值得到，
+// 使每个类别的样本数至少达到最多类别样本数的ratio倍。nominal中的特征（如类别码）不参与距离计算，
+// 也不插值，直接取所选样本的值。合成样本追加在原样本之后，权重与所选样本相同
+func SMOTE(data *types.Dataset, ratio float64, k int, nominal []int, rng *rand.Rand) (*types.Dataset, error) {
+	if k < 1 {
+		return nil, errors.New("近邻数k必须大于0")
+	}
+	labels, groups, err := classGroups(data, ratio)
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range data.Features {
+		for j, v := range row {
+			if math.IsNaN(v) {
+				return nil, fmt.Errorf("第 %d 行第 %d 列为缺失值，SMOTE前请先填充", i, j)
+			}
+		}
+	}
+	isNominal := make(map[int]bool, len(nominal))
+	for _, j := range nominal {
+		isNominal[j] = true
+	}
+
+	result := copyDataset(data)
+	for _, label := range labels {
+		n := oversampleCount(groups, label, ratio)
+		if n == 0 {
+			continue
+		}
+		members := groups[label]
+		if len(members) < 2 {
+			return nil, fmt.Errorf("类别 %v 只有 %d 个样本，SMOTE至少需要2个样本", label, len(members))
+		}
+		neighbors := nearestNeighbors(data.Features, members, min(k, len(members)-1), isNominal)
+		for ; n > 0; n-- {
+			p := rng.Intn(len(members))
+			base := data.Features[members[p]]
+			neighbor := data.Features[neighbors[p][rng.Intn(len(neighbors[p]))]]
+			gap := rng.Float64()
+
+			synthetic := make([]float64, len(base))
+			for j := range synthetic {
+				if isNominal[j] {
+					synthetic[j] = base[j]
+				} else {
+					synthetic[j] = base[j] + gap*(neighbor[j]-base[j])
+				}
+			}
+			result.Features = append(result.Features, synthetic)
+			result.Target = append(result.Target, label)
+			if len(data.Weights) > 0 {
+				result.Weights = append(result.Weights, data.Weights[members[p]])
+			}
+		}
+	}
+	return result, nil
+}
+
+// RandomUnderSample 随机无放回地删除多数类样本，使每个类别的样本数不超过最少类别样本数的1/ratio倍。
+// 保留的样本维持原有顺序
+func RandomUnderSample(data *types.Dataset, ratio float64, rng *rand.Rand) (*types.Dataset, error) {
+	labels, groups, err := classGroups(data, ratio)
+	if err != nil {
+		return nil, err
+	}
+
+	n := undersampleCount(groups, ratio)
+	var kept []int
+	for _, label := range labels {
+		members := groups[label]
+		if len(members) <= n {
+			kept = append(kept, members...)
+			continue
+		}
+		for _, p := range rng.Perm(len(members))[:n] {
+			kept = append(kept, members[p])
+		}
+	}
+	return subsetDataset(data, kept), nil
+}
+
+// NearMiss NearMiss-1欠采样：优先保留多数类中到最少类别的k个最近样本平均距离最小、即最靠近类别边界的样本，
+// 使每个类别的样本数不超过最少类别样本数的1/ratio倍。nominal中的特征不参与距离计算，保留的样本维持原有顺序
+func NearMiss(data *types.Dataset, ratio float64, k int, nominal []int) (*types.Dataset, error) {
+	if k < 1 {
+		return nil, errors.New("近邻数k必须大于0")
+	}
+	labels, groups, err := classGroups(data, ratio)
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range data.Features {
+		for j, v := range row {
+			if math.IsNaN(v) {
+				return nil, fmt.Errorf("第 %d 行第 %d 列为缺失值，NearMiss前请先填充", i, j)
+			}
+		}
+	}
+	isNominal := make(map[int]bool, len(nominal))
+	for _, j := range nominal {
+		isNominal[j] = true
+	}
+
+	minority := labels[0]
+	for _, label := range labels {
+		if len(groups[label]) < len(groups[minority]) {
+			minority = label
+		}
+	}
+	reference := groups[minority]
+	k = min(k, len(reference))
+
+	n := undersampleCount(groups, ratio)
+	var kept []int
+	for _, label := range labels {
+		members := groups[label]
+		if len(members) <= n {
+			kept = append(kept, members...)
+			continue
+		}
+		scores := make([]float64, len(members))
+		distances := make([]float64, len(reference))
+		for p, i := range members {
+			for q, other := range reference {
+				distances[q] = squaredDistance(data.Features[i], data.Features[other], isNominal)
+			}
+			sort.Float64s(distances)
+			for _, d := range distances[:k] {
+				scores[p] += math.Sqrt(d)
+			}
+		}
+		order := make([]int, len(members))
+		for p := range order {
+			order[p] = p
+		}
+		sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] < scores[order[b]] })
+		for _, p := range order[:n] {
+			kept = append(kept, members[p])
+		}
+	}
+	return subsetDataset(data, kept), nil
+}
+
+// classGroups 检查数据集和比例，按类别分组样本索引，类别按升序返回
+func classGroups(data *types.Dataset, ratio float64) ([]float64, map[float64][]int, error) {
+	if data == nil || !data.IsValid() {
+		return nil, nil, errors.New("无效的数据集")
+	}
+	if ratio <= 0 || ratio > 1 {
+		return nil, nil, errors.New("采样比例必须在(0, 1]之间")
+	}
+
+	groups := make(map[float64][]int)
+	for i, label := range data.Target {
+		if math.IsNaN(label) {
+			return nil, nil, fmt.Errorf("第 %d 个目标值为NaN", i)
+		}
+		groups[label] = append(groups[label], i)
+	}
+	if len(groups) < 2 {
+		return nil, nil, errors.New("重采样至少需要2个类别")
+	}
+	labels := make([]float64, 0, len(groups))
+	for label := range groups {
+		labels = append(labels, label)
+	}
+	sort.Float64s(labels)
+	return labels, groups, nil
+}
+
+// oversampleCount 返回类别需要新增的样本数
+func oversampleCount(groups map[float64][]int, label, ratio float64) int {
+	largest := 0
+	for _, members := range groups {
+		largest = max(largest, len(members))
+	}
+	target := int(math.Ceil(ratio * float64(largest)))
+	return max(target-len(groups[label]), 0)
+}
+
+// undersampleCount 返回欠采样后每个类别最多保留的样本数
+func undersampleCount(groups map[float64][]int, ratio float64) int {
+	smallest := math.MaxInt
+	for _, members := range groups {
+		smallest = min(smallest, len(members))
+	}
+	return int(math.Floor(float64(smallest) / ratio))
+}
+
+// squaredDistance 返回两个样本的欧氏距离平方，跳过nominal中的特征
+func squaredDistance(a, b []float64, nominal map[int]bool) float64 {
+	d := 0.0
+	for j, v := range a {
+		if !nominal[j] {
+			d += (v - b[j]) * (v - b[j])
+		}
+	}
+	return d
+}
+
+// nearestNeighbors 返回每个成员在同组成员中欧氏距离最近的k个样本的索引（不含自身），跳过nominal中的特征
+func nearestNeighbors(features [][]float64, members []int, k int, nominal map[int]bool) [][]int {
+	neighbors := make([][]int, len(members))
+	distances := make([]float64, len(members))
+	order := make([]int, len(members))
+	for p, i := range members {
+		for q, other := range members {
+			distances[q] = squaredDistance(features[i], features[other], nominal)
+			order[q] = q
+		}
+		distances[p] = math.Inf(1)
+		sort.SliceStable(order, func(a, b int) bool { return distances[order[a]] < distances[order[b]] })
+		neighbors[p] = make([]int, k)
+		for m := 0; m < k; m++ {
+			neighbors[p][m] = members[order[m]]
+		}
+	}
+	return neighbors
+}
+
+// copyDataset 复制数据集，行切片与原数据集共享
+func copyDataset(data *types.Dataset) *types.Dataset {
+	result := &types.Dataset{
+		Features:     append([][]float64(nil), data.Features...),
+		Target:       append([]float64(nil), data.Target...),
+		FeatureNames: data.FeatureNames,
+		TargetName:   data.TargetName,
+	}
+	if len(data.Weights) > 0 {
+		result.Weights = append([]float64(nil), data.Weights...)
+	}
+	return result
+}
+
+// appendSample 将原数据集的第i个样本追加到result
+func appendSample(result, data *types.Dataset, i int) {
+	result.Features = append(result.Features, append([]float64(nil), data.Features[i]...))
+	result.Target = append(result.Target, data.Target[i])
+	if len(data.Weights) > 0 {
+		result.Weights = append(result.Weights, data.Weights[i])
+	}
+}
+
+// subsetDataset 按样本索引从数据集中取出样本，索引先按升序排列以维持原有顺序
+func subsetDataset(data *types.Dataset, indices []int) *types.Dataset {
+	sort.Ints(indices)
+	result := &types.Dataset{
+		Features:     make([][]float64, 0, len(indices)),
+		Target:       make([]float64, 0, len(indices)),
+		FeatureNames: data.FeatureNames,
+		TargetName:   data.TargetName,
+	}
+	for _, i := range indices {
+		appendSample(result, data, i)
+	}
+	return result
+}
@@ -2,77 +2,169 @@ package data
 
 import (
 	"errors"
+	"fmt"
 	"github.com/feiyuluoye/Go-Model/internal/types"
+	"math"
 	"math/rand"
+	"sort"
 	"time"
 )
 
 // SplitDataset 将数据集分割为训练集和测试集
 // testRatio: 测试集比例（0-1之间）
-// shuffle: 是否随机打乱数据
-func SplitDataset(data *types.Dataset, testRatio float64, shuffle bool) (*types.Dataset, *types.Dataset, error) {
+// shuffle: 是否随机打乱数据，打乱时使用以当前时间为种子的独立随机源；需要可复现的划分时使用SplitDatasetWithRand
+// stratify: 是否按目标类别分层划分（用于分类），分层划分总是打乱数据
+func SplitDataset(data *types.Dataset, testRatio float64, shuffle, stratify bool) (*types.Dataset, *types.Dataset, error) {
+	var rng *rand.Rand
+	if shuffle || stratify {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return SplitDatasetWithRand(data, testRatio, rng, stratify)
+}
+
+// SplitDatasetWithRand 使用rng打乱后将数据集分割为训练集和测试集，rng为nil时不打乱。
+// stratify为true时按目标类别分层划分，此时rng不能为nil（见SplitIndices）。
+// rng只在本次调用中使用，不同goroutine应各自持有rng
+func SplitDatasetWithRand(data *types.Dataset, testRatio float64, rng *rand.Rand, stratify bool) (*types.Dataset, *types.Dataset, error) {
 	if data == nil || !data.IsValid() {
 		return nil, nil, errors.New("无效的数据集")
 	}
 
+	trainIndices, testIndices, err := SplitIndices(data.Target, testRatio, rng, stratify)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subset := func(indices []int) *types.Dataset {
+		features := make([][]float64, len(indices))
+		target := make([]float64, len(indices))
+		for i, idx := range indices {
+			features[i] = append([]float64(nil), data.Features[idx]...)
+			target[i] = data.Target[idx]
+		}
+		return types.NewDataset(features, target, data.FeatureNames)
+	}
+	return subset(trainIndices), subset(testIndices), nil
+}
+
+// SplitIndices 划分训练集和测试集的样本索引，测试集大小为样本数×testRatio向下取整。
+// stratify为false时按rng打乱后取末尾的样本作为测试集，rng为nil时不打乱；
+// stratify为true时按目标类别分层划分（见stratifiedIndices），此时rng不能为nil
+func SplitIndices(target []float64, testRatio float64, rng *rand.Rand, stratify bool) ([]int, []int, error) {
 	if testRatio <= 0 || testRatio >= 1 {
 		return nil, nil, errors.New("测试集比例必须在0和1之间")
 	}
+	if stratify {
+		if rng == nil {
+			return nil, nil, errors.New("分层划分需要随机数生成器")
+		}
+		return stratifiedIndices(target, testRatio, rng)
+	}
 
-	nSamples := data.NumSamples()
-	testSize := int(float64(nSamples) * testRatio)
-	trainSize := nSamples - testSize
-
-	// 创建索引数组
-	indices := make([]int, nSamples)
-	for i := 0; i < nSamples; i++ {
+	n := len(target)
+	indices := make([]int, n)
+	for i := range indices {
 		indices[i] = i
 	}
-
-	// 随机打乱索引
-	if shuffle {
-		rand.Seed(time.Now().UnixNano())
-		rand.Shuffle(nSamples, func(i, j int) {
+	if rng != nil {
+		rng.Shuffle(n, func(i, j int) {
 			indices[i], indices[j] = indices[j], indices[i]
 		})
 	}
+	trainSize := n - int(float64(n)*testRatio)
+	return indices[:trainSize], indices[trainSize:], nil
+}
 
-	// 创建训练集
-	trainFeatures := make([][]float64, trainSize)
-	trainTarget := make([]float64, trainSize)
-
-	for i := 0; i < trainSize; i++ {
-		idx := indices[i]
-		trainFeatures[i] = make([]float64, len(data.Features[idx]))
-		copy(trainFeatures[i], data.Features[idx])
-		trainTarget[i] = data.Target[idx]
+// stratifiedIndices 按目标类别分层划分样本索引，返回打乱后的训练集和测试集索引。
+// 测试集大小按比例分配到各类别，每个类别在训练集和测试集中都至少有1个样本，因此每个类别至少需要2个样本
+func stratifiedIndices(target []float64, testRatio float64, rng *rand.Rand) ([]int, []int, error) {
+	groups := make(map[float64][]int)
+	for i, label := range target {
+		if math.IsNaN(label) {
+			return nil, nil, fmt.Errorf("第 %d 个目标值为NaN，无法分层", i)
+		}
+		groups[label] = append(groups[label], i)
 	}
+	labels := make([]float64, 0, len(groups))
+	for label, members := range groups {
+		if len(members) < 2 {
+			return nil, nil, fmt.Errorf("类别 %v 只有 %d 个样本，分层划分要求每个类别至少2个样本", label, len(members))
+		}
+		labels = append(labels, label)
+	}
+	sort.Float64s(labels)
 
-	// 创建测试集
-	testFeatures := make([][]float64, testSize)
-	testTarget := make([]float64, testSize)
-
-	for i := 0; i < testSize; i++ {
-		idx := indices[trainSize+i]
-		testFeatures[i] = make([]float64, len(data.Features[idx]))
-		copy(testFeatures[i], data.Features[idx])
-		testTarget[i] = data.Target[idx]
+	n := len(target)
+	testSize := int(float64(n) * testRatio)
+	if testSize < len(labels) || n-testSize < len(labels) {
+		return nil, nil, fmt.Errorf("训练集和测试集的样本数都不能少于类别数 %d", len(labels))
 	}
 
-	// 创建训练集和测试集数据集
-	trainDataset := types.NewDataset(trainFeatures, trainTarget, data.FeatureNames)
-	testDataset := types.NewDataset(testFeatures, testTarget, data.FeatureNames)
+	// 按比例分配测试集样本数，每个类别在训练集和测试集中至少各保留1个样本；
+	// 与目标总数的差额按最大余数法增减
+	exact := make([]float64, len(labels))
+	counts := make([]int, len(labels))
+	assigned := 0
+	for k, label := range labels {
+		size := len(groups[label])
+		exact[k] = float64(size) * float64(testSize) / float64(n)
+		counts[k] = min(max(int(exact[k]), 1), size-1)
+		assigned += counts[k]
+	}
+	for assigned != testSize {
+		best := -1
+		for k, label := range labels {
+			if assigned < testSize && counts[k] < len(groups[label])-1 &&
+				(best < 0 || exact[k]-float64(counts[k]) > exact[best]-float64(counts[best])) {
+				best = k
+			}
+			if assigned > testSize && counts[k] > 1 &&
+				(best < 0 || exact[k]-float64(counts[k]) < exact[best]-float64(counts[best])) {
+				best = k
+			}
+		}
+		if assigned < testSize {
+			counts[best]++
+			assigned++
+		} else {
+			counts[best]--
+			assigned--
+		}
+	}
 
-	return trainDataset, testDataset, nil
+	var trainIndices, testIndices []int
+	for k, label := range labels {
+		members := append([]int(nil), groups[label]...)
+		rng.Shuffle(len(members), func(i, j int) {
+			members[i], members[j] = members[j], members[i]
+		})
+		testIndices = append(testIndices, members[:counts[k]]...)
+		trainIndices = append(trainIndices, members[counts[k]:]...)
+	}
+	rng.Shuffle(len(trainIndices), func(i, j int) {
+		trainIndices[i], trainIndices[j] = trainIndices[j], trainIndices[i]
+	})
+	rng.Shuffle(len(testIndices), func(i, j int) {
+		testIndices[i], testIndices[j] = testIndices[j], testIndices[i]
+	})
+	return trainIndices, testIndices, nil
 }
 
-// TrainTestSplit 是SplitDataset的便捷包装函数，默认打乱数据
-func TrainTestSplit(data *types.Dataset, testRatio float64) (*types.Dataset, *types.Dataset, error) {
-	return SplitDataset(data, testRatio, true)
+// TrainTestSplit 是SplitDataset的便捷包装函数，默认打乱数据，stratify为true时按目标类别分层
+func TrainTestSplit(data *types.Dataset, testRatio float64, stratify bool) (*types.Dataset, *types.Dataset, error) {
+	return SplitDataset(data, testRatio, true, stratify)
 }
 
-// CrossValidationSplit 将数据集分割为k折交叉验证的折
+// CrossValidationSplit 将数据集分割为k折交叉验证的折，使用以当前时间为种子的独立随机源打乱样本
 func CrossValidationSplit(data *types.Dataset, k int) ([]*types.Dataset, []*types.Dataset, error) {
+	return CrossValidationSplitWithRand(data, k, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+// CrossValidationSplitWithRand 使用rng打乱样本后将数据集分割为k折，相同种子得到相同的折
+func CrossValidationSplitWithRand(data *types.Dataset, k int, rng *rand.Rand) ([]*types.Dataset, []*types.Dataset, error) {
+	if rng == nil {
+		return nil, nil, errors.New("随机数生成器不能为空")
+	}
 	if data == nil || !data.IsValid() {
 		return nil, nil, errors.New("无效的数据集")
 	}
@@ -92,8 +184,7 @@ func CrossValidationSplit(data *types.Dataset, k int) ([]*types.Dataset, []*type
 		indices[i] = i
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(nSamples, func(i, j int) {
+	rng.Shuffle(nSamples, func(i, j int) {
 		indices[i], indices[j] = indices[j], indices[i]
 	})
 
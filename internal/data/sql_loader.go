@@ -0,0 +1,134 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// LoadSQL 执行查询并将结果集构建为数据集，除目标列外的所有结果列都作为特征
+// db: 数据库连接（Postgres、MySQL等任意database/sql驱动）
+// query: 查询语句，args为查询参数
+// targetColumn: 目标变量列名（不区分大小写）
+// 目标值为NULL或不是有效数字的行会被跳过；特征值为NULL或无效时记为NaN，与LoadCSVWithMissing一致，
+// 可用Imputer填充
+func LoadSQL(db *sql.DB, query string, targetColumn string, args ...interface{}) (*types.Dataset, error) {
+	if db == nil {
+		return nil, errors.New("数据库连接不能为空")
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("执行查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("读取结果列失败: %w", err)
+	}
+
+	targetIndex := -1
+	for i, name := range columns {
+		if strings.EqualFold(name, targetColumn) {
+			targetIndex = i
+			break
+		}
+	}
+	if targetIndex == -1 {
+		return nil, fmt.Errorf("未找到目标列: %s", targetColumn)
+	}
+
+	featureNames := make([]string, 0, len(columns)-1)
+	for i, name := range columns {
+		if i != targetIndex {
+			featureNames = append(featureNames, name)
+		}
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var features [][]float64
+	var target []float64
+	for line := 1; rows.Next(); line++ {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("读取第 %d 行失败: %w", line, err)
+		}
+
+		if values[targetIndex] == nil {
+			log.Printf("警告: 第 %d 行的目标值为NULL，跳过此行", line)
+			continue
+		}
+		value, err := sqlToFloat64(values[targetIndex])
+		if err != nil {
+			log.Printf("警告: 第 %d 行的目标值不是有效数字，跳过此行: %v", line, err)
+			continue
+		}
+
+		row := make([]float64, 0, len(featureNames))
+		for j, raw := range values {
+			if j == targetIndex {
+				continue
+			}
+			if raw == nil {
+				row = append(row, math.NaN())
+				continue
+			}
+			val, err := sqlToFloat64(raw)
+			if err != nil {
+				log.Printf("警告: 第 %d 行列 %s 的值不是有效数字，记为缺失值", line, columns[j])
+				val = math.NaN()
+			}
+			row = append(row, val)
+		}
+		features = append(features, row)
+		target = append(target, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取查询结果失败: %w", err)
+	}
+
+	if len(features) == 0 {
+		return nil, errors.New("查询结果中没有有效的数据行")
+	}
+	return types.NewDataset(features, target, featureNames), nil
+}
+
+// sqlToFloat64 将驱动返回的值转换为float64：
+// DECIMAL/NUMERIC等以[]byte返回的数值按文本解析，布尔值转换为0/1，时间转换为Unix秒
+func sqlToFloat64(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case []byte:
+		return strconv.ParseFloat(strings.TrimSpace(string(v)), 64)
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case uint64:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case time.Time:
+		return float64(v.Unix()), nil
+	default:
+		return toFloat64(val)
+	}
+}
@@ -26,7 +26,7 @@ func TestDataPipeline(t *testing.T) {
 	}
 
 	// 分割训练集和测试集
-	trainData, testData, err := TrainTestSplit(polyData, 0.2)
+	trainData, testData, err := TrainTestSplit(polyData, 0.2, false)
 	if err != nil {
 		log.Fatalf("分割数据集失败: %v", err)
 	}
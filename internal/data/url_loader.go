@@ -0,0 +1,52 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// DefaultURLTimeout LoadCSVFromURL下载数据的默认超时，包括读取响应体的时间
+const DefaultURLTimeout = 5 * time.Minute
+
+// defaultURLClient 未指定客户端时使用的HTTP客户端，避免服务端无响应时永久阻塞
+var defaultURLClient = &http.Client{Timeout: DefaultURLTimeout}
+
+// LoadCSVFromURL 通过HTTP(S)下载并解析CSV数据，适用于HTTP接口和S3预签名URL等数据源。
+// 响应体按魔数自动解压gzip/zstd/bzip2；请求超时为DefaultURLTimeout，
+// 需要自定义超时、代理或认证时使用LoadCSVFromURLWithClient
+func LoadCSVFromURL(rawURL string, targetColumn interface{}, hasHeader bool) (*types.Dataset, error) {
+	return LoadCSVFromURLWithClient(nil, rawURL, targetColumn, hasHeader)
+}
+
+// LoadCSVFromURLWithClient 使用指定的HTTP客户端下载并解析CSV数据，client为nil时使用超时为DefaultURLTimeout的客户端
+func LoadCSVFromURLWithClient(client *http.Client, rawURL string, targetColumn interface{}, hasHeader bool) (*types.Dataset, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("无效的URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("不支持的URL协议: %s", u.Scheme)
+	}
+	if client == nil {
+		client = defaultURLClient
+	}
+
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("请求URL失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("请求URL失败: HTTP状态 %s", resp.Status)
+	}
+
+	body, err := decompressReader(resp.Body, u.Path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadCSVFromReader(body, hasHeader, targetColumn)
+}
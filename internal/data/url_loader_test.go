@@ -0,0 +1,106 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const urlTestCSV = "x1,x2,y\n1,2,3\n4,5,9\n"
+
+func TestLoadCSVFromURL(t *testing.T) {
+	var gzipped bytes.Buffer
+	w := gzip.NewWriter(&gzipped)
+	w.Write([]byte(urlTestCSV))
+	w.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/data.csv.gz" {
+			w.Write(gzipped.Bytes())
+			return
+		}
+		w.Write([]byte(urlTestCSV))
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/data.csv", "/data.csv.gz"} {
+		dataset, err := LoadCSVFromURL(server.URL+path, "y", true)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if dataset.NumSamples() != 2 || dataset.Target[1] != 9 || dataset.Features[1][0] != 4 {
+			t.Errorf("%s: features = %v, target = %v", path, dataset.Features, dataset.Target)
+		}
+	}
+}
+
+func TestLoadCSVFromURLStatusError(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusForbidden, http.StatusInternalServerError} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 错误响应体不能被当作CSV数据解析
+			w.WriteHeader(status)
+			w.Write([]byte(urlTestCSV))
+		}))
+		_, err := LoadCSVFromURL(server.URL+"/data.csv", "y", true)
+		server.Close()
+		if err == nil || !strings.Contains(err.Error(), http.StatusText(status)) {
+			t.Errorf("status %d: error = %v, want an HTTP status error", status, err)
+		}
+	}
+}
+
+func TestLoadCSVFromURLTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-body" {
+			// 先返回响应头和部分数据，再停止发送
+			w.Write([]byte("x1,x2,y\n"))
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := &http.Client{Timeout: 100 * time.Millisecond}
+	for _, path := range []string{"/slow-headers", "/slow-body"} {
+		start := time.Now()
+		_, err := LoadCSVFromURLWithClient(client, server.URL+path, "y", true)
+		if err == nil {
+			t.Fatalf("%s: a stalled response should fail", path)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("%s: returned after %v, want the client timeout to apply", path, elapsed)
+		}
+	}
+}
+
+func TestLoadCSVFromURLDefaultTimeout(t *testing.T) {
+	if defaultURLClient.Timeout != DefaultURLTimeout || DefaultURLTimeout <= 0 {
+		t.Fatalf("default client timeout = %v, want %v", defaultURLClient.Timeout, DefaultURLTimeout)
+	}
+}
+
+func TestLoadCSVFromURLInvalid(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"ftp://example.com/data.csv", "不支持的URL协议"},
+		{"data.csv", "不支持的URL协议"},
+		{"http://[::1", "无效的URL"},
+	}
+	for _, tc := range tests {
+		_, err := LoadCSVFromURL(tc.url, "y", true)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: error = %v, want it to contain %q", tc.url, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// 数据质量问题的类型
+const (
+	IssueRowMismatch   = "row_mismatch"     // 特征行长度、目标变量或样本权重的长度与样本数不一致
+	IssueMissing       = "missing"          // 含缺失值(NaN)
+	IssueInfinite      = "infinite"         // 含无穷值(±Inf)
+	IssueConstant      = "constant_column"  // 特征为常数（缺失值除外）
+	IssueDuplicate     = "duplicate_column" // 特征与另一列完全相同
+	IssueTargetLeakage = "target_leakage"   // 特征与目标变量完全相同
+)
+
+// QualityIssue 数据集中发现的一个问题
+type QualityIssue struct {
+	Type    string `json:"type"`
+	Column  string `json:"column,omitempty"`  // 相关的特征，为空时表示目标变量或整个数据集
+	Related string `json:"related,omitempty"` // 重复列问题中与之相同的另一列
+	Count   int    `json:"count,omitempty"`   // 缺失值或无穷值的个数
+	Fatal   bool   `json:"fatal"`             // 为true时不能在该数据上训练
+	Message string `json:"message"`
+}
+
+// QualityReport 数据集的质量检查结果
+type QualityReport struct {
+	Samples  int            `json:"samples"`
+	Features int            `json:"features"`
+	Issues   []QualityIssue `json:"issues"`
+	Valid    bool           `json:"valid"` // 没有致命问题
+}
+
+// Validator 数据集质量检查器。行数不一致、无穷值、目标变量缺失和目标泄漏总是致命问题，
+// 特征缺失值、常数列和重复列是否致命由对应字段决定
+type Validator struct {
+	AllowMissing    bool // 特征缺失值不致命，如后续会先填充
+	RejectConstant  bool // 常数列为致命问题
+	RejectDuplicate bool // 重复列为致命问题
+}
+
+// NewValidator 创建默认的检查器：特征缺失值为致命问题，常数列和重复列只作为告警
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// Validate 检查数据集并返回质量报告；行数不一致时不再做逐列检查
+func (v *Validator) Validate(data *types.Dataset) (*QualityReport, error) {
+	if data == nil || data.NumSamples() == 0 || data.NumFeatures() == 0 {
+		return nil, errors.New("数据集为空")
+	}
+	n, c := data.NumSamples(), data.NumFeatures()
+	names := data.FeatureNames
+	if len(names) != c {
+		names = make([]string, c)
+		for j := range names {
+			names[j] = fmt.Sprintf("feature_%d", j)
+		}
+	}
+	report := &QualityReport{Samples: n, Features: c}
+	add := func(issue QualityIssue) {
+		report.Issues = append(report.Issues, issue)
+	}
+
+	for i, row := range data.Features {
+		if len(row) != c {
+			add(QualityIssue{Type: IssueRowMismatch, Fatal: true, Message: fmt.Sprintf("第 %d 行有 %d 个特征，应为 %d 个", i, len(row), c)})
+			return report, nil
+		}
+	}
+	if len(data.Target) != n {
+		add(QualityIssue{Type: IssueRowMismatch, Fatal: true, Message: fmt.Sprintf("目标变量长度(%d)与样本数(%d)不一致", len(data.Target), n)})
+	}
+	if len(data.Weights) > 0 && len(data.Weights) != n {
+		add(QualityIssue{Type: IssueRowMismatch, Fatal: true, Message: fmt.Sprintf("样本权重长度(%d)与样本数(%d)不一致", len(data.Weights), n)})
+	}
+	if len(report.Issues) > 0 {
+		return report, nil
+	}
+
+	missing, infinite := countNonFinite(data.Target)
+	if missing > 0 {
+		add(QualityIssue{Type: IssueMissing, Count: missing, Fatal: true, Message: fmt.Sprintf("目标变量有 %d 个缺失值", missing)})
+	}
+	if infinite > 0 {
+		add(QualityIssue{Type: IssueInfinite, Count: infinite, Fatal: true, Message: fmt.Sprintf("目标变量有 %d 个无穷值", infinite)})
+	}
+
+	columns := make([][]float64, c)
+	constant := make([]bool, c)
+	for j := range columns {
+		columns[j] = make([]float64, n)
+		for i, row := range data.Features {
+			columns[j][i] = row[j]
+		}
+		missing, infinite := countNonFinite(columns[j])
+		if missing > 0 {
+			add(QualityIssue{Type: IssueMissing, Column: names[j], Count: missing, Fatal: !v.AllowMissing, Message: fmt.Sprintf("特征%s有 %d 个缺失值", names[j], missing)})
+		}
+		if infinite > 0 {
+			add(QualityIssue{Type: IssueInfinite, Column: names[j], Count: infinite, Fatal: true, Message: fmt.Sprintf("特征%s有 %d 个无穷值", names[j], infinite)})
+		}
+		if n > 1 && isConstant(columns[j]) {
+			constant[j] = true
+			add(QualityIssue{Type: IssueConstant, Column: names[j], Fatal: v.RejectConstant, Message: fmt.Sprintf("特征%s为常数，不含任何信息", names[j])})
+		}
+	}
+
+	for j := range columns {
+		if constant[j] {
+			continue
+		}
+		for i := 0; i < j; i++ {
+			if !constant[i] && identical(columns[i], columns[j]) {
+				add(QualityIssue{Type: IssueDuplicate, Column: names[j], Related: names[i], Fatal: v.RejectDuplicate, Message: fmt.Sprintf("特征%s与%s完全相同", names[j], names[i])})
+				break
+			}
+		}
+		if identical(columns[j], data.Target) {
+			add(QualityIssue{Type: IssueTargetLeakage, Column: names[j], Fatal: true, Message: fmt.Sprintf("特征%s与目标变量完全相同，存在目标泄漏", names[j])})
+		}
+	}
+
+	report.Valid = true
+	for _, issue := range report.Issues {
+		if issue.Fatal {
+			report.Valid = false
+		}
+	}
+	return report, nil
+}
+
+// countNonFinite 返回缺失值和无穷值的个数
+func countNonFinite(values []float64) (missing, infinite int) {
+	for _, v := range values {
+		if math.IsNaN(v) {
+			missing++
+		} else if math.IsInf(v, 0) {
+			infinite++
+		}
+	}
+	return missing, infinite
+}
+
+// isConstant 判断除缺失值外的取值是否全部相同，全为缺失值时也视为常数
+func isConstant(values []float64) bool {
+	first := math.NaN()
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		if math.IsNaN(first) {
+			first = v
+		} else if v != first {
+			return false
+		}
+	}
+	return true
+}
+
+// identical 判断两列在每一行都相同，同一行都为缺失值也视为相同
+func identical(a, b []float64) bool {
+	for i, v := range a {
+		if v != b[i] && !(math.IsNaN(v) && math.IsNaN(b[i])) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package data
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// SaveCSV 将数据集保存为带表头的CSV文件，特征列在前、目标列在最后
+// targetName: 目标列的列名，为空时使用"target"
+// 缺失值(NaN)写为空字符串，可用LoadCSVWithMissing按原样读回
+func SaveCSV(data *types.Dataset, filePath string, targetName string) error {
+	return saveFile(filePath, "CSV", func(w io.Writer) error {
+		return WriteCSV(w, data, targetName)
+	})
+}
+
+// WriteCSV 将数据集以CSV格式写入writer，格式与SaveCSV一致
+func WriteCSV(w io.Writer, data *types.Dataset, targetName string) error {
+	if data == nil || !data.IsValid() {
+		return errors.New("无效的数据集")
+	}
+	names, err := outputColumns(data, targetName)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(names); err != nil {
+		return fmt.Errorf("写入CSV数据失败: %w", err)
+	}
+	record := make([]string, len(names))
+	for i, row := range data.Features {
+		for j, v := range row {
+			record[j] = formatCSVValue(v)
+		}
+		record[len(row)] = formatCSVValue(data.Target[i])
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入CSV数据失败: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("写入CSV数据失败: %w", err)
+	}
+	return nil
+}
+
+// SaveJSON 将数据集保存为JSON对象数组，每个样本一个对象，键为特征名和目标列名，可用LoadJSON读回
+// targetName: 目标列的列名，为空时使用"target"
+// JSON不能表示NaN和无穷大，这些值写为null
+func SaveJSON(data *types.Dataset, filePath string, targetName string) error {
+	return saveFile(filePath, "JSON", func(w io.Writer) error {
+		return WriteJSON(w, data, targetName)
+	})
+}
+
+// WriteJSON 将数据集以JSON格式写入writer，格式与SaveJSON一致，对象中的键按列的顺序排列
+func WriteJSON(w io.Writer, data *types.Dataset, targetName string) error {
+	if data == nil || !data.IsValid() {
+		return errors.New("无效的数据集")
+	}
+	names, err := outputColumns(data, targetName)
+	if err != nil {
+		return err
+	}
+	keys := make([][]byte, len(names))
+	for j, name := range names {
+		keys[j], _ = json.Marshal(name)
+	}
+
+	buf := bufio.NewWriter(w)
+	buf.WriteString("[")
+	for i, row := range data.Features {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {")
+		for j := range names {
+			v := data.Target[i]
+			if j < len(row) {
+				v = row[j]
+			}
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			buf.Write(keys[j])
+			buf.WriteString(": ")
+			buf.WriteString(formatJSONValue(v))
+		}
+		buf.WriteString("}")
+	}
+	buf.WriteString("\n]\n")
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("写入JSON数据失败: %w", err)
+	}
+	return nil
+}
+
+// outputColumns 返回写出数据集时的列名：特征名（缺失时为feature_i）加目标列名，列名重复时返回错误
+func outputColumns(data *types.Dataset, targetName string) ([]string, error) {
+	if targetName == "" {
+		targetName = "target"
+	}
+	nFeatures := data.NumFeatures()
+	names := make([]string, 0, nFeatures+1)
+	for j := 0; j < nFeatures; j++ {
+		name := fmt.Sprintf("feature_%d", j)
+		if j < len(data.FeatureNames) && data.FeatureNames[j] != "" {
+			name = data.FeatureNames[j]
+		}
+		names = append(names, name)
+	}
+	names = append(names, targetName)
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("列名重复: %s", name)
+		}
+		seen[name] = true
+	}
+	return names, nil
+}
+
+// saveFile 创建文件并调用write写入内容，写入失败时删除不完整的文件
+func saveFile(filePath, format string, write func(io.Writer) error) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("创建%s文件失败: %w", format, err)
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(filePath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(filePath)
+		return fmt.Errorf("写入%s文件失败: %w", format, err)
+	}
+	return nil
+}
+
+// formatCSVValue 以最短的可精确还原的形式格式化数值，NaN写为空字符串
+func formatCSVValue(v float64) string {
+	if math.IsNaN(v) {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// formatJSONValue 格式化JSON数值，NaN和无穷大写为null
+func formatJSONValue(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "null"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,316 @@
+package data
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// xlsxWorkbook xl/workbook.xml中的工作表列表
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships xl/_rels/workbook.xml.rels中工作表ID到文件路径的映射
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText 共享字符串或内联字符串，富文本由多个run拼接
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String 返回拼接后的文本
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	b.WriteString(t.T)
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+// xlsxSharedStrings xl/sharedStrings.xml
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxWorksheet 工作表中的单元格数据
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// LoadXLSX 从Excel(.xlsx)文件加载数据
+// filePath: xlsx文件路径
+// sheet: 工作表名称，为空时使用第一个工作表
+// hasHeader: 首行是否为表头
+// targetColumn: 目标变量列名或索引
+// 空行会被忽略；目标值无效的行会被跳过，无效或空白的特征值记为NaN，与LoadCSVWithMissing的处理方式一致
+func LoadXLSX(filePath string, sheet string, hasHeader bool, targetColumn interface{}) (*types.Dataset, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开xlsx文件: %w", err)
+	}
+	defer archive.Close()
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := xlsxSheetPath(files, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedStrings xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(f, &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("xlsx文件中缺少工作表数据: %s", sheetPath)
+	}
+	var worksheet xlsxWorksheet
+	if err := decodeXLSXPart(f, &worksheet); err != nil {
+		return nil, err
+	}
+
+	records, err := xlsxRecords(worksheet, sharedStrings)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("工作表为空")
+	}
+
+	return recordsToDataset(records, hasHeader, targetColumn)
+}
+
+// xlsxSheetPath 根据工作表名称查找对应的XML文件路径
+func xlsxSheetPath(files map[string]*zip.File, sheet string) (string, error) {
+	f, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("不是有效的xlsx文件: 缺少xl/workbook.xml")
+	}
+	var workbook xlsxWorkbook
+	if err := decodeXLSXPart(f, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("xlsx文件中没有工作表")
+	}
+
+	rid := ""
+	if sheet == "" {
+		rid = workbook.Sheets[0].RID
+	} else {
+		names := make([]string, len(workbook.Sheets))
+		for i, s := range workbook.Sheets {
+			names[i] = s.Name
+			if s.Name == sheet {
+				rid = s.RID
+			}
+		}
+		if rid == "" {
+			return "", fmt.Errorf("未找到工作表: %s（可用工作表: %s）", sheet, strings.Join(names, ", "))
+		}
+	}
+
+	f, ok = files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "", errors.New("不是有效的xlsx文件: 缺少xl/_rels/workbook.xml.rels")
+	}
+	var rels xlsxRelationships
+	if err := decodeXLSXPart(f, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != rid {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("未找到工作表关系: %s", rid)
+}
+
+// decodeXLSXPart 解析压缩包中的XML部件
+func decodeXLSXPart(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("读取%s失败: %w", f.Name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("解析%s失败: %w", f.Name, err)
+	}
+	return nil
+}
+
+// xlsxRecords 将工作表转换为按列对齐的字符串记录，缺失的单元格为空字符串
+func xlsxRecords(worksheet xlsxWorksheet, sharedStrings xlsxSharedStrings) ([][]string, error) {
+	records := make([][]string, 0, len(worksheet.Rows))
+	width := 0
+	for _, row := range worksheet.Rows {
+		var record []string
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				var err error
+				if col, err = xlsxColumnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(sharedStrings.Items) {
+					return nil, fmt.Errorf("单元格 %s 的共享字符串索引无效: %s", cell.Ref, cell.Value)
+				}
+				value = sharedStrings.Items[idx].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			}
+
+			for len(record) <= col {
+				record = append(record, "")
+			}
+			record[col] = value
+		}
+
+		empty := true
+		for _, value := range record {
+			if strings.TrimSpace(value) != "" {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			continue
+		}
+		records = append(records, record)
+		width = max(width, len(record))
+	}
+
+	for i, record := range records {
+		for len(record) < width {
+			record = append(record, "")
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// xlsxColumnIndex 将单元格引用（如"AB12"）转换为从0开始的列索引
+func xlsxColumnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("无效的单元格引用: %s", ref)
+	}
+	return col - 1, nil
+}
+
+// recordsToDataset 按LoadCSV的规则将字符串记录转换为数据集
+func recordsToDataset(records [][]string, hasHeader bool, targetColumn interface{}) (*types.Dataset, error) {
+	header := records[0]
+	targetIndex, err := targetColumnIndex(header, hasHeader, targetColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	featureNames := make([]string, 0, len(header)-1)
+	for i, name := range header {
+		if i == targetIndex {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if !hasHeader || name == "" {
+			name = fmt.Sprintf("feature_%d", len(featureNames))
+		}
+		featureNames = append(featureNames, name)
+	}
+
+	start := 0
+	if hasHeader {
+		start = 1
+	}
+	features := make([][]float64, 0, len(records)-start)
+	target := make([]float64, 0, len(records)-start)
+	for i := start; i < len(records); i++ {
+		record := records[i]
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[targetIndex]), 64)
+		if err != nil {
+			log.Printf("警告: 第 %d 行的目标值 '%s' 不是有效数字，跳过此行", i+1, record[targetIndex])
+			continue
+		}
+
+		row := make([]float64, 0, len(featureNames))
+		for j, field := range record {
+			if j == targetIndex {
+				continue
+			}
+			field = strings.TrimSpace(field)
+			if field == "" {
+				row = append(row, math.NaN())
+				continue
+			}
+			val, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				log.Printf("警告: 第 %d 行列 %d 的值 '%s' 不是有效数字，记为缺失值", i+1, j, field)
+				val = math.NaN()
+			}
+			row = append(row, val)
+		}
+		features = append(features, row)
+		target = append(target, value)
+	}
+
+	if len(features) == 0 {
+		return nil, errors.New("没有有效的数据行")
+	}
+	return types.NewDataset(features, target, featureNames), nil
+}
@@ -0,0 +1,1007 @@
+package data
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// zstd格式常量（RFC 8878）
+const (
+	zstdMagic          = 0xFD2FB528
+	zstdSkippableMagic = 0x184D2A50
+	zstdSkippableMask  = 0xFFFFFFF0
+	zstdMaxBlockSize   = 128 << 10
+	zstdMaxWindowSize  = 1 << 31
+)
+
+// 序列解码中字面量长度、匹配长度码对应的基础值和附加位数
+var (
+	zstdLiteralLengthBase = [36]int{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		16, 18, 20, 22, 24, 28, 32, 40, 48, 64, 128, 256, 512, 1024, 2048, 4096,
+		8192, 16384, 32768, 65536,
+	}
+	zstdLiteralLengthBits = [36]int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 6, 7, 8, 9, 10, 11, 12,
+		13, 14, 15, 16,
+	}
+	zstdMatchLengthBase = [53]int{
+		3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18,
+		19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34,
+		35, 37, 39, 41, 43, 47, 51, 59, 67, 83, 99, 131, 259, 515, 1027, 2051,
+		4099, 8195, 16387, 32771, 65539,
+	}
+	zstdMatchLengthBits = [53]int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 7, 8, 9, 10, 11,
+		12, 13, 14, 15, 16,
+	}
+)
+
+// 预定义的FSE分布
+var (
+	zstdPredefinedLiteralLengths = mustZstdFSETable([]int{
+		4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+		-1, -1, -1, -1,
+	}, 6)
+	zstdPredefinedMatchLengths = mustZstdFSETable([]int{
+		1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
+		-1, -1, -1, -1, -1,
+	}, 6)
+	zstdPredefinedOffsets = mustZstdFSETable([]int{
+		1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+	}, 5)
+)
+
+// zstdReader 流式解压zstd数据，只在内存中保留一个窗口大小的历史数据
+type zstdReader struct {
+	r        *bufio.Reader
+	inFrame  bool
+	last     bool // 当前帧的最后一个块已解码
+	checksum bool
+	hash     zstdXXH64 // 帧内容的校验和，checksum为true时计算
+	size     uint64    // 当前帧已解码的字节数
+	declared int64     // 帧头声明的内容大小，未声明时为-1
+	window   int
+	history  []byte
+	out      []byte // 已解码但尚未读取的数据，指向history的末尾
+	block    []byte
+	literals []byte
+	rep      [3]int
+
+	// 同一帧内后续块可重复使用的熵编码表
+	huffman        *zstdHuffmanTable
+	literalLengths *zstdFSETable
+	offsets        *zstdFSETable
+	matchLengths   *zstdFSETable
+
+	err error
+}
+
+// newZstdReader 创建zstd解压reader，支持多个连续帧和可跳过帧，不支持字典
+func newZstdReader(r io.Reader) *zstdReader {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &zstdReader{r: br}
+}
+
+// Read 实现io.Reader
+func (z *zstdReader) Read(p []byte) (int, error) {
+	for len(z.out) == 0 {
+		if z.err != nil {
+			return 0, z.err
+		}
+		z.err = z.advance()
+	}
+	n := copy(p, z.out)
+	z.out = z.out[n:]
+	return n, nil
+}
+
+// advance 读取下一个帧头或解码下一个块
+func (z *zstdReader) advance() error {
+	if !z.inFrame {
+		return z.readFrameHeader()
+	}
+	if z.last {
+		if z.declared >= 0 && uint64(z.declared) != z.size {
+			return fmt.Errorf("zstd帧的内容大小为%d字节，与帧头声明的%d字节不一致", z.size, z.declared)
+		}
+		if z.checksum {
+			// 内容校验和为XXH64的低32位
+			var sum [4]byte
+			if _, err := io.ReadFull(z.r, sum[:]); err != nil {
+				return unexpectedEOF(err)
+			}
+			if binary.LittleEndian.Uint32(sum[:]) != uint32(z.hash.sum()) {
+				return errors.New("zstd数据校验和不匹配")
+			}
+		}
+		z.inFrame = false
+		return nil
+	}
+	return z.decodeBlock()
+}
+
+// readFrameHeader 解析帧头；数据在帧边界处结束时返回io.EOF
+func (z *zstdReader) readFrameHeader() error {
+	var buf [8]byte
+	if _, err := io.ReadFull(z.r, buf[:4]); err != nil {
+		return err
+	}
+	magic := binary.LittleEndian.Uint32(buf[:4])
+	if magic&zstdSkippableMask == zstdSkippableMagic {
+		if _, err := io.ReadFull(z.r, buf[:4]); err != nil {
+			return unexpectedEOF(err)
+		}
+		if _, err := z.r.Discard(int(binary.LittleEndian.Uint32(buf[:4]))); err != nil {
+			return unexpectedEOF(err)
+		}
+		return nil
+	}
+	if magic != zstdMagic {
+		return errors.New("不是有效的zstd数据")
+	}
+
+	descriptor, err := z.r.ReadByte()
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+	if descriptor&0x08 != 0 {
+		return errors.New("zstd帧头的保留位不为0")
+	}
+	singleSegment := descriptor&0x20 != 0
+	z.checksum = descriptor&0x04 != 0
+
+	window := 0
+	if !singleSegment {
+		wd, err := z.r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		base := 1 << (10 + int(wd>>3))
+		window = base + base/8*int(wd&7)
+	}
+
+	dictSize := [4]int{0, 1, 2, 4}[descriptor&3]
+	if _, err := io.ReadFull(z.r, buf[:dictSize]); err != nil {
+		return unexpectedEOF(err)
+	}
+	for _, b := range buf[:dictSize] {
+		if b != 0 {
+			return errors.New("不支持使用字典压缩的zstd数据")
+		}
+	}
+
+	fcsSize := [4]int{0, 2, 4, 8}[descriptor>>6]
+	if fcsSize == 0 && singleSegment {
+		fcsSize = 1
+	}
+	if _, err := io.ReadFull(z.r, buf[:fcsSize]); err != nil {
+		return unexpectedEOF(err)
+	}
+	var contentSize uint64
+	for i := fcsSize - 1; i >= 0; i-- {
+		contentSize = contentSize<<8 | uint64(buf[i])
+	}
+	if fcsSize == 2 {
+		contentSize += 256
+	}
+	if singleSegment {
+		if contentSize > zstdMaxWindowSize {
+			return fmt.Errorf("zstd窗口过大: %d", contentSize)
+		}
+		window = int(contentSize)
+	}
+	if window > zstdMaxWindowSize {
+		return fmt.Errorf("zstd窗口过大: %d", window)
+	}
+
+	z.inFrame = true
+	z.last = false
+	z.hash.reset()
+	z.size = 0
+	z.declared = -1
+	if fcsSize > 0 {
+		z.declared = int64(contentSize)
+	}
+	z.window = window
+	z.history = z.history[:0]
+	z.rep = [3]int{1, 4, 8}
+	z.huffman = nil
+	z.literalLengths, z.offsets, z.matchLengths = nil, nil, nil
+	return nil
+}
+
+// decodeBlock 解码一个块并将结果追加到history
+func (z *zstdReader) decodeBlock() error {
+	var header [3]byte
+	if _, err := io.ReadFull(z.r, header[:]); err != nil {
+		return unexpectedEOF(err)
+	}
+	h := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	z.last = h&1 != 0
+	size := h >> 3
+	if size > zstdMaxBlockSize {
+		return fmt.Errorf("zstd块过大: %d", size)
+	}
+
+	// 历史数据超过两倍窗口时只保留最近一个窗口，此时out已全部读取
+	if keep := max(z.window, zstdMaxBlockSize); len(z.history) > 2*keep {
+		n := copy(z.history, z.history[len(z.history)-keep:])
+		z.history = z.history[:n]
+	}
+	start := len(z.history)
+
+	switch (h >> 1) & 3 {
+	case 0: // Raw
+		z.history = append(z.history, make([]byte, size)...)
+		if _, err := io.ReadFull(z.r, z.history[start:]); err != nil {
+			return unexpectedEOF(err)
+		}
+	case 1: // RLE
+		b, err := z.r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		for i := 0; i < size; i++ {
+			z.history = append(z.history, b)
+		}
+	case 2: // Compressed
+		if cap(z.block) < size {
+			z.block = make([]byte, size)
+		}
+		z.block = z.block[:size]
+		if _, err := io.ReadFull(z.r, z.block); err != nil {
+			return unexpectedEOF(err)
+		}
+		literals, sequences, err := z.decodeLiterals(z.block)
+		if err != nil {
+			return err
+		}
+		if err := z.decodeSequences(sequences, literals); err != nil {
+			return err
+		}
+		if len(z.history)-start > zstdMaxBlockSize {
+			return errors.New("zstd块解压后的大小超出限制")
+		}
+	default:
+		return errors.New("zstd块类型无效")
+	}
+
+	z.out = z.history[start:]
+	z.size += uint64(len(z.out))
+	if z.declared >= 0 && z.size > uint64(z.declared) {
+		return fmt.Errorf("zstd帧的内容超过帧头声明的%d字节", z.declared)
+	}
+	if z.checksum {
+		z.hash.write(z.out)
+	}
+	return nil
+}
+
+// decodeLiterals 解码块中的字面量部分，返回字面量和剩余的序列部分
+func (z *zstdReader) decodeLiterals(block []byte) ([]byte, []byte, error) {
+	if len(block) == 0 {
+		return nil, nil, errors.New("zstd字面量头不完整")
+	}
+	literalsType := block[0] & 3
+	sizeFormat := (block[0] >> 2) & 3
+
+	if literalsType < 2 {
+		var regenerated, headerSize int
+		switch sizeFormat {
+		case 0, 2:
+			regenerated, headerSize = int(block[0]>>3), 1
+		case 1:
+			if len(block) < 2 {
+				return nil, nil, errors.New("zstd字面量头不完整")
+			}
+			regenerated, headerSize = int(block[0]>>4)|int(block[1])<<4, 2
+		case 3:
+			if len(block) < 3 {
+				return nil, nil, errors.New("zstd字面量头不完整")
+			}
+			regenerated, headerSize = int(block[0]>>4)|int(block[1])<<4|int(block[2])<<12, 3
+		}
+		if regenerated > zstdMaxBlockSize {
+			return nil, nil, errors.New("zstd字面量过长")
+		}
+
+		if literalsType == 0 {
+			end := headerSize + regenerated
+			if len(block) < end {
+				return nil, nil, errors.New("zstd字面量数据不完整")
+			}
+			return block[headerSize:end], block[end:], nil
+		}
+		if len(block) < headerSize+1 {
+			return nil, nil, errors.New("zstd字面量数据不完整")
+		}
+		z.literals = z.literals[:0]
+		for i := 0; i < regenerated; i++ {
+			z.literals = append(z.literals, block[headerSize])
+		}
+		return z.literals, block[headerSize+1:], nil
+	}
+
+	headerSize := [4]int{3, 3, 4, 5}[sizeFormat]
+	sizeBits := [4]uint{10, 10, 14, 18}[sizeFormat]
+	if len(block) < headerSize {
+		return nil, nil, errors.New("zstd字面量头不完整")
+	}
+	var v uint64
+	for i := 0; i < headerSize; i++ {
+		v |= uint64(block[i]) << (8 * i)
+	}
+	mask := uint64(1)<<sizeBits - 1
+	regenerated := int(v >> 4 & mask)
+	compressed := int(v >> (4 + sizeBits) & mask)
+	if regenerated > zstdMaxBlockSize {
+		return nil, nil, errors.New("zstd字面量过长")
+	}
+	end := headerSize + compressed
+	if len(block) < end {
+		return nil, nil, errors.New("zstd字面量数据不完整")
+	}
+	data := block[headerSize:end]
+
+	if literalsType == 2 {
+		table, n, err := readZstdHuffmanTable(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		z.huffman = table
+		data = data[n:]
+	} else if z.huffman == nil {
+		return nil, nil, errors.New("zstd数据缺少可重复使用的Huffman表")
+	}
+
+	streams := 4
+	if sizeFormat == 0 {
+		streams = 1
+	}
+	literals, err := decodeZstdHuffmanStreams(z.literals[:0], data, regenerated, streams, z.huffman)
+	if err != nil {
+		return nil, nil, err
+	}
+	z.literals = literals
+	return literals, block[end:], nil
+}
+
+// decodeSequences 解码序列部分并执行：依次复制字面量和历史中的匹配数据
+func (z *zstdReader) decodeSequences(data []byte, literals []byte) error {
+	if len(data) == 0 {
+		return errors.New("zstd序列头不完整")
+	}
+	count, p := int(data[0]), 1
+	if count == 0 {
+		z.history = append(z.history, literals...)
+		return nil
+	}
+	if count >= 128 {
+		if count < 255 {
+			if len(data) < 2 {
+				return errors.New("zstd序列头不完整")
+			}
+			count, p = (count-128)<<8+int(data[1]), 2
+		} else {
+			if len(data) < 3 {
+				return errors.New("zstd序列头不完整")
+			}
+			count, p = int(data[1])+int(data[2])<<8+0x7F00, 3
+		}
+	}
+	if len(data) < p+1 {
+		return errors.New("zstd序列头不完整")
+	}
+	modes := data[p]
+	p++
+	if modes&3 != 0 {
+		return errors.New("zstd序列压缩模式的保留位不为0")
+	}
+
+	var err error
+	var n int
+	if z.literalLengths, n, err = zstdSequenceTable(data[p:], modes>>6, z.literalLengths, zstdPredefinedLiteralLengths, 9, 35); err != nil {
+		return err
+	}
+	p += n
+	if z.offsets, n, err = zstdSequenceTable(data[p:], (modes>>4)&3, z.offsets, zstdPredefinedOffsets, 8, 31); err != nil {
+		return err
+	}
+	p += n
+	if z.matchLengths, n, err = zstdSequenceTable(data[p:], (modes>>2)&3, z.matchLengths, zstdPredefinedMatchLengths, 9, 52); err != nil {
+		return err
+	}
+	p += n
+
+	var br zstdBitReader
+	if err := br.init(data[p:]); err != nil {
+		return err
+	}
+	ll, of, ml := z.literalLengths, z.offsets, z.matchLengths
+	llState := int(br.read(ll.log))
+	ofState := int(br.read(of.log))
+	mlState := int(br.read(ml.log))
+
+	for i := 0; i < count; i++ {
+		ofCode := int(of.entries[ofState].symbol)
+		mlCode := int(ml.entries[mlState].symbol)
+		llCode := int(ll.entries[llState].symbol)
+
+		offsetValue := 1<<ofCode + int(br.read(ofCode))
+		matchLength := zstdMatchLengthBase[mlCode] + int(br.read(zstdMatchLengthBits[mlCode]))
+		literalLength := zstdLiteralLengthBase[llCode] + int(br.read(zstdLiteralLengthBits[llCode]))
+
+		if i < count-1 {
+			llState = ll.next(llState, &br)
+			mlState = ml.next(mlState, &br)
+			ofState = of.next(ofState, &br)
+		}
+		if br.pos < 0 {
+			return errors.New("zstd序列数据不完整")
+		}
+
+		offset, err := z.resolveOffset(offsetValue, literalLength)
+		if err != nil {
+			return err
+		}
+		if literalLength > len(literals) {
+			return errors.New("zstd序列的字面量长度超出范围")
+		}
+		z.history = append(z.history, literals[:literalLength]...)
+		literals = literals[literalLength:]
+
+		if offset > len(z.history) {
+			return errors.New("zstd匹配偏移超出历史数据范围")
+		}
+		start := len(z.history) - offset
+		if offset >= matchLength {
+			z.history = append(z.history, z.history[start:start+matchLength]...)
+		} else {
+			for j := 0; j < matchLength; j++ {
+				z.history = append(z.history, z.history[start+j])
+			}
+		}
+	}
+	if br.pos != 0 {
+		return errors.New("zstd序列数据长度不一致")
+	}
+
+	z.history = append(z.history, literals...)
+	return nil
+}
+
+// resolveOffset 将偏移值转换为实际偏移并更新重复偏移
+func (z *zstdReader) resolveOffset(offsetValue, literalLength int) (int, error) {
+	if offsetValue > 3 {
+		offset := offsetValue - 3
+		z.rep = [3]int{offset, z.rep[0], z.rep[1]}
+		return offset, nil
+	}
+
+	index := offsetValue
+	if literalLength == 0 {
+		index++
+	}
+	switch index {
+	case 1:
+		return z.rep[0], nil
+	case 2:
+		z.rep = [3]int{z.rep[1], z.rep[0], z.rep[2]}
+	case 3:
+		z.rep = [3]int{z.rep[2], z.rep[0], z.rep[1]}
+	default:
+		offset := z.rep[0] - 1
+		if offset == 0 {
+			return 0, errors.New("zstd重复偏移无效")
+		}
+		z.rep = [3]int{offset, z.rep[0], z.rep[1]}
+	}
+	return z.rep[0], nil
+}
+
+// zstdSequenceTable 按压缩模式获取序列解码表，返回消耗的字节数
+func zstdSequenceTable(data []byte, mode byte, previous, predefined *zstdFSETable, maxLog, maxSymbol int) (*zstdFSETable, int, error) {
+	switch mode {
+	case 0: // Predefined
+		return predefined, 0, nil
+	case 1: // RLE
+		if len(data) == 0 {
+			return nil, 0, errors.New("zstd序列表不完整")
+		}
+		if int(data[0]) > maxSymbol {
+			return nil, 0, errors.New("zstd序列表的符号超出范围")
+		}
+		return &zstdFSETable{entries: []zstdFSEEntry{{symbol: data[0]}}}, 1, nil
+	case 2: // FSE_Compressed
+		return readZstdFSETable(data, maxLog, maxSymbol)
+	default: // Repeat
+		if previous == nil {
+			return nil, 0, errors.New("zstd数据缺少可重复使用的序列表")
+		}
+		return previous, 0, nil
+	}
+}
+
+// zstdFSEEntry FSE解码表中的一个状态
+type zstdFSEEntry struct {
+	symbol byte
+	bits   int
+	base   int
+}
+
+// zstdFSETable FSE解码表
+type zstdFSETable struct {
+	log     int
+	entries []zstdFSEEntry
+}
+
+// next 读取附加位并转移到下一个状态
+func (t *zstdFSETable) next(state int, br *zstdBitReader) int {
+	e := t.entries[state]
+	return e.base + int(br.read(e.bits))
+}
+
+// mustZstdFSETable 由预定义分布构造解码表
+func mustZstdFSETable(distribution []int, log int) *zstdFSETable {
+	table, err := buildZstdFSETable(distribution, log)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// readZstdFSETable 解析FSE表描述并构造解码表，返回消耗的字节数
+func readZstdFSETable(data []byte, maxLog, maxSymbol int) (*zstdFSETable, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errors.New("zstd FSE表不完整")
+	}
+	log := int(data[0]&15) + 5
+	if log > maxLog {
+		return nil, 0, fmt.Errorf("zstd FSE表精度过高: %d", log)
+	}
+
+	pos := 4
+	remaining := 1<<log + 1
+	threshold := 1 << log
+	nbBits := log + 1
+	distribution := make([]int, 0, maxSymbol+1)
+	previousZero := false
+	for remaining > 1 && len(distribution) <= maxSymbol {
+		if previousZero {
+			zeros := 0
+			for {
+				repeat := int(zstdBits(data, pos, 2))
+				pos += 2
+				zeros += repeat
+				if repeat != 3 {
+					break
+				}
+			}
+			if len(distribution)+zeros > maxSymbol {
+				return nil, 0, errors.New("zstd FSE表的符号超出范围")
+			}
+			for ; zeros > 0; zeros-- {
+				distribution = append(distribution, 0)
+			}
+		}
+
+		limit := 2*threshold - 1 - remaining
+		v := int(zstdBits(data, pos, nbBits))
+		count := v & (threshold - 1)
+		if count < limit {
+			pos += nbBits - 1
+		} else {
+			count = v & (2*threshold - 1)
+			if count >= threshold {
+				count -= limit
+			}
+			pos += nbBits
+		}
+		count--
+
+		if count < 0 {
+			remaining += count
+		} else {
+			remaining -= count
+		}
+		distribution = append(distribution, count)
+		previousZero = count == 0
+		for remaining < threshold && threshold > 1 {
+			nbBits--
+			threshold >>= 1
+		}
+	}
+	if remaining != 1 || pos > 8*len(data) {
+		return nil, 0, errors.New("zstd FSE表描述无效")
+	}
+
+	table, err := buildZstdFSETable(distribution, log)
+	if err != nil {
+		return nil, 0, err
+	}
+	return table, (pos + 7) / 8, nil
+}
+
+// buildZstdFSETable 按归一化分布展开符号并计算每个状态的转移参数
+func buildZstdFSETable(distribution []int, log int) (*zstdFSETable, error) {
+	size := 1 << log
+	entries := make([]zstdFSEEntry, size)
+	next := make([]int, len(distribution))
+	high := size - 1
+	for s, count := range distribution {
+		if count == -1 {
+			entries[high].symbol = byte(s)
+			high--
+			next[s] = 1
+		} else {
+			next[s] = count
+		}
+	}
+
+	step := size>>1 + size>>3 + 3
+	mask := size - 1
+	pos := 0
+	for s, count := range distribution {
+		for i := 0; i < count; i++ {
+			entries[pos].symbol = byte(s)
+			pos = (pos + step) & mask
+			for pos > high {
+				pos = (pos + step) & mask
+			}
+		}
+	}
+	if pos != 0 {
+		return nil, errors.New("zstd FSE分布无效")
+	}
+
+	for u := range entries {
+		s := entries[u].symbol
+		state := next[s]
+		next[s]++
+		nb := log - (bits.Len(uint(state)) - 1)
+		entries[u].bits = nb
+		entries[u].base = state<<nb - size
+	}
+	return &zstdFSETable{log: log, entries: entries}, nil
+}
+
+// zstdHuffmanEntry Huffman解码表项
+type zstdHuffmanEntry struct {
+	symbol byte
+	bits   int
+}
+
+// zstdHuffmanTable 以最长码长为索引宽度的Huffman解码表
+type zstdHuffmanTable struct {
+	log     int
+	entries []zstdHuffmanEntry
+}
+
+// readZstdHuffmanTable 解析Huffman树描述，返回消耗的字节数
+func readZstdHuffmanTable(data []byte) (*zstdHuffmanTable, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errors.New("zstd Huffman表不完整")
+	}
+	header := int(data[0])
+	var weights []byte
+	var consumed int
+	if header < 128 {
+		if len(data) < 1+header {
+			return nil, 0, errors.New("zstd Huffman表不完整")
+		}
+		var err error
+		if weights, err = decodeZstdHuffmanWeights(data[1 : 1+header]); err != nil {
+			return nil, 0, err
+		}
+		consumed = 1 + header
+	} else {
+		n := header - 127
+		consumed = 1 + (n+1)/2
+		if len(data) < consumed {
+			return nil, 0, errors.New("zstd Huffman表不完整")
+		}
+		weights = make([]byte, n)
+		for i := range weights {
+			b := data[1+i/2]
+			if i%2 == 0 {
+				weights[i] = b >> 4
+			} else {
+				weights[i] = b & 15
+			}
+		}
+	}
+
+	// 最后一个符号的权重由其余权重推出，使总和恰好为2的幂
+	total := 0
+	for _, w := range weights {
+		if w > 11 {
+			return nil, 0, errors.New("zstd Huffman权重无效")
+		}
+		if w > 0 {
+			total += 1 << (w - 1)
+		}
+	}
+	if total == 0 {
+		return nil, 0, errors.New("zstd Huffman权重无效")
+	}
+	log := bits.Len(uint(total))
+	rest := 1<<log - total
+	if log > 11 || rest&(rest-1) != 0 {
+		return nil, 0, errors.New("zstd Huffman权重无效")
+	}
+	weights = append(weights, byte(bits.Len(uint(rest))))
+
+	var rankStart [13]int
+	for _, w := range weights {
+		if w > 0 {
+			rankStart[w] += 1 << (w - 1)
+		}
+	}
+	pos := 0
+	for w := 1; w <= log; w++ {
+		count := rankStart[w]
+		rankStart[w] = pos
+		pos += count
+	}
+
+	entries := make([]zstdHuffmanEntry, 1<<log)
+	for s, w := range weights {
+		if w == 0 {
+			continue
+		}
+		entry := zstdHuffmanEntry{symbol: byte(s), bits: log + 1 - int(w)}
+		length := 1 << (w - 1)
+		for i := rankStart[w]; i < rankStart[w]+length; i++ {
+			entries[i] = entry
+		}
+		rankStart[w] += length
+	}
+	return &zstdHuffmanTable{log: log, entries: entries}, consumed, nil
+}
+
+// decodeZstdHuffmanWeights 解码以FSE压缩的Huffman权重，两个状态交替解码直到比特流耗尽
+func decodeZstdHuffmanWeights(data []byte) ([]byte, error) {
+	table, n, err := readZstdFSETable(data, 6, 255)
+	if err != nil {
+		return nil, err
+	}
+	var br zstdBitReader
+	if err := br.init(data[n:]); err != nil {
+		return nil, err
+	}
+
+	state1 := int(br.read(table.log))
+	state2 := int(br.read(table.log))
+	weights := make([]byte, 0, 256)
+	for len(weights) < 255 {
+		weights = append(weights, table.entries[state1].symbol)
+		state1 = table.next(state1, &br)
+		if br.pos < 0 {
+			return append(weights, table.entries[state2].symbol), nil
+		}
+
+		weights = append(weights, table.entries[state2].symbol)
+		state2 = table.next(state2, &br)
+		if br.pos < 0 {
+			return append(weights, table.entries[state1].symbol), nil
+		}
+	}
+	return nil, errors.New("zstd Huffman权重过多")
+}
+
+// decodeZstdHuffmanStreams 解码1个或4个Huffman流，4个流时前6字节为前三个流的长度
+func decodeZstdHuffmanStreams(dst, data []byte, regenerated, streams int, table *zstdHuffmanTable) ([]byte, error) {
+	if streams == 1 {
+		return decodeZstdHuffmanStream(dst, data, regenerated, table)
+	}
+	if len(data) < 6 {
+		return nil, errors.New("zstd Huffman跳转表不完整")
+	}
+	sizes := [4]int{
+		int(binary.LittleEndian.Uint16(data[0:])),
+		int(binary.LittleEndian.Uint16(data[2:])),
+		int(binary.LittleEndian.Uint16(data[4:])),
+	}
+	data = data[6:]
+	sizes[3] = len(data) - sizes[0] - sizes[1] - sizes[2]
+	if sizes[3] < 0 {
+		return nil, errors.New("zstd Huffman跳转表无效")
+	}
+
+	perStream := (regenerated + 3) / 4
+	for i, size := range sizes {
+		count := perStream
+		if i == 3 {
+			count = regenerated - 3*perStream
+		}
+		if count < 0 {
+			return nil, errors.New("zstd Huffman流长度无效")
+		}
+		var err error
+		if dst, err = decodeZstdHuffmanStream(dst, data[:size], count, table); err != nil {
+			return nil, err
+		}
+		data = data[size:]
+	}
+	return dst, nil
+}
+
+// decodeZstdHuffmanStream 从反向比特流中解码count个符号
+func decodeZstdHuffmanStream(dst, data []byte, count int, table *zstdHuffmanTable) ([]byte, error) {
+	if count == 0 && len(data) == 0 {
+		return dst, nil
+	}
+	var br zstdBitReader
+	if err := br.init(data); err != nil {
+		return nil, err
+	}
+	for i := 0; i < count; i++ {
+		entry := table.entries[br.peek(table.log)]
+		br.pos -= entry.bits
+		dst = append(dst, entry.symbol)
+	}
+	if br.pos != 0 {
+		return nil, errors.New("zstd Huffman流长度不一致")
+	}
+	return dst, nil
+}
+
+// zstdBitReader 反向比特流：从末字节的结束标记位之后开始，由高位向低位读取
+type zstdBitReader struct {
+	data []byte
+	pos  int // 尚未读取的比特数，小于0表示读取越界
+}
+
+// init 定位末字节中的结束标记
+func (br *zstdBitReader) init(data []byte) error {
+	if len(data) == 0 || data[len(data)-1] == 0 {
+		return errors.New("zstd比特流无效")
+	}
+	br.data = data
+	br.pos = 8*(len(data)-1) + bits.Len8(data[len(data)-1]) - 1
+	return nil
+}
+
+// peek 返回接下来的n位而不消耗，越过流起点的部分补0
+func (br *zstdBitReader) peek(n int) uint64 {
+	start := br.pos - n
+	if start >= 0 {
+		return zstdBits(br.data, start, n)
+	}
+	if br.pos <= 0 {
+		return 0
+	}
+	return zstdBits(br.data, 0, br.pos) << uint(-start)
+}
+
+// read 读取并消耗n位
+func (br *zstdBitReader) read(n int) uint64 {
+	v := br.peek(n)
+	br.pos -= n
+	return v
+}
+
+// zstdBits 返回从第start位开始的n位（小端序，n不超过56），超出数据末尾的部分补0
+func zstdBits(data []byte, start, n int) uint64 {
+	i := start >> 3
+	var v uint64
+	if i+8 <= len(data) {
+		v = binary.LittleEndian.Uint64(data[i:])
+	} else {
+		for j := 0; i+j < len(data); j++ {
+			v |= uint64(data[i+j]) << (8 * j)
+		}
+	}
+	return v >> (start & 7) & (1<<n - 1)
+}
+
+// unexpectedEOF 帧内的io.EOF表示数据被截断
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// XXH64的常数
+const (
+	xxh64Prime1 uint64 = 11400714785074694791
+	xxh64Prime2 uint64 = 14029467366897019727
+	xxh64Prime3 uint64 = 1609587929392839161
+	xxh64Prime4 uint64 = 9650029242287828579
+	xxh64Prime5 uint64 = 2870177450012600261
+)
+
+// zstdXXH64 种子为0的流式XXH64哈希，用于校验zstd帧的内容
+type zstdXXH64 struct {
+	v     [4]uint64
+	total uint64
+	buf   [32]byte
+	n     int // buf中待处理的字节数
+}
+
+func (h *zstdXXH64) reset() {
+	p1, p2 := xxh64Prime1, xxh64Prime2 // 变量运算按模2^64回绕
+	*h = zstdXXH64{v: [4]uint64{p1 + p2, p2, 0, -p1}}
+}
+
+func (h *zstdXXH64) write(p []byte) {
+	h.total += uint64(len(p))
+	if h.n > 0 {
+		k := copy(h.buf[h.n:], p)
+		h.n += k
+		p = p[k:]
+		if h.n < 32 {
+			return
+		}
+		h.stripe(h.buf[:])
+		h.n = 0
+	}
+	for ; len(p) >= 32; p = p[32:] {
+		h.stripe(p)
+	}
+	h.n = copy(h.buf[:], p)
+}
+
+// stripe 处理32字节的数据块
+func (h *zstdXXH64) stripe(p []byte) {
+	for i := range h.v {
+		h.v[i] = xxh64Round(h.v[i], binary.LittleEndian.Uint64(p[8*i:]))
+	}
+}
+
+func (h *zstdXXH64) sum() uint64 {
+	var acc uint64
+	if h.total >= 32 {
+		acc = bits.RotateLeft64(h.v[0], 1) + bits.RotateLeft64(h.v[1], 7) +
+			bits.RotateLeft64(h.v[2], 12) + bits.RotateLeft64(h.v[3], 18)
+		for _, v := range h.v {
+			acc = (acc^xxh64Round(0, v))*xxh64Prime1 + xxh64Prime4
+		}
+	} else {
+		acc = xxh64Prime5
+	}
+	acc += h.total
+
+	p := h.buf[:h.n]
+	for ; len(p) >= 8; p = p[8:] {
+		acc ^= xxh64Round(0, binary.LittleEndian.Uint64(p))
+		acc = bits.RotateLeft64(acc, 27)*xxh64Prime1 + xxh64Prime4
+	}
+	if len(p) >= 4 {
+		acc ^= uint64(binary.LittleEndian.Uint32(p)) * xxh64Prime1
+		acc = bits.RotateLeft64(acc, 23)*xxh64Prime2 + xxh64Prime3
+		p = p[4:]
+	}
+	for _, b := range p {
+		acc ^= uint64(b) * xxh64Prime5
+		acc = bits.RotateLeft64(acc, 11) * xxh64Prime1
+	}
+
+	acc ^= acc >> 33
+	acc *= xxh64Prime2
+	acc ^= acc >> 29
+	acc *= xxh64Prime3
+	acc ^= acc >> 32
+	return acc
+}
+
+func xxh64Round(acc, input uint64) uint64 {
+	acc += input * xxh64Prime2
+	return bits.RotateLeft64(acc, 31) * xxh64Prime1
+}
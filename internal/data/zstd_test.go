@@ -0,0 +1,252 @@
+package data
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// zstd测试数据由zstd命令行工具（v1.5.6）生成，生成方法见testdata/zstd/README
+
+// multiblockCSV 返回multiblock*.csv.zst的原始内容（约135KB，超过一个块的最大大小）
+func multiblockCSV() []byte {
+	var b strings.Builder
+	for i := 0; i < 9000; i++ {
+		fmt.Fprintf(&b, "%d,%d,%d,row%d\n", i, i%100, i%7, i%13)
+	}
+	return []byte(b.String())
+}
+
+// rawBytes 返回raw.zst的原始内容：线性同余生成器产生的4096个不可压缩字节
+func rawBytes() []byte {
+	data := make([]byte, 4096)
+	x := uint32(12345)
+	for i := range data {
+		x = (x*1103515245 + 12345) % (1 << 31)
+		data[i] = byte(x >> 16)
+	}
+	return data
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "zstd", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func decompressZstd(data []byte) ([]byte, error) {
+	return io.ReadAll(newZstdReader(bytes.NewReader(data)))
+}
+
+// zstdBlockTypes 解析第一个帧的帧头和块头，返回各块的类型（0为Raw，1为RLE，2为Compressed）
+func zstdBlockTypes(t *testing.T, frame []byte) []int {
+	t.Helper()
+	if binary.LittleEndian.Uint32(frame) != zstdMagic {
+		t.Fatal("fixture does not start with a zstd frame")
+	}
+	descriptor := frame[4]
+	pos := 5
+	singleSegment := descriptor&0x20 != 0
+	if !singleSegment {
+		pos++
+	}
+	pos += [4]int{0, 1, 2, 4}[descriptor&3]
+	fcsSize := [4]int{0, 2, 4, 8}[descriptor>>6]
+	if fcsSize == 0 && singleSegment {
+		fcsSize = 1
+	}
+	pos += fcsSize
+
+	var types []int
+	for {
+		h := int(frame[pos]) | int(frame[pos+1])<<8 | int(frame[pos+2])<<16
+		blockType, size := (h>>1)&3, h>>3
+		types = append(types, blockType)
+		pos += 3
+		if blockType == 1 {
+			pos++
+		} else {
+			pos += size
+		}
+		if h&1 != 0 {
+			return types
+		}
+	}
+}
+
+func countBlocks(types []int, blockType int) int {
+	n := 0
+	for _, tp := range types {
+		if tp == blockType {
+			n++
+		}
+	}
+	return n
+}
+
+func TestZstdFixtures(t *testing.T) {
+	small := readFixture(t, "small.txt")
+	tests := []struct {
+		name       string
+		fixture    string
+		want       []byte
+		blockType  int // 帧中至少出现一次的块类型
+		multiBlock bool
+	}{
+		{"compressed with checksum", "small.txt.zst", small, 2, false},
+		{"compressed without checksum", "small_nocheck.zst", small, 2, false},
+		{"multi-block level 1", "multiblock1.csv.zst", multiblockCSV(), 2, true},
+		{"multi-block level 19", "multiblock19.csv.zst", multiblockCSV(), 2, true},
+		{"rle blocks", "rle.zst", bytes.Repeat([]byte("a"), 300000), 1, true},
+		{"raw block", "raw.zst", rawBytes(), 0, false},
+		{"empty", "empty.zst", []byte{}, -1, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			compressed := readFixture(t, tc.fixture)
+			types := zstdBlockTypes(t, compressed)
+			if tc.blockType >= 0 && countBlocks(types, tc.blockType) == 0 {
+				t.Fatalf("fixture block types %v do not include type %d", types, tc.blockType)
+			}
+			if tc.multiBlock && len(types) < 2 {
+				t.Fatalf("fixture has only %d block", len(types))
+			}
+
+			got, err := decompressZstd(compressed)
+			if err != nil {
+				t.Fatalf("decompress: %v", err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("decompressed %d bytes, want %d bytes of the original", len(got), len(tc.want))
+			}
+		})
+	}
+}
+
+func TestZstdSmallReads(t *testing.T) {
+	// 每次只读一个字节，数据需跨多次Read正确返回
+	r := newZstdReader(bytes.NewReader(readFixture(t, "multiblock19.csv.zst")))
+	var got bytes.Buffer
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		got.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+	}
+	if !bytes.Equal(got.Bytes(), multiblockCSV()) {
+		t.Fatal("byte-by-byte reads returned different data")
+	}
+}
+
+func TestZstdConcatenatedAndSkippableFrames(t *testing.T) {
+	skippable := make([]byte, 8, 13)
+	binary.LittleEndian.PutUint32(skippable, zstdSkippableMagic+3)
+	binary.LittleEndian.PutUint32(skippable[4:], 5)
+	skippable = append(skippable, "hello"...)
+
+	var input []byte
+	input = append(input, readFixture(t, "small.txt.zst")...)
+	input = append(input, skippable...)
+	input = append(input, readFixture(t, "empty.zst")...)
+	input = append(input, readFixture(t, "raw.zst")...)
+
+	got, err := decompressZstd(input)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	want := append(readFixture(t, "small.txt"), rawBytes()...)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("decompressed %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestZstdTruncated(t *testing.T) {
+	for _, fixture := range []string{"small.txt.zst", "small_nocheck.zst", "rle.zst", "raw.zst", "empty.zst", "multiblock19.csv.zst"} {
+		t.Run(fixture, func(t *testing.T) {
+			data := readFixture(t, fixture)
+			step := 1
+			if len(data) > 2000 {
+				step = 97 // 大文件抽样截断位置
+			}
+			// 截断为0字节时是空输入，不是错误
+			for n := 1; n < len(data); n += step {
+				if _, err := decompressZstd(data[:n]); err == nil {
+					t.Fatalf("truncating to %d of %d bytes should fail", n, len(data))
+				}
+			}
+		})
+	}
+}
+
+func TestZstdCorrupt(t *testing.T) {
+	small := readFixture(t, "small.txt.zst")
+	mutate := func(data []byte, f func(d []byte)) []byte {
+		d := append([]byte(nil), data...)
+		f(d)
+		return d
+	}
+	// 帧头：魔数4字节、帧头描述符1字节，small.txt.zst为单段帧
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"bad magic", mutate(small, func(d []byte) { d[0] ^= 0xff }), "不是有效的zstd数据"},
+		{"reserved bit", mutate(small, func(d []byte) { d[4] |= 0x08 }), "保留位"},
+		{"dictionary", mutate(small, func(d []byte) { d[4] |= 0x01 }), ""},
+		{"checksum", mutate(small, func(d []byte) { d[len(d)-1] ^= 0x01 }), "校验和不匹配"},
+		{"reserved block type", mutate(readFixture(t, "raw.zst"), func(d []byte) {
+			// raw.zst为单段帧，帧头为魔数、描述符和2字节的内容大小，块头从第7字节开始
+			d[7] |= 0x06
+		}), "块类型无效"},
+		{"trailing garbage", append(append([]byte(nil), small...), 1, 2, 3, 4, 5), "不是有效的zstd数据"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decompressZstd(tc.data)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestZstdBitFlips(t *testing.T) {
+	// 翻转压缩数据中的任意一位，解压可以失败，但不能panic，也不能返回与原文不同的数据
+	for _, fixture := range []string{"small.txt.zst", "multiblock19.csv.zst"} {
+		t.Run(fixture, func(t *testing.T) {
+			data := readFixture(t, fixture)
+			want, err := decompressZstd(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			step := 1
+			if len(data) > 2000 {
+				step = 151 // 大文件抽样翻转位置
+			}
+			for i := 0; i < len(data); i += step {
+				for bit := 0; bit < 8; bit++ {
+					corrupted := append([]byte(nil), data...)
+					corrupted[i] ^= 1 << bit
+					got, err := decompressZstd(corrupted)
+					if err == nil && !bytes.Equal(got, want) {
+						t.Fatalf("flipping bit %d of byte %d returned corrupted data without an error", bit, i)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,540 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 多类别指标的平均方式
+const (
+	AverageBinary   = "binary"   // 只计算正类（标签1）的指标，要求标签为0或1
+	AverageMacro    = "macro"    // 各类别指标的算术平均，每个类别同等重要
+	AverageMicro    = "micro"    // 汇总所有类别的TP/FP/FN/TN后计算
+	AverageWeighted = "weighted" // 按各类别真实样本数加权平均
+)
+
+// 混淆矩阵的归一化方式
+const (
+	NormalizeTrue = "true" // 每行除以该真实类别的样本数，对角线为各类别的召回率
+	NormalizePred = "pred" // 每列除以预测为该类别的样本数，对角线为各类别的精确率
+	NormalizeAll  = "all"  // 除以总样本数
+)
+
+// ConfusionMatrix 混淆矩阵，Counts[i][j]为真实类别Labels[i]被预测为Labels[j]的样本数
+type ConfusionMatrix struct {
+	Labels []float64
+	Counts [][]int
+}
+
+// ClassReport 单个类别一对其余时的指标
+type ClassReport struct {
+	Label       float64
+	Precision   float64
+	Recall      float64
+	F1          float64
+	Specificity float64
+	Support     int // 该类别的真实样本数
+}
+
+// NewConfusionMatrix 由真实标签和预测标签构建混淆矩阵，类别为两者中出现过的所有标签，按升序排列
+func NewConfusionMatrix(yTrue, yPred []float64) (*ConfusionMatrix, error) {
+	if len(yTrue) != len(yPred) {
+		return nil, errors.New("预测值和真实值长度不匹配")
+	}
+	if len(yTrue) == 0 {
+		return nil, errors.New("样本数不能为0")
+	}
+
+	index := make(map[float64]int)
+	for i := range yTrue {
+		for _, label := range []float64{yTrue[i], yPred[i]} {
+			if math.IsNaN(label) {
+				return nil, fmt.Errorf("第 %d 个样本的标签为NaN", i)
+			}
+			index[label] = 0
+		}
+	}
+	labels := make([]float64, 0, len(index))
+	for label := range index {
+		labels = append(labels, label)
+	}
+	sort.Float64s(labels)
+	for k, label := range labels {
+		index[label] = k
+	}
+
+	counts := make([][]int, len(labels))
+	for k := range counts {
+		counts[k] = make([]int, len(labels))
+	}
+	for i := range yTrue {
+		counts[index[yTrue[i]]][index[yPred[i]]]++
+	}
+	return &ConfusionMatrix{Labels: labels, Counts: counts}, nil
+}
+
+// classCounts 返回第k个类别一对其余时的TP、FP、FN和TN
+func (m *ConfusionMatrix) classCounts(k int) (tp, fp, fn, tn int) {
+	for i, row := range m.Counts {
+		for j, count := range row {
+			switch {
+			case i == k && j == k:
+				tp += count
+			case j == k:
+				fp += count
+			case i == k:
+				fn += count
+			default:
+				tn += count
+			}
+		}
+	}
+	return tp, fp, fn, tn
+}
+
+// support 返回第k个类别的真实样本数
+func (m *ConfusionMatrix) support(k int) int {
+	total := 0
+	for _, count := range m.Counts[k] {
+		total += count
+	}
+	return total
+}
+
+// Normalized 返回按mode归一化的混淆矩阵，分母为0的行或列保持为0
+func (m *ConfusionMatrix) Normalized(mode string) ([][]float64, error) {
+	n := len(m.Labels)
+	rowTotals := make([]int, n)
+	colTotals := make([]int, n)
+	total := 0
+	for i, row := range m.Counts {
+		for j, count := range row {
+			rowTotals[i] += count
+			colTotals[j] += count
+			total += count
+		}
+	}
+
+	normalized := make([][]float64, n)
+	for i, row := range m.Counts {
+		normalized[i] = make([]float64, n)
+		for j, count := range row {
+			switch mode {
+			case NormalizeTrue:
+				normalized[i][j] = ratio(count, rowTotals[i])
+			case NormalizePred:
+				normalized[i][j] = ratio(count, colTotals[j])
+			case NormalizeAll:
+				normalized[i][j] = ratio(count, total)
+			default:
+				return nil, fmt.Errorf("不支持的归一化方式: %s", mode)
+			}
+		}
+	}
+	return normalized, nil
+}
+
+// PerClass 返回每个类别一对其余时的精确率、召回率、F1、特异度和样本数，顺序与Labels一致
+func (m *ConfusionMatrix) PerClass() []ClassReport {
+	reports := make([]ClassReport, len(m.Labels))
+	for k, label := range m.Labels {
+		tp, fp, fn, tn := m.classCounts(k)
+		reports[k] = ClassReport{
+			Label:       label,
+			Precision:   ratio(tp, tp+fp),
+			Recall:      ratio(tp, tp+fn),
+			F1:          ratio(2*tp, 2*tp+fp+fn),
+			Specificity: ratio(tn, tn+fp),
+			Support:     m.support(k),
+		}
+	}
+	return reports
+}
+
+// String 以表格形式输出混淆矩阵，行为真实类别，列为预测类别，并附上每个类别的指标
+func (m *ConfusionMatrix) String() string {
+	labels := make([]string, len(m.Labels))
+	width := len("true\\pred")
+	for k, label := range m.Labels {
+		labels[k] = strconv.FormatFloat(label, 'g', -1, 64)
+		width = max(width, len(labels[k]))
+	}
+	for _, row := range m.Counts {
+		for _, count := range row {
+			width = max(width, len(strconv.Itoa(count)))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%*s", width, "true\\pred")
+	for _, label := range labels {
+		fmt.Fprintf(&b, "  %*s", width, label)
+	}
+	b.WriteString("\n")
+	for i, row := range m.Counts {
+		fmt.Fprintf(&b, "%*s", width, labels[i])
+		for _, count := range row {
+			fmt.Fprintf(&b, "  %*d", width, count)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%*s  %9s  %9s  %9s  %11s  %7s\n", width, "class", "precision", "recall", "f1", "specificity", "support")
+	for k, report := range m.PerClass() {
+		fmt.Fprintf(&b, "%*s  %9.4f  %9.4f  %9.4f  %11.4f  %7d\n",
+			width, labels[k], report.Precision, report.Recall, report.F1, report.Specificity, report.Support)
+	}
+	fmt.Fprintf(&b, "%*s  %9.4f\n", width, "accuracy", m.Accuracy())
+	fmt.Fprintf(&b, "%*s  %9.4f\n", width, "mcc", m.MCC())
+	fmt.Fprintf(&b, "%*s  %9.4f\n", width, "kappa", m.CohenKappa())
+	return b.String()
+}
+
+// Accuracy 返回预测正确的样本比例
+func (m *ConfusionMatrix) Accuracy() float64 {
+	correct, total := 0, 0
+	for i, row := range m.Counts {
+		for j, count := range row {
+			if i == j {
+				correct += count
+			}
+			total += count
+		}
+	}
+	return ratio(correct, total)
+}
+
+// marginals 返回各类别的真实样本数、预测样本数、预测正确的样本数和总样本数
+func (m *ConfusionMatrix) marginals() (trueTotals, predTotals []float64, correct, total float64) {
+	trueTotals = make([]float64, len(m.Labels))
+	predTotals = make([]float64, len(m.Labels))
+	for i, row := range m.Counts {
+		for j, count := range row {
+			trueTotals[i] += float64(count)
+			predTotals[j] += float64(count)
+			total += float64(count)
+			if i == j {
+				correct += float64(count)
+			}
+		}
+	}
+	return trueTotals, predTotals, correct, total
+}
+
+// MCC 返回马修斯相关系数，取值在[-1,1]之间，1为完全正确、0相当于随机猜测；
+// 同时考虑四个格子的计数，类别极不平衡时也不会因全部预测为多数类而偏高。
+// 多分类时使用Gorodkin的推广形式；真实值或预测值只有一个类别时返回0
+func (m *ConfusionMatrix) MCC() float64 {
+	trueTotals, predTotals, correct, total := m.marginals()
+	var agreement, sumPred, sumTrue float64
+	for k := range m.Labels {
+		agreement += trueTotals[k] * predTotals[k]
+		sumPred += predTotals[k] * predTotals[k]
+		sumTrue += trueTotals[k] * trueTotals[k]
+	}
+	denominator := math.Sqrt((total*total - sumPred) * (total*total - sumTrue))
+	if denominator == 0 {
+		return 0
+	}
+	return (correct*total - agreement) / denominator
+}
+
+// CohenKappa 返回Cohen's kappa系数 (p_o-p_e)/(1-p_e)，即扣除按各类别比例随机预测的期望一致率后的准确率，
+// 1为完全一致、0相当于随机猜测；期望一致率为1（真实值和预测值都只有同一个类别）时返回0
+func (m *ConfusionMatrix) CohenKappa() float64 {
+	trueTotals, predTotals, correct, total := m.marginals()
+	var expected float64
+	for k := range m.Labels {
+		expected += trueTotals[k] * predTotals[k]
+	}
+	expected /= total * total
+	if expected == 1 {
+		return 0
+	}
+	return (correct/total - expected) / (1 - expected)
+}
+
+// Precision 按average方式计算精确率 TP/(TP+FP)
+func (m *ConfusionMatrix) Precision(average string) (float64, error) {
+	return m.average(average, func(tp, fp, fn, tn int) (int, int) { return tp, tp + fp })
+}
+
+// Recall 按average方式计算召回率 TP/(TP+FN)
+func (m *ConfusionMatrix) Recall(average string) (float64, error) {
+	return m.average(average, func(tp, fp, fn, tn int) (int, int) { return tp, tp + fn })
+}
+
+// Specificity 按average方式计算特异度 TN/(TN+FP)，即负类的召回率
+func (m *ConfusionMatrix) Specificity(average string) (float64, error) {
+	return m.average(average, func(tp, fp, fn, tn int) (int, int) { return tn, tn + fp })
+}
+
+// F1 按average方式计算F1值 2TP/(2TP+FP+FN)，即精确率和召回率的调和平均
+func (m *ConfusionMatrix) F1(average string) (float64, error) {
+	return m.average(average, func(tp, fp, fn, tn int) (int, int) { return 2 * tp, 2*tp + fp + fn })
+}
+
+// BalancedAccuracy 返回各类别召回率的算术平均，不受类别比例影响；
+// 只出现在预测值中的类别没有真实样本，不参与平均
+func (m *ConfusionMatrix) BalancedAccuracy() float64 {
+	var sum float64
+	classes := 0
+	for k := range m.Labels {
+		if m.support(k) == 0 {
+			continue
+		}
+		tp, _, fn, _ := m.classCounts(k)
+		sum += ratio(tp, tp+fn)
+		classes++
+	}
+	if classes == 0 {
+		return 0
+	}
+	return sum / float64(classes)
+}
+
+// average 按average方式汇总各类别的指标，metric返回一对其余时指标的分子和分母；分母为0时指标记为0
+func (m *ConfusionMatrix) average(average string, metric func(tp, fp, fn, tn int) (int, int)) (float64, error) {
+	switch average {
+	case AverageBinary:
+		for _, label := range m.Labels {
+			if label != 0 && label != 1 {
+				return 0, fmt.Errorf("binary平均要求标签为0或1，发现标签 %v", label)
+			}
+		}
+		for k, label := range m.Labels {
+			if label == 1 {
+				return ratio(metric(m.classCounts(k))), nil
+			}
+		}
+		// 没有正类样本也没有正类预测时，正类的TP、FP、FN均为0
+		total := 0
+		for k := range m.Labels {
+			total += m.support(k)
+		}
+		return ratio(metric(0, 0, 0, total)), nil
+	case AverageMacro:
+		var sum float64
+		for k := range m.Labels {
+			sum += ratio(metric(m.classCounts(k)))
+		}
+		return sum / float64(len(m.Labels)), nil
+	case AverageMicro:
+		var numerator, denominator int
+		for k := range m.Labels {
+			num, den := metric(m.classCounts(k))
+			numerator += num
+			denominator += den
+		}
+		return ratio(numerator, denominator), nil
+	case AverageWeighted:
+		var sum float64
+		total := 0
+		for k := range m.Labels {
+			support := m.support(k)
+			sum += float64(support) * ratio(metric(m.classCounts(k)))
+			total += support
+		}
+		return sum / float64(total), nil
+	default:
+		return 0, fmt.Errorf("不支持的平均方式: %s", average)
+	}
+}
+
+// ratio 返回numerator/denominator，分母为0时返回0
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+// logLossEpsilon 计算对数损失时概率裁剪的边界，避免log(0)
+const logLossEpsilon = 1e-15
+
+// LogLoss 计算二分类的对数损失（交叉熵）：-Σw[y·log(p)+(1-y)·log(1-p)]/Σw。
+// probabilities为正类概率，裁剪到[1e-15, 1-1e-15]；weights为每个样本的权重，为nil时等权
+func LogLoss(yTrue, probabilities, weights []float64) (float64, error) {
+	if len(yTrue) != len(probabilities) {
+		return 0, errors.New("预测值和真实值长度不匹配")
+	}
+	if weights != nil && len(weights) != len(yTrue) {
+		return 0, errors.New("样本权重和真实值长度不匹配")
+	}
+	if len(yTrue) == 0 {
+		return 0, errors.New("样本数不能为0")
+	}
+
+	var loss, total float64
+	for i, label := range yTrue {
+		if label != 0 && label != 1 {
+			return 0, fmt.Errorf("第 %d 个样本的标签 %v 不是0或1", i, label)
+		}
+		if math.IsNaN(probabilities[i]) {
+			return 0, fmt.Errorf("第 %d 个样本的预测概率为NaN", i)
+		}
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		p := math.Min(math.Max(probabilities[i], logLossEpsilon), 1-logLossEpsilon)
+		if label == 1 {
+			loss -= w * math.Log(p)
+		} else {
+			loss -= w * math.Log(1-p)
+		}
+		total += w
+	}
+	if total <= 0 {
+		return 0, errors.New("样本权重之和必须为正数")
+	}
+	return loss / total, nil
+}
+
+// BrierScore 计算二分类的Brier分数：Σw(p-y)²/Σw，即正类概率与标签的加权均方误差，取值[0, 1]，越小越好。
+// 与对数损失不同，它对置信但错误的预测惩罚有界；weights为每个样本的权重，为nil时等权
+func BrierScore(yTrue, probabilities, weights []float64) (float64, error) {
+	if len(yTrue) != len(probabilities) {
+		return 0, errors.New("预测值和真实值长度不匹配")
+	}
+	if weights != nil && len(weights) != len(yTrue) {
+		return 0, errors.New("样本权重和真实值长度不匹配")
+	}
+	if len(yTrue) == 0 {
+		return 0, errors.New("样本数不能为0")
+	}
+
+	var sum, total float64
+	for i, label := range yTrue {
+		if label != 0 && label != 1 {
+			return 0, fmt.Errorf("第 %d 个样本的标签 %v 不是0或1", i, label)
+		}
+		p := probabilities[i]
+		if math.IsNaN(p) || p < 0 || p > 1 {
+			return 0, fmt.Errorf("第 %d 个样本的预测概率 %v 不在[0, 1]内", i, p)
+		}
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		sum += w * (p - label) * (p - label)
+		total += w
+	}
+	if total <= 0 {
+		return 0, errors.New("样本权重之和必须为正数")
+	}
+	return sum / total, nil
+}
+
+// Precision 计算精确率，average为Average*常量之一
+func Precision(yTrue, yPred []float64, average string) (float64, error) {
+	m, err := NewConfusionMatrix(yTrue, yPred)
+	if err != nil {
+		return 0, err
+	}
+	return m.Precision(average)
+}
+
+// Recall 计算召回率，average为Average*常量之一
+func Recall(yTrue, yPred []float64, average string) (float64, error) {
+	m, err := NewConfusionMatrix(yTrue, yPred)
+	if err != nil {
+		return 0, err
+	}
+	return m.Recall(average)
+}
+
+// F1Score 计算F1值，average为Average*常量之一
+func F1Score(yTrue, yPred []float64, average string) (float64, error) {
+	m, err := NewConfusionMatrix(yTrue, yPred)
+	if err != nil {
+		return 0, err
+	}
+	return m.F1(average)
+}
+
+// Specificity 计算特异度，average为Average*常量之一
+func Specificity(yTrue, yPred []float64, average string) (float64, error) {
+	m, err := NewConfusionMatrix(yTrue, yPred)
+	if err != nil {
+		return 0, err
+	}
+	return m.Specificity(average)
+}
+
+// BalancedAccuracy 计算平衡准确率，即各类别召回率的平均
+func BalancedAccuracy(yTrue, yPred []float64) (float64, error) {
+	m, err := NewConfusionMatrix(yTrue, yPred)
+	if err != nil {
+		return 0, err
+	}
+	return m.BalancedAccuracy(), nil
+}
+
+// MatthewsCorrCoef 计算马修斯相关系数
+func MatthewsCorrCoef(yTrue, yPred []float64) (float64, error) {
+	m, err := NewConfusionMatrix(yTrue, yPred)
+	if err != nil {
+		return 0, err
+	}
+	return m.MCC(), nil
+}
+
+// CohenKappa 计算Cohen's kappa系数
+func CohenKappa(yTrue, yPred []float64) (float64, error) {
+	m, err := NewConfusionMatrix(yTrue, yPred)
+	if err != nil {
+		return 0, err
+	}
+	return m.CohenKappa(), nil
+}
+
+// EvaluateClassifier 计算所有分类指标并返回结果映射：accuracy、balanced_accuracy、mcc、cohen_kappa，
+// 以及precision、recall、f1、specificity的macro、micro和weighted平均（如"f1_macro"）；
+// 标签只有0和1时还包含正类的二分类指标（如"f1"）
+func EvaluateClassifier(yTrue, yPred []float64) (map[string]float64, error) {
+	m, err := NewConfusionMatrix(yTrue, yPred)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := map[string]float64{
+		"accuracy":          m.Accuracy(),
+		"balanced_accuracy": m.BalancedAccuracy(),
+		"mcc":               m.MCC(),
+		"cohen_kappa":       m.CohenKappa(),
+	}
+	averages := []string{AverageMacro, AverageMicro, AverageWeighted}
+	binary := true
+	for _, label := range m.Labels {
+		binary = binary && (label == 0 || label == 1)
+	}
+	if binary {
+		averages = append(averages, AverageBinary)
+	}
+
+	for _, average := range averages {
+		suffix := "_" + average
+		if average == AverageBinary {
+			suffix = ""
+		}
+		for name, metric := range map[string]func(string) (float64, error){
+			"precision":   m.Precision,
+			"recall":      m.Recall,
+			"f1":          m.F1,
+			"specificity": m.Specificity,
+		} {
+			value, err := metric(average)
+			if err != nil {
+				return nil, err
+			}
+			metrics[name+suffix] = value
+		}
+	}
+	return metrics, nil
+}
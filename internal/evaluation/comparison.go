@@ -0,0 +1,181 @@
+package evaluation
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// WilcoxonExactMaxSamples Wilcoxon符号秩检验使用精确分布的最大有效配对数，超过时使用正态近似
+const WilcoxonExactMaxSamples = 25
+
+// PairedTestResult 配对检验结果，原假设为两组得分的差值以0为中心
+type PairedTestResult struct {
+	Statistic      float64 `json:"statistic"`       // t统计量，或Wilcoxon检验中正差值的秩和W+
+	PValue         float64 `json:"p_value"`         // 双侧p值
+	MeanDifference float64 `json:"mean_difference"` // a-b的均值
+	N              int     `json:"n"`               // 参与检验的配对数，Wilcoxon检验不含差值为0的配对
+}
+
+// PairedTTest 对配对样本a、b执行双侧配对t检验，自由度为n-1
+func PairedTTest(a, b []float64) (*PairedTestResult, error) {
+	return CorrectedPairedTTest(a, b, 0)
+}
+
+// CorrectedPairedTTest 执行Nadeau-Bengio修正的配对t检验：交叉验证各折的训练集相互重叠，得分并不独立，
+// 普通配对t检验会低估方差而过于乐观。修正后差值的方差按 (1/n + testTrainRatio) 缩放，
+// testTrainRatio为验证集与训练集样本数之比（K折时约为1/(K-1)），为0时即普通配对t检验
+func CorrectedPairedTTest(a, b []float64, testTrainRatio float64) (*PairedTestResult, error) {
+	d, err := differences(a, b)
+	if err != nil {
+		return nil, err
+	}
+	n := len(d)
+	if n < 2 {
+		return nil, errors.New("配对t检验至少需要2对样本")
+	}
+	if testTrainRatio < 0 {
+		return nil, errors.New("验证集与训练集的比例不能为负")
+	}
+
+	var mean float64
+	for _, v := range d {
+		mean += v
+	}
+	mean /= float64(n)
+	var ss float64
+	for _, v := range d {
+		ss += (v - mean) * (v - mean)
+	}
+	variance := ss / float64(n-1)
+
+	result := &PairedTestResult{MeanDifference: mean, N: n, PValue: 1}
+	se := math.Sqrt(variance * (1/float64(n) + testTrainRatio))
+	if se == 0 {
+		// 差值完全相同：均值为0时没有差异，否则差异是确定的
+		if mean != 0 {
+			result.Statistic = math.Copysign(math.Inf(1), mean)
+			result.PValue = 0
+		}
+		return result, nil
+	}
+	result.Statistic = mean / se
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(n - 1)}
+	result.PValue = math.Min(2*t.Survival(math.Abs(result.Statistic)), 1)
+	return result, nil
+}
+
+// WilcoxonSignedRank 对配对样本a、b执行双侧Wilcoxon符号秩检验，不要求差值服从正态分布。
+// 差值为0的配对被剔除，绝对值相同的差值取平均秩；有效配对数不超过WilcoxonExactMaxSamples时
+// 按符号随机翻转的精确分布计算p值，否则使用带连续性校正和结校正的正态近似。
+// 配对数很少时p值有下限（如5对时为2/2⁵=0.0625），可能无法在常用的显著性水平下拒绝原假设
+func WilcoxonSignedRank(a, b []float64) (*PairedTestResult, error) {
+	d, err := differences(a, b)
+	if err != nil {
+		return nil, err
+	}
+	var mean float64
+	for _, v := range d {
+		mean += v
+	}
+	mean /= float64(len(d))
+
+	var nonZero []float64
+	for _, v := range d {
+		if v != 0 {
+			nonZero = append(nonZero, v)
+		}
+	}
+	n := len(nonZero)
+	result := &PairedTestResult{MeanDifference: mean, N: n, PValue: 1}
+	if n == 0 {
+		return result, nil
+	}
+
+	// 按绝对值排序并计算平均秩，秩乘2后总为整数
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(x, y int) bool { return math.Abs(nonZero[order[x]]) < math.Abs(nonZero[order[y]]) })
+	doubledRanks := make([]int, n)
+	var tieCorrection float64
+	for start := 0; start < n; {
+		end := start + 1
+		for end < n && math.Abs(nonZero[order[end]]) == math.Abs(nonZero[order[start]]) {
+			end++
+		}
+		// 秩start+1到end的平均值乘2
+		for k := start; k < end; k++ {
+			doubledRanks[order[k]] = start + 1 + end
+		}
+		t := float64(end - start)
+		tieCorrection += t*t*t - t
+		start = end
+	}
+
+	var doubledWPlus int
+	for i, v := range nonZero {
+		if v > 0 {
+			doubledWPlus += doubledRanks[i]
+		}
+	}
+	result.Statistic = float64(doubledWPlus) / 2
+
+	if n <= WilcoxonExactMaxSamples {
+		// counts[s] 为W+的两倍等于s的符号组合数，共2^n种组合等可能
+		total := 0
+		for _, r := range doubledRanks {
+			total += r
+		}
+		counts := make([]float64, total+1)
+		counts[0] = 1
+		for _, r := range doubledRanks {
+			for s := total; s >= r; s-- {
+				counts[s] += counts[s-r]
+			}
+		}
+		var lower, upper float64
+		for s, count := range counts {
+			if s <= doubledWPlus {
+				lower += count
+			}
+			if s >= doubledWPlus {
+				upper += count
+			}
+		}
+		combinations := math.Pow(2, float64(n))
+		result.PValue = math.Min(2*math.Min(lower, upper)/combinations, 1)
+		return result, nil
+	}
+
+	nf := float64(n)
+	expected := nf * (nf + 1) / 4
+	variance := nf*(nf+1)*(2*nf+1)/24 - tieCorrection/48
+	if variance <= 0 {
+		return result, nil
+	}
+	diff := result.Statistic - expected
+	// 连续性校正
+	diff = math.Copysign(math.Max(math.Abs(diff)-0.5, 0), diff)
+	z := diff / math.Sqrt(variance)
+	result.PValue = math.Min(2*distuv.UnitNormal.Survival(math.Abs(z)), 1)
+	return result, nil
+}
+
+// differences 返回配对样本的差值a-b
+func differences(a, b []float64) ([]float64, error) {
+	if len(a) != len(b) {
+		return nil, errors.New("配对样本长度不匹配")
+	}
+	if len(a) == 0 {
+		return nil, errors.New("配对样本不能为空")
+	}
+	d := make([]float64, len(a))
+	for i := range a {
+		d[i] = a[i] - b[i]
+	}
+	return d, nil
+}
@@ -0,0 +1,136 @@
+package evaluation
+
+import (
+	"math"
+	"testing"
+)
+
+// R自带的sleep数据集，两种药物在同一组10名受试者上增加的睡眠时长
+var (
+	sleepGroup1 = []float64{0.7, -1.6, -0.2, -1.2, -0.1, 3.4, 3.7, 0.8, 0.0, 2.0}
+	sleepGroup2 = []float64{1.9, 0.8, 1.1, 0.1, -0.1, 4.4, 5.5, 1.6, 4.6, 3.4}
+)
+
+func TestPairedTTest(t *testing.T) {
+	tests := []struct {
+		name      string
+		ratio     float64
+		statistic float64
+		pValue    float64
+	}{
+		// R: t.test(extra ~ group, data = sleep, paired = TRUE) 给出 t = -4.0621, df = 9, p-value = 0.002833
+		{"paired", 0, -4.0621277, 0.0028329},
+		// Nadeau-Bengio修正：t = d̄/√(s²·(1/n+0.25))，参考值按该定义和t分布(df = 9)独立计算
+		{"corrected", 0.25, -2.1712986, 0.0579907},
+	}
+	for _, tc := range tests {
+		got, err := CorrectedPairedTTest(sleepGroup1, sleepGroup2, tc.ratio)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if math.Abs(got.Statistic-tc.statistic) > 1e-6 || math.Abs(got.PValue-tc.pValue) > 1e-6 {
+			t.Errorf("%s: t = %.7f, p = %.7f, want %.7f, %.7f", tc.name, got.Statistic, got.PValue, tc.statistic, tc.pValue)
+		}
+		if got.N != 10 || math.Abs(got.MeanDifference+1.58) > 1e-9 {
+			t.Errorf("%s: n = %d, mean difference = %g, want 10, -1.58", tc.name, got.N, got.MeanDifference)
+		}
+	}
+
+	plain, err := PairedTTest(sleepGroup1, sleepGroup2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(plain.Statistic-tests[0].statistic) > 1e-6 {
+		t.Errorf("PairedTTest t = %.7f, want %.7f", plain.Statistic, tests[0].statistic)
+	}
+
+	// 差值全部相同时方差为0
+	got, err := PairedTTest([]float64{2, 3, 4}, []float64{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(got.Statistic, 1) || got.PValue != 0 {
+		t.Errorf("constant difference: t = %g, p = %g, want +Inf, 0", got.Statistic, got.PValue)
+	}
+
+	errorCases := []struct {
+		name  string
+		a, b  []float64
+		ratio float64
+	}{
+		{"mismatched", []float64{1, 2}, []float64{1}, 0},
+		{"single pair", []float64{1}, []float64{2}, 0},
+		{"negative ratio", sleepGroup1, sleepGroup2, -0.1},
+	}
+	for _, tc := range errorCases {
+		if _, err := CorrectedPairedTTest(tc.a, tc.b, tc.ratio); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+func TestWilcoxonSignedRank(t *testing.T) {
+	// 差值中含0和绝对值相同的结，非零差值有31个，超过WilcoxonExactMaxSamples时使用正态近似
+	approximate := []float64{0.5, -0.3, 1.2, 0.8, -1.1, 2.0, 0.8, 1.5, -0.4, 0.9, 1.3, -0.8, 2.2, 0.6, 1.7, 0, 1.0, -0.6, 1.4, 0.3, 2.5, -0.9, 1.1, 0.7, 1.8, -0.2, 1.6, 0.4, 1.9, 1.2, -1.3, 0.5}
+
+	tests := []struct {
+		name      string
+		a, b      []float64
+		statistic float64
+		pValue    float64
+		n         int
+	}{
+		// R ?wilcox.test 中的抑郁量表示例：wilcox.test(x, y, paired = TRUE, alternative = "greater") 给出 V = 40, p-value = 0.01953，
+		// 无结时精确分布对称，双侧p值为其2倍
+		{
+			name:      "exact",
+			a:         []float64{1.83, 0.50, 1.62, 2.48, 1.68, 1.88, 1.55, 3.06, 1.30},
+			b:         []float64{0.878, 0.647, 0.598, 2.05, 1.06, 1.29, 1.06, 3.14, 1.29},
+			statistic: 40,
+			pValue:    0.0390625,
+			n:         9,
+		},
+		// 参考值按R的wilcox.test(exact = FALSE, correct = TRUE)的公式独立计算：
+		// z = (V - n(n+1)/4 - 0.5·sign) / √(n(n+1)(2n+1)/24 - Σ(t³-t)/48)
+		{
+			name:      "normal approximation",
+			a:         approximate,
+			b:         make([]float64, len(approximate)),
+			statistic: 414,
+			pValue:    0.0011779643,
+			n:         31,
+		},
+	}
+	for _, tc := range tests {
+		got, err := WilcoxonSignedRank(tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got.Statistic != tc.statistic || math.Abs(got.PValue-tc.pValue) > 1e-9 || got.N != tc.n {
+			t.Errorf("%s: V = %g, p = %.10f, n = %d, want %g, %.10f, %d",
+				tc.name, got.Statistic, got.PValue, got.N, tc.statistic, tc.pValue, tc.n)
+		}
+	}
+
+	// 配对数很少时精确p值有下限 2/2⁵
+	got, err := WilcoxonSignedRank([]float64{2, 3, 4, 5, 6}, []float64{1, 1, 1, 1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Statistic != 15 || got.PValue != 0.0625 {
+		t.Errorf("five positive pairs: V = %g, p = %g, want 15, 0.0625", got.Statistic, got.PValue)
+	}
+
+	// 差值全为0时没有有效配对
+	got, err = WilcoxonSignedRank([]float64{1, 2}, []float64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.N != 0 || got.PValue != 1 {
+		t.Errorf("identical samples: n = %d, p = %g, want 0, 1", got.N, got.PValue)
+	}
+
+	if _, err := WilcoxonSignedRank(nil, nil); err == nil {
+		t.Error("expected an error for empty samples")
+	}
+}
@@ -0,0 +1,82 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ConfigModel 由模型类型和参数描述的模型，实现Model和Cloner，使内部模型可以用于KFoldCrossValidation等函数。
+// 每次Fit都由Factory创建新的未训练模型，因此同一实例重复训练或克隆后并发训练都互不影响
+type ConfigModel struct {
+	Config  models.ModelConfig
+	Factory models.ModelFactory
+	model   models.Model
+}
+
+// NewConfigModel 创建指定类型和参数的模型，使用models.ModelManager创建内部模型
+func NewConfigModel(modelType string, params map[string]interface{}) *ConfigModel {
+	return &ConfigModel{
+		Config:  models.ModelConfig{ModelType: modelType, Parameters: params},
+		Factory: models.NewModelManager(),
+	}
+}
+
+// Fit 创建新的内部模型并训练
+func (m *ConfigModel) Fit(X [][]float64, y []float64) error {
+	if len(X) == 0 || len(X) != len(y) {
+		return errors.New("特征矩阵和目标变量长度不匹配或为空")
+	}
+	features, err := rowsToDense(X)
+	if err != nil {
+		return err
+	}
+	model, err := m.Factory.CreateModel(&m.Config)
+	if err != nil {
+		return err
+	}
+	if err := model.Fit(features, mat.NewVecDense(len(y), append([]float64(nil), y...))); err != nil {
+		return err
+	}
+	m.model = model
+	return nil
+}
+
+// Predict 使用最近一次训练得到的内部模型预测
+func (m *ConfigModel) Predict(X [][]float64) ([]float64, error) {
+	if m.model == nil {
+		return nil, fmt.Errorf("模型 %s 尚未训练", m.Config.ModelType)
+	}
+	if len(X) == 0 {
+		return nil, errors.New("特征矩阵不能为空")
+	}
+	features, err := rowsToDense(X)
+	if err != nil {
+		return nil, err
+	}
+	return mat.Col(nil, 0, m.model.Predict(features)), nil
+}
+
+// Clone 返回类型、参数和Factory相同的未训练模型
+func (m *ConfigModel) Clone() Model {
+	return &ConfigModel{Config: m.Config, Factory: m.Factory}
+}
+
+// Model 返回最近一次训练得到的内部模型，未训练时为nil
+func (m *ConfigModel) Model() models.Model {
+	return m.model
+}
+
+// rowsToDense 将按行存储的特征转换为gonum矩阵，要求各行长度相同
+func rowsToDense(X [][]float64) (*mat.Dense, error) {
+	dense := mat.NewDense(len(X), len(X[0]), nil)
+	for i, row := range X {
+		if len(row) != len(X[0]) {
+			return nil, fmt.Errorf("第 %d 行的特征数 %d 与第一行的 %d 不一致", i, len(row), len(X[0]))
+		}
+		dense.SetRow(i, row)
+	}
+	return dense, nil
+}
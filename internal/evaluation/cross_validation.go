@@ -3,9 +3,14 @@ package evaluation
 import (
 	"errors"
 	"fmt"
+	"github.com/feiyuluoye/Go-Model/internal/models"
 	"github.com/feiyuluoye/Go-Model/internal/types"
+	"gonum.org/v1/gonum/mat"
 	"math"
 	"math/rand"
+	"reflect"
+	"runtime"
+	"sync"
 	"time"
 )
 
@@ -17,8 +22,63 @@ type Model interface {
 	Predict(X [][]float64) ([]float64, error)
 }
 
-// KFoldCrossValidation 执行k折交叉验证
+// Cloner 可以复制自身的模型，Clone返回超参数相同、未训练的新实例。
+// KFoldCrossValidation等只接收一个模型实例的函数要求模型实现该接口，以便每折在新实例上训练
+type Cloner interface {
+	Clone() Model
+}
+
+// KFoldCrossValidation 执行k折交叉验证，使用以当前时间为种子的独立随机源划分折；
+// 需要可复现的结果时使用KFoldCrossValidationWithRand
 func KFoldCrossValidation(model Model, X [][]float64, y []float64, k int) (map[string]float64, error) {
+	return KFoldCrossValidationWithRand(model, X, y, k, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+// KFoldCrossValidationWithRand 使用rng打乱样本后执行k折交叉验证，相同种子得到相同的折。
+// model必须实现Cloner，每折在它的新副本上训练，model本身不会被训练；各折串行执行，需要并发时使用KFoldCrossValidationParallel
+func KFoldCrossValidationWithRand(model Model, X [][]float64, y []float64, k int, rng *rand.Rand) (map[string]float64, error) {
+	newModel, err := cloneModel(model)
+	if err != nil {
+		return nil, err
+	}
+	return kFoldCrossValidation(newModel, X, y, k, rng, 1, EvaluateModel)
+}
+
+// KFoldCrossValidationParallel 与KFoldCrossValidationWithRand相同，但每折调用newModel创建新的未训练模型，
+// 由最多workers个goroutine并发训练和评估（不大于0时使用CPU核数）。结果与workers无关。
+// newModel每次都返回同一个实例时模型无法为每折复制，各折退回串行执行
+func KFoldCrossValidationParallel(newModel func() Model, X [][]float64, y []float64, k int, rng *rand.Rand, workers int) (map[string]float64, error) {
+	if newModel == nil {
+		return nil, errors.New("模型构造函数不能为空")
+	}
+	return kFoldCrossValidation(newModel, X, y, k, rng, workers, EvaluateModel)
+}
+
+// KFoldCrossValidationWithScorer 与KFoldCrossValidationParallel相同，但用score代替回归指标评估每折，
+// 返回得分的均值"score"和标准差"score_std"，可用于分类模型（如ScoringFunc(ScoringF1)）或自定义指标
+func KFoldCrossValidationWithScorer(newModel func() Model, X [][]float64, y []float64, k int, rng *rand.Rand, workers int, score ScoreFunc) (map[string]float64, error) {
+	if newModel == nil || score == nil {
+		return nil, errors.New("模型构造函数和评分函数不能为空")
+	}
+	return kFoldCrossValidation(newModel, X, y, k, rng, workers, func(yTrue, yPred []float64) (map[string]float64, error) {
+		value, err := score(yTrue, yPred)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]float64{"score": value}, nil
+	})
+}
+
+// kFoldCrossValidation 划分k折后由最多workers个goroutine执行各折，用evaluate评估每折的预测，
+// 返回各指标的均值和标准差（以_std结尾）
+func kFoldCrossValidation(newModel func() Model, X [][]float64, y []float64, k int, rng *rand.Rand, workers int, evaluate func(yTrue, yPred []float64) (map[string]float64, error)) (map[string]float64, error) {
+	if rng == nil {
+		return nil, errors.New("随机数生成器不能为空")
+	}
+	// 各折共用同一个实例时并发训练会互相覆盖模型状态，只能串行
+	if workers != 1 && sameInstance(newModel(), newModel()) {
+		workers = 1
+	}
 	if k <= 1 {
 		return nil, errors.New("折数必须大于1")
 	}
@@ -38,8 +98,7 @@ func KFoldCrossValidation(model Model, X [][]float64, y []float64, k int) (map[s
 		indices[i] = i
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(nSamples, func(i, j int) {
+	rng.Shuffle(nSamples, func(i, j int) {
 		indices[i], indices[j] = indices[j], indices[i]
 	})
 
@@ -47,10 +106,7 @@ func KFoldCrossValidation(model Model, X [][]float64, y []float64, k int) (map[s
 	foldSize := nSamples / k
 	extraSamples := nSamples % k
 
-	// 存储每折的评估指标
-	foldMetrics := make([]map[string]float64, k)
-
-	// 执行k折交叉验证
+	folds := make([]Fold, k)
 	start := 0
 	for fold := 0; fold < k; fold++ {
 		// 计算当前折的大小
@@ -60,55 +116,86 @@ func KFoldCrossValidation(model Model, X [][]float64, y []float64, k int) (map[s
 		}
 
 		// 分割训练集和测试集
-		testIndices := indices[start : start+size]
 		trainIndices := make([]int, 0, nSamples-size)
 		trainIndices = append(trainIndices, indices[:start]...)
 		trainIndices = append(trainIndices, indices[start+size:]...)
+		folds[fold] = Fold{Train: trainIndices, Test: indices[start : start+size]}
+		start += size
+	}
+
+	return foldCrossValidation(newModel, X, y, folds, workers, evaluate)
+}
+
+// foldCrossValidation 由最多workers个goroutine按给定的划分执行各折，用evaluate评估每折的预测，
+// 返回所有折都有的指标的均值和标准差（以_std结尾）
+func foldCrossValidation(newModel func() Model, X [][]float64, y []float64, folds []Fold, workers int, evaluate func(yTrue, yPred []float64) (map[string]float64, error)) (map[string]float64, error) {
+	k := len(folds)
+
+	// 存储每折的评估指标
+	foldMetrics := make([]map[string]float64, k)
 
+	// 执行k折交叉验证
+	err := RunFolds(k, workers, func(fold int) error {
 		// 创建训练集
-		trainX := make([][]float64, len(trainIndices))
-		trainY := make([]float64, len(trainIndices))
-		for i, idx := range trainIndices {
+		trainX := make([][]float64, len(folds[fold].Train))
+		trainY := make([]float64, len(folds[fold].Train))
+		for i, idx := range folds[fold].Train {
 			trainX[i] = make([]float64, len(X[idx]))
 			copy(trainX[i], X[idx])
 			trainY[i] = y[idx]
 		}
 
 		// 创建测试集
-		testX := make([][]float64, len(testIndices))
-		testY := make([]float64, len(testIndices))
-		for i, idx := range testIndices {
+		testX := make([][]float64, len(folds[fold].Test))
+		testY := make([]float64, len(folds[fold].Test))
+		for i, idx := range folds[fold].Test {
 			testX[i] = make([]float64, len(X[idx]))
 			copy(testX[i], X[idx])
 			testY[i] = y[idx]
 		}
 
 		// 训练模型
-		modelCopy := cloneModel(model)
+		modelCopy := newModel()
 		err := modelCopy.Fit(trainX, trainY)
 		if err != nil {
-			return nil, fmt.Errorf("折 %d 训练失败: %v", fold, err)
+			return fmt.Errorf("折 %d 训练失败: %v", fold, err)
 		}
 
 		// 预测
 		predictions, err := modelCopy.Predict(testX)
 		if err != nil {
-			return nil, fmt.Errorf("折 %d 预测失败: %v", fold, err)
+			return fmt.Errorf("折 %d 预测失败: %v", fold, err)
 		}
 
 		// 评估
-		metrics, err := EvaluateModel(testY, predictions)
+		metrics, err := evaluate(testY, predictions)
 		if err != nil {
-			return nil, fmt.Errorf("折 %d 评估失败: %v", fold, err)
+			return fmt.Errorf("折 %d 评估失败: %v", fold, err)
 		}
 
 		foldMetrics[fold] = metrics
-		start += size
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// 计算平均指标
 	averageMetrics := make(map[string]float64)
-	metricNames := []string{"r2", "mse", "rmse", "mae"}
+	var metricNames []string
+	for name := range foldMetrics[0] {
+		// 只汇总所有折都有的指标（如某一折真实值全为0时没有mape）
+		inAllFolds := true
+		for _, metrics := range foldMetrics {
+			if _, ok := metrics[name]; !ok {
+				inAllFolds = false
+				break
+			}
+		}
+		if inAllFolds {
+			metricNames = append(metricNames, name)
+		}
+	}
 
 	for _, name := range metricNames {
 		var sum float64
@@ -133,17 +220,46 @@ func KFoldCrossValidation(model Model, X [][]float64, y []float64, k int) (map[s
 	return averageMetrics, nil
 }
 
+// LeaveOneGroupOutCrossValidation 执行留一组交叉验证：groups[i]为第i个样本的组标签（如受试者或门店），
+// 每折以一个组的全部样本为验证集、其余组为训练集，返回各指标在各组上的均值和标准差（以_std结尾）。
+// 与LeaveOneOutCrossValidation相同，model必须实现Cloner，各折串行执行
+func LeaveOneGroupOutCrossValidation(model Model, X [][]float64, y []float64, groups []string) (map[string]float64, error) {
+	if len(X) != len(y) || len(groups) != len(y) {
+		return nil, errors.New("特征矩阵、目标变量和组标签长度不匹配")
+	}
+	newModel, err := cloneModel(model)
+	if err != nil {
+		return nil, err
+	}
+	folds, err := LeaveOneGroupOut{}.Split(groups)
+	if err != nil {
+		return nil, err
+	}
+	return foldCrossValidation(newModel, X, y, folds, 1, EvaluateModel)
+}
+
 // LeaveOneOutCrossValidation 执行留一法交叉验证
 func LeaveOneOutCrossValidation(model Model, X [][]float64, y []float64) (map[string]float64, error) {
 	return KFoldCrossValidation(model, X, y, len(X))
 }
 
-// 为了简单起见，这里提供一个模型克隆函数
-// 注意：在实际实现中，您可能需要根据具体的模型类型实现更复杂的克隆逻辑
-func cloneModel(model Model) Model {
-	// 这个实现是简化版的，在实际使用时需要根据具体模型类型进行扩展
-	// 这里假设model是一个可以直接使用的模型实例
-	return model
+// cloneModel 返回为每折创建model新副本的构造函数。各折若共用同一个实例，上一折训练得到的状态会影响下一折，
+// 并发时还会互相覆盖，因此不支持克隆的模型直接报错
+func cloneModel(model Model) (func() Model, error) {
+	if model == nil {
+		return nil, errors.New("模型不能为空")
+	}
+	cloner, ok := model.(Cloner)
+	if !ok {
+		return nil, fmt.Errorf("模型 %T 未实现Cloner，无法为每折创建新实例；请使用ConfigModel或KFoldCrossValidationParallel", model)
+	}
+	return cloner.Clone, nil
+}
+
+// sameInstance 判断a和b是否为同一个指针指向的模型实例
+func sameInstance(a, b Model) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Kind() == reflect.Pointer && va.Type() == vb.Type() && va.Pointer() == vb.Pointer()
 }
 
 // CrossValidateDataset 使用Dataset进行交叉验证
@@ -154,3 +270,240 @@ func CrossValidateDataset(model Model, dataset *types.Dataset, k int) (map[strin
 
 	return KFoldCrossValidation(model, dataset.Features, dataset.Target, k)
 }
+
+// CrossValidator 基于模型配置的K折交叉验证器
+// 每一折都会根据模型类型和参数创建新的模型实例，避免各折之间共享状态，因此各折可以并发执行
+type CrossValidator struct {
+	K          int
+	RandomSeed int64
+	Scoring    string              // 评分方式（Scoring*常量），为空时使用模型的Score方法
+	ScoreFunc  ScoreFunc           // 自定义评分函数，设置后忽略Scoring
+	Stratify   bool                // 按目标类别分层划分（用于分类），使每折的类别比例与整体一致
+	Workers    int                 // 并发训练和评估的折数，不大于0时使用CPU核数，为1时串行
+	Factory    models.ModelFactory // 为每折创建新的未训练模型，必须可并发调用
+}
+
+// NewCrossValidator 创建新的交叉验证器
+func NewCrossValidator(k int, randomSeed int64) *CrossValidator {
+	return &CrossValidator{
+		K:          k,
+		RandomSeed: randomSeed,
+		Factory:    models.NewModelManager(),
+	}
+}
+
+// Validate 对数据集执行K折交叉验证，返回每折验证集上的模型得分
+// 得分由ScoreFunc或Scoring决定，默认由模型的Score方法给出（回归为R²，分类为准确率）
+func (cv *CrossValidator) Validate(dataset *types.Dataset, modelType string, params map[string]interface{}) ([]float64, error) {
+	if dataset == nil || !dataset.IsValid() {
+		return nil, errors.New("无效的数据集")
+	}
+	folds, err := cv.Split(dataset.Target)
+	if err != nil {
+		return nil, err
+	}
+	return cv.ValidateFolds(dataset, modelType, params, folds)
+}
+
+// ValidateGroups 对数据集执行留一组交叉验证，groups[i]为第i个样本的组标签；
+// 返回以每个组（按首次出现的顺序）为验证集时的得分，忽略K和Stratify
+func (cv *CrossValidator) ValidateGroups(dataset *types.Dataset, modelType string, params map[string]interface{}, groups []string) ([]float64, error) {
+	if dataset == nil || !dataset.IsValid() {
+		return nil, errors.New("无效的数据集")
+	}
+	if len(groups) != dataset.NumSamples() {
+		return nil, fmt.Errorf("组标签数 %d 与样本数 %d 不匹配", len(groups), dataset.NumSamples())
+	}
+	folds, err := LeaveOneGroupOut{}.Split(groups)
+	if err != nil {
+		return nil, err
+	}
+	return cv.ValidateFolds(dataset, modelType, params, folds)
+}
+
+// Split 将样本按RandomSeed打乱后划分为K折，Stratify为true时按target的类别分层
+func (cv *CrossValidator) Split(target []float64) ([]Fold, error) {
+	if cv.Stratify {
+		return NewStratifiedKFold(cv.K, cv.RandomSeed).Split(target)
+	}
+	return NewKFold(cv.K, cv.RandomSeed).Split(len(target))
+}
+
+// ValidateFolds 按给定的划分（如TimeSeriesSplit的结果）执行交叉验证，返回每折验证集上的模型得分
+func (cv *CrossValidator) ValidateFolds(dataset *types.Dataset, modelType string, params map[string]interface{}, folds []Fold) ([]float64, error) {
+	if dataset == nil || !dataset.IsValid() {
+		return nil, errors.New("无效的数据集")
+	}
+	if len(folds) == 0 {
+		return nil, errors.New("没有可用的折")
+	}
+
+	config := &models.ModelConfig{
+		ModelType:  modelType,
+		Parameters: params,
+	}
+
+	if err := checkFolds(folds); err != nil {
+		return nil, err
+	}
+	scores := make([]float64, len(folds))
+	err := RunFolds(len(folds), cv.Workers, func(fold int) error {
+		f := folds[fold]
+		model, err := cv.fit(dataset, config, f.Train)
+		if err != nil {
+			return fmt.Errorf("折 %d 训练失败: %v", fold, err)
+		}
+
+		testX, testY := subsetMatrix(dataset, f.Test)
+		score, err := cv.score(model, testX, testY)
+		if err != nil {
+			return fmt.Errorf("折 %d 评分失败: %v", fold, err)
+		}
+		scores[fold] = score
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// TrainValidationScores 按给定的划分执行交叉验证，返回每折模型在其训练集和验证集上的得分，
+// 两者的差距反映过拟合的程度
+func (cv *CrossValidator) TrainValidationScores(dataset *types.Dataset, modelType string, params map[string]interface{}, folds []Fold) ([]float64, []float64, error) {
+	if dataset == nil || !dataset.IsValid() {
+		return nil, nil, errors.New("无效的数据集")
+	}
+	if len(folds) == 0 {
+		return nil, nil, errors.New("没有可用的折")
+	}
+
+	config := &models.ModelConfig{
+		ModelType:  modelType,
+		Parameters: params,
+	}
+	if err := checkFolds(folds); err != nil {
+		return nil, nil, err
+	}
+	trainScores := make([]float64, len(folds))
+	validationScores := make([]float64, len(folds))
+	err := RunFolds(len(folds), cv.Workers, func(fold int) error {
+		f := folds[fold]
+		model, err := cv.fit(dataset, config, f.Train)
+		if err != nil {
+			return fmt.Errorf("折 %d 训练失败: %v", fold, err)
+		}
+
+		trainX, trainY := subsetMatrix(dataset, f.Train)
+		if trainScores[fold], err = cv.score(model, trainX, trainY); err != nil {
+			return fmt.Errorf("折 %d 评分失败: %v", fold, err)
+		}
+		testX, testY := subsetMatrix(dataset, f.Test)
+		if validationScores[fold], err = cv.score(model, testX, testY); err != nil {
+			return fmt.Errorf("折 %d 评分失败: %v", fold, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return trainScores, validationScores, nil
+}
+
+// score 按ScoreFunc或Scoring计算已训练模型的得分
+func (cv *CrossValidator) score(model models.Model, X *mat.Dense, y *mat.VecDense) (float64, error) {
+	if cv.ScoreFunc != nil {
+		return ScoreModelWith(model, X, y, cv.ScoreFunc)
+	}
+	return ScoreModel(model, X, y, cv.Scoring)
+}
+
+// checkFolds 检查每一折的训练集和验证集都不为空
+func checkFolds(folds []Fold) error {
+	for fold, f := range folds {
+		if len(f.Train) == 0 || len(f.Test) == 0 {
+			return fmt.Errorf("折 %d 的训练集或验证集为空", fold)
+		}
+	}
+	return nil
+}
+
+// RunFolds 由最多workers个goroutine并发执行run(0)到run(nFolds-1)，workers不大于0时使用CPU核数，为1时按顺序串行执行。
+// 各折互相独立，run只能写入属于该折的结果；全部执行完后返回编号最小的失败折的错误，与并发度无关
+func RunFolds(nFolds, workers int, run func(fold int) error) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, nFolds)
+	if workers <= 1 {
+		for fold := 0; fold < nFolds; fold++ {
+			if err := run(fold); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, nFolds)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for fold := range jobs {
+				errs[fold] = run(fold)
+			}
+		}()
+	}
+	for fold := 0; fold < nFolds; fold++ {
+		jobs <- fold
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fit 在indices指定的样本上训练新的模型实例
+func (cv *CrossValidator) fit(dataset *types.Dataset, config *models.ModelConfig, indices []int) (models.Model, error) {
+	model, err := cv.Factory.CreateModel(config)
+	if err != nil {
+		return nil, err
+	}
+	X, y := subsetMatrix(dataset, indices)
+	if err := model.FitWeighted(X, y, subsetWeights(dataset, indices)); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// subsetMatrix 按索引抽取样本并转换为gonum矩阵
+func subsetMatrix(dataset *types.Dataset, indices []int) (*mat.Dense, *mat.VecDense) {
+	nFeatures := dataset.NumFeatures()
+	X := mat.NewDense(len(indices), nFeatures, nil)
+	y := mat.NewVecDense(len(indices), nil)
+	for i, idx := range indices {
+		X.SetRow(i, dataset.Features[idx])
+		y.SetVec(i, dataset.Target[idx])
+	}
+	return X, y
+}
+
+// subsetWeights 按索引抽取样本权重，数据集未设置权重时返回nil
+func subsetWeights(dataset *types.Dataset, indices []int) *mat.VecDense {
+	if len(dataset.Weights) == 0 {
+		return nil
+	}
+	weights := mat.NewVecDense(len(indices), nil)
+	for i, idx := range indices {
+		weights.SetVec(i, dataset.Weights[idx])
+	}
+	return weights
+}
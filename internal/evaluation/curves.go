@@ -0,0 +1,257 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// ROC ROC曲线，第i个点为得分不小于Thresholds[i]时判为正类的假正率和真正率。
+// 第一个点的阈值为+Inf（全部判为负类），曲线从(0,0)开始、到(1,1)结束
+type ROC struct {
+	FPR        []float64
+	TPR        []float64
+	Thresholds []float64
+	AUC        float64 // 曲线下面积，等于随机抽取的正类样本得分高于负类样本的概率
+}
+
+// ROCCurve 根据二分类标签（0或1，正类为1）和正类得分（如预测概率）计算ROC曲线和AUC。
+// 得分相同的样本在同一个阈值处一起计入，AUC按梯形法计算；要求两个类别都有样本
+func ROCCurve(yTrue, scores []float64) (*ROC, error) {
+	order, positives, negatives, err := rankScores(yTrue, scores)
+	if err != nil {
+		return nil, err
+	}
+
+	roc := &ROC{FPR: []float64{0}, TPR: []float64{0}, Thresholds: []float64{math.Inf(1)}}
+	var tp, fp int
+	for p, i := range order {
+		if yTrue[i] == 1 {
+			tp++
+		} else {
+			fp++
+		}
+		// 得分相同的样本处于同一阈值，只在最后一个之后记录一个点
+		if p+1 < len(order) && scores[order[p+1]] == scores[i] {
+			continue
+		}
+		tpr := float64(tp) / float64(positives)
+		fpr := float64(fp) / float64(negatives)
+		last := len(roc.FPR) - 1
+		roc.AUC += (fpr - roc.FPR[last]) * (tpr + roc.TPR[last]) / 2
+		roc.FPR = append(roc.FPR, fpr)
+		roc.TPR = append(roc.TPR, tpr)
+		roc.Thresholds = append(roc.Thresholds, scores[i])
+	}
+	return roc, nil
+}
+
+// ROCAUC 计算ROC曲线下面积
+func ROCAUC(yTrue, scores []float64) (float64, error) {
+	roc, err := ROCCurve(yTrue, scores)
+	if err != nil {
+		return 0, err
+	}
+	return roc.AUC, nil
+}
+
+// YoudenThreshold 返回使约登指数TPR-FPR最大的阈值及该点的TPR和FPR，
+// 得分不小于该阈值时判为正类；指数相同时取更高（更保守）的阈值
+func (r *ROC) YoudenThreshold() (threshold, tpr, fpr float64) {
+	best := 0
+	for i := range r.Thresholds {
+		if r.TPR[i]-r.FPR[i] > r.TPR[best]-r.FPR[best] {
+			best = i
+		}
+	}
+	return r.Thresholds[best], r.TPR[best], r.FPR[best]
+}
+
+// PR 精确率-召回率曲线，第i个点为得分不小于Thresholds[i]时判为正类的精确率和召回率。
+// 第一个点的阈值为+Inf，召回率为0、精确率记为1；之后阈值递减，召回率单调不减
+type PR struct {
+	Precision        []float64
+	Recall           []float64
+	Thresholds       []float64
+	AveragePrecision float64 // 各阈值处精确率按召回率增量加权的和，不做插值
+}
+
+// PRCurve 根据二分类标签（0或1，正类为1）和正类得分计算精确率-召回率曲线和平均精确率(AP)。
+// 正类稀少时AP比ROC AUC更能反映模型找出正类的能力：随机得分的AP约等于正类比例，而AUC总是约0.5
+func PRCurve(yTrue, scores []float64) (*PR, error) {
+	order, positives, _, err := rankScores(yTrue, scores)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &PR{Precision: []float64{1}, Recall: []float64{0}, Thresholds: []float64{math.Inf(1)}}
+	var tp, fp int
+	for p, i := range order {
+		if yTrue[i] == 1 {
+			tp++
+		} else {
+			fp++
+		}
+		if p+1 < len(order) && scores[order[p+1]] == scores[i] {
+			continue
+		}
+		precision := float64(tp) / float64(tp+fp)
+		recall := float64(tp) / float64(positives)
+		pr.AveragePrecision += (recall - pr.Recall[len(pr.Recall)-1]) * precision
+		pr.Precision = append(pr.Precision, precision)
+		pr.Recall = append(pr.Recall, recall)
+		pr.Thresholds = append(pr.Thresholds, scores[i])
+	}
+	return pr, nil
+}
+
+// AveragePrecision 计算平均精确率，即精确率-召回率曲线的阶梯面积
+func AveragePrecision(yTrue, scores []float64) (float64, error) {
+	pr, err := PRCurve(yTrue, scores)
+	if err != nil {
+		return 0, err
+	}
+	return pr.AveragePrecision, nil
+}
+
+// BestF1Threshold 返回使F1最大的阈值及该点的精确率和召回率，得分不小于该阈值时判为正类；
+// F1相同时取更高的阈值。第一个点（+Inf）的召回率为0，只在所有点的F1都为0时返回
+func (p *PR) BestF1Threshold() (threshold, precision, recall float64) {
+	best, bestF1 := 0, 0.0
+	for i := range p.Thresholds {
+		if p.Precision[i]+p.Recall[i] == 0 {
+			continue
+		}
+		if f1 := 2 * p.Precision[i] * p.Recall[i] / (p.Precision[i] + p.Recall[i]); f1 > bestF1 {
+			best, bestF1 = i, f1
+		}
+	}
+	return p.Thresholds[best], p.Precision[best], p.Recall[best]
+}
+
+// rankScores 校验二分类标签和得分，返回按得分从高到低排列的样本下标以及正负类样本数
+func rankScores(yTrue, scores []float64) ([]int, int, int, error) {
+	if len(yTrue) != len(scores) {
+		return nil, 0, 0, errors.New("预测值和真实值长度不匹配")
+	}
+	var positives, negatives int
+	for i, label := range yTrue {
+		switch label {
+		case 1:
+			positives++
+		case 0:
+			negatives++
+		default:
+			return nil, 0, 0, fmt.Errorf("第 %d 个样本的标签 %v 不是0或1", i, label)
+		}
+		if math.IsNaN(scores[i]) {
+			return nil, 0, 0, fmt.Errorf("第 %d 个样本的得分为NaN", i)
+		}
+	}
+	if positives == 0 || negatives == 0 {
+		return nil, 0, 0, errors.New("真实标签中必须同时包含正类和负类样本")
+	}
+
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+	return order, positives, negatives, nil
+}
+
+// 校准曲线的分箱方式
+const (
+	CalibrationUniform  = "uniform"  // 将[0, 1]等分为等宽的箱
+	CalibrationQuantile = "quantile" // 按预测概率的分位数分箱，各箱样本数大致相同
+)
+
+// Calibration 校准曲线（可靠性图），第i个点为第i个非空箱内预测概率的均值和正类样本的实际比例。
+// 完全校准的模型各点落在对角线上；点在对角线下方说明预测概率偏高（过于自信地判为正类），上方说明偏低
+type Calibration struct {
+	MeanPredicted            []float64 // 箱内预测概率的均值
+	FractionPositive         []float64 // 箱内正类样本的比例
+	Counts                   []int     // 箱内样本数
+	BinEdges                 []float64 // 箱的边界，第k个箱为[BinEdges[k], BinEdges[k+1])，最后一个箱包含右端点；空箱不产生点
+	ExpectedCalibrationError float64   // 各箱|实际比例-平均预测概率|按样本数加权的均值(ECE)
+	MaxCalibrationError      float64   // 各箱|实际比例-平均预测概率|的最大值(MCE)
+}
+
+// CalibrationCurve 根据二分类标签（0或1，正类为1）和预测的正类概率计算校准曲线，nBins为箱数，
+// strategy为Calibration*常量之一（为空时为等宽分箱）。按分位数分箱时重复的边界会被合并，箱数可能少于nBins
+func CalibrationCurve(yTrue, probabilities []float64, nBins int, strategy string) (*Calibration, error) {
+	if len(yTrue) != len(probabilities) {
+		return nil, errors.New("预测值和真实值长度不匹配")
+	}
+	if len(yTrue) == 0 {
+		return nil, errors.New("样本数不能为0")
+	}
+	if nBins < 1 {
+		return nil, errors.New("箱数必须为正数")
+	}
+	for i, label := range yTrue {
+		if label != 0 && label != 1 {
+			return nil, fmt.Errorf("第 %d 个样本的标签 %v 不是0或1", i, label)
+		}
+		if p := probabilities[i]; math.IsNaN(p) || p < 0 || p > 1 {
+			return nil, fmt.Errorf("第 %d 个样本的预测概率 %v 不在[0, 1]内", i, p)
+		}
+	}
+
+	var edges []float64
+	switch strategy {
+	case "", CalibrationUniform:
+		for k := 0; k <= nBins; k++ {
+			edges = append(edges, float64(k)/float64(nBins))
+		}
+	case CalibrationQuantile:
+		sorted := append([]float64(nil), probabilities...)
+		sort.Float64s(sorted)
+		for k := 0; k <= nBins; k++ {
+			edge := stat.Quantile(float64(k)/float64(nBins), stat.LinInterp, sorted, nil)
+			if len(edges) == 0 || edge > edges[len(edges)-1] {
+				edges = append(edges, edge)
+			}
+		}
+		if len(edges) == 1 {
+			// 所有预测概率相同
+			edges = append(edges, edges[0])
+		}
+	default:
+		return nil, fmt.Errorf("不支持的分箱方式: %s", strategy)
+	}
+
+	bins := len(edges) - 1
+	counts := make([]int, bins)
+	sumPredicted := make([]float64, bins)
+	sumPositive := make([]float64, bins)
+	for i, p := range probabilities {
+		// 第一个右边界大于p的箱，p等于最后一个边界时归入最后一个箱
+		k := sort.Search(bins, func(k int) bool { return edges[k+1] > p })
+		if k == bins {
+			k = bins - 1
+		}
+		counts[k]++
+		sumPredicted[k] += p
+		sumPositive[k] += yTrue[i]
+	}
+
+	curve := &Calibration{BinEdges: edges}
+	for k := 0; k < bins; k++ {
+		if counts[k] == 0 {
+			continue
+		}
+		meanPredicted := sumPredicted[k] / float64(counts[k])
+		fraction := sumPositive[k] / float64(counts[k])
+		gap := math.Abs(fraction - meanPredicted)
+		curve.MeanPredicted = append(curve.MeanPredicted, meanPredicted)
+		curve.FractionPositive = append(curve.FractionPositive, fraction)
+		curve.Counts = append(curve.Counts, counts[k])
+		curve.ExpectedCalibrationError += gap * float64(counts[k]) / float64(len(probabilities))
+		curve.MaxCalibrationError = math.Max(curve.MaxCalibrationError, gap)
+	}
+	return curve, nil
+}
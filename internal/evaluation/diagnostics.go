@@ -0,0 +1,430 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// 残差诊断的经验阈值
+const (
+	DurbinWatsonLower     = 1.5  // Durbin-Watson统计量低于该值时存在正自相关
+	DurbinWatsonUpper     = 2.5  // Durbin-Watson统计量高于该值时存在负自相关
+	DiagnosticsAlpha      = 0.05 // 假设检验的显著性水平
+	ResidualOutlierZ      = 3.0  // 标准化残差绝对值超过该值的样本视为异常点
+	ResidualMeanTolerance = 0.1  // 残差均值超过残差标准差的该比例时认为存在系统性偏差
+	ShapiroWilkMaxSamples = 5000 // Shapiro-Wilk检验p值近似适用的最大样本数
+)
+
+// BreuschPaganTest Breusch-Pagan异方差检验结果
+type BreuschPaganTest struct {
+	Statistic float64 `json:"statistic"` // LM统计量 n·R²，R²为残差平方对特征回归的决定系数
+	DF        int     `json:"df"`        // 卡方分布的自由度，等于线性无关的特征数
+	PValue    float64 `json:"p_value"`   // 原假设为同方差
+}
+
+// ResidualSummary 残差与拟合值的汇总
+type ResidualSummary struct {
+	Mean                 float64 `json:"mean"`
+	Std                  float64 `json:"std"`
+	Min                  float64 `json:"min"`
+	Max                  float64 `json:"max"`
+	FittedCorrelation    float64 `json:"fitted_correlation"`     // 残差与拟合值的相关系数，不为0说明模型遗漏了线性结构
+	AbsFittedCorrelation float64 `json:"abs_fitted_correlation"` // 残差绝对值与拟合值的相关系数，为正说明误差随拟合值增大（漏斗形）
+	Outliers             []int   `json:"outliers"`               // 标准化残差绝对值超过ResidualOutlierZ的样本下标
+}
+
+// ShapiroWilkTest Shapiro-Wilk正态性检验结果
+type ShapiroWilkTest struct {
+	Statistic float64 `json:"statistic"` // W统计量，取值在(0,1]之间，越接近1越接近正态分布
+	PValue    float64 `json:"p_value"`   // 原假设为服从正态分布
+}
+
+// JarqueBeraTest Jarque-Bera正态性检验结果
+type JarqueBeraTest struct {
+	Statistic float64 `json:"statistic"` // n/6·(S²+(K-3)²/4)，大样本下服从自由度为2的卡方分布
+	PValue    float64 `json:"p_value"`   // 原假设为服从正态分布
+	Skewness  float64 `json:"skewness"`  // 偏度，正态分布为0
+	Kurtosis  float64 `json:"kurtosis"`  // 峰度，正态分布为3
+}
+
+// QQPoint QQ图上的一个点：排序后的残差及对应的标准正态分位数，残差服从正态分布时各点近似在一条直线上
+type QQPoint struct {
+	Theoretical float64 `json:"theoretical"`
+	Sample      float64 `json:"sample"`
+}
+
+// ResidualDiagnostics 线性模型的残差诊断报告
+type ResidualDiagnostics struct {
+	NumSamples      int              `json:"num_samples"`
+	DurbinWatson    float64          `json:"durbin_watson"` // 取值在[0,4]之间，接近2说明残差无一阶自相关
+	BreuschPagan    BreuschPaganTest `json:"breusch_pagan"`
+	ShapiroWilk     *ShapiroWilkTest `json:"shapiro_wilk,omitempty"` // 样本数超出[3, ShapiroWilkMaxSamples]或残差为常数时为nil
+	JarqueBera      *JarqueBeraTest  `json:"jarque_bera,omitempty"`  // 残差为常数时为nil
+	Residuals       ResidualSummary  `json:"residuals"`
+	QQPlot          []QQPoint        `json:"qq_plot"`
+	Autocorrelated  bool             `json:"autocorrelated"`  // Durbin-Watson统计量超出[DurbinWatsonLower, DurbinWatsonUpper]
+	Heteroskedastic bool             `json:"heteroskedastic"` // Breusch-Pagan检验的p值小于DiagnosticsAlpha
+	NonNormal       bool             `json:"non_normal"`      // Shapiro-Wilk（不可用时为Jarque-Bera）检验的p值小于DiagnosticsAlpha
+	Biased          bool             `json:"biased"`          // 残差均值明显偏离0
+	Warnings        []string         `json:"warnings"`
+	Passed          bool             `json:"passed"` // 未发现自相关、异方差、非正态和系统性偏差
+}
+
+// DurbinWatson 计算残差的Durbin-Watson统计量 Σ(e_t-e_{t-1})²/Σe_t²，残差需按观测顺序（如时间）排列。
+// 残差全为0时返回2（无自相关）
+func DurbinWatson(residuals []float64) (float64, error) {
+	if len(residuals) < 2 {
+		return 0, errors.New("至少需要2个残差才能计算Durbin-Watson统计量")
+	}
+	var diffSquares, squares float64
+	for t, e := range residuals {
+		squares += e * e
+		if t > 0 {
+			diff := e - residuals[t-1]
+			diffSquares += diff * diff
+		}
+	}
+	if squares == 0 {
+		return 2, nil
+	}
+	return diffSquares / squares, nil
+}
+
+// BreuschPagan 执行Koenker稳健形式的Breusch-Pagan异方差检验：将残差平方对截距和特征做最小二乘回归，
+// LM=n·R²在同方差假设下服从自由度为特征数的卡方分布。特征完全共线时按线性无关的特征数计算自由度
+func BreuschPagan(X *mat.Dense, residuals []float64) (*BreuschPaganTest, error) {
+	if X == nil {
+		return nil, errors.New("特征矩阵不能为空")
+	}
+	n, p := X.Dims()
+	if n != len(residuals) {
+		return nil, errors.New("特征矩阵行数和残差长度不匹配")
+	}
+	if n <= p+1 {
+		return nil, errors.New("样本数必须大于特征数加1")
+	}
+
+	squared := make([]float64, n)
+	var mean float64
+	for i, e := range residuals {
+		squared[i] = e * e
+		mean += squared[i]
+	}
+	mean /= float64(n)
+	var sst float64
+	for _, g := range squared {
+		sst += (g - mean) * (g - mean)
+	}
+	if sst == 0 {
+		// 残差平方全部相同，没有异方差的迹象
+		return &BreuschPaganTest{Statistic: 0, DF: p, PValue: 1}, nil
+	}
+
+	// 中心化特征后，残差平方在特征列空间上的投影即为去掉截距后的回归拟合值
+	centered := mat.NewDense(n, p, nil)
+	for j := 0; j < p; j++ {
+		var colMean float64
+		for i := 0; i < n; i++ {
+			colMean += X.At(i, j)
+		}
+		colMean /= float64(n)
+		for i := 0; i < n; i++ {
+			centered.Set(i, j, X.At(i, j)-colMean)
+		}
+	}
+	var svd mat.SVD
+	if !svd.Factorize(centered, mat.SVDThin) {
+		return nil, errors.New("特征矩阵奇异值分解失败")
+	}
+	values := svd.Values(nil)
+	var u mat.Dense
+	svd.UTo(&u)
+
+	target := make([]float64, n)
+	for i, g := range squared {
+		target[i] = g - mean
+	}
+	targetVec := mat.NewVecDense(n, target)
+	var ssr float64
+	rank := 0
+	for k, s := range values {
+		if s <= values[0]*1e-10*float64(max(n, p)) {
+			continue
+		}
+		rank++
+		proj := mat.Dot(u.ColView(k), targetVec)
+		ssr += proj * proj
+	}
+	if rank == 0 {
+		return nil, errors.New("特征全部为常数，无法进行Breusch-Pagan检验")
+	}
+
+	statistic := float64(n) * ssr / sst
+	chi2 := distuv.ChiSquared{K: float64(rank)}
+	return &BreuschPaganTest{Statistic: statistic, DF: rank, PValue: chi2.Survival(statistic)}, nil
+}
+
+// ShapiroWilk 执行Shapiro-Wilk正态性检验，系数和p值按Royston(1995)的AS R94算法近似计算，
+// 要求样本数在[3, ShapiroWilkMaxSamples]之间且不全相同
+func ShapiroWilk(x []float64) (*ShapiroWilkTest, error) {
+	n := len(x)
+	if n < 3 {
+		return nil, errors.New("Shapiro-Wilk检验至少需要3个样本")
+	}
+	if n > ShapiroWilkMaxSamples {
+		return nil, fmt.Errorf("Shapiro-Wilk检验的样本数不能超过%d", ShapiroWilkMaxSamples)
+	}
+	sorted := append([]float64(nil), x...)
+	sort.Float64s(sorted)
+	if sorted[0] == sorted[n-1] {
+		return nil, errors.New("所有样本的值相同，无法进行Shapiro-Wilk检验")
+	}
+
+	// 系数a由正态顺序统计量期望的近似m_i构造，两端的系数用多项式修正
+	a := make([]float64, n)
+	if n == 3 {
+		a[0], a[2] = -math.Sqrt(0.5), math.Sqrt(0.5)
+	} else {
+		m := normalScores(n)
+		var summ2 float64
+		for _, v := range m {
+			summ2 += v * v
+		}
+		u := 1 / math.Sqrt(float64(n))
+		last := m[n-1]/math.Sqrt(summ2) + polynomial(u, 0, 0.221157, -0.147981, -2.071190, 4.434685, -2.706056)
+		a[n-1], a[0] = last, -last
+		inner, phi := 1, (summ2-2*m[n-1]*m[n-1])/(1-2*last*last)
+		if n > 5 {
+			second := m[n-2]/math.Sqrt(summ2) + polynomial(u, 0, 0.042981, -0.293762, -1.752461, 5.682633, -3.582633)
+			a[n-2], a[1] = second, -second
+			inner = 2
+			phi = (summ2 - 2*m[n-1]*m[n-1] - 2*m[n-2]*m[n-2]) / (1 - 2*last*last - 2*second*second)
+		}
+		for i := inner; i < n-inner; i++ {
+			a[i] = m[i] / math.Sqrt(phi)
+		}
+	}
+
+	var mean float64
+	for _, v := range sorted {
+		mean += v
+	}
+	mean /= float64(n)
+	var numerator, denominator float64
+	for i, v := range sorted {
+		numerator += a[i] * v
+		denominator += (v - mean) * (v - mean)
+	}
+	w := math.Min(numerator*numerator/denominator, 1)
+
+	// W的p值：n=3时有精确分布，其余对ln(1-W)做正态近似
+	var pValue float64
+	switch {
+	case n == 3:
+		pValue = math.Max(6/math.Pi*(math.Asin(math.Sqrt(w))-math.Asin(math.Sqrt(0.75))), 0)
+	case n <= 11:
+		fn := float64(n)
+		gamma := -2.273 + 0.459*fn
+		y := math.Log(1 - w)
+		if y >= gamma {
+			pValue = 0
+			break
+		}
+		mu := polynomial(fn, 0.5440, -0.39978, 0.025054, -0.0006714)
+		sigma := math.Exp(polynomial(fn, 1.3822, -0.77857, 0.062767, -0.0020322))
+		pValue = distuv.UnitNormal.Survival((-math.Log(gamma-y) - mu) / sigma)
+	default:
+		ln := math.Log(float64(n))
+		mu := polynomial(ln, -1.5861, -0.31082, -0.083751, 0.0038915)
+		sigma := math.Exp(polynomial(ln, -0.4803, -0.082676, 0.0030302))
+		pValue = distuv.UnitNormal.Survival((math.Log(1-w) - mu) / sigma)
+	}
+	return &ShapiroWilkTest{Statistic: w, PValue: math.Min(pValue, 1)}, nil
+}
+
+// JarqueBera 根据样本的偏度和峰度执行Jarque-Bera正态性检验，p值为大样本下的卡方近似，小样本时偏保守
+func JarqueBera(x []float64) (*JarqueBeraTest, error) {
+	n := float64(len(x))
+	if len(x) < 2 {
+		return nil, errors.New("Jarque-Bera检验至少需要2个样本")
+	}
+	var mean float64
+	for _, v := range x {
+		mean += v
+	}
+	mean /= n
+	var m2, m3, m4 float64
+	for _, v := range x {
+		d := v - mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	m2, m3, m4 = m2/n, m3/n, m4/n
+	if m2 == 0 {
+		return nil, errors.New("所有样本的值相同，无法进行Jarque-Bera检验")
+	}
+
+	skewness := m3 / math.Pow(m2, 1.5)
+	kurtosis := m4 / (m2 * m2)
+	statistic := n / 6 * (skewness*skewness + (kurtosis-3)*(kurtosis-3)/4)
+	chi2 := distuv.ChiSquared{K: 2}
+	return &JarqueBeraTest{Statistic: statistic, PValue: chi2.Survival(statistic), Skewness: skewness, Kurtosis: kurtosis}, nil
+}
+
+// QQPlot 返回正态QQ图的点：按从小到大排列的样本值及对应的标准正态分位数，
+// 第i个点的分位数取Φ⁻¹((i-0.375)/(n+0.25))
+func QQPlot(x []float64) []QQPoint {
+	sorted := append([]float64(nil), x...)
+	sort.Float64s(sorted)
+	scores := normalScores(len(sorted))
+	points := make([]QQPoint, len(sorted))
+	for i, v := range sorted {
+		points[i] = QQPoint{Theoretical: scores[i], Sample: v}
+	}
+	return points
+}
+
+// normalScores 返回n个标准正态顺序统计量期望的Blom近似Φ⁻¹((i-0.375)/(n+0.25))，i=1..n
+func normalScores(n int) []float64 {
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = distuv.UnitNormal.Quantile((float64(i+1) - 0.375) / (float64(n) + 0.25))
+	}
+	return scores
+}
+
+// polynomial 计算多项式 c0 + c1·x + c2·x² + ...
+func polynomial(x float64, coefficients ...float64) float64 {
+	var result float64
+	for k := len(coefficients) - 1; k >= 0; k-- {
+		result = result*x + coefficients[k]
+	}
+	return result
+}
+
+// DiagnoseResiduals 根据特征、真实值和模型拟合值诊断线性模型的残差：
+// Durbin-Watson自相关、Breusch-Pagan异方差、Shapiro-Wilk和Jarque-Bera正态性以及残差与拟合值的关系，
+// 并给出是否通过的判断
+func DiagnoseResiduals(X *mat.Dense, yTrue, yFitted []float64) (*ResidualDiagnostics, error) {
+	if len(yTrue) != len(yFitted) {
+		return nil, errors.New("预测值和真实值长度不匹配")
+	}
+	n := len(yTrue)
+	residuals := make([]float64, n)
+	for i := range yTrue {
+		residuals[i] = yTrue[i] - yFitted[i]
+	}
+
+	dw, err := DurbinWatson(residuals)
+	if err != nil {
+		return nil, err
+	}
+	bp, err := BreuschPagan(X, residuals)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ResidualDiagnostics{
+		NumSamples:   n,
+		DurbinWatson: dw,
+		BreuschPagan: *bp,
+		Residuals:    summarizeResiduals(residuals, yFitted),
+		QQPlot:       QQPlot(residuals),
+	}
+	// 残差为常数（如完美拟合）时正态性检验没有定义，不记录
+	if sw, err := ShapiroWilk(residuals); err == nil {
+		report.ShapiroWilk = sw
+	}
+	if jb, err := JarqueBera(residuals); err == nil {
+		report.JarqueBera = jb
+	}
+
+	if dw < DurbinWatsonLower {
+		report.Autocorrelated = true
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Durbin-Watson统计量为%.3f，残差存在正自相关", dw))
+	} else if dw > DurbinWatsonUpper {
+		report.Autocorrelated = true
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Durbin-Watson统计量为%.3f，残差存在负自相关", dw))
+	}
+	if bp.PValue < DiagnosticsAlpha {
+		report.Heteroskedastic = true
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Breusch-Pagan检验p值为%.4f，残差存在异方差，系数的标准误不可靠", bp.PValue))
+	}
+	switch {
+	case report.ShapiroWilk != nil && report.ShapiroWilk.PValue < DiagnosticsAlpha:
+		report.NonNormal = true
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Shapiro-Wilk检验p值为%.4f，残差不服从正态分布，系数的t检验和置信区间不可靠", report.ShapiroWilk.PValue))
+	case report.ShapiroWilk == nil && report.JarqueBera != nil && report.JarqueBera.PValue < DiagnosticsAlpha:
+		report.NonNormal = true
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Jarque-Bera检验p值为%.4f，残差不服从正态分布，系数的t检验和置信区间不可靠", report.JarqueBera.PValue))
+	}
+	summary := report.Residuals
+	if summary.Std > 0 && math.Abs(summary.Mean) > ResidualMeanTolerance*summary.Std {
+		report.Biased = true
+		report.Warnings = append(report.Warnings, fmt.Sprintf("残差均值为%.4g，预测存在系统性偏差", summary.Mean))
+	}
+	if len(summary.Outliers) > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d个样本的标准化残差绝对值超过%.0f", len(summary.Outliers), ResidualOutlierZ))
+	}
+
+	report.Passed = !report.Autocorrelated && !report.Heteroskedastic && !report.NonNormal && !report.Biased
+	return report, nil
+}
+
+// summarizeResiduals 计算残差的均值、标准差、范围、与拟合值的相关系数和异常点
+func summarizeResiduals(residuals, fitted []float64) ResidualSummary {
+	n := float64(len(residuals))
+	summary := ResidualSummary{Min: math.Inf(1), Max: math.Inf(-1)}
+	for _, e := range residuals {
+		summary.Mean += e
+		summary.Min = math.Min(summary.Min, e)
+		summary.Max = math.Max(summary.Max, e)
+	}
+	summary.Mean /= n
+
+	var ss float64
+	for _, e := range residuals {
+		ss += (e - summary.Mean) * (e - summary.Mean)
+	}
+	summary.Std = math.Sqrt(ss / (n - 1))
+
+	absResiduals := make([]float64, len(residuals))
+	for i, e := range residuals {
+		absResiduals[i] = math.Abs(e)
+		if summary.Std > 0 && math.Abs(e-summary.Mean)/summary.Std > ResidualOutlierZ {
+			summary.Outliers = append(summary.Outliers, i)
+		}
+	}
+	summary.FittedCorrelation = pearson(residuals, fitted)
+	summary.AbsFittedCorrelation = pearson(absResiduals, fitted)
+	return summary
+}
+
+// pearson 计算皮尔逊相关系数，任一变量为常数时返回0
+func pearson(x, y []float64) float64 {
+	n := float64(len(x))
+	var meanX, meanY float64
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= n
+	meanY /= n
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
@@ -0,0 +1,154 @@
+package evaluation
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// R自带的cars数据集（speed, dist），50个样本
+var (
+	carsSpeed = []float64{4, 4, 7, 7, 8, 9, 10, 10, 10, 11, 11, 12, 12, 12, 12, 13, 13, 13, 13, 14, 14, 14, 14, 15, 15, 15, 16, 16, 17, 17, 17, 18, 18, 18, 18, 19, 19, 19, 20, 20, 20, 20, 20, 22, 23, 24, 24, 24, 24, 25}
+	carsDist  = []float64{2, 10, 4, 22, 16, 10, 18, 26, 34, 17, 28, 14, 20, 24, 28, 26, 34, 34, 46, 26, 36, 60, 80, 20, 26, 54, 32, 40, 32, 40, 50, 42, 56, 76, 84, 36, 46, 68, 32, 48, 52, 56, 64, 66, 54, 70, 92, 93, 120, 85}
+)
+
+// carsResiduals 返回lm(dist ~ speed, data = cars)的残差
+func carsResiduals() []float64 {
+	n := float64(len(carsSpeed))
+	var mx, my float64
+	for i := range carsSpeed {
+		mx += carsSpeed[i]
+		my += carsDist[i]
+	}
+	mx, my = mx/n, my/n
+	var sxx, sxy float64
+	for i := range carsSpeed {
+		sxx += (carsSpeed[i] - mx) * (carsSpeed[i] - mx)
+		sxy += (carsSpeed[i] - mx) * (carsDist[i] - my)
+	}
+	slope := sxy / sxx
+	intercept := my - slope*mx
+	residuals := make([]float64, len(carsSpeed))
+	for i := range carsSpeed {
+		residuals[i] = carsDist[i] - intercept - slope*carsSpeed[i]
+	}
+	return residuals
+}
+
+func TestDurbinWatson(t *testing.T) {
+	tests := []struct {
+		name      string
+		residuals []float64
+		want      float64
+	}{
+		// R: lmtest::dwtest(dist ~ speed, data = cars) 给出 DW = 1.6762
+		{"cars", carsResiduals(), 1.6762253},
+		{"alternating", []float64{1, -1, 1, -1}, 3},
+		{"constant", []float64{2, 2, 2, 2}, 0},
+		{"zero", []float64{0, 0, 0}, 2},
+	}
+	for _, tc := range tests {
+		got, err := DurbinWatson(tc.residuals)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if math.Abs(got-tc.want) > 1e-6 {
+			t.Errorf("%s: DW = %.7f, want %.7f", tc.name, got, tc.want)
+		}
+	}
+
+	if _, err := DurbinWatson([]float64{1}); err == nil {
+		t.Error("expected an error for a single residual")
+	}
+}
+
+func TestBreuschPagan(t *testing.T) {
+	X := mat.NewDense(len(carsSpeed), 1, append([]float64(nil), carsSpeed...))
+	// R: lmtest::bptest(dist ~ speed, data = cars) 给出 BP = 3.2149, df = 1, p-value = 0.07297
+	got, err := BreuschPagan(X, carsResiduals())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got.Statistic-3.2148799) > 1e-6 || got.DF != 1 || math.Abs(got.PValue-0.0729715) > 1e-6 {
+		t.Errorf("BP = %.7f, df = %d, p = %.7f, want 3.2148799, 1, 0.0729715", got.Statistic, got.DF, got.PValue)
+	}
+
+	// 重复的特征列不增加自由度
+	duplicated := mat.NewDense(len(carsSpeed), 2, nil)
+	for i, v := range carsSpeed {
+		duplicated.SetRow(i, []float64{v, 2 * v})
+	}
+	got, err = BreuschPagan(duplicated, carsResiduals())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DF != 1 || math.Abs(got.Statistic-3.2148799) > 1e-6 {
+		t.Errorf("collinear features: BP = %.7f, df = %d, want 3.2148799, 1", got.Statistic, got.DF)
+	}
+
+	if _, err := BreuschPagan(X, carsResiduals()[:10]); err == nil {
+		t.Error("expected an error for mismatched lengths")
+	}
+}
+
+func TestShapiroWilk(t *testing.T) {
+	// R自带的ToothGrowth$len
+	toothLength := []float64{4.2, 11.5, 7.3, 5.8, 6.4, 10, 11.2, 11.2, 5.2, 7, 16.5, 16.5, 15.2, 17.3, 22.5, 17.3, 13.6, 14.5, 18.8, 15.5, 23.6, 18.5, 33.9, 25.5, 26.4, 32.5, 26.7, 21.5, 23.3, 29.5, 15.2, 21.5, 17.6, 9.7, 14.5, 10, 8.2, 9.4, 16.5, 9.7, 19.7, 23.3, 23.6, 26.4, 20, 25.2, 25.8, 21.2, 14.5, 27.3, 25.5, 26.4, 22.4, 24.5, 24.8, 30.9, 26.4, 27.3, 29.4, 23}
+	tests := []struct {
+		name string
+		x    []float64
+		w, p float64
+		pTol float64
+	}{
+		// R: shapiro.test(ToothGrowth$len) 给出 W = 0.96743, p-value = 0.1091
+		{"tooth growth", toothLength, 0.96743, 0.1091, 1e-4},
+		// R: shapiro.test(c(148, 154, 158, 160, 161, 162, 166, 170, 182, 195, 236)) 给出 W = 0.78881, p-value = 0.006704
+		{"weights", []float64{148, 154, 158, 160, 161, 162, 166, 170, 182, 195, 236}, 0.78881, 0.006704, 1e-6},
+		// n = 3时p值有精确解，R: shapiro.test(c(1, 2, 4)) 给出 W = 0.96429, p-value = 0.6369
+		{"three samples", []float64{1, 2, 4}, 0.96429, 0.6369, 1e-4},
+	}
+	for _, tc := range tests {
+		got, err := ShapiroWilk(tc.x)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if math.Abs(got.Statistic-tc.w) > 1e-5 || math.Abs(got.PValue-tc.p) > tc.pTol {
+			t.Errorf("%s: W = %.5f, p = %.6f, want %.5f, %.6f", tc.name, got.Statistic, got.PValue, tc.w, tc.p)
+		}
+	}
+
+	if _, err := ShapiroWilk([]float64{1, 2}); err == nil {
+		t.Error("expected an error for fewer than 3 samples")
+	}
+	if _, err := ShapiroWilk([]float64{3, 3, 3, 3}); err == nil {
+		t.Error("expected an error for constant samples")
+	}
+}
+
+func TestJarqueBera(t *testing.T) {
+	// 参考值按定义 JB = n/6·(S²+(K-3)²/4)、p = exp(-JB/2)（自由度为2的卡方生存函数）独立计算，
+	// 与tseries::jarque.bera.test和statsmodels的jarque_bera使用同一公式
+	got, err := JarqueBera(carsResiduals())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := JarqueBeraTest{Statistic: 8.1887836, PValue: 0.0166659, Skewness: 0.8850519, Kurtosis: 3.8929437}
+	if math.Abs(got.Statistic-want.Statistic) > 1e-6 || math.Abs(got.PValue-want.PValue) > 1e-6 ||
+		math.Abs(got.Skewness-want.Skewness) > 1e-6 || math.Abs(got.Kurtosis-want.Kurtosis) > 1e-6 {
+		t.Errorf("JarqueBera = %+v, want %+v", *got, want)
+	}
+
+	// 对称且峰度为正态值附近的样本不应拒绝正态性
+	got, err = JarqueBera([]float64{-2, -1, -1, 0, 0, 0, 0, 1, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Skewness != 0 || got.PValue < 0.5 {
+		t.Errorf("symmetric sample: skewness = %g, p = %g", got.Skewness, got.PValue)
+	}
+
+	if _, err := JarqueBera([]float64{1, 1, 1}); err == nil {
+		t.Error("expected an error for constant samples")
+	}
+}
@@ -0,0 +1,161 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// PermutationImportanceResult 置换特征重要性
+type PermutationImportanceResult struct {
+	BaselineScore float64     `json:"baseline_score"` // 未打乱时的得分
+	Importances   []float64   `json:"importances"`    // 各特征打乱后得分下降量的均值，越大越重要，接近0或为负说明模型不依赖该特征
+	Std           []float64   `json:"std"`            // 各特征得分下降量在重复之间的标准差
+	Drops         [][]float64 `json:"drops"`          // Drops[j][r] 为第r次打乱第j个特征时的得分下降量
+}
+
+// PermutationImportance 计算已训练模型的置换特征重要性：每次随机打乱一个特征列（其余列不变），
+// 以scoring（见Scoring*常量）计算得分相对基线的下降量，重复nRepeats次。只需要模型的Predict/Score，
+// 适用于任意模型；高度相关的特征会分摊重要性。X不会被修改
+func PermutationImportance(model models.Model, X *mat.Dense, y *mat.VecDense, scoring string, nRepeats int, rng *rand.Rand) (*PermutationImportanceResult, error) {
+	if X == nil || y == nil {
+		return nil, errors.New("特征矩阵和目标不能为空")
+	}
+	n, p := X.Dims()
+	if n != y.Len() {
+		return nil, errors.New("特征矩阵行数和目标长度不匹配")
+	}
+	if n < 2 {
+		return nil, errors.New("至少需要2个样本才能打乱特征")
+	}
+	if nRepeats <= 0 {
+		return nil, errors.New("重复次数必须为正数")
+	}
+
+	baseline, err := ScoreModel(model, X, y, scoring)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PermutationImportanceResult{
+		BaselineScore: baseline,
+		Importances:   make([]float64, p),
+		Std:           make([]float64, p),
+		Drops:         make([][]float64, p),
+	}
+	permuted := mat.DenseCopyOf(X)
+	column := make([]float64, n)
+	for j := 0; j < p; j++ {
+		original := mat.Col(nil, j, X)
+		result.Drops[j] = make([]float64, nRepeats)
+		for r := 0; r < nRepeats; r++ {
+			copy(column, original)
+			rng.Shuffle(n, func(a, b int) { column[a], column[b] = column[b], column[a] })
+			permuted.SetCol(j, column)
+			score, err := ScoreModel(model, permuted, y, scoring)
+			if err != nil {
+				return nil, err
+			}
+			result.Drops[j][r] = baseline - score
+		}
+		permuted.SetCol(j, original)
+
+		var mean float64
+		for _, drop := range result.Drops[j] {
+			mean += drop
+		}
+		mean /= float64(nRepeats)
+		var ss float64
+		for _, drop := range result.Drops[j] {
+			ss += (drop - mean) * (drop - mean)
+		}
+		result.Importances[j] = mean
+		result.Std[j] = math.Sqrt(ss / float64(nRepeats))
+	}
+	return result, nil
+}
+
+// PartialDependenceResult 单个特征的部分依赖
+type PartialDependenceResult struct {
+	Feature int       `json:"feature"` // 特征的列下标
+	Grid    []float64 `json:"grid"`    // 特征的取值
+	Average []float64 `json:"average"` // 所有样本的该特征都取Grid[k]时预测值的均值
+}
+
+// PartialDependence 计算已训练模型对第feature个特征的部分依赖：对grid中的每个取值，将X中所有样本的该特征
+// 替换为这个值并预测，取预测值的均值，反映在其余特征的分布下该特征对预测的平均影响。X不会被修改
+func PartialDependence(model models.Model, X *mat.Dense, feature int, grid []float64) (*PartialDependenceResult, error) {
+	if X == nil {
+		return nil, errors.New("特征矩阵不能为空")
+	}
+	n, p := X.Dims()
+	if feature < 0 || feature >= p {
+		return nil, fmt.Errorf("特征下标 %d 超出范围 [0, %d)", feature, p)
+	}
+	if len(grid) == 0 {
+		return nil, errors.New("取值网格不能为空")
+	}
+
+	result := &PartialDependenceResult{
+		Feature: feature,
+		Grid:    append([]float64(nil), grid...),
+		Average: make([]float64, len(grid)),
+	}
+	modified := mat.DenseCopyOf(X)
+	column := make([]float64, n)
+	for k, value := range grid {
+		for i := range column {
+			column[i] = value
+		}
+		modified.SetCol(feature, column)
+		result.Average[k] = mat.Sum(model.Predict(modified)) / float64(n)
+	}
+	return result, nil
+}
+
+// PartialDependenceGrid 在第feature个特征的lower和upper分位数之间生成points个等距取值；
+// 特征的不同取值不超过points个时（如类别特征）直接使用这些取值
+func PartialDependenceGrid(X *mat.Dense, feature int, points int, lower, upper float64) ([]float64, error) {
+	if X == nil {
+		return nil, errors.New("特征矩阵不能为空")
+	}
+	_, p := X.Dims()
+	if feature < 0 || feature >= p {
+		return nil, fmt.Errorf("特征下标 %d 超出范围 [0, %d)", feature, p)
+	}
+	if points < 2 {
+		return nil, errors.New("网格至少需要2个取值")
+	}
+	if lower < 0 || upper > 1 || lower >= upper {
+		return nil, errors.New("分位数必须满足 0 <= lower < upper <= 1")
+	}
+
+	values := mat.Col(nil, feature, X)
+	sort.Float64s(values)
+	var unique []float64
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	if len(unique) <= points {
+		return unique, nil
+	}
+
+	low := stat.Quantile(lower, stat.LinInterp, values, nil)
+	high := stat.Quantile(upper, stat.LinInterp, values, nil)
+	if low == high {
+		return []float64{low}, nil
+	}
+	grid := make([]float64, points)
+	for k := range grid {
+		grid[k] = low + (high-low)*float64(k)/float64(points-1)
+	}
+	return grid, nil
+}
@@ -0,0 +1,82 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// LearningCurveResult 学习曲线，得分按 [训练样本数][折] 排列
+type LearningCurveResult struct {
+	TrainSizes       []int       `json:"train_sizes"`       // 实际使用的训练样本数，升序且不重复
+	TrainScores      [][]float64 `json:"train_scores"`      // 模型在所用训练样本上的得分
+	ValidationScores [][]float64 `json:"validation_scores"` // 模型在该折验证集上的得分
+}
+
+// LearningCurve 对每一折，依次取训练集的前 fraction×m 个样本训练新模型（m为各折训练集大小的最小值，
+// fraction取值(0, 1]），并在所用训练样本和该折验证集上评分。随机K折的训练集索引已经打乱，
+// 取前若干个即为随机子集；时间序列划分时为最早的样本
+func (cv *CrossValidator) LearningCurve(dataset *types.Dataset, modelType string, params map[string]interface{}, folds []Fold, fractions []float64) (*LearningCurveResult, error) {
+	if dataset == nil || !dataset.IsValid() {
+		return nil, errors.New("无效的数据集")
+	}
+	if len(folds) == 0 {
+		return nil, errors.New("没有可用的折")
+	}
+	if len(fractions) == 0 {
+		return nil, errors.New("训练集比例不能为空")
+	}
+
+	maxTrain := len(folds[0].Train)
+	for fold, f := range folds {
+		if len(f.Train) == 0 || len(f.Test) == 0 {
+			return nil, fmt.Errorf("折 %d 的训练集或验证集为空", fold)
+		}
+		maxTrain = min(maxTrain, len(f.Train))
+	}
+
+	var sizes []int
+	for _, fraction := range fractions {
+		if fraction <= 0 || fraction > 1 {
+			return nil, fmt.Errorf("训练集比例必须在(0, 1]内: %v", fraction)
+		}
+		size := max(int(math.Round(fraction*float64(maxTrain))), 1)
+		sizes = append(sizes, size)
+	}
+	sizes = uniqueSortedInts(sizes)
+
+	result := &LearningCurveResult{
+		TrainSizes:       sizes,
+		TrainScores:      make([][]float64, len(sizes)),
+		ValidationScores: make([][]float64, len(sizes)),
+	}
+	truncated := make([]Fold, len(folds))
+	for k, size := range sizes {
+		for fold, f := range folds {
+			truncated[fold] = Fold{Train: f.Train[:size], Test: f.Test}
+		}
+		trainScores, validationScores, err := cv.TrainValidationScores(dataset, modelType, params, truncated)
+		if err != nil {
+			return nil, fmt.Errorf("训练样本数为 %d 时%v", size, err)
+		}
+		result.TrainScores[k] = trainScores
+		result.ValidationScores[k] = validationScores
+	}
+	return result, nil
+}
+
+// uniqueSortedInts 返回升序排列且去重后的整数
+func uniqueSortedInts(values []int) []int {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	var unique []int
+	for i, v := range sorted {
+		if i == 0 || v != sorted[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
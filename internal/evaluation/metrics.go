@@ -3,6 +3,7 @@ package evaluation
 import (
 	"errors"
 	"math"
+	"sort"
 
 	"gonum.org/v1/gonum/mat"
 )
@@ -50,6 +51,73 @@ func MAE(yTrue, yPred []float64) (float64, error) {
 	return sumAbsoluteError / n, nil
 }
 
+// MAPE 计算平均绝对百分比误差 (Mean Absolute Percentage Error) mean(|y-ŷ|/|y|)，以比例表示（0.1即10%）。
+// 真实值为0的样本百分比误差没有定义，不参与计算；真实值全为0时返回错误
+func MAPE(yTrue, yPred []float64) (float64, error) {
+	if len(yTrue) != len(yPred) {
+		return 0, errors.New("预测值和真实值长度不匹配")
+	}
+
+	var sumPercentageError float64
+	var count int
+	for i := range yTrue {
+		if yTrue[i] == 0 {
+			continue
+		}
+		sumPercentageError += math.Abs((yTrue[i] - yPred[i]) / yTrue[i])
+		count++
+	}
+	if count == 0 {
+		return 0, errors.New("真实值全为0，无法计算平均绝对百分比误差")
+	}
+
+	return sumPercentageError / float64(count), nil
+}
+
+// SMAPE 计算对称平均绝对百分比误差 (Symmetric MAPE) mean(2|y-ŷ|/(|y|+|ŷ|))，取值在[0,2]之间。
+// 真实值和预测值都为0的样本误差记为0
+func SMAPE(yTrue, yPred []float64) (float64, error) {
+	if len(yTrue) != len(yPred) {
+		return 0, errors.New("预测值和真实值长度不匹配")
+	}
+	if len(yTrue) == 0 {
+		return 0, errors.New("样本数不能为0")
+	}
+
+	var sumPercentageError float64
+	for i := range yTrue {
+		denominator := math.Abs(yTrue[i]) + math.Abs(yPred[i])
+		if denominator == 0 {
+			continue
+		}
+		sumPercentageError += 2 * math.Abs(yTrue[i]-yPred[i]) / denominator
+	}
+
+	return sumPercentageError / float64(len(yTrue)), nil
+}
+
+// MedianAE 计算绝对误差的中位数 (Median Absolute Error)，不受少数异常值影响
+func MedianAE(yTrue, yPred []float64) (float64, error) {
+	if len(yTrue) != len(yPred) {
+		return 0, errors.New("预测值和真实值长度不匹配")
+	}
+	if len(yTrue) == 0 {
+		return 0, errors.New("样本数不能为0")
+	}
+
+	errs := make([]float64, len(yTrue))
+	for i := range yTrue {
+		errs[i] = math.Abs(yTrue[i] - yPred[i])
+	}
+	sort.Float64s(errs)
+
+	mid := len(errs) / 2
+	if len(errs)%2 == 0 {
+		return (errs[mid-1] + errs[mid]) / 2, nil
+	}
+	return errs[mid], nil
+}
+
 // R2Score 计算决定系数 (Coefficient of Determination, R²)
 func R2Score(yTrue, yPred []float64) (float64, error) {
 	if len(yTrue) != len(yPred) {
@@ -137,7 +205,52 @@ func R2ScoreMat(yTrue, yPred *mat.VecDense) float64 {
 	return 1.0 - (sse / sst)
 }
 
-// EvaluateModel 计算所有评估指标并返回结果映射
+// AdjustedR2 计算调整R²：1-(1-R²)(n-1)/(n-p-1)，n为样本数、p为特征数（不含截距）。
+// 特征数越多惩罚越大，可用于比较特征数不同的模型；要求n>p+1
+func AdjustedR2(r2 float64, nSamples, nFeatures int) (float64, error) {
+	if nFeatures < 0 {
+		return 0, errors.New("特征数不能为负数")
+	}
+	if nSamples <= nFeatures+1 {
+		return 0, errors.New("样本数必须大于特征数加1")
+	}
+	n, p := float64(nSamples), float64(nFeatures)
+	return 1 - (1-r2)*(n-1)/(n-p-1), nil
+}
+
+// GaussianLogLikelihood 计算残差服从正态分布（方差取最大似然估计RSS/n）时的对数似然：
+// -n/2·(ln(2π·RSS/n)+1)；残差全为0时对数似然没有定义，返回错误
+func GaussianLogLikelihood(yTrue, yPred []float64) (float64, error) {
+	if len(yTrue) != len(yPred) {
+		return 0, errors.New("预测值和真实值长度不匹配")
+	}
+	if len(yTrue) == 0 {
+		return 0, errors.New("样本数不能为0")
+	}
+	var rss float64
+	for i := range yTrue {
+		diff := yTrue[i] - yPred[i]
+		rss += diff * diff
+	}
+	if rss == 0 {
+		return 0, errors.New("残差平方和为0，对数似然没有定义")
+	}
+	n := float64(len(yTrue))
+	return -n / 2 * (math.Log(2*math.Pi*rss/n) + 1), nil
+}
+
+// AIC 计算赤池信息准则 2k-2ln(L)，k为模型参数个数（线性模型为特征数加截距），越小越好
+func AIC(logLikelihood float64, nParams int) float64 {
+	return 2*float64(nParams) - 2*logLikelihood
+}
+
+// BIC 计算贝叶斯信息准则 k·ln(n)-2ln(L)，样本较多时对参数个数的惩罚比AIC更重，越小越好
+func BIC(logLikelihood float64, nParams, nSamples int) float64 {
+	return float64(nParams)*math.Log(float64(nSamples)) - 2*logLikelihood
+}
+
+// EvaluateModel 计算所有评估指标并返回结果映射：r2、mse、rmse、mae、mape、smape和median_ae，
+// 其中mape和smape以比例表示，不受目标变量量纲的影响
 func EvaluateModel(yTrue, yPred []float64) (map[string]float64, error) {
 	metrics := make(map[string]float64)
 
@@ -165,6 +278,23 @@ func EvaluateModel(yTrue, yPred []float64) (map[string]float64, error) {
 	}
 	metrics["mae"] = mae
 
+	// 真实值全为0时MAPE没有定义，不记录
+	if mape, err := MAPE(yTrue, yPred); err == nil {
+		metrics["mape"] = mape
+	}
+
+	smape, err := SMAPE(yTrue, yPred)
+	if err != nil {
+		return nil, err
+	}
+	metrics["smape"] = smape
+
+	medianAE, err := MedianAE(yTrue, yPred)
+	if err != nil {
+		return nil, err
+	}
+	metrics["median_ae"] = medianAE
+
 	return metrics, nil
 }
 
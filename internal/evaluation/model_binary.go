@@ -0,0 +1,150 @@
+package evaluation
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/models/linear"
+	"gonum.org/v1/gonum/mat"
+)
+
+// BinaryModelVersion 当前写入的二进制模型格式版本，读取时拒绝更高的版本
+const BinaryModelVersion uint32 = 1
+
+// binaryModelMagic 二进制模型文件开头的魔数，用于与JSON模型文件区分
+const binaryModelMagic = "GOMODEL\x00"
+
+// 模型参数中可能出现的非基本类型，gob编码interface{}时需要先注册
+func init() {
+	gob.Register([]float64{})
+	gob.Register([][]float64{})
+	gob.Register([]string{})
+	gob.Register(map[float64]float64{})
+	gob.Register(map[string]float64{})
+	gob.Register(map[string]interface{}{})
+	gob.Register([]map[string]interface{}{})
+	gob.Register(&linear.InferenceResult{})
+}
+
+// SaveModelBinary 将模型保存为二进制文件。文件由魔数、格式版本和gob编码的ModelData组成，
+// 浮点数按原始位模式保存（包括NaN和Inf），PLS等含权重矩阵的模型比JSON格式小得多
+func SaveModelBinary(model ModelSerializer, filePath string, metrics map[string]float64) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("创建模型文件失败: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	if err := WriteModelBinary(writer, model, metrics); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("写入模型文件失败: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("写入模型文件失败: %w", err)
+	}
+	return nil
+}
+
+// LoadModelBinary 从SaveModelBinary保存的文件加载模型到已创建的模型实例
+func LoadModelBinary(filePath string, model ModelSerializer) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("读取模型文件失败: %w", err)
+	}
+	defer file.Close()
+	return ReadModelBinary(bufio.NewReader(file), model)
+}
+
+// WriteModelBinary 将模型以二进制格式写入w
+func WriteModelBinary(w io.Writer, model ModelSerializer, metrics map[string]float64) error {
+	modelData := ModelData{
+		ModelType:    model.GetModelType(),
+		Parameters:   binaryParameters(model.GetParameters()),
+		TrainingTime: time.Now().Format(time.RFC3339),
+		Metrics:      metrics,
+	}
+
+	header := make([]byte, len(binaryModelMagic)+4)
+	copy(header, binaryModelMagic)
+	binary.BigEndian.PutUint32(header[len(binaryModelMagic):], BinaryModelVersion)
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("写入模型文件失败: %w", err)
+	}
+	if err := gob.NewEncoder(w).Encode(&modelData); err != nil {
+		return fmt.Errorf("序列化模型失败: %w", err)
+	}
+	return nil
+}
+
+// ReadModelBinary 从r读取二进制格式的模型并设置到已创建的模型实例
+func ReadModelBinary(r io.Reader, model ModelSerializer) error {
+	header := make([]byte, len(binaryModelMagic)+4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return errors.New("不是有效的二进制模型文件")
+	}
+	if string(header[:len(binaryModelMagic)]) != binaryModelMagic {
+		return errors.New("不是有效的二进制模型文件")
+	}
+	if version := binary.BigEndian.Uint32(header[len(binaryModelMagic):]); version > BinaryModelVersion {
+		return fmt.Errorf("不支持的模型文件版本: %d", version)
+	}
+
+	var modelData ModelData
+	if err := gob.NewDecoder(r).Decode(&modelData); err != nil {
+		return fmt.Errorf("解析模型数据失败: %w", err)
+	}
+	if model.GetModelType() != modelData.ModelType {
+		return errors.New("模型类型不匹配")
+	}
+	if err := model.SetParameters(modelData.Parameters); err != nil {
+		return fmt.Errorf("设置模型参数失败: %w", err)
+	}
+	return nil
+}
+
+// IsBinaryModelFile 判断文件是否为SaveModelBinary保存的二进制模型文件，否则应使用LoadModel按JSON格式读取
+func IsBinaryModelFile(filePath string) (bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, fmt.Errorf("读取模型文件失败: %w", err)
+	}
+	defer file.Close()
+	magic := make([]byte, len(binaryModelMagic))
+	n, err := io.ReadFull(file, magic)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("读取模型文件失败: %w", err)
+	}
+	return n == len(binaryModelMagic) && string(magic) == binaryModelMagic, nil
+}
+
+// binaryParameters 将gob无法直接编码的mat类型转换为切片，其余参数原样保留
+func binaryParameters(params map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		switch v := value.(type) {
+		case *mat.VecDense:
+			converted[key] = VecDenseToSlice(v)
+		case *mat.Dense:
+			rows, _ := v.Dims()
+			matrix := make([][]float64, rows)
+			for i := range matrix {
+				matrix[i] = mat.Row(nil, i, v)
+			}
+			converted[key] = matrix
+		case map[string]interface{}:
+			converted[key] = binaryParameters(v)
+		default:
+			converted[key] = value
+		}
+	}
+	return converted
+}
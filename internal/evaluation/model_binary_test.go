@@ -0,0 +1,127 @@
+package evaluation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/feiyuluoye/Go-Model/internal/models/linear"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, tc := range roundTripCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			X, y := tc.data()
+			model := tc.newModel()
+			if err := model.Fit(X, y); err != nil {
+				t.Fatalf("Fit: %v", err)
+			}
+
+			path := filepath.Join(t.TempDir(), "model.bin")
+			if err := SaveModelBinary(model, path, map[string]float64{"r2": 0.9}); err != nil {
+				t.Fatalf("SaveModelBinary: %v", err)
+			}
+			if isBinary, err := IsBinaryModelFile(path); err != nil || !isBinary {
+				t.Fatalf("IsBinaryModelFile = %v, %v", isBinary, err)
+			}
+			loaded := tc.newModel()
+			if err := LoadModelBinary(path, loaded); err != nil {
+				t.Fatalf("LoadModelBinary: %v", err)
+			}
+			assertSamePredictions(t, model, loaded, X)
+		})
+	}
+}
+
+// trainedBinaryModel 返回一个已训练OLS模型的二进制编码
+func trainedBinaryModel(t *testing.T) []byte {
+	t.Helper()
+	X, y := linearData()
+	model := linear.NewOLS()
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteModelBinary(&buf, model, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadModelBinaryTruncated(t *testing.T) {
+	data := trainedBinaryModel(t)
+	for n := 0; n < len(data); n++ {
+		if err := ReadModelBinary(bytes.NewReader(data[:n]), linear.NewOLS()); err == nil {
+			t.Fatalf("reading the first %d of %d bytes should fail", n, len(data))
+		}
+	}
+	if err := ReadModelBinary(bytes.NewReader(data), linear.NewOLS()); err != nil {
+		t.Fatalf("reading the complete model failed: %v", err)
+	}
+}
+
+func TestReadModelBinaryCorrupt(t *testing.T) {
+	valid := trainedBinaryModel(t)
+	headerLen := len(binaryModelMagic) + 4
+
+	corrupt := func(mutate func(data []byte) []byte) []byte {
+		return mutate(append([]byte(nil), valid...))
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"bad magic", corrupt(func(d []byte) []byte { d[0] = 'X'; return d }), "不是有效的二进制模型文件"},
+		{"json file", []byte(`{"model_type": "OLS", "parameters": {}}`), "不是有效的二进制模型文件"},
+		{"future version", corrupt(func(d []byte) []byte {
+			binary.BigEndian.PutUint32(d[len(binaryModelMagic):], BinaryModelVersion+1)
+			return d
+		}), "不支持的模型文件版本"},
+		{"garbage body", corrupt(func(d []byte) []byte {
+			for i := headerLen; i < len(d); i++ {
+				d[i] = byte(i * 31)
+			}
+			return d
+		}), "解析模型数据失败"},
+		{"empty body", valid[:headerLen], "解析模型数据失败"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ReadModelBinary(bytes.NewReader(tc.data), linear.NewOLS())
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	// 逐字节翻转模型数据，读取可以失败，但不能panic
+	for i := headerLen; i < len(valid); i++ {
+		data := corrupt(func(d []byte) []byte { d[i] ^= 0xff; return d })
+		ReadModelBinary(bytes.NewReader(data), linear.NewOLS())
+	}
+}
+
+func TestReadModelBinaryRejectsMismatchedType(t *testing.T) {
+	err := ReadModelBinary(bytes.NewReader(trainedBinaryModel(t)), linear.NewRidge(1))
+	if err == nil || !strings.Contains(err.Error(), "模型类型不匹配") {
+		t.Fatalf("error = %v, want a model type mismatch", err)
+	}
+}
+
+func TestIsBinaryModelFileOnJSON(t *testing.T) {
+	X, y := linearData()
+	model := linear.NewOLS()
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := SaveModel(model, path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if isBinary, err := IsBinaryModelFile(path); err != nil || isBinary {
+		t.Fatalf("IsBinaryModelFile on a JSON model = %v, %v", isBinary, err)
+	}
+}
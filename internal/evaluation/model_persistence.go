@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"reflect"
+	"strconv"
 	"time"
 
 	"gonum.org/v1/gonum/mat"
@@ -33,7 +34,7 @@ type ModelData struct {
 func SaveModel(model ModelSerializer, filePath string, metrics map[string]float64) error {
 	modelData := ModelData{
 		ModelType:    model.GetModelType(),
-		Parameters:   model.GetParameters(),
+		Parameters:   SerializeParameters(model.GetParameters()),
 		TrainingTime: time.Now().Format(time.RFC3339),
 		Metrics:      metrics,
 	}
@@ -97,15 +98,22 @@ func SliceToVecDense(slice []float64) *mat.VecDense {
 	return mat.NewVecDense(len(slice), slice)
 }
 
-// 辅助函数：序列化map中的mat.VecDense
+// 辅助函数：将map中JSON无法直接编码的mat.VecDense和以数值为键的map（如逻辑回归的class_weight）
+// 转换为切片和以字符串为键的map
 func SerializeParameters(params map[string]interface{}) map[string]interface{} {
 	serialized := make(map[string]interface{})
 
 	for key, value := range params {
-		// 处理mat.VecDense类型
-		if vec, ok := value.(*mat.VecDense); ok {
-			serialized[key] = VecDenseToSlice(vec)
-		} else {
+		switch v := value.(type) {
+		case *mat.VecDense:
+			serialized[key] = VecDenseToSlice(v)
+		case map[float64]float64:
+			entries := make(map[string]float64, len(v))
+			for k, w := range v {
+				entries[strconv.FormatFloat(k, 'g', -1, 64)] = w
+			}
+			serialized[key] = entries
+		default:
 			serialized[key] = value
 		}
 	}
@@ -0,0 +1,144 @@
+package evaluation
+
+import (
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/feiyuluoye/Go-Model/internal/models/linear"
+	"github.com/feiyuluoye/Go-Model/internal/models/nonlinear"
+	"gonum.org/v1/gonum/mat"
+)
+
+// persistableModel 可训练、可预测且可序列化的模型
+type persistableModel interface {
+	ModelSerializer
+	Fit(X *mat.Dense, y *mat.VecDense) error
+	Predict(X *mat.Dense) *mat.VecDense
+}
+
+// roundTripCase 一个模型的保存/加载用例，newModel返回未训练的新实例
+type roundTripCase struct {
+	name     string
+	newModel func() persistableModel
+	data     func() (*mat.Dense, *mat.VecDense)
+}
+
+// linearData 生成三个特征的线性回归数据
+func linearData() (*mat.Dense, *mat.VecDense) {
+	rng := rand.New(rand.NewSource(1))
+	n := 60
+	X := mat.NewDense(n, 3, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		a, b, c := rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()
+		X.SetRow(i, []float64{a, b, c})
+		y.SetVec(i, 1.5+2*a-b+0.5*c+0.1*rng.NormFloat64())
+	}
+	return X, y
+}
+
+// binaryData 生成两个特征的二分类数据
+func binaryData() (*mat.Dense, *mat.VecDense) {
+	rng := rand.New(rand.NewSource(2))
+	n := 80
+	X := mat.NewDense(n, 2, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		a, b := rng.NormFloat64(), rng.NormFloat64()
+		X.SetRow(i, []float64{a, b})
+		if a-b+0.8*rng.NormFloat64() > 0 {
+			y.SetVec(i, 1)
+		}
+	}
+	return X, y
+}
+
+// curveData 生成单特征、x和y均为正的数据，f为无噪声的曲线
+func curveData(f func(x float64) float64) func() (*mat.Dense, *mat.VecDense) {
+	return func() (*mat.Dense, *mat.VecDense) {
+		rng := rand.New(rand.NewSource(3))
+		n := 40
+		X := mat.NewDense(n, 1, nil)
+		y := mat.NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			x := 0.5 + 4*float64(i)/float64(n)
+			X.Set(i, 0, x)
+			y.SetVec(i, f(x)*(1+0.02*rng.NormFloat64()))
+		}
+		return X, y
+	}
+}
+
+func roundTripCases() []roundTripCase {
+	return []roundTripCase{
+		{"ols", func() persistableModel { return linear.NewOLS() }, linearData},
+		{"ridge", func() persistableModel { return linear.NewRidge(0.5) }, linearData},
+		{"lasso", func() persistableModel { return linear.NewLasso(0.05) }, linearData},
+		{"ridgecv", func() persistableModel { return linear.NewRidgeCV(nil) }, linearData},
+		{"lassocv", func() persistableModel { return linear.NewLassoCV(nil) }, linearData},
+		{"logistic", func() persistableModel { return linear.NewLogistic() }, binaryData},
+		{"logistic_class_weight", func() persistableModel {
+			model := linear.NewLogistic()
+			model.ClassWeight = map[float64]float64{0: 1, 1: 3}
+			return model
+		}, binaryData},
+		{"pls", func() persistableModel { return linear.NewPLS(2) }, linearData},
+		{"poly", func() persistableModel { return nonlinear.NewPolynomial(3) }, curveData(func(x float64) float64 { return 1 + x - 0.5*x*x + 0.1*x*x*x })},
+		{"exp", func() persistableModel { return nonlinear.NewExponential() }, curveData(func(x float64) float64 { return 2 * math.Exp(0.3*x) })},
+		{"log", func() persistableModel { return nonlinear.NewLogarithmic() }, curveData(func(x float64) float64 { return 3*math.Log(x) + 5 })},
+		{"pow", func() persistableModel { return nonlinear.NewPower() }, curveData(func(x float64) float64 { return 1.5 * math.Pow(x, 1.7) })},
+	}
+}
+
+// assertSamePredictions 检查两个模型在X上的预测完全相同
+func assertSamePredictions(t *testing.T, want, got persistableModel, X *mat.Dense) {
+	t.Helper()
+	wantPredictions, gotPredictions := want.Predict(X), got.Predict(X)
+	if wantPredictions.Len() != gotPredictions.Len() {
+		t.Fatalf("got %d predictions, want %d", gotPredictions.Len(), wantPredictions.Len())
+	}
+	for i := 0; i < wantPredictions.Len(); i++ {
+		if gotPredictions.AtVec(i) != wantPredictions.AtVec(i) {
+			t.Fatalf("prediction %d after reload = %v, want %v", i, gotPredictions.AtVec(i), wantPredictions.AtVec(i))
+		}
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	for _, tc := range roundTripCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			X, y := tc.data()
+			model := tc.newModel()
+			if err := model.Fit(X, y); err != nil {
+				t.Fatalf("Fit: %v", err)
+			}
+
+			path := filepath.Join(t.TempDir(), "model.json")
+			if err := SaveModel(model, path, map[string]float64{"r2": 0.9}); err != nil {
+				t.Fatalf("SaveModel: %v", err)
+			}
+			loaded := tc.newModel()
+			if err := LoadModel(path, loaded); err != nil {
+				t.Fatalf("LoadModel: %v", err)
+			}
+			assertSamePredictions(t, model, loaded, X)
+		})
+	}
+}
+
+func TestLoadModelRejectsMismatchedType(t *testing.T) {
+	X, y := linearData()
+	model := linear.NewOLS()
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := SaveModel(model, path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadModel(path, linear.NewRidge(1)); err == nil {
+		t.Fatal("loading an OLS model into a Ridge model should fail")
+	}
+}
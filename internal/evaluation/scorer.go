@@ -0,0 +1,172 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Scorer 评估指标，由真实值和模型的预测值计算指标值。
+// 误差类指标GreaterIsBetter为false，用作验证得分时取负值
+type Scorer interface {
+	Score(yTrue, yPred []float64) (float64, error)
+	GreaterIsBetter() bool
+}
+
+// ClassificationScorer 二分类指标。NeedsProbabilities为true时Score接收正类概率（如roc_auc、logloss），
+// 否则接收正类概率按0.5的阈值转换得到的类别（如accuracy、f1）。未实现该接口的Scorer视为回归指标
+type ClassificationScorer interface {
+	Scorer
+	NeedsProbabilities() bool
+}
+
+// metricScorer 由指标函数构造的回归指标
+type metricScorer struct {
+	metric  func(yTrue, yPred []float64) (float64, error)
+	greater bool
+}
+
+func (s *metricScorer) Score(yTrue, yPred []float64) (float64, error) { return s.metric(yTrue, yPred) }
+func (s *metricScorer) GreaterIsBetter() bool                         { return s.greater }
+
+// classificationScorer 由指标函数构造的分类指标
+type classificationScorer struct {
+	metricScorer
+	probabilities bool
+}
+
+func (s *classificationScorer) NeedsProbabilities() bool { return s.probabilities }
+
+// NewScorer 由指标函数构造回归指标
+func NewScorer(metric func(yTrue, yPred []float64) (float64, error), greaterIsBetter bool) Scorer {
+	return &metricScorer{metric: metric, greater: greaterIsBetter}
+}
+
+// NewClassificationScorer 由指标函数构造分类指标，needsProbabilities为true时指标函数接收正类概率，否则接收类别
+func NewClassificationScorer(metric func(yTrue, yPred []float64) (float64, error), greaterIsBetter, needsProbabilities bool) Scorer {
+	return &classificationScorer{metricScorer: metricScorer{metric: metric, greater: greaterIsBetter}, probabilities: needsProbabilities}
+}
+
+var (
+	scorersMu sync.RWMutex
+	scorers   = map[string]Scorer{}
+)
+
+// RegisterScorer 按名称注册评估指标，注册后可用于训练结果的指标、模型比较，以及作为验证和参数搜索的评分方式
+// （越大越好的指标用name，误差类指标用"neg_"+name）。名称不能为空，也不能与已注册的指标重复
+func RegisterScorer(name string, scorer Scorer) error {
+	if name == "" {
+		return errors.New("指标名称不能为空")
+	}
+	if scorer == nil {
+		return errors.New("评估指标不能为空")
+	}
+	scorersMu.Lock()
+	defer scorersMu.Unlock()
+	if _, exists := scorers[name]; exists {
+		return fmt.Errorf("指标 %s 已注册", name)
+	}
+	scorers[name] = scorer
+	return nil
+}
+
+// GetScorer 返回名称对应的评估指标
+func GetScorer(name string) (Scorer, error) {
+	scorersMu.RLock()
+	defer scorersMu.RUnlock()
+	scorer, ok := scorers[name]
+	if !ok {
+		return nil, fmt.Errorf("未注册的指标: %s", name)
+	}
+	return scorer, nil
+}
+
+// MetricNames 返回适用于分类模型（classifier为true）或回归模型的已注册指标名称，按名称排序
+func MetricNames(classifier bool) []string {
+	scorersMu.RLock()
+	defer scorersMu.RUnlock()
+	var names []string
+	for name, scorer := range scorers {
+		if _, ok := scorer.(ClassificationScorer); ok == classifier {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyScorer 由真实值和模型的预测值计算指标；不需要概率的分类指标先将正类概率按0.5的阈值转换为类别
+func ApplyScorer(scorer Scorer, yTrue, predictions []float64) (float64, error) {
+	if c, ok := scorer.(ClassificationScorer); ok && !c.NeedsProbabilities() {
+		classes := make([]float64, len(predictions))
+		for i, p := range predictions {
+			if p >= 0.5 {
+				classes[i] = 1
+			}
+		}
+		predictions = classes
+	}
+	return scorer.Score(yTrue, predictions)
+}
+
+// ComputeMetric 计算名称对应的已注册指标
+func ComputeMetric(name string, yTrue, predictions []float64) (float64, error) {
+	scorer, err := GetScorer(name)
+	if err != nil {
+		return 0, err
+	}
+	return ApplyScorer(scorer, yTrue, predictions)
+}
+
+// ComputeMetrics 计算多个已注册指标，无法计算的指标（如样本只有一个类别时的roc_auc）不记录
+func ComputeMetrics(names []string, yTrue, predictions []float64) map[string]float64 {
+	metrics := make(map[string]float64, len(names))
+	for _, name := range names {
+		if value, err := ComputeMetric(name, yTrue, predictions); err == nil {
+			metrics[name] = value
+		}
+	}
+	return metrics
+}
+
+// onConfusionMatrix 由混淆矩阵计算的分类指标
+func onConfusionMatrix(metric func(m *ConfusionMatrix) (float64, error)) func(yTrue, yPred []float64) (float64, error) {
+	return func(yTrue, yPred []float64) (float64, error) {
+		m, err := NewConfusionMatrix(yTrue, yPred)
+		if err != nil {
+			return 0, err
+		}
+		return metric(m)
+	}
+}
+
+// 内置指标，名称与训练结果Metrics中的键相同
+func init() {
+	builtin := map[string]Scorer{
+		"r2":        NewScorer(R2Score, true),
+		"mse":       NewScorer(MSE, false),
+		"rmse":      NewScorer(RMSE, false),
+		"mae":       NewScorer(MAE, false),
+		"mape":      NewScorer(MAPE, false),
+		"smape":     NewScorer(SMAPE, false),
+		"median_ae": NewScorer(MedianAE, false),
+
+		"accuracy":          NewClassificationScorer(onConfusionMatrix(func(m *ConfusionMatrix) (float64, error) { return m.Accuracy(), nil }), true, false),
+		"balanced_accuracy": NewClassificationScorer(onConfusionMatrix(func(m *ConfusionMatrix) (float64, error) { return m.BalancedAccuracy(), nil }), true, false),
+		"precision":         NewClassificationScorer(onConfusionMatrix(func(m *ConfusionMatrix) (float64, error) { return m.Precision(AverageBinary) }), true, false),
+		"recall":            NewClassificationScorer(onConfusionMatrix(func(m *ConfusionMatrix) (float64, error) { return m.Recall(AverageBinary) }), true, false),
+		"f1":                NewClassificationScorer(onConfusionMatrix(func(m *ConfusionMatrix) (float64, error) { return m.F1(AverageBinary) }), true, false),
+		"specificity":       NewClassificationScorer(onConfusionMatrix(func(m *ConfusionMatrix) (float64, error) { return m.Specificity(AverageBinary) }), true, false),
+		"mcc":               NewClassificationScorer(onConfusionMatrix(func(m *ConfusionMatrix) (float64, error) { return m.MCC(), nil }), true, false),
+		"cohen_kappa":       NewClassificationScorer(onConfusionMatrix(func(m *ConfusionMatrix) (float64, error) { return m.CohenKappa(), nil }), true, false),
+
+		"roc_auc":           NewClassificationScorer(ROCAUC, true, true),
+		"average_precision": NewClassificationScorer(AveragePrecision, true, true),
+		"logloss":           NewClassificationScorer(func(yTrue, yPred []float64) (float64, error) { return LogLoss(yTrue, yPred, nil) }, false, true),
+		"brier_score":       NewClassificationScorer(func(yTrue, yPred []float64) (float64, error) { return BrierScore(yTrue, yPred, nil) }, false, true),
+	}
+	for name, scorer := range builtin {
+		scorers[name] = scorer
+	}
+}
@@ -0,0 +1,98 @@
+package evaluation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"gonum.org/v1/gonum/mat"
+)
+
+// 交叉验证和参数搜索的评分方式，得分总是越大越好（误差类指标取负值）
+const (
+	ScoringDefault          = ""                  // 模型的Score方法：回归为R²，分类为准确率
+	ScoringNegLogLoss       = "neg_log_loss"      // 负对数损失，用于输出概率的分类模型，设置了类别权重时按类别加权
+	ScoringNegBrierScore    = "neg_brier_score"   // 负Brier分数，用于输出正类概率的二分类模型，衡量概率是否校准
+	ScoringR2               = "r2"                // 决定系数
+	ScoringNegMSE           = "neg_mse"           // 负均方误差
+	ScoringNegRMSE          = "neg_rmse"          // 负均方根误差
+	ScoringNegMAE           = "neg_mae"           // 负平均绝对误差
+	ScoringNegMAPE          = "neg_mape"          // 负平均绝对百分比误差，忽略真实值为0的样本
+	ScoringNegMedianAE      = "neg_median_ae"     // 负绝对误差中位数
+	ScoringAccuracy         = "accuracy"          // 准确率，预测的正类概率按0.5的阈值转换为类别
+	ScoringBalancedAccuracy = "balanced_accuracy" // 平衡准确率，即各类别召回率的均值
+	ScoringPrecision        = "precision"         // 正类（标签1）的精确率
+	ScoringRecall           = "recall"            // 正类（标签1）的召回率
+	ScoringF1               = "f1"                // 正类（标签1）的F1
+	ScoringROCAUC           = "roc_auc"           // ROC曲线下面积，用于输出正类概率的二分类模型
+	ScoringAveragePrecision = "average_precision" // 平均精确率（PR曲线下面积），用于输出正类概率的二分类模型
+)
+
+// ScoreFunc 由真实值和模型的预测值计算得分，得分越大越好。分类模型的预测值为正类概率，
+// 需要类别时按0.5的阈值转换
+type ScoreFunc func(yTrue, yPred []float64) (float64, error)
+
+// ScoringFunc 返回评分方式name对应的评分函数：越大越好的已注册指标（见RegisterScorer）以其名称作为评分方式，
+// 误差类指标以"neg_"加名称作为评分方式并取负值（ScoringNegLogLoss对应logloss）。
+// ScoringDefault依赖模型的Score方法，没有对应的函数；ScoringNegLogLoss的函数不按类别权重加权
+func ScoringFunc(name string) (ScoreFunc, error) {
+	metric, negated := name, false
+	if name == ScoringNegLogLoss {
+		metric, negated = "logloss", true
+	} else if strings.HasPrefix(name, "neg_") {
+		metric, negated = strings.TrimPrefix(name, "neg_"), true
+	}
+	scorer, err := GetScorer(metric)
+	if err != nil || scorer.GreaterIsBetter() == negated {
+		return nil, fmt.Errorf("不支持的评分方式: %s", name)
+	}
+	return func(yTrue, yPred []float64) (float64, error) {
+		value, err := ApplyScorer(scorer, yTrue, yPred)
+		if negated {
+			value = -value
+		}
+		return value, err
+	}, nil
+}
+
+// ScoreModel 按scoring计算已训练模型在数据上的得分，得分越大越好
+func ScoreModel(model models.Model, X *mat.Dense, y *mat.VecDense, scoring string) (float64, error) {
+	switch scoring {
+	case ScoringDefault:
+		return model.Score(X, y), nil
+	case ScoringNegLogLoss:
+		labels := mat.Col(nil, 0, y)
+		loss, err := LogLoss(labels, mat.Col(nil, 0, model.Predict(X)), ClassSampleWeights(model.GetParameters(), labels))
+		if err != nil {
+			return 0, err
+		}
+		return -loss, nil
+	}
+
+	score, err := ScoringFunc(scoring)
+	if err != nil {
+		return 0, err
+	}
+	return ScoreModelWith(model, X, y, score)
+}
+
+// ScoreModelWith 用评分函数计算已训练模型在数据上的得分
+func ScoreModelWith(model models.Model, X *mat.Dense, y *mat.VecDense, score ScoreFunc) (float64, error) {
+	return score(mat.Col(nil, 0, y), mat.Col(nil, 0, model.Predict(X)))
+}
+
+// ClassSampleWeights 按模型参数中的类别权重（class_weight）返回每个样本的权重，没有设置类别权重时返回nil
+func ClassSampleWeights(params map[string]interface{}, labels []float64) []float64 {
+	classWeights, ok := params["class_weight"].(map[float64]float64)
+	if !ok || len(classWeights) == 0 {
+		return nil
+	}
+	weights := make([]float64, len(labels))
+	for i, label := range labels {
+		weights[i] = 1
+		if w, ok := classWeights[label]; ok {
+			weights[i] = w
+		}
+	}
+	return weights
+}
@@ -0,0 +1,308 @@
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Fold 交叉验证中一折的训练集和验证集样本索引
+type Fold struct {
+	Train []int
+	Test  []int
+}
+
+// TimeSeriesSplit 时间序列交叉验证划分器，样本按时间顺序排列且不打乱：
+// 第k折的验证集是紧随训练集之后的TestSize个样本，训练集只包含更早的样本，避免未来数据泄露到训练中
+type TimeSeriesSplit struct {
+	NSplits      int // 折数
+	TestSize     int // 每折验证集的样本数，为0时为 样本数/(NSplits+1)
+	Gap          int // 训练集末尾与验证集开头之间跳过的样本数，用于隔离滞后特征等造成的泄露
+	MaxTrainSize int // 训练集的最大样本数，大于0时为滚动窗口，否则为扩展窗口
+}
+
+// NewTimeSeriesSplit 创建扩展窗口、无间隔的时间序列划分器
+func NewTimeSeriesSplit(nSplits int) *TimeSeriesSplit {
+	return &TimeSeriesSplit{NSplits: nSplits}
+}
+
+// Split 按时间顺序划分nSamples个样本
+func (s *TimeSeriesSplit) Split(nSamples int) ([]Fold, error) {
+	if s.NSplits < 1 {
+		return nil, errors.New("折数必须大于0")
+	}
+	if s.Gap < 0 || s.TestSize < 0 || s.MaxTrainSize < 0 {
+		return nil, errors.New("TestSize、Gap和MaxTrainSize不能为负数")
+	}
+	testSize := s.TestSize
+	if testSize == 0 {
+		testSize = nSamples / (s.NSplits + 1)
+	}
+	if testSize == 0 {
+		return nil, fmt.Errorf("样本数 %d 不足以划分 %d 折", nSamples, s.NSplits)
+	}
+	firstTest := nSamples - s.NSplits*testSize
+	if firstTest-s.Gap <= 0 {
+		return nil, fmt.Errorf("样本数 %d 不足以划分 %d 折（每折验证集 %d 个样本，间隔 %d）", nSamples, s.NSplits, testSize, s.Gap)
+	}
+
+	folds := make([]Fold, s.NSplits)
+	for k := range folds {
+		testStart := firstTest + k*testSize
+		trainEnd := testStart - s.Gap
+		trainStart := 0
+		if s.MaxTrainSize > 0 && trainEnd > s.MaxTrainSize {
+			trainStart = trainEnd - s.MaxTrainSize
+		}
+		folds[k] = Fold{Train: indexRange(trainStart, trainEnd), Test: indexRange(testStart, testStart+testSize)}
+	}
+	return folds, nil
+}
+
+// indexRange 返回[start, end)区间的索引
+func indexRange(start, end int) []int {
+	indices := make([]int, end-start)
+	for i := range indices {
+		indices[i] = start + i
+	}
+	return indices
+}
+
+// KFold 随机K折划分器：打乱样本后按顺序切分为NSplits折，各折大小最多相差1
+type KFold struct {
+	NSplits    int
+	RandomSeed int64
+}
+
+// NewKFold 创建随机K折划分器
+func NewKFold(nSplits int, randomSeed int64) *KFold {
+	return &KFold{NSplits: nSplits, RandomSeed: randomSeed}
+}
+
+// Split 划分nSamples个样本，训练集索引保持打乱后的顺序
+func (s *KFold) Split(nSamples int) ([]Fold, error) {
+	if s.NSplits <= 1 {
+		return nil, errors.New("折数必须大于1")
+	}
+	if s.NSplits > nSamples {
+		return nil, errors.New("折数不能大于样本数量")
+	}
+
+	// 使用独立的随机源打乱索引，保证相同种子结果可复现
+	indices := rand.New(rand.NewSource(s.RandomSeed)).Perm(nSamples)
+
+	foldSize := nSamples / s.NSplits
+	extraSamples := nSamples % s.NSplits
+
+	folds := make([]Fold, s.NSplits)
+	start := 0
+	for fold := 0; fold < s.NSplits; fold++ {
+		size := foldSize
+		if fold < extraSamples {
+			size++
+		}
+
+		trainIndices := make([]int, 0, nSamples-size)
+		trainIndices = append(trainIndices, indices[:start]...)
+		trainIndices = append(trainIndices, indices[start+size:]...)
+		folds[fold] = Fold{Train: trainIndices, Test: indices[start : start+size]}
+		start += size
+	}
+	return folds, nil
+}
+
+// RepeatedKFold 重复K折划分器：以不同的随机打乱重复NRepeats次K折划分，共得到NSplits×NRepeats折，
+// 第r次重复的各折位于结果的 [r×NSplits, (r+1)×NSplits) 区间。在小数据集上平均更多的折可以降低估计的方差
+type RepeatedKFold struct {
+	NSplits    int
+	NRepeats   int
+	RandomSeed int64
+	Stratify   bool // 每次重复都按类别分层划分
+}
+
+// NewRepeatedKFold 创建重复K折划分器
+func NewRepeatedKFold(nSplits, nRepeats int, randomSeed int64) *RepeatedKFold {
+	return &RepeatedKFold{NSplits: nSplits, NRepeats: nRepeats, RandomSeed: randomSeed}
+}
+
+// Split 按每个样本的目标值划分，只有Stratify为true时才使用标签的取值
+func (s *RepeatedKFold) Split(labels []float64) ([]Fold, error) {
+	if s.NRepeats < 1 {
+		return nil, errors.New("重复次数必须为正数")
+	}
+
+	// 每次重复的种子由RandomSeed依次生成
+	rng := rand.New(rand.NewSource(s.RandomSeed))
+	folds := make([]Fold, 0, s.NSplits*s.NRepeats)
+	for r := 0; r < s.NRepeats; r++ {
+		seed := rng.Int63()
+		var repeat []Fold
+		var err error
+		if s.Stratify {
+			repeat, err = NewStratifiedKFold(s.NSplits, seed).Split(labels)
+		} else {
+			repeat, err = NewKFold(s.NSplits, seed).Split(len(labels))
+		}
+		if err != nil {
+			return nil, err
+		}
+		folds = append(folds, repeat...)
+	}
+	return folds, nil
+}
+
+// StratifiedKFold 分层K折划分器：打乱后每个类别的样本轮流分配到各折，使每折的类别比例与整体一致，
+// 各折大小最多相差1。少数类样本少于折数时部分折的验证集中没有该类别
+type StratifiedKFold struct {
+	NSplits    int
+	RandomSeed int64
+}
+
+// NewStratifiedKFold 创建分层K折划分器
+func NewStratifiedKFold(nSplits int, randomSeed int64) *StratifiedKFold {
+	return &StratifiedKFold{NSplits: nSplits, RandomSeed: randomSeed}
+}
+
+// Split 按每个样本的类别标签划分。训练集索引保持打乱后的顺序，取其前若干个仍近似分层
+func (s *StratifiedKFold) Split(labels []float64) ([]Fold, error) {
+	if s.NSplits < 2 {
+		return nil, errors.New("折数必须大于1")
+	}
+	if s.NSplits > len(labels) {
+		return nil, errors.New("折数不能大于样本数量")
+	}
+
+	// 按类别值排序后依次处理，保证相同种子结果可复现
+	members := make(map[float64][]int)
+	for i, label := range labels {
+		members[label] = append(members[label], i)
+	}
+	classes := make([]float64, 0, len(members))
+	for class := range members {
+		classes = append(classes, class)
+	}
+	sort.Float64s(classes)
+
+	rng := rand.New(rand.NewSource(s.RandomSeed))
+	order := make([]int, 0, len(labels))
+	for _, class := range classes {
+		indices := members[class]
+		rng.Shuffle(len(indices), func(a, b int) { indices[a], indices[b] = indices[b], indices[a] })
+		order = append(order, indices...)
+	}
+	// 各类别首尾相接后按位置轮流分配，每个类别在各折中的数量最多相差1
+	foldOf := make([]int, len(labels))
+	for position, i := range order {
+		foldOf[i] = position % s.NSplits
+	}
+	// 训练集按打乱后的顺序排列，避免同一类别的样本连续出现
+	rng.Shuffle(len(order), func(a, b int) { order[a], order[b] = order[b], order[a] })
+
+	folds := make([]Fold, s.NSplits)
+	for _, i := range order {
+		for f := range folds {
+			if f == foldOf[i] {
+				folds[f].Test = append(folds[f].Test, i)
+			} else {
+				folds[f].Train = append(folds[f].Train, i)
+			}
+		}
+	}
+	return folds, nil
+}
+
+// GroupKFold 按分组的K折划分器：同一组的样本（如同一受试者的多次测量）总在同一折中，
+// 不会同时出现在训练集和验证集里。各组按样本数从多到少依次分配给当前样本最少的折，使各折大小尽量均衡
+type GroupKFold struct {
+	NSplits int
+}
+
+// NewGroupKFold 创建按分组的K折划分器
+func NewGroupKFold(nSplits int) *GroupKFold {
+	return &GroupKFold{NSplits: nSplits}
+}
+
+// Split 按每个样本的组标签划分
+func (s *GroupKFold) Split(groups []string) ([]Fold, error) {
+	if s.NSplits < 2 {
+		return nil, errors.New("折数必须大于1")
+	}
+	names, members := groupMembers(groups)
+	if len(names) < s.NSplits {
+		return nil, fmt.Errorf("组数 %d 少于折数 %d", len(names), s.NSplits)
+	}
+
+	// 按组大小降序（大小相同时按首次出现的顺序）分配
+	order := make([]int, len(names))
+	for g := range order {
+		order[g] = g
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(members[names[order[a]]]) > len(members[names[order[b]]])
+	})
+	assignment := make([]int, len(names))
+	sizes := make([]int, s.NSplits)
+	for _, g := range order {
+		smallest := 0
+		for k := range sizes {
+			if sizes[k] < sizes[smallest] {
+				smallest = k
+			}
+		}
+		assignment[g] = smallest
+		sizes[smallest] += len(members[names[g]])
+	}
+
+	foldOf := make(map[string]int, len(names))
+	for g, name := range names {
+		foldOf[name] = assignment[g]
+	}
+	folds := make([]Fold, s.NSplits)
+	for i, group := range groups {
+		k := foldOf[group]
+		for f := range folds {
+			if f == k {
+				folds[f].Test = append(folds[f].Test, i)
+			} else {
+				folds[f].Train = append(folds[f].Train, i)
+			}
+		}
+	}
+	return folds, nil
+}
+
+// LeaveOneGroupOut 留一组交叉验证划分器：每折以一个组的全部样本为验证集，其余组为训练集，折数等于组数
+type LeaveOneGroupOut struct{}
+
+// Split 按每个样本的组标签划分，各折按组首次出现的顺序排列
+func (LeaveOneGroupOut) Split(groups []string) ([]Fold, error) {
+	names, members := groupMembers(groups)
+	if len(names) < 2 {
+		return nil, errors.New("留一组交叉验证至少需要2个组")
+	}
+
+	folds := make([]Fold, len(names))
+	for k, name := range names {
+		folds[k].Test = members[name]
+		for i, group := range groups {
+			if group != name {
+				folds[k].Train = append(folds[k].Train, i)
+			}
+		}
+	}
+	return folds, nil
+}
+
+// groupMembers 按首次出现的顺序返回组名及每组的样本索引
+func groupMembers(groups []string) ([]string, map[string][]int) {
+	var names []string
+	members := make(map[string][]int)
+	for i, group := range groups {
+		if _, ok := members[group]; !ok {
+			names = append(names, group)
+		}
+		members[group] = append(members[group], i)
+	}
+	return names, members
+}
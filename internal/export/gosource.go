@@ -0,0 +1,190 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+)
+
+// GoSource 生成只依赖标准库的Go源文件，包含模型的系数和预测函数，使其他服务无需引入本库即可预测。
+// 生成的包中NumFeatures为输入特征数，Predict(x []float64) float64返回单个样本的预测值，
+// x的长度不等于NumFeatures时panic；Logistic的Predict返回正类概率，另有PredictClass按0.5的阈值返回类别。
+// 支持OLS、Ridge、Lasso、RidgeCV、LassoCV、PLS、Logistic、Polynomial、Exponential、Logarithmic和Power，
+// 系数以完整的float64精度写出。PLS的系数合并为 XWeights·YLoadingsᵀ，预测值与原模型可能有舍入误差
+func GoSource(model models.Model, packageName string, w io.Writer) error {
+	if packageName == "" {
+		packageName = "model"
+	}
+	if !token.IsIdentifier(packageName) || packageName == "_" {
+		return fmt.Errorf("无效的包名: %q", packageName)
+	}
+
+	modelType := model.GetModelType()
+	params := model.GetParameters()
+	var body bytes.Buffer
+	var usesMath bool
+
+	switch modelType {
+	case "Polynomial":
+		coefficients, ok := params["coefficients"].([]float64)
+		if !ok || len(coefficients) == 0 {
+			return fmt.Errorf("%w: %s", ErrNotTrained, modelType)
+		}
+		if err := writeArray(&body, "coefficients", "多项式系数，第j个为x^j的系数", coefficients); err != nil {
+			return err
+		}
+		body.WriteString(`
+// Predict 返回单个样本的预测值
+func Predict(x []float64) float64 {
+	checkFeatures(x)
+	y := 0.0
+	for j, c := range coefficients {
+		y += c * math.Pow(x[0], float64(j))
+	}
+	return y
+}
+`)
+		return writeGoSource(w, packageName, modelType, 1, true, body.Bytes())
+	case "Exponential", "Logarithmic", "Power":
+		a, _ := params["a"].(float64)
+		b, _ := params["b"].(float64)
+		if err := writeConstants(&body, map[string]float64{"a": a, "b": b}, "a", "b"); err != nil {
+			return err
+		}
+		var expression, check string
+		switch modelType {
+		case "Exponential":
+			expression = "a * math.Exp(b*x[0])"
+		case "Logarithmic":
+			expression, check = "a*math.Log(x[0]) + b", "logarithmic regression requires positive x values for prediction"
+		case "Power":
+			expression, check = "a * math.Pow(x[0], b)", "power regression requires positive x values for prediction"
+		}
+		body.WriteString("\n// Predict 返回单个样本的预测值\nfunc Predict(x []float64) float64 {\n\tcheckFeatures(x)\n")
+		if check != "" {
+			fmt.Fprintf(&body, "\tif x[0] <= 0 {\n\t\tpanic(%q)\n\t}\n", check)
+		}
+		fmt.Fprintf(&body, "\treturn %s\n}\n", expression)
+		return writeGoSource(w, packageName, modelType, 1, true, body.Bytes())
+	}
+
+	form, err := Linear(model)
+	if err != nil {
+		return err
+	}
+	if err := writeConstants(&body, map[string]float64{"intercept": form.Intercept}, "intercept"); err != nil {
+		return err
+	}
+	if err := writeArray(&body, "coefficients", "各特征的系数", form.Coefficients); err != nil {
+		return err
+	}
+	body.WriteString(`
+// decision 返回线性决策函数 intercept + Σ coefficients[j]·x[j]
+func decision(x []float64) float64 {
+	checkFeatures(x)
+	z := intercept
+	for j, c := range coefficients {
+		z += c * x[j]
+	}
+	return z
+}
+`)
+	if form.Classifier {
+		usesMath = true
+		body.WriteString(`
+// Predict 返回单个样本属于类别1的概率
+func Predict(x []float64) float64 {
+	return 1 / (1 + math.Exp(-decision(x)))
+}
+
+// PredictClass 返回单个样本的类别，概率不小于0.5时为1，否则为0
+func PredictClass(x []float64) int {
+	if Predict(x) >= 0.5 {
+		return 1
+	}
+	return 0
+}
+`)
+	} else {
+		body.WriteString(`
+// Predict 返回单个样本的预测值
+func Predict(x []float64) float64 {
+	return decision(x)
+}
+`)
+	}
+	return writeGoSource(w, packageName, modelType, form.NumFeatures(), usesMath, body.Bytes())
+}
+
+// writeGoSource 写出文件头、NumFeatures和特征数检查，并用gofmt格式化整个文件
+func writeGoSource(w io.Writer, packageName, modelType string, numFeatures int, usesMath bool, body []byte) error {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by %s from a trained %s model. DO NOT EDIT.\n\n", producer, modelType)
+	fmt.Fprintf(&src, "// Package %s 包含训练好的%s模型的系数和预测函数，只依赖标准库\npackage %s\n\n", packageName, modelType, packageName)
+	if usesMath {
+		src.WriteString("import \"math\"\n\n")
+	}
+	fmt.Fprintf(&src, "// NumFeatures 模型的输入特征数\nconst NumFeatures = %d\n\n", numFeatures)
+	src.Write(body)
+	fmt.Fprintf(&src, `
+// checkFeatures 检查样本的特征数
+func checkFeatures(x []float64) {
+	if len(x) != NumFeatures {
+		panic(%q)
+	}
+}
+`, fmt.Sprintf("%s: feature count must be %d", packageName, numFeatures))
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("生成的源代码无效: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// writeConstants 按names的顺序写出float64常量
+func writeConstants(buf *bytes.Buffer, values map[string]float64, names ...string) error {
+	buf.WriteString("// 模型参数\nconst (\n")
+	for _, name := range names {
+		literal, err := floatLiteral(values[name])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "\t%s = %s\n", name, literal)
+	}
+	buf.WriteString(")\n")
+	return nil
+}
+
+// writeArray 写出float64数组变量
+func writeArray(buf *bytes.Buffer, name, doc string, values []float64) error {
+	fmt.Fprintf(buf, "\n// %s %s\nvar %s = [...]float64{\n", name, doc, name)
+	for _, v := range values {
+		literal, err := floatLiteral(v)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "\t%s,\n", literal)
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+// floatLiteral 返回能精确还原v的Go浮点字面量，NaN和Inf无法写成常量
+func floatLiteral(v float64) (string, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "", fmt.Errorf("模型参数不是有限值: %v", v)
+	}
+	literal := formatFloat(v)
+	if _, err := strconv.Atoi(literal); err == nil {
+		literal += ".0"
+	}
+	return literal, nil
+}
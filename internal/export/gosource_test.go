@@ -0,0 +1,158 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"gonum.org/v1/gonum/mat"
+)
+
+// generatedCase 一个导出为Go源码的模型及用于比较的样本
+type generatedCase struct {
+	pkg   string
+	model models.Model
+	X     *mat.Dense
+}
+
+// curveModel 在单特征、x为正的数据上训练指定类型的非线性模型
+func curveModel(t *testing.T, modelType string, params map[string]interface{}, f func(x float64) float64) (models.Model, *mat.Dense) {
+	t.Helper()
+	n := 25
+	X := mat.NewDense(n, 1, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x := 0.4 + float64(i)/8
+		X.Set(i, 0, x)
+		y.SetVec(i, f(x)*(1+0.01*math.Sin(float64(i))))
+	}
+	model, err := models.NewModelManager().CreateModel(&models.ModelConfig{ModelType: modelType, Parameters: params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := model.Fit(X, y); err != nil {
+		t.Fatalf("%s: %v", modelType, err)
+	}
+	return model, X
+}
+
+func TestGoSourceCompilesAndPredicts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles generated code with the go tool")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	ols, logistic, X := fittedModels(t)
+	polynomial, curveX := curveModel(t, "polynomial", map[string]interface{}{"degree": 3}, func(x float64) float64 { return 1 - x + 0.2*x*x*x })
+	power, _ := curveModel(t, "power", nil, func(x float64) float64 { return 2 * math.Pow(x, 1.3) })
+	logarithmic, _ := curveModel(t, "logarithmic", nil, func(x float64) float64 { return 1 + 2*math.Log(x) })
+	exponential, _ := curveModel(t, "exponential", nil, func(x float64) float64 { return 0.5 * math.Exp(0.4*x) })
+	cases := []generatedCase{
+		{"olsmodel", ols, X},
+		{"logisticmodel", logistic, X},
+		{"polymodel", polynomial, curveX},
+		{"powermodel", power, curveX},
+		{"logmodel", logarithmic, curveX},
+		{"expmodel", exponential, curveX},
+	}
+
+	// 每个模型生成一个包，main逐行输出"包名 行号 预测值"，浮点数以可精确还原的形式输出
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("go.mod", "module generated\n\ngo 1.21\n")
+	var main bytes.Buffer
+	main.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"strconv\"\n\n")
+	for _, c := range cases {
+		fmt.Fprintf(&main, "\t%q\n", "generated/"+c.pkg)
+	}
+	main.WriteString(")\n\nfunc show(name string, i int, v float64) {\n\tfmt.Println(name, i, strconv.FormatFloat(v, 'g', -1, 64))\n}\n\nfunc main() {\n")
+	for _, c := range cases {
+		var src bytes.Buffer
+		if err := GoSource(c.model, c.pkg, &src); err != nil {
+			t.Fatalf("%s: %v", c.pkg, err)
+		}
+		if !strings.HasPrefix(src.String(), "// Code generated by "+producer) {
+			t.Errorf("%s: missing the generated-code header", c.pkg)
+		}
+		writeFile(filepath.Join(c.pkg, c.pkg+".go"), src.String())
+
+		rows, cols := c.X.Dims()
+		fmt.Fprintf(&main, "\tif %s.NumFeatures != %d {\n\t\tpanic(\"NumFeatures\")\n\t}\n", c.pkg, cols)
+		for i := 0; i < rows; i++ {
+			row := make([]string, cols)
+			for j := range row {
+				row[j] = strconv.FormatFloat(c.X.At(i, j), 'g', -1, 64)
+			}
+			fmt.Fprintf(&main, "\tshow(%q, %d, %s.Predict([]float64{%s}))\n", c.pkg, i, c.pkg, strings.Join(row, ", "))
+		}
+	}
+	main.WriteString("\tshow(\"class\", 0, float64(logisticmodel.PredictClass([]float64{3, -3, 0})))\n")
+	main.WriteString("\tshow(\"class\", 1, float64(logisticmodel.PredictClass([]float64{-3, 3, 0})))\n}\n")
+	writeFile("main.go", main.String())
+
+	cmd := exec.Command(goTool, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, output)
+	}
+
+	got := make(map[string]float64)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		value, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			t.Fatalf("unexpected output line %q", line)
+		}
+		got[fields[0]+" "+fields[1]] = value
+	}
+	for _, c := range cases {
+		want := c.model.Predict(c.X)
+		for i := 0; i < want.Len(); i++ {
+			v, ok := got[fmt.Sprintf("%s %d", c.pkg, i)]
+			if !ok || math.Abs(v-want.AtVec(i)) > 1e-12*math.Max(1, math.Abs(want.AtVec(i))) {
+				t.Errorf("%s row %d: generated code predicts %v, model %g", c.pkg, i, v, want.AtVec(i))
+			}
+		}
+	}
+	if got["class 0"] != 1 || got["class 1"] != 0 {
+		t.Errorf("PredictClass = %v, %v, want 1, 0", got["class 0"], got["class 1"])
+	}
+}
+
+func TestGoSourceInvalidInput(t *testing.T) {
+	ols, _, _ := fittedModels(t)
+	for _, name := range []string{"_", "1model", "my-model", "func"} {
+		if err := GoSource(ols, name, &bytes.Buffer{}); err == nil {
+			t.Errorf("package name %q: expected an error", name)
+		}
+	}
+
+	var src bytes.Buffer
+	if err := GoSource(ols, "", &src); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(src.String(), "\npackage model\n") {
+		t.Error("empty package name should default to model")
+	}
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+)
+
+// producer 写入导出文件的生成工具名称
+const producer = "Go-Model"
+
+// 导出失败的原因，可用errors.Is判断
+var (
+	ErrUnsupportedModel = errors.New("不支持导出的模型类型")
+	ErrNotTrained       = errors.New("模型尚未训练")
+)
+
+// LinearForm 训练好的线性模型的决策函数 intercept + Σ coefficients[j]·x[j]。
+// 分类模型（Logistic）的正类概率为决策函数的sigmoid
+type LinearForm struct {
+	ModelType    string
+	Coefficients []float64
+	Intercept    float64
+	Classifier   bool
+}
+
+// NumFeatures 返回模型的输入特征数
+func (f *LinearForm) NumFeatures() int {
+	return len(f.Coefficients)
+}
+
+// Linear 从模型参数中提取线性决策函数，支持OLS、Ridge、Lasso、RidgeCV、LassoCV、Logistic和PLS；
+// PLS的系数为 XWeights·YLoadingsᵀ，没有截距
+func Linear(model models.Model) (*LinearForm, error) {
+	modelType := model.GetModelType()
+	params := model.GetParameters()
+	form := &LinearForm{ModelType: modelType}
+
+	switch modelType {
+	case "OLS", "Ridge", "Lasso", "RidgeCV", "LassoCV", "Logistic":
+		coefficients, ok := params["coefficients"].([]float64)
+		if !ok || len(coefficients) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotTrained, modelType)
+		}
+		intercept, _ := params["intercept"].(float64)
+		form.Coefficients = append([]float64(nil), coefficients...)
+		form.Intercept = intercept
+		form.Classifier = modelType == "Logistic"
+	case "PLS":
+		weights, ok := params["x_weights"].([][]float64)
+		loadings, ok2 := params["y_loadings"].([][]float64)
+		if !ok || !ok2 || len(weights) == 0 || len(loadings) != 1 {
+			return nil, fmt.Errorf("%w: %s", ErrNotTrained, modelType)
+		}
+		form.Coefficients = make([]float64, len(weights))
+		for j, row := range weights {
+			if len(row) != len(loadings[0]) {
+				return nil, fmt.Errorf("PLS权重矩阵与载荷的成分数不一致")
+			}
+			for k, w := range row {
+				form.Coefficients[j] += w * loadings[0][k]
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedModel, modelType)
+	}
+	return form, nil
+}
@@ -0,0 +1,206 @@
+package export
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+)
+
+// ONNX格式的版本：IR版本7对应ONNX 1.8，ai.onnx.ml的LinearRegressor和LinearClassifier自opset 1起可用
+const (
+	onnxIRVersion    = 7
+	onnxOpsetVersion = 13
+	onnxMLDomain     = "ai.onnx.ml"
+	onnxMLOpset      = 1
+)
+
+// ONNX张量元素类型（TensorProto.DataType）和属性类型（AttributeProto.AttributeType）
+const (
+	onnxFloat = 1
+	onnxInt64 = 7
+
+	attributeInt    = 2
+	attributeString = 3
+	attributeFloats = 6
+	attributeInts   = 7
+)
+
+// ONNX 将线性模型导出为ONNX模型写入w。回归模型（OLS、Ridge、Lasso、RidgeCV、LassoCV、PLS）导出为
+// LinearRegressor，输入为float张量X [N, C]，输出Y [N, 1]；Logistic导出为LinearClassifier，
+// 输出int64类别label [N]和两列概率probabilities [N, 2]（依次为类别0和1）。
+// ONNX的系数为float32，预测值与Go模型相比有约1e-7的相对误差
+func ONNX(model models.Model, w io.Writer) error {
+	form, err := Linear(model)
+	if err != nil {
+		return err
+	}
+
+	batch := "N"
+	input := valueInfo("X", onnxFloat, batch, form.NumFeatures())
+	var node *protoBuffer
+	var outputs []*protoBuffer
+	if form.Classifier {
+		// 两个类别的得分分别为-z和z，经sigmoid后即为类别0和1的概率
+		coefficients := make([]float64, 0, 2*len(form.Coefficients))
+		for _, c := range form.Coefficients {
+			coefficients = append(coefficients, -c)
+		}
+		coefficients = append(coefficients, form.Coefficients...)
+		node = nodeProto("LinearClassifier", []string{"X"}, []string{"label", "probabilities"},
+			intsAttribute("classlabels_ints", []int64{0, 1}),
+			floatsAttribute("coefficients", coefficients),
+			floatsAttribute("intercepts", []float64{-form.Intercept, form.Intercept}),
+			intAttribute("multi_class", 0),
+			stringAttribute("post_transform", "LOGISTIC"),
+		)
+		outputs = []*protoBuffer{valueInfo("label", onnxInt64, batch), valueInfo("probabilities", onnxFloat, batch, 2)}
+	} else {
+		node = nodeProto("LinearRegressor", []string{"X"}, []string{"Y"},
+			floatsAttribute("coefficients", form.Coefficients),
+			floatsAttribute("intercepts", []float64{form.Intercept}),
+			stringAttribute("post_transform", "NONE"),
+			intAttribute("targets", 1),
+		)
+		outputs = []*protoBuffer{valueInfo("Y", onnxFloat, batch, 1)}
+	}
+
+	// GraphProto
+	graph := &protoBuffer{}
+	graph.message(1, node)
+	graph.string(2, form.ModelType)
+	graph.message(11, input)
+	for _, output := range outputs {
+		graph.message(12, output)
+	}
+
+	// ModelProto
+	modelProto := &protoBuffer{}
+	modelProto.varint(1, onnxIRVersion)
+	modelProto.string(2, producer)
+	modelProto.message(7, graph)
+	modelProto.message(8, opsetImport("", onnxOpsetVersion))
+	modelProto.message(8, opsetImport(onnxMLDomain, onnxMLOpset))
+
+	_, err = w.Write(modelProto.data)
+	return err
+}
+
+// nodeProto 构造ai.onnx.ml域中的节点
+func nodeProto(opType string, inputs, outputs []string, attributes ...*protoBuffer) *protoBuffer {
+	node := &protoBuffer{}
+	for _, name := range inputs {
+		node.string(1, name)
+	}
+	for _, name := range outputs {
+		node.string(2, name)
+	}
+	node.string(3, opType)
+	node.string(4, opType)
+	for _, attribute := range attributes {
+		node.message(5, attribute)
+	}
+	node.string(7, onnxMLDomain)
+	return node
+}
+
+// valueInfo 构造图的输入或输出，dims中的第一维为名为batch的可变维度
+func valueInfo(name string, elemType int, batch string, dims ...int) *protoBuffer {
+	shape := &protoBuffer{}
+	batchDim := &protoBuffer{}
+	batchDim.string(2, batch)
+	shape.message(1, batchDim)
+	for _, d := range dims {
+		dim := &protoBuffer{}
+		dim.varint(1, uint64(d))
+		shape.message(1, dim)
+	}
+	tensor := &protoBuffer{}
+	tensor.varint(1, uint64(elemType))
+	tensor.message(2, shape)
+	typeProto := &protoBuffer{}
+	typeProto.message(1, tensor)
+
+	info := &protoBuffer{}
+	info.string(1, name)
+	info.message(2, typeProto)
+	return info
+}
+
+func opsetImport(domain string, version int) *protoBuffer {
+	opset := &protoBuffer{}
+	if domain != "" {
+		opset.string(1, domain)
+	}
+	opset.varint(2, uint64(version))
+	return opset
+}
+
+func intAttribute(name string, value int64) *protoBuffer {
+	attribute := &protoBuffer{}
+	attribute.string(1, name)
+	attribute.varint(3, uint64(value))
+	attribute.varint(20, attributeInt)
+	return attribute
+}
+
+func stringAttribute(name, value string) *protoBuffer {
+	attribute := &protoBuffer{}
+	attribute.string(1, name)
+	attribute.string(4, value)
+	attribute.varint(20, attributeString)
+	return attribute
+}
+
+func floatsAttribute(name string, values []float64) *protoBuffer {
+	attribute := &protoBuffer{}
+	attribute.string(1, name)
+	packed := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(packed[4*i:], math.Float32bits(float32(v)))
+	}
+	attribute.bytes(7, packed)
+	attribute.varint(20, attributeFloats)
+	return attribute
+}
+
+func intsAttribute(name string, values []int64) *protoBuffer {
+	attribute := &protoBuffer{}
+	attribute.string(1, name)
+	packed := &protoBuffer{}
+	for _, v := range values {
+		packed.data = binary.AppendUvarint(packed.data, uint64(v))
+	}
+	attribute.bytes(8, packed.data)
+	attribute.varint(20, attributeInts)
+	return attribute
+}
+
+// protoBuffer 按protobuf线格式编码消息，只实现ONNX模型用到的varint和长度前缀两种字段
+type protoBuffer struct {
+	data []byte
+}
+
+func (b *protoBuffer) tag(field, wireType int) {
+	b.data = binary.AppendUvarint(b.data, uint64(field<<3|wireType))
+}
+
+func (b *protoBuffer) varint(field int, value uint64) {
+	b.tag(field, 0)
+	b.data = binary.AppendUvarint(b.data, value)
+}
+
+func (b *protoBuffer) bytes(field int, value []byte) {
+	b.tag(field, 2)
+	b.data = binary.AppendUvarint(b.data, uint64(len(value)))
+	b.data = append(b.data, value...)
+}
+
+func (b *protoBuffer) string(field int, value string) {
+	b.bytes(field, []byte(value))
+}
+
+func (b *protoBuffer) message(field int, message *protoBuffer) {
+	b.bytes(field, message.data)
+}
@@ -0,0 +1,304 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"github.com/feiyuluoye/Go-Model/internal/models/linear"
+	"gonum.org/v1/gonum/mat"
+)
+
+// exportData 生成三个特征的数据，y为线性目标，labels为由同一线性边界加噪声得到的0/1标签
+func exportData(n int) (*mat.Dense, *mat.VecDense, *mat.VecDense) {
+	rng := rand.New(rand.NewSource(1))
+	X := mat.NewDense(n, 3, nil)
+	y := mat.NewVecDense(n, nil)
+	labels := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		a, b, c := rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()
+		X.SetRow(i, []float64{a, b, c})
+		y.SetVec(i, 1.5+2*a-b+0.5*c+0.1*rng.NormFloat64())
+		if 0.3+a-b+rng.NormFloat64() > 0 {
+			labels.SetVec(i, 1)
+		}
+	}
+	return X, y, labels
+}
+
+// fittedModels 返回训练好的OLS和Logistic模型及训练特征
+func fittedModels(t *testing.T) (models.Model, models.Model, *mat.Dense) {
+	t.Helper()
+	X, y, labels := exportData(80)
+	ols := linear.NewOLS()
+	if err := ols.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	logistic := linear.NewLogistic()
+	if err := logistic.Fit(X, labels); err != nil {
+		t.Fatal(err)
+	}
+	return ols, logistic, X
+}
+
+// protoField 解码得到的一个protobuf字段，varint字段的值在Varint中，长度前缀字段的内容在Bytes中
+type protoField struct {
+	Number int
+	Varint uint64
+	Bytes  []byte
+}
+
+// protoMessage 按字段号分组的已解码消息
+type protoMessage map[int][]protoField
+
+// decodeProto 独立于编码器按线格式解码消息，只接受varint和长度前缀两种类型
+func decodeProto(t *testing.T, data []byte) protoMessage {
+	t.Helper()
+	message := protoMessage{}
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			t.Fatalf("invalid field key")
+		}
+		data = data[n:]
+		field := protoField{Number: int(key >> 3)}
+		switch key & 7 {
+		case 0:
+			field.Varint, n = binary.Uvarint(data)
+			if n <= 0 {
+				t.Fatalf("field %d: invalid varint", field.Number)
+			}
+			data = data[n:]
+		case 2:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				t.Fatalf("field %d: invalid length", field.Number)
+			}
+			field.Bytes = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			t.Fatalf("field %d: unexpected wire type %d", field.Number, key&7)
+		}
+		message[field.Number] = append(message[field.Number], field)
+	}
+	return message
+}
+
+// strings 返回字段的所有字符串值
+func (m protoMessage) strings(number int) []string {
+	var values []string
+	for _, f := range m[number] {
+		values = append(values, string(f.Bytes))
+	}
+	return values
+}
+
+// onnxAttribute 解码后的NodeProto属性
+type onnxAttribute struct {
+	Type   uint64
+	Int    int64
+	String string
+	Floats []float32
+	Ints   []int64
+}
+
+// decodeAttributes 解码节点的全部属性，按名称索引
+func decodeAttributes(t *testing.T, node protoMessage) map[string]onnxAttribute {
+	t.Helper()
+	attributes := make(map[string]onnxAttribute)
+	for _, f := range node[5] {
+		a := decodeProto(t, f.Bytes)
+		attribute := onnxAttribute{Type: a[20][0].Varint}
+		if v, ok := a[3]; ok {
+			attribute.Int = int64(v[0].Varint)
+		}
+		if v, ok := a[4]; ok {
+			attribute.String = string(v[0].Bytes)
+		}
+		if v, ok := a[7]; ok {
+			packed := v[0].Bytes
+			for i := 0; i+4 <= len(packed); i += 4 {
+				attribute.Floats = append(attribute.Floats, math.Float32frombits(binary.LittleEndian.Uint32(packed[i:])))
+			}
+		}
+		if v, ok := a[8]; ok {
+			for packed := v[0].Bytes; len(packed) > 0; {
+				value, n := binary.Uvarint(packed)
+				attribute.Ints = append(attribute.Ints, int64(value))
+				packed = packed[n:]
+			}
+		}
+		attributes[a.strings(1)[0]] = attribute
+	}
+	return attributes
+}
+
+// valueShape 解码ValueInfoProto，返回名称、元素类型和各维度（可变维度为其名称）
+func valueShape(t *testing.T, data []byte) (string, uint64, []interface{}) {
+	t.Helper()
+	info := decodeProto(t, data)
+	typeProto := decodeProto(t, info[2][0].Bytes)
+	tensor := decodeProto(t, typeProto[1][0].Bytes)
+	shape := decodeProto(t, tensor[2][0].Bytes)
+	var dims []interface{}
+	for _, d := range shape[1] {
+		dim := decodeProto(t, d.Bytes)
+		if v, ok := dim[1]; ok {
+			dims = append(dims, int(v[0].Varint))
+		} else {
+			dims = append(dims, dim.strings(2)[0])
+		}
+	}
+	return info.strings(1)[0], tensor[1][0].Varint, dims
+}
+
+// decodeONNX 导出模型并解码出ModelProto、GraphProto和唯一的节点
+func decodeONNX(t *testing.T, model models.Model) (protoMessage, protoMessage, protoMessage) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := ONNX(model, &buf); err != nil {
+		t.Fatal(err)
+	}
+	modelProto := decodeProto(t, buf.Bytes())
+	if len(modelProto[7]) != 1 {
+		t.Fatalf("model has %d graphs, want 1", len(modelProto[7]))
+	}
+	graph := decodeProto(t, modelProto[7][0].Bytes)
+	if len(graph[1]) != 1 {
+		t.Fatalf("graph has %d nodes, want 1", len(graph[1]))
+	}
+	return modelProto, graph, decodeProto(t, graph[1][0].Bytes)
+}
+
+func TestONNXModelHeader(t *testing.T) {
+	ols, _, _ := fittedModels(t)
+	modelProto, graph, _ := decodeONNX(t, ols)
+
+	if modelProto[1][0].Varint != onnxIRVersion || modelProto.strings(2)[0] != producer {
+		t.Errorf("ir_version = %d, producer = %q", modelProto[1][0].Varint, modelProto.strings(2)[0])
+	}
+	opsets := make(map[string]uint64)
+	for _, f := range modelProto[8] {
+		opset := decodeProto(t, f.Bytes)
+		domain := ""
+		if d := opset.strings(1); len(d) > 0 {
+			domain = d[0]
+		}
+		opsets[domain] = opset[2][0].Varint
+	}
+	if len(opsets) != 2 || opsets[""] != onnxOpsetVersion || opsets[onnxMLDomain] != onnxMLOpset {
+		t.Errorf("opset imports = %v", opsets)
+	}
+	// 系数以节点属性给出，图中没有initializer（字段5）
+	if graph.strings(2)[0] != "OLS" || len(graph[5]) != 0 {
+		t.Errorf("graph name = %q with %d initializers, want OLS with none", graph.strings(2)[0], len(graph[5]))
+	}
+}
+
+func TestONNXLinearRegressor(t *testing.T) {
+	ols, _, X := fittedModels(t)
+	_, graph, node := decodeONNX(t, ols)
+
+	if op := node.strings(4)[0]; op != "LinearRegressor" || node.strings(7)[0] != onnxMLDomain {
+		t.Fatalf("op_type = %q, domain = %q", op, node.strings(7)[0])
+	}
+	if in, out := node.strings(1), node.strings(2); len(in) != 1 || in[0] != "X" || len(out) != 1 || out[0] != "Y" {
+		t.Errorf("node inputs = %v, outputs = %v", in, out)
+	}
+	name, elemType, dims := valueShape(t, graph[11][0].Bytes)
+	if name != "X" || elemType != onnxFloat || len(dims) != 2 || dims[0] != "N" || dims[1] != 3 {
+		t.Errorf("input = %s %d %v, want X float [N 3]", name, elemType, dims)
+	}
+	name, elemType, dims = valueShape(t, graph[12][0].Bytes)
+	if name != "Y" || elemType != onnxFloat || len(dims) != 2 || dims[1] != 1 {
+		t.Errorf("output = %s %d %v, want Y float [N 1]", name, elemType, dims)
+	}
+
+	attributes := decodeAttributes(t, node)
+	coefficients, intercepts := attributes["coefficients"], attributes["intercepts"]
+	if coefficients.Type != attributeFloats || len(coefficients.Floats) != 3 || len(intercepts.Floats) != 1 {
+		t.Fatalf("coefficients = %+v, intercepts = %+v", coefficients, intercepts)
+	}
+	if attributes["post_transform"].String != "NONE" || attributes["targets"].Int != 1 {
+		t.Errorf("post_transform = %q, targets = %d", attributes["post_transform"].String, attributes["targets"].Int)
+	}
+
+	// 按解码出的float32系数计算的预测值与Go模型一致
+	want := ols.Predict(X)
+	rows, cols := X.Dims()
+	for i := 0; i < rows; i++ {
+		got := float64(intercepts.Floats[0])
+		for j := 0; j < cols; j++ {
+			got += float64(coefficients.Floats[j]) * X.At(i, j)
+		}
+		if math.Abs(got-want.AtVec(i)) > 1e-5*math.Max(1, math.Abs(want.AtVec(i))) {
+			t.Fatalf("row %d: ONNX prediction %g, model %g", i, got, want.AtVec(i))
+		}
+	}
+}
+
+func TestONNXLinearClassifier(t *testing.T) {
+	_, logistic, X := fittedModels(t)
+	_, graph, node := decodeONNX(t, logistic)
+
+	if op := node.strings(4)[0]; op != "LinearClassifier" {
+		t.Fatalf("op_type = %q, want LinearClassifier", op)
+	}
+	if out := node.strings(2); len(out) != 2 || out[0] != "label" || out[1] != "probabilities" {
+		t.Errorf("node outputs = %v", out)
+	}
+	if len(graph[12]) != 2 {
+		t.Fatalf("graph has %d outputs, want 2", len(graph[12]))
+	}
+	if name, elemType, _ := valueShape(t, graph[12][0].Bytes); name != "label" || elemType != onnxInt64 {
+		t.Errorf("first output = %s of type %d, want label int64", name, elemType)
+	}
+	if name, elemType, dims := valueShape(t, graph[12][1].Bytes); name != "probabilities" || elemType != onnxFloat || dims[1] != 2 {
+		t.Errorf("second output = %s of type %d %v, want probabilities float [N 2]", name, elemType, dims)
+	}
+
+	attributes := decodeAttributes(t, node)
+	labels := attributes["classlabels_ints"].Ints
+	coefficients, intercepts := attributes["coefficients"].Floats, attributes["intercepts"].Floats
+	if len(labels) != 2 || labels[0] != 0 || labels[1] != 1 || len(coefficients) != 6 || len(intercepts) != 2 {
+		t.Fatalf("labels = %v, coefficients = %v, intercepts = %v", labels, coefficients, intercepts)
+	}
+	if attributes["post_transform"].String != "LOGISTIC" || attributes["multi_class"].Int != 0 {
+		t.Errorf("post_transform = %q, multi_class = %d", attributes["post_transform"].String, attributes["multi_class"].Int)
+	}
+
+	// 每个类别一行系数，LOGISTIC后处理对各类别得分分别取sigmoid
+	want := logistic.Predict(X)
+	rows, cols := X.Dims()
+	for i := 0; i < rows; i++ {
+		var probabilities [2]float64
+		for k := 0; k < 2; k++ {
+			score := float64(intercepts[k])
+			for j := 0; j < cols; j++ {
+				score += float64(coefficients[k*cols+j]) * X.At(i, j)
+			}
+			probabilities[k] = 1 / (1 + math.Exp(-score))
+		}
+		if math.Abs(probabilities[1]-want.AtVec(i)) > 1e-6 || math.Abs(probabilities[0]+probabilities[1]-1) > 1e-6 {
+			t.Fatalf("row %d: ONNX probabilities %v, model %g", i, probabilities, want.AtVec(i))
+		}
+	}
+}
+
+func TestONNXUnsupportedModels(t *testing.T) {
+	if err := ONNX(linear.NewOLS(), &bytes.Buffer{}); !errors.Is(err, ErrNotTrained) {
+		t.Errorf("untrained model: error = %v, want ErrNotTrained", err)
+	}
+	manager := models.NewModelManager()
+	model, err := manager.CreateModel(&models.ModelConfig{ModelType: "polynomial", Parameters: map[string]interface{}{"degree": 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ONNX(model, &bytes.Buffer{}); !errors.Is(err, ErrUnsupportedModel) {
+		t.Errorf("polynomial model: error = %v, want ErrUnsupportedModel", err)
+	}
+}
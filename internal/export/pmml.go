@@ -0,0 +1,322 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+)
+
+// PMMLNamespace PMML 4.4的XML命名空间
+const PMMLNamespace = "http://www.dmg.org/PMML-4_4"
+
+// PMML 将模型导出为PMML 4.4的RegressionModel写入w。featureNames为输入字段名，为空时依次为x1、x2…；
+// targetName为目标字段名，为空时为y。支持的模型：
+//   - OLS、Ridge、Lasso、RidgeCV、LassoCV和PLS：线性回归
+//   - Logistic：normalizationMethod为logit的二分类模型，类别为0和1，输出两个类别的概率
+//   - Polynomial：以幂次为exponent的NumericPredictor表示
+//   - Exponential、Logarithmic和Power：以exp(b*x)、ln(x)和pow(x, b)作为派生字段的线性回归
+func PMML(model models.Model, featureNames []string, targetName string, w io.Writer) error {
+	regression, err := pmmlRegression(model)
+	if err != nil {
+		return err
+	}
+	if len(featureNames) == 0 {
+		featureNames = make([]string, regression.numFeatures)
+		for j := range featureNames {
+			featureNames[j] = fmt.Sprintf("x%d", j+1)
+		}
+	}
+	if len(featureNames) != regression.numFeatures {
+		return fmt.Errorf("特征名数量 %d 与模型的特征数 %d 不一致", len(featureNames), regression.numFeatures)
+	}
+	if targetName == "" {
+		targetName = "y"
+	}
+	seen := map[string]bool{targetName: true}
+	for _, name := range featureNames {
+		if name == "" || seen[name] {
+			return fmt.Errorf("字段名不能为空或重复: %q", name)
+		}
+		seen[name] = true
+	}
+
+	doc := pmmlDocument{
+		Xmlns:   PMMLNamespace,
+		Version: "4.4",
+		Header:  pmmlHeader{Description: model.GetModelType(), Application: pmmlApplication{Name: producer}},
+	}
+	for _, name := range featureNames {
+		doc.DataDictionary.Fields = append(doc.DataDictionary.Fields, pmmlDataField{Name: name, OpType: "continuous", DataType: "double"})
+	}
+	target := pmmlDataField{Name: targetName, OpType: "continuous", DataType: "double"}
+	if regression.classifier {
+		target = pmmlDataField{Name: targetName, OpType: "categorical", DataType: "integer", Values: []pmmlValue{{Value: "0"}, {Value: "1"}}}
+	}
+	doc.DataDictionary.Fields = append(doc.DataDictionary.Fields, target)
+	doc.DataDictionary.NumberOfFields = len(doc.DataDictionary.Fields)
+
+	// 派生字段按名称引用输入字段，名称不能与输入字段重复
+	if len(regression.derived) > 0 {
+		doc.Transformations = &pmmlTransformations{}
+		for _, derived := range regression.derived {
+			field := derived(featureNames)
+			if seen[field.Name] {
+				return fmt.Errorf("字段名 %q 与派生字段重复", field.Name)
+			}
+			doc.Transformations.Fields = append(doc.Transformations.Fields, field)
+		}
+	}
+
+	m := &doc.Model
+	m.ModelName = model.GetModelType()
+	m.FunctionName = "regression"
+	m.NormalizationMethod = "none"
+	for _, name := range featureNames {
+		m.MiningSchema.Fields = append(m.MiningSchema.Fields, pmmlMiningField{Name: name})
+	}
+	m.MiningSchema.Fields = append(m.MiningSchema.Fields, pmmlMiningField{Name: targetName, UsageType: "target"})
+
+	table := pmmlRegressionTable{Intercept: formatFloat(regression.intercept)}
+	for _, term := range regression.terms {
+		name := term.name
+		if term.feature >= 0 {
+			name = featureNames[term.feature]
+		}
+		predictor := pmmlNumericPredictor{Name: name, Coefficient: formatFloat(term.coefficient)}
+		if term.exponent > 1 {
+			predictor.Exponent = strconv.Itoa(term.exponent)
+		}
+		table.Predictors = append(table.Predictors, predictor)
+	}
+
+	if regression.classifier {
+		// 二分类logit：第一个表给出类别1的概率，最后一个类别的概率为1减去其余类别之和
+		m.FunctionName = "classification"
+		m.NormalizationMethod = "logit"
+		table.TargetCategory = "1"
+		m.Tables = []pmmlRegressionTable{table, {Intercept: "0", TargetCategory: "0"}}
+		m.Output = &pmmlOutput{Fields: []pmmlOutputField{
+			{Name: "predicted_" + targetName, OpType: "categorical", DataType: "integer", Feature: "predictedValue"},
+			{Name: "probability_0", OpType: "continuous", DataType: "double", Feature: "probability", Value: "0"},
+			{Name: "probability_1", OpType: "continuous", DataType: "double", Feature: "probability", Value: "1"},
+		}}
+	} else {
+		m.Tables = []pmmlRegressionTable{table}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// pmmlTerm 回归表中的一项，feature为输入字段的下标，为-1时引用名为name的派生字段
+type pmmlTerm struct {
+	feature     int
+	name        string
+	exponent    int
+	coefficient float64
+}
+
+// pmmlModel 模型在PMML RegressionModel中的表示
+type pmmlModel struct {
+	numFeatures int
+	intercept   float64
+	terms       []pmmlTerm
+	derived     []func(featureNames []string) pmmlDerivedField
+	classifier  bool
+}
+
+// pmmlRegression 将模型转换为回归表
+func pmmlRegression(model models.Model) (*pmmlModel, error) {
+	modelType := model.GetModelType()
+	params := model.GetParameters()
+	a, _ := params["a"].(float64)
+	b, _ := params["b"].(float64)
+
+	switch modelType {
+	case "Polynomial":
+		coefficients, ok := params["coefficients"].([]float64)
+		if !ok || len(coefficients) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotTrained, modelType)
+		}
+		result := &pmmlModel{numFeatures: 1, intercept: coefficients[0]}
+		for j := 1; j < len(coefficients); j++ {
+			result.terms = append(result.terms, pmmlTerm{feature: 0, exponent: j, coefficient: coefficients[j]})
+		}
+		return result, nil
+	case "Exponential":
+		// y = a * exp(b*x)
+		return &pmmlModel{
+			numFeatures: 1,
+			terms:       []pmmlTerm{{feature: -1, name: "exp_b_x", coefficient: a}},
+			derived: []func([]string) pmmlDerivedField{func(names []string) pmmlDerivedField {
+				return derivedField("exp_b_x", apply("exp", apply("*", constant(b), pmmlFieldRef{Field: names[0]})))
+			}},
+		}, nil
+	case "Logarithmic":
+		// y = a * ln(x) + b
+		return &pmmlModel{
+			numFeatures: 1,
+			intercept:   b,
+			terms:       []pmmlTerm{{feature: -1, name: "ln_x", coefficient: a}},
+			derived: []func([]string) pmmlDerivedField{func(names []string) pmmlDerivedField {
+				return derivedField("ln_x", apply("ln", pmmlFieldRef{Field: names[0]}))
+			}},
+		}, nil
+	case "Power":
+		// y = a * x^b
+		return &pmmlModel{
+			numFeatures: 1,
+			terms:       []pmmlTerm{{feature: -1, name: "pow_x_b", coefficient: a}},
+			derived: []func([]string) pmmlDerivedField{func(names []string) pmmlDerivedField {
+				return derivedField("pow_x_b", apply("pow", pmmlFieldRef{Field: names[0]}, constant(b)))
+			}},
+		}, nil
+	}
+
+	form, err := Linear(model)
+	if err != nil {
+		return nil, err
+	}
+	result := &pmmlModel{numFeatures: form.NumFeatures(), intercept: form.Intercept, classifier: form.Classifier}
+	for j, c := range form.Coefficients {
+		result.terms = append(result.terms, pmmlTerm{feature: j, exponent: 1, coefficient: c})
+	}
+	return result, nil
+}
+
+func derivedField(name string, expression pmmlApply) pmmlDerivedField {
+	return pmmlDerivedField{Name: name, OpType: "continuous", DataType: "double", Apply: expression}
+}
+
+// apply 构造函数调用，args依次为pmmlApply、pmmlConstant或pmmlFieldRef
+func apply(function string, args ...interface{}) pmmlApply {
+	return pmmlApply{Function: function, Args: args}
+}
+
+func constant(v float64) pmmlConstant {
+	return pmmlConstant{DataType: "double", Value: formatFloat(v)}
+}
+
+// formatFloat 以能精确还原float64的最短形式格式化
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// 以下为PMML文档中用到的元素
+
+type pmmlDocument struct {
+	XMLName         xml.Name             `xml:"PMML"`
+	Xmlns           string               `xml:"xmlns,attr"`
+	Version         string               `xml:"version,attr"`
+	Header          pmmlHeader           `xml:"Header"`
+	DataDictionary  pmmlDataDictionary   `xml:"DataDictionary"`
+	Transformations *pmmlTransformations `xml:"TransformationDictionary,omitempty"`
+	Model           pmmlRegressionModel  `xml:"RegressionModel"`
+}
+
+type pmmlHeader struct {
+	Description string          `xml:"description,attr,omitempty"`
+	Application pmmlApplication `xml:"Application"`
+}
+
+type pmmlApplication struct {
+	Name string `xml:"name,attr"`
+}
+
+type pmmlDataDictionary struct {
+	NumberOfFields int             `xml:"numberOfFields,attr"`
+	Fields         []pmmlDataField `xml:"DataField"`
+}
+
+type pmmlDataField struct {
+	Name     string      `xml:"name,attr"`
+	OpType   string      `xml:"optype,attr"`
+	DataType string      `xml:"dataType,attr"`
+	Values   []pmmlValue `xml:"Value,omitempty"`
+}
+
+type pmmlValue struct {
+	Value string `xml:"value,attr"`
+}
+
+type pmmlTransformations struct {
+	Fields []pmmlDerivedField `xml:"DerivedField"`
+}
+
+type pmmlDerivedField struct {
+	Name     string    `xml:"name,attr"`
+	OpType   string    `xml:"optype,attr"`
+	DataType string    `xml:"dataType,attr"`
+	Apply    pmmlApply `xml:"Apply"`
+}
+
+// pmmlApply 函数调用，Args按参数顺序排列，元素名由各参数的XMLName决定
+type pmmlApply struct {
+	XMLName  xml.Name      `xml:"Apply"`
+	Function string        `xml:"function,attr"`
+	Args     []interface{} `xml:",any"`
+}
+
+type pmmlConstant struct {
+	XMLName  xml.Name `xml:"Constant"`
+	DataType string   `xml:"dataType,attr"`
+	Value    string   `xml:",chardata"`
+}
+
+type pmmlFieldRef struct {
+	XMLName xml.Name `xml:"FieldRef"`
+	Field   string   `xml:"field,attr"`
+}
+
+type pmmlRegressionModel struct {
+	ModelName           string                `xml:"modelName,attr"`
+	FunctionName        string                `xml:"functionName,attr"`
+	NormalizationMethod string                `xml:"normalizationMethod,attr"`
+	MiningSchema        pmmlMiningSchema      `xml:"MiningSchema"`
+	Output              *pmmlOutput           `xml:"Output,omitempty"`
+	Tables              []pmmlRegressionTable `xml:"RegressionTable"`
+}
+
+type pmmlMiningSchema struct {
+	Fields []pmmlMiningField `xml:"MiningField"`
+}
+
+type pmmlMiningField struct {
+	Name      string `xml:"name,attr"`
+	UsageType string `xml:"usageType,attr,omitempty"`
+}
+
+type pmmlOutput struct {
+	Fields []pmmlOutputField `xml:"OutputField"`
+}
+
+type pmmlOutputField struct {
+	Name     string `xml:"name,attr"`
+	OpType   string `xml:"optype,attr"`
+	DataType string `xml:"dataType,attr"`
+	Feature  string `xml:"feature,attr"`
+	Value    string `xml:"value,attr,omitempty"`
+}
+
+type pmmlRegressionTable struct {
+	Intercept      string                 `xml:"intercept,attr"`
+	TargetCategory string                 `xml:"targetCategory,attr,omitempty"`
+	Predictors     []pmmlNumericPredictor `xml:"NumericPredictor"`
+}
+
+type pmmlNumericPredictor struct {
+	Name        string `xml:"name,attr"`
+	Exponent    string `xml:"exponent,attr,omitempty"`
+	Coefficient string `xml:"coefficient,attr"`
+}
@@ -0,0 +1,278 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"gonum.org/v1/gonum/mat"
+)
+
+// 以下结构独立于导出代码定义，按PMML 4.4的元素名解析导出的文档
+
+type parsedPMML struct {
+	XMLName xml.Name `xml:"http://www.dmg.org/PMML-4_4 PMML"`
+	Version string   `xml:"version,attr"`
+	Header  struct {
+		Application struct {
+			Name string `xml:"name,attr"`
+		} `xml:"Application"`
+	} `xml:"Header"`
+	DataDictionary struct {
+		NumberOfFields int `xml:"numberOfFields,attr"`
+		Fields         []struct {
+			Name     string `xml:"name,attr"`
+			OpType   string `xml:"optype,attr"`
+			DataType string `xml:"dataType,attr"`
+			Values   []struct {
+				Value string `xml:"value,attr"`
+			} `xml:"Value"`
+		} `xml:"DataField"`
+	} `xml:"DataDictionary"`
+	DerivedFields []struct {
+		Name  string      `xml:"name,attr"`
+		Apply parsedApply `xml:"Apply"`
+	} `xml:"TransformationDictionary>DerivedField"`
+	Model struct {
+		FunctionName        string `xml:"functionName,attr"`
+		NormalizationMethod string `xml:"normalizationMethod,attr"`
+		MiningFields        []struct {
+			Name      string `xml:"name,attr"`
+			UsageType string `xml:"usageType,attr"`
+		} `xml:"MiningSchema>MiningField"`
+		OutputFields []struct {
+			Name    string `xml:"name,attr"`
+			Feature string `xml:"feature,attr"`
+			Value   string `xml:"value,attr"`
+		} `xml:"Output>OutputField"`
+		Tables []struct {
+			Intercept      float64 `xml:"intercept,attr"`
+			TargetCategory string  `xml:"targetCategory,attr"`
+			Predictors     []struct {
+				Name        string  `xml:"name,attr"`
+				Exponent    int     `xml:"exponent,attr"`
+				Coefficient float64 `xml:"coefficient,attr"`
+			} `xml:"NumericPredictor"`
+		} `xml:"RegressionTable"`
+	} `xml:"RegressionModel"`
+}
+
+type parsedApply struct {
+	Function  string        `xml:"function,attr"`
+	Applies   []parsedApply `xml:"Apply"`
+	Constants []float64     `xml:"Constant"`
+	FieldRefs []struct {
+		Field string `xml:"field,attr"`
+	} `xml:"FieldRef"`
+}
+
+// exportPMML 导出模型并解析得到的文档
+func exportPMML(t *testing.T, model models.Model, featureNames []string, targetName string) *parsedPMML {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := PMML(model, featureNames, targetName, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("document does not start with an XML declaration")
+	}
+	doc := &parsedPMML{}
+	if err := xml.Unmarshal(buf.Bytes(), doc); err != nil {
+		t.Fatalf("invalid PMML: %v\n%s", err, buf.String())
+	}
+	dictionary := doc.DataDictionary
+	if doc.Version != "4.4" || doc.Header.Application.Name != producer || dictionary.NumberOfFields != len(dictionary.Fields) {
+		t.Errorf("version = %q, application = %q, numberOfFields = %d with %d fields",
+			doc.Version, doc.Header.Application.Name, dictionary.NumberOfFields, len(dictionary.Fields))
+	}
+	return doc
+}
+
+// evaluate 按PMML语义计算一个样本的第一个回归表的值，派生字段只支持导出用到的函数
+func (doc *parsedPMML) evaluate(t *testing.T, fields map[string]float64) float64 {
+	t.Helper()
+	var eval func(a parsedApply) float64
+	eval = func(a parsedApply) float64 {
+		var args []float64
+		for _, c := range a.Constants {
+			args = append(args, c)
+		}
+		for _, f := range a.FieldRefs {
+			args = append(args, fields[f.Field])
+		}
+		for _, nested := range a.Applies {
+			args = append(args, eval(nested))
+		}
+		switch a.Function {
+		case "exp":
+			return math.Exp(args[0])
+		case "ln":
+			return math.Log(args[0])
+		case "*":
+			return args[0] * args[1]
+		case "pow":
+			// pow的参数依次为字段和常数
+			return math.Pow(fields[a.FieldRefs[0].Field], a.Constants[0])
+		}
+		t.Fatalf("unexpected function %q", a.Function)
+		return 0
+	}
+	for _, d := range doc.DerivedFields {
+		fields[d.Name] = eval(d.Apply)
+	}
+
+	table := doc.Model.Tables[0]
+	value := table.Intercept
+	for _, p := range table.Predictors {
+		exponent := p.Exponent
+		if exponent == 0 {
+			exponent = 1
+		}
+		value += p.Coefficient * math.Pow(fields[p.Name], float64(exponent))
+	}
+	return value
+}
+
+func TestPMMLRegression(t *testing.T) {
+	ols, _, X := fittedModels(t)
+	doc := exportPMML(t, ols, []string{"a", "b", "c"}, "target")
+
+	wantFields := []string{"a", "b", "c", "target"}
+	for i, f := range doc.DataDictionary.Fields {
+		if i >= len(wantFields) || f.Name != wantFields[i] || f.OpType != "continuous" || f.DataType != "double" {
+			t.Errorf("data field %d = %+v, want continuous double %s", i, f, wantFields[i])
+		}
+	}
+	m := doc.Model
+	if m.FunctionName != "regression" || m.NormalizationMethod != "none" || len(m.Tables) != 1 {
+		t.Fatalf("function = %q, normalization = %q, %d tables", m.FunctionName, m.NormalizationMethod, len(m.Tables))
+	}
+	if n := len(m.MiningFields); n != 4 || m.MiningFields[3].Name != "target" || m.MiningFields[3].UsageType != "target" || m.MiningFields[0].UsageType != "" {
+		t.Errorf("mining schema = %+v", m.MiningFields)
+	}
+
+	// 系数以最短的可还原形式写出，解析后与模型参数完全相同
+	params := ols.GetParameters()
+	coefficients := params["coefficients"].([]float64)
+	if m.Tables[0].Intercept != params["intercept"].(float64) {
+		t.Errorf("intercept = %v, want %v", m.Tables[0].Intercept, params["intercept"])
+	}
+	for j, p := range m.Tables[0].Predictors {
+		if p.Name != wantFields[j] || p.Coefficient != coefficients[j] {
+			t.Errorf("predictor %d = %+v, want %s with coefficient %v", j, p, wantFields[j], coefficients[j])
+		}
+	}
+
+	want := ols.Predict(X)
+	for i := 0; i < 5; i++ {
+		got := doc.evaluate(t, map[string]float64{"a": X.At(i, 0), "b": X.At(i, 1), "c": X.At(i, 2)})
+		if math.Abs(got-want.AtVec(i)) > 1e-12 {
+			t.Errorf("row %d: PMML prediction %g, model %g", i, got, want.AtVec(i))
+		}
+	}
+}
+
+func TestPMMLClassification(t *testing.T) {
+	_, logistic, X := fittedModels(t)
+	doc := exportPMML(t, logistic, nil, "")
+
+	target := doc.DataDictionary.Fields[len(doc.DataDictionary.Fields)-1]
+	if doc.DataDictionary.Fields[0].Name != "x1" || target.Name != "y" || target.OpType != "categorical" || len(target.Values) != 2 {
+		t.Errorf("data fields = %+v", doc.DataDictionary.Fields)
+	}
+	m := doc.Model
+	if m.FunctionName != "classification" || m.NormalizationMethod != "logit" {
+		t.Errorf("function = %q, normalization = %q", m.FunctionName, m.NormalizationMethod)
+	}
+	if len(m.Tables) != 2 || m.Tables[0].TargetCategory != "1" || m.Tables[1].TargetCategory != "0" ||
+		m.Tables[1].Intercept != 0 || len(m.Tables[1].Predictors) != 0 {
+		t.Fatalf("regression tables = %+v", m.Tables)
+	}
+	if len(m.OutputFields) != 3 || m.OutputFields[0].Feature != "predictedValue" || m.OutputFields[2].Value != "1" {
+		t.Errorf("output fields = %+v", m.OutputFields)
+	}
+
+	// logit归一化下类别1的概率为第一个表得分的sigmoid
+	want := logistic.Predict(X)
+	for i := 0; i < 5; i++ {
+		fields := map[string]float64{"x1": X.At(i, 0), "x2": X.At(i, 1), "x3": X.At(i, 2)}
+		got := 1 / (1 + math.Exp(-doc.evaluate(t, fields)))
+		if math.Abs(got-want.AtVec(i)) > 1e-12 {
+			t.Errorf("row %d: PMML probability %g, model %g", i, got, want.AtVec(i))
+		}
+	}
+}
+
+func TestPMMLNonlinear(t *testing.T) {
+	n := 30
+	X := mat.NewDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		X.Set(i, 0, 0.5+float64(i)/10)
+	}
+	tests := []struct {
+		modelType string
+		params    map[string]interface{}
+		f         func(x float64) float64
+		derived   string
+	}{
+		{"polynomial", map[string]interface{}{"degree": 2}, func(x float64) float64 { return 1 + 2*x - 0.5*x*x }, ""},
+		{"exponential", nil, func(x float64) float64 { return 2 * math.Exp(0.3*x) }, "exp_b_x"},
+		{"logarithmic", nil, func(x float64) float64 { return 3*math.Log(x) + 1 }, "ln_x"},
+		{"power", nil, func(x float64) float64 { return 1.5 * math.Pow(x, 0.7) }, "pow_x_b"},
+	}
+	manager := models.NewModelManager()
+	for _, tc := range tests {
+		y := mat.NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			y.SetVec(i, tc.f(X.At(i, 0)))
+		}
+		model, err := manager.CreateModel(&models.ModelConfig{ModelType: tc.modelType, Parameters: tc.params})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := model.Fit(X, y); err != nil {
+			t.Fatalf("%s: %v", tc.modelType, err)
+		}
+		doc := exportPMML(t, model, []string{"x"}, "")
+
+		if tc.derived != "" && (len(doc.DerivedFields) != 1 || doc.DerivedFields[0].Name != tc.derived) {
+			t.Errorf("%s: derived fields = %+v, want %s", tc.modelType, doc.DerivedFields, tc.derived)
+		}
+		if tc.modelType == "polynomial" {
+			predictors := doc.Model.Tables[0].Predictors
+			if len(predictors) != 2 || predictors[0].Exponent != 0 || predictors[1].Exponent != 2 {
+				t.Errorf("polynomial predictors = %+v, want x and x^2", predictors)
+			}
+		}
+
+		want := model.Predict(X)
+		for i := 0; i < n; i += 7 {
+			got := doc.evaluate(t, map[string]float64{"x": X.At(i, 0)})
+			if math.Abs(got-want.AtVec(i)) > 1e-9*math.Max(1, math.Abs(want.AtVec(i))) {
+				t.Errorf("%s row %d: PMML prediction %g, model %g", tc.modelType, i, got, want.AtVec(i))
+			}
+		}
+	}
+}
+
+func TestPMMLInvalidFieldNames(t *testing.T) {
+	ols, _, _ := fittedModels(t)
+	tests := []struct {
+		name         string
+		featureNames []string
+		targetName   string
+	}{
+		{"wrong count", []string{"a", "b"}, ""},
+		{"duplicate", []string{"a", "b", "a"}, ""},
+		{"empty", []string{"a", "", "c"}, ""},
+		{"target clash", []string{"a", "b", "c"}, "b"},
+	}
+	for _, tc := range tests {
+		if err := PMML(ols, tc.featureNames, tc.targetName, &bytes.Buffer{}); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package calibration
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/feiyuluoye/Go-Model/internal/models/ensemble"
+	"gonum.org/v1/gonum/mat"
+)
+
+// 概率校准方法
+const (
+	MethodSigmoid  = "sigmoid"  // Platt缩放：在基模型得分上拟合逻辑函数
+	MethodIsotonic = "isotonic" // 保序回归：非参数的单调映射，需要较多校准样本
+)
+
+// ProbabilityModel 可以输出正类概率的分类器；未实现该接口的模型使用Predict的输出作为得分
+type ProbabilityModel interface {
+	PredictProba(X *mat.Dense) *mat.VecDense
+}
+
+// CalibratedClassifier 概率校准分类器：在训练集上训练基分类器，
+// 再用留出的校准集将其输出得分映射为校准后的正类概率
+type CalibratedClassifier struct {
+	BaseSpec        ensemble.ModelSpec
+	Method          string
+	CalibrationSize float64 // 留作校准集的样本比例
+	RandomSeed      int64
+	factory         ensemble.Factory
+	base            ensemble.Model
+	plattA, plattB  float64
+	isotonicX       []float64
+	isotonicY       []float64
+	isTrained       bool
+}
+
+// NewCalibratedClassifier 创建新的概率校准分类器
+func NewCalibratedClassifier(factory ensemble.Factory, baseSpec ensemble.ModelSpec, method string) *CalibratedClassifier {
+	return &CalibratedClassifier{
+		BaseSpec:        baseSpec,
+		Method:          method,
+		CalibrationSize: 0.2,
+		RandomSeed:      42,
+		factory:         factory,
+		isTrained:       false,
+	}
+}
+
+// Fit 划分训练集和校准集，训练基分类器并在校准集上拟合校准映射
+func (c *CalibratedClassifier) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return c.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练基分类器并拟合校准映射，weights为nil时等价于Fit
+func (c *CalibratedClassifier) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
+	if c.Method == "" {
+		c.Method = MethodSigmoid
+	}
+	if c.Method != MethodSigmoid && c.Method != MethodIsotonic {
+		return fmt.Errorf("unsupported calibration method: %s", c.Method)
+	}
+	n, p := X.Dims()
+	if y.Len() != n {
+		return fmt.Errorf("mismatched dimensions: X has %d samples, y has %d", n, y.Len())
+	}
+	if weights != nil && weights.Len() != n {
+		return fmt.Errorf("mismatched dimensions: %d weights for %d samples", weights.Len(), n)
+	}
+	for i := 0; i < n; i++ {
+		if v := y.AtVec(i); v != 0 && v != 1 {
+			return fmt.Errorf("calibration requires binary labels 0/1, got %v", v)
+		}
+	}
+	if c.CalibrationSize <= 0 || c.CalibrationSize >= 1 {
+		return fmt.Errorf("calibration_size must be in (0, 1), got %v", c.CalibrationSize)
+	}
+
+	nCal := int(math.Round(c.CalibrationSize * float64(n)))
+	if nCal < 2 || n-nCal < 2 {
+		return fmt.Errorf("not enough samples for a calibration split: %d", n)
+	}
+	perm := rand.New(rand.NewSource(c.RandomSeed)).Perm(n)
+
+	XTrain, yTrain := mat.NewDense(n-nCal, p, nil), mat.NewVecDense(n-nCal, nil)
+	var wTrain *mat.VecDense
+	if weights != nil {
+		wTrain = mat.NewVecDense(n-nCal, nil)
+	}
+	for i, idx := range perm[nCal:] {
+		XTrain.SetRow(i, mat.Row(nil, idx, X))
+		yTrain.SetVec(i, y.AtVec(idx))
+		if weights != nil {
+			wTrain.SetVec(i, weights.AtVec(idx))
+		}
+	}
+	XCal := mat.NewDense(nCal, p, nil)
+	yCal := make([]float64, nCal)
+	wCal := make([]float64, nCal)
+	for i, idx := range perm[:nCal] {
+		XCal.SetRow(i, mat.Row(nil, idx, X))
+		yCal[i] = y.AtVec(idx)
+		wCal[i] = 1.0
+		if weights != nil {
+			wCal[i] = weights.AtVec(idx)
+		}
+	}
+
+	base, err := c.factory(c.BaseSpec)
+	if err != nil {
+		return err
+	}
+	if err := base.FitWeighted(XTrain, yTrain, wTrain); err != nil {
+		return fmt.Errorf("base classifier %s failed: %v", c.BaseSpec.ModelType, err)
+	}
+	c.base = base
+
+	scores := mat.Col(nil, 0, c.scores(XCal))
+	if c.Method == MethodSigmoid {
+		c.plattA, c.plattB = fitPlatt(scores, yCal, wCal)
+	} else {
+		c.isotonicX, c.isotonicY = fitIsotonic(scores, yCal, wCal)
+	}
+
+	c.isTrained = true
+	return nil
+}
+
+// Predict 返回校准后的正类概率
+func (c *CalibratedClassifier) Predict(X *mat.Dense) *mat.VecDense {
+	scores := c.scores(X)
+	n := scores.Len()
+	probabilities := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		s := scores.AtVec(i)
+		if c.Method == MethodSigmoid {
+			probabilities.SetVec(i, 1/(1+math.Exp(c.plattA*s+c.plattB)))
+		} else {
+			probabilities.SetVec(i, interpolate(c.isotonicX, c.isotonicY, s))
+		}
+	}
+	return probabilities
+}
+
+// PredictProba 返回校准后的正类概率，与Predict相同
+func (c *CalibratedClassifier) PredictProba(X *mat.Dense) *mat.VecDense {
+	return c.Predict(X)
+}
+
+// PredictClass 按阈值将校准后的概率转换为类别（0或1）
+func (c *CalibratedClassifier) PredictClass(X *mat.Dense, threshold float64) *mat.VecDense {
+	probabilities := c.Predict(X)
+	classes := mat.NewVecDense(probabilities.Len(), nil)
+	for i := 0; i < probabilities.Len(); i++ {
+		if probabilities.AtVec(i) >= threshold {
+			classes.SetVec(i, 1.0)
+		}
+	}
+	return classes
+}
+
+// Score 计算准确率（阈值0.5）
+func (c *CalibratedClassifier) Score(X *mat.Dense, y *mat.VecDense) float64 {
+	predictions := c.PredictClass(X, 0.5)
+	n := y.Len()
+	correct := 0
+	for i := 0; i < n; i++ {
+		if predictions.AtVec(i) == y.AtVec(i) {
+			correct++
+		}
+	}
+	return float64(correct) / float64(n)
+}
+
+// GetParameters 返回模型参数
+func (c *CalibratedClassifier) GetParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	params["base_model"] = c.BaseSpec.ModelType
+	params["method"] = c.Method
+	params["calibration_size"] = c.CalibrationSize
+	params["random_seed"] = c.RandomSeed
+	if c.Method == MethodSigmoid {
+		params["platt_a"] = c.plattA
+		params["platt_b"] = c.plattB
+	} else {
+		params["isotonic_thresholds"] = c.isotonicX
+		params["isotonic_values"] = c.isotonicY
+	}
+	if c.base != nil {
+		params["base_parameters"] = c.base.GetParameters()
+	}
+
+	return params
+}
+
+// GetModelType 返回模型类型名称
+func (c *CalibratedClassifier) GetModelType() string {
+	return "CalibratedClassifier"
+}
+
+// scores 返回基分类器的输出得分
+func (c *CalibratedClassifier) scores(X *mat.Dense) *mat.VecDense {
+	if proba, ok := c.base.(ProbabilityModel); ok {
+		return proba.PredictProba(X)
+	}
+	return c.base.Predict(X)
+}
+
+// fitPlatt 使用牛顿法拟合 P(y=1|s) = 1 / (1 + exp(A*s + B))
+// 目标值按Platt的方法做平滑，以避免在可分数据上过拟合；weights为各样本的权重
+func fitPlatt(scores, labels, weights []float64) (float64, float64) {
+	var nPos, nNeg float64
+	for i, y := range labels {
+		if y == 1 {
+			nPos += weights[i]
+		} else {
+			nNeg += weights[i]
+		}
+	}
+	hiTarget := (nPos + 1) / (nPos + 2)
+	loTarget := 1 / (nNeg + 2)
+	targets := make([]float64, len(labels))
+	for i, y := range labels {
+		if y == 1 {
+			targets[i] = hiTarget
+		} else {
+			targets[i] = loTarget
+		}
+	}
+
+	a, b := 0.0, math.Log((nNeg+1)/(nPos+1))
+	const sigma = 1e-12
+	for iter := 0; iter < 100; iter++ {
+		// 梯度与Hessian（对负对数似然）
+		var gA, gB, hAA, hAB, hBB float64
+		for i, s := range scores {
+			p := 1 / (1 + math.Exp(a*s+b))
+			d := weights[i] * (targets[i] - p)
+			w := weights[i] * p * (1 - p)
+			gA += s * d
+			gB += d
+			hAA += s * s * w
+			hAB += s * w
+			hBB += w
+		}
+		hAA += sigma
+		hBB += sigma
+
+		det := hAA*hBB - hAB*hAB
+		if det == 0 {
+			break
+		}
+		dA := -(hBB*gA - hAB*gB) / det
+		dB := -(-hAB*gA + hAA*gB) / det
+		a += dA
+		b += dB
+		if math.Abs(dA) < 1e-10 && math.Abs(dB) < 1e-10 {
+			break
+		}
+	}
+	return a, b
+}
+
+// fitIsotonic 使用PAV(pool adjacent violators)算法拟合单调不减的映射，
+// 返回各块的得分加权均值（阈值）及对应的概率；weights为各样本的权重
+func fitIsotonic(scores, labels, weights []float64) ([]float64, []float64) {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return scores[order[i]] < scores[order[j]]
+	})
+
+	type block struct {
+		sumX, sumY, weight float64
+	}
+	blocks := make([]block, 0, len(order))
+	for _, idx := range order {
+		if weights[idx] == 0 {
+			continue
+		}
+		w := weights[idx]
+		blocks = append(blocks, block{w * scores[idx], w * labels[idx], w})
+		// 合并违反单调性的相邻块
+		for len(blocks) > 1 {
+			last, prev := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if prev.sumY/prev.weight <= last.sumY/last.weight {
+				break
+			}
+			blocks = blocks[:len(blocks)-2]
+			blocks = append(blocks, block{prev.sumX + last.sumX, prev.sumY + last.sumY, prev.weight + last.weight})
+		}
+	}
+
+	xs := make([]float64, len(blocks))
+	ys := make([]float64, len(blocks))
+	for i, b := range blocks {
+		xs[i] = b.sumX / b.weight
+		ys[i] = b.sumY / b.weight
+	}
+	return xs, ys
+}
+
+// interpolate 在保序回归的阈值之间线性插值，超出范围时取端点值
+func interpolate(xs, ys []float64, x float64) float64 {
+	if len(xs) == 0 {
+		return 0.5
+	}
+	if x <= xs[0] {
+		return ys[0]
+	}
+	if x >= xs[len(xs)-1] {
+		return ys[len(ys)-1]
+	}
+	k := sort.SearchFloat64s(xs, x)
+	if xs[k] == x {
+		return ys[k]
+	}
+	t := (x - xs[k-1]) / (xs[k] - xs[k-1])
+	return ys[k-1] + t*(ys[k]-ys[k-1])
+}
@@ -0,0 +1,287 @@
+package ensemble
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Bagging 装袋集成：对样本（可选特征）进行自助采样，并行训练多个基模型副本并平均其预测
+// 训练时同时利用每个基模型未抽到的袋外(OOB)样本估计泛化误差。
+// 基模型为输出正类概率的分类模型（如Logistic）时，平均的是概率，Score和袋外评分按0.5的阈值计算准确率
+type Bagging struct {
+	BaseSpec          ModelSpec
+	NEstimators       int
+	MaxSamples        float64 // 每个基模型的采样比例（有放回）
+	MaxFeatures       float64 // 每个基模型使用的特征比例
+	BootstrapFeatures bool    // 为true时特征也有放回采样
+	RandomSeed        int64
+	NJobs             int     // 并行训练的goroutine数，不大于0时使用CPU核数
+	OOBScore          float64 // 袋外R²，分类模型为袋外准确率，无袋外样本时为NaN
+	OOBError          float64 // 袋外均方误差，分类模型为袋外错误率
+	factory           Factory
+	estimators        []Model
+	features          [][]int
+	classifier        bool
+	isTrained         bool
+}
+
+// NewBagging 创建新的装袋集成模型
+func NewBagging(factory Factory, baseSpec ModelSpec) *Bagging {
+	return &Bagging{
+		BaseSpec:    baseSpec,
+		NEstimators: 10,
+		MaxSamples:  1.0,
+		MaxFeatures: 1.0,
+		RandomSeed:  42,
+		factory:     factory,
+		isTrained:   false,
+	}
+}
+
+// Fit 生成自助样本并并行训练所有基模型，然后计算袋外误差
+func (b *Bagging) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return b.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练，每个基模型使用其自助样本对应的权重，weights为nil时等价于Fit
+func (b *Bagging) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
+	n, p := X.Dims()
+	if y.Len() != n {
+		return fmt.Errorf("mismatched dimensions: X has %d samples, y has %d", n, y.Len())
+	}
+	if b.NEstimators < 1 {
+		return fmt.Errorf("n_estimators must be positive, got %d", b.NEstimators)
+	}
+	if b.MaxSamples <= 0 || b.MaxSamples > 1 || b.MaxFeatures <= 0 || b.MaxFeatures > 1 {
+		return fmt.Errorf("max_samples and max_features must be in (0, 1]")
+	}
+
+	// 先按顺序生成所有采样方案，保证结果与并行调度无关
+	rng := rand.New(rand.NewSource(b.RandomSeed))
+	nSamples := int(math.Max(1, math.Round(b.MaxSamples*float64(n))))
+	nFeatures := int(math.Max(1, math.Round(b.MaxFeatures*float64(p))))
+	samples := make([][]int, b.NEstimators)
+	features := make([][]int, b.NEstimators)
+	for e := range samples {
+		samples[e] = make([]int, nSamples)
+		for i := range samples[e] {
+			samples[e][i] = rng.Intn(n)
+		}
+		switch {
+		case b.BootstrapFeatures:
+			features[e] = make([]int, nFeatures)
+			for j := range features[e] {
+				features[e][j] = rng.Intn(p)
+			}
+		case nFeatures < p:
+			features[e] = rng.Perm(p)[:nFeatures]
+		default:
+			features[e] = nil // 使用全部特征
+		}
+	}
+
+	estimators := make([]Model, b.NEstimators)
+	errs := make([]error, b.NEstimators)
+	jobs := b.NJobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	semaphore := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for e := range estimators {
+		wg.Add(1)
+		go func(e int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			model, err := b.factory(b.BaseSpec)
+			if err != nil {
+				errs[e] = err
+				return
+			}
+			XSample, ySample := subsetRows(selectFeatures(X, features[e]), y, samples[e])
+			if err := model.FitWeighted(XSample, ySample, subsetWeights(weights, samples[e])); err != nil {
+				errs[e] = fmt.Errorf("estimator %d (%s) failed: %v", e, b.BaseSpec.ModelType, err)
+				return
+			}
+			estimators[e] = model
+		}(e)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	b.estimators = estimators
+	b.features = features
+	b.classifier = isClassifier(estimators[0])
+	b.computeOOB(X, y, samples)
+	b.isTrained = true
+	return nil
+}
+
+// computeOOB 用未参与训练的基模型预测每个样本，计算袋外R²和均方误差；分类模型计算袋外准确率和错误率
+func (b *Bagging) computeOOB(X *mat.Dense, y *mat.VecDense, samples [][]int) {
+	n, _ := X.Dims()
+	sums := make([]float64, n)
+	counts := make([]int, n)
+	for e, model := range b.estimators {
+		inBag := make([]bool, n)
+		for _, idx := range samples[e] {
+			inBag[idx] = true
+		}
+		predictions := model.Predict(selectFeatures(X, b.features[e]))
+		for i := 0; i < n; i++ {
+			if !inBag[i] {
+				sums[i] += predictions.AtVec(i)
+				counts[i]++
+			}
+		}
+	}
+
+	var ySum float64
+	var m int
+	for i := 0; i < n; i++ {
+		if counts[i] > 0 {
+			ySum += y.AtVec(i)
+			m++
+		}
+	}
+	if m == 0 {
+		b.OOBScore, b.OOBError = math.NaN(), math.NaN()
+		return
+	}
+
+	if b.classifier {
+		correct := 0
+		for i := 0; i < n; i++ {
+			if counts[i] > 0 && classify(sums[i]/float64(counts[i]), 0.5) == y.AtVec(i) {
+				correct++
+			}
+		}
+		b.OOBScore = float64(correct) / float64(m)
+		b.OOBError = 1 - b.OOBScore
+		return
+	}
+
+	yMean := ySum / float64(m)
+	var ssRes, ssTotal float64
+	for i := 0; i < n; i++ {
+		if counts[i] == 0 {
+			continue
+		}
+		diff := y.AtVec(i) - sums[i]/float64(counts[i])
+		ssRes += diff * diff
+		diff = y.AtVec(i) - yMean
+		ssTotal += diff * diff
+	}
+	b.OOBError = ssRes / float64(m)
+	if ssTotal == 0 {
+		b.OOBScore = 1.0
+	} else {
+		b.OOBScore = 1 - ssRes/ssTotal
+	}
+}
+
+// Predict 平均所有基模型的预测
+func (b *Bagging) Predict(X *mat.Dense) *mat.VecDense {
+	n, _ := X.Dims()
+	predictions := mat.NewVecDense(n, nil)
+	for e, model := range b.estimators {
+		predictions.AddVec(predictions, model.Predict(selectFeatures(X, b.features[e])))
+	}
+	predictions.ScaleVec(1/float64(len(b.estimators)), predictions)
+	return predictions
+}
+
+// PredictClass 将平均的正类概率按阈值转换为类别（0或1），仅对分类基模型有意义
+func (b *Bagging) PredictClass(X *mat.Dense, threshold float64) *mat.VecDense {
+	probabilities := b.Predict(X)
+	n := probabilities.Len()
+	classes := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		classes.SetVec(i, classify(probabilities.AtVec(i), threshold))
+	}
+	return classes
+}
+
+// IsClassifier 返回基模型是否为分类模型，训练后才能确定
+func (b *Bagging) IsClassifier() bool {
+	return b.classifier
+}
+
+// Score 计算模型评分：回归为R²，分类为按0.5的阈值计算的准确率
+func (b *Bagging) Score(X *mat.Dense, y *mat.VecDense) float64 {
+	n := y.Len()
+	if b.classifier {
+		classes := b.PredictClass(X, 0.5)
+		correct := 0
+		for i := 0; i < n; i++ {
+			if classes.AtVec(i) == y.AtVec(i) {
+				correct++
+			}
+		}
+		return float64(correct) / float64(n)
+	}
+
+	predictions := b.Predict(X)
+
+	ymean := 0.0
+	for i := 0; i < n; i++ {
+		ymean += y.AtVec(i)
+	}
+	ymean /= float64(n)
+
+	var ssTotal, ssRes float64
+	for i := 0; i < n; i++ {
+		diff := y.AtVec(i) - ymean
+		ssTotal += diff * diff
+		diff = y.AtVec(i) - predictions.AtVec(i)
+		ssRes += diff * diff
+	}
+	if ssTotal == 0 {
+		return 1.0
+	}
+	return 1 - ssRes/ssTotal
+}
+
+// GetParameters 返回模型参数
+func (b *Bagging) GetParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	params["base_model"] = b.BaseSpec.ModelType
+	params["n_estimators"] = b.NEstimators
+	params["max_samples"] = b.MaxSamples
+	params["max_features"] = b.MaxFeatures
+	params["bootstrap_features"] = b.BootstrapFeatures
+	params["random_seed"] = b.RandomSeed
+	params["oob_score"] = b.OOBScore
+	params["oob_error"] = b.OOBError
+
+	return params
+}
+
+// GetModelType 返回模型类型名称
+func (b *Bagging) GetModelType() string {
+	return "Bagging"
+}
+
+// selectFeatures 按列索引提取特征子集，indices为nil时返回原矩阵
+func selectFeatures(X *mat.Dense, indices []int) *mat.Dense {
+	if indices == nil {
+		return X
+	}
+	n, _ := X.Dims()
+	XSub := mat.NewDense(n, len(indices), nil)
+	for k, j := range indices {
+		XSub.SetCol(k, mat.Col(nil, j, X))
+	}
+	return XSub
+}
@@ -0,0 +1,112 @@
+package ensemble
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/feiyuluoye/Go-Model/internal/models/linear"
+	"gonum.org/v1/gonum/mat"
+)
+
+func testFactory(spec ModelSpec) (Model, error) {
+	switch spec.ModelType {
+	case "logistic":
+		return linear.NewLogistic(), nil
+	case "ols":
+		return linear.NewOLS(), nil
+	}
+	return nil, fmt.Errorf("unsupported model type: %s", spec.ModelType)
+}
+
+// classificationData 生成两个特征的二分类数据，类别由带噪声的线性边界决定
+func classificationData(n int, seed int64) (*mat.Dense, *mat.VecDense) {
+	rng := rand.New(rand.NewSource(seed))
+	X := mat.NewDense(n, 2, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		a, b := rng.NormFloat64(), rng.NormFloat64()
+		X.SetRow(i, []float64{a, b})
+		if 2*a-b+0.5*rng.NormFloat64() > 0 {
+			y.SetVec(i, 1)
+		}
+	}
+	return X, y
+}
+
+func accuracy(classes, y *mat.VecDense) float64 {
+	correct := 0
+	for i := 0; i < y.Len(); i++ {
+		if classes.AtVec(i) == y.AtVec(i) {
+			correct++
+		}
+	}
+	return float64(correct) / float64(y.Len())
+}
+
+func TestBaggingClassifierScoreIsAccuracy(t *testing.T) {
+	X, y := classificationData(300, 1)
+
+	single := linear.NewLogistic()
+	if err := single.Fit(X, y); err != nil {
+		t.Fatalf("logistic fit: %v", err)
+	}
+	singleScore := single.Score(X, y)
+
+	bagging := NewBagging(testFactory, ModelSpec{ModelType: "logistic"})
+	bagging.NEstimators = 15
+	if err := bagging.Fit(X, y); err != nil {
+		t.Fatalf("bagging fit: %v", err)
+	}
+	if !bagging.IsClassifier() {
+		t.Fatal("bagging of logistic models should be a classifier")
+	}
+
+	score := bagging.Score(X, y)
+	if score < 0 || score > 1 {
+		t.Fatalf("score %v is not an accuracy", score)
+	}
+	if want := accuracy(bagging.PredictClass(X, 0.5), y); score != want {
+		t.Errorf("score = %v, want thresholded accuracy %v", score, want)
+	}
+	if math.Abs(score-singleScore) > 0.05 {
+		t.Errorf("bagging accuracy %v is not comparable to single logistic accuracy %v", score, singleScore)
+	}
+
+	if bagging.OOBScore < 0 || bagging.OOBScore > 1 {
+		t.Fatalf("OOB score %v is not an accuracy", bagging.OOBScore)
+	}
+	if math.Abs(bagging.OOBScore+bagging.OOBError-1) > 1e-12 {
+		t.Errorf("OOB error %v should be 1 - OOB accuracy %v", bagging.OOBError, bagging.OOBScore)
+	}
+	if math.Abs(bagging.OOBScore-singleScore) > 0.1 {
+		t.Errorf("OOB accuracy %v is not comparable to single logistic accuracy %v", bagging.OOBScore, singleScore)
+	}
+}
+
+func TestBaggingRegressorScoreIsR2(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	n := 200
+	X := mat.NewDense(n, 2, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		a, b := rng.NormFloat64(), rng.NormFloat64()
+		X.SetRow(i, []float64{a, b})
+		y.SetVec(i, 3*a-b+0.1*rng.NormFloat64())
+	}
+
+	bagging := NewBagging(testFactory, ModelSpec{ModelType: "ols"})
+	if err := bagging.Fit(X, y); err != nil {
+		t.Fatalf("bagging fit: %v", err)
+	}
+	if bagging.IsClassifier() {
+		t.Fatal("bagging of OLS models should not be a classifier")
+	}
+	if score := bagging.Score(X, y); score < 0.99 {
+		t.Errorf("R² = %v, want close to 1", score)
+	}
+	if bagging.OOBScore < 0.99 || bagging.OOBError <= 0 || bagging.OOBError > 0.05 {
+		t.Errorf("OOB R² = %v, OOB MSE = %v", bagging.OOBScore, bagging.OOBError)
+	}
+}
@@ -0,0 +1,103 @@
+package ensemble
+
+import (
+	"fmt"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Model 集成模型的成员模型接口，与models.Model一致
+// 在此单独定义以避免ensemble与models包之间的循环依赖
+type Model interface {
+	Fit(X *mat.Dense, y *mat.VecDense) error
+	FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error
+	Predict(X *mat.Dense) *mat.VecDense
+	Score(X *mat.Dense, y *mat.VecDense) float64
+	GetParameters() map[string]interface{}
+	GetModelType() string
+}
+
+// probabilityClassifier 预测正类概率、可按阈值输出类别的二分类模型，如Logistic和CalibratedClassifier
+type probabilityClassifier interface {
+	PredictClass(X *mat.Dense, threshold float64) *mat.VecDense
+}
+
+// isClassifier 判断模型是否为二分类模型，Bagging按其基模型判断
+func isClassifier(model Model) bool {
+	if bagging, ok := model.(*Bagging); ok {
+		return bagging.IsClassifier()
+	}
+	_, ok := model.(probabilityClassifier)
+	return ok
+}
+
+// classify 将正类概率按阈值转换为类别
+func classify(probability, threshold float64) float64 {
+	if probability >= threshold {
+		return 1
+	}
+	return 0
+}
+
+// ModelSpec 成员模型的配置
+type ModelSpec struct {
+	ModelType  string                 `json:"model_type"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// Factory 根据配置创建一个未训练的新模型，由模型管理器提供
+type Factory func(spec ModelSpec) (Model, error)
+
+// kFoldIndices 打乱样本后划分为k折，返回每折的验证集索引
+func kFoldIndices(n, k int, seed int64) ([][]int, error) {
+	if k < 2 || k > n {
+		return nil, fmt.Errorf("number of folds must be between 2 and %d, got %d", n, k)
+	}
+
+	perm := rand.New(rand.NewSource(seed)).Perm(n)
+	folds := make([][]int, k)
+	for i := range folds {
+		folds[i] = perm[i*n/k : (i+1)*n/k]
+	}
+	return folds, nil
+}
+
+// complement 返回[0, n)中不属于indices的索引
+func complement(n int, indices []int) []int {
+	excluded := make([]bool, n)
+	for _, idx := range indices {
+		excluded[idx] = true
+	}
+	result := make([]int, 0, n-len(indices))
+	for i := 0; i < n; i++ {
+		if !excluded[i] {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// subsetRows 按索引提取样本子集
+func subsetRows(X *mat.Dense, y *mat.VecDense, indices []int) (*mat.Dense, *mat.VecDense) {
+	_, p := X.Dims()
+	XSub := mat.NewDense(len(indices), p, nil)
+	ySub := mat.NewVecDense(len(indices), nil)
+	for i, idx := range indices {
+		XSub.SetRow(i, mat.Row(nil, idx, X))
+		ySub.SetVec(i, y.AtVec(idx))
+	}
+	return XSub, ySub
+}
+
+// subsetWeights 按索引提取样本权重，weights为nil时返回nil
+func subsetWeights(weights *mat.VecDense, indices []int) *mat.VecDense {
+	if weights == nil {
+		return nil
+	}
+	subset := mat.NewVecDense(len(indices), nil)
+	for i, idx := range indices {
+		subset.SetVec(i, weights.AtVec(idx))
+	}
+	return subset
+}
@@ -0,0 +1,163 @@
+package ensemble
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Stacking 堆叠集成：先训练若干基模型，用它们的折外(out-of-fold)预测作为特征训练元模型
+type Stacking struct {
+	BaseSpecs   []ModelSpec
+	MetaSpec    ModelSpec
+	Folds       int
+	RandomSeed  int64
+	Passthrough bool // 为true时元模型同时使用原始特征和基模型预测
+	factory     Factory
+	baseModels  []Model
+	metaModel   Model
+	isTrained   bool
+}
+
+// NewStacking 创建新的堆叠集成模型，默认使用5折生成折外预测
+func NewStacking(factory Factory, baseSpecs []ModelSpec, metaSpec ModelSpec) *Stacking {
+	return &Stacking{
+		BaseSpecs:  baseSpecs,
+		MetaSpec:   metaSpec,
+		Folds:      5,
+		RandomSeed: 42,
+		factory:    factory,
+		isTrained:  false,
+	}
+}
+
+// Fit 生成各基模型的折外预测并训练元模型，然后在全部数据上重新训练基模型
+func (s *Stacking) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return s.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练基模型和元模型，weights为nil时等价于Fit
+func (s *Stacking) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
+	if len(s.BaseSpecs) == 0 {
+		return fmt.Errorf("stacking requires at least one base model")
+	}
+	n, _ := X.Dims()
+	if y.Len() != n {
+		return fmt.Errorf("mismatched dimensions: X has %d samples, y has %d", n, y.Len())
+	}
+
+	folds, err := kFoldIndices(n, s.Folds, s.RandomSeed)
+	if err != nil {
+		return err
+	}
+
+	// 折外预测：每个样本的基模型预测都来自未见过该样本的模型
+	oof := mat.NewDense(n, len(s.BaseSpecs), nil)
+	for m, spec := range s.BaseSpecs {
+		for _, validation := range folds {
+			model, err := s.factory(spec)
+			if err != nil {
+				return err
+			}
+			train := complement(n, validation)
+			XTrain, yTrain := subsetRows(X, y, train)
+			if err := model.FitWeighted(XTrain, yTrain, subsetWeights(weights, train)); err != nil {
+				return fmt.Errorf("base model %s failed: %v", spec.ModelType, err)
+			}
+			XVal, _ := subsetRows(X, y, validation)
+			predictions := model.Predict(XVal)
+			for i, idx := range validation {
+				oof.Set(idx, m, predictions.AtVec(i))
+			}
+		}
+	}
+
+	meta, err := s.factory(s.MetaSpec)
+	if err != nil {
+		return err
+	}
+	if err := meta.FitWeighted(s.metaFeatures(X, oof), y, weights); err != nil {
+		return fmt.Errorf("meta model %s failed: %v", s.MetaSpec.ModelType, err)
+	}
+
+	baseModels := make([]Model, len(s.BaseSpecs))
+	for m, spec := range s.BaseSpecs {
+		model, err := s.factory(spec)
+		if err != nil {
+			return err
+		}
+		if err := model.FitWeighted(X, y, weights); err != nil {
+			return fmt.Errorf("base model %s failed: %v", spec.ModelType, err)
+		}
+		baseModels[m] = model
+	}
+
+	s.baseModels = baseModels
+	s.metaModel = meta
+	s.isTrained = true
+	return nil
+}
+
+// Predict 将基模型的预测输入元模型得到最终预测
+func (s *Stacking) Predict(X *mat.Dense) *mat.VecDense {
+	return s.metaModel.Predict(s.metaFeatures(X, s.basePredictions(X)))
+}
+
+// Score 计算模型评分，评分方式与元模型一致（回归为R²，分类为准确率）
+func (s *Stacking) Score(X *mat.Dense, y *mat.VecDense) float64 {
+	return s.metaModel.Score(s.metaFeatures(X, s.basePredictions(X)), y)
+}
+
+// GetParameters 返回模型参数
+func (s *Stacking) GetParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	baseTypes := make([]string, len(s.BaseSpecs))
+	for m, spec := range s.BaseSpecs {
+		baseTypes[m] = spec.ModelType
+	}
+	params["base_models"] = baseTypes
+	params["meta_model"] = s.MetaSpec.ModelType
+	params["cv_folds"] = s.Folds
+	params["passthrough"] = s.Passthrough
+
+	if s.metaModel != nil {
+		params["meta_parameters"] = s.metaModel.GetParameters()
+	}
+	if s.baseModels != nil {
+		baseParams := make([]map[string]interface{}, len(s.baseModels))
+		for m, model := range s.baseModels {
+			baseParams[m] = model.GetParameters()
+		}
+		params["base_parameters"] = baseParams
+	}
+
+	return params
+}
+
+// GetModelType 返回模型类型名称
+func (s *Stacking) GetModelType() string {
+	return "Stacking"
+}
+
+// basePredictions 计算各基模型在X上的预测，每个模型一列
+func (s *Stacking) basePredictions(X *mat.Dense) *mat.Dense {
+	n, _ := X.Dims()
+	predictions := mat.NewDense(n, len(s.baseModels), nil)
+	for m, model := range s.baseModels {
+		predictions.SetCol(m, mat.Col(nil, 0, model.Predict(X)))
+	}
+	return predictions
+}
+
+// metaFeatures 构造元模型的输入特征
+func (s *Stacking) metaFeatures(X, predictions *mat.Dense) *mat.Dense {
+	if !s.Passthrough {
+		return predictions
+	}
+	n, p := X.Dims()
+	_, m := predictions.Dims()
+	features := mat.NewDense(n, m+p, nil)
+	features.Slice(0, n, 0, m).(*mat.Dense).Copy(predictions)
+	features.Slice(0, n, m, m+p).(*mat.Dense).Copy(X)
+	return features
+}
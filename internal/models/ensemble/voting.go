@@ -0,0 +1,210 @@
+package ensemble
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 投票集成的组合方式
+const (
+	VotingAverage  = "average"  // 回归：预测值加权平均
+	VotingMajority = "majority" // 分类：加权多数投票
+)
+
+// Voting 投票/平均集成：对多个回归模型的预测加权平均，或对多个分类器的预测加权多数投票
+type Voting struct {
+	Specs     []ModelSpec // 成员模型配置，由已训练模型构造时为空
+	Weights   []float64   // 成员权重，为空时等权
+	Mode      string
+	factory   Factory
+	members   []Model
+	isTrained bool
+}
+
+// NewVoting 根据成员模型配置创建投票集成，成员在Fit时训练
+func NewVoting(factory Factory, specs []ModelSpec, weights []float64, mode string) *Voting {
+	return &Voting{
+		Specs:     specs,
+		Weights:   weights,
+		Mode:      mode,
+		factory:   factory,
+		isTrained: false,
+	}
+}
+
+// NewVotingFromModels 由已训练的模型直接构造投票集成，无需再调用Fit
+func NewVotingFromModels(members []Model, weights []float64, mode string) (*Voting, error) {
+	v := &Voting{
+		Weights: weights,
+		Mode:    mode,
+		members: members,
+	}
+	if err := v.validate(len(members)); err != nil {
+		return nil, err
+	}
+	v.isTrained = true
+	return v, nil
+}
+
+// Fit 训练所有成员模型；由已训练模型构造的集成会在新数据上重新训练这些成员
+func (v *Voting) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return v.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练所有成员模型，weights为nil时等价于Fit
+func (v *Voting) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
+	members := v.members
+	if len(v.Specs) > 0 {
+		members = make([]Model, len(v.Specs))
+		for m, spec := range v.Specs {
+			model, err := v.factory(spec)
+			if err != nil {
+				return err
+			}
+			members[m] = model
+		}
+	}
+	if err := v.validate(len(members)); err != nil {
+		return err
+	}
+
+	for _, model := range members {
+		if err := model.FitWeighted(X, y, weights); err != nil {
+			return fmt.Errorf("member model %s failed: %v", model.GetModelType(), err)
+		}
+	}
+
+	v.members = members
+	v.isTrained = true
+	return nil
+}
+
+// Predict 组合各成员模型的预测
+func (v *Voting) Predict(X *mat.Dense) *mat.VecDense {
+	n, _ := X.Dims()
+	memberPredictions := make([]*mat.VecDense, len(v.members))
+	for m, model := range v.members {
+		memberPredictions[m] = model.Predict(X)
+	}
+
+	predictions := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		if v.Mode == VotingMajority {
+			votes := make(map[float64]float64)
+			for m, p := range memberPredictions {
+				votes[math.Round(p.AtVec(i))] += v.weight(m)
+			}
+			best, bestVotes := math.Inf(1), math.Inf(-1)
+			for label, count := range votes {
+				if count > bestVotes || (count == bestVotes && label < best) {
+					best, bestVotes = label, count
+				}
+			}
+			predictions.SetVec(i, best)
+		} else {
+			sum, totalWeight := 0.0, 0.0
+			for m, p := range memberPredictions {
+				sum += v.weight(m) * p.AtVec(i)
+				totalWeight += v.weight(m)
+			}
+			predictions.SetVec(i, sum/totalWeight)
+		}
+	}
+	return predictions
+}
+
+// Score 计算模型评分：平均模式为R²，多数投票模式为准确率
+func (v *Voting) Score(X *mat.Dense, y *mat.VecDense) float64 {
+	predictions := v.Predict(X)
+	n := y.Len()
+
+	if v.Mode == VotingMajority {
+		correct := 0
+		for i := 0; i < n; i++ {
+			if predictions.AtVec(i) == y.AtVec(i) {
+				correct++
+			}
+		}
+		return float64(correct) / float64(n)
+	}
+
+	ymean := 0.0
+	for i := 0; i < n; i++ {
+		ymean += y.AtVec(i)
+	}
+	ymean /= float64(n)
+
+	var ssTotal, ssRes float64
+	for i := 0; i < n; i++ {
+		diff := y.AtVec(i) - ymean
+		ssTotal += diff * diff
+		diff = y.AtVec(i) - predictions.AtVec(i)
+		ssRes += diff * diff
+	}
+	if ssTotal == 0 {
+		return 1.0
+	}
+	return 1 - ssRes/ssTotal
+}
+
+// GetParameters 返回模型参数
+func (v *Voting) GetParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	memberTypes := make([]string, len(v.members))
+	memberParams := make([]map[string]interface{}, len(v.members))
+	for m, model := range v.members {
+		memberTypes[m] = model.GetModelType()
+		memberParams[m] = model.GetParameters()
+	}
+	params["members"] = memberTypes
+	params["member_parameters"] = memberParams
+	params["weights"] = v.Weights
+	params["voting"] = v.Mode
+
+	return params
+}
+
+// GetModelType 返回模型类型名称
+func (v *Voting) GetModelType() string {
+	return "Voting"
+}
+
+// validate 检查成员数量、权重和组合方式
+func (v *Voting) validate(nMembers int) error {
+	if nMembers == 0 {
+		return fmt.Errorf("voting ensemble requires at least one member model")
+	}
+	if v.Mode == "" {
+		v.Mode = VotingAverage
+	}
+	if v.Mode != VotingAverage && v.Mode != VotingMajority {
+		return fmt.Errorf("unsupported voting mode: %s", v.Mode)
+	}
+	if len(v.Weights) == 0 {
+		return nil
+	}
+	if len(v.Weights) != nMembers {
+		return fmt.Errorf("got %d weights for %d member models", len(v.Weights), nMembers)
+	}
+	total := 0.0
+	for _, w := range v.Weights {
+		if w < 0 {
+			return fmt.Errorf("weights must be non-negative")
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("weights must not all be zero")
+	}
+	return nil
+}
+
+// weight 返回第m个成员的权重
+func (v *Voting) weight(m int) float64 {
+	if len(v.Weights) == 0 {
+		return 1
+	}
+	return v.Weights[m]
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"github.com/feiyuluoye/Go-Model/internal/types"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -8,6 +9,8 @@ import (
 type Model interface {
 	// Fit 训练模型
 	Fit(X *mat.Dense, y *mat.VecDense) error
+	// FitWeighted 使用样本权重训练模型，weights为nil时等价于Fit
+	FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error
 	// Predict 预测
 	Predict(X *mat.Dense) *mat.VecDense
 	// Score 计算R²分数
@@ -31,6 +34,12 @@ type ModelConfig struct {
 	Parameters map[string]interface{} `json:"parameters"`
 }
 
+// ModelFactory 根据模型类型和参数创建新的未训练模型，ModelManager实现了该接口。
+// 交叉验证等需要多次训练同一配置的场景应为每次训练创建新实例，而不是复用已训练的模型
+type ModelFactory interface {
+	CreateModel(config *ModelConfig) (Model, error)
+}
+
 // TrainingResult 训练结果
 type TrainingResult struct {
 	ModelID       string             `json:"model_id"`
@@ -73,3 +82,11 @@ type ModelError struct {
 func (e ModelError) Error() string {
 	return e.Message
 }
+
+// SparseModel 支持CSR稀疏特征矩阵的模型（Ridge、Lasso、Logistic），训练和预测时不分配稠密的设计矩阵
+type SparseModel interface {
+	// FitSparse 在稀疏特征上训练模型，weights为nil时所有样本等权
+	FitSparse(X *types.CSRMatrix, y *mat.VecDense, weights *mat.VecDense) error
+	// PredictSparse 在稀疏特征上预测
+	PredictSparse(X *types.CSRMatrix) *mat.VecDense
+}
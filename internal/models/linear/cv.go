@@ -0,0 +1,381 @@
+package linear
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 交叉验证选择lambda的默认设置
+const (
+	defaultCVFolds      = 5
+	defaultCVRandomSeed = 42
+	defaultCVNumLambdas = 50
+)
+
+// RidgeCV 通过内部K折交叉验证自动选择lambda的Ridge回归
+type RidgeCV struct {
+	Lambdas    []float64 // 候选lambda，为空时使用10^-3到10^3的对数网格
+	Folds      int
+	RandomSeed int64
+	BestLambda float64
+	CVScores   []float64 // 每个候选lambda的平均验证R²，与Lambdas一一对应
+	CVStd      []float64 // 每个候选lambda的验证R²标准差
+	model      *Ridge
+	isTrained  bool
+}
+
+// NewRidgeCV 创建新的RidgeCV模型，lambdas为空时自动生成候选网格
+func NewRidgeCV(lambdas []float64) *RidgeCV {
+	return &RidgeCV{
+		Lambdas:    lambdas,
+		Folds:      defaultCVFolds,
+		RandomSeed: defaultCVRandomSeed,
+		isTrained:  false,
+	}
+}
+
+// Fit 对每个候选lambda执行K折交叉验证，选出平均R²最高者并在全部数据上重新训练
+func (r *RidgeCV) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return r.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重执行交叉验证和最终训练，weights为nil时等价于Fit
+func (r *RidgeCV) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
+	lambdas := r.Lambdas
+	if len(lambdas) == 0 {
+		lambdas = logSpace(-3, 3, 13)
+	}
+	lambdas = descending(lambdas)
+
+	folds, err := cvFolds(X, y, r.Folds, r.RandomSeed)
+	if err != nil {
+		return err
+	}
+
+	scores := make([][]float64, len(lambdas))
+	for _, fold := range folds {
+		XTrain, yTrain := subsetRows(X, y, fold.train)
+		XVal, yVal := subsetRows(X, y, fold.validation)
+		wTrain := subsetWeights(weights, fold.train)
+		for k, lambda := range lambdas {
+			ridge := NewRidge(lambda)
+			if err := ridge.FitWeighted(XTrain, yTrain, wTrain); err != nil {
+				return fmt.Errorf("cross-validation failed for lambda %v: %v", lambda, err)
+			}
+			scores[k] = append(scores[k], ridge.Score(XVal, yVal))
+		}
+	}
+
+	r.Lambdas = lambdas
+	r.CVScores, r.CVStd = cvCurve(scores)
+	r.BestLambda = lambdas[argmax(r.CVScores)]
+
+	r.model = NewRidge(r.BestLambda)
+	if err := r.model.FitWeighted(X, y, weights); err != nil {
+		return err
+	}
+
+	r.isTrained = true
+	return nil
+}
+
+// Predict 使用选出的lambda训练的模型进行预测
+func (r *RidgeCV) Predict(X *mat.Dense) *mat.VecDense {
+	return r.model.Predict(X)
+}
+
+// Score 计算模型评分 (R²)
+func (r *RidgeCV) Score(X *mat.Dense, y *mat.VecDense) float64 {
+	return r.model.Score(X, y)
+}
+
+// GetParameters 返回模型参数，包含选出的lambda和交叉验证曲线
+func (r *RidgeCV) GetParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	if r.model != nil {
+		params = r.model.GetParameters()
+	}
+	params["lambdas"] = r.Lambdas
+	params["cv_scores"] = r.CVScores
+	params["cv_std"] = r.CVStd
+	params["cv_folds"] = r.Folds
+	params["best_lambda"] = r.BestLambda
+
+	return params
+}
+
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复交叉验证结果和用选出的lambda训练的模型
+func (r *RidgeCV) SetParameters(params map[string]interface{}) error {
+	model := NewRidge(0)
+	if err := model.SetParameters(params); err != nil {
+		return err
+	}
+	series := make(map[string][]float64)
+	for _, key := range []string{"lambdas", "cv_scores", "cv_std"} {
+		values, err := floatSlice(params, key)
+		if err != nil {
+			return err
+		}
+		series[key] = values
+	}
+	folds, bestLambda := r.Folds, model.Lambda
+	if err := setInt(params, "cv_folds", &folds); err != nil {
+		return err
+	}
+	if err := setFloat(params, "best_lambda", &bestLambda); err != nil {
+		return err
+	}
+
+	r.Lambdas = series["lambdas"]
+	r.CVScores = series["cv_scores"]
+	r.CVStd = series["cv_std"]
+	r.Folds = folds
+	r.BestLambda = bestLambda
+	r.model = model
+	r.isTrained = true
+	return nil
+}
+
+// GetModelType 返回模型类型名称
+func (r *RidgeCV) GetModelType() string {
+	return "RidgeCV"
+}
+
+// LassoCV 通过内部K折交叉验证自动选择lambda的Lasso回归
+// 每折沿lambda序列热启动计算正则化路径，因此候选lambda较多时开销仍然可控
+type LassoCV struct {
+	Lambdas    []float64 // 候选lambda，为空时根据数据自动生成
+	NumLambdas int       // 自动生成的lambda个数
+	Folds      int
+	RandomSeed int64
+	MaxIter    int
+	Tol        float64
+	BestLambda float64
+	CVScores   []float64 // 每个候选lambda的平均验证R²，与Lambdas一一对应
+	CVStd      []float64 // 每个候选lambda的验证R²标准差
+	model      *Lasso
+	isTrained  bool
+}
+
+// NewLassoCV 创建新的LassoCV模型，lambdas为空时自动生成候选序列
+func NewLassoCV(lambdas []float64) *LassoCV {
+	return &LassoCV{
+		Lambdas:    lambdas,
+		NumLambdas: defaultCVNumLambdas,
+		Folds:      defaultCVFolds,
+		RandomSeed: defaultCVRandomSeed,
+		MaxIter:    1000,
+		Tol:        1e-4,
+		isTrained:  false,
+	}
+}
+
+// Fit 在每折训练集上计算正则化路径并在验证集上评分，选出平均R²最高的lambda后在全部数据上重新训练
+func (l *LassoCV) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return l.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重执行交叉验证和最终训练，weights为nil时等价于Fit
+func (l *LassoCV) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
+	lambdas := l.Lambdas
+	if len(lambdas) == 0 {
+		if _, p := X.Dims(); p == 0 {
+			return fmt.Errorf("empty feature matrix")
+		}
+		lambdas = lambdaSequence(X, y, 1.0, l.NumLambdas, 1e-3)
+	}
+	lambdas = descending(lambdas)
+
+	folds, err := cvFolds(X, y, l.Folds, l.RandomSeed)
+	if err != nil {
+		return err
+	}
+
+	scores := make([][]float64, len(lambdas))
+	for _, fold := range folds {
+		XTrain, yTrain := subsetRows(X, y, fold.train)
+		XVal, yVal := subsetRows(X, y, fold.validation)
+		opts := &PathOptions{Lambdas: lambdas, MaxIter: l.MaxIter, Tol: l.Tol, Weights: subsetWeights(weights, fold.train)}
+		path, err := LassoPath(XTrain, yTrain, opts)
+		if err != nil {
+			return err
+		}
+		for k := range lambdas {
+			lasso := &Lasso{
+				Coefficients: mat.NewVecDense(len(path.Coefficients[k]), path.Coefficients[k]),
+				Intercept:    path.Intercepts[k],
+				isTrained:    true,
+			}
+			scores[k] = append(scores[k], lasso.Score(XVal, yVal))
+		}
+	}
+
+	l.Lambdas = lambdas
+	l.CVScores, l.CVStd = cvCurve(scores)
+	l.BestLambda = lambdas[argmax(l.CVScores)]
+
+	l.model = NewLasso(l.BestLambda)
+	l.model.MaxIter = l.MaxIter
+	l.model.Tol = l.Tol
+	if err := l.model.FitWeighted(X, y, weights); err != nil {
+		return err
+	}
+
+	l.isTrained = true
+	return nil
+}
+
+// Predict 使用选出的lambda训练的模型进行预测
+func (l *LassoCV) Predict(X *mat.Dense) *mat.VecDense {
+	return l.model.Predict(X)
+}
+
+// Score 计算模型评分 (R²)
+func (l *LassoCV) Score(X *mat.Dense, y *mat.VecDense) float64 {
+	return l.model.Score(X, y)
+}
+
+// GetParameters 返回模型参数，包含选出的lambda和交叉验证曲线
+func (l *LassoCV) GetParameters() map[string]interface{} {
+	params := make(map[string]interface{})
+	if l.model != nil {
+		params = l.model.GetParameters()
+	}
+	params["lambdas"] = l.Lambdas
+	params["cv_scores"] = l.CVScores
+	params["cv_std"] = l.CVStd
+	params["cv_folds"] = l.Folds
+	params["best_lambda"] = l.BestLambda
+
+	return params
+}
+
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复交叉验证结果和用选出的lambda训练的模型
+func (l *LassoCV) SetParameters(params map[string]interface{}) error {
+	model := NewLasso(0)
+	if err := model.SetParameters(params); err != nil {
+		return err
+	}
+	series := make(map[string][]float64)
+	for _, key := range []string{"lambdas", "cv_scores", "cv_std"} {
+		values, err := floatSlice(params, key)
+		if err != nil {
+			return err
+		}
+		series[key] = values
+	}
+	folds, bestLambda := l.Folds, model.Lambda
+	if err := setInt(params, "cv_folds", &folds); err != nil {
+		return err
+	}
+	if err := setFloat(params, "best_lambda", &bestLambda); err != nil {
+		return err
+	}
+
+	l.Lambdas = series["lambdas"]
+	l.CVScores = series["cv_scores"]
+	l.CVStd = series["cv_std"]
+	l.Folds = folds
+	l.BestLambda = bestLambda
+	l.MaxIter, l.Tol = model.MaxIter, model.Tol
+	l.model = model
+	l.isTrained = true
+	return nil
+}
+
+// GetModelType 返回模型类型名称
+func (l *LassoCV) GetModelType() string {
+	return "LassoCV"
+}
+
+// cvFold 单折的训练/验证样本索引
+type cvFold struct {
+	train      []int
+	validation []int
+}
+
+// cvFolds 打乱样本后划分为k折，样本数少于k时退化为留一法
+func cvFolds(X *mat.Dense, y *mat.VecDense, k int, seed int64) ([]cvFold, error) {
+	n, _ := X.Dims()
+	if y.Len() != n {
+		return nil, fmt.Errorf("mismatched dimensions: X has %d samples, y has %d", n, y.Len())
+	}
+	if k <= 0 {
+		k = defaultCVFolds
+	}
+	if n < k {
+		k = n
+	}
+	if k < 2 {
+		return nil, fmt.Errorf("at least 2 samples are required for cross-validation, got %d", n)
+	}
+
+	perm := rand.New(rand.NewSource(seed)).Perm(n)
+	folds := make([]cvFold, k)
+	for i := range folds {
+		start, end := i*n/k, (i+1)*n/k
+		folds[i].validation = perm[start:end]
+		folds[i].train = append(append([]int(nil), perm[:start]...), perm[end:]...)
+	}
+	return folds, nil
+}
+
+// subsetRows 按索引提取样本子集
+func subsetRows(X *mat.Dense, y *mat.VecDense, indices []int) (*mat.Dense, *mat.VecDense) {
+	_, p := X.Dims()
+	XSub := mat.NewDense(len(indices), p, nil)
+	ySub := mat.NewVecDense(len(indices), nil)
+	for i, idx := range indices {
+		XSub.SetRow(i, X.RawRowView(idx))
+		ySub.SetVec(i, y.AtVec(idx))
+	}
+	return XSub, ySub
+}
+
+// cvCurve 计算每个候选lambda在各折上的平均得分和标准差
+func cvCurve(scores [][]float64) ([]float64, []float64) {
+	means := make([]float64, len(scores))
+	stds := make([]float64, len(scores))
+	for k, foldScores := range scores {
+		for _, s := range foldScores {
+			means[k] += s
+		}
+		means[k] /= float64(len(foldScores))
+		for _, s := range foldScores {
+			stds[k] += (s - means[k]) * (s - means[k])
+		}
+		stds[k] = math.Sqrt(stds[k] / float64(len(foldScores)))
+	}
+	return means, stds
+}
+
+// argmax 返回最大值的索引，并列时取靠前者（即正则化更强的lambda）
+func argmax(values []float64) int {
+	best := 0
+	for i, v := range values {
+		if v > values[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// logSpace 生成10^start到10^stop的num个对数等间隔值
+func logSpace(start, stop float64, num int) []float64 {
+	values := make([]float64, num)
+	for i := range values {
+		values[i] = math.Pow(10, start+float64(i)*(stop-start)/float64(num-1))
+	}
+	return values
+}
+
+// descending 返回按从大到小排序的副本
+func descending(values []float64) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+	return sorted
+}
@@ -0,0 +1,233 @@
+package linear
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// 系数检验使用的分布
+const (
+	TestT = "t" // t检验，用于OLS和Ridge
+	TestZ = "z" // 基于渐近正态性的z检验（Wald检验），用于逻辑回归
+)
+
+// InferenceResult 系数的统计推断结果。Coefficients、StdErrors、Statistics和PValues的
+// 第0个元素为截距，之后依次为各特征的系数；Covariance按同样的顺序排列
+type InferenceResult struct {
+	Coefficients []float64   `json:"coefficients"`
+	StdErrors    []float64   `json:"std_errors"`
+	Statistics   []float64   `json:"statistics"` // 系数除以标准误得到的t或z统计量
+	PValues      []float64   `json:"p_values"`   // 双侧检验的p值，原假设为系数等于0
+	Covariance   [][]float64 `json:"covariance"` // 系数的协方差矩阵
+	Test         string      `json:"test"`       // TestT或TestZ
+	DFModel      float64     `json:"df_model"`   // 模型自由度（不含截距），Ridge为有效自由度
+	DFResidual   float64     `json:"df_residual"`
+	// 以下为线性回归的整体F检验，原假设为除截距外的系数全为0；逻辑回归为0
+	Sigma2     float64 `json:"sigma2"` // 残差方差的估计
+	FStatistic float64 `json:"f_statistic"`
+	FPValue    float64 `json:"f_p_value"`
+	// 以下为逻辑回归相对仅含截距模型的似然比检验；线性回归为0
+	LLRStatistic float64 `json:"llr_statistic"`
+	LLRPValue    float64 `json:"llr_p_value"`
+	PseudoR2     float64 `json:"pseudo_r2"` // McFadden伪R²
+}
+
+// Interval 单个样本预测值的区间估计
+type Interval struct {
+	StdError        float64 `json:"std_error"`        // 均值预测的标准误，逻辑回归为线性预测值的标准误
+	Lower           float64 `json:"lower"`            // 均值（期望）的置信区间下限
+	Upper           float64 `json:"upper"`            // 均值（期望）的置信区间上限
+	PredictionLower float64 `json:"prediction_lower"` // 单个新观测的预测区间下限
+	PredictionUpper float64 `json:"prediction_upper"` // 单个新观测的预测区间上限
+}
+
+// Intervals 计算X中每个样本在置信水平level（如0.95）下的置信区间和预测区间。
+// 线性回归的置信区间为 ŷ±t·√(x₀ᵀΣx₀)，预测区间另加残差方差 ŷ±t·√(x₀ᵀΣx₀+σ²)；
+// 逻辑回归先在线性预测值上按正态近似构造区间再经sigmoid变换为概率，新观测只能为0或1，预测区间与置信区间相同
+func (r *InferenceResult) Intervals(X *mat.Dense, level float64) ([]Interval, error) {
+	if level <= 0 || level >= 1 {
+		return nil, fmt.Errorf("confidence level must be in (0, 1), got %v", level)
+	}
+	n, p := X.Dims()
+	if p+1 != len(r.Coefficients) {
+		return nil, fmt.Errorf("mismatched dimensions: model has %d features, X has %d", len(r.Coefficients)-1, p)
+	}
+
+	var critical float64
+	if r.Test == TestZ {
+		critical = distuv.UnitNormal.Quantile(1 - (1-level)/2)
+	} else {
+		critical = distuv.StudentsT{Mu: 0, Sigma: 1, Nu: r.DFResidual}.Quantile(1 - (1-level)/2)
+	}
+
+	intervals := make([]Interval, n)
+	x0 := make([]float64, p+1)
+	for i := 0; i < n; i++ {
+		x0[0] = 1
+		for j := 0; j < p; j++ {
+			x0[j+1] = X.At(i, j)
+		}
+		var estimate, variance float64
+		for j := range x0 {
+			estimate += x0[j] * r.Coefficients[j]
+			for k := range x0 {
+				variance += x0[j] * r.Covariance[j][k] * x0[k]
+			}
+		}
+		se := math.Sqrt(math.Max(variance, 0))
+
+		interval := Interval{StdError: se}
+		if r.Test == TestZ {
+			interval.Lower = sigmoid(estimate - critical*se)
+			interval.Upper = sigmoid(estimate + critical*se)
+			interval.PredictionLower, interval.PredictionUpper = interval.Lower, interval.Upper
+		} else {
+			interval.Lower = estimate - critical*se
+			interval.Upper = estimate + critical*se
+			predictionSE := math.Sqrt(math.Max(variance, 0) + r.Sigma2)
+			interval.PredictionLower = estimate - critical*predictionSE
+			interval.PredictionUpper = estimate + critical*predictionSE
+		}
+		intervals[i] = interval
+	}
+	return intervals, nil
+}
+
+// linearInference 根据（按sqrt(w)加权后的）含截距设计矩阵、目标和系数计算线性回归的推断结果，
+// lambda为不作用于截距的L2正则化强度。系数协方差为 σ²·A⁻¹XᵀXA⁻¹（A=XᵀX+λI），λ=0时即OLS的σ²(XᵀX)⁻¹；
+// Ridge估计有偏，其检验只是近似。矩阵奇异或残差自由度不为正时返回nil
+func linearInference(XWithIntercept *mat.Dense, y *mat.VecDense, coefficients *mat.VecDense, lambda float64) *InferenceResult {
+	n, cols := XWithIntercept.Dims()
+
+	var xtx mat.SymDense
+	xtx.SymOuterK(1, XWithIntercept.T())
+	a := mat.NewSymDense(cols, nil)
+	a.CopySym(&xtx)
+	for j := 1; j < cols; j++ {
+		a.SetSym(j, j, a.At(j, j)+lambda)
+	}
+	var aInv mat.Dense
+	if err := aInv.Inverse(a); err != nil {
+		return nil
+	}
+
+	// 帽子矩阵的迹 tr(H)=tr(A⁻¹XᵀX) 为包含截距的有效参数个数
+	var hat mat.Dense
+	hat.Mul(&aInv, &xtx)
+	trace := mat.Trace(&hat)
+	dfResidual := float64(n) - trace
+	if dfResidual <= 0 {
+		return nil
+	}
+
+	var fitted mat.VecDense
+	fitted.MulVec(XWithIntercept, coefficients)
+	// 截距列为sqrt(w)，加权均值 ȳ = Σ√w·ỹ / Σw
+	var rss, sw, swy float64
+	for i := 0; i < n; i++ {
+		diff := y.AtVec(i) - fitted.AtVec(i)
+		rss += diff * diff
+		s := XWithIntercept.At(i, 0)
+		sw += s * s
+		swy += s * y.AtVec(i)
+	}
+	yMean := swy / sw
+	var tss float64
+	for i := 0; i < n; i++ {
+		diff := y.AtVec(i) - yMean*XWithIntercept.At(i, 0)
+		tss += diff * diff
+	}
+	sigma2 := rss / dfResidual
+
+	var covariance mat.Dense
+	covariance.Mul(&hat, &aInv)
+	covariance.Scale(sigma2, &covariance)
+
+	result := newInferenceResult(coefficients, &covariance, TestT, distuv.StudentsT{Mu: 0, Sigma: 1, Nu: dfResidual})
+	result.DFModel = trace - 1
+	result.DFResidual = dfResidual
+	result.Sigma2 = sigma2
+	if result.DFModel > 0 && rss > 0 {
+		result.FStatistic = (math.Max(tss-rss, 0) / result.DFModel) / sigma2
+		f := distuv.F{D1: result.DFModel, D2: dfResidual}
+		result.FPValue = f.Survival(result.FStatistic)
+	}
+	return result
+}
+
+// logisticInference 根据含截距设计矩阵、0/1标签、样本权重和系数计算逻辑回归的推断结果：
+// 系数协方差为Fisher信息矩阵 XᵀWX（W=w·p(1-p)）的逆，整体检验为与仅含截距模型的似然比检验。
+// 完全可分等导致信息矩阵奇异时返回nil
+func logisticInference(XWithIntercept *mat.Dense, y *mat.VecDense, weights []float64, theta *mat.VecDense) *InferenceResult {
+	n, cols := XWithIntercept.Dims()
+	information := mat.NewSymDense(cols, nil)
+	var logLikelihood, totalWeight, positiveWeight float64
+	for i := 0; i < n; i++ {
+		row := XWithIntercept.RawRowView(i)
+		prob := sigmoid(mat.Dot(mat.NewVecDense(cols, row), theta))
+		curvature := weights[i] * prob * (1 - prob)
+		for j := 0; j < cols; j++ {
+			for k := j; k < cols; k++ {
+				information.SetSym(j, k, information.At(j, k)+curvature*row[j]*row[k])
+			}
+		}
+		logLikelihood += weights[i] * bernoulliLogLikelihood(y.AtVec(i), prob)
+		totalWeight += weights[i]
+		positiveWeight += weights[i] * y.AtVec(i)
+	}
+
+	var covariance mat.Dense
+	if err := covariance.Inverse(information); err != nil {
+		return nil
+	}
+
+	result := newInferenceResult(theta, &covariance, TestZ, distuv.UnitNormal)
+	result.DFModel = float64(cols - 1)
+	result.DFResidual = float64(n - cols)
+
+	// 仅含截距的模型预测概率恒为正类的加权比例
+	base := positiveWeight / totalWeight
+	var nullLogLikelihood float64
+	for i := 0; i < n; i++ {
+		nullLogLikelihood += weights[i] * bernoulliLogLikelihood(y.AtVec(i), base)
+	}
+	if cols > 1 {
+		result.LLRStatistic = math.Max(2*(logLikelihood-nullLogLikelihood), 0)
+		chi2 := distuv.ChiSquared{K: float64(cols - 1)}
+		result.LLRPValue = chi2.Survival(result.LLRStatistic)
+	}
+	if nullLogLikelihood != 0 {
+		result.PseudoR2 = 1 - logLikelihood/nullLogLikelihood
+	}
+	return result
+}
+
+// newInferenceResult 由系数和协方差矩阵计算标准误、检验统计量和双侧p值
+func newInferenceResult(coefficients *mat.VecDense, covariance *mat.Dense, test string, dist interface{ Survival(float64) float64 }) *InferenceResult {
+	cols := coefficients.Len()
+	result := &InferenceResult{
+		Coefficients: make([]float64, cols),
+		StdErrors:    make([]float64, cols),
+		Statistics:   make([]float64, cols),
+		PValues:      make([]float64, cols),
+		Covariance:   make([][]float64, cols),
+		Test:         test,
+	}
+	for j := 0; j < cols; j++ {
+		result.Covariance[j] = mat.Row(nil, j, covariance)
+		result.Coefficients[j] = coefficients.AtVec(j)
+		result.StdErrors[j] = math.Sqrt(math.Max(covariance.At(j, j), 0))
+		result.Statistics[j] = result.Coefficients[j] / result.StdErrors[j]
+		result.PValues[j] = math.Min(2*dist.Survival(math.Abs(result.Statistics[j])), 1)
+	}
+	return result
+}
+
+// bernoulliLogLikelihood 返回标签y在正类概率p下的对数似然，p裁剪到[1e-15, 1-1e-15]
+func bernoulliLogLikelihood(y, p float64) float64 {
+	p = math.Min(math.Max(p, 1e-15), 1-1e-15)
+	return y*math.Log(p) + (1-y)*math.Log(1-p)
+}
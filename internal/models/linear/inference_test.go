@@ -0,0 +1,119 @@
+package linear
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// R自带的cars数据集（speed, dist），50个样本
+var (
+	carsSpeed = []float64{4, 4, 7, 7, 8, 9, 10, 10, 10, 11, 11, 12, 12, 12, 12, 13, 13, 13, 13, 14, 14, 14, 14, 15, 15, 15, 16, 16, 17, 17, 17, 18, 18, 18, 18, 19, 19, 19, 20, 20, 20, 20, 20, 22, 23, 24, 24, 24, 24, 25}
+	carsDist  = []float64{2, 10, 4, 22, 16, 10, 18, 26, 34, 17, 28, 14, 20, 24, 28, 26, 34, 34, 46, 26, 36, 60, 80, 20, 26, 54, 32, 40, 32, 40, 50, 42, 56, 76, 84, 36, 46, 68, 32, 48, 52, 56, 64, 66, 54, 70, 92, 93, 120, 85}
+)
+
+// fitCars 拟合lm(dist ~ speed, data = cars)并返回推断结果
+func fitCars(t *testing.T) *InferenceResult {
+	t.Helper()
+	X := mat.NewDense(len(carsSpeed), 1, append([]float64(nil), carsSpeed...))
+	y := mat.NewVecDense(len(carsDist), append([]float64(nil), carsDist...))
+	model := NewOLS()
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	result := model.Inference()
+	if result == nil {
+		t.Fatal("inference is nil after fitting")
+	}
+	return result
+}
+
+func TestOLSInference(t *testing.T) {
+	result := fitCars(t)
+
+	// R: summary(lm(dist ~ speed, data = cars))
+	//              Estimate Std. Error t value Pr(>|t|)
+	// (Intercept) -17.5791     6.7584  -2.601   0.0123
+	// speed         3.9324     0.4155   9.464 1.49e-12
+	// Residual standard error: 15.38 on 48 degrees of freedom
+	// F-statistic: 89.57 on 1 and 48 DF,  p-value: 1.49e-12
+	// 表中的高精度参考值按简单线性回归的闭式解独立计算，与R的输出在显示精度内一致
+	tests := []struct {
+		name                          string
+		estimate, stdError, statistic float64
+		pValue, pTol                  float64
+	}{
+		{"intercept", -17.5790949, 6.7584402, -2.6010580, 0.0123188, 1e-6},
+		{"speed", 3.9324088, 0.4155128, 9.4639900, 1.4898365e-12, 1e-16},
+	}
+	for j, tc := range tests {
+		if math.Abs(result.Coefficients[j]-tc.estimate) > 1e-6 {
+			t.Errorf("%s: estimate = %.7f, want %.7f", tc.name, result.Coefficients[j], tc.estimate)
+		}
+		if math.Abs(result.StdErrors[j]-tc.stdError) > 1e-6 {
+			t.Errorf("%s: std error = %.7f, want %.7f", tc.name, result.StdErrors[j], tc.stdError)
+		}
+		if math.Abs(result.Statistics[j]-tc.statistic) > 1e-6 {
+			t.Errorf("%s: t = %.7f, want %.7f", tc.name, result.Statistics[j], tc.statistic)
+		}
+		if math.Abs(result.PValues[j]-tc.pValue) > tc.pTol {
+			t.Errorf("%s: p = %.7g, want %.7g", tc.name, result.PValues[j], tc.pValue)
+		}
+	}
+
+	if result.Test != TestT || result.DFModel != 1 || math.Abs(result.DFResidual-48) > 1e-9 {
+		t.Errorf("test = %s, df = %g/%g, want t, 1/48", result.Test, result.DFModel, result.DFResidual)
+	}
+	if sigma := math.Sqrt(result.Sigma2); math.Abs(sigma-15.3795867) > 1e-6 {
+		t.Errorf("residual standard error = %.7f, want 15.3795867", sigma)
+	}
+	// 单个特征时F统计量等于t统计量的平方，p值相同
+	if math.Abs(result.FStatistic-89.5671065) > 1e-5 || math.Abs(result.FPValue-1.4898365e-12) > 1e-16 {
+		t.Errorf("F = %.7f, p = %.7g, want 89.5671065, 1.4898365e-12", result.FStatistic, result.FPValue)
+	}
+}
+
+func TestOLSIntervals(t *testing.T) {
+	result := fitCars(t)
+
+	// R: predict(lm(dist ~ speed, data = cars), data.frame(speed = c(12, 19, 24)), interval = "confidence"/"prediction")，
+	// 参考值按 ŷ±t₀.₉₇₅,₄₈·s·√(h) 和 ŷ±t₀.₉₇₅,₄₈·s·√(1+h) 独立计算，speed = 12时R输出 fit 29.60981，
+	// 置信区间 [24.39514, 34.82448]，预测区间 [-1.749529, 60.96915]
+	tests := []struct {
+		speed                            float64
+		lower, upper                     float64
+		predictionLower, predictionUpper float64
+	}{
+		{12, 24.3951377, 34.8244828, -1.7495288, 60.9691492},
+		{19, 51.8291331, 62.4442100, 25.7617564, 88.5115866},
+		{24, 68.3876526, 85.2097780, 44.7524783, 108.8449524},
+	}
+	X := mat.NewDense(len(tests), 1, nil)
+	for i, tc := range tests {
+		X.Set(i, 0, tc.speed)
+	}
+	intervals, err := result.Intervals(X, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tc := range tests {
+		got := intervals[i]
+		if math.Abs(got.Lower-tc.lower) > 1e-6 || math.Abs(got.Upper-tc.upper) > 1e-6 {
+			t.Errorf("speed %g: confidence interval = [%.7f, %.7f], want [%.7f, %.7f]", tc.speed, got.Lower, got.Upper, tc.lower, tc.upper)
+		}
+		if math.Abs(got.PredictionLower-tc.predictionLower) > 1e-6 || math.Abs(got.PredictionUpper-tc.predictionUpper) > 1e-6 {
+			t.Errorf("speed %g: prediction interval = [%.7f, %.7f], want [%.7f, %.7f]",
+				tc.speed, got.PredictionLower, got.PredictionUpper, tc.predictionLower, tc.predictionUpper)
+		}
+	}
+
+	for _, level := range []float64{0, 1, -0.5} {
+		if _, err := result.Intervals(X, level); err == nil {
+			t.Errorf("level %g: expected an error", level)
+		}
+	}
+	if _, err := result.Intervals(mat.NewDense(1, 2, nil), 0.95); err == nil {
+		t.Error("expected an error for mismatched feature count")
+	}
+}
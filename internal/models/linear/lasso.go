@@ -27,6 +27,11 @@ func NewLasso(lambda float64) *Lasso {
 
 // Fit 训练Lasso模型使用坐标下降法
 func (l *Lasso) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return l.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练Lasso模型，weights为nil时等价于Fit
+func (l *Lasso) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
 	n, p := X.Dims()
 
 	// 添加截距项
@@ -37,79 +42,98 @@ func (l *Lasso) Fit(X *mat.Dense, y *mat.VecDense) error {
 			XWithIntercept.Set(i, j+1, X.At(i, j))
 		}
 	}
+	XWithIntercept, y, err := weightRows(XWithIntercept, y, weights)
+	if err != nil {
+		return err
+	}
 
 	// 初始化系数
 	beta := mat.NewVecDense(p+1, nil)
-	for i := 0; i < p+1; i++ {
-		beta.SetVec(i, 0.0)
-	}
 
 	// 坐标下降算法
-	for iter := 0; iter < l.MaxIter; iter++ {
-		betaOld := mat.VecDenseCopyOf(beta)
-		
-		for j := 0; j < p+1; j++ {
+	coordinateDescent(XWithIntercept, y, beta, l.Lambda, 1.0, l.MaxIter, l.Tol)
+
+	// 提取截距和系数
+	l.Intercept = beta.AtVec(0)
+	l.Coefficients = mat.NewVecDense(p, nil)
+	for i := 0; i < p; i++ {
+		l.Coefficients.SetVec(i, beta.AtVec(i+1))
+	}
+
+	l.isTrained = true
+	return nil
+}
+
+// coordinateDescent 以beta为初始值（热启动）执行弹性网坐标下降，结果写回beta
+// XWithIntercept第0列为截距项且不参与正则化；l1Ratio为1时即Lasso。返回实际迭代次数
+func coordinateDescent(XWithIntercept *mat.Dense, y *mat.VecDense, beta *mat.VecDense, lambda, l1Ratio float64, maxIter int, tol float64) int {
+	n, cols := XWithIntercept.Dims()
+
+	// 预先计算 X_j^T X_j / n
+	xjNorms := make([]float64, cols)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < n; i++ {
+			xjNorms[j] += XWithIntercept.At(i, j) * XWithIntercept.At(i, j)
+		}
+		xjNorms[j] /= float64(n)
+	}
+
+	// 维护残差 r = y - X beta
+	residual := mat.NewVecDense(n, nil)
+	residual.MulVec(XWithIntercept, beta)
+	residual.SubVec(y, residual)
+
+	iter := 0
+	for iter < maxIter {
+		iter++
+		maxDiff := 0.0
+
+		for j := 0; j < cols; j++ {
+			if xjNorms[j] == 0 {
+				continue
+			}
+
 			// 对截距项不进行正则化
-			lambda := l.Lambda
+			l1, l2 := lambda*l1Ratio, lambda*(1-l1Ratio)
 			if j == 0 {
-				lambda = 0
+				l1, l2 = 0, 0
 			}
 
 			// 计算 rho = (1/n) * X_j^T (y - X_{-j} beta_{-j})
+			old := beta.AtVec(j)
 			var rho float64
 			for i := 0; i < n; i++ {
-				pred := 0.0
-				for k := 0; k < p+1; k++ {
-					if k != j {
-						pred += XWithIntercept.At(i, k) * beta.AtVec(k)
-					}
-				}
-				rho += XWithIntercept.At(i, j) * (y.At(i, 0) - pred)
+				rho += XWithIntercept.At(i, j) * (residual.AtVec(i) + XWithIntercept.At(i, j)*old)
 			}
 			rho /= float64(n)
 
-			// 计算 X_j^T X_j / n
-			xjNorm := 0.0
-			for i := 0; i < n; i++ {
-				xjNorm += XWithIntercept.At(i, j) * XWithIntercept.At(i, j)
+			// 软阈值操作
+			threshold := l1 / xjNorms[j]
+			newValue := 0.0
+			if rho > threshold {
+				newValue = (rho - threshold) / (xjNorms[j] + l2)
+			} else if rho < -threshold {
+				newValue = (rho + threshold) / (xjNorms[j] + l2)
 			}
-			xjNorm /= float64(n)
 
-			// 软阈值操作
-			if xjNorm > 0 {
-				threshold := lambda / xjNorm
-				if rho > threshold {
-					beta.SetVec(j, (rho-threshold)/xjNorm)
-				} else if rho < -threshold {
-					beta.SetVec(j, (rho+threshold)/xjNorm)
-				} else {
-					beta.SetVec(j, 0.0)
+			if delta := newValue - old; delta != 0 {
+				for i := 0; i < n; i++ {
+					residual.SetVec(i, residual.AtVec(i)-XWithIntercept.At(i, j)*delta)
 				}
+				beta.SetVec(j, newValue)
 			}
-		}
-
-		// 检查收敛性
-		maxDiff := 0.0
-		for i := 0; i < p+1; i++ {
-			diff := math.Abs(beta.AtVec(i) - betaOld.AtVec(i))
-			if diff > maxDiff {
+			if diff := math.Abs(newValue - old); diff > maxDiff {
 				maxDiff = diff
 			}
 		}
-		if maxDiff < l.Tol {
+
+		// 检查收敛性
+		if maxDiff < tol {
 			break
 		}
 	}
 
-	// 提取截距和系数
-	l.Intercept = beta.AtVec(0)
-	l.Coefficients = mat.NewVecDense(p, nil)
-	for i := 0; i < p; i++ {
-		l.Coefficients.SetVec(i, beta.AtVec(i+1))
-	}
-
-	l.isTrained = true
-	return nil
+	return iter
 }
 
 // Predict 使用训练好的模型进行预测
@@ -159,6 +183,8 @@ func (l *Lasso) GetParameters() map[string]interface{} {
 	params := make(map[string]interface{})
 	params["lambda"] = l.Lambda
 	params["intercept"] = l.Intercept
+	params["max_iter"] = l.MaxIter
+	params["tol"] = l.Tol
 	
 	if l.Coefficients != nil {
 		coeffs := make([]float64, l.Coefficients.Len())
@@ -171,6 +197,35 @@ func (l *Lasso) GetParameters() map[string]interface{} {
 	return params
 }
 
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复已训练的模型
+func (l *Lasso) SetParameters(params map[string]interface{}) error {
+	coefficients, err := requiredVector(params, "coefficients")
+	if err != nil {
+		return err
+	}
+	intercept, lambda, maxIter, tol := 0.0, l.Lambda, l.MaxIter, l.Tol
+	if err := setFloat(params, "intercept", &intercept); err != nil {
+		return err
+	}
+	if err := setFloat(params, "lambda", &lambda); err != nil {
+		return err
+	}
+	if err := setInt(params, "max_iter", &maxIter); err != nil {
+		return err
+	}
+	if err := setFloat(params, "tol", &tol); err != nil {
+		return err
+	}
+
+	l.Coefficients = coefficients
+	l.Intercept = intercept
+	l.Lambda = lambda
+	l.MaxIter = maxIter
+	l.Tol = tol
+	l.isTrained = true
+	return nil
+}
+
 // GetModelType 返回模型类型名称
 func (l *Lasso) GetModelType() string {
 	return "Lasso"
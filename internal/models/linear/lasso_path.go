@@ -0,0 +1,147 @@
+package linear
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// RegularizationPath Lasso/弹性网正则化路径，按lambda从大到小排列
+type RegularizationPath struct {
+	Lambdas      []float64   `json:"lambdas"`
+	Coefficients [][]float64 `json:"coefficients"` // 每个lambda对应的特征系数
+	Intercepts   []float64   `json:"intercepts"`
+	Iterations   []int       `json:"iterations"` // 每个lambda上坐标下降的迭代次数
+	L1Ratio      float64     `json:"l1_ratio"`
+}
+
+// PathOptions 正则化路径的配置
+type PathOptions struct {
+	Lambdas    []float64 // 指定的lambda序列，为空时自动生成
+	NumLambdas int       // 自动生成的lambda个数
+	Eps        float64   // 自动生成时最小lambda与lambda_max的比值
+	MaxIter    int
+	Tol        float64
+	Weights    *mat.VecDense // 样本权重，为nil时所有样本等权
+}
+
+// DefaultPathOptions 返回默认的路径配置
+func DefaultPathOptions() *PathOptions {
+	return &PathOptions{
+		NumLambdas: 100,
+		Eps:        1e-3,
+		MaxIter:    1000,
+		Tol:        1e-4,
+	}
+}
+
+// LassoPath 沿递减的lambda序列拟合Lasso，并利用上一个解热启动
+func LassoPath(X *mat.Dense, y *mat.VecDense, opts *PathOptions) (*RegularizationPath, error) {
+	return ElasticNetPath(X, y, 1.0, opts)
+}
+
+// ElasticNetPath 沿递减的lambda序列拟合弹性网，l1Ratio取值(0, 1]，为1时即Lasso
+func ElasticNetPath(X *mat.Dense, y *mat.VecDense, l1Ratio float64, opts *PathOptions) (*RegularizationPath, error) {
+	n, p := X.Dims()
+	if n == 0 || p == 0 {
+		return nil, fmt.Errorf("empty feature matrix")
+	}
+	if y.Len() != n {
+		return nil, fmt.Errorf("mismatched dimensions: X has %d samples, y has %d", n, y.Len())
+	}
+	if l1Ratio <= 0 || l1Ratio > 1 {
+		return nil, fmt.Errorf("l1_ratio must be in (0, 1], got %v", l1Ratio)
+	}
+	if opts == nil {
+		opts = DefaultPathOptions()
+	}
+
+	// 添加截距项
+	XWithIntercept := mat.NewDense(n, p+1, nil)
+	for i := 0; i < n; i++ {
+		XWithIntercept.Set(i, 0, 1.0)
+		for j := 0; j < p; j++ {
+			XWithIntercept.Set(i, j+1, X.At(i, j))
+		}
+	}
+	XWithIntercept, yWeighted, err := weightRows(XWithIntercept, y, opts.Weights)
+	if err != nil {
+		return nil, err
+	}
+
+	lambdas := opts.Lambdas
+	if len(lambdas) == 0 {
+		lambdas = lambdaSequence(X, y, l1Ratio, opts.NumLambdas, opts.Eps)
+	} else {
+		// 热启动要求lambda从大到小
+		lambdas = descending(lambdas)
+	}
+
+	path := &RegularizationPath{
+		Lambdas:      lambdas,
+		Coefficients: make([][]float64, len(lambdas)),
+		Intercepts:   make([]float64, len(lambdas)),
+		Iterations:   make([]int, len(lambdas)),
+		L1Ratio:      l1Ratio,
+	}
+
+	beta := mat.NewVecDense(p+1, nil)
+	for k, lambda := range lambdas {
+		path.Iterations[k] = coordinateDescent(XWithIntercept, yWeighted, beta, lambda, l1Ratio, opts.MaxIter, opts.Tol)
+		path.Intercepts[k] = beta.AtVec(0)
+		coeffs := make([]float64, p)
+		for j := 0; j < p; j++ {
+			coeffs[j] = beta.AtVec(j + 1)
+		}
+		path.Coefficients[k] = coeffs
+	}
+
+	return path, nil
+}
+
+// lambdaSequence 生成从lambda_max到eps*lambda_max的对数等间隔序列
+// lambda_max为使所有系数恰好为0的最小正则化强度
+func lambdaSequence(X *mat.Dense, y *mat.VecDense, l1Ratio float64, num int, eps float64) []float64 {
+	n, p := X.Dims()
+	if num <= 0 {
+		num = 100
+	}
+	if eps <= 0 {
+		eps = 1e-3
+	}
+
+	yMean := 0.0
+	for i := 0; i < n; i++ {
+		yMean += y.AtVec(i)
+	}
+	yMean /= float64(n)
+
+	lambdaMax := 0.0
+	for j := 0; j < p; j++ {
+		var rho, xjNorm float64
+		for i := 0; i < n; i++ {
+			rho += X.At(i, j) * (y.AtVec(i) - yMean)
+			xjNorm += X.At(i, j) * X.At(i, j)
+		}
+		rho /= float64(n)
+		xjNorm /= float64(n)
+		if v := math.Abs(rho) * xjNorm / l1Ratio; v > lambdaMax {
+			lambdaMax = v
+		}
+	}
+	if lambdaMax == 0 {
+		lambdaMax = 1
+	}
+
+	lambdas := make([]float64, num)
+	if num == 1 {
+		lambdas[0] = lambdaMax
+		return lambdas
+	}
+	logMax, logMin := math.Log(lambdaMax), math.Log(lambdaMax*eps)
+	for k := 0; k < num; k++ {
+		lambdas[k] = math.Exp(logMax + float64(k)*(logMin-logMax)/float64(num-1))
+	}
+	return lambdas
+}
@@ -1,18 +1,37 @@
 package linear
 
 import (
+	"fmt"
+	"github.com/feiyuluoye/Go-Model/internal/optimize"
 	"gonum.org/v1/gonum/mat"
 	"math"
 )
 
+// 逻辑回归的求解器
+const (
+	SolverGradientDescent = "gd"     // 固定学习率的梯度下降
+	SolverNewton          = "newton" // 牛顿法/迭代重加权最小二乘(IRLS)，无需学习率，通常数次迭代即收敛
+	SolverLBFGS           = "lbfgs"  // 有限内存BFGS，无需学习率，适合中等规模问题，为默认求解器
+)
+
 // Logistic 逻辑回归模型实现
 type Logistic struct {
 	Coefficients *mat.VecDense
 	Intercept    float64
 	MaxIter      int
 	Tol          float64
-	LearningRate float64
-	isTrained    bool
+	LearningRate float64 // 仅用于梯度下降
+	Solver       string  // 求解器，为空时使用L-BFGS
+	Iterations   int     // 训练实际使用的迭代次数
+	GradNorm     float64 // 训练结束时平均对数损失梯度的无穷范数
+	Converged    bool    // 是否在MaxIter之前满足收敛条件
+	// ClassWeight 各类别（0/1）的样本权重，未列出的类别权重为1；为nil时所有样本等权
+	ClassWeight map[float64]float64
+	// BalancedClassWeight 为true时按 n / (2 * n_c) 自动计算类别权重，忽略ClassWeight
+	BalancedClassWeight bool
+	classWeights        map[float64]float64
+	inference           *InferenceResult
+	isTrained           bool
 }
 
 // NewLogistic 创建新的逻辑回归模型
@@ -21,6 +40,7 @@ func NewLogistic() *Logistic {
 		MaxIter:      1000,
 		Tol:          1e-4,
 		LearningRate: 0.01,
+		Solver:       SolverLBFGS,
 		isTrained:    false,
 	}
 }
@@ -36,9 +56,26 @@ func sigmoid(z float64) float64 {
 	return 1.0 / (1.0 + math.Exp(-z))
 }
 
-// Fit 训练逻辑回归模型使用梯度下降
+// Fit 使用配置的求解器训练逻辑回归模型
 func (l *Logistic) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return l.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练逻辑回归模型，样本权重与类别权重相乘，sampleWeights为nil时等价于Fit
+func (l *Logistic) FitWeighted(X *mat.Dense, y *mat.VecDense, sampleWeights *mat.VecDense) error {
 	n, p := X.Dims()
+	switch l.Solver {
+	case "":
+		l.Solver = SolverLBFGS
+	case SolverLBFGS, SolverGradientDescent, SolverNewton:
+	default:
+		return fmt.Errorf("unsupported logistic solver: %s", l.Solver)
+	}
+
+	weights, totalWeight, err := l.trainingWeights(y, sampleWeights)
+	if err != nil {
+		return err
+	}
 
 	// 添加截距项
 	XWithIntercept := mat.NewDense(n, p+1, nil)
@@ -51,12 +88,40 @@ func (l *Logistic) Fit(X *mat.Dense, y *mat.VecDense) error {
 
 	// 初始化参数
 	theta := mat.NewVecDense(p+1, nil)
-	for i := 0; i < p+1; i++ {
-		theta.SetVec(i, 0.0)
+	switch l.Solver {
+	case SolverNewton:
+		if l.Iterations, l.Converged, err = l.newton(XWithIntercept, y, weights, theta); err != nil {
+			return err
+		}
+	case SolverGradientDescent:
+		l.Iterations, l.Converged = l.gradientDescent(XWithIntercept, y, weights, totalWeight, theta)
+	default:
+		if l.Iterations, l.Converged, err = l.lbfgs(XWithIntercept, y, weights, totalWeight, theta); err != nil {
+			return err
+		}
+	}
+	l.GradNorm = logLossGradient(XWithIntercept, y, weights, totalWeight, theta, nil)
+
+	// 提取截距和系数
+	l.Intercept = theta.AtVec(0)
+	l.Coefficients = mat.NewVecDense(p, nil)
+	for i := 0; i < p; i++ {
+		l.Coefficients.SetVec(i, theta.AtVec(i+1))
 	}
+	l.inference = logisticInference(XWithIntercept, y, weights, theta)
+
+	l.isTrained = true
+	return nil
+}
+
+// gradientDescent 以固定学习率执行梯度下降，结果写回theta，返回迭代次数及是否收敛
+func (l *Logistic) gradientDescent(XWithIntercept *mat.Dense, y *mat.VecDense, weights []float64, totalWeight float64, theta *mat.VecDense) (int, bool) {
+	n, cols := XWithIntercept.Dims()
+	p := cols - 1
 
-	// 梯度下降
-	for iter := 0; iter < l.MaxIter; iter++ {
+	iter := 0
+	for iter < l.MaxIter {
+		iter++
 		thetaOld := mat.VecDenseCopyOf(theta)
 
 		// 前向传播：计算预测值
@@ -69,15 +134,15 @@ func (l *Logistic) Fit(X *mat.Dense, y *mat.VecDense) error {
 			predictions.SetVec(i, sigmoid(z))
 		}
 
-		// 计算梯度
+		// 计算梯度（按样本权重加权）
 		gradient := mat.NewVecDense(p+1, nil)
 		for j := 0; j < p+1; j++ {
 			var sum float64
 			for i := 0; i < n; i++ {
 				error := predictions.At(i, 0) - y.At(i, 0)
-				sum += XWithIntercept.At(i, j) * error
+				sum += weights[i] * XWithIntercept.At(i, j) * error
 			}
-			gradient.SetVec(j, sum/float64(n))
+			gradient.SetVec(j, sum/totalWeight)
 		}
 
 		// 更新参数
@@ -92,19 +157,140 @@ func (l *Logistic) Fit(X *mat.Dense, y *mat.VecDense) error {
 			}
 		}
 		if maxDiff < l.Tol {
-			break
+			return iter, true
 		}
 	}
 
-	// 提取截距和系数
-	l.Intercept = theta.AtVec(0)
-	l.Coefficients = mat.NewVecDense(p, nil)
-	for i := 0; i < p; i++ {
-		l.Coefficients.SetVec(i, theta.AtVec(i+1))
+	return iter, false
+}
+
+// newton 使用牛顿法（IRLS）求解，结果写回theta，返回迭代次数及是否收敛
+// 每步求解 (X^T W X) delta = X^T w(y - p)，若加权对数损失上升则将步长减半
+func (l *Logistic) newton(XWithIntercept *mat.Dense, y *mat.VecDense, weights []float64, theta *mat.VecDense) (int, bool, error) {
+	n, cols := XWithIntercept.Dims()
+	loss := weightedLogLoss(XWithIntercept, y, weights, theta)
+
+	iter := 0
+	for iter < l.MaxIter {
+		iter++
+
+		// 梯度与Hessian
+		gradient := mat.NewVecDense(cols, nil)
+		hessian := mat.NewSymDense(cols, nil)
+		for i := 0; i < n; i++ {
+			row := XWithIntercept.RawRowView(i)
+			prob := sigmoid(mat.Dot(mat.NewVecDense(cols, row), theta))
+			residual := weights[i] * (y.AtVec(i) - prob)
+			curvature := weights[i] * prob * (1 - prob)
+			for j := 0; j < cols; j++ {
+				gradient.SetVec(j, gradient.AtVec(j)+row[j]*residual)
+				for k := j; k < cols; k++ {
+					hessian.SetSym(j, k, hessian.At(j, k)+curvature*row[j]*row[k])
+				}
+			}
+		}
+		// 微小的对角扰动，避免完全可分或共线数据导致Hessian奇异
+		for j := 0; j < cols; j++ {
+			hessian.SetSym(j, j, hessian.At(j, j)+1e-10)
+		}
+
+		var cholesky mat.Cholesky
+		if ok := cholesky.Factorize(hessian); !ok {
+			return iter, false, fmt.Errorf("newton solver failed: Hessian is not positive definite (data may be perfectly separable)")
+		}
+		delta := mat.NewVecDense(cols, nil)
+		if err := cholesky.SolveVecTo(delta, gradient); err != nil {
+			return iter, false, fmt.Errorf("newton solver failed: %v", err)
+		}
+
+		// 步长减半保证损失不上升
+		step := 1.0
+		candidate := mat.NewVecDense(cols, nil)
+		for halving := 0; halving < 30; halving++ {
+			candidate.AddScaledVec(theta, step, delta)
+			if newLoss := weightedLogLoss(XWithIntercept, y, weights, candidate); newLoss <= loss {
+				loss = newLoss
+				break
+			}
+			step /= 2
+		}
+		theta.CopyVec(candidate)
+
+		// 检查收敛性
+		maxDiff := 0.0
+		for j := 0; j < cols; j++ {
+			if diff := math.Abs(step * delta.AtVec(j)); diff > maxDiff {
+				maxDiff = diff
+			}
+		}
+		if maxDiff < l.Tol {
+			return iter, true, nil
+		}
 	}
 
-	l.isTrained = true
-	return nil
+	return iter, false, nil
+}
+
+// lbfgs 使用L-BFGS最小化平均加权对数损失，结果写回theta，返回迭代次数及是否收敛
+// 以梯度无穷范数小于Tol作为收敛条件
+func (l *Logistic) lbfgs(XWithIntercept *mat.Dense, y *mat.VecDense, weights []float64, totalWeight float64, theta *mat.VecDense) (int, bool, error) {
+	_, cols := XWithIntercept.Dims()
+	objective := func(x, grad []float64) float64 {
+		current := mat.NewVecDense(cols, x)
+		logLossGradient(XWithIntercept, y, weights, totalWeight, current, grad)
+		return weightedLogLoss(XWithIntercept, y, weights, current) / totalWeight
+	}
+
+	settings := optimize.DefaultLBFGSSettings()
+	settings.MaxIter = l.MaxIter
+	settings.GradTol = l.Tol
+	result, err := optimize.LBFGS(objective, mat.Col(nil, 0, theta), settings)
+	if err != nil {
+		return 0, false, fmt.Errorf("lbfgs solver failed: %v", err)
+	}
+	for j, v := range result.X {
+		theta.SetVec(j, v)
+	}
+	return result.Iterations, result.Converged, nil
+}
+
+// logLossGradient 计算平均加权对数损失的梯度，grad非nil时写入梯度，返回梯度的无穷范数
+func logLossGradient(XWithIntercept *mat.Dense, y *mat.VecDense, weights []float64, totalWeight float64, theta *mat.VecDense, grad []float64) float64 {
+	n, cols := XWithIntercept.Dims()
+	if grad == nil {
+		grad = make([]float64, cols)
+	}
+	for j := range grad {
+		grad[j] = 0
+	}
+	z := mat.NewVecDense(n, nil)
+	z.MulVec(XWithIntercept, theta)
+	for i := 0; i < n; i++ {
+		residual := weights[i] * (sigmoid(z.AtVec(i)) - y.AtVec(i)) / totalWeight
+		for j, x := range XWithIntercept.RawRowView(i) {
+			grad[j] += x * residual
+		}
+	}
+
+	norm := 0.0
+	for _, g := range grad {
+		norm = math.Max(norm, math.Abs(g))
+	}
+	return norm
+}
+
+// weightedLogLoss 计算带截距设计矩阵上的加权对数损失之和
+func weightedLogLoss(XWithIntercept *mat.Dense, y *mat.VecDense, weights []float64, theta *mat.VecDense) float64 {
+	n, _ := XWithIntercept.Dims()
+	z := mat.NewVecDense(n, nil)
+	z.MulVec(XWithIntercept, theta)
+	loss := 0.0
+	for i := 0; i < n; i++ {
+		// log(1 + exp(z)) - y*z 的数值稳定形式
+		zi := z.AtVec(i)
+		loss += weights[i] * (math.Max(zi, 0) + math.Log1p(math.Exp(-math.Abs(zi))) - y.AtVec(i)*zi)
+	}
+	return loss
 }
 
 // Predict 预测概率
@@ -140,19 +326,103 @@ func (l *Logistic) PredictClass(X *mat.Dense, threshold float64) *mat.VecDense {
 	return classifications
 }
 
-// Score 计算准确率
+// Score 计算准确率，设置了类别权重时按类别权重加权
 func (l *Logistic) Score(X *mat.Dense, y *mat.VecDense) float64 {
 	predictions := l.PredictClass(X, 0.5)
 	n, _ := y.Dims()
-	correct := 0
+	var correct, total float64
 
 	for i := 0; i < n; i++ {
+		w := l.weight(y.At(i, 0))
 		if predictions.At(i, 0) == y.At(i, 0) {
-			correct++
+			correct += w
 		}
+		total += w
+	}
+
+	return correct / total
+}
+
+// LogLoss 计算对数损失（交叉熵），设置了类别权重时按类别权重加权
+func (l *Logistic) LogLoss(X *mat.Dense, y *mat.VecDense) float64 {
+	probabilities := l.Predict(X)
+	n, _ := y.Dims()
+	const eps = 1e-15
+	var loss, total float64
+
+	for i := 0; i < n; i++ {
+		p := math.Min(math.Max(probabilities.AtVec(i), eps), 1-eps)
+		w := l.weight(y.At(i, 0))
+		loss -= w * (y.At(i, 0)*math.Log(p) + (1-y.At(i, 0))*math.Log(1-p))
+		total += w
 	}
 
-	return float64(correct) / float64(n)
+	return loss / total
+}
+
+// trainingWeights 确定类别权重，返回每个样本的训练权重（归一化的样本权重乘以类别权重）及其总和
+func (l *Logistic) trainingWeights(y *mat.VecDense, sampleWeights *mat.VecDense) ([]float64, float64, error) {
+	classWeights, err := l.resolveClassWeights(y)
+	if err != nil {
+		return nil, 0, err
+	}
+	l.classWeights = classWeights
+	n := y.Len()
+	weights, err := normalizedWeights(sampleWeights, n)
+	if err != nil {
+		return nil, 0, err
+	}
+	var totalWeight float64
+	for i := 0; i < n; i++ {
+		weights[i] *= l.weight(y.AtVec(i))
+		totalWeight += weights[i]
+	}
+	return weights, totalWeight, nil
+}
+
+// resolveClassWeights 根据训练标签确定实际使用的类别权重
+func (l *Logistic) resolveClassWeights(y *mat.VecDense) (map[float64]float64, error) {
+	counts := make(map[float64]int)
+	for i := 0; i < y.Len(); i++ {
+		label := y.AtVec(i)
+		if label != 0 && label != 1 {
+			if l.BalancedClassWeight || l.ClassWeight != nil {
+				return nil, fmt.Errorf("class weights require binary labels 0/1, got %v", label)
+			}
+			continue
+		}
+		counts[label]++
+	}
+
+	if l.BalancedClassWeight {
+		weights := make(map[float64]float64, len(counts))
+		for label, count := range counts {
+			weights[label] = float64(y.Len()) / float64(2*count)
+		}
+		return weights, nil
+	}
+	if l.ClassWeight == nil {
+		return nil, nil
+	}
+	weights := make(map[float64]float64, len(l.ClassWeight))
+	for label, w := range l.ClassWeight {
+		if label != 0 && label != 1 {
+			return nil, fmt.Errorf("class weight given for unknown class %v", label)
+		}
+		if w <= 0 {
+			return nil, fmt.Errorf("class weights must be positive, got %v for class %v", w, label)
+		}
+		weights[label] = w
+	}
+	return weights, nil
+}
+
+// weight 返回类别label的样本权重
+func (l *Logistic) weight(label float64) float64 {
+	if w, ok := l.classWeights[label]; ok {
+		return w
+	}
+	return 1.0
 }
 
 // GetParameters 返回模型参数
@@ -162,6 +432,13 @@ func (l *Logistic) GetParameters() map[string]interface{} {
 	params["max_iter"] = l.MaxIter
 	params["tol"] = l.Tol
 	params["learning_rate"] = l.LearningRate
+	params["solver"] = l.Solver
+	params["iterations"] = l.Iterations
+	params["gradient_norm"] = l.GradNorm
+	params["converged"] = l.Converged
+	if l.classWeights != nil {
+		params["class_weight"] = l.classWeights
+	}
 	
 	if l.Coefficients != nil {
 		coeffs := make([]float64, l.Coefficients.Len())
@@ -170,10 +447,72 @@ func (l *Logistic) GetParameters() map[string]interface{} {
 		}
 		params["coefficients"] = coeffs
 	}
+	if l.inference != nil {
+		params["inference"] = l.inference
+	}
 	
 	return params
 }
 
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复已训练的模型
+func (l *Logistic) SetParameters(params map[string]interface{}) error {
+	coefficients, err := requiredVector(params, "coefficients")
+	if err != nil {
+		return err
+	}
+	intercept, tol, learningRate, gradNorm := 0.0, l.Tol, l.LearningRate, l.GradNorm
+	maxIter, iterations := l.MaxIter, l.Iterations
+	for key, target := range map[string]*float64{"intercept": &intercept, "tol": &tol, "learning_rate": &learningRate, "gradient_norm": &gradNorm} {
+		if err := setFloat(params, key, target); err != nil {
+			return err
+		}
+	}
+	if err := setInt(params, "max_iter", &maxIter); err != nil {
+		return err
+	}
+	if err := setInt(params, "iterations", &iterations); err != nil {
+		return err
+	}
+	solver, converged := l.Solver, l.Converged
+	if raw, ok := params["solver"]; ok {
+		if solver, ok = raw.(string); !ok {
+			return fmt.Errorf("parameter solver must be a string, got %T", raw)
+		}
+	}
+	if raw, ok := params["converged"]; ok {
+		if converged, ok = raw.(bool); !ok {
+			return fmt.Errorf("parameter converged must be a bool, got %T", raw)
+		}
+	}
+	classWeights, err := classWeightsParam(params)
+	if err != nil {
+		return err
+	}
+	inference, err := inferenceParam(params)
+	if err != nil {
+		return err
+	}
+
+	l.Coefficients = coefficients
+	l.Intercept = intercept
+	l.MaxIter = maxIter
+	l.Tol = tol
+	l.LearningRate = learningRate
+	l.Solver = solver
+	l.Iterations = iterations
+	l.GradNorm = gradNorm
+	l.Converged = converged
+	l.classWeights = classWeights
+	l.inference = inference
+	l.isTrained = true
+	return nil
+}
+
+// Inference 返回系数的标准误、z统计量、p值和似然比检验，模型未训练或信息矩阵奇异（如完全可分）时为nil
+func (l *Logistic) Inference() *InferenceResult {
+	return l.inference
+}
+
 // GetModelType 返回模型类型名称
 func (l *Logistic) GetModelType() string {
 	return "Logistic"
@@ -0,0 +1,115 @@
+package linear
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// logisticData 生成两个特征的二分类数据，类别由带噪声的线性边界决定，数据不可分，最大似然估计唯一
+func logisticData(n int, seed int64) (*mat.Dense, *mat.VecDense) {
+	rng := rand.New(rand.NewSource(seed))
+	X := mat.NewDense(n, 2, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		a, b := rng.NormFloat64(), rng.NormFloat64()
+		X.SetRow(i, []float64{a, b})
+		if 0.5+1.5*a-b+rng.NormFloat64() > 0 {
+			y.SetVec(i, 1)
+		}
+	}
+	return X, y
+}
+
+func TestLogisticSolversAgree(t *testing.T) {
+	X, y := logisticData(300, 1)
+
+	fit := func(solver string) *Logistic {
+		t.Helper()
+		model := NewLogistic()
+		model.Solver = solver
+		model.Tol = 1e-10
+		if solver == SolverGradientDescent {
+			// 固定学习率收敛较慢，增大学习率和迭代次数使其达到同一最优点
+			model.LearningRate = 1
+			model.MaxIter = 100000
+		}
+		if err := model.Fit(X, y); err != nil {
+			t.Fatalf("%s: %v", solver, err)
+		}
+		if !model.Converged {
+			t.Fatalf("%s: did not converge after %d iterations (gradient norm %g)", solver, model.Iterations, model.GradNorm)
+		}
+		return model
+	}
+
+	reference := fit(SolverNewton)
+	if reference.Iterations > 20 {
+		t.Errorf("newton: %d iterations, want only a handful", reference.Iterations)
+	}
+	want := append([]float64{reference.Intercept}, reference.Coefficients.RawVector().Data...)
+
+	for _, solver := range []string{SolverLBFGS, SolverGradientDescent} {
+		model := fit(solver)
+		got := append([]float64{model.Intercept}, model.Coefficients.RawVector().Data...)
+		for j := range want {
+			if math.Abs(got[j]-want[j]) > 1e-4 {
+				t.Errorf("%s: coefficient %d = %.6f, newton = %.6f", solver, j, got[j], want[j])
+			}
+		}
+		if model.GradNorm > 1e-5 {
+			t.Errorf("%s: gradient norm = %g at the solution", solver, model.GradNorm)
+		}
+	}
+}
+
+func TestLogisticSolversAgreeWeighted(t *testing.T) {
+	X, y := logisticData(200, 2)
+	weights := mat.NewVecDense(200, nil)
+	for i := 0; i < 200; i++ {
+		weights.SetVec(i, float64(1+i%3))
+	}
+
+	var coefficients [][]float64
+	for _, solver := range []string{SolverNewton, SolverLBFGS} {
+		model := NewLogistic()
+		model.Solver = solver
+		model.Tol = 1e-10
+		if err := model.FitWeighted(X, y, weights); err != nil {
+			t.Fatalf("%s: %v", solver, err)
+		}
+		coefficients = append(coefficients, append([]float64{model.Intercept}, model.Coefficients.RawVector().Data...))
+	}
+	for j := range coefficients[0] {
+		if math.Abs(coefficients[0][j]-coefficients[1][j]) > 1e-4 {
+			t.Errorf("coefficient %d: newton = %.6f, lbfgs = %.6f", j, coefficients[0][j], coefficients[1][j])
+		}
+	}
+}
+
+func TestLogisticUnknownSolver(t *testing.T) {
+	X, y := logisticData(20, 3)
+	model := NewLogistic()
+	model.Solver = "sgd"
+	err := model.Fit(X, y)
+	if err == nil || !strings.Contains(err.Error(), "unsupported logistic solver") {
+		t.Fatalf("error = %v, want an unsupported solver error", err)
+	}
+	if model.isTrained {
+		t.Error("model should not be marked as trained after a solver error")
+	}
+}
+
+func TestLogisticDefaultSolver(t *testing.T) {
+	X, y := logisticData(50, 4)
+	model := &Logistic{MaxIter: 100, Tol: 1e-8}
+	if err := model.Fit(X, y); err != nil {
+		t.Fatal(err)
+	}
+	if model.Solver != SolverLBFGS {
+		t.Errorf("solver = %q, want %q when unset", model.Solver, SolverLBFGS)
+	}
+}
@@ -9,6 +9,7 @@ import (
 type OLS struct {
 	Coefficients *mat.VecDense
 	Intercept    float64
+	inference    *InferenceResult
 	isTrained    bool
 }
 
@@ -21,6 +22,11 @@ func NewOLS() *OLS {
 
 // Fit 训练OLS模型
 func (o *OLS) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return o.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练OLS模型（加权最小二乘），weights为nil时等价于Fit
+func (o *OLS) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
 	n, p := X.Dims()
 	if n == 0 || p == 0 {
 		return fmt.Errorf("empty feature matrix")
@@ -39,6 +45,10 @@ func (o *OLS) Fit(X *mat.Dense, y *mat.VecDense) error {
 			XWithIntercept.Set(i, j+1, X.At(i, j))
 		}
 	}
+	XWithIntercept, y, err := weightRows(XWithIntercept, y, weights)
+	if err != nil {
+		return err
+	}
 
 	// 使用正规方程: (X^T * X)^-1 * X^T * y
 	var XTX mat.Dense
@@ -62,6 +72,7 @@ func (o *OLS) Fit(X *mat.Dense, y *mat.VecDense) error {
 	for i := 0; i < p; i++ {
 		o.Coefficients.SetVec(i, coefficients.AtVec(i+1))
 	}
+	o.inference = linearInference(XWithIntercept, y, coefficients, 0)
 
 	o.isTrained = true
 	return nil
@@ -121,10 +132,40 @@ func (o *OLS) GetParameters() map[string]interface{} {
 		}
 		params["coefficients"] = coeffs
 	}
+	if o.inference != nil {
+		params["inference"] = o.inference
+	}
 	
 	return params
 }
 
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复已训练的模型
+func (o *OLS) SetParameters(params map[string]interface{}) error {
+	coefficients, err := requiredVector(params, "coefficients")
+	if err != nil {
+		return err
+	}
+	var intercept float64
+	if err := setFloat(params, "intercept", &intercept); err != nil {
+		return err
+	}
+	inference, err := inferenceParam(params)
+	if err != nil {
+		return err
+	}
+
+	o.Coefficients = coefficients
+	o.Intercept = intercept
+	o.inference = inference
+	o.isTrained = true
+	return nil
+}
+
+// Inference 返回系数的标准误、t统计量、p值和整体F检验，模型未训练或设计矩阵奇异时为nil
+func (o *OLS) Inference() *InferenceResult {
+	return o.inference
+}
+
 // GetModelType 返回模型类型名称
 func (o *OLS) GetModelType() string {
 	return "OLS"
@@ -0,0 +1,189 @@
+package linear
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 以下函数从GetParameters的结果中读取参数，供SetParameters使用。
+// 参数既可能是GetParameters返回的原始类型，也可能是经JSON编解码后的float64、[]interface{}和map[string]interface{}
+
+// floatValue 读取数值参数，exists为false表示参数不存在
+func floatValue(params map[string]interface{}, key string) (value float64, exists bool, err error) {
+	raw, ok := params[key]
+	if !ok || raw == nil {
+		return 0, false, nil
+	}
+	switch v := raw.(type) {
+	case float64:
+		return v, true, nil
+	case float32:
+		return float64(v), true, nil
+	case int:
+		return float64(v), true, nil
+	case int64:
+		return float64(v), true, nil
+	case json.Number:
+		f, err := v.Float64()
+		return f, true, err
+	}
+	return 0, true, fmt.Errorf("parameter %s must be a number, got %T", key, raw)
+}
+
+// setFloat 参数存在时写入target
+func setFloat(params map[string]interface{}, key string, target *float64) error {
+	value, ok, err := floatValue(params, key)
+	if ok && err == nil {
+		*target = value
+	}
+	return err
+}
+
+// setInt 参数存在时写入target，参数必须为整数
+func setInt(params map[string]interface{}, key string, target *int) error {
+	value, ok, err := floatValue(params, key)
+	if !ok || err != nil {
+		return err
+	}
+	if value != float64(int(value)) {
+		return fmt.Errorf("parameter %s must be an integer, got %v", key, value)
+	}
+	*target = int(value)
+	return nil
+}
+
+// floatSlice 读取向量参数，参数不存在时返回nil
+func floatSlice(params map[string]interface{}, key string) ([]float64, error) {
+	raw, ok := params[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	switch v := raw.(type) {
+	case []float64:
+		return append([]float64(nil), v...), nil
+	case *mat.VecDense:
+		return mat.Col(nil, 0, v), nil
+	case []interface{}:
+		values := make([]float64, len(v))
+		for i, item := range v {
+			f, ok := item.(float64)
+			if !ok {
+				return nil, fmt.Errorf("element %d of parameter %s must be a number, got %T", i, key, item)
+			}
+			values[i] = f
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("parameter %s must be a list of numbers, got %T", key, raw)
+}
+
+// requiredVector 读取不能缺少的向量参数
+func requiredVector(params map[string]interface{}, key string) (*mat.VecDense, error) {
+	values, err := floatSlice(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("missing parameter %s", key)
+	}
+	return mat.NewVecDense(len(values), values), nil
+}
+
+// denseParam 读取按行存储的矩阵参数，参数不存在时返回nil
+func denseParam(params map[string]interface{}, key string) (*mat.Dense, error) {
+	raw, ok := params[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	var rows [][]float64
+	switch v := raw.(type) {
+	case [][]float64:
+		rows = v
+	case *mat.Dense:
+		return mat.DenseCopyOf(v), nil
+	case []interface{}:
+		rows = make([][]float64, len(v))
+		for i, item := range v {
+			row, err := floatSlice(map[string]interface{}{key: item}, key)
+			if err != nil {
+				return nil, err
+			}
+			rows[i] = row
+		}
+	default:
+		return nil, fmt.Errorf("parameter %s must be a matrix, got %T", key, raw)
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return nil, fmt.Errorf("parameter %s must not be empty", key)
+	}
+	dense := mat.NewDense(len(rows), len(rows[0]), nil)
+	for i, row := range rows {
+		if len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("row %d of parameter %s has %d columns, expected %d", i, key, len(row), len(rows[0]))
+		}
+		dense.SetRow(i, row)
+	}
+	return dense, nil
+}
+
+// inferenceParam 读取统计推断结果，参数不存在时返回nil
+func inferenceParam(params map[string]interface{}) (*InferenceResult, error) {
+	raw, ok := params["inference"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	if inference, ok := raw.(*InferenceResult); ok {
+		return inference, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameter inference: %v", err)
+	}
+	var inference InferenceResult
+	if err := json.Unmarshal(data, &inference); err != nil {
+		return nil, fmt.Errorf("invalid parameter inference: %v", err)
+	}
+	return &inference, nil
+}
+
+// classWeightsParam 读取类别权重，JSON中类别以字符串作为键
+func classWeightsParam(params map[string]interface{}) (map[float64]float64, error) {
+	raw, ok := params["class_weight"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	switch v := raw.(type) {
+	case map[float64]float64:
+		weights := make(map[float64]float64, len(v))
+		for class, w := range v {
+			weights[class] = w
+		}
+		return weights, nil
+	case map[string]float64:
+		generic := make(map[string]interface{}, len(v))
+		for k, w := range v {
+			generic[k] = w
+		}
+		raw = generic
+	}
+	entries, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("parameter class_weight must be a map, got %T", raw)
+	}
+	weights := make(map[float64]float64, len(entries))
+	for k, item := range entries {
+		class, err := strconv.ParseFloat(k, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid class %q in parameter class_weight", k)
+		}
+		w, ok := item.(float64)
+		if !ok {
+			return nil, fmt.Errorf("weight of class %s must be a number, got %T", k, item)
+		}
+		weights[class] = w
+	}
+	return weights, nil
+}
@@ -1,8 +1,10 @@
 package linear
 
 import (
-	"gonum.org/v1/gonum/mat"
+	"fmt"
 	"math"
+
+	"gonum.org/v1/gonum/mat"
 )
 
 // PLS 偏最小二乘回归模型实现
@@ -27,7 +29,17 @@ func NewPLS(numComponents int) *PLS {
 
 // Fit 训练PLS模型使用NIPALS算法
 func (p *PLS) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return p.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练PLS模型，weights为nil时等价于Fit
+// 各样本行按sqrt(w_i)缩放后再执行NIPALS
+func (p *PLS) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
 	n, pDim := X.Dims()
+	X, y, err := weightRows(X, y, weights)
+	if err != nil {
+		return err
+	}
 
 	// 转换y为矩阵
 	yMatrix := mat.NewDense(n, 1, nil)
@@ -209,10 +221,52 @@ func (p *PLS) GetParameters() map[string]interface{} {
 	if p.YLoadings != nil {
 		params["y_loadings"] = denseToSlice2D(p.YLoadings)
 	}
+	if p.XLoadings != nil {
+		params["x_loadings"] = denseToSlice2D(p.XLoadings)
+	}
+	if p.YWeights != nil {
+		params["y_weights"] = denseToSlice2D(p.YWeights)
+	}
 
 	return params
 }
 
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复已训练的模型，
+// 预测需要x_weights和y_loadings，x_loadings和y_weights可以缺少
+func (p *PLS) SetParameters(params map[string]interface{}) error {
+	matrices := make(map[string]*mat.Dense)
+	for _, key := range []string{"x_weights", "y_loadings", "x_loadings", "y_weights"} {
+		m, err := denseParam(params, key)
+		if err != nil {
+			return err
+		}
+		matrices[key] = m
+	}
+	xWeights, yLoadings := matrices["x_weights"], matrices["y_loadings"]
+	if xWeights == nil || yLoadings == nil {
+		return fmt.Errorf("missing parameter x_weights or y_loadings")
+	}
+	_, components := xWeights.Dims()
+	if err := setInt(params, "num_components", &components); err != nil {
+		return err
+	}
+	if _, c := xWeights.Dims(); c != components {
+		return fmt.Errorf("x_weights has %d columns, expected %d components", c, components)
+	}
+	if r, c := yLoadings.Dims(); r != 1 || c != components {
+		return fmt.Errorf("y_loadings must be 1x%d, got %dx%d", components, r, c)
+	}
+
+	p.NumComponents = components
+	p.XWeights = xWeights
+	p.YLoadings = yLoadings
+	p.XLoadings = matrices["x_loadings"]
+	p.YWeights = matrices["y_weights"]
+	p.XScores, p.YScores = nil, nil
+	p.isTrained = true
+	return nil
+}
+
 // GetModelType 返回模型类型名称
 func (p *PLS) GetModelType() string {
 	return "PLS"
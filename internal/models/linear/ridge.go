@@ -10,6 +10,7 @@ type Ridge struct {
 	Coefficients *mat.VecDense
 	Intercept    float64
 	Lambda       float64 // 正则化参数
+	inference    *InferenceResult
 	isTrained    bool
 }
 
@@ -23,6 +24,11 @@ func NewRidge(lambda float64) *Ridge {
 
 // Fit 训练Ridge模型
 func (r *Ridge) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return r.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练Ridge模型，weights为nil时等价于Fit
+func (r *Ridge) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
 	n, p := X.Dims()
 
 	// 添加截距项
@@ -33,6 +39,10 @@ func (r *Ridge) Fit(X *mat.Dense, y *mat.VecDense) error {
 			XWithIntercept.Set(i, j+1, X.At(i, j))
 		}
 	}
+	XWithIntercept, y, err := weightRows(XWithIntercept, y, weights)
+	if err != nil {
+		return err
+	}
 
 	// 计算 X^T X + λI
 	var XTX mat.Dense
@@ -84,6 +94,7 @@ func (r *Ridge) Fit(X *mat.Dense, y *mat.VecDense) error {
 	for i := 0; i < p; i++ {
 		r.Coefficients.SetVec(i, coefficients.AtVec(i+1))
 	}
+	r.inference = linearInference(XWithIntercept, y, coefficients, r.Lambda)
 
 	r.isTrained = true
 	return nil
@@ -144,10 +155,44 @@ func (r *Ridge) GetParameters() map[string]interface{} {
 		}
 		params["coefficients"] = coeffs
 	}
+	if r.inference != nil {
+		params["inference"] = r.inference
+	}
 	
 	return params
 }
 
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复已训练的模型
+func (r *Ridge) SetParameters(params map[string]interface{}) error {
+	coefficients, err := requiredVector(params, "coefficients")
+	if err != nil {
+		return err
+	}
+	intercept, lambda := 0.0, r.Lambda
+	if err := setFloat(params, "intercept", &intercept); err != nil {
+		return err
+	}
+	if err := setFloat(params, "lambda", &lambda); err != nil {
+		return err
+	}
+	inference, err := inferenceParam(params)
+	if err != nil {
+		return err
+	}
+
+	r.Coefficients = coefficients
+	r.Intercept = intercept
+	r.Lambda = lambda
+	r.inference = inference
+	r.isTrained = true
+	return nil
+}
+
+// Inference 返回系数的近似推断结果（基于有效自由度），模型未训练或残差自由度不为正时为nil
+func (r *Ridge) Inference() *InferenceResult {
+	return r.inference
+}
+
 // GetModelType 返回模型类型名称
 func (r *Ridge) GetModelType() string {
 	return "Ridge"
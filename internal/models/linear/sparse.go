@@ -0,0 +1,394 @@
+package linear
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/feiyuluoye/Go-Model/internal/optimize"
+	"github.com/feiyuluoye/Go-Model/internal/types"
+	"gonum.org/v1/gonum/mat"
+)
+
+// checkSparseInput 检查稀疏特征矩阵与目标的维度
+func checkSparseInput(X *types.CSRMatrix, y *mat.VecDense) error {
+	if X == nil || y == nil {
+		return fmt.Errorf("features and target cannot be nil")
+	}
+	if n, _ := X.Dims(); y.Len() != n {
+		return fmt.Errorf("mismatched dimensions: %d samples but %d targets", n, y.Len())
+	}
+	return nil
+}
+
+// rowScales 返回每行的缩放系数sqrt(w_i)（权重归一化为均值1），weights为nil时为全1，
+// 与weightRows对稠密矩阵的处理一致
+func rowScales(weights *mat.VecDense, n int) ([]float64, error) {
+	scales, err := normalizedWeights(weights, n)
+	if err != nil {
+		return nil, err
+	}
+	for i, w := range scales {
+		scales[i] = math.Sqrt(w)
+	}
+	return scales, nil
+}
+
+// sparseLinearPredict 计算 intercept + X coefficients
+func sparseLinearPredict(X *types.CSRMatrix, intercept float64, coefficients *mat.VecDense) *mat.VecDense {
+	n, _ := X.Dims()
+	predictions := make([]float64, n)
+	X.MulVecTo(predictions, coefficients.RawVector().Data)
+	for i := range predictions {
+		predictions[i] += intercept
+	}
+	return mat.NewVecDense(n, predictions)
+}
+
+// FitSparse 在CSR稀疏特征上训练Ridge模型，weights为nil时所有样本等权。
+// 用Jacobi预条件共轭梯度法求解与FitWeighted相同的正规方程 (X^T W X + λI) β = X^T W y，
+// 只需矩阵向量乘法，不构造稠密的设计矩阵或 X^T X
+func (r *Ridge) FitSparse(X *types.CSRMatrix, y *mat.VecDense, weights *mat.VecDense) error {
+	if err := checkSparseInput(X, y); err != nil {
+		return err
+	}
+	n, p := X.Dims()
+	scales, err := rowScales(weights, n)
+	if err != nil {
+		return err
+	}
+	w := make([]float64, n)
+	for i, s := range scales {
+		w[i] = s * s
+	}
+
+	// 带截距的正规方程算子，beta[0]为截距（不正则化）
+	t := make([]float64, n)
+	apply := func(dst, beta []float64) {
+		X.MulVecTo(t, beta[1:])
+		dst[0] = 0
+		for i := range t {
+			t[i] = w[i] * (t[i] + beta[0])
+			dst[0] += t[i]
+		}
+		X.MulTransVecTo(dst[1:], t)
+		for j := 1; j <= p; j++ {
+			dst[j] += r.Lambda * beta[j]
+		}
+	}
+
+	rhs := make([]float64, p+1)
+	for i := range t {
+		t[i] = w[i] * y.AtVec(i)
+		rhs[0] += t[i]
+	}
+	X.MulTransVecTo(rhs[1:], t)
+
+	diag := make([]float64, p+1)
+	for i := 0; i < n; i++ {
+		diag[0] += w[i]
+		cols, values := X.RowNonZeros(i)
+		for k, j := range cols {
+			diag[j+1] += w[i] * values[k] * values[k]
+		}
+	}
+	for j := 1; j <= p; j++ {
+		diag[j] += r.Lambda
+	}
+
+	beta, err := conjugateGradient(apply, rhs, diag, max(2*(p+1), 100), 1e-10)
+	if err != nil {
+		return err
+	}
+
+	r.Intercept = beta[0]
+	r.Coefficients = mat.NewVecDense(p, beta[1:])
+	r.isTrained = true
+	return nil
+}
+
+// PredictSparse 在CSR稀疏特征上预测
+func (r *Ridge) PredictSparse(X *types.CSRMatrix) *mat.VecDense {
+	return sparseLinearPredict(X, r.Intercept, r.Coefficients)
+}
+
+// conjugateGradient 用Jacobi预条件共轭梯度法求解对称半正定方程组 A x = b，apply计算 dst = A v，
+// diag为A的对角线；残差范数小于 tol*||b|| 时收敛
+func conjugateGradient(apply func(dst, v []float64), b, diag []float64, maxIter int, tol float64) ([]float64, error) {
+	size := len(b)
+	x := make([]float64, size)
+	residual := append([]float64(nil), b...)
+	z := make([]float64, size)
+	direction := make([]float64, size)
+	product := make([]float64, size)
+
+	precondition := func() float64 {
+		rz := 0.0
+		for j := range residual {
+			z[j] = residual[j]
+			if diag[j] > 0 {
+				z[j] /= diag[j]
+			}
+			rz += residual[j] * z[j]
+		}
+		return rz
+	}
+
+	bNorm := math.Sqrt(dot(b, b))
+	if bNorm == 0 {
+		return x, nil
+	}
+	rz := precondition()
+	copy(direction, z)
+	for iter := 0; iter < maxIter; iter++ {
+		apply(product, direction)
+		curvature := dot(direction, product)
+		if curvature <= 0 {
+			break
+		}
+		alpha := rz / curvature
+		for j := range x {
+			x[j] += alpha * direction[j]
+			residual[j] -= alpha * product[j]
+		}
+		if math.Sqrt(dot(residual, residual)) <= tol*bNorm {
+			return x, nil
+		}
+		next := precondition()
+		for j := range direction {
+			direction[j] = z[j] + next/rz*direction[j]
+		}
+		rz = next
+	}
+
+	if math.Sqrt(dot(residual, residual)) <= math.Sqrt(tol)*bNorm {
+		return x, nil
+	}
+	return nil, fmt.Errorf("conjugate gradient did not converge; the system may be singular (try a larger lambda)")
+}
+
+// dot 返回两个向量的内积
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// FitSparse 在CSR稀疏特征上训练Lasso模型，weights为nil时所有样本等权。
+// 坐标下降只遍历每列的非零元素，结果与FitWeighted一致
+func (l *Lasso) FitSparse(X *types.CSRMatrix, y *mat.VecDense, weights *mat.VecDense) error {
+	if err := checkSparseInput(X, y); err != nil {
+		return err
+	}
+	n, p := X.Dims()
+	scales, err := rowScales(weights, n)
+	if err != nil {
+		return err
+	}
+
+	// 第0列为截距列，各列按sqrt(w_i)缩放
+	rows := make([][]int, p+1)
+	values := make([][]float64, p+1)
+	rows[0] = make([]int, n)
+	values[0] = make([]float64, n)
+	for i := 0; i < n; i++ {
+		rows[0][i] = i
+		values[0][i] = scales[i]
+	}
+	featureRows, featureValues := X.Columns()
+	for j := 0; j < p; j++ {
+		rows[j+1] = featureRows[j]
+		values[j+1] = featureValues[j]
+		for k, i := range featureRows[j] {
+			values[j+1][k] *= scales[i]
+		}
+	}
+	target := make([]float64, n)
+	for i := range target {
+		target[i] = scales[i] * y.AtVec(i)
+	}
+
+	beta := make([]float64, p+1)
+	sparseCoordinateDescent(rows, values, target, beta, l.Lambda, 1.0, l.MaxIter, l.Tol)
+
+	l.Intercept = beta[0]
+	l.Coefficients = mat.NewVecDense(p, beta[1:])
+	l.isTrained = true
+	return nil
+}
+
+// PredictSparse 在CSR稀疏特征上预测
+func (l *Lasso) PredictSparse(X *types.CSRMatrix) *mat.VecDense {
+	return sparseLinearPredict(X, l.Intercept, l.Coefficients)
+}
+
+// sparseCoordinateDescent 与coordinateDescent相同的弹性网坐标下降，设计矩阵按列稀疏存储：
+// 第j列的非零元素位于rows[j]行，值为values[j]；第0列为截距项且不参与正则化。返回实际迭代次数
+func sparseCoordinateDescent(rows [][]int, values [][]float64, y, beta []float64, lambda, l1Ratio float64, maxIter int, tol float64) int {
+	n, cols := len(y), len(rows)
+
+	// 预先计算 X_j^T X_j / n
+	xjNorms := make([]float64, cols)
+	for j := 0; j < cols; j++ {
+		for _, v := range values[j] {
+			xjNorms[j] += v * v
+		}
+		xjNorms[j] /= float64(n)
+	}
+
+	// 维护残差 r = y - X beta
+	residual := append([]float64(nil), y...)
+	for j := 0; j < cols; j++ {
+		for k, i := range rows[j] {
+			residual[i] -= values[j][k] * beta[j]
+		}
+	}
+
+	iter := 0
+	for iter < maxIter {
+		iter++
+		maxDiff := 0.0
+
+		for j := 0; j < cols; j++ {
+			if xjNorms[j] == 0 {
+				continue
+			}
+
+			l1, l2 := lambda*l1Ratio, lambda*(1-l1Ratio)
+			if j == 0 {
+				l1, l2 = 0, 0
+			}
+
+			old := beta[j]
+			var rho float64
+			for k, i := range rows[j] {
+				rho += values[j][k] * (residual[i] + values[j][k]*old)
+			}
+			rho /= float64(n)
+
+			threshold := l1 / xjNorms[j]
+			newValue := 0.0
+			if rho > threshold {
+				newValue = (rho - threshold) / (xjNorms[j] + l2)
+			} else if rho < -threshold {
+				newValue = (rho + threshold) / (xjNorms[j] + l2)
+			}
+
+			if delta := newValue - old; delta != 0 {
+				for k, i := range rows[j] {
+					residual[i] -= values[j][k] * delta
+				}
+				beta[j] = newValue
+			}
+			if diff := math.Abs(newValue - old); diff > maxDiff {
+				maxDiff = diff
+			}
+		}
+
+		if maxDiff < tol {
+			break
+		}
+	}
+
+	return iter
+}
+
+// FitSparse 在CSR稀疏特征上训练逻辑回归模型，支持L-BFGS和梯度下降求解器；
+// 牛顿法需要稠密的Hessian，不支持稀疏特征
+func (l *Logistic) FitSparse(X *types.CSRMatrix, y *mat.VecDense, sampleWeights *mat.VecDense) error {
+	if err := checkSparseInput(X, y); err != nil {
+		return err
+	}
+	_, p := X.Dims()
+	switch l.Solver {
+	case "":
+		l.Solver = SolverLBFGS
+	case SolverLBFGS, SolverGradientDescent:
+	case SolverNewton:
+		return fmt.Errorf("newton solver does not support sparse features; use lbfgs or gd")
+	default:
+		return fmt.Errorf("unsupported logistic solver: %s", l.Solver)
+	}
+
+	weights, totalWeight, err := l.trainingWeights(y, sampleWeights)
+	if err != nil {
+		return err
+	}
+
+	theta := make([]float64, p+1)
+	if l.Solver == SolverGradientDescent {
+		grad := make([]float64, p+1)
+		l.Iterations, l.Converged = l.MaxIter, false
+		for iter := 1; iter <= l.MaxIter; iter++ {
+			sparseLogLoss(X, y, weights, totalWeight, theta, grad)
+			maxDiff := 0.0
+			for j := range theta {
+				step := l.LearningRate * grad[j]
+				theta[j] -= step
+				maxDiff = math.Max(maxDiff, math.Abs(step))
+			}
+			if maxDiff < l.Tol {
+				l.Iterations, l.Converged = iter, true
+				break
+			}
+		}
+	} else {
+		objective := func(x, grad []float64) float64 {
+			return sparseLogLoss(X, y, weights, totalWeight, x, grad)
+		}
+		settings := optimize.DefaultLBFGSSettings()
+		settings.MaxIter = l.MaxIter
+		settings.GradTol = l.Tol
+		result, err := optimize.LBFGS(objective, theta, settings)
+		if err != nil {
+			return fmt.Errorf("lbfgs solver failed: %v", err)
+		}
+		theta = result.X
+		l.Iterations, l.Converged = result.Iterations, result.Converged
+	}
+
+	grad := make([]float64, p+1)
+	sparseLogLoss(X, y, weights, totalWeight, theta, grad)
+	l.GradNorm = 0
+	for _, g := range grad {
+		l.GradNorm = math.Max(l.GradNorm, math.Abs(g))
+	}
+
+	l.Intercept = theta[0]
+	l.Coefficients = mat.NewVecDense(p, theta[1:])
+	l.isTrained = true
+	return nil
+}
+
+// sparseLogLoss 计算稀疏特征上的平均加权对数损失，grad非nil时写入梯度；theta[0]为截距
+func sparseLogLoss(X *types.CSRMatrix, y *mat.VecDense, weights []float64, totalWeight float64, theta, grad []float64) float64 {
+	n, _ := X.Dims()
+	z := make([]float64, n)
+	X.MulVecTo(z, theta[1:])
+
+	loss := 0.0
+	residual := make([]float64, n)
+	for i := range z {
+		zi := z[i] + theta[0]
+		loss += weights[i] * (math.Max(zi, 0) + math.Log1p(math.Exp(-math.Abs(zi))) - y.AtVec(i)*zi)
+		residual[i] = weights[i] * (sigmoid(zi) - y.AtVec(i)) / totalWeight
+	}
+	if grad != nil {
+		grad[0] = 0
+		for _, r := range residual {
+			grad[0] += r
+		}
+		X.MulTransVecTo(grad[1:], residual)
+	}
+	return loss / totalWeight
+}
+
+// PredictSparse 在CSR稀疏特征上预测概率
+func (l *Logistic) PredictSparse(X *types.CSRMatrix) *mat.VecDense {
+	probabilities := sparseLinearPredict(X, l.Intercept, l.Coefficients)
+	for i := 0; i < probabilities.Len(); i++ {
+		probabilities.SetVec(i, sigmoid(probabilities.AtVec(i)))
+	}
+	return probabilities
+}
@@ -0,0 +1,86 @@
+package linear
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// checkWeights 检查样本权重：长度与样本数一致、有限非负且不全为0
+func checkWeights(weights *mat.VecDense, n int) error {
+	if weights.Len() != n {
+		return fmt.Errorf("mismatched dimensions: %d weights for %d samples", weights.Len(), n)
+	}
+	total := 0.0
+	for i := 0; i < n; i++ {
+		w := weights.AtVec(i)
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return fmt.Errorf("sample weights must be finite and non-negative, got %v", w)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("sample weights must not all be zero")
+	}
+	return nil
+}
+
+// normalizedWeights 将样本权重归一化为均值1，使正则化强度与未加权时可比
+// weights为nil时返回全1权重
+func normalizedWeights(weights *mat.VecDense, n int) ([]float64, error) {
+	normalized := make([]float64, n)
+	if weights == nil {
+		for i := range normalized {
+			normalized[i] = 1.0
+		}
+		return normalized, nil
+	}
+	if err := checkWeights(weights, n); err != nil {
+		return nil, err
+	}
+	total := 0.0
+	for i := 0; i < n; i++ {
+		total += weights.AtVec(i)
+	}
+	for i := range normalized {
+		normalized[i] = weights.AtVec(i) * float64(n) / total
+	}
+	return normalized, nil
+}
+
+// weightRows 将设计矩阵和目标的每行乘以sqrt(w_i)，使普通最小二乘等价于加权最小二乘
+// weights为nil时原样返回
+func weightRows(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) (*mat.Dense, *mat.VecDense, error) {
+	if weights == nil {
+		return X, y, nil
+	}
+	n, p := X.Dims()
+	w, err := normalizedWeights(weights, n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	XWeighted := mat.NewDense(n, p, nil)
+	yWeighted := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		s := math.Sqrt(w[i])
+		for j := 0; j < p; j++ {
+			XWeighted.Set(i, j, s*X.At(i, j))
+		}
+		yWeighted.SetVec(i, s*y.AtVec(i))
+	}
+	return XWeighted, yWeighted, nil
+}
+
+// subsetWeights 按索引提取样本权重，weights为nil时返回nil
+func subsetWeights(weights *mat.VecDense, indices []int) *mat.VecDense {
+	if weights == nil {
+		return nil
+	}
+	subset := mat.NewVecDense(len(indices), nil)
+	for i, idx := range indices {
+		subset.SetVec(i, weights.AtVec(idx))
+	}
+	return subset
+}
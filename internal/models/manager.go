@@ -2,8 +2,12 @@ package models
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
 
+	"github.com/feiyuluoye/Go-Model/internal/models/calibration"
+	"github.com/feiyuluoye/Go-Model/internal/models/ensemble"
+	"github.com/feiyuluoye/Go-Model/internal/models/linear"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -24,62 +28,169 @@ func NewModelManager() *ModelManager {
 
 // CreateModel 创建模型
 func (mm *ModelManager) CreateModel(config *ModelConfig) (Model, error) {
+	params := config.Parameters
 	switch config.ModelType {
 	case "ols":
 		return NewOLS(), nil
 	case "ridge":
-		alpha := 1.0
-		if param, ok := config.Parameters["alpha"]; ok {
-			if a, ok := param.(float64); ok {
-				alpha = a
-			}
-		}
-		return NewRidge(alpha), nil
+		return NewRidge(floatParam(params, 1.0, "lambda", "alpha")), nil
 	case "lasso":
-		alpha := 1.0
-		if param, ok := config.Parameters["alpha"]; ok {
-			if a, ok := param.(float64); ok {
-				alpha = a
-			}
-		}
-		return NewLasso(alpha), nil
+		lasso := linear.NewLasso(floatParam(params, 1.0, "lambda", "alpha"))
+		lasso.MaxIter = intParam(params, lasso.MaxIter, "max_iterations", "max_iter")
+		lasso.Tol = floatParam(params, lasso.Tol, "tolerance", "tol")
+		return lasso, nil
+	case "ridge_cv":
+		ridgeCV := linear.NewRidgeCV(floatSliceParam(params, "lambdas", "alphas"))
+		ridgeCV.Folds = intParam(params, ridgeCV.Folds, "cv_folds", "folds")
+		ridgeCV.RandomSeed = int64(intParam(params, int(ridgeCV.RandomSeed), "random_seed"))
+		return ridgeCV, nil
+	case "lasso_cv":
+		lassoCV := linear.NewLassoCV(floatSliceParam(params, "lambdas", "alphas"))
+		lassoCV.NumLambdas = intParam(params, lassoCV.NumLambdas, "num_lambdas")
+		lassoCV.Folds = intParam(params, lassoCV.Folds, "cv_folds", "folds")
+		lassoCV.RandomSeed = int64(intParam(params, int(lassoCV.RandomSeed), "random_seed"))
+		lassoCV.MaxIter = intParam(params, lassoCV.MaxIter, "max_iterations", "max_iter")
+		lassoCV.Tol = floatParam(params, lassoCV.Tol, "tolerance", "tol")
+		return lassoCV, nil
 	case "logistic":
-		return NewLogistic(), nil
-	case "pls":
-		numComponents := 2
-		if param, ok := config.Parameters["num_components"]; ok {
-			if n, ok := param.(int); ok {
-				numComponents = n
+		logistic := linear.NewLogistic()
+		logistic.LearningRate = floatParam(params, logistic.LearningRate, "learning_rate")
+		logistic.MaxIter = intParam(params, logistic.MaxIter, "max_iterations", "max_iter")
+		logistic.Tol = floatParam(params, logistic.Tol, "tolerance", "tol")
+		if solver, ok := params["solver"].(string); ok {
+			if solver == "irls" {
+				solver = linear.SolverNewton
 			}
+			logistic.Solver = solver
 		}
-		return NewPLS(numComponents), nil
-	case "polynomial":
-		degree := 2
-		if param, ok := config.Parameters["degree"]; ok {
-			if d, ok := param.(int); ok {
-				degree = d
+		if value, ok := params["class_weight"]; ok {
+			if value == "balanced" {
+				logistic.BalancedClassWeight = true
+			} else if weights, ok := classWeightParam(value); ok {
+				logistic.ClassWeight = weights
+			} else {
+				return nil, ModelError{
+					Code:    ErrorCodeInvalidInput,
+					Message: "无效的class_weight参数，应为\"balanced\"或类别到权重的映射",
+				}
 			}
 		}
-		return NewPolynomial(degree), nil
+		return logistic, nil
+	case "pls":
+		return NewPLS(intParam(params, 2, "components", "num_components")), nil
+	case "polynomial":
+		return NewPolynomial(intParam(params, 2, "degree")), nil
 	case "exponential":
 		return NewExponential(), nil
 	case "logarithmic":
 		return NewLogarithmic(), nil
 	case "power":
 		return NewPower(), nil
+	case "stacking":
+		baseSpecs, err := modelSpecsParam(params, "base_models", "estimators")
+		if err != nil {
+			return nil, err
+		}
+		metaSpec := ensemble.ModelSpec{ModelType: "ridge", Parameters: map[string]interface{}{"lambda": 1.0}}
+		if specs, err := modelSpecsParam(params, "meta_model", "final_estimator"); err != nil {
+			return nil, err
+		} else if len(specs) > 0 {
+			metaSpec = specs[0]
+		}
+		stacking := ensemble.NewStacking(mm.factory, baseSpecs, metaSpec)
+		stacking.Folds = intParam(params, stacking.Folds, "cv_folds", "folds")
+		stacking.RandomSeed = int64(intParam(params, int(stacking.RandomSeed), "random_seed"))
+		stacking.Passthrough, _ = params["passthrough"].(bool)
+		return stacking, nil
+	case "voting":
+		specs, err := modelSpecsParam(params, "models", "estimators")
+		if err != nil {
+			return nil, err
+		}
+		mode, _ := params["voting"].(string)
+		return ensemble.NewVoting(mm.factory, specs, floatSliceParam(params, "weights"), mode), nil
+	case "bagging":
+		baseSpec := ensemble.ModelSpec{ModelType: "ols"}
+		if specs, err := modelSpecsParam(params, "base_model", "estimator"); err != nil {
+			return nil, err
+		} else if len(specs) > 0 {
+			baseSpec = specs[0]
+		}
+		bagging := ensemble.NewBagging(mm.factory, baseSpec)
+		bagging.NEstimators = intParam(params, bagging.NEstimators, "n_estimators")
+		bagging.MaxSamples = floatParam(params, bagging.MaxSamples, "max_samples")
+		bagging.MaxFeatures = floatParam(params, bagging.MaxFeatures, "max_features")
+		bagging.BootstrapFeatures, _ = params["bootstrap_features"].(bool)
+		bagging.RandomSeed = int64(intParam(params, int(bagging.RandomSeed), "random_seed"))
+		bagging.NJobs = intParam(params, bagging.NJobs, "n_jobs")
+		return bagging, nil
+	case "calibrated":
+		baseSpec := ensemble.ModelSpec{ModelType: "logistic"}
+		if specs, err := modelSpecsParam(params, "base_model", "estimator"); err != nil {
+			return nil, err
+		} else if len(specs) > 0 {
+			baseSpec = specs[0]
+		}
+		method, _ := params["method"].(string)
+		calibrated := calibration.NewCalibratedClassifier(mm.factory, baseSpec, method)
+		calibrated.CalibrationSize = floatParam(params, calibrated.CalibrationSize, "calibration_size")
+		calibrated.RandomSeed = int64(intParam(params, int(calibrated.RandomSeed), "random_seed"))
+		return calibrated, nil
 	default:
 		return nil, ModelError{
 			Code:    ErrorCodeInvalidInput,
 			Message: fmt.Sprintf("不支持的模型类型: %s", config.ModelType),
 			Details: map[string]interface{}{
-				"supported_models": []string{"ols", "ridge", "lasso", "logistic", "pls", "polynomial", "exponential", "logarithmic", "power"},
+				"supported_models": []string{"ols", "ridge", "lasso", "ridge_cv", "lasso_cv", "logistic", "pls", "polynomial", "exponential", "logarithmic", "power", "stacking", "voting", "bagging", "calibrated"},
 			},
 		}
 	}
 }
 
+// factory 为集成模型创建成员模型
+func (mm *ModelManager) factory(spec ensemble.ModelSpec) (ensemble.Model, error) {
+	return mm.CreateModel(&ModelConfig{ModelType: spec.ModelType, Parameters: spec.Parameters})
+}
+
+// CreateVotingEnsemble 将已训练的模型组合为投票集成并注册为新模型，返回其ID
+// mode为"average"时对预测加权平均（回归），为"majority"时加权多数投票（分类）
+func (mm *ModelManager) CreateVotingEnsemble(memberIDs []string, weights []float64, mode string) (string, error) {
+	members := make([]ensemble.Model, len(memberIDs))
+	for i, modelID := range memberIDs {
+		model, exists := mm.getModel(modelID)
+		if !exists {
+			return "", ModelError{
+				Code:    ErrorCodeModelNotFound,
+				Message: fmt.Sprintf("模型不存在: %s", modelID),
+				Details: map[string]interface{}{
+					"model_id": modelID,
+				},
+			}
+		}
+		members[i] = model
+	}
+
+	voting, err := ensemble.NewVotingFromModels(members, weights, mode)
+	if err != nil {
+		return "", ModelError{
+			Code:    ErrorCodeInvalidInput,
+			Message: fmt.Sprintf("创建集成模型失败: %v", err),
+			Details: map[string]interface{}{
+				"member_ids": memberIDs,
+			},
+		}
+	}
+
+	return mm.addModel(voting), nil
+}
+
 // TrainModel 训练模型
 func (mm *ModelManager) TrainModel(config *ModelConfig, X *mat.Dense, y *mat.VecDense) (*TrainingResult, error) {
+	return mm.TrainModelWeighted(config, X, y, nil)
+}
+
+// TrainModelWeighted 使用样本权重训练模型，weights为nil时等价于TrainModel
+func (mm *ModelManager) TrainModelWeighted(config *ModelConfig, X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) (*TrainingResult, error) {
 	// 创建模型
 	model, err := mm.CreateModel(config)
 	if err != nil {
@@ -87,7 +198,7 @@ func (mm *ModelManager) TrainModel(config *ModelConfig, X *mat.Dense, y *mat.Vec
 	}
 
 	// 训练模型
-	if err := model.Fit(X, y); err != nil {
+	if err := model.FitWeighted(X, y, weights); err != nil {
 		return nil, ModelError{
 			Code:    ErrorCodeTrainingFailed,
 			Message: fmt.Sprintf("模型训练失败: %v", err),
@@ -191,6 +302,21 @@ func (mm *ModelManager) GetModelInfo(modelID string) (*ModelInfo, error) {
 	}, nil
 }
 
+// GetModel 获取已训练的模型实例，调用方不应再对其调用Fit
+func (mm *ModelManager) GetModel(modelID string) (Model, error) {
+	model, exists := mm.getModel(modelID)
+	if !exists {
+		return nil, ModelError{
+			Code:    ErrorCodeModelNotFound,
+			Message: fmt.Sprintf("模型不存在: %s", modelID),
+			Details: map[string]interface{}{
+				"model_id": modelID,
+			},
+		}
+	}
+	return model, nil
+}
+
 // 内部方法：添加模型
 func (mm *ModelManager) addModel(model Model) string {
 	mm.mu.Lock()
@@ -202,6 +328,45 @@ func (mm *ModelManager) addModel(model Model) string {
 	return modelID
 }
 
+// RegisterModel 以指定ID保存已训练的模型（如从模型仓库加载的模型），ID已存在时返回错误。
+// 形如model_N的ID会同时被预留，之后训练的模型不会与之重复
+func (mm *ModelManager) RegisterModel(modelID string, model Model) error {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	if _, exists := mm.models[modelID]; exists {
+		return ModelError{
+			Code:    ErrorCodeInvalidInput,
+			Message: fmt.Sprintf("模型已存在: %s", modelID),
+		}
+	}
+	mm.models[modelID] = model
+	mm.reserveID(modelID)
+	return nil
+}
+
+// RemoveModel 删除模型，模型不存在时不做任何操作
+func (mm *ModelManager) RemoveModel(modelID string) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	delete(mm.models, modelID)
+}
+
+// ReserveModelID 预留已在别处使用的模型ID（如模型仓库中已保存的模型），之后训练的模型不会使用该ID
+func (mm *ModelManager) ReserveModelID(modelID string) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.reserveID(modelID)
+}
+
+// reserveID 使nextID大于形如model_N的ID中的N，调用方需持有写锁
+func (mm *ModelManager) reserveID(modelID string) {
+	var n int
+	if _, err := fmt.Sscanf(modelID, "model_%d", &n); err == nil && fmt.Sprintf("model_%d", n) == modelID && n >= mm.nextID {
+		mm.nextID = n + 1
+	}
+}
+
 // 内部方法：获取模型
 func (mm *ModelManager) getModel(modelID string) (Model, bool) {
 	mm.mu.RLock()
@@ -210,3 +375,163 @@ func (mm *ModelManager) getModel(modelID string) (Model, bool) {
 	model, exists := mm.models[modelID]
 	return model, exists
 }
+
+// floatParam 按顺序查找参数名并转换为float64，均不存在时返回默认值
+func floatParam(params map[string]interface{}, defaultValue float64, keys ...string) float64 {
+	for _, key := range keys {
+		if v, ok := toFloat(params[key]); ok {
+			return v
+		}
+	}
+	return defaultValue
+}
+
+// toFloat 将数值类型的参数转换为float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// intParam 按顺序查找参数名并转换为int，均不存在时返回默认值
+func intParam(params map[string]interface{}, defaultValue int, keys ...string) int {
+	for _, key := range keys {
+		switch v := params[key].(type) {
+		case int:
+			return v
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		case float32:
+			return int(v)
+		}
+	}
+	return defaultValue
+}
+
+// floatSliceParam 按顺序查找参数名并转换为[]float64，均不存在时返回nil
+func floatSliceParam(params map[string]interface{}, keys ...string) []float64 {
+	for _, key := range keys {
+		switch v := params[key].(type) {
+		case []float64:
+			return v
+		case []interface{}:
+			values := make([]float64, 0, len(v))
+			for _, item := range v {
+				if f, ok := toFloat(item); ok {
+					values = append(values, f)
+				}
+			}
+			return values
+		}
+	}
+	return nil
+}
+
+// classWeightParam 将类别权重参数转换为map[float64]float64
+// 支持以数值或字符串（如JSON中的"0"、"1"）为键的映射
+func classWeightParam(value interface{}) (map[float64]float64, bool) {
+	switch v := value.(type) {
+	case map[float64]float64:
+		return v, true
+	case map[int]float64:
+		weights := make(map[float64]float64, len(v))
+		for label, w := range v {
+			weights[float64(label)] = w
+		}
+		return weights, true
+	case map[string]float64:
+		weights := make(map[float64]float64, len(v))
+		for label, w := range v {
+			l, err := strconv.ParseFloat(label, 64)
+			if err != nil {
+				return nil, false
+			}
+			weights[l] = w
+		}
+		return weights, true
+	case map[string]interface{}:
+		weights := make(map[float64]float64, len(v))
+		for label, item := range v {
+			l, err := strconv.ParseFloat(label, 64)
+			w, ok := toFloat(item)
+			if err != nil || !ok {
+				return nil, false
+			}
+			weights[l] = w
+		}
+		return weights, true
+	}
+	return nil, false
+}
+
+// modelSpecsParam 解析嵌套的模型配置，支持单个或列表形式的*ModelConfig、ModelConfig
+// 以及包含model_type和parameters键的map
+func modelSpecsParam(params map[string]interface{}, keys ...string) ([]ensemble.ModelSpec, error) {
+	for _, key := range keys {
+		value, ok := params[key]
+		if !ok {
+			continue
+		}
+
+		var items []interface{}
+		switch v := value.(type) {
+		case []interface{}:
+			items = v
+		case []map[string]interface{}:
+			for _, item := range v {
+				items = append(items, item)
+			}
+		case []*ModelConfig:
+			for _, item := range v {
+				items = append(items, item)
+			}
+		default:
+			items = []interface{}{v}
+		}
+
+		specs := make([]ensemble.ModelSpec, 0, len(items))
+		for _, item := range items {
+			spec, ok := modelSpec(item)
+			if !ok {
+				return nil, ModelError{
+					Code:    ErrorCodeInvalidInput,
+					Message: fmt.Sprintf("无效的嵌套模型配置: %s", key),
+				}
+			}
+			specs = append(specs, spec)
+		}
+		return specs, nil
+	}
+	return nil, nil
+}
+
+// modelSpec 将单个嵌套模型配置转换为ensemble.ModelSpec
+func modelSpec(value interface{}) (ensemble.ModelSpec, bool) {
+	switch v := value.(type) {
+	case *ModelConfig:
+		if v == nil {
+			return ensemble.ModelSpec{}, false
+		}
+		return ensemble.ModelSpec{ModelType: v.ModelType, Parameters: v.Parameters}, true
+	case ModelConfig:
+		return ensemble.ModelSpec{ModelType: v.ModelType, Parameters: v.Parameters}, true
+	case map[string]interface{}:
+		modelType, ok := v["model_type"].(string)
+		if !ok || modelType == "" {
+			return ensemble.ModelSpec{}, false
+		}
+		parameters, _ := v["parameters"].(map[string]interface{})
+		return ensemble.ModelSpec{ModelType: modelType, Parameters: parameters}, true
+	}
+	return ensemble.ModelSpec{}, false
+}
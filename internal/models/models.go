@@ -20,6 +20,14 @@ func NewLasso(lambda float64) Model {
 	return linear.NewLasso(lambda)
 }
 
+func NewRidgeCV(lambdas []float64) Model {
+	return linear.NewRidgeCV(lambdas)
+}
+
+func NewLassoCV(lambdas []float64) Model {
+	return linear.NewLassoCV(lambdas)
+}
+
 func NewLogistic() Model {
 	return linear.NewLogistic()
 }
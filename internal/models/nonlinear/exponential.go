@@ -22,6 +22,11 @@ func NewExponential() *Exponential {
 
 // Fit 训练指数回归模型使用线性化
 func (e *Exponential) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return e.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练指数回归模型（在线性化空间加权），weights为nil时等价于Fit
+func (e *Exponential) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
 	n, cols := X.Dims()
 	if cols != 1 {
 		return fmt.Errorf("exponential regression requires single feature input")
@@ -46,6 +51,10 @@ func (e *Exponential) Fit(X *mat.Dense, y *mat.VecDense) error {
 		XDesign.Set(i, 0, 1.0)           // 截距项
 		XDesign.Set(i, 1, X.At(i, 0))   // x值
 	}
+	XDesign, lnY, err := weightRows(XDesign, lnY, weights)
+	if err != nil {
+		return err
+	}
 
 	// 求解正规方程：beta = (X^T X)^-1 X^T lnY
 	var XTX mat.Dense
@@ -120,6 +129,15 @@ func (e *Exponential) GetParameters() map[string]interface{} {
 	return params
 }
 
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复已训练的模型
+func (e *Exponential) SetParameters(params map[string]interface{}) error {
+	if err := setAB(params, &e.A, &e.B); err != nil {
+		return err
+	}
+	e.isTrained = true
+	return nil
+}
+
 // GetModelType 返回模型类型名称
 func (e *Exponential) GetModelType() string {
 	return "Exponential"
@@ -22,6 +22,11 @@ func NewLogarithmic() *Logarithmic {
 
 // Fit 训练对数回归模型
 func (l *Logarithmic) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return l.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练对数回归模型，weights为nil时等价于Fit
+func (l *Logarithmic) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
 	n, cols := X.Dims()
 	if cols != 1 {
 		return fmt.Errorf("logarithmic regression requires single feature input")
@@ -40,6 +45,10 @@ func (l *Logarithmic) Fit(X *mat.Dense, y *mat.VecDense) error {
 		XDesign.Set(i, 0, math.Log(X.At(i, 0))) // ln(x)
 		XDesign.Set(i, 1, 1.0)                  // 截距项
 	}
+	XDesign, y, err := weightRows(XDesign, y, weights)
+	if err != nil {
+		return err
+	}
 
 	// 求解正规方程：beta = (X^T X)^-1 X^T y
 	var XTX mat.Dense
@@ -117,6 +126,15 @@ func (l *Logarithmic) GetParameters() map[string]interface{} {
 	return params
 }
 
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复已训练的模型
+func (l *Logarithmic) SetParameters(params map[string]interface{}) error {
+	if err := setAB(params, &l.A, &l.B); err != nil {
+		return err
+	}
+	l.isTrained = true
+	return nil
+}
+
 // GetModelType 返回模型类型名称
 func (l *Logarithmic) GetModelType() string {
 	return "Logarithmic"
@@ -0,0 +1,69 @@
+package nonlinear
+
+import (
+	"fmt"
+)
+
+// 以下函数从GetParameters的结果中读取参数，供SetParameters使用。
+// 参数既可能是GetParameters返回的原始类型，也可能是经JSON编解码后的float64和[]interface{}
+
+// requiredFloat 读取不能缺少的数值参数
+func requiredFloat(params map[string]interface{}, key string) (float64, error) {
+	raw, ok := params[key]
+	if !ok || raw == nil {
+		return 0, fmt.Errorf("missing parameter %s", key)
+	}
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+	return 0, fmt.Errorf("parameter %s must be a number, got %T", key, raw)
+}
+
+// requiredFloats 读取不能缺少的向量参数
+func requiredFloats(params map[string]interface{}, key string) ([]float64, error) {
+	raw, ok := params[key]
+	if !ok || raw == nil {
+		return nil, fmt.Errorf("missing parameter %s", key)
+	}
+	var values []float64
+	switch v := raw.(type) {
+	case []float64:
+		values = append([]float64(nil), v...)
+	case []interface{}:
+		values = make([]float64, len(v))
+		for i, item := range v {
+			f, ok := item.(float64)
+			if !ok {
+				return nil, fmt.Errorf("element %d of parameter %s must be a number, got %T", i, key, item)
+			}
+			values[i] = f
+		}
+	default:
+		return nil, fmt.Errorf("parameter %s must be a list of numbers, got %T", key, raw)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("parameter %s must not be empty", key)
+	}
+	return values, nil
+}
+
+// setAB 读取形如 y = f(a, b, x) 的模型的参数a和b
+func setAB(params map[string]interface{}, a, b *float64) error {
+	valueA, err := requiredFloat(params, "a")
+	if err != nil {
+		return err
+	}
+	valueB, err := requiredFloat(params, "b")
+	if err != nil {
+		return err
+	}
+	*a, *b = valueA, valueB
+	return nil
+}
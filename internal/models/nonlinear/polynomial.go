@@ -23,6 +23,11 @@ func NewPolynomial(degree int) *Polynomial {
 
 // Fit 训练多项式回归模型
 func (p *Polynomial) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return p.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练多项式回归模型，weights为nil时等价于Fit
+func (p *Polynomial) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
 	n, cols := X.Dims()
 	if cols != 1 {
 		return fmt.Errorf("polynomial regression requires single feature input")
@@ -36,6 +41,10 @@ func (p *Polynomial) Fit(X *mat.Dense, y *mat.VecDense) error {
 			XPoly.Set(i, j, math.Pow(x, float64(j)))
 		}
 	}
+	XPoly, y, err := weightRows(XPoly, y, weights)
+	if err != nil {
+		return err
+	}
 
 	// 求解正规方程：beta = (X^T X)^-1 X^T y
 	var XTX mat.Dense
@@ -118,6 +127,29 @@ func (p *Polynomial) GetParameters() map[string]interface{} {
 	return params
 }
 
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复已训练的模型，系数个数为degree+1
+func (p *Polynomial) SetParameters(params map[string]interface{}) error {
+	coefficients, err := requiredFloats(params, "coefficients")
+	if err != nil {
+		return err
+	}
+	degree := len(coefficients) - 1
+	if _, ok := params["degree"]; ok {
+		value, err := requiredFloat(params, "degree")
+		if err != nil {
+			return err
+		}
+		if value != float64(degree) {
+			return fmt.Errorf("degree %v does not match %d coefficients", value, len(coefficients))
+		}
+	}
+
+	p.Degree = degree
+	p.Coefficients = mat.NewVecDense(len(coefficients), coefficients)
+	p.isTrained = true
+	return nil
+}
+
 // GetModelType 返回模型类型名称
 func (p *Polynomial) GetModelType() string {
 	return "Polynomial"
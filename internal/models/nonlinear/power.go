@@ -22,6 +22,11 @@ func NewPower() *Power {
 
 // Fit 训练幂回归模型使用线性化
 func (p *Power) Fit(X *mat.Dense, y *mat.VecDense) error {
+	return p.FitWeighted(X, y, nil)
+}
+
+// FitWeighted 使用样本权重训练幂回归模型（在线性化空间加权），weights为nil时等价于Fit
+func (p *Power) FitWeighted(X *mat.Dense, y *mat.VecDense, weights *mat.VecDense) error {
 	n, cols := X.Dims()
 	if cols != 1 {
 		return fmt.Errorf("power regression requires single feature input")
@@ -51,6 +56,10 @@ func (p *Power) Fit(X *mat.Dense, y *mat.VecDense) error {
 		XDesign.Set(i, 0, 1.0)           // 截距项
 		XDesign.Set(i, 1, lnX.At(i, 0))  // ln(x)
 	}
+	XDesign, lnY, err := weightRows(XDesign, lnY, weights)
+	if err != nil {
+		return err
+	}
 
 	// 求解正规方程：beta = (X^T X)^-1 X^T lnY
 	var XTX mat.Dense
@@ -128,6 +137,15 @@ func (p *Power) GetParameters() map[string]interface{} {
 	return params
 }
 
+// SetParameters 由GetParameters的结果（可以经过JSON编解码）恢复已训练的模型
+func (p *Power) SetParameters(params map[string]interface{}) error {
+	if err := setAB(params, &p.A, &p.B); err != nil {
+		return err
+	}
+	p.isTrained = true
+	return nil
+}
+
 // GetModelType 返回模型类型名称
 func (p *Power) GetModelType() string {
 	return "Power"
@@ -0,0 +1,40 @@
+package nonlinear
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// weightRows 将设计矩阵和目标的每行乘以sqrt(w_i)，使正规方程的解即为加权最小二乘解
+// weights为nil时原样返回
+func weightRows(XDesign *mat.Dense, y *mat.VecDense, weights *mat.VecDense) (*mat.Dense, *mat.VecDense, error) {
+	if weights == nil {
+		return XDesign, y, nil
+	}
+	n, p := XDesign.Dims()
+	if weights.Len() != n {
+		return nil, nil, fmt.Errorf("mismatched dimensions: %d weights for %d samples", weights.Len(), n)
+	}
+
+	XWeighted := mat.NewDense(n, p, nil)
+	yWeighted := mat.NewVecDense(n, nil)
+	total := 0.0
+	for i := 0; i < n; i++ {
+		w := weights.AtVec(i)
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, nil, fmt.Errorf("sample weights must be finite and non-negative, got %v", w)
+		}
+		total += w
+		s := math.Sqrt(w)
+		for j := 0; j < p; j++ {
+			XWeighted.Set(i, j, s*XDesign.At(i, j))
+		}
+		yWeighted.SetVec(i, s*y.AtVec(i))
+	}
+	if total == 0 {
+		return nil, nil, fmt.Errorf("sample weights must not all be zero")
+	}
+	return XWeighted, yWeighted, nil
+}
@@ -0,0 +1,209 @@
+package optimize
+
+import (
+	"errors"
+	"math"
+)
+
+// Objective 可微目标函数：返回x处的函数值，并将梯度写入grad（长度与x相同）
+type Objective func(x, grad []float64) float64
+
+// LBFGSSettings L-BFGS的配置
+type LBFGSSettings struct {
+	Memory  int     // 保存的历史修正对数量
+	MaxIter int     // 最大迭代次数
+	GradTol float64 // 梯度无穷范数小于该值时视为收敛
+	FuncTol float64 // 相邻两次函数值的相对变化小于该值时视为收敛
+}
+
+// DefaultLBFGSSettings 返回默认的L-BFGS配置
+func DefaultLBFGSSettings() *LBFGSSettings {
+	return &LBFGSSettings{
+		Memory:  10,
+		MaxIter: 1000,
+		GradTol: 1e-6,
+		FuncTol: 1e-12,
+	}
+}
+
+// 终止状态
+const (
+	StatusGradientConverged = "gradient_converged" // 梯度范数满足GradTol
+	StatusFunctionConverged = "function_converged" // 函数值变化满足FuncTol
+	StatusMaxIterations     = "max_iterations"     // 达到最大迭代次数仍未收敛
+	StatusLineSearchFailed  = "line_search_failed" // 线搜索无法使函数值下降
+)
+
+// Result 优化结果
+type Result struct {
+	X          []float64 `json:"x"`
+	F          float64   `json:"f"`
+	GradNorm   float64   `json:"gradient_norm"` // 终止时梯度的无穷范数
+	Iterations int       `json:"iterations"`
+	FuncEvals  int       `json:"function_evaluations"`
+	Converged  bool      `json:"converged"`
+	Status     string    `json:"status"`
+}
+
+// LBFGS 使用有限内存BFGS方法从x0开始最小化目标函数
+// 搜索方向由两循环递归计算，步长由回溯线搜索（Armijo条件）确定
+func LBFGS(f Objective, x0 []float64, settings *LBFGSSettings) (*Result, error) {
+	if f == nil {
+		return nil, errors.New("objective function is nil")
+	}
+	if len(x0) == 0 {
+		return nil, errors.New("initial point is empty")
+	}
+	if settings == nil {
+		settings = DefaultLBFGSSettings()
+	}
+	memory := settings.Memory
+	if memory <= 0 {
+		memory = 10
+	}
+
+	dim := len(x0)
+	x := append([]float64(nil), x0...)
+	grad := make([]float64, dim)
+	fx := f(x, grad)
+	result := &Result{FuncEvals: 1}
+	if math.IsNaN(fx) || math.IsInf(fx, 0) {
+		return nil, errors.New("objective is not finite at the initial point")
+	}
+
+	var sHist, yHist [][]float64
+	var rhoHist []float64
+	direction := make([]float64, dim)
+	xNew := make([]float64, dim)
+	gradNew := make([]float64, dim)
+
+	result.Status = StatusMaxIterations
+	for result.Iterations < settings.MaxIter {
+		if infNorm(grad) < settings.GradTol {
+			result.Status = StatusGradientConverged
+			break
+		}
+		result.Iterations++
+
+		twoLoop(grad, sHist, yHist, rhoHist, direction)
+		slope := dot(grad, direction)
+		if slope >= 0 {
+			// 方向不是下降方向时重置为最速下降
+			sHist, yHist, rhoHist = nil, nil, nil
+			for i := range direction {
+				direction[i] = -grad[i]
+			}
+			slope = dot(grad, direction)
+		}
+
+		// 回溯线搜索，首次迭代按梯度大小缩放初始步长
+		step := 1.0
+		if len(sHist) == 0 {
+			step = math.Min(1, 1/math.Max(infNorm(grad), 1e-12))
+		}
+		const armijo = 1e-4
+		var fNew float64
+		accepted := false
+		for trial := 0; trial < 50; trial++ {
+			for i := range xNew {
+				xNew[i] = x[i] + step*direction[i]
+			}
+			fNew = f(xNew, gradNew)
+			result.FuncEvals++
+			if !math.IsNaN(fNew) && fNew <= fx+armijo*step*slope {
+				accepted = true
+				break
+			}
+			step /= 2
+		}
+		if !accepted {
+			result.Status = StatusLineSearchFailed
+			break
+		}
+
+		// 更新历史修正对，曲率条件不满足时跳过以保持正定
+		s := make([]float64, dim)
+		yDiff := make([]float64, dim)
+		for i := range s {
+			s[i] = xNew[i] - x[i]
+			yDiff[i] = gradNew[i] - grad[i]
+		}
+		if sy := dot(s, yDiff); sy > 1e-10 {
+			if len(sHist) == memory {
+				sHist, yHist, rhoHist = sHist[1:], yHist[1:], rhoHist[1:]
+			}
+			sHist = append(sHist, s)
+			yHist = append(yHist, yDiff)
+			rhoHist = append(rhoHist, 1/sy)
+		}
+
+		fOld := fx
+		copy(x, xNew)
+		copy(grad, gradNew)
+		fx = fNew
+
+		if math.Abs(fOld-fx) <= settings.FuncTol*math.Max(1, math.Max(math.Abs(fOld), math.Abs(fx))) {
+			result.Status = StatusFunctionConverged
+			if infNorm(grad) < settings.GradTol {
+				result.Status = StatusGradientConverged
+			}
+			break
+		}
+	}
+
+	result.X = x
+	result.F = fx
+	result.GradNorm = infNorm(grad)
+	result.Converged = result.Status == StatusGradientConverged || result.Status == StatusFunctionConverged
+	return result, nil
+}
+
+// twoLoop 两循环递归：用历史修正对近似逆Hessian与梯度的乘积，结果的相反数写入direction
+func twoLoop(grad []float64, sHist, yHist [][]float64, rhoHist []float64, direction []float64) {
+	copy(direction, grad)
+	k := len(sHist)
+	alpha := make([]float64, k)
+	for i := k - 1; i >= 0; i-- {
+		alpha[i] = rhoHist[i] * dot(sHist[i], direction)
+		axpy(-alpha[i], yHist[i], direction)
+	}
+	if k > 0 {
+		// 初始逆Hessian取 gamma*I，gamma = s^T y / y^T y
+		gamma := 1 / (rhoHist[k-1] * dot(yHist[k-1], yHist[k-1]))
+		for i := range direction {
+			direction[i] *= gamma
+		}
+	}
+	for i := 0; i < k; i++ {
+		beta := rhoHist[i] * dot(yHist[i], direction)
+		axpy(alpha[i]-beta, sHist[i], direction)
+	}
+	for i := range direction {
+		direction[i] = -direction[i]
+	}
+}
+
+// dot 向量内积
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// axpy 计算 y += alpha * x
+func axpy(alpha float64, x, y []float64) {
+	for i := range x {
+		y[i] += alpha * x[i]
+	}
+}
+
+// infNorm 向量的无穷范数
+func infNorm(v []float64) float64 {
+	norm := 0.0
+	for _, x := range v {
+		norm = math.Max(norm, math.Abs(x))
+	}
+	return norm
+}
@@ -0,0 +1,108 @@
+package optimize
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// rosenbrock Rosenbrock函数，最小值0位于(1, ..., 1)
+func rosenbrock(x, grad []float64) float64 {
+	for i := range grad {
+		grad[i] = 0
+	}
+	f := 0.0
+	for i := 0; i+1 < len(x); i++ {
+		a, b := 1-x[i], x[i+1]-x[i]*x[i]
+		f += a*a + 100*b*b
+		grad[i] += -2*a - 400*x[i]*b
+		grad[i+1] += 200 * b
+	}
+	return f
+}
+
+func TestLBFGSMinimizes(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Objective
+		x0   []float64
+		want []float64
+	}{
+		{
+			name: "quadratic",
+			f: func(x, grad []float64) float64 {
+				// (x0-3)² + 10(x1+2)²
+				grad[0] = 2 * (x[0] - 3)
+				grad[1] = 20 * (x[1] + 2)
+				return (x[0]-3)*(x[0]-3) + 10*(x[1]+2)*(x[1]+2)
+			},
+			x0:   []float64{0, 0},
+			want: []float64{3, -2},
+		},
+		{name: "rosenbrock", f: rosenbrock, x0: []float64{-1.2, 1}, want: []float64{1, 1}},
+		{name: "rosenbrock 5d", f: rosenbrock, x0: []float64{-1, 2, -1, 2, -1}, want: []float64{1, 1, 1, 1, 1}},
+	}
+	for _, tc := range tests {
+		result, err := LBFGS(tc.f, tc.x0, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		// 接近最小值时函数值的相对变化先于梯度满足阈值，两种终止状态都算收敛
+		if !result.Converged {
+			t.Errorf("%s: status = %s after %d iterations, want convergence", tc.name, result.Status, result.Iterations)
+		}
+		if result.GradNorm > 1e-4 {
+			t.Errorf("%s: gradient norm = %g", tc.name, result.GradNorm)
+		}
+		for i := range tc.want {
+			if math.Abs(result.X[i]-tc.want[i]) > 1e-4 {
+				t.Errorf("%s: x = %v, want %v", tc.name, result.X, tc.want)
+				break
+			}
+		}
+		if result.FuncEvals < result.Iterations {
+			t.Errorf("%s: %d function evaluations for %d iterations", tc.name, result.FuncEvals, result.Iterations)
+		}
+	}
+}
+
+func TestLBFGSDoesNotModifyStart(t *testing.T) {
+	x0 := []float64{-1.2, 1}
+	if _, err := LBFGS(rosenbrock, x0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if x0[0] != -1.2 || x0[1] != 1 {
+		t.Errorf("x0 = %v, want it unchanged", x0)
+	}
+}
+
+func TestLBFGSMaxIterations(t *testing.T) {
+	settings := DefaultLBFGSSettings()
+	settings.MaxIter = 3
+	result, err := LBFGS(rosenbrock, []float64{-1.2, 1}, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Converged || result.Status != StatusMaxIterations || result.Iterations != 3 {
+		t.Errorf("status = %s, converged = %v, iterations = %d", result.Status, result.Converged, result.Iterations)
+	}
+}
+
+func TestLBFGSInvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       Objective
+		x0      []float64
+		wantErr string
+	}{
+		{"nil objective", nil, []float64{1}, "objective function is nil"},
+		{"empty start", rosenbrock, nil, "initial point is empty"},
+		{"non-finite start", func(x, grad []float64) float64 { return math.NaN() }, []float64{1}, "not finite"},
+	}
+	for _, tc := range tests {
+		_, err := LBFGS(tc.f, tc.x0, nil)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: error = %v, want it to contain %q", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -5,6 +5,8 @@ type Dataset struct {
 	Features     [][]float64
 	Target       []float64
 	FeatureNames []string
+	Weights      []float64 // 样本权重（可选），为空时所有样本等权
+	TargetName   string    // 目标变量名（可选）
 }
 
 // NewDataset 创建新的数据集
@@ -40,5 +42,8 @@ func (d *Dataset) IsValid() bool {
 	if len(d.Target) != d.NumSamples() {
 		return false
 	}
+	if len(d.Weights) > 0 && len(d.Weights) != d.NumSamples() {
+		return false
+	}
 	return true
 }
@@ -0,0 +1,430 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ColumnType 列的数据类型
+type ColumnType int
+
+// 支持的列类型
+const (
+	FloatColumn ColumnType = iota
+	IntColumn
+	StringColumn
+	BoolColumn
+	TimeColumn
+)
+
+// String 返回列类型的名称
+func (t ColumnType) String() string {
+	switch t {
+	case FloatColumn:
+		return "float"
+	case IntColumn:
+		return "int"
+	case StringColumn:
+		return "string"
+	case BoolColumn:
+		return "bool"
+	case TimeColumn:
+		return "time"
+	}
+	return fmt.Sprintf("ColumnType(%d)", int(t))
+}
+
+// Column 类型化的数据列，只有与Type对应的值切片有效
+type Column struct {
+	Name    string
+	Type    ColumnType
+	Floats  []float64
+	Ints    []int64
+	Strings []string
+	Bools   []bool
+	Times   []time.Time
+	Missing []bool // Missing[i]为true表示第i个值缺失（可选），浮点列的NaN同样视为缺失
+}
+
+// NewFloatColumn 创建浮点列
+func NewFloatColumn(name string, values []float64) *Column {
+	return &Column{Name: name, Type: FloatColumn, Floats: values}
+}
+
+// NewIntColumn 创建整数列
+func NewIntColumn(name string, values []int64) *Column {
+	return &Column{Name: name, Type: IntColumn, Ints: values}
+}
+
+// NewStringColumn 创建字符串列
+func NewStringColumn(name string, values []string) *Column {
+	return &Column{Name: name, Type: StringColumn, Strings: values}
+}
+
+// NewBoolColumn 创建布尔列
+func NewBoolColumn(name string, values []bool) *Column {
+	return &Column{Name: name, Type: BoolColumn, Bools: values}
+}
+
+// NewTimeColumn 创建时间列
+func NewTimeColumn(name string, values []time.Time) *Column {
+	return &Column{Name: name, Type: TimeColumn, Times: values}
+}
+
+// Len 返回列的长度
+func (c *Column) Len() int {
+	switch c.Type {
+	case IntColumn:
+		return len(c.Ints)
+	case StringColumn:
+		return len(c.Strings)
+	case BoolColumn:
+		return len(c.Bools)
+	case TimeColumn:
+		return len(c.Times)
+	}
+	return len(c.Floats)
+}
+
+// IsMissing 返回第i个值是否缺失
+func (c *Column) IsMissing(i int) bool {
+	if c.Missing != nil && c.Missing[i] {
+		return true
+	}
+	return c.Type == FloatColumn && math.IsNaN(c.Floats[i])
+}
+
+// IsNumeric 返回列能否直接转换为数值（字符串列需要先编码）
+func (c *Column) IsNumeric() bool {
+	return c.Type != StringColumn
+}
+
+// Value 返回第i个值，缺失时返回nil
+func (c *Column) Value(i int) interface{} {
+	if c.IsMissing(i) {
+		return nil
+	}
+	switch c.Type {
+	case IntColumn:
+		return c.Ints[i]
+	case StringColumn:
+		return c.Strings[i]
+	case BoolColumn:
+		return c.Bools[i]
+	case TimeColumn:
+		return c.Times[i]
+	}
+	return c.Floats[i]
+}
+
+// Float64 将第i个值转换为浮点数：布尔值为0/1，时间为Unix秒；缺失值和字符串返回NaN
+func (c *Column) Float64(i int) float64 {
+	if c.IsMissing(i) {
+		return math.NaN()
+	}
+	switch c.Type {
+	case IntColumn:
+		return float64(c.Ints[i])
+	case BoolColumn:
+		if c.Bools[i] {
+			return 1
+		}
+		return 0
+	case TimeColumn:
+		return float64(c.Times[i].Unix())
+	case StringColumn:
+		return math.NaN()
+	}
+	return c.Floats[i]
+}
+
+// Format 将第i个值格式化为字符串，缺失值返回空字符串
+func (c *Column) Format(i int) string {
+	if c.IsMissing(i) {
+		return ""
+	}
+	switch c.Type {
+	case IntColumn:
+		return strconv.FormatInt(c.Ints[i], 10)
+	case StringColumn:
+		return c.Strings[i]
+	case BoolColumn:
+		return strconv.FormatBool(c.Bools[i])
+	case TimeColumn:
+		return c.Times[i].Format(time.RFC3339)
+	}
+	return strconv.FormatFloat(c.Floats[i], 'g', -1, 64)
+}
+
+// Frame 列式存储的类型化数据集，各列保留原始类型（浮点、整数、字符串、布尔、时间），
+// 只在建模时通过ToDataset或Dense转换为数值矩阵。Select、Drop、Rename返回新的Frame，与原Frame共享列数据
+type Frame struct {
+	columns []*Column
+	index   map[string]int
+}
+
+// NewFrame 由等长且名称唯一的列创建数据集
+func NewFrame(columns ...*Column) (*Frame, error) {
+	f := &Frame{index: make(map[string]int, len(columns))}
+	for _, c := range columns {
+		if err := f.AddColumn(c); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// FrameFromDataset 将数值数据集转换为Frame，目标变量作为最后一列（名称为TargetName，为空时为"target"）
+func FrameFromDataset(d *Dataset) *Frame {
+	n := d.NumSamples()
+	f := &Frame{index: make(map[string]int, d.NumFeatures()+1)}
+	for j := 0; j < d.NumFeatures(); j++ {
+		name := fmt.Sprintf("feature_%d", j)
+		if j < len(d.FeatureNames) {
+			name = d.FeatureNames[j]
+		}
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = d.Features[i][j]
+		}
+		f.index[name] = len(f.columns)
+		f.columns = append(f.columns, NewFloatColumn(name, values))
+	}
+	targetName := d.TargetName
+	if targetName == "" {
+		targetName = "target"
+	}
+	f.index[targetName] = len(f.columns)
+	f.columns = append(f.columns, NewFloatColumn(targetName, append([]float64(nil), d.Target...)))
+	return f
+}
+
+// NumRows 返回行数
+func (f *Frame) NumRows() int {
+	if len(f.columns) == 0 {
+		return 0
+	}
+	return f.columns[0].Len()
+}
+
+// NumColumns 返回列数
+func (f *Frame) NumColumns() int {
+	return len(f.columns)
+}
+
+// Names 返回按顺序排列的列名
+func (f *Frame) Names() []string {
+	names := make([]string, len(f.columns))
+	for j, c := range f.columns {
+		names[j] = c.Name
+	}
+	return names
+}
+
+// Columns 返回全部列
+func (f *Frame) Columns() []*Column {
+	return f.columns
+}
+
+// Column 按名称返回列
+func (f *Frame) Column(name string) (*Column, bool) {
+	j, ok := f.index[name]
+	if !ok {
+		return nil, false
+	}
+	return f.columns[j], true
+}
+
+// AddColumn 在末尾追加一列，列名必须唯一且长度与已有列一致
+func (f *Frame) AddColumn(c *Column) error {
+	if c == nil || c.Name == "" {
+		return fmt.Errorf("列名不能为空")
+	}
+	if _, exists := f.index[c.Name]; exists {
+		return fmt.Errorf("列名重复: %s", c.Name)
+	}
+	if c.Missing != nil && len(c.Missing) != c.Len() {
+		return fmt.Errorf("列 %s 的缺失标记长度 %d 与值的长度 %d 不一致", c.Name, len(c.Missing), c.Len())
+	}
+	if len(f.columns) > 0 && c.Len() != f.NumRows() {
+		return fmt.Errorf("列 %s 的长度 %d 与数据集行数 %d 不一致", c.Name, c.Len(), f.NumRows())
+	}
+	f.index[c.Name] = len(f.columns)
+	f.columns = append(f.columns, c)
+	return nil
+}
+
+// Select 按给定顺序选择列
+func (f *Frame) Select(names ...string) (*Frame, error) {
+	selected := make([]*Column, len(names))
+	for k, name := range names {
+		c, ok := f.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("未找到列: %s", name)
+		}
+		selected[k] = c
+	}
+	return NewFrame(selected...)
+}
+
+// Drop 删除给定的列
+func (f *Frame) Drop(names ...string) (*Frame, error) {
+	dropped := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, ok := f.index[name]; !ok {
+			return nil, fmt.Errorf("未找到列: %s", name)
+		}
+		dropped[name] = true
+	}
+	var kept []*Column
+	for _, c := range f.columns {
+		if !dropped[c.Name] {
+			kept = append(kept, c)
+		}
+	}
+	return NewFrame(kept...)
+}
+
+// Rename 按旧名到新名的映射重命名列
+func (f *Frame) Rename(mapping map[string]string) (*Frame, error) {
+	for name := range mapping {
+		if _, ok := f.index[name]; !ok {
+			return nil, fmt.Errorf("未找到列: %s", name)
+		}
+	}
+	renamed := make([]*Column, len(f.columns))
+	for j, c := range f.columns {
+		renamed[j] = c
+		if name, ok := mapping[c.Name]; ok {
+			copied := *c
+			copied.Name = name
+			renamed[j] = &copied
+		}
+	}
+	return NewFrame(renamed...)
+}
+
+// ParseTime 按layout（time.Parse的格式）将字符串列解析为时间列，空白和缺失的值记为缺失
+func (f *Frame) ParseTime(name, layout string, loc *time.Location) (*Frame, error) {
+	j, ok := f.index[name]
+	if !ok {
+		return nil, fmt.Errorf("未找到列: %s", name)
+	}
+	c := f.columns[j]
+	if c.Type != StringColumn {
+		return nil, fmt.Errorf("列 %s 不是字符串列", name)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	times := make([]time.Time, c.Len())
+	var missing []bool
+	for i, s := range c.Strings {
+		if c.IsMissing(i) || strings.TrimSpace(s) == "" {
+			if missing == nil {
+				missing = make([]bool, c.Len())
+			}
+			missing[i] = true
+			continue
+		}
+		t, err := time.ParseInLocation(layout, strings.TrimSpace(s), loc)
+		if err != nil {
+			return nil, fmt.Errorf("列 %s 第 %d 行的时间无效: %v", name, i, err)
+		}
+		times[i] = t
+	}
+
+	parsed := make([]*Column, len(f.columns))
+	copy(parsed, f.columns)
+	parsed[j] = &Column{Name: name, Type: TimeColumn, Times: times, Missing: missing}
+	return NewFrame(parsed...)
+}
+
+// Dense 将给定的列（为空时为全部列）转换为数值矩阵，字符串列无法转换时返回错误
+func (f *Frame) Dense(names ...string) (*mat.Dense, error) {
+	if len(names) == 0 {
+		names = f.Names()
+	}
+	if len(names) == 0 || f.NumRows() == 0 {
+		return nil, fmt.Errorf("数据集为空")
+	}
+	m := mat.NewDense(f.NumRows(), len(names), nil)
+	for j, name := range names {
+		c, ok := f.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("未找到列: %s", name)
+		}
+		if !c.IsNumeric() {
+			return nil, fmt.Errorf("列 %s 是字符串列，需要先编码", name)
+		}
+		for i := 0; i < c.Len(); i++ {
+			m.Set(i, j, c.Float64(i))
+		}
+	}
+	return m, nil
+}
+
+// ToDataset 以targetColumn为目标变量、其余列为特征转换为数值数据集。
+// 字符串列按出现顺序编码为类别码（与LoadCSVCategorical一致），返回每个字符串列的类别表；
+// 缺失的特征值记为NaN，目标值缺失的行被跳过
+func (f *Frame) ToDataset(targetColumn string) (*Dataset, map[string][]string, error) {
+	target, ok := f.Column(targetColumn)
+	if !ok {
+		return nil, nil, fmt.Errorf("未找到目标列: %s", targetColumn)
+	}
+
+	categories := make(map[string][]string)
+	codes := make(map[string]map[string]int)
+	// value 返回列c第i个值的数值，字符串列编码为类别码
+	value := func(c *Column, i int) float64 {
+		if c.Type != StringColumn || c.IsMissing(i) {
+			return c.Float64(i)
+		}
+		if codes[c.Name] == nil {
+			codes[c.Name] = make(map[string]int)
+		}
+		code, ok := codes[c.Name][c.Strings[i]]
+		if !ok {
+			code = len(categories[c.Name])
+			codes[c.Name][c.Strings[i]] = code
+			categories[c.Name] = append(categories[c.Name], c.Strings[i])
+		}
+		return float64(code)
+	}
+
+	var featureNames []string
+	var columns []*Column
+	for _, c := range f.columns {
+		if c.Name != targetColumn {
+			featureNames = append(featureNames, c.Name)
+			columns = append(columns, c)
+		}
+	}
+
+	var features [][]float64
+	var targets []float64
+	for i := 0; i < f.NumRows(); i++ {
+		if target.IsMissing(i) {
+			continue
+		}
+		row := make([]float64, len(columns))
+		for j, c := range columns {
+			row[j] = value(c, i)
+		}
+		features = append(features, row)
+		targets = append(targets, value(target, i))
+	}
+	if len(features) == 0 {
+		return nil, nil, fmt.Errorf("数据集中没有有效的数据行")
+	}
+
+	dataset := NewDataset(features, targets, featureNames)
+	dataset.TargetName = targetColumn
+	return dataset, categories, nil
+}
@@ -0,0 +1,156 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// CSRMatrix 压缩稀疏行(CSR)格式的矩阵，只存储非零元素：
+// 第i行的非零元素位于Data[Indptr[i]:Indptr[i+1]]，对应的列号在Indices的同一区间内且严格递增。
+// 实现mat.Matrix接口，但At需要二分查找，逐元素访问较慢，模型应使用RowNonZeros或MulVecTo
+type CSRMatrix struct {
+	Indptr  []int
+	Indices []int
+	Data    []float64
+	rows    int
+	cols    int
+}
+
+// NewCSRMatrix 由CSR三元组创建稀疏矩阵并检查其结构
+func NewCSRMatrix(rows, cols int, indptr, indices []int, data []float64) (*CSRMatrix, error) {
+	if rows <= 0 || cols <= 0 {
+		return nil, fmt.Errorf("矩阵维度必须为正数: %d x %d", rows, cols)
+	}
+	if len(indptr) != rows+1 || indptr[0] != 0 {
+		return nil, fmt.Errorf("indptr长度应为 %d 且以0开始", rows+1)
+	}
+	if len(indices) != len(data) || indptr[rows] != len(data) {
+		return nil, fmt.Errorf("indices、data的长度与indptr[%d]=%d不一致", rows, indptr[rows])
+	}
+	for i := 0; i < rows; i++ {
+		if indptr[i] > indptr[i+1] {
+			return nil, fmt.Errorf("indptr必须单调不减，第 %d 行出错", i)
+		}
+		for k := indptr[i]; k < indptr[i+1]; k++ {
+			if indices[k] < 0 || indices[k] >= cols {
+				return nil, fmt.Errorf("第 %d 行的列号 %d 超出范围", i, indices[k])
+			}
+			if k > indptr[i] && indices[k] <= indices[k-1] {
+				return nil, fmt.Errorf("第 %d 行的列号必须严格递增", i)
+			}
+		}
+	}
+	return &CSRMatrix{Indptr: indptr, Indices: indices, Data: data, rows: rows, cols: cols}, nil
+}
+
+// CSRFromDense 将矩阵转换为CSR格式，只保留非零元素
+func CSRFromDense(m mat.Matrix) *CSRMatrix {
+	rows, cols := m.Dims()
+	s := &CSRMatrix{Indptr: make([]int, rows+1), rows: rows, cols: cols}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if v := m.At(i, j); v != 0 {
+				s.Indices = append(s.Indices, j)
+				s.Data = append(s.Data, v)
+			}
+		}
+		s.Indptr[i+1] = len(s.Data)
+	}
+	return s
+}
+
+// Dims 返回矩阵的行数和列数
+func (s *CSRMatrix) Dims() (int, int) {
+	return s.rows, s.cols
+}
+
+// At 返回第i行第j列的元素
+func (s *CSRMatrix) At(i, j int) float64 {
+	if i < 0 || i >= s.rows || j < 0 || j >= s.cols {
+		panic(mat.ErrIndexOutOfRange)
+	}
+	start, end := s.Indptr[i], s.Indptr[i+1]
+	k := start + sort.SearchInts(s.Indices[start:end], j)
+	if k < end && s.Indices[k] == j {
+		return s.Data[k]
+	}
+	return 0
+}
+
+// T 返回矩阵的转置（不复制数据）
+func (s *CSRMatrix) T() mat.Matrix {
+	return mat.Transpose{Matrix: s}
+}
+
+// NNZ 返回非零元素个数
+func (s *CSRMatrix) NNZ() int {
+	return len(s.Data)
+}
+
+// RowNonZeros 返回第i行非零元素的列号和值（与矩阵共享存储）
+func (s *CSRMatrix) RowNonZeros(i int) ([]int, []float64) {
+	start, end := s.Indptr[i], s.Indptr[i+1]
+	return s.Indices[start:end], s.Data[start:end]
+}
+
+// MulVecTo 计算 dst = S x，dst长度为行数，x长度为列数
+func (s *CSRMatrix) MulVecTo(dst, x []float64) {
+	for i := 0; i < s.rows; i++ {
+		sum := 0.0
+		for k := s.Indptr[i]; k < s.Indptr[i+1]; k++ {
+			sum += s.Data[k] * x[s.Indices[k]]
+		}
+		dst[i] = sum
+	}
+}
+
+// MulTransVecTo 计算 dst = S^T x，dst长度为列数，x长度为行数
+func (s *CSRMatrix) MulTransVecTo(dst, x []float64) {
+	for j := range dst {
+		dst[j] = 0
+	}
+	for i := 0; i < s.rows; i++ {
+		for k := s.Indptr[i]; k < s.Indptr[i+1]; k++ {
+			dst[s.Indices[k]] += s.Data[k] * x[i]
+		}
+	}
+}
+
+// Columns 按列重新组织非零元素（即CSC格式），返回每列非零元素的行号和值，供按列访问的算法使用
+func (s *CSRMatrix) Columns() ([][]int, [][]float64) {
+	rowIndices := make([][]int, s.cols)
+	values := make([][]float64, s.cols)
+	for i := 0; i < s.rows; i++ {
+		for k := s.Indptr[i]; k < s.Indptr[i+1]; k++ {
+			j := s.Indices[k]
+			rowIndices[j] = append(rowIndices[j], i)
+			values[j] = append(values[j], s.Data[k])
+		}
+	}
+	return rowIndices, values
+}
+
+// SubsetRows 按索引提取行，返回新的稀疏矩阵
+func (s *CSRMatrix) SubsetRows(indices []int) *CSRMatrix {
+	subset := &CSRMatrix{Indptr: make([]int, len(indices)+1), rows: len(indices), cols: s.cols}
+	for r, i := range indices {
+		cols, values := s.RowNonZeros(i)
+		subset.Indices = append(subset.Indices, cols...)
+		subset.Data = append(subset.Data, values...)
+		subset.Indptr[r+1] = len(subset.Data)
+	}
+	return subset
+}
+
+// ToDense 转换为稠密矩阵
+func (s *CSRMatrix) ToDense() *mat.Dense {
+	dense := mat.NewDense(s.rows, s.cols, nil)
+	for i := 0; i < s.rows; i++ {
+		for k := s.Indptr[i]; k < s.Indptr[i+1]; k++ {
+			dense.Set(i, s.Indices[k], s.Data[k])
+		}
+	}
+	return dense
+}
@@ -0,0 +1,249 @@
+package gomodel
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+)
+
+// LeaderboardEntry 自动建模中单个算法的最佳交叉验证结果
+type LeaderboardEntry struct {
+	Rank       int                    `json:"rank"`
+	Algorithm  AlgorithmType          `json:"algorithm"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Scores     []float64              `json:"scores"`
+	MeanScore  float64                `json:"mean_score"`
+	StdScore   float64                `json:"std_score"`
+	Candidates int                    `json:"candidates"` // 实际评估的参数组合数
+	Duration   time.Duration          `json:"duration"`
+	Error      string                 `json:"error,omitempty"` // 所有参数组合均失败时的错误信息
+}
+
+// AutoMLResult 自动建模结果
+type AutoMLResult struct {
+	Task        string              `json:"task"` // TaskRegression或TaskClassification
+	Leaderboard []*LeaderboardEntry `json:"leaderboard"`
+	BestConfig  *ModelConfig        `json:"best_config"`
+	BestModel   *ModelResult        `json:"best_model"`
+	Skipped     []AlgorithmType     `json:"skipped,omitempty"` // 因时间预算耗尽而未评估的算法
+	Elapsed     time.Duration       `json:"elapsed"`
+	Client      *Client             `json:"-"` // 持有最佳模型的客户端，可通过BestModel.ModelID预测
+}
+
+// autoCandidate 自动建模中的候选算法及其参数范围
+type autoCandidate struct {
+	algorithm AlgorithmType
+	grid      ParamGrid
+}
+
+// AutoTrain 使用默认客户端自动选择算法，详见Client.AutoTrain
+func AutoTrain(data *TrainingData, budget time.Duration) (*AutoMLResult, error) {
+	return NewClient(nil).AutoTrain(data, budget)
+}
+
+// AutoTrain 在时间预算内尝试所有适用于数据的算法及其常用参数范围，
+// 对每个候选执行K折交叉验证，返回按平均得分排序的排行榜，并在全部数据上训练最佳模型。
+// 目标只含0和1时按二分类处理，候选为逻辑回归、校准的逻辑回归以及以逻辑回归为成员的Bagging、Voting和Stacking，按准确率排序；
+// 其他目标按回归处理，候选为线性模型，单特征时还包括非线性模型。
+// budget不大于0时不限制时间；预算耗尽后剩余算法会被跳过，但至少会评估一个候选
+func (c *Client) AutoTrain(data *TrainingData, budget time.Duration) (*AutoMLResult, error) {
+	if data == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "training data cannot be nil",
+		}
+	}
+	if err := c.validateData(data); err != nil {
+		return nil, err
+	}
+	if err := c.checkQuality(data); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	deadline := start.Add(budget)
+	expired := func() bool {
+		return budget > 0 && time.Now().After(deadline)
+	}
+
+	task, candidates := autoCandidates(data)
+	validation := &ValidationConfig{
+		Method:     "kfold",
+		KFolds:     5,
+		RandomSeed: c.config.RandomSeed,
+	}
+	if task == TaskClassification {
+		// 分类候选统一按准确率比较，集成模型的Score不一定是准确率
+		validation.Scoring = evaluation.ScoringAccuracy
+	}
+	if n, _ := data.Features.Dims(); n < validation.KFolds {
+		validation.KFolds = n
+	}
+
+	result := &AutoMLResult{Task: task, Client: c}
+	evaluated := 0
+	for _, candidate := range candidates {
+		if evaluated > 0 && expired() {
+			result.Skipped = append(result.Skipped, candidate.algorithm)
+			continue
+		}
+
+		entry := &LeaderboardEntry{Algorithm: candidate.algorithm, MeanScore: math.Inf(-1)}
+		entryStart := time.Now()
+		combinations, _ := expandGrid(candidate.grid)
+		if len(candidate.grid) == 0 {
+			combinations = []map[string]interface{}{{}}
+		}
+		for _, params := range combinations {
+			if evaluated > 0 && expired() {
+				break
+			}
+			evaluated++
+			entry.Candidates++
+
+			searchResult, err := c.evaluateCandidate(data, GetDefaultConfig(candidate.algorithm), params, validation)
+			if err != nil {
+				entry.Error = err.Error()
+				continue
+			}
+			if math.IsNaN(searchResult.MeanScore) || math.IsInf(searchResult.MeanScore, 0) {
+				entry.Error = "non-finite validation score"
+				continue
+			}
+			if searchResult.MeanScore > entry.MeanScore {
+				entry.Parameters = searchResult.Parameters
+				entry.Scores = searchResult.Scores
+				entry.MeanScore = searchResult.MeanScore
+				entry.StdScore = searchResult.StdScore
+			}
+		}
+		entry.Duration = time.Since(entryStart)
+
+		if entry.Parameters != nil {
+			entry.Error = ""
+		} else {
+			entry.MeanScore = math.NaN()
+		}
+		result.Leaderboard = append(result.Leaderboard, entry)
+	}
+
+	// 成功的算法按得分从高到低排在前面，失败的算法排在最后
+	sort.SliceStable(result.Leaderboard, func(i, j int) bool {
+		a, b := result.Leaderboard[i], result.Leaderboard[j]
+		if math.IsNaN(b.MeanScore) {
+			return !math.IsNaN(a.MeanScore)
+		}
+		return a.MeanScore > b.MeanScore
+	})
+	for i, entry := range result.Leaderboard {
+		entry.Rank = i + 1
+	}
+
+	best := result.Leaderboard[0]
+	if best.Parameters == nil {
+		return nil, &Error{
+			Code:    ErrTrainingFailed,
+			Message: "no algorithm could be trained on the data",
+			Details: best.Error,
+		}
+	}
+
+	result.BestConfig = &ModelConfig{
+		Algorithm:    best.Algorithm,
+		Parameters:   best.Parameters,
+		LossFunction: GetDefaultConfig(best.Algorithm).LossFunction,
+	}
+	if task == TaskClassification {
+		result.BestConfig.LossFunction = Accuracy
+	}
+
+	model, err := c.Train(data, result.BestConfig)
+	if err != nil {
+		return nil, err
+	}
+	model.CrossValidation = &CVResult{
+		Scores:    best.Scores,
+		MeanScore: best.MeanScore,
+		StdScore:  best.StdScore,
+		FoldCount: len(best.Scores),
+	}
+	model.ValidationScore = &best.MeanScore
+	result.BestModel = model
+	result.Elapsed = time.Since(start)
+
+	return result, nil
+}
+
+// autoCandidates 根据数据特点判断任务类型，并列出适用的算法及参数范围（按训练开销从低到高）
+func autoCandidates(data *TrainingData) (string, []autoCandidate) {
+	n, p := data.Features.Dims()
+
+	binary := true
+	positiveY := true
+	for i := 0; i < n; i++ {
+		v := data.Target.AtVec(i)
+		if v != 0 && v != 1 {
+			binary = false
+		}
+		if v <= 0 {
+			positiveY = false
+		}
+	}
+	if binary {
+		// 集成模型的成员均为逻辑回归，Voting对成员的正类概率取平均
+		return TaskClassification, []autoCandidate{
+			{Logistic, nil},
+			{Calibrated, ParamGrid{"method": {"sigmoid", "isotonic"}}},
+			{Bagging, ParamGrid{
+				"base_model":   {GetDefaultConfig(Logistic)},
+				"n_estimators": {10, 25},
+			}},
+			{Voting, ParamGrid{
+				"models": {[]*ModelConfig{GetDefaultConfig(Logistic), GetDefaultConfig(Calibrated)}},
+				"voting": {"average"},
+			}},
+			{Stacking, ParamGrid{
+				"base_models": {[]*ModelConfig{GetDefaultConfig(Logistic), GetDefaultConfig(Calibrated)}},
+				"meta_model":  {GetDefaultConfig(Logistic)},
+			}},
+		}
+	}
+
+	components := make([]interface{}, 0, 5)
+	for k := 1; k <= p && k <= 5; k++ {
+		components = append(components, k)
+	}
+
+	candidates := []autoCandidate{
+		{OLS, nil},
+		{Ridge, ParamGrid{"lambda": {0.01, 0.1, 1.0, 10.0, 100.0}}},
+		{PLS, ParamGrid{"components": components}},
+		{Lasso, ParamGrid{"lambda": {0.001, 0.01, 0.1, 1.0}}},
+	}
+
+	// 非线性模型仅支持单特征输入
+	if p != 1 {
+		return TaskRegression, candidates
+	}
+	positiveX := true
+	for i := 0; i < n; i++ {
+		if data.Features.At(i, 0) <= 0 {
+			positiveX = false
+			break
+		}
+	}
+
+	candidates = append(candidates, autoCandidate{Polynomial, ParamGrid{"degree": {2, 3, 4}}})
+	if positiveY {
+		candidates = append(candidates, autoCandidate{Exponential, nil})
+	}
+	if positiveX {
+		candidates = append(candidates, autoCandidate{Logarithmic, nil})
+	}
+	if positiveX && positiveY {
+		candidates = append(candidates, autoCandidate{Power, nil})
+	}
+	return TaskRegression, candidates
+}
@@ -0,0 +1,250 @@
+package gomodel
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+)
+
+// 树结构Parzen估计器(TPE)的默认设置
+const (
+	tpeStartupTrials = 10   // 开始建模前的随机试验次数
+	tpeGamma         = 0.25 // 视为"好"观测的比例
+	tpeCandidates    = 24   // 每轮从好观测密度中采样的候选数量
+)
+
+// BayesianSearch 使用树结构Parzen估计器(TPE)进行序贯的基于模型的超参数优化
+// 搜索空间与RandomizedSearch使用相同的ParamDistributions定义：前若干轮随机采样，
+// 之后每轮根据已有结果将观测分为好/差两组，选择使两组密度比l(x)/g(x)最大的候选参数
+func (c *Client) BayesianSearch(data *TrainingData, config *ModelConfig, distributions ParamDistributions, nIter int, validation *ValidationConfig) (*TuningResult, error) {
+	if err := c.validateSearchInput(data, config); err != nil {
+		return nil, err
+	}
+	if nIter <= 0 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "n_iter must be positive",
+		}
+	}
+	if err := validateDistributions(distributions); err != nil {
+		return nil, err
+	}
+
+	validation = c.searchValidation(validation)
+	rng := rand.New(rand.NewSource(c.config.RandomSeed))
+
+	names := make([]string, 0, len(distributions))
+	for name := range distributions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	candidates := make([]map[string]interface{}, 0, nIter)
+	results := make([]*SearchResult, 0, nIter)
+	for iter := 0; iter < nIter; iter++ {
+		var candidate map[string]interface{}
+		if iter < tpeStartupTrials {
+			candidate = sampleDistributions(distributions, rng)
+		} else {
+			candidate = suggestTPE(names, distributions, candidates, results, rng)
+		}
+
+		result, err := c.evaluateCandidate(data, config, candidate, validation)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+		results = append(results, result)
+	}
+
+	return rankResults(config, results), nil
+}
+
+// suggestTPE 根据历史观测提出下一组候选参数
+func suggestTPE(names []string, distributions ParamDistributions, candidates []map[string]interface{}, results []*SearchResult, rng *rand.Rand) map[string]interface{} {
+	// 按得分从高到低划分好/差两组观测
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return results[order[i]].MeanScore > results[order[j]].MeanScore
+	})
+	nGood := int(math.Ceil(tpeGamma * float64(len(results))))
+	if nGood < 1 {
+		nGood = 1
+	}
+
+	good := make([]map[string]interface{}, 0, nGood)
+	bad := make([]map[string]interface{}, 0, len(results)-nGood)
+	for rank, idx := range order {
+		if rank < nGood {
+			good = append(good, candidates[idx])
+		} else {
+			bad = append(bad, candidates[idx])
+		}
+	}
+
+	// 各参数相互独立地从好观测密度中采样，选择对数密度比最大的候选
+	var best map[string]interface{}
+	bestScore := math.Inf(-1)
+	for i := 0; i < tpeCandidates; i++ {
+		candidate := make(map[string]interface{}, len(names))
+		score := 0.0
+		for _, name := range names {
+			distribution := distributions[name]
+			goodValues := parameterValues(good, name)
+			badValues := parameterValues(bad, name)
+
+			value := tpeSample(distribution, goodValues, rng)
+			candidate[name] = value
+			score += math.Log(tpeDensity(distribution, goodValues, value)) -
+				math.Log(tpeDensity(distribution, badValues, value))
+		}
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	return best
+}
+
+// parameterValues 提取一组观测中指定参数的取值
+func parameterValues(observations []map[string]interface{}, name string) []interface{} {
+	values := make([]interface{}, len(observations))
+	for i, observation := range observations {
+		values[i] = observation[name]
+	}
+	return values
+}
+
+// tpeSample 从观测构成的Parzen混合分布（含先验分量）中采样
+func tpeSample(distribution ParamDistribution, observed []interface{}, rng *rand.Rand) interface{} {
+	switch d := distribution.(type) {
+	case Choice:
+		weights := choiceWeights(d, observed)
+		r := rng.Float64()
+		for i, w := range weights {
+			r -= w
+			if r <= 0 {
+				return d[i]
+			}
+		}
+		return d[len(d)-1]
+	case Uniform, LogUniform, IntUniform:
+		low, high := tpeBounds(distribution)
+		// 第0个分量为先验，其余分量以观测值为中心
+		component := rng.Intn(len(observed) + 1)
+		var x float64
+		if component == 0 {
+			x = low + rng.Float64()*(high-low)
+		} else {
+			center := tpeTransform(distribution, observed[component-1])
+			x = center + rng.NormFloat64()*tpeBandwidth(low, high, len(observed))
+			x = math.Max(low, math.Min(high, x))
+		}
+		return tpeInverse(distribution, x)
+	default:
+		return distribution.Sample(rng)
+	}
+}
+
+// tpeDensity 计算取值在观测构成的Parzen混合分布下的密度
+func tpeDensity(distribution ParamDistribution, observed []interface{}, value interface{}) float64 {
+	switch d := distribution.(type) {
+	case Choice:
+		weights := choiceWeights(d, observed)
+		for i, option := range d {
+			if reflect.DeepEqual(option, value) {
+				return weights[i]
+			}
+		}
+		return 1e-12
+	case Uniform, LogUniform, IntUniform:
+		low, high := tpeBounds(distribution)
+		x := tpeTransform(distribution, value)
+		sigma := tpeBandwidth(low, high, len(observed))
+
+		density := 1.0 / (high - low)
+		for _, o := range observed {
+			z := (x - tpeTransform(distribution, o)) / sigma
+			density += math.Exp(-0.5*z*z) / (sigma * math.Sqrt(2*math.Pi))
+		}
+		return density / float64(len(observed)+1)
+	default:
+		return 1
+	}
+}
+
+// choiceWeights 计算离散候选的平滑概率（每个候选带有1次先验计数）
+func choiceWeights(choice Choice, observed []interface{}) []float64 {
+	weights := make([]float64, len(choice))
+	for i := range weights {
+		weights[i] = 1
+	}
+	for _, o := range observed {
+		for i, option := range choice {
+			if reflect.DeepEqual(option, o) {
+				weights[i]++
+				break
+			}
+		}
+	}
+	total := float64(len(choice) + len(observed))
+	for i := range weights {
+		weights[i] /= total
+	}
+	return weights
+}
+
+// tpeBounds 返回连续参数在变换空间中的取值范围
+func tpeBounds(distribution ParamDistribution) (float64, float64) {
+	switch d := distribution.(type) {
+	case Uniform:
+		return d.Low, d.High
+	case LogUniform:
+		return math.Log(d.Low), math.Log(d.High)
+	case IntUniform:
+		return float64(d.Low) - 0.5, float64(d.High) + 0.5
+	}
+	return 0, 1
+}
+
+// tpeTransform 将参数取值映射到建模所用的连续空间（对数均匀分布取对数）
+func tpeTransform(distribution ParamDistribution, value interface{}) float64 {
+	var x float64
+	switch v := value.(type) {
+	case float64:
+		x = v
+	case int:
+		x = float64(v)
+	}
+	if _, ok := distribution.(LogUniform); ok {
+		return math.Log(x)
+	}
+	return x
+}
+
+// tpeInverse 将连续空间中的值映射回参数取值
+func tpeInverse(distribution ParamDistribution, x float64) interface{} {
+	switch d := distribution.(type) {
+	case LogUniform:
+		return math.Exp(x)
+	case IntUniform:
+		v := int(math.Round(x))
+		if v < d.Low {
+			v = d.Low
+		}
+		if v > d.High {
+			v = d.High
+		}
+		return v
+	}
+	return x
+}
+
+// tpeBandwidth 高斯核带宽，随观测数量增加而收窄
+func tpeBandwidth(low, high float64, n int) float64 {
+	return math.Max((high-low)/float64(n+1), (high-low)*0.01)
+}
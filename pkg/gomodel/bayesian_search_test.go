@@ -0,0 +1,109 @@
+package gomodel
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestBayesianSearchStartupMatchesRandomSearch(t *testing.T) {
+	data := ridgeData(120)
+	config := &ModelConfig{Algorithm: Ridge}
+	distributions := ParamDistributions{"lambda": LogUniform{Low: 1e-3, High: 1e4}}
+
+	// 前tpeStartupTrials轮为随机采样，与相同种子的RandomizedSearch评估同一批候选
+	bayesian, err := searchClient(11).BayesianSearch(data, config, distributions, tpeStartupTrials, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	random, err := searchClient(11).RandomizedSearch(data, config, distributions, tpeStartupTrials, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bayesian.Results, random.Results) {
+		t.Error("startup trials differ from randomized search with the same seed")
+	}
+}
+
+func TestBayesianSearchConcentratesOnOptimum(t *testing.T) {
+	data := ridgeData(120)
+	config := &ModelConfig{Algorithm: Ridge}
+	distributions := ParamDistributions{"lambda": LogUniform{Low: 1e-3, High: 1e4}}
+	const nIter = 30
+
+	result, err := searchClient(11).BayesianSearch(data, config, distributions, nIter, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Results) != nIter {
+		t.Fatalf("got %d results, want %d", len(result.Results), nIter)
+	}
+	checkRanking(t, result)
+
+	// 区分随机启动的候选和TPE提出的候选，后者应集中在lambda较小（得分较高）的区域
+	startup := make(map[float64]bool)
+	rng := rand.New(rand.NewSource(11))
+	for i := 0; i < tpeStartupTrials; i++ {
+		startup[sampleDistributions(distributions, rng)["lambda"].(float64)] = true
+	}
+	var startupLogs, suggestedLogs []float64
+	for _, r := range result.Results {
+		lambda := r.Parameters["lambda"].(float64)
+		if startup[lambda] {
+			startupLogs = append(startupLogs, math.Log10(lambda))
+		} else {
+			suggestedLogs = append(suggestedLogs, math.Log10(lambda))
+		}
+	}
+	if len(startupLogs) != tpeStartupTrials {
+		t.Fatalf("found %d startup trials, want %d", len(startupLogs), tpeStartupTrials)
+	}
+	if median(suggestedLogs) >= median(startupLogs) {
+		t.Errorf("median log10(lambda) of TPE suggestions = %.2f, startup = %.2f; want suggestions closer to the optimum",
+			median(suggestedLogs), median(startupLogs))
+	}
+
+	same, err := searchClient(11).BayesianSearch(data, config, distributions, nIter, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Results, same.Results) {
+		t.Error("bayesian search with the same seed returned different results")
+	}
+}
+
+func TestSuggestTPEChoice(t *testing.T) {
+	distributions := ParamDistributions{"solver": Choice{"a", "b", "c"}}
+	var candidates []map[string]interface{}
+	var results []*SearchResult
+	// "a"得分最高，"b"和"c"较差
+	for i, solver := range []string{"a", "a", "b", "c", "b", "c", "b", "c"} {
+		score := 0.0
+		if solver == "a" {
+			score = 1 + float64(i)
+		}
+		candidates = append(candidates, map[string]interface{}{"solver": solver})
+		results = append(results, &SearchResult{MeanScore: score})
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5; i++ {
+		suggestion := suggestTPE([]string{"solver"}, distributions, candidates, results, rng)
+		if suggestion["solver"] != "a" {
+			t.Errorf("suggestion %d = %v, want the best observed choice a", i, suggestion["solver"])
+		}
+	}
+}
+
+// median 返回中位数
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
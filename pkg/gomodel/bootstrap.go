@@ -0,0 +1,289 @@
+package gomodel
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// BootstrapConfig 自助法配置
+type BootstrapConfig struct {
+	Iterations int        `json:"iterations"`  // 重抽样次数，不大于0时为200
+	Level      float64    `json:"level"`       // 置信水平，不大于0时为0.95
+	RandomSeed int64      `json:"random_seed"` // 随机种子，为0时使用当前时间
+	Workers    int        `json:"workers"`     // 并发拟合的数量，不大于0时使用CPU核数
+	Features   *mat.Dense `json:"-"`           // 需要计算预测值区间的样本，为nil时不计算
+}
+
+// BootstrapInterval 自助法百分位置信区间
+type BootstrapInterval struct {
+	Estimate float64 `json:"estimate"`  // 在全部数据上拟合的估计值；指标为各次袋外得分的均值
+	Lower    float64 `json:"lower"`     // 自助估计的(1-Level)/2分位数
+	Upper    float64 `json:"upper"`     // 自助估计的(1+Level)/2分位数
+	StdError float64 `json:"std_error"` // 自助估计的标准差
+}
+
+// BootstrapResult 自助法结果
+type BootstrapResult struct {
+	Iterations   int                          `json:"iterations"` // 成功重新拟合的次数
+	Failed       int                          `json:"failed"`     // 拟合失败（如重抽样后只剩一个类别）的次数
+	Level        float64                      `json:"level"`
+	Intercept    *BootstrapInterval           `json:"intercept,omitempty"`    // 模型参数中没有截距时为nil
+	Coefficients []BootstrapInterval          `json:"coefficients,omitempty"` // 模型参数中没有系数时为空
+	Metrics      map[string]BootstrapInterval `json:"metrics"`                // 在每次重抽样的袋外样本上计算的指标
+	Predictions  []BootstrapInterval          `json:"predictions,omitempty"`  // BootstrapConfig.Features中各样本的预测值
+}
+
+// bootstrapSample 单次重抽样拟合的结果
+type bootstrapSample struct {
+	ok           bool
+	intercept    float64
+	coefficients []float64
+	metrics      map[string]float64
+	predictions  []float64
+}
+
+// Bootstrap 对任意算法的模型配置执行自助法：有放回地抽取与原数据等量的样本重新拟合Iterations次，
+// 返回系数、袋外指标和指定样本预测值的百分位置信区间。回归模型的指标为r2、mse、rmse、mae等，
+// 分类模型为accuracy、balanced_accuracy、f1、roc_auc、logloss和brier_score（正类概率按0.5的阈值转换为类别）。
+// 各次重抽样的种子由RandomSeed预先生成，结果与Workers无关
+func (c *Client) Bootstrap(data *TrainingData, config *ModelConfig, bootstrap *BootstrapConfig) (*BootstrapResult, error) {
+	if data == nil || config == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "training data and model config cannot be nil",
+		}
+	}
+	if !c.isValidAlgorithm(config.Algorithm) {
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("unsupported algorithm: %s", config.Algorithm),
+		}
+	}
+	if err := c.validateData(data); err != nil {
+		return nil, err
+	}
+	settings := BootstrapConfig{}
+	if bootstrap != nil {
+		settings = *bootstrap
+	}
+	if settings.Iterations <= 0 {
+		settings.Iterations = 200
+	}
+	if settings.Level <= 0 {
+		settings.Level = 0.95
+	}
+	if settings.Level >= 1 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("confidence level must be in (0, 1), got %v", settings.Level),
+		}
+	}
+	if settings.Workers <= 0 {
+		settings.Workers = runtime.NumCPU()
+	}
+	if settings.Features != nil {
+		_, cols := settings.Features.Dims()
+		if _, expected := data.Features.Dims(); cols != expected {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("prediction features have %d columns, training data has %d", cols, expected),
+			}
+		}
+	}
+
+	// 全部数据上的拟合给出系数和预测值的点估计
+	full, err := c.bootstrapFit(config, data, nil, settings.Features)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrTrainingFailed,
+			Message: "failed to train model on full data",
+			Details: err.Error(),
+		}
+	}
+
+	rng := rand.New(rand.NewSource(NewDataUtils(settings.RandomSeed).RandomSeed()))
+	seeds := make([]int64, settings.Iterations)
+	for b := range seeds {
+		seeds[b] = rng.Int63()
+	}
+	samples := make([]bootstrapSample, settings.Iterations)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(settings.Workers, settings.Iterations); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				samples[b] = c.bootstrapIteration(config, data, settings.Features, rand.New(rand.NewSource(seeds[b])))
+			}
+		}()
+	}
+	for b := range samples {
+		jobs <- b
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := &BootstrapResult{Level: settings.Level, Metrics: make(map[string]BootstrapInterval)}
+	var succeeded []bootstrapSample
+	for _, sample := range samples {
+		if sample.ok {
+			succeeded = append(succeeded, sample)
+		} else {
+			result.Failed++
+		}
+	}
+	result.Iterations = len(succeeded)
+	if len(succeeded) < 2 {
+		return nil, &Error{
+			Code:    ErrTrainingFailed,
+			Message: "bootstrap failed",
+			Details: fmt.Sprintf("only %d of %d resamples could be fitted", len(succeeded), settings.Iterations),
+		}
+	}
+
+	collect := func(value func(s bootstrapSample) (float64, bool)) []float64 {
+		var values []float64
+		for _, sample := range succeeded {
+			if v, ok := value(sample); ok {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	if full.hasIntercept {
+		interval := percentileInterval(full.intercept, collect(func(s bootstrapSample) (float64, bool) { return s.intercept, true }), settings.Level)
+		result.Intercept = &interval
+	}
+	for j, estimate := range full.coefficients {
+		result.Coefficients = append(result.Coefficients, percentileInterval(estimate, collect(func(s bootstrapSample) (float64, bool) {
+			if j >= len(s.coefficients) {
+				return 0, false
+			}
+			return s.coefficients[j], true
+		}), settings.Level))
+	}
+	for i, estimate := range full.predictions {
+		result.Predictions = append(result.Predictions, percentileInterval(estimate, collect(func(s bootstrapSample) (float64, bool) {
+			return s.predictions[i], true
+		}), settings.Level))
+	}
+
+	names := make(map[string]bool)
+	for _, sample := range succeeded {
+		for name := range sample.metrics {
+			names[name] = true
+		}
+	}
+	for name := range names {
+		values := collect(func(s bootstrapSample) (float64, bool) {
+			v, ok := s.metrics[name]
+			return v, ok
+		})
+		if len(values) < 2 {
+			continue
+		}
+		result.Metrics[name] = percentileInterval(stat.Mean(values, nil), values, settings.Level)
+	}
+	return result, nil
+}
+
+// bootstrapFitResult 单次拟合得到的参数和预测值
+type bootstrapFitResult struct {
+	model        models.Model
+	hasIntercept bool
+	intercept    float64
+	coefficients []float64
+	predictions  []float64
+}
+
+// bootstrapFit 在indices指定的样本（为nil时为全部样本）上拟合新模型，并在features上预测
+func (c *Client) bootstrapFit(config *ModelConfig, data *TrainingData, indices []int, features *mat.Dense) (*bootstrapFitResult, error) {
+	sample := data
+	if indices != nil {
+		sample = subsetTrainingData(data, indices)
+	}
+	model, err := c.manager.CreateModel(c.internalConfig(config))
+	if err != nil {
+		return nil, err
+	}
+	if err := model.FitWeighted(sample.Features, sample.Target, sample.Weights); err != nil {
+		return nil, err
+	}
+
+	fit := &bootstrapFitResult{model: model}
+	params := model.GetParameters()
+	fit.intercept, fit.hasIntercept = params["intercept"].(float64)
+	fit.coefficients, _ = params["coefficients"].([]float64)
+	if features != nil {
+		fit.predictions = mat.Col(nil, 0, model.Predict(features))
+	}
+	return fit, nil
+}
+
+// bootstrapIteration 执行一次有放回抽样、拟合和袋外评估
+func (c *Client) bootstrapIteration(config *ModelConfig, data *TrainingData, features *mat.Dense, rng *rand.Rand) bootstrapSample {
+	n := data.Target.Len()
+	indices := make([]int, n)
+	inBag := make([]bool, n)
+	for i := range indices {
+		indices[i] = rng.Intn(n)
+		inBag[indices[i]] = true
+	}
+	fit, err := c.bootstrapFit(config, data, indices, features)
+	if err != nil {
+		return bootstrapSample{}
+	}
+
+	sample := bootstrapSample{
+		ok:           true,
+		intercept:    fit.intercept,
+		coefficients: fit.coefficients,
+		predictions:  fit.predictions,
+	}
+	var outOfBag []int
+	for i, in := range inBag {
+		if !in {
+			outOfBag = append(outOfBag, i)
+		}
+	}
+	if len(outOfBag) > 0 {
+		oob := subsetTrainingData(data, outOfBag)
+		yTrue := mat.Col(nil, 0, oob.Target)
+		sample.metrics = heldOutMetrics(config, yTrue, mat.Col(nil, 0, fit.model.Predict(oob.Features)))
+	}
+	return sample
+}
+
+// heldOutMetrics 计算模型在未参与训练的样本（袋外样本或验证集）上的指标，
+// 无法计算的指标（如样本只有一个类别时的roc_auc）不记录
+func heldOutMetrics(config *ModelConfig, yTrue, predictions []float64) map[string]float64 {
+	if !isClassifier(config) {
+		return evaluation.ComputeMetrics(evaluation.MetricNames(false), yTrue, predictions)
+	}
+	return evaluation.ComputeMetrics([]string{"accuracy", "balanced_accuracy", "f1", "roc_auc", "logloss", "brier_score"}, yTrue, predictions)
+}
+
+// percentileInterval 由自助估计值计算百分位置信区间和标准差
+func percentileInterval(estimate float64, values []float64, level float64) BootstrapInterval {
+	interval := BootstrapInterval{Estimate: estimate}
+	if len(values) == 0 {
+		return interval
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	interval.Lower = stat.Quantile((1-level)/2, stat.LinInterp, sorted, nil)
+	interval.Upper = stat.Quantile((1+level)/2, stat.LinInterp, sorted, nil)
+	if len(values) > 1 {
+		interval.StdError = stat.StdDev(values, nil)
+	}
+	return interval
+}
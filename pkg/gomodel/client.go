@@ -2,9 +2,9 @@ package gomodel
 
 import (
 	"fmt"
+	"math"
 	"time"
 
-	"github.com/feiyuluoye/Go-Model/internal/data"
 	"github.com/feiyuluoye/Go-Model/internal/evaluation"
 	"github.com/feiyuluoye/Go-Model/internal/models"
 	"github.com/feiyuluoye/Go-Model/internal/types"
@@ -22,6 +22,7 @@ type ClientConfig struct {
 	DefaultValidation *ValidationConfig `json:"default_validation"`
 	RandomSeed        int64             `json:"random_seed"`
 	Verbose           bool              `json:"verbose"`
+	Validator         *Validator        `json:"validator,omitempty"` // 训练前的数据质量检查，为nil时使用NewValidator()
 }
 
 // NewClient 创建新的客户端实例
@@ -65,23 +66,12 @@ func (c *Client) Train(data *TrainingData, config *ModelConfig) (*ModelResult, e
 	if err := c.validateData(data); err != nil {
 		return nil, err
 	}
-
-	// 创建模型
-	modelID := fmt.Sprintf("%s_%d", config.Algorithm, time.Now().UnixNano())
-	err := c.manager.CreateModel(modelID, string(config.Algorithm), config.Parameters)
-	if err != nil {
-		return nil, &Error{
-			Code:    ErrTrainingFailed,
-			Message: "failed to create model",
-			Details: err.Error(),
-		}
+	if err := c.checkQuality(data); err != nil {
+		return nil, err
 	}
 
-	// 准备训练数据
-	X, y := c.prepareTrainingData(data)
-
 	// 执行训练
-	err = c.manager.TrainModel(modelID, X, y)
+	trainingResult, err := c.manager.TrainModelWeighted(c.internalConfig(config), data.Features, data.Target, data.Weights)
 	if err != nil {
 		return nil, &Error{
 			Code:    ErrTrainingFailed,
@@ -89,28 +79,20 @@ func (c *Client) Train(data *TrainingData, config *ModelConfig) (*ModelResult, e
 			Details: err.Error(),
 		}
 	}
-
-	// 计算训练分数
-	trainingScore, err := c.manager.EvaluateModel(modelID, X, y)
-	if err != nil {
-		return nil, &Error{
-			Code:    ErrTrainingFailed,
-			Message: "failed to evaluate training score",
-			Details: err.Error(),
-		}
-	}
+	modelID := trainingResult.ModelID
 
 	// 构建结果
 	result := &ModelResult{
+		ModelID:       modelID,
 		Algorithm:     config.Algorithm,
 		Parameters:    config.Parameters,
-		TrainingScore: trainingScore,
+		TrainingScore: trainingResult.TrainingScore,
 		Metrics:       make(map[string]float64),
 		ModelInfo:     make(map[string]interface{}),
 	}
 
 	// 计算额外指标
-	c.calculateMetrics(result, modelID, X, y, config.LossFunction)
+	c.calculateMetrics(result, modelID, data, config)
 
 	// 执行验证（如果配置了）
 	if config.Validation != nil {
@@ -123,9 +105,13 @@ func (c *Client) Train(data *TrainingData, config *ModelConfig) (*ModelResult, e
 	// 获取模型信息
 	modelInfo, err := c.manager.GetModelInfo(modelID)
 	if err == nil {
+		result.ModelInfo["model_id"] = modelID
 		result.ModelInfo["model_type"] = modelInfo.ModelType
-		result.ModelInfo["created_at"] = modelInfo.CreatedAt
-		result.ModelInfo["trained"] = modelInfo.Trained
+		result.ModelInfo["created_at"] = time.Now().Format(time.RFC3339)
+		result.ModelInfo["trained"] = modelInfo.IsTrained
+		result.ModelInfo["data_hash"] = data.Fingerprint()
+		result.Convergence = convergenceInfo(modelInfo.Parameters)
+		result.Inference = inferenceInfo(modelInfo.Parameters)
 	}
 
 	return result, nil
@@ -140,18 +126,8 @@ func (c *Client) Predict(modelID string, features *mat.Dense) (*PredictionResult
 		}
 	}
 
-	// 转换数据格式
-	r, c_count := features.Dims()
-	X := make([][]float64, r)
-	for i := 0; i < r; i++ {
-		X[i] = make([]float64, c_count)
-		for j := 0; j < c_count; j++ {
-			X[i][j] = features.At(i, j)
-		}
-	}
-
 	// 执行预测
-	predictions, err := c.manager.PredictModel(modelID, X)
+	prediction, err := c.manager.Predict(modelID, features)
 	if err != nil {
 		return nil, &Error{
 			Code:    ErrPredictionFailed,
@@ -161,13 +137,13 @@ func (c *Client) Predict(modelID string, features *mat.Dense) (*PredictionResult
 	}
 
 	result := &PredictionResult{
-		Predictions: predictions,
+		Predictions: prediction.Predictions,
 		Metadata:    make(map[string]interface{}),
 	}
 
 	// 添加元数据
 	result.Metadata["model_id"] = modelID
-	result.Metadata["prediction_count"] = len(predictions)
+	result.Metadata["prediction_count"] = len(prediction.Predictions)
 	result.Metadata["predicted_at"] = time.Now().Format(time.RFC3339)
 
 	return result, nil
@@ -181,35 +157,34 @@ func (c *Client) TrainAndPredict(trainData *TrainingData, testFeatures *mat.Dens
 		return nil, nil, err
 	}
 
-	// 生成临时模型ID进行预测
-	modelID := fmt.Sprintf("%s_%d", config.Algorithm, time.Now().UnixNano())
-	
-	// 重新创建和训练模型用于预测
-	err = c.manager.CreateModel(modelID, string(config.Algorithm), config.Parameters)
+	// 使用刚训练好的模型进行预测
+	predictions, err := c.Predict(result.ModelID, testFeatures)
 	if err != nil {
 		return result, nil, err
 	}
 
-	X, y := c.prepareTrainingData(trainData)
-	err = c.manager.TrainModel(modelID, X, y)
-	if err != nil {
-		return result, nil, err
-	}
+	return result, predictions, nil
+}
 
-	// 进行预测
-	predictions, err := c.Predict(modelID, testFeatures)
+// CreateEnsemble 将已训练的模型组合为投票集成，返回新模型的ID
+// voting为"average"时对预测加权平均（回归），为"majority"时加权多数投票（分类）；weights为空时等权
+func (c *Client) CreateEnsemble(modelIDs []string, weights []float64, voting string) (string, error) {
+	modelID, err := c.manager.CreateVotingEnsemble(modelIDs, weights, voting)
 	if err != nil {
-		return result, nil, err
+		return "", &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to create ensemble",
+			Details: err.Error(),
+		}
 	}
-
-	return result, predictions, nil
+	return modelID, nil
 }
 
 // GetSupportedAlgorithms 获取支持的算法列表
 func (c *Client) GetSupportedAlgorithms() []AlgorithmType {
 	return []AlgorithmType{
-		OLS, Ridge, Lasso, Logistic, PLS,
-		Polynomial, Exponential, Logarithmic, Power,
+		OLS, Ridge, Lasso, RidgeCV, LassoCV, Logistic, PLS,
+		Polynomial, Exponential, Logarithmic, Power, Stacking, Voting, Bagging, Calibrated,
 	}
 }
 
@@ -246,14 +221,25 @@ func (c *Client) isValidAlgorithm(algorithm AlgorithmType) bool {
 }
 
 func (c *Client) validateData(data *TrainingData) error {
-	if data.Features == nil || data.Target == nil {
+	return validateTrainingData(data)
+}
+
+// validateTrainingData 检查训练数据的维度和样本权重，Client和ModelManager训练前共用
+func validateTrainingData(data *TrainingData) error {
+	if data == nil || data.Features == nil || data.Target == nil {
 		return &Error{
 			Code:    ErrInvalidData,
 			Message: "features and target cannot be nil",
 		}
 	}
 
-	r, _ := data.Features.Dims()
+	r, cols := data.Features.Dims()
+	if r == 0 || cols == 0 {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "training data cannot be empty",
+		}
+	}
 	targetLen := data.Target.Len()
 
 	if r != targetLen {
@@ -263,17 +249,81 @@ func (c *Client) validateData(data *TrainingData) error {
 		}
 	}
 
+	if data.Weights != nil {
+		if data.Weights.Len() != r {
+			return &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("feature rows (%d) must match weights length (%d)", r, data.Weights.Len()),
+			}
+		}
+		total := 0.0
+		for i := 0; i < r; i++ {
+			w := data.Weights.AtVec(i)
+			if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+				return &Error{
+					Code:    ErrInvalidData,
+					Message: "sample weights must be finite and non-negative",
+				}
+			}
+			total += w
+		}
+		if total == 0 {
+			return &Error{
+				Code:    ErrInvalidData,
+				Message: "sample weights must not all be zero",
+			}
+		}
+	}
+
 	return nil
 }
 
+// checkQuality 使用客户端配置的检查器检查数据质量，存在致命问题时拒绝训练
+func (c *Client) checkQuality(data *TrainingData) error {
+	return checkQuality(c.config.Validator, data)
+}
+
+// checkQuality 使用validator检查数据质量，validator为nil时使用NewValidator()
+func checkQuality(validator *Validator, data *TrainingData) error {
+	if validator == nil {
+		validator = NewValidator()
+	}
+	report, err := validator.Validate(data)
+	if err != nil {
+		return err
+	}
+	return report.Err()
+}
+
+// convergenceInfo 从模型参数中提取迭代求解器的收敛信息，模型不是迭代求解时返回nil
+func convergenceInfo(params map[string]interface{}) *ConvergenceInfo {
+	gradNorm, ok := params["gradient_norm"].(float64)
+	if !ok {
+		return nil
+	}
+	info := &ConvergenceInfo{GradientNorm: gradNorm}
+	info.Solver, _ = params["solver"].(string)
+	info.Iterations, _ = params["iterations"].(int)
+	info.Converged, _ = params["converged"].(bool)
+	return info
+}
+
+// weightsSlice 将样本权重向量转换为切片，weights为nil时返回nil
+func weightsSlice(weights *mat.VecDense) []float64 {
+	if weights == nil {
+		return nil
+	}
+	return mat.Col(nil, 0, weights)
+}
+
 func (c *Client) prepareTrainingData(data *TrainingData) ([][]float64, []float64) {
-	r, c := data.Features.Dims()
-	
+	r, cols := data.Features.Dims()
+
 	// 转换特征矩阵
 	X := make([][]float64, r)
 	for i := 0; i < r; i++ {
-		X[i] = make([]float64, c)
-		for j := 0; j < c; j++ {
+		X[i] = make([]float64, cols)
+		for j := 0; j < cols; j++ {
 			X[i][j] = data.Features.At(i, j)
 		}
 	}
@@ -287,28 +337,158 @@ func (c *Client) prepareTrainingData(data *TrainingData) ([][]float64, []float64
 	return X, y
 }
 
-func (c *Client) calculateMetrics(result *ModelResult, modelID string, X [][]float64, y []float64, lossFunc LossFunction) {
+// internalConfig 将公共模型配置转换为内部模型管理器使用的配置
+func (c *Client) internalConfig(config *ModelConfig) *models.ModelConfig {
+	return toInternalConfig(config)
+}
+
+// toInternalConfig 将公共模型配置转换为内部配置，参数中嵌套的模型配置（如集成模型的成员）一并转换
+func toInternalConfig(config *ModelConfig) *models.ModelConfig {
+	return &models.ModelConfig{
+		ModelType:  string(config.Algorithm),
+		Parameters: internalParameters(config.Parameters),
+	}
+}
+
+// internalParameters 转换参数中嵌套的*ModelConfig及其列表，其余参数原样保留
+func internalParameters(params map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		switch v := value.(type) {
+		case *ModelConfig:
+			converted[key] = toInternalConfig(v)
+		case []*ModelConfig:
+			configs := make([]*models.ModelConfig, len(v))
+			for i, config := range v {
+				configs[i] = toInternalConfig(config)
+			}
+			converted[key] = configs
+		default:
+			converted[key] = value
+		}
+	}
+	return converted
+}
+
+// calculateMetrics 计算训练数据上的评估指标：分类模型计算准确率、精确率、召回率、F1、ROC AUC、平均精确率、对数损失等分类指标，
+// 其他模型计算R2和RMSE；此外总是计算损失函数对应的指标
+func (c *Client) calculateMetrics(result *ModelResult, modelID string, data *TrainingData, config *ModelConfig) {
 	// 获取预测值
-	predictions, err := c.manager.PredictModel(modelID, X)
+	prediction, err := c.manager.Predict(modelID, data.Features)
 	if err != nil {
 		return
 	}
+	_, y := c.prepareTrainingData(data)
+	predictions := prediction.Predictions
 
-	// 计算各种指标
-	switch lossFunc {
-	case MSE:
-		result.Metrics["mse"] = c.calculateMSE(y, predictions)
-	case MAE:
-		result.Metrics["mae"] = c.calculateMAE(y, predictions)
-	case RMSE:
-		result.Metrics["rmse"] = c.calculateRMSE(y, predictions)
+	// 损失函数对应的指标，名称即已注册的指标名称
+	switch config.LossFunction {
+	case "":
 	case R2:
 		result.Metrics["r2"] = result.TrainingScore // R2 已经在TrainingScore中
+	case Accuracy:
+		// 不能使用TrainingScore：Bagging和平均模式的Voting等集成模型的Score不是准确率
+		if value, err := evaluation.ComputeMetric("accuracy", y, predictions); err == nil {
+			result.Metrics["accuracy"] = value
+		}
+	case LogLoss:
+		if loss, ok := c.logLoss(modelID, y, predictions); ok {
+			result.Metrics["logloss"] = loss
+		}
+	default:
+		if value, err := evaluation.ComputeMetric(string(config.LossFunction), y, predictions); err == nil {
+			result.Metrics[string(config.LossFunction)] = value
+		}
 	}
 
-	// 总是计算R2和RMSE作为基本指标
+	if isClassifier(config) {
+		// 分类模型预测的是正类概率，按0.5的阈值转换为类别
+		classes := make([]float64, len(predictions))
+		for i, p := range predictions {
+			if p >= 0.5 {
+				classes[i] = 1
+			}
+		}
+		// 已由损失函数计算的指标优先
+		if metrics, err := evaluation.EvaluateClassifier(y, classes); err == nil {
+			for name, value := range metrics {
+				if _, ok := result.Metrics[name]; !ok {
+					result.Metrics[name] = value
+				}
+			}
+		}
+		if _, ok := result.Metrics["logloss"]; !ok {
+			if loss, ok := c.logLoss(modelID, y, predictions); ok {
+				result.Metrics["logloss"] = loss
+			}
+		}
+		// 其余已注册的分类指标（包括roc_auc、average_precision、brier_score和自定义指标）；
+		// 训练数据只有一个类别时AUC和AP没有定义，不记录
+		for name, value := range evaluation.ComputeMetrics(evaluation.MetricNames(true), y, predictions) {
+			if _, ok := result.Metrics[name]; !ok {
+				result.Metrics[name] = value
+			}
+		}
+		// 信息准则使用不加权的对数似然 -n·logloss
+		if loss, err := evaluation.LogLoss(y, predictions, nil); err == nil {
+			nSamples, nFeatures := data.Features.Dims()
+			addInformationCriteria(result.Metrics, -float64(nSamples)*loss, nSamples, nFeatures)
+		}
+		return
+	}
+
+	// 回归模型总是计算R2和RMSE作为基本指标
 	result.Metrics["r2"] = result.TrainingScore
 	result.Metrics["rmse"] = c.calculateRMSE(y, predictions)
+
+	nSamples, nFeatures := data.Features.Dims()
+	if adjusted, err := evaluation.AdjustedR2(result.TrainingScore, nSamples, nFeatures); err == nil {
+		result.Metrics["adjusted_r2"] = adjusted
+	}
+	if logLikelihood, err := evaluation.GaussianLogLikelihood(y, predictions); err == nil {
+		addInformationCriteria(result.Metrics, logLikelihood, nSamples, nFeatures)
+	}
+}
+
+// addInformationCriteria 按特征数加截距作为参数个数，记录aic和bic指标。
+// 对树模型、集成模型等非线性模型，参数个数只是近似，这两个指标仅供参考
+func addInformationCriteria(metrics map[string]float64, logLikelihood float64, nSamples, nFeatures int) {
+	nParams := nFeatures + 1
+	metrics["aic"] = evaluation.AIC(logLikelihood, nParams)
+	metrics["bic"] = evaluation.BIC(logLikelihood, nParams, nSamples)
+}
+
+// isClassifier 判断模型配置是否为（二）分类模型：Logistic和Calibrated，
+// 以及基模型全部为分类模型的Bagging、Voting和Stacking
+func isClassifier(config *ModelConfig) bool {
+	if config == nil {
+		return false
+	}
+	switch config.Algorithm {
+	case Logistic, Calibrated:
+		return true
+	case Bagging:
+		base, ok := config.Parameters["base_model"].(*ModelConfig)
+		return ok && isClassifier(base)
+	case Voting:
+		members, ok := config.Parameters["models"].([]*ModelConfig)
+		return ok && allClassifiers(members)
+	case Stacking:
+		members, ok := config.Parameters["base_models"].([]*ModelConfig)
+		meta, metaOK := config.Parameters["meta_model"].(*ModelConfig)
+		return ok && metaOK && allClassifiers(members) && isClassifier(meta)
+	}
+	return false
+}
+
+// allClassifiers 判断所有模型配置都是分类模型，列表为空时返回false
+func allClassifiers(configs []*ModelConfig) bool {
+	for _, config := range configs {
+		if !isClassifier(config) {
+			return false
+		}
+	}
+	return len(configs) > 0
 }
 
 func (c *Client) performValidation(result *ModelResult, modelID string, data *TrainingData, config *ModelConfig) error {
@@ -331,32 +511,21 @@ func (c *Client) performValidation(result *ModelResult, modelID string, data *Tr
 }
 
 func (c *Client) performHoldoutValidation(result *ModelResult, data *TrainingData, config *ModelConfig, validation *ValidationConfig) error {
-	// 分割数据
-	X, y := c.prepareTrainingData(data)
-	
-	// 这里应该实现数据分割逻辑
-	// 为简化，暂时使用全部数据作为验证集
-	testScore := result.TrainingScore
+	testScore, err := c.holdoutScore(data, config, validation)
+	if err != nil {
+		return &Error{
+			Code:    ErrValidationFailed,
+			Message: "holdout validation failed",
+			Details: err.Error(),
+		}
+	}
+
 	result.ValidationScore = &testScore
-	
 	return nil
 }
 
 func (c *Client) performKFoldValidation(result *ModelResult, data *TrainingData, config *ModelConfig, validation *ValidationConfig) error {
-	// 实现K折交叉验证
-	X, y := c.prepareTrainingData(data)
-	
-	// 转换为internal包需要的格式
-	dataset := &types.Dataset{
-		Features: X,
-		Target:   y,
-	}
-
-	// 创建交叉验证器
-	cv := evaluation.NewCrossValidator(validation.KFolds, validation.RandomSeed)
-	
-	// 执行交叉验证
-	scores, err := cv.Validate(dataset, string(config.Algorithm), config.Parameters)
+	scores, err := c.kfoldScores(data, config, validation)
 	if err != nil {
 		return &Error{
 			Code:    ErrValidationFailed,
@@ -367,12 +536,12 @@ func (c *Client) performKFoldValidation(result *ModelResult, data *TrainingData,
 
 	// 计算统计信息
 	meanScore, stdScore := c.calculateStats(scores)
-	
+
 	result.CrossValidation = &CVResult{
 		Scores:    scores,
 		MeanScore: meanScore,
 		StdScore:  stdScore,
-		FoldCount: validation.KFolds,
+		FoldCount: len(scores),
 	}
 
 	result.ValidationScore = &meanScore
@@ -380,6 +549,110 @@ func (c *Client) performKFoldValidation(result *ModelResult, data *TrainingData,
 	return nil
 }
 
+// validationScores 按验证配置评估模型配置，holdout返回单个得分，kfold返回每折得分
+func (c *Client) validationScores(data *TrainingData, config *ModelConfig, validation *ValidationConfig) ([]float64, error) {
+	switch validation.Method {
+	case "holdout":
+		score, err := c.holdoutScore(data, config, validation)
+		if err != nil {
+			return nil, err
+		}
+		return []float64{score}, nil
+	case "kfold":
+		return c.kfoldScores(data, config, validation)
+	default:
+		return nil, fmt.Errorf("unsupported validation method: %s", validation.Method)
+	}
+}
+
+// holdoutScore 在随机划分的训练集上拟合新模型，并返回其在测试集上的得分
+func (c *Client) holdoutScore(data *TrainingData, config *ModelConfig, validation *ValidationConfig) (float64, error) {
+	trainData, testData, err := splitHoldout(data, validation)
+	if err != nil {
+		return 0, err
+	}
+
+	model, err := c.manager.CreateModel(c.internalConfig(config))
+	if err != nil {
+		return 0, err
+	}
+	if err := model.FitWeighted(trainData.Features, trainData.Target, trainData.Weights); err != nil {
+		return 0, err
+	}
+
+	return scoreModel(model, testData.Features, testData.Target, config, validation)
+}
+
+// validationScoring 返回验证时的评分方式：优先使用验证配置中的ScoreFunc和Scoring；否则损失函数为LogLoss时
+// 使用负对数损失（越大越好），其余使用模型的Score方法。validation可以为nil
+func validationScoring(config *ModelConfig, validation *ValidationConfig) (string, ScoreFunc) {
+	if validation != nil {
+		if validation.ScoreFunc != nil || validation.Scoring != "" {
+			return validation.Scoring, validation.ScoreFunc
+		}
+	}
+	if config.LossFunction == LogLoss {
+		return evaluation.ScoringNegLogLoss, nil
+	}
+	return evaluation.ScoringDefault, nil
+}
+
+// scoreModel 按验证配置的评分方式计算已训练模型在数据上的得分
+func scoreModel(model models.Model, X *mat.Dense, y *mat.VecDense, config *ModelConfig, validation *ValidationConfig) (float64, error) {
+	scoring, score := validationScoring(config, validation)
+	if score != nil {
+		return evaluation.ScoreModelWith(model, X, y, score)
+	}
+	return evaluation.ScoreModel(model, X, y, scoring)
+}
+
+// splitHoldout 按验证配置划分holdout验证的训练集和测试集，Stratify为true时按目标类别分层
+func splitHoldout(data *TrainingData, validation *ValidationConfig) (*TrainingData, *TrainingData, error) {
+	return NewDataUtils(validation.RandomSeed).Split(data, SplitOptions{
+		TestSize: validation.TestSize,
+		Shuffle:  true,
+		Stratify: validation.Stratify,
+	})
+}
+
+// kfoldScores 执行K折交叉验证并返回每折得分
+func (c *Client) kfoldScores(data *TrainingData, config *ModelConfig, validation *ValidationConfig) ([]float64, error) {
+	cv, dataset, folds, err := c.crossValidationFolds(data, config, validation)
+	if err != nil {
+		return nil, err
+	}
+	return cv.ValidateFolds(dataset, string(config.Algorithm), internalParameters(config.Parameters), folds)
+}
+
+// crossValidationFolds 按验证配置构造交叉验证器、internal包的数据集和各折划分，
+// 设置了Splitter时按其划分，否则按KFolds和RandomSeed随机划分；分类模型或Stratify为true时按类别分层
+func (c *Client) crossValidationFolds(data *TrainingData, config *ModelConfig, validation *ValidationConfig) (*evaluation.CrossValidator, *types.Dataset, []Fold, error) {
+	X, y := c.prepareTrainingData(data)
+
+	// 转换为internal包需要的格式
+	dataset := &types.Dataset{
+		Features: X,
+		Target:   y,
+		Weights:  weightsSlice(data.Weights),
+	}
+
+	cv := evaluation.NewCrossValidator(validation.KFolds, validation.RandomSeed)
+	cv.Scoring, cv.ScoreFunc = validationScoring(config, validation)
+	cv.Stratify = validation.Stratify || isClassifier(config)
+	cv.Workers = validation.Workers
+	var folds []Fold
+	var err error
+	if validation.Splitter != nil {
+		folds, err = validation.Splitter.Split(data)
+	} else {
+		folds, err = cv.Split(y)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cv, dataset, folds, nil
+}
+
 func (c *Client) validateAlgorithmParameters(algorithm AlgorithmType, params map[string]interface{}) error {
 	// 根据不同算法验证参数
 	switch algorithm {
@@ -394,6 +667,119 @@ func (c *Client) validateAlgorithmParameters(algorithm AlgorithmType, params map
 				}
 			}
 		}
+	case RidgeCV, LassoCV:
+		if folds, ok := params["cv_folds"]; ok {
+			if foldsVal, ok := folds.(int); ok {
+				if foldsVal < 2 {
+					return &Error{
+						Code:    ErrInvalidParameters,
+						Message: "cv_folds must be at least 2",
+					}
+				}
+			}
+		}
+	case Logistic:
+		if solver, ok := params["solver"].(string); ok && solver != "lbfgs" && solver != "gd" && solver != "newton" && solver != "irls" {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: "solver must be \"lbfgs\", \"gd\", \"newton\" or \"irls\"",
+			}
+		}
+		if classWeight, ok := params["class_weight"]; ok {
+			switch v := classWeight.(type) {
+			case string:
+				if v != "balanced" {
+					return &Error{
+						Code:    ErrInvalidParameters,
+						Message: "class_weight must be \"balanced\" or a map[float64]float64",
+					}
+				}
+			case map[float64]float64:
+				for label, w := range v {
+					if label != 0 && label != 1 {
+						return &Error{
+							Code:    ErrInvalidParameters,
+							Message: fmt.Sprintf("class_weight has unknown class %v, expected 0 or 1", label),
+						}
+					}
+					if w <= 0 {
+						return &Error{
+							Code:    ErrInvalidParameters,
+							Message: "class weights must be positive",
+						}
+					}
+				}
+			default:
+				return &Error{
+					Code:    ErrInvalidParameters,
+					Message: "class_weight must be \"balanced\" or a map[float64]float64",
+				}
+			}
+		}
+	case Stacking:
+		baseModels, _ := params["base_models"].([]*ModelConfig)
+		if len(baseModels) == 0 {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: "stacking requires base_models as a non-empty []*ModelConfig",
+			}
+		}
+		for _, base := range append(baseModels, metaModelConfig(params)...) {
+			if err := c.ValidateConfig(base); err != nil {
+				return err
+			}
+		}
+	case Voting:
+		members, _ := params["models"].([]*ModelConfig)
+		if len(members) == 0 {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: "voting requires models as a non-empty []*ModelConfig",
+			}
+		}
+		for _, member := range members {
+			if err := c.ValidateConfig(member); err != nil {
+				return err
+			}
+		}
+		if voting, ok := params["voting"].(string); ok && voting != "average" && voting != "majority" {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: "voting must be \"average\" or \"majority\"",
+			}
+		}
+	case Bagging:
+		if base, ok := params["base_model"].(*ModelConfig); ok {
+			if err := c.ValidateConfig(base); err != nil {
+				return err
+			}
+		}
+		for _, key := range []string{"max_samples", "max_features"} {
+			if fraction, ok := params[key].(float64); ok && (fraction <= 0 || fraction > 1) {
+				return &Error{
+					Code:    ErrInvalidParameters,
+					Message: fmt.Sprintf("%s must be in (0, 1]", key),
+				}
+			}
+		}
+	case Calibrated:
+		if base, ok := params["base_model"].(*ModelConfig); ok {
+			if err := c.ValidateConfig(base); err != nil {
+				return err
+			}
+		}
+		if method, ok := params["method"].(string); ok && method != "sigmoid" && method != "isotonic" {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: "method must be \"sigmoid\" or \"isotonic\"",
+			}
+		}
+		if size, ok := params["calibration_size"].(float64); ok && (size <= 0 || size >= 1) {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: "calibration_size must be in (0, 1)",
+			}
+		}
 	case Polynomial:
 		if degree, ok := params["degree"]; ok {
 			if degreeVal, ok := degree.(int); ok {
@@ -421,6 +807,14 @@ func (c *Client) validateAlgorithmParameters(algorithm AlgorithmType, params map
 	return nil
 }
 
+// metaModelConfig 返回堆叠集成中指定的元模型配置，未指定时返回空列表
+func metaModelConfig(params map[string]interface{}) []*ModelConfig {
+	if meta, ok := params["meta_model"].(*ModelConfig); ok && meta != nil {
+		return []*ModelConfig{meta}
+	}
+	return nil
+}
+
 // 统计计算辅助方法
 
 func (c *Client) calculateMSE(actual, predicted []float64) float64 {
@@ -436,25 +830,20 @@ func (c *Client) calculateMSE(actual, predicted []float64) float64 {
 	return sum / float64(len(actual))
 }
 
-func (c *Client) calculateMAE(actual, predicted []float64) float64 {
-	if len(actual) != len(predicted) {
-		return 0
+// logLoss 计算模型预测概率的对数损失，模型设置了类别权重时按样本所属类别加权；
+// 标签不是0/1等无法计算时返回false
+func (c *Client) logLoss(modelID string, actual, predicted []float64) (float64, bool) {
+	var weights []float64
+	if info, err := c.manager.GetModelInfo(modelID); err == nil {
+		weights = evaluation.ClassSampleWeights(info.Parameters, actual)
 	}
-
-	sum := 0.0
-	for i := range actual {
-		diff := actual[i] - predicted[i]
-		if diff < 0 {
-			diff = -diff
-		}
-		sum += diff
-	}
-	return sum / float64(len(actual))
+	loss, err := evaluation.LogLoss(actual, predicted, weights)
+	return loss, err == nil
 }
 
 func (c *Client) calculateRMSE(actual, predicted []float64) float64 {
 	mse := c.calculateMSE(actual, predicted)
-	return mat.Sqrt(mse)
+	return math.Sqrt(mse)
 }
 
 func (c *Client) calculateStats(values []float64) (mean, std float64) {
@@ -475,7 +864,7 @@ func (c *Client) calculateStats(values []float64) (mean, std float64) {
 		diff := v - mean
 		sumSquares += diff * diff
 	}
-	std = mat.Sqrt(sumSquares / float64(len(values)))
+	std = math.Sqrt(sumSquares / float64(len(values)))
 
 	return mean, std
 }
@@ -0,0 +1,246 @@
+package gomodel
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 未被任何变换选中的列的处理方式
+const (
+	RemainderDrop        = "drop"
+	RemainderPassthrough = "passthrough"
+)
+
+// ColumnTransform 作用于一组列的变换，Transformer为nil时这些列原样保留
+type ColumnTransform struct {
+	Name        string      `json:"name"`
+	Transformer Transformer `json:"-"`
+	Columns     []string    `json:"columns"` // 按特征名选择列
+}
+
+// ColumnTransformer 对不同的列子集应用不同的变换器（如对数值列标准化、对类别列编码），
+// 并按变换定义的顺序横向拼接结果；Remainder决定未被选中的列是丢弃还是原样追加在末尾
+type ColumnTransformer struct {
+	Transforms []ColumnTransform `json:"transforms"`
+	Remainder  string            `json:"remainder"`
+	indices    [][]int
+	remainder  []int
+	nFeatures  int
+	fitted     bool
+}
+
+// NewColumnTransformer 创建新的列变换器，默认丢弃未被选中的列
+func NewColumnTransformer(transforms ...ColumnTransform) *ColumnTransformer {
+	return &ColumnTransformer{
+		Transforms: transforms,
+		Remainder:  RemainderDrop,
+	}
+}
+
+// Fit 解析各变换选择的列，并在对应的列子集上拟合每个变换器
+func (ct *ColumnTransformer) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if ct.Remainder != RemainderDrop && ct.Remainder != RemainderPassthrough {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported remainder: %s", ct.Remainder),
+		}
+	}
+
+	ct.fitted = false
+	names := columnNames(d)
+	position := make(map[string]int, len(names))
+	for j, name := range names {
+		position[name] = j
+	}
+
+	selected := make([]bool, len(names))
+	ct.indices = make([][]int, len(ct.Transforms))
+	for t, transform := range ct.Transforms {
+		if len(transform.Columns) == 0 {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: fmt.Sprintf("column transform %q selects no columns", transform.Name),
+			}
+		}
+		for _, column := range transform.Columns {
+			j, ok := position[column]
+			if !ok {
+				return &Error{
+					Code:    ErrInvalidData,
+					Message: fmt.Sprintf("column %q used by transform %q not found", column, transform.Name),
+				}
+			}
+			ct.indices[t] = append(ct.indices[t], j)
+			selected[j] = true
+		}
+
+		if transform.Transformer != nil {
+			if err := transform.Transformer.Fit(selectColumns(d, ct.indices[t], names)); err != nil {
+				return &Error{
+					Code:    ErrInvalidData,
+					Message: fmt.Sprintf("column transform %q failed to fit", transform.Name),
+					Details: err.Error(),
+				}
+			}
+		}
+	}
+
+	ct.remainder = nil
+	for j, used := range selected {
+		if !used {
+			ct.remainder = append(ct.remainder, j)
+		}
+	}
+	ct.nFeatures = len(names)
+	ct.fitted = true
+	return nil
+}
+
+// Transform 对各列子集应用已拟合的变换器并拼接结果
+func (ct *ColumnTransformer) Transform(d *TrainingData) (*TrainingData, error) {
+	if !ct.fitted {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	if d == nil || d.Features == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if _, c := d.Features.Dims(); c != ct.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", ct.nFeatures, c),
+		}
+	}
+
+	names := columnNames(d)
+	parts := make([]*TrainingData, 0, len(ct.Transforms)+1)
+	for t, transform := range ct.Transforms {
+		part := selectColumns(d, ct.indices[t], names)
+		if transform.Transformer != nil {
+			var err error
+			if part, err = transform.Transformer.Transform(part); err != nil {
+				return nil, &Error{
+					Code:    ErrInvalidData,
+					Message: fmt.Sprintf("column transform %q failed to transform", transform.Name),
+					Details: err.Error(),
+				}
+			}
+		}
+		parts = append(parts, part)
+	}
+	if ct.Remainder == RemainderPassthrough && len(ct.remainder) > 0 {
+		parts = append(parts, selectColumns(d, ct.remainder, names))
+	}
+
+	if len(parts) == 0 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "column transformer selects no columns",
+		}
+	}
+
+	return concatColumns(d, parts), nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (ct *ColumnTransformer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(ct, d)
+}
+
+// columnNames 返回数据的特征名，未提供时使用默认名称feature_i
+func columnNames(d *TrainingData) []string {
+	_, c := d.Features.Dims()
+	if len(d.FeatureNames) == c {
+		return d.FeatureNames
+	}
+	names := make([]string, c)
+	for j := range names {
+		names[j] = fmt.Sprintf("feature_%d", j)
+	}
+	return names
+}
+
+// selectColumns 按列索引提取特征子集，目标变量保持不变
+func selectColumns(d *TrainingData, indices []int, names []string) *TrainingData {
+	r, _ := d.Features.Dims()
+	features := mat.NewDense(r, len(indices), nil)
+	selectedNames := make([]string, len(indices))
+	for k, j := range indices {
+		for i := 0; i < r; i++ {
+			features.Set(i, k, d.Features.At(i, j))
+		}
+		selectedNames[k] = names[j]
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: selectedNames,
+		TargetName:   d.TargetName,
+		Categories:   categoriesFor(d.Categories, selectedNames),
+	}
+}
+
+// concatColumns 横向拼接多个特征子集
+func concatColumns(original *TrainingData, parts []*TrainingData) *TrainingData {
+	r, _ := original.Features.Dims()
+	total := 0
+	for _, part := range parts {
+		_, c := part.Features.Dims()
+		total += c
+	}
+
+	features := mat.NewDense(r, total, nil)
+	names := make([]string, 0, total)
+	categories := make(map[string][]string)
+	offset := 0
+	for _, part := range parts {
+		for name, labels := range part.Categories {
+			categories[name] = labels
+		}
+		_, c := part.Features.Dims()
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				features.Set(i, offset+j, part.Features.At(i, j))
+			}
+		}
+		names = append(names, columnNames(part)...)
+		offset += c
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       original.Target,
+		Weights:      original.Weights,
+		FeatureNames: names,
+		TargetName:   original.TargetName,
+		Categories:   categoriesFor(categories, names),
+	}
+}
+
+// categoriesFor 返回names中类别特征的类别表，没有类别特征时返回nil
+func categoriesFor(categories map[string][]string, names []string) map[string][]string {
+	var result map[string][]string
+	for _, name := range names {
+		if labels, ok := categories[name]; ok {
+			if result == nil {
+				result = make(map[string][]string)
+			}
+			result[name] = labels
+		}
+	}
+	return result
+}
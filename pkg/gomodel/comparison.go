@@ -0,0 +1,146 @@
+package gomodel
+
+import (
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+)
+
+// PairedTest 配对检验的统计量、双侧p值以及在显著性水平下差异是否显著
+type PairedTest struct {
+	Statistic   float64 `json:"statistic"`
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"`
+}
+
+// PairwiseComparison 两个模型配置在相同各折上的得分比较，差值为A减B
+type PairwiseComparison struct {
+	ModelA         int        `json:"model_a"` // 在configs中的下标
+	ModelB         int        `json:"model_b"`
+	MeanDifference float64    `json:"mean_difference"`  // 平均得分之差，为正表示A更好
+	TTest          PairedTest `json:"t_test"`           // 配对t检验
+	CorrectedTTest PairedTest `json:"corrected_t_test"` // Nadeau-Bengio修正的配对t检验，考虑了各折训练集的重叠，更保守
+	Wilcoxon       PairedTest `json:"wilcoxon"`         // Wilcoxon符号秩检验，统计量为正差值的秩和
+}
+
+// ModelComparison 多个模型配置在相同划分上交叉验证的比较结果，得分按 [配置][折] 排列，越大越好
+type ModelComparison struct {
+	Scores      [][]float64          `json:"scores"`
+	MeanScores  []float64            `json:"mean_scores"`
+	StdScores   []float64            `json:"std_scores"`
+	BestIndex   int                  `json:"best_index"` // 平均得分最高的配置在configs中的下标
+	Alpha       float64              `json:"alpha"`
+	Comparisons []PairwiseComparison `json:"comparisons"` // 每对配置一项，按(0,1)、(0,2)、…、(1,2)…排列
+}
+
+// CompareModelsCV 在完全相同的各折上交叉验证两个或更多模型配置，并对每对配置的各折得分做配对t检验和
+// Wilcoxon符号秩检验，以判断平均得分的差异在显著性水平alpha下是否显著（alpha为0时为0.05）。
+// 各折训练集相互重叠，普通t检验偏乐观，建议以CorrectedTTest为准；多对比较时未做多重比较校正，
+// 可自行使用Bonferroni等方法调整alpha。划分使用validation的KFolds（不大于0时为10，5折时Wilcoxon检验的
+// p值不会低于0.0625）、RandomSeed和Splitter，分层和评分方式由第一个配置决定，所有配置使用相同的评分方式
+func (c *Client) CompareModelsCV(data *TrainingData, configs []*ModelConfig, validation *ValidationConfig, alpha float64) (*ModelComparison, error) {
+	if len(configs) < 2 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "at least two model configs are required",
+		}
+	}
+	for _, config := range configs {
+		if err := c.validateSearchInput(data, config); err != nil {
+			return nil, err
+		}
+	}
+	if alpha == 0 {
+		alpha = 0.05
+	}
+	if alpha < 0 || alpha >= 1 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("alpha must be in (0, 1), got %v", alpha),
+		}
+	}
+
+	settings := ValidationConfig{}
+	if validation != nil {
+		settings = *validation
+	}
+	if settings.KFolds <= 0 {
+		settings.KFolds = 10
+	}
+	cv, dataset, folds, err := c.crossValidationFolds(data, configs[0], &settings)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrValidationFailed,
+			Message: "failed to split data",
+			Details: err.Error(),
+		}
+	}
+
+	result := &ModelComparison{Alpha: alpha}
+	for k, config := range configs {
+		scores, err := cv.ValidateFolds(dataset, string(config.Algorithm), internalParameters(config.Parameters), folds)
+		if err != nil {
+			return nil, &Error{
+				Code:    ErrValidationFailed,
+				Message: fmt.Sprintf("cross-validation failed for config %d (%s)", k, config.Algorithm),
+				Details: err.Error(),
+			}
+		}
+		mean, std := c.calculateStats(scores)
+		result.Scores = append(result.Scores, scores)
+		result.MeanScores = append(result.MeanScores, mean)
+		result.StdScores = append(result.StdScores, std)
+		if mean > result.MeanScores[result.BestIndex] {
+			result.BestIndex = k
+		}
+	}
+
+	// 验证集与训练集样本数之比的平均值，用于修正t检验
+	var ratio float64
+	for _, f := range folds {
+		ratio += float64(len(f.Test)) / float64(len(f.Train))
+	}
+	ratio /= float64(len(folds))
+
+	for a := 0; a < len(configs); a++ {
+		for b := a + 1; b < len(configs); b++ {
+			comparison, err := compareScores(result.Scores[a], result.Scores[b], ratio, alpha)
+			if err != nil {
+				return nil, &Error{
+					Code:    ErrValidationFailed,
+					Message: fmt.Sprintf("failed to compare config %d with config %d", a, b),
+					Details: err.Error(),
+				}
+			}
+			comparison.ModelA, comparison.ModelB = a, b
+			result.Comparisons = append(result.Comparisons, *comparison)
+		}
+	}
+	return result, nil
+}
+
+// compareScores 对两组配对的各折得分执行配对t检验、修正t检验和Wilcoxon符号秩检验
+func compareScores(a, b []float64, testTrainRatio, alpha float64) (*PairwiseComparison, error) {
+	tTest, err := evaluation.PairedTTest(a, b)
+	if err != nil {
+		return nil, err
+	}
+	corrected, err := evaluation.CorrectedPairedTTest(a, b, testTrainRatio)
+	if err != nil {
+		return nil, err
+	}
+	wilcoxon, err := evaluation.WilcoxonSignedRank(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	test := func(r *evaluation.PairedTestResult) PairedTest {
+		return PairedTest{Statistic: r.Statistic, PValue: r.PValue, Significant: r.PValue < alpha}
+	}
+	return &PairwiseComparison{
+		MeanDifference: tTest.MeanDifference,
+		TTest:          test(tTest),
+		CorrectedTTest: test(corrected),
+		Wilcoxon:       test(wilcoxon),
+	}, nil
+}
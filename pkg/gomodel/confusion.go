@@ -0,0 +1,105 @@
+package gomodel
+
+import (
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+)
+
+// 混淆矩阵的归一化方式
+const (
+	NormalizeTrue = evaluation.NormalizeTrue // 每行除以该真实类别的样本数，对角线为各类别的召回率
+	NormalizePred = evaluation.NormalizePred // 每列除以预测为该类别的样本数，对角线为各类别的精确率
+	NormalizeAll  = evaluation.NormalizeAll  // 除以总样本数
+)
+
+// ConfusionMatrix 混淆矩阵，Counts[i][j]为真实类别Labels[i]被预测为Labels[j]的样本数
+type ConfusionMatrix struct {
+	Labels []float64 `json:"labels"` // 真实值和预测值中出现过的所有类别，按升序排列
+	Counts [][]int   `json:"counts"`
+}
+
+// ClassMetrics 单个类别一对其余时的指标
+type ClassMetrics struct {
+	Label       float64 `json:"label"`
+	Precision   float64 `json:"precision"`
+	Recall      float64 `json:"recall"`
+	F1          float64 `json:"f1"`
+	Specificity float64 `json:"specificity"`
+	Support     int     `json:"support"` // 该类别的真实样本数
+}
+
+// NewConfusionMatrix 由真实类别和预测类别构建混淆矩阵
+func NewConfusionMatrix(yTrue, yPred []float64) (*ConfusionMatrix, error) {
+	m, err := evaluation.NewConfusionMatrix(yTrue, yPred)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to build confusion matrix",
+			Details: err.Error(),
+		}
+	}
+	return &ConfusionMatrix{Labels: m.Labels, Counts: m.Counts}, nil
+}
+
+// Normalized 返回按mode（NormalizeTrue、NormalizePred或NormalizeAll）归一化的混淆矩阵
+func (m *ConfusionMatrix) Normalized(mode string) ([][]float64, error) {
+	normalized, err := m.internal().Normalized(mode)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported normalization: %s", mode),
+		}
+	}
+	return normalized, nil
+}
+
+// PerClass 返回每个类别的精确率、召回率、F1、特异度和样本数，顺序与Labels一致
+func (m *ConfusionMatrix) PerClass() []ClassMetrics {
+	reports := m.internal().PerClass()
+	metrics := make([]ClassMetrics, len(reports))
+	for k, report := range reports {
+		metrics[k] = ClassMetrics(report)
+	}
+	return metrics
+}
+
+// Accuracy 返回预测正确的样本比例
+func (m *ConfusionMatrix) Accuracy() float64 {
+	return m.internal().Accuracy()
+}
+
+// MCC 返回马修斯相关系数，取值在[-1,1]之间，0相当于随机猜测，适合作为类别不平衡数据上的单一评价指标
+func (m *ConfusionMatrix) MCC() float64 {
+	return m.internal().MCC()
+}
+
+// CohenKappa 返回Cohen's kappa系数，即扣除随机一致后的准确率
+func (m *ConfusionMatrix) CohenKappa() float64 {
+	return m.internal().CohenKappa()
+}
+
+// String 以表格形式输出混淆矩阵（行为真实类别，列为预测类别）及每个类别的指标
+func (m *ConfusionMatrix) String() string {
+	return m.internal().String()
+}
+
+// internal 转换为内部混淆矩阵
+func (m *ConfusionMatrix) internal() *evaluation.ConfusionMatrix {
+	return &evaluation.ConfusionMatrix{Labels: m.Labels, Counts: m.Counts}
+}
+
+// ConfusionMatrix 在数据上预测并构建混淆矩阵；预测值（分类模型为正类概率）不小于threshold时记为类别1，否则为0
+func (c *Client) ConfusionMatrix(modelID string, data *TrainingData, threshold float64) (*ConfusionMatrix, error) {
+	yTrue, scores, err := c.targetAndPredictions(modelID, data)
+	if err != nil {
+		return nil, err
+	}
+	classes := make([]float64, len(scores))
+	for i, p := range scores {
+		if p >= threshold {
+			classes[i] = 1
+		}
+	}
+	return NewConfusionMatrix(yTrue, classes)
+}
@@ -0,0 +1,176 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 相关系数的计算方法
+const (
+	CorrelationPearson  = "pearson"  // 线性相关
+	CorrelationSpearman = "spearman" // 秩相关，衡量单调关系，对异常值不敏感
+)
+
+// CorrelationMatrix 特征之间（及与目标变量）的相关系数矩阵
+type CorrelationMatrix struct {
+	Method         string         `json:"method"`
+	Names          []string       `json:"names"`           // 矩阵的行列名称，包含目标变量时目标变量在最后
+	Index          map[string]int `json:"index"`           // 名称到行列号的映射
+	IncludesTarget bool           `json:"includes_target"` // 最后一行/列是否为目标变量
+	Matrix         *mat.SymDense  `json:"-"`
+}
+
+// Get 返回两个特征（或特征与目标变量）之间的相关系数，名称不存在时返回错误
+func (m *CorrelationMatrix) Get(a, b string) (float64, error) {
+	i, okA := m.Index[a]
+	j, okB := m.Index[b]
+	if !okA || !okB {
+		return 0, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unknown column in correlation matrix: %s, %s", a, b),
+		}
+	}
+	return m.Matrix.At(i, j), nil
+}
+
+// Pairs 返回相关系数绝对值不小于threshold的特征对（不含目标变量），按绝对值从大到小排列，
+// 可用于在高度相关的特征中只保留一个
+func (m *CorrelationMatrix) Pairs(threshold float64) []CorrelatedPair {
+	n := len(m.Names)
+	if m.IncludesTarget {
+		n--
+	}
+	var pairs []CorrelatedPair
+	for j := 0; j < n; j++ {
+		for k := j + 1; k < n; k++ {
+			if corr := m.Matrix.At(j, k); math.Abs(corr) >= threshold {
+				pairs = append(pairs, CorrelatedPair{Feature1: m.Names[j], Feature2: m.Names[k], Correlation: corr})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool {
+		return math.Abs(pairs[a].Correlation) > math.Abs(pairs[b].Correlation)
+	})
+	return pairs
+}
+
+// CorrelationMatrix 计算所有特征两两之间的皮尔逊或斯皮尔曼相关系数；includeTarget为true时目标变量作为最后一行/列，
+// 其名称为TargetName（为空时为"target"）。含缺失值(NaN)时每对列只使用两者都不缺失的行，
+// 常数列与其他列的相关系数为0，可用的行少于2行时为NaN
+func (du *DataUtils) CorrelationMatrix(d *TrainingData, method string, includeTarget bool) (*CorrelationMatrix, error) {
+	if d == nil || d.Features == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if method == "" {
+		method = CorrelationPearson
+	}
+	if method != CorrelationPearson && method != CorrelationSpearman {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported correlation method: %s", method),
+		}
+	}
+
+	r, c := d.Features.Dims()
+	names := append([]string(nil), columnNames(d)...)
+	columns := make([][]float64, c, c+1)
+	for j := range columns {
+		columns[j] = mat.Col(nil, j, d.Features)
+	}
+	if includeTarget {
+		if d.Target == nil || d.Target.Len() != r {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: "target must be present and match the number of feature rows",
+			}
+		}
+		name := d.TargetName
+		if name == "" {
+			name = "target"
+		}
+		names = append(names, name)
+		columns = append(columns, mat.Col(nil, 0, d.Target))
+	}
+
+	// 不含缺失值的列只需排名一次
+	complete := make([]bool, len(columns))
+	ranked := make([][]float64, len(columns))
+	for j, column := range columns {
+		complete[j] = len(observedValues(column)) == r
+		if complete[j] && method == CorrelationSpearman {
+			ranked[j] = averageRanks(column)
+		}
+	}
+
+	matrix := mat.NewSymDense(len(columns), nil)
+	for j := range columns {
+		for k := j; k < len(columns); k++ {
+			var corr float64
+			switch {
+			case !complete[j] || !complete[k] || r < 2:
+				corr = completeCorrelation(columns[j], columns[k], method)
+			case method == CorrelationSpearman:
+				corr = pearson(ranked[j], ranked[k])
+			default:
+				corr = pearson(columns[j], columns[k])
+			}
+			if j == k && !math.IsNaN(corr) {
+				corr = 1
+			}
+			matrix.SetSym(j, k, corr)
+		}
+	}
+
+	index := make(map[string]int, len(names))
+	for j, name := range names {
+		index[name] = j
+	}
+	return &CorrelationMatrix{Method: method, Names: names, Index: index, Matrix: matrix, IncludesTarget: includeTarget}, nil
+}
+
+// completeCorrelation 只使用两列都不缺失的行计算相关系数，可用的行少于2行时为NaN
+func completeCorrelation(x, y []float64, method string) float64 {
+	var xs, ys []float64
+	for i := range x {
+		if !math.IsNaN(x[i]) && !math.IsNaN(y[i]) {
+			xs = append(xs, x[i])
+			ys = append(ys, y[i])
+		}
+	}
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	if method == CorrelationSpearman {
+		return pearson(averageRanks(xs), averageRanks(ys))
+	}
+	return pearson(xs, ys)
+}
+
+// averageRanks 返回各值从1开始的秩，相同的值取平均秩
+func averageRanks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+
+	ranks := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && values[order[end]] == values[order[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, i := range order[start:end] {
+			ranks[i] = rank
+		}
+		start = end
+	}
+	return ranks
+}
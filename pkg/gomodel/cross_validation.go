@@ -0,0 +1,236 @@
+package gomodel
+
+import (
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ScoreFunc 由真实值和模型的预测值（分类模型为正类概率）计算得分，得分越大越好
+type ScoreFunc = evaluation.ScoreFunc
+
+// 验证得分的评分方式，可设置在ValidationConfig.Scoring中；得分总是越大越好，误差类指标取负值
+const (
+	ScoringR2               = evaluation.ScoringR2
+	ScoringNegMSE           = evaluation.ScoringNegMSE
+	ScoringNegRMSE          = evaluation.ScoringNegRMSE
+	ScoringNegMAE           = evaluation.ScoringNegMAE
+	ScoringNegMAPE          = evaluation.ScoringNegMAPE
+	ScoringNegMedianAE      = evaluation.ScoringNegMedianAE
+	ScoringNegLogLoss       = evaluation.ScoringNegLogLoss
+	ScoringNegBrierScore    = evaluation.ScoringNegBrierScore
+	ScoringAccuracy         = evaluation.ScoringAccuracy
+	ScoringBalancedAccuracy = evaluation.ScoringBalancedAccuracy
+	ScoringPrecision        = evaluation.ScoringPrecision
+	ScoringRecall           = evaluation.ScoringRecall
+	ScoringF1               = evaluation.ScoringF1
+	ScoringROCAUC           = evaluation.ScoringROCAUC
+	ScoringAveragePrecision = evaluation.ScoringAveragePrecision
+)
+
+// MetricSummary 指标在各折上的取值及其均值和标准差
+type MetricSummary struct {
+	Values []float64 `json:"values"`
+	Mean   float64   `json:"mean"`
+	Std    float64   `json:"std"`
+}
+
+// RepeatedCVResult 重复K折交叉验证结果，各折按重复依次排列
+type RepeatedCVResult struct {
+	NSplits      int                      `json:"n_splits"`
+	NRepeats     int                      `json:"n_repeats"`
+	Scores       []float64                `json:"scores"` // 各折的得分，与kfold验证的得分相同
+	MeanScore    float64                  `json:"mean_score"`
+	StdScore     float64                  `json:"std_score"`
+	RepeatScores []float64                `json:"repeat_scores"` // 每次重复的平均得分，其波动反映划分方式对估计的影响
+	Metrics      map[string]MetricSummary `json:"metrics"`       // 各折验证集上的指标，只包含所有折都能计算的指标
+}
+
+// RepeatedCrossValidate 以randomSeed生成的不同随机打乱重复nRepeats次nSplits折交叉验证，汇总每个指标在全部折上的
+// 均值和标准差。回归模型的指标为r2、mse、rmse、mae等，分类模型为accuracy、balanced_accuracy、f1、roc_auc、logloss和brier_score，
+// 分类模型按类别分层划分，各折并发执行。得分与kfold验证相同，按config.Validation的评分方式计算
+func (c *Client) RepeatedCrossValidate(data *TrainingData, config *ModelConfig, nSplits, nRepeats int, randomSeed int64) (*RepeatedCVResult, error) {
+	if data == nil || config == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "training data and model config cannot be nil",
+		}
+	}
+	if !c.isValidAlgorithm(config.Algorithm) {
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("unsupported algorithm: %s", config.Algorithm),
+		}
+	}
+	if err := c.validateData(data); err != nil {
+		return nil, err
+	}
+
+	splitter := NewRepeatedKFold(nSplits, nRepeats, randomSeed)
+	splitter.Stratify = isClassifier(config)
+	folds, err := splitter.Split(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RepeatedCVResult{
+		NSplits:  nSplits,
+		NRepeats: nRepeats,
+		Scores:   make([]float64, len(folds)),
+	}
+	foldMetrics := make([]map[string]float64, len(folds))
+	err = evaluation.RunFolds(len(folds), 0, func(k int) error {
+		f := folds[k]
+		fit, err := c.bootstrapFit(config, data, f.Train, nil)
+		if err != nil {
+			return fmt.Errorf("repeat %d fold %d: %v", k/nSplits, k%nSplits, err)
+		}
+		test := subsetTrainingData(data, f.Test)
+		result.Scores[k], err = scoreModel(fit.model, test.Features, test.Target, config, config.Validation)
+		if err != nil {
+			return fmt.Errorf("repeat %d fold %d: %v", k/nSplits, k%nSplits, err)
+		}
+		foldMetrics[k] = heldOutMetrics(config, mat.Col(nil, 0, test.Target), mat.Col(nil, 0, fit.model.Predict(test.Features)))
+		return nil
+	})
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrValidationFailed,
+			Message: "cross-validation failed",
+			Details: err.Error(),
+		}
+	}
+
+	result.MeanScore, result.StdScore = c.calculateStats(result.Scores)
+	for r := 0; r < nRepeats; r++ {
+		mean, _ := c.calculateStats(result.Scores[r*nSplits : (r+1)*nSplits])
+		result.RepeatScores = append(result.RepeatScores, mean)
+	}
+
+	result.Metrics = c.summarizeFoldMetrics(foldMetrics)
+	return result, nil
+}
+
+// NestedCVConfig 嵌套交叉验证配置
+type NestedCVConfig struct {
+	Grid          ParamGrid          `json:"-"`      // 内层网格搜索的参数网格
+	Distributions ParamDistributions `json:"-"`      // 设置后内层使用随机搜索，忽略Grid
+	NIter         int                `json:"n_iter"` // 随机搜索的采样次数，不大于0时为10
+	Inner         *ValidationConfig  `json:"inner"`  // 内层搜索的验证配置，为nil时使用客户端的默认验证配置
+	Outer         *ValidationConfig  `json:"outer"`  // 外层评估的划分，使用KFolds（不大于0时为5）、RandomSeed和Splitter
+}
+
+// NestedCVFold 外层一折的结果
+type NestedCVFold struct {
+	BestParameters map[string]interface{} `json:"best_parameters"` // 内层搜索在该折训练集上选出的参数
+	InnerScore     float64                `json:"inner_score"`     // 内层搜索的最佳平均得分，因参与了选择而偏乐观
+	Score          float64                `json:"score"`           // 用选出的参数在该折训练集上拟合后在验证集上的得分
+}
+
+// NestedCVResult 嵌套交叉验证结果
+type NestedCVResult struct {
+	Scores    []float64                `json:"scores"` // 外层各折的得分
+	MeanScore float64                  `json:"mean_score"`
+	StdScore  float64                  `json:"std_score"`
+	Folds     []NestedCVFold           `json:"folds"`
+	Metrics   map[string]MetricSummary `json:"metrics"` // 外层各折验证集上的指标
+}
+
+// NestedCrossValidate 执行嵌套交叉验证：外层每折只在其训练集上做超参数搜索（内层交叉验证），
+// 再用选出的参数在该训练集上拟合并在外层验证集上评分。外层验证集从未参与参数选择，
+// 其平均得分是"调参+训练"整个流程泛化能力的无偏估计；各折选出的参数不一致说明参数选择不稳定。
+// 外层分类模型按类别分层，外层得分按Outer的评分方式计算
+func (c *Client) NestedCrossValidate(data *TrainingData, config *ModelConfig, nested *NestedCVConfig) (*NestedCVResult, error) {
+	if err := c.validateSearchInput(data, config); err != nil {
+		return nil, err
+	}
+	if nested == nil || (len(nested.Grid) == 0 && len(nested.Distributions) == 0) {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "nested cross-validation requires a parameter grid or distributions",
+		}
+	}
+	nIter := nested.NIter
+	if nIter <= 0 {
+		nIter = 10
+	}
+
+	_, _, folds, err := c.crossValidationFolds(data, config, curveValidation(nested.Outer))
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrValidationFailed,
+			Message: "failed to split data",
+			Details: err.Error(),
+		}
+	}
+
+	result := &NestedCVResult{
+		Scores: make([]float64, len(folds)),
+		Folds:  make([]NestedCVFold, len(folds)),
+	}
+	foldMetrics := make([]map[string]float64, len(folds))
+	for k, f := range folds {
+		train := subsetTrainingData(data, f.Train)
+		var tuning *TuningResult
+		if len(nested.Distributions) > 0 {
+			tuning, err = c.RandomizedSearch(train, config, nested.Distributions, nIter, nested.Inner)
+		} else {
+			tuning, err = c.GridSearch(train, config, nested.Grid, nested.Inner)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fit, err := c.bootstrapFit(tuning.BestConfig, data, f.Train, nil)
+		if err != nil {
+			return nil, &Error{
+				Code:    ErrValidationFailed,
+				Message: "nested cross-validation failed",
+				Details: fmt.Sprintf("outer fold %d: %v", k, err),
+			}
+		}
+		test := subsetTrainingData(data, f.Test)
+		score, err := scoreModel(fit.model, test.Features, test.Target, tuning.BestConfig, nested.Outer)
+		if err != nil {
+			return nil, &Error{
+				Code:    ErrValidationFailed,
+				Message: "nested cross-validation failed",
+				Details: fmt.Sprintf("outer fold %d: %v", k, err),
+			}
+		}
+		result.Scores[k] = score
+		result.Folds[k] = NestedCVFold{
+			BestParameters: tuning.BestParameters,
+			InnerScore:     tuning.BestScore,
+			Score:          score,
+		}
+		foldMetrics[k] = heldOutMetrics(tuning.BestConfig, mat.Col(nil, 0, test.Target), mat.Col(nil, 0, fit.model.Predict(test.Features)))
+	}
+
+	result.MeanScore, result.StdScore = c.calculateStats(result.Scores)
+	result.Metrics = c.summarizeFoldMetrics(foldMetrics)
+	return result, nil
+}
+
+// summarizeFoldMetrics 汇总各折的指标，只保留所有折都能计算的指标
+func (c *Client) summarizeFoldMetrics(foldMetrics []map[string]float64) map[string]MetricSummary {
+	summaries := make(map[string]MetricSummary)
+	if len(foldMetrics) == 0 {
+		return summaries
+	}
+	for name := range foldMetrics[0] {
+		values := make([]float64, 0, len(foldMetrics))
+		for _, metrics := range foldMetrics {
+			if v, ok := metrics[name]; ok {
+				values = append(values, v)
+			}
+		}
+		if len(values) != len(foldMetrics) {
+			continue
+		}
+		mean, std := c.calculateStats(values)
+		summaries[name] = MetricSummary{Values: values, Mean: mean, Std: std}
+	}
+	return summaries
+}
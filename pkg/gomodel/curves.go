@@ -0,0 +1,158 @@
+package gomodel
+
+import (
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ROCCurve ROC曲线，第i个点为得分不小于Thresholds[i]时判为正类的假正率和真正率。
+// 第一个点的阈值为+Inf（全部判为负类），曲线从(0,0)开始、到(1,1)结束
+type ROCCurve struct {
+	FPR        []float64
+	TPR        []float64
+	Thresholds []float64
+	AUC        float64 // 曲线下面积，等于随机抽取的正类样本得分高于负类样本的概率
+}
+
+// NewROCCurve 根据二分类标签（0或1，正类为1）和正类得分（如Logistic预测的概率）计算ROC曲线和AUC，
+// 要求两个类别都有样本
+func NewROCCurve(yTrue, scores []float64) (*ROCCurve, error) {
+	roc, err := evaluation.ROCCurve(yTrue, scores)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to compute ROC curve",
+			Details: err.Error(),
+		}
+	}
+	return &ROCCurve{FPR: roc.FPR, TPR: roc.TPR, Thresholds: roc.Thresholds, AUC: roc.AUC}, nil
+}
+
+// YoudenThreshold 返回使约登指数TPR-FPR最大的阈值及该点的TPR和FPR，可作为ConfusionMatrix等的分类阈值
+func (r *ROCCurve) YoudenThreshold() (threshold, tpr, fpr float64) {
+	roc := &evaluation.ROC{FPR: r.FPR, TPR: r.TPR, Thresholds: r.Thresholds, AUC: r.AUC}
+	return roc.YoudenThreshold()
+}
+
+// ROCCurve 在数据上预测并计算ROC曲线，模型的预测值作为正类得分
+func (c *Client) ROCCurve(modelID string, data *TrainingData) (*ROCCurve, error) {
+	yTrue, scores, err := c.targetAndPredictions(modelID, data)
+	if err != nil {
+		return nil, err
+	}
+	return NewROCCurve(yTrue, scores)
+}
+
+// PrecisionRecallCurve 精确率-召回率曲线，第i个点为得分不小于Thresholds[i]时判为正类的精确率和召回率。
+// 第一个点的阈值为+Inf，召回率为0、精确率记为1；之后阈值递减，召回率单调不减
+type PrecisionRecallCurve struct {
+	Precision        []float64
+	Recall           []float64
+	Thresholds       []float64
+	AveragePrecision float64 // 平均精确率(AP)，随机得分的AP约等于正类比例
+}
+
+// NewPrecisionRecallCurve 根据二分类标签（0或1，正类为1）和正类得分计算精确率-召回率曲线和平均精确率，
+// 要求两个类别都有样本。正类稀少时比ROC曲线更能反映模型的表现
+func NewPrecisionRecallCurve(yTrue, scores []float64) (*PrecisionRecallCurve, error) {
+	pr, err := evaluation.PRCurve(yTrue, scores)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to compute precision-recall curve",
+			Details: err.Error(),
+		}
+	}
+	return &PrecisionRecallCurve{Precision: pr.Precision, Recall: pr.Recall, Thresholds: pr.Thresholds, AveragePrecision: pr.AveragePrecision}, nil
+}
+
+// BestF1Threshold 返回使F1最大的阈值及该点的精确率和召回率
+func (p *PrecisionRecallCurve) BestF1Threshold() (threshold, precision, recall float64) {
+	pr := &evaluation.PR{Precision: p.Precision, Recall: p.Recall, Thresholds: p.Thresholds, AveragePrecision: p.AveragePrecision}
+	return pr.BestF1Threshold()
+}
+
+// PrecisionRecallCurve 在数据上预测并计算精确率-召回率曲线，模型的预测值作为正类得分
+func (c *Client) PrecisionRecallCurve(modelID string, data *TrainingData) (*PrecisionRecallCurve, error) {
+	yTrue, scores, err := c.targetAndPredictions(modelID, data)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrecisionRecallCurve(yTrue, scores)
+}
+
+// 校准曲线的分箱方式
+const (
+	CalibrationUniform  = evaluation.CalibrationUniform  // 将[0, 1]等分为等宽的箱
+	CalibrationQuantile = evaluation.CalibrationQuantile // 按预测概率的分位数分箱，各箱样本数大致相同
+)
+
+// CalibrationCurve 校准曲线（可靠性图），第i个点为第i个非空箱内预测概率的均值和正类样本的实际比例。
+// 完全校准的模型各点落在对角线上；点在对角线下方说明预测概率偏高，上方说明偏低
+type CalibrationCurve struct {
+	MeanPredicted            []float64
+	FractionPositive         []float64
+	Counts                   []int     // 箱内样本数，样本很少的箱波动较大
+	BinEdges                 []float64 // 箱的边界，空箱不产生点
+	ExpectedCalibrationError float64   // 各箱|实际比例-平均预测概率|按样本数加权的均值(ECE)
+	MaxCalibrationError      float64   // 各箱|实际比例-平均预测概率|的最大值(MCE)
+	BrierScore               float64   // 预测概率与标签的均方误差，越小越好
+}
+
+// NewCalibrationCurve 根据二分类标签（0或1，正类为1）和预测的正类概率计算校准曲线和Brier分数。
+// nBins不大于0时为10，strategy为CalibrationUniform（为空时的默认值）或CalibrationQuantile
+func NewCalibrationCurve(yTrue, probabilities []float64, nBins int, strategy string) (*CalibrationCurve, error) {
+	if nBins <= 0 {
+		nBins = 10
+	}
+	curve, err := evaluation.CalibrationCurve(yTrue, probabilities, nBins, strategy)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to compute calibration curve",
+			Details: err.Error(),
+		}
+	}
+	brier, err := evaluation.BrierScore(yTrue, probabilities, nil)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to compute Brier score",
+			Details: err.Error(),
+		}
+	}
+	return &CalibrationCurve{
+		MeanPredicted:            curve.MeanPredicted,
+		FractionPositive:         curve.FractionPositive,
+		Counts:                   curve.Counts,
+		BinEdges:                 curve.BinEdges,
+		ExpectedCalibrationError: curve.ExpectedCalibrationError,
+		MaxCalibrationError:      curve.MaxCalibrationError,
+		BrierScore:               brier,
+	}, nil
+}
+
+// CalibrationCurve 在数据上预测并计算校准曲线，用于检验分类模型的预测概率是否可信，
+// 以及比较Calibrated校准前后的效果；应在未参与训练的数据上计算
+func (c *Client) CalibrationCurve(modelID string, data *TrainingData, nBins int, strategy string) (*CalibrationCurve, error) {
+	yTrue, probabilities, err := c.targetAndPredictions(modelID, data)
+	if err != nil {
+		return nil, err
+	}
+	return NewCalibrationCurve(yTrue, probabilities, nBins, strategy)
+}
+
+// targetAndPredictions 返回数据的真实值和模型在数据上的预测值（分类模型为正类概率）
+func (c *Client) targetAndPredictions(modelID string, data *TrainingData) ([]float64, []float64, error) {
+	if data == nil || data.Features == nil || data.Target == nil {
+		return nil, nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	prediction, err := c.Predict(modelID, data.Features)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mat.Col(nil, 0, data.Target), prediction.Predictions, nil
+}
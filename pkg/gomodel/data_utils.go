@@ -1,9 +1,12 @@
 package gomodel
 
 import (
+	"database/sql"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
+	"net/http"
 	"time"
 
 	"github.com/feiyuluoye/Go-Model/internal/data"
@@ -11,12 +14,14 @@ import (
 	"gonum.org/v1/gonum/mat"
 )
 
-// DataUtils 提供数据处理相关的实用工具
+// DataUtils 提供数据处理相关的实用工具。
+// 需要随机数的方法每次调用都从随机种子创建独立的随机数生成器，不使用全局随机源：
+// 相同种子的结果总是可复现，同一实例也可以被多个goroutine并发使用
 type DataUtils struct {
 	randomSeed int64
 }
 
-// NewDataUtils 创建数据工具实例
+// NewDataUtils 创建数据工具实例，randomSeed为0时使用当前时间作为种子
 func NewDataUtils(randomSeed int64) *DataUtils {
 	if randomSeed == 0 {
 		randomSeed = time.Now().UnixNano()
@@ -24,6 +29,16 @@ func NewDataUtils(randomSeed int64) *DataUtils {
 	return &DataUtils{randomSeed: randomSeed}
 }
 
+// RandomSeed 返回实例使用的随机种子，种子由时间生成时可据此复现结果
+func (du *DataUtils) RandomSeed() int64 {
+	return du.randomSeed
+}
+
+// newRand 创建以实例种子初始化的随机数生成器，只在单次调用内使用
+func (du *DataUtils) newRand() *rand.Rand {
+	return rand.New(rand.NewSource(du.randomSeed))
+}
+
 // LoadFromCSV 从CSV文件加载数据
 func (du *DataUtils) LoadFromCSV(filePath string, targetColumn interface{}, hasHeader bool) (*TrainingData, error) {
 	dataset, err := data.LoadCSV(filePath, hasHeader, targetColumn)
@@ -38,9 +53,39 @@ func (du *DataUtils) LoadFromCSV(filePath string, targetColumn interface{}, hasH
 	return du.convertToTrainingData(dataset), nil
 }
 
+// LoadFromNDJSON 从JSON Lines(NDJSON)文件逐行加载数据，字段名支持点号分隔的嵌套路径
+func (du *DataUtils) LoadFromNDJSON(filePath string, featureColumns []string, targetColumn string) (*TrainingData, error) {
+	dataset, err := data.LoadNDJSON(filePath, featureColumns, targetColumn)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load NDJSON data",
+			Details: err.Error(),
+		}
+	}
+
+	trainingData := du.convertToTrainingData(dataset)
+	trainingData.TargetName = targetColumn
+	return trainingData, nil
+}
+
+// LoadFromXLSX 从Excel(.xlsx)文件加载数据，sheet为空时使用第一个工作表
+func (du *DataUtils) LoadFromXLSX(filePath string, sheet string, targetColumn interface{}, hasHeader bool) (*TrainingData, error) {
+	dataset, err := data.LoadXLSX(filePath, sheet, hasHeader, targetColumn)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load XLSX data",
+			Details: err.Error(),
+		}
+	}
+
+	return du.convertToTrainingData(dataset), nil
+}
+
 // LoadFromJSON 从JSON文件加载数据
-func (du *DataUtils) LoadFromJSON(filePath string) (*TrainingData, error) {
-	dataset, err := data.LoadJSON(filePath)
+func (du *DataUtils) LoadFromJSON(filePath string, featureColumns []string, targetColumn string) (*TrainingData, error) {
+	dataset, err := data.LoadJSON(filePath, featureColumns, targetColumn)
 	if err != nil {
 		return nil, &Error{
 			Code:    ErrInvalidData,
@@ -52,6 +97,233 @@ func (du *DataUtils) LoadFromJSON(filePath string) (*TrainingData, error) {
 	return du.convertToTrainingData(dataset), nil
 }
 
+// LoadFromSQL 执行SQL查询并加载结果集，除目标列外的所有结果列都作为特征，args为查询参数
+func (du *DataUtils) LoadFromSQL(db *sql.DB, query string, targetColumn string, args ...interface{}) (*TrainingData, error) {
+	dataset, err := data.LoadSQL(db, query, targetColumn, args...)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load SQL data",
+			Details: err.Error(),
+		}
+	}
+
+	trainingData := du.convertToTrainingData(dataset)
+	trainingData.TargetName = targetColumn
+	return trainingData, nil
+}
+
+// LoadFromParquet 从Parquet文件加载数据，featureColumns为空时使用除目标列外的所有列
+func (du *DataUtils) LoadFromParquet(filePath string, featureColumns []string, targetColumn string) (*TrainingData, error) {
+	dataset, err := data.LoadParquet(filePath, featureColumns, targetColumn)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load Parquet data",
+			Details: err.Error(),
+		}
+	}
+
+	trainingData := du.convertToTrainingData(dataset)
+	trainingData.TargetName = targetColumn
+	return trainingData, nil
+}
+
+// SaveToParquet 将训练数据保存为Parquet文件，目标列使用TargetName命名（为空时为"target"）
+func (du *DataUtils) SaveToParquet(d *TrainingData, filePath string) error {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+
+	if err := data.SaveParquet(toDataset(d), filePath, d.TargetName); err != nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to save Parquet data",
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// SaveToCSV 将训练数据保存为带表头的CSV文件，特征列在前，目标列在最后并使用TargetName命名（为空时为"target"）；
+// 缺失值写为空字符串，可用LoadFromCSVWithMissing读回。样本权重不会被保存
+func (du *DataUtils) SaveToCSV(d *TrainingData, filePath string) error {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+
+	if err := data.SaveCSV(toDataset(d), filePath, d.TargetName); err != nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to save CSV data",
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// SaveToJSON 将训练数据保存为JSON对象数组，每个样本一个对象，键为特征名和目标列名（TargetName为空时为"target"），
+// 可用LoadFromJSON读回；NaN和无穷大写为null。样本权重不会被保存
+func (du *DataUtils) SaveToJSON(d *TrainingData, filePath string) error {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+
+	if err := data.SaveJSON(toDataset(d), filePath, d.TargetName); err != nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to save JSON data",
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// LoadFromCSVWithMissing 从CSV文件加载数据，空白或非数值的特征值保留为NaN，以便用Imputer填充
+func (du *DataUtils) LoadFromCSVWithMissing(filePath string, targetColumn interface{}, hasHeader bool) (*TrainingData, error) {
+	dataset, err := data.LoadCSVWithMissing(filePath, hasHeader, targetColumn)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load CSV data",
+			Details: err.Error(),
+		}
+	}
+
+	return du.convertToTrainingData(dataset), nil
+}
+
+// LoadFromCSVCategorical 加载包含字符串类别特征的CSV文件，类别特征按标签编码为类别码并记录在Categories中。
+// columns为空时自动识别含有非数值内容的列；加载预测数据时传入训练数据的Categories，
+// 已有类别沿用训练时的编码。空白值和无效的数值特征保留为NaN；
+// 目标列为字符串时同样编码，其类别表以TargetName为键
+func (du *DataUtils) LoadFromCSVCategorical(filePath string, targetColumn interface{}, hasHeader bool, columns []string, categories map[string][]string) (*TrainingData, error) {
+	dataset, labels, err := data.LoadCSVCategorical(filePath, hasHeader, targetColumn, columns, categories)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load CSV data",
+			Details: err.Error(),
+		}
+	}
+
+	trainingData := du.convertToTrainingData(dataset)
+	if len(labels) > 0 {
+		trainingData.Categories = labels
+	}
+	return trainingData, nil
+}
+
+// LoadFromCSVReader 从reader加载CSV数据，适用于嵌入的测试数据或已打开的网络响应
+func (du *DataUtils) LoadFromCSVReader(r io.Reader, targetColumn interface{}, hasHeader bool) (*TrainingData, error) {
+	dataset, err := data.LoadCSVFromReader(r, hasHeader, targetColumn)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load CSV data",
+			Details: err.Error(),
+		}
+	}
+
+	return du.convertToTrainingData(dataset), nil
+}
+
+// LoadFromCSVURL 通过HTTP(S)下载并加载CSV数据（如S3预签名URL），压缩的响应体会自动解压。
+// 请求超时为5分钟，需要其他超时或认证时使用LoadFromCSVURLWithClient
+func (du *DataUtils) LoadFromCSVURL(url string, targetColumn interface{}, hasHeader bool) (*TrainingData, error) {
+	return du.LoadFromCSVURLWithClient(nil, url, targetColumn, hasHeader)
+}
+
+// LoadFromCSVURLWithClient 使用指定的HTTP客户端下载并加载CSV数据，client为nil时与LoadFromCSVURL相同
+func (du *DataUtils) LoadFromCSVURLWithClient(client *http.Client, url string, targetColumn interface{}, hasHeader bool) (*TrainingData, error) {
+	dataset, err := data.LoadCSVFromURLWithClient(client, url, targetColumn, hasHeader)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load CSV data from URL",
+			Details: err.Error(),
+		}
+	}
+
+	return du.convertToTrainingData(dataset), nil
+}
+
+// LoadFromCSVStreaming 分批解析CSV文件并构建训练数据，不在内存中保留原始字符串记录，
+// 适用于ReadAll会耗尽内存的大文件；chunkSize不大于0时使用默认批大小
+func (du *DataUtils) LoadFromCSVStreaming(filePath string, targetColumn interface{}, hasHeader bool, chunkSize int) (*TrainingData, error) {
+	features, target, featureNames, err := data.LoadCSVStreamingFlat(filePath, hasHeader, targetColumn, chunkSize)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load CSV data",
+			Details: err.Error(),
+		}
+	}
+
+	// 直接包装连续的特征数组，避免再复制一份
+	return &TrainingData{
+		Features:     mat.NewDense(len(target), len(featureNames), features),
+		Target:       mat.NewVecDense(len(target), target),
+		FeatureNames: featureNames,
+	}, nil
+}
+
+// CSVBatchReader 按批读取CSV文件的迭代器
+type CSVBatchReader struct {
+	stream *data.CSVStream
+	du     *DataUtils
+}
+
+// StreamCSV 打开CSV文件并返回按批读取的迭代器，每批最多chunkSize行，使用完毕后需要调用Close
+func (du *DataUtils) StreamCSV(filePath string, targetColumn interface{}, hasHeader bool, chunkSize int) (*CSVBatchReader, error) {
+	stream, err := data.OpenCSVStream(filePath, hasHeader, targetColumn, chunkSize)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to open CSV stream",
+			Details: err.Error(),
+		}
+	}
+
+	return &CSVBatchReader{stream: stream, du: du}, nil
+}
+
+// Next 返回下一批训练数据，没有更多数据时返回io.EOF
+func (r *CSVBatchReader) Next() (*TrainingData, error) {
+	dataset, err := r.stream.Next()
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to read CSV batch",
+			Details: err.Error(),
+		}
+	}
+
+	return r.du.convertToTrainingData(dataset), nil
+}
+
+// FeatureNames 返回特征名（不含目标列）
+func (r *CSVBatchReader) FeatureNames() []string {
+	return r.stream.FeatureNames()
+}
+
+// Close 关闭底层文件
+func (r *CSVBatchReader) Close() error {
+	return r.stream.Close()
+}
+
 // CreateFromArrays 从数组创建训练数据
 func (du *DataUtils) CreateFromArrays(features [][]float64, target []float64, featureNames []string, targetName string) (*TrainingData, error) {
 	if len(features) == 0 || len(target) == 0 {
@@ -87,72 +359,57 @@ func (du *DataUtils) CreateFromArrays(features [][]float64, target []float64, fe
 	}, nil
 }
 
-// SplitTrainTest 分割训练和测试数据
-func (du *DataUtils) SplitTrainTest(data *TrainingData, testSize float64, shuffle bool) (*TrainingData, *TrainingData, error) {
-	if testSize <= 0 || testSize >= 1 {
+// SplitOptions 训练集/测试集的划分方式
+type SplitOptions struct {
+	TestSize float64 // 测试集比例 (0-1)
+	Shuffle  bool    // 划分前随机打乱样本，随机数由NewDataUtils的种子决定
+	Stratify bool    // 按目标类别分层划分（用于分类），总是打乱样本
+}
+
+// SplitTrainTest 分割训练和测试数据，stratify为true时按目标类别分层，
+// 等价于Split(data, SplitOptions{TestSize: testSize, Shuffle: shuffle, Stratify: stratify})
+func (du *DataUtils) SplitTrainTest(data *TrainingData, testSize float64, shuffle, stratify bool) (*TrainingData, *TrainingData, error) {
+	return du.Split(data, SplitOptions{TestSize: testSize, Shuffle: shuffle, Stratify: stratify})
+}
+
+// Split 按options将数据划分为训练集和测试集，样本权重随样本一起划分。
+// Stratify为true时两部分中各类别的比例与原数据一致，且每个类别在两部分中都至少有1个样本，
+// 适用于类别不平衡的分类目标，每个类别至少需要2个样本
+func (du *DataUtils) Split(d *TrainingData, options SplitOptions) (*TrainingData, *TrainingData, error) {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return nil, nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	if options.TestSize <= 0 || options.TestSize >= 1 {
 		return nil, nil, &Error{
 			Code:    ErrInvalidParameters,
 			Message: "test size must be between 0 and 1",
 		}
 	}
 
-	r, c := data.Features.Dims()
-	testCount := int(float64(r) * testSize)
-	trainCount := r - testCount
-
-	// 创建索引
-	indices := make([]int, r)
-	for i := range indices {
-		indices[i] = i
-	}
-
-	// 如果需要打乱
-	if shuffle {
-		rand.Seed(du.randomSeed)
-		rand.Shuffle(len(indices), func(i, j int) {
-			indices[i], indices[j] = indices[j], indices[i]
-		})
+	var rng *rand.Rand
+	if options.Shuffle || options.Stratify {
+		// 使用独立的随机源，并发划分时结果仍可复现
+		rng = du.newRand()
 	}
-
-	// 分割索引
-	trainIndices := indices[:trainCount]
-	testIndices := indices[trainCount:]
-
-	// 创建训练数据
-	trainFeatures := mat.NewDense(trainCount, c, nil)
-	trainTarget := mat.NewVecDense(trainCount, nil)
-	for i, idx := range trainIndices {
-		for j := 0; j < c; j++ {
-			trainFeatures.Set(i, j, data.Features.At(idx, j))
+	trainIndices, testIndices, err := data.SplitIndices(mat.Col(nil, 0, d.Target), options.TestSize, rng, options.Stratify)
+	if err != nil {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to split data",
+			Details: err.Error(),
 		}
-		trainTarget.SetVec(i, data.Target.AtVec(idx))
 	}
-
-	// 创建测试数据
-	testFeatures := mat.NewDense(testCount, c, nil)
-	testTarget := mat.NewVecDense(testCount, nil)
-	for i, idx := range testIndices {
-		for j := 0; j < c; j++ {
-			testFeatures.Set(i, j, data.Features.At(idx, j))
+	if len(trainIndices) == 0 || len(testIndices) == 0 {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("test size %v leaves an empty training or test set for %d samples", options.TestSize, len(trainIndices)+len(testIndices)),
 		}
-		testTarget.SetVec(i, data.Target.AtVec(idx))
 	}
 
-	trainData := &TrainingData{
-		Features:     trainFeatures,
-		Target:       trainTarget,
-		FeatureNames: data.FeatureNames,
-		TargetName:   data.TargetName,
-	}
-
-	testData := &TrainingData{
-		Features:     testFeatures,
-		Target:       testTarget,
-		FeatureNames: data.FeatureNames,
-		TargetName:   data.TargetName,
-	}
-
-	return trainData, testData, nil
+	return subsetTrainingData(d, trainIndices), subsetTrainingData(d, testIndices), nil
 }
 
 // Normalize 标准化特征数据 (z-score normalization)
@@ -176,6 +433,7 @@ func (du *DataUtils) Normalize(data *TrainingData) (*TrainingData, error) {
 	return &TrainingData{
 		Features:     normalizedFeatures,
 		Target:       data.Target,
+		Weights:      data.Weights,
 		FeatureNames: data.FeatureNames,
 		TargetName:   data.TargetName,
 	}, nil
@@ -202,18 +460,21 @@ func (du *DataUtils) Scale(data *TrainingData) (*TrainingData, error) {
 	return &TrainingData{
 		Features:     scaledFeatures,
 		Target:       data.Target,
+		Weights:      data.Weights,
 		FeatureNames: data.FeatureNames,
 		TargetName:   data.TargetName,
 	}, nil
 }
 
-// RemoveOutliers 移除异常值
+// RemoveOutliers 移除任一特征为异常值的行
+// iqr: threshold为四分位距的倍数（<=0时为1.5），超出[Q1-k*IQR, Q3+k*IQR]的值为异常值；
+// zscore: threshold为标准差的倍数（<=0时为3），|x-均值|>k*标准差的值为异常值。
+// 需要只检查部分列、检查目标变量、调整整行判定策略或查看被移除的行时使用RemoveOutliersWithReport
 func (du *DataUtils) RemoveOutliers(data *TrainingData, method string, threshold float64) (*TrainingData, error) {
 	switch method {
-	case "iqr":
-		return du.removeOutliersIQR(data, threshold)
-	case "zscore":
-		return du.removeOutliersZScore(data, threshold)
+	case OutlierIQR, OutlierZScore:
+		cleaned, _, err := du.RemoveOutliersWithReport(data, &OutlierConfig{Method: method, Threshold: threshold})
+		return cleaned, err
 	default:
 		return nil, &Error{
 			Code:    ErrInvalidParameters,
@@ -222,17 +483,20 @@ func (du *DataUtils) RemoveOutliers(data *TrainingData, method string, threshold
 	}
 }
 
-// GenerateSyntheticData 生成合成数据用于测试
+// GenerateSyntheticData 生成合成数据用于测试，dataType为Synthetic*常量之一；需要真实生成参数时使用GenerateSynthetic
 func (du *DataUtils) GenerateSyntheticData(samples int, features int, noiseLevel float64, dataType string) (*TrainingData, error) {
-	rand.Seed(du.randomSeed)
-	
+	rng := du.newRand()
+
 	switch dataType {
 	case "linear":
-		return du.generateLinearData(samples, features, noiseLevel)
+		return du.generateLinearData(samples, features, noiseLevel, rng)
 	case "polynomial":
-		return du.generatePolynomialData(samples, features, noiseLevel)
+		return du.generatePolynomialData(samples, features, noiseLevel, rng)
 	case "classification":
-		return du.generateClassificationData(samples, features, noiseLevel)
+		return du.generateClassificationData(samples, features, noiseLevel, rng)
+	case SyntheticFriedman1, SyntheticSparse, SyntheticBlobs, SyntheticMoons, SyntheticImbalanced:
+		d, _, err := du.GenerateSynthetic(&SyntheticConfig{Type: dataType, Samples: samples, Features: features, Noise: noiseLevel})
+		return d, err
 	default:
 		return nil, &Error{
 			Code:    ErrInvalidParameters,
@@ -241,51 +505,6 @@ func (du *DataUtils) GenerateSyntheticData(samples int, features int, noiseLevel
 	}
 }
 
-// GetDataSummary 获取数据摘要统计信息
-func (du *DataUtils) GetDataSummary(data *TrainingData) map[string]interface{} {
-	r, c := data.Features.Dims()
-	
-	summary := map[string]interface{}{
-		"samples":  r,
-		"features": c,
-		"feature_stats": make(map[string]map[string]float64),
-		"target_stats":  make(map[string]float64),
-	}
-
-	// 特征统计
-	featureStats := make(map[string]map[string]float64)
-	for j := 0; j < c; j++ {
-		featureName := fmt.Sprintf("feature_%d", j)
-		if j < len(data.FeatureNames) && data.FeatureNames[j] != "" {
-			featureName = data.FeatureNames[j]
-		}
-		
-		mean, std := du.calculateColumnStats(data.Features, j)
-		min, max := du.calculateColumnMinMax(data.Features, j)
-		
-		featureStats[featureName] = map[string]float64{
-			"mean": mean,
-			"std":  std,
-			"min":  min,
-			"max":  max,
-		}
-	}
-	summary["feature_stats"] = featureStats
-
-	// 目标变量统计
-	targetMean, targetStd := du.calculateVectorStats(data.Target)
-	targetMin, targetMax := du.calculateVectorMinMax(data.Target)
-	
-	summary["target_stats"] = map[string]float64{
-		"mean": targetMean,
-		"std":  targetStd,
-		"min":  targetMin,
-		"max":  targetMax,
-	}
-
-	return summary
-}
-
 // 辅助方法
 
 func (du *DataUtils) convertToTrainingData(dataset *types.Dataset) *TrainingData {
@@ -304,8 +523,10 @@ func (du *DataUtils) convertToTrainingData(dataset *types.Dataset) *TrainingData
 	targetVector := mat.NewVecDense(len(dataset.Target), dataset.Target)
 
 	return &TrainingData{
-		Features: featureMatrix,
-		Target:   targetVector,
+		Features:     featureMatrix,
+		Target:       targetVector,
+		FeatureNames: dataset.FeatureNames,
+		TargetName:   dataset.TargetName,
 	}
 }
 
@@ -349,59 +570,7 @@ func (du *DataUtils) calculateColumnMinMax(matrix *mat.Dense, col int) (min, max
 	return min, max
 }
 
-func (du *DataUtils) calculateVectorStats(vector *mat.VecDense) (mean, std float64) {
-	n := vector.Len()
-	
-	// 计算均值
-	sum := 0.0
-	for i := 0; i < n; i++ {
-		sum += vector.AtVec(i)
-	}
-	mean = sum / float64(n)
-
-	// 计算标准差
-	sumSquares := 0.0
-	for i := 0; i < n; i++ {
-		diff := vector.AtVec(i) - mean
-		sumSquares += diff * diff
-	}
-	std = math.Sqrt(sumSquares / float64(n))
-
-	return mean, std
-}
-
-func (du *DataUtils) calculateVectorMinMax(vector *mat.VecDense) (min, max float64) {
-	n := vector.Len()
-	
-	min = vector.AtVec(0)
-	max = vector.AtVec(0)
-	
-	for i := 1; i < n; i++ {
-		val := vector.AtVec(i)
-		if val < min {
-			min = val
-		}
-		if val > max {
-			max = val
-		}
-	}
-
-	return min, max
-}
-
-func (du *DataUtils) removeOutliersIQR(data *TrainingData, multiplier float64) (*TrainingData, error) {
-	// IQR方法移除异常值的实现
-	// 这里简化实现，实际应该计算四分位数
-	return data, nil
-}
-
-func (du *DataUtils) removeOutliersZScore(data *TrainingData, threshold float64) (*TrainingData, error) {
-	// Z-score方法移除异常值的实现
-	// 这里简化实现，实际应该计算z-score并过滤
-	return data, nil
-}
-
-func (du *DataUtils) generateLinearData(samples, features int, noiseLevel float64) (*TrainingData, error) {
+func (du *DataUtils) generateLinearData(samples, features int, noiseLevel float64, rng *rand.Rand) (*TrainingData, error) {
 	// 生成线性关系的合成数据
 	X := make([][]float64, samples)
 	y := make([]float64, samples)
@@ -409,7 +578,7 @@ func (du *DataUtils) generateLinearData(samples, features int, noiseLevel float6
 	// 生成随机系数
 	coefficients := make([]float64, features)
 	for i := range coefficients {
-		coefficients[i] = rand.Float64()*4 - 2 // [-2, 2]
+		coefficients[i] = rng.Float64()*4 - 2 // [-2, 2]
 	}
 	
 	for i := 0; i < samples; i++ {
@@ -417,19 +586,19 @@ func (du *DataUtils) generateLinearData(samples, features int, noiseLevel float6
 		target := 0.0
 		
 		for j := 0; j < features; j++ {
-			X[i][j] = rand.Float64()*10 - 5 // [-5, 5]
+			X[i][j] = rng.Float64()*10 - 5 // [-5, 5]
 			target += coefficients[j] * X[i][j]
 		}
 		
 		// 添加噪声
-		noise := rand.NormFloat64() * noiseLevel
+		noise := rng.NormFloat64() * noiseLevel
 		y[i] = target + noise
 	}
 	
 	return du.CreateFromArrays(X, y, nil, "target")
 }
 
-func (du *DataUtils) generatePolynomialData(samples, features int, noiseLevel float64) (*TrainingData, error) {
+func (du *DataUtils) generatePolynomialData(samples, features int, noiseLevel float64, rng *rand.Rand) (*TrainingData, error) {
 	// 生成多项式关系的合成数据
 	X := make([][]float64, samples)
 	y := make([]float64, samples)
@@ -439,20 +608,20 @@ func (du *DataUtils) generatePolynomialData(samples, features int, noiseLevel fl
 		target := 0.0
 		
 		for j := 0; j < features; j++ {
-			X[i][j] = rand.Float64()*4 - 2 // [-2, 2]
+			X[i][j] = rng.Float64()*4 - 2 // [-2, 2]
 			// 简单的二次关系
 			target += X[i][j] + 0.5*X[i][j]*X[i][j]
 		}
 		
 		// 添加噪声
-		noise := rand.NormFloat64() * noiseLevel
+		noise := rng.NormFloat64() * noiseLevel
 		y[i] = target + noise
 	}
 	
 	return du.CreateFromArrays(X, y, nil, "target")
 }
 
-func (du *DataUtils) generateClassificationData(samples, features int, noiseLevel float64) (*TrainingData, error) {
+func (du *DataUtils) generateClassificationData(samples, features int, noiseLevel float64, rng *rand.Rand) (*TrainingData, error) {
 	// 生成分类数据
 	X := make([][]float64, samples)
 	y := make([]float64, samples)
@@ -462,7 +631,7 @@ func (du *DataUtils) generateClassificationData(samples, features int, noiseLeve
 		sum := 0.0
 		
 		for j := 0; j < features; j++ {
-			X[i][j] = rand.Float64()*4 - 2 // [-2, 2]
+			X[i][j] = rng.Float64()*4 - 2 // [-2, 2]
 			sum += X[i][j]
 		}
 		
@@ -474,7 +643,7 @@ func (du *DataUtils) generateClassificationData(samples, features int, noiseLeve
 		}
 		
 		// 添加一些噪声（翻转标签）
-		if rand.Float64() < noiseLevel {
+		if rng.Float64() < noiseLevel {
 			y[i] = 1.0 - y[i]
 		}
 	}
@@ -0,0 +1,193 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 可从时间戳中提取的字段
+const (
+	DateYear      = "year"
+	DateMonth     = "month"     // 1-12
+	DateDay       = "day"       // 1-31
+	DateDayOfWeek = "dayofweek" // 0-6，星期日为0
+	DateDayOfYear = "dayofyear" // 1-366
+	DateHour      = "hour"      // 0-23
+	DateMinute    = "minute"    // 0-59
+)
+
+// datePeriods 周期性字段的周期及起始值，用于正弦/余弦编码
+var datePeriods = map[string]struct{ period, offset float64 }{
+	DateMonth:     {12, 1},
+	DateDay:       {31, 1},
+	DateDayOfWeek: {7, 0},
+	DateDayOfYear: {366, 1},
+	DateHour:      {24, 0},
+	DateMinute:    {60, 0},
+}
+
+// DateFeaturizer 将时间戳列（Unix秒，即FrameToTrainingData对时间列的转换结果）展开为年、月、星期、小时等字段，
+// 可作为流水线步骤。Cyclical为true时为周期性字段额外生成正弦/余弦编码（如12月与1月相邻），列名为"列名_字段_sin"。
+// 原时间戳列被生成的列替换；缺失的时间戳(NaN)生成的字段均为NaN
+type DateFeaturizer struct {
+	Columns   []string       `json:"columns"`  // 时间戳列
+	Fields    []string       `json:"fields"`   // 要提取的字段，为空时为year、month、dayofweek、hour
+	Cyclical  bool           `json:"cyclical"` // 是否为周期性字段生成正弦/余弦编码
+	Location  *time.Location `json:"-"`        // 提取字段时使用的时区，为nil时为UTC
+	columns   map[int]bool
+	names     []string
+	fields    []string
+	nFeatures int
+}
+
+// NewDateFeaturizer 创建时间特征提取器，提取年、月、星期和小时并生成周期编码
+func NewDateFeaturizer(columns ...string) *DateFeaturizer {
+	return &DateFeaturizer{Columns: columns, Cyclical: true}
+}
+
+// Fit 确定时间戳列和要提取的字段，变换本身不需要学习参数
+func (f *DateFeaturizer) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if len(f.Columns) == 0 {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "at least one timestamp column is required",
+		}
+	}
+	fields := f.Fields
+	if len(fields) == 0 {
+		fields = []string{DateYear, DateMonth, DateDayOfWeek, DateHour}
+	}
+	for _, field := range fields {
+		if _, periodic := datePeriods[field]; !periodic && field != DateYear {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: fmt.Sprintf("unsupported date field: %s", field),
+			}
+		}
+	}
+
+	names := columnNames(d)
+	indices, err := numericColumns(d, names, f.Columns)
+	if err != nil {
+		return err
+	}
+	columns := make(map[int]bool, len(indices))
+	for _, j := range indices {
+		columns[j] = true
+	}
+
+	f.columns = columns
+	f.names = names
+	f.fields = fields
+	_, f.nFeatures = d.Features.Dims()
+	return nil
+}
+
+// Transform 将时间戳列替换为提取出的字段，其余列保持原有顺序
+func (f *DateFeaturizer) Transform(d *TrainingData) (*TrainingData, error) {
+	if f.columns == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != f.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", f.nFeatures, c),
+		}
+	}
+
+	var names []string
+	for j := 0; j < c; j++ {
+		if !f.columns[j] {
+			names = append(names, f.names[j])
+			continue
+		}
+		for _, field := range f.fields {
+			names = append(names, f.names[j]+"_"+field)
+			if _, periodic := datePeriods[field]; periodic && f.Cyclical {
+				names = append(names, f.names[j]+"_"+field+"_sin", f.names[j]+"_"+field+"_cos")
+			}
+		}
+	}
+
+	loc := f.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	features := mat.NewDense(r, len(names), nil)
+	for i := 0; i < r; i++ {
+		offset := 0
+		for j := 0; j < c; j++ {
+			v := d.Features.At(i, j)
+			if !f.columns[j] {
+				features.Set(i, offset, v)
+				offset++
+				continue
+			}
+			var t time.Time
+			if !math.IsNaN(v) {
+				t = time.Unix(int64(math.Floor(v)), 0).In(loc)
+			}
+			for _, field := range f.fields {
+				value := math.NaN()
+				if !math.IsNaN(v) {
+					value = dateField(t, field)
+				}
+				features.Set(i, offset, value)
+				offset++
+				if p, periodic := datePeriods[field]; periodic && f.Cyclical {
+					angle := 2 * math.Pi * (value - p.offset) / p.period
+					features.Set(i, offset, math.Sin(angle))
+					features.Set(i, offset+1, math.Cos(angle))
+					offset += 2
+				}
+			}
+		}
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: names,
+		TargetName:   d.TargetName,
+		Categories:   categoriesFor(d.Categories, names),
+	}, nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (f *DateFeaturizer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(f, d)
+}
+
+// dateField 返回时间的指定字段
+func dateField(t time.Time, field string) float64 {
+	switch field {
+	case DateYear:
+		return float64(t.Year())
+	case DateMonth:
+		return float64(t.Month())
+	case DateDay:
+		return float64(t.Day())
+	case DateDayOfWeek:
+		return float64(t.Weekday())
+	case DateDayOfYear:
+		return float64(t.YearDay())
+	case DateHour:
+		return float64(t.Hour())
+	default:
+		return float64(t.Minute())
+	}
+}
@@ -0,0 +1,171 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DeduplicateConfig 重复行移除配置
+type DeduplicateConfig struct {
+	Tolerance    float64  `json:"tolerance"`         // 每列取值之差的绝对值不超过该值即视为相同，0表示完全相同
+	Columns      []string `json:"columns,omitempty"` // 参与比较的特征列，为空时比较全部特征
+	IgnoreTarget bool     `json:"ignore_target"`     // 为true时不比较目标变量，特征相同而目标不同的行也会被移除
+}
+
+// DuplicateRow 被移除的一行及与之重复的保留行
+type DuplicateRow struct {
+	Index       int `json:"index"`        // 在原数据中的行号
+	DuplicateOf int `json:"duplicate_of"` // 与之重复、被保留的行在原数据中的行号
+}
+
+// DeduplicateReport 重复行移除报告
+type DeduplicateReport struct {
+	Tolerance      float64        `json:"tolerance"`
+	TotalRows      int            `json:"total_rows"`
+	RemovedRows    int            `json:"removed_rows"`
+	RemovedIndices []int          `json:"removed_indices"` // 被移除行在原数据中的行号
+	Rows           []DuplicateRow `json:"rows"`
+	Groups         int            `json:"groups"` // 含重复行的组数，即至少有一个重复的保留行的个数
+}
+
+// Deduplicate 移除完全相同或近似相同的行，每组重复行只保留第一次出现的行，其样本权重不变。
+// 两行在每个被比较的列上取值之差都不超过Tolerance时视为重复，同一位置都为缺失值(NaN)也视为相同。
+// 重复行会同时出现在训练折和验证折中，使交叉验证得分虚高，建议在划分数据之前调用
+func (du *DataUtils) Deduplicate(data *TrainingData, config *DeduplicateConfig) (*TrainingData, *DeduplicateReport, error) {
+	if data == nil || data.Features == nil || data.Target == nil {
+		return nil, nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	if config == nil {
+		config = &DeduplicateConfig{}
+	}
+	if config.Tolerance < 0 || math.IsNaN(config.Tolerance) {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("tolerance cannot be negative, got %g", config.Tolerance),
+		}
+	}
+	r, c := data.Features.Dims()
+	if data.Target.Len() != r {
+		return nil, nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("feature rows (%d) must match target length (%d)", r, data.Target.Len()),
+		}
+	}
+
+	names := columnNames(data)
+	indices := make([]int, c)
+	for j := range indices {
+		indices[j] = j
+	}
+	if len(config.Columns) > 0 {
+		position := make(map[string]int, c)
+		for j, name := range names {
+			position[name] = j
+		}
+		indices = indices[:0]
+		for _, name := range config.Columns {
+			j, ok := position[name]
+			if !ok {
+				return nil, nil, &Error{
+					Code:    ErrInvalidParameters,
+					Message: fmt.Sprintf("unknown column: %s", name),
+				}
+			}
+			indices = append(indices, j)
+		}
+	}
+
+	rows := make([][]float64, r)
+	for i := range rows {
+		rows[i] = make([]float64, 0, len(indices)+1)
+		for _, j := range indices {
+			rows[i] = append(rows[i], data.Features.At(i, j))
+		}
+		if !config.IgnoreTarget {
+			rows[i] = append(rows[i], data.Target.AtVec(i))
+		}
+	}
+	duplicateOf := findDuplicates(rows, config.Tolerance)
+
+	report := &DeduplicateReport{Tolerance: config.Tolerance, TotalRows: r}
+	kept := make([]int, 0, r)
+	groups := make(map[int]bool)
+	for i, original := range duplicateOf {
+		if original < 0 {
+			kept = append(kept, i)
+			continue
+		}
+		report.RemovedIndices = append(report.RemovedIndices, i)
+		report.Rows = append(report.Rows, DuplicateRow{Index: i, DuplicateOf: original})
+		groups[original] = true
+	}
+	report.RemovedRows = len(report.RemovedIndices)
+	report.Groups = len(groups)
+	return subsetTrainingData(data, kept), report, nil
+}
+
+// findDuplicates 返回每行重复的、更早出现且被保留的行号，不是重复行时为-1。
+// 按首列排序后只在首列取值相差不超过tolerance的窗口内比较，首列为NaN的行只与同样为NaN的行比较
+func findDuplicates(rows [][]float64, tolerance float64) []int {
+	duplicateOf := make([]int, len(rows))
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		for i := range duplicateOf {
+			duplicateOf[i] = -1
+		}
+		return duplicateOf
+	}
+
+	var sorted, missing []int
+	for i, row := range rows {
+		if math.IsNaN(row[0]) {
+			missing = append(missing, i)
+		} else {
+			sorted = append(sorted, i)
+		}
+	}
+	sort.SliceStable(sorted, func(a, b int) bool { return rows[sorted[a]][0] < rows[sorted[b]][0] })
+	keys := make([]float64, len(sorted))
+	for p, i := range sorted {
+		keys[p] = rows[i][0]
+	}
+
+	for i, row := range rows {
+		duplicateOf[i] = -1
+		candidates := missing
+		if !math.IsNaN(row[0]) {
+			start := sort.SearchFloat64s(keys, row[0]-tolerance)
+			end := start
+			for end < len(keys) && keys[end] <= row[0]+tolerance {
+				end++
+			}
+			candidates = sorted[start:end]
+		}
+		for _, other := range candidates {
+			earliest := duplicateOf[i] < 0 || other < duplicateOf[i]
+			if other < i && duplicateOf[other] < 0 && earliest && rowsClose(row, rows[other], tolerance) {
+				duplicateOf[i] = other
+			}
+		}
+	}
+	return duplicateOf
+}
+
+// rowsClose 判断两行在每一列上取值之差都不超过tolerance，同为NaN视为相同
+func rowsClose(a, b []float64, tolerance float64) bool {
+	for j, v := range a {
+		if math.IsNaN(v) || math.IsNaN(b[j]) {
+			if math.IsNaN(v) != math.IsNaN(b[j]) {
+				return false
+			}
+			continue
+		}
+		if math.Abs(v-b[j]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package gomodel
+
+import (
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/data"
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+)
+
+// CorrelatedPair 一对高度相关的特征
+type CorrelatedPair struct {
+	Feature1    string  `json:"feature1"`
+	Feature2    string  `json:"feature2"`
+	Correlation float64 `json:"correlation"`
+}
+
+// CollinearityReport 多重共线性诊断结果
+type CollinearityReport struct {
+	FeatureNames     []string         `json:"feature_names"`
+	VIF              []float64        `json:"vif"`              // 各特征的方差膨胀因子，>5为中度、>10为严重共线性
+	ConditionNumber  float64          `json:"condition_number"` // 标准化特征矩阵的条件数，>30时系数估计不稳定
+	Correlations     [][]float64      `json:"correlations"`     // 特征间的皮尔逊相关系数矩阵
+	HighCorrelations []CorrelatedPair `json:"high_correlations"`
+	Warnings         []string         `json:"warnings"`
+	Collinear        bool             `json:"collinear"` // 是否存在严重共线性，为true时建议使用Ridge或PLS代替OLS
+}
+
+// DiagnoseCollinearity 诊断特征间的多重共线性：计算方差膨胀因子(VIF)、条件数和两两相关系数，
+// 相关系数绝对值不小于threshold的特征对会产生告警；threshold不大于0时使用0.9。
+// 完全共线或常数特征的VIF及条件数为+Inf
+func (du *DataUtils) DiagnoseCollinearity(d *TrainingData, threshold float64) (*CollinearityReport, error) {
+	if d == nil || d.Features == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+
+	report, err := data.DiagnoseCollinearity(toDataset(d), threshold)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to diagnose collinearity",
+			Details: err.Error(),
+		}
+	}
+
+	pairs := make([]CorrelatedPair, len(report.HighCorrelations))
+	for i, pair := range report.HighCorrelations {
+		pairs[i] = CorrelatedPair(pair)
+	}
+	return &CollinearityReport{
+		FeatureNames:     report.FeatureNames,
+		VIF:              report.VIF,
+		ConditionNumber:  report.ConditionNumber,
+		Correlations:     report.Correlations,
+		HighCorrelations: pairs,
+		Warnings:         report.Warnings,
+		Collinear:        report.Collinear,
+	}, nil
+}
+
+// BreuschPaganTest Breusch-Pagan异方差检验结果
+type BreuschPaganTest struct {
+	Statistic float64 `json:"statistic"` // LM统计量 n·R²
+	DF        int     `json:"df"`        // 卡方分布的自由度
+	PValue    float64 `json:"p_value"`   // 原假设为同方差，小于0.05时认为存在异方差
+}
+
+// ResidualSummary 残差与拟合值的汇总
+type ResidualSummary struct {
+	Mean                 float64 `json:"mean"`
+	Std                  float64 `json:"std"`
+	Min                  float64 `json:"min"`
+	Max                  float64 `json:"max"`
+	FittedCorrelation    float64 `json:"fitted_correlation"`     // 残差与拟合值的相关系数
+	AbsFittedCorrelation float64 `json:"abs_fitted_correlation"` // 残差绝对值与拟合值的相关系数，为正说明误差随拟合值增大
+	Outliers             []int   `json:"outliers"`               // 标准化残差绝对值超过3的样本下标
+}
+
+// ShapiroWilkTest Shapiro-Wilk正态性检验结果
+type ShapiroWilkTest struct {
+	Statistic float64 `json:"statistic"` // W统计量，越接近1越接近正态分布
+	PValue    float64 `json:"p_value"`   // 原假设为服从正态分布
+}
+
+// JarqueBeraTest Jarque-Bera正态性检验结果
+type JarqueBeraTest struct {
+	Statistic float64 `json:"statistic"`
+	PValue    float64 `json:"p_value"`  // 原假设为服从正态分布
+	Skewness  float64 `json:"skewness"` // 偏度，正态分布为0
+	Kurtosis  float64 `json:"kurtosis"` // 峰度，正态分布为3
+}
+
+// QQPoint 正态QQ图上的一个点：排序后的残差及对应的标准正态分位数
+type QQPoint struct {
+	Theoretical float64 `json:"theoretical"`
+	Sample      float64 `json:"sample"`
+}
+
+// ResidualDiagnostics 线性模型的残差诊断报告
+type ResidualDiagnostics struct {
+	NumSamples      int              `json:"num_samples"`
+	DurbinWatson    float64          `json:"durbin_watson"` // 接近2说明残差无一阶自相关
+	BreuschPagan    BreuschPaganTest `json:"breusch_pagan"`
+	ShapiroWilk     *ShapiroWilkTest `json:"shapiro_wilk,omitempty"` // 样本数超过5000或残差为常数时为nil
+	JarqueBera      *JarqueBeraTest  `json:"jarque_bera,omitempty"`  // 残差为常数时为nil
+	Residuals       ResidualSummary  `json:"residuals"`
+	QQPlot          []QQPoint        `json:"qq_plot"`
+	Autocorrelated  bool             `json:"autocorrelated"`  // Durbin-Watson统计量超出[1.5, 2.5]
+	Heteroskedastic bool             `json:"heteroskedastic"` // Breusch-Pagan检验的p值小于0.05
+	NonNormal       bool             `json:"non_normal"`      // Shapiro-Wilk（样本过多时为Jarque-Bera）检验的p值小于0.05
+	Biased          bool             `json:"biased"`          // 残差均值明显偏离0
+	Warnings        []string         `json:"warnings"`
+	Passed          bool             `json:"passed"` // 未发现自相关、异方差、非正态和系统性偏差
+}
+
+// Diagnostics 在数据上诊断训练好的线性模型（OLS、Ridge、Lasso、RidgeCV、LassoCV、PLS）的残差：
+// Durbin-Watson自相关检验（样本需按观测顺序排列）、Breusch-Pagan异方差检验、
+// Shapiro-Wilk和Jarque-Bera正态性检验、正态QQ图数据以及残差-拟合值汇总
+func (c *Client) Diagnostics(modelID string, data *TrainingData) (*ResidualDiagnostics, error) {
+	info, err := c.manager.GetModelInfo(modelID)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "model not found",
+			Details: err.Error(),
+		}
+	}
+	switch info.ModelType {
+	case "OLS", "Ridge", "Lasso", "RidgeCV", "LassoCV", "PLS":
+	default:
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("residual diagnostics require a linear regression model, got %s", info.ModelType),
+		}
+	}
+
+	yTrue, fitted, err := c.targetAndPredictions(modelID, data)
+	if err != nil {
+		return nil, err
+	}
+	report, err := evaluation.DiagnoseResiduals(data.Features, yTrue, fitted)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to diagnose residuals",
+			Details: err.Error(),
+		}
+	}
+
+	diagnostics := &ResidualDiagnostics{
+		NumSamples:      report.NumSamples,
+		DurbinWatson:    report.DurbinWatson,
+		BreuschPagan:    BreuschPaganTest(report.BreuschPagan),
+		Residuals:       ResidualSummary(report.Residuals),
+		QQPlot:          make([]QQPoint, len(report.QQPlot)),
+		Autocorrelated:  report.Autocorrelated,
+		Heteroskedastic: report.Heteroskedastic,
+		NonNormal:       report.NonNormal,
+		Biased:          report.Biased,
+		Warnings:        report.Warnings,
+		Passed:          report.Passed,
+	}
+	if report.ShapiroWilk != nil {
+		sw := ShapiroWilkTest(*report.ShapiroWilk)
+		diagnostics.ShapiroWilk = &sw
+	}
+	if report.JarqueBera != nil {
+		jb := JarqueBeraTest(*report.JarqueBera)
+		diagnostics.JarqueBera = &jb
+	}
+	for i, point := range report.QQPlot {
+		diagnostics.QQPlot[i] = QQPoint(point)
+	}
+	return diagnostics, nil
+}
@@ -0,0 +1,312 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 分箱策略
+const (
+	BinUniform  = "uniform"  // 等宽分箱
+	BinQuantile = "quantile" // 等频分箱，每箱样本数大致相同
+	BinKMeans   = "kmeans"   // 按一维k均值聚类的簇中心划分
+)
+
+// 分箱结果的编码方式
+const (
+	BinEncodeOrdinal = "ordinal" // 箱号0到n-1
+	BinEncodeOneHot  = "onehot"  // 每个箱一个0/1列，列名为"特征名=binK"
+)
+
+// kmeansBinIterations 一维k均值分箱的最大迭代次数
+const kmeansBinIterations = 300
+
+// KBinsDiscretizer 将连续特征离散化为NBins个箱，可作为流水线步骤。
+// 学到的分箱边界保存在Edges中，测试数据按相同边界分箱，超出训练范围的值归入首箱或末箱。
+// 取值重复较多时quantile和kmeans策略可能合并相同的边界，实际箱数少于NBins。
+// 缺失值(NaN)在ordinal编码下保持为NaN，在onehot编码下编码为全0
+type KBinsDiscretizer struct {
+	NBins     int                  `json:"n_bins"`   // 每列的箱数，至少为2
+	Strategy  string               `json:"strategy"` // uniform、quantile（为空时）或kmeans
+	Encode    string               `json:"encode"`   // ordinal（为空时）或onehot
+	Columns   []string             `json:"columns"`  // 要离散化的列，为空时离散化Categories中未记录的全部数值特征
+	Edges     map[string][]float64 `json:"edges"`    // 每列的分箱边界，长度为箱数+1
+	columns   []int
+	names     []string
+	nFeatures int
+}
+
+// NewKBinsDiscretizer 创建分箱变换器，columns为空时离散化全部数值特征
+func NewKBinsDiscretizer(nBins int, strategy, encode string, columns ...string) (*KBinsDiscretizer, error) {
+	k := &KBinsDiscretizer{NBins: nBins, Strategy: strategy, Encode: encode, Columns: columns}
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Fit 按分箱策略计算每列的分箱边界
+func (k *KBinsDiscretizer) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if err := k.validate(); err != nil {
+		return err
+	}
+
+	names := columnNames(d)
+	columns, err := numericColumns(d, names, k.Columns)
+	if err != nil {
+		return err
+	}
+
+	r, c := d.Features.Dims()
+	edges := make(map[string][]float64, len(columns))
+	values := make([]float64, r)
+	for _, j := range columns {
+		for i := range values {
+			values[i] = d.Features.At(i, j)
+		}
+		observed := observedValues(values)
+		if len(observed) == 0 {
+			return &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("feature %q has no observed values to compute bin edges", names[j]),
+			}
+		}
+		sort.Float64s(observed)
+
+		switch k.Strategy {
+		case BinUniform:
+			edges[names[j]] = uniformEdges(observed, k.NBins)
+		case BinKMeans:
+			edges[names[j]] = kmeansEdges(observed, k.NBins)
+		default:
+			edges[names[j]] = quantileEdges(observed, k.NBins)
+		}
+	}
+
+	k.Edges = edges
+	k.columns = columns
+	k.names = names
+	k.nFeatures = c
+	return nil
+}
+
+// Transform 按学到的边界将特征替换为箱号或箱的哑变量
+func (k *KBinsDiscretizer) Transform(d *TrainingData) (*TrainingData, error) {
+	if k.Edges == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != k.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", k.nFeatures, c),
+		}
+	}
+
+	binned := make(map[int][]float64, len(k.columns))
+	width := c
+	for _, j := range k.columns {
+		edges := k.Edges[k.names[j]]
+		bins := make([]float64, r)
+		for i := range bins {
+			bins[i] = binIndex(edges, d.Features.At(i, j))
+		}
+		binned[j] = bins
+		if k.Encode == BinEncodeOneHot {
+			width += len(edges) - 2
+		}
+	}
+
+	if k.Encode != BinEncodeOneHot {
+		features := mat.DenseCopyOf(d.Features)
+		for j, bins := range binned {
+			features.SetCol(j, bins)
+		}
+		return &TrainingData{
+			Features:     features,
+			Target:       d.Target,
+			Weights:      d.Weights,
+			FeatureNames: d.FeatureNames,
+			TargetName:   d.TargetName,
+			Categories:   d.Categories,
+		}, nil
+	}
+
+	features := mat.NewDense(r, width, nil)
+	names := make([]string, 0, width)
+	offset := 0
+	for j := 0; j < c; j++ {
+		bins, ok := binned[j]
+		if !ok {
+			for i := 0; i < r; i++ {
+				features.Set(i, offset, d.Features.At(i, j))
+			}
+			names = append(names, k.names[j])
+			offset++
+			continue
+		}
+
+		nBins := len(k.Edges[k.names[j]]) - 1
+		for i, bin := range bins {
+			if !math.IsNaN(bin) {
+				features.Set(i, offset+int(bin), 1)
+			}
+		}
+		for b := 0; b < nBins; b++ {
+			names = append(names, fmt.Sprintf("%s=bin%d", k.names[j], b))
+		}
+		offset += nBins
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: names,
+		TargetName:   d.TargetName,
+		Categories:   categoriesFor(d.Categories, names),
+	}, nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (k *KBinsDiscretizer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(k, d)
+}
+
+// validate 检查箱数、分箱策略和编码方式
+func (k *KBinsDiscretizer) validate() error {
+	if k.NBins < 2 {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("n_bins must be at least 2, got %d", k.NBins),
+		}
+	}
+	switch k.Strategy {
+	case "", BinUniform, BinQuantile, BinKMeans:
+	default:
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported binning strategy: %s", k.Strategy),
+		}
+	}
+	switch k.Encode {
+	case "", BinEncodeOrdinal, BinEncodeOneHot:
+	default:
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported bin encoding: %s", k.Encode),
+		}
+	}
+	return nil
+}
+
+// binIndex 返回x所在的箱号：第b箱为[edges[b], edges[b+1])，超出范围的值归入首箱或末箱，NaN返回NaN
+func binIndex(edges []float64, x float64) float64 {
+	if math.IsNaN(x) {
+		return x
+	}
+	inner := edges[1 : len(edges)-1]
+	return float64(sort.Search(len(inner), func(b int) bool { return inner[b] > x }))
+}
+
+// uniformEdges 在最小值和最大值之间等宽划分
+func uniformEdges(sorted []float64, nBins int) []float64 {
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	if lo == hi {
+		return []float64{lo, hi}
+	}
+	edges := make([]float64, nBins+1)
+	for b := range edges {
+		edges[b] = lo + (hi-lo)*float64(b)/float64(nBins)
+	}
+	return edges
+}
+
+// quantileEdges 以等间隔的分位数为边界，合并相同的边界
+func quantileEdges(sorted []float64, nBins int) []float64 {
+	edges := make([]float64, 0, nBins+1)
+	for b := 0; b <= nBins; b++ {
+		edges = append(edges, quantile(sorted, float64(b)/float64(nBins)))
+	}
+	return uniqueEdges(edges)
+}
+
+// kmeansEdges 对取值做一维k均值聚类（簇中心初始化为等宽箱的中点），以相邻簇中心的中点为边界
+func kmeansEdges(sorted []float64, nBins int) []float64 {
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	if lo == hi {
+		return []float64{lo, hi}
+	}
+	centers := make([]float64, nBins)
+	for b := range centers {
+		centers[b] = lo + (hi-lo)*(float64(b)+0.5)/float64(nBins)
+	}
+
+	sums := make([]float64, nBins)
+	counts := make([]int, nBins)
+	for iter := 0; iter < kmeansBinIterations; iter++ {
+		for b := range sums {
+			sums[b], counts[b] = 0, 0
+		}
+		// 簇中心有序，取值按与相邻中心中点的比较归入对应的簇
+		boundaries := midpoints(centers)
+		for _, v := range sorted {
+			b := sort.Search(len(boundaries), func(m int) bool { return boundaries[m] > v })
+			sums[b] += v
+			counts[b]++
+		}
+
+		changed := false
+		for b := range centers {
+			if counts[b] == 0 {
+				continue
+			}
+			if center := sums[b] / float64(counts[b]); center != centers[b] {
+				centers[b] = center
+				changed = true
+			}
+		}
+		sort.Float64s(centers)
+		if !changed {
+			break
+		}
+	}
+
+	edges := append([]float64{lo}, midpoints(centers)...)
+	return uniqueEdges(append(edges, hi))
+}
+
+// midpoints 返回有序值中相邻两个值的中点
+func midpoints(sorted []float64) []float64 {
+	result := make([]float64, len(sorted)-1)
+	for b := range result {
+		result[b] = (sorted[b] + sorted[b+1]) / 2
+	}
+	return result
+}
+
+// uniqueEdges 去掉有序边界中重复的值，至少保留首尾两个边界
+func uniqueEdges(edges []float64) []float64 {
+	result := edges[:1]
+	for _, e := range edges[1:] {
+		if e > result[len(result)-1] {
+			result = append(result, e)
+		}
+	}
+	if len(result) == 1 {
+		result = append(result, result[0])
+	}
+	return result
+}
@@ -0,0 +1,922 @@
+package gomodel
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 遇到训练时未见过的类别时的处理方式
+const (
+	HandleUnknownIgnore = "ignore"
+	HandleUnknownError  = "error"
+)
+
+// OneHotEncoder 将类别特征展开为0/1哑变量列，列名为"特征名=类别"，可作为流水线步骤。
+// 类别特征的值为类别码（LoadFromCSVCategorical加载的字符串列即按此编码）；
+// 数据带有Categories时按类别标签匹配，因此预测数据即使单独加载、编码不同也能正确展开
+type OneHotEncoder struct {
+	Columns       []string            `json:"columns"`        // 要编码的列，为空时编码数据Categories中记录的全部类别特征
+	HandleUnknown string              `json:"handle_unknown"` // ignore（默认）时未见过的类别编码为全0，error时返回错误
+	Categories    map[string][]string `json:"categories"`     // 每列学到的类别标签，依次对应展开后的哑变量列
+	encoded       []int               // 被编码列的索引
+	codes         []map[float64]int   // 训练数据中的类别码到哑变量位置
+	positions     []map[string]int    // 类别标签到哑变量位置
+	names         []string
+	nFeatures     int
+}
+
+// NewOneHotEncoder 创建独热编码器，columns为空时自动使用数据中的类别特征
+func NewOneHotEncoder(columns ...string) *OneHotEncoder {
+	return &OneHotEncoder{
+		Columns:       columns,
+		HandleUnknown: HandleUnknownIgnore,
+	}
+}
+
+// Fit 学习每个类别特征的类别集合
+func (e *OneHotEncoder) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if e.HandleUnknown == "" {
+		e.HandleUnknown = HandleUnknownIgnore
+	}
+	if e.HandleUnknown != HandleUnknownIgnore && e.HandleUnknown != HandleUnknownError {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported handle_unknown: %s", e.HandleUnknown),
+		}
+	}
+
+	names := columnNames(d)
+	encoded, err := categoricalColumns(d, names, e.Columns)
+	if err != nil {
+		return err
+	}
+
+	r, _ := d.Features.Dims()
+	e.Categories = make(map[string][]string, len(encoded))
+	e.codes = make([]map[float64]int, len(encoded))
+	e.positions = make([]map[string]int, len(encoded))
+	for k, j := range encoded {
+		var seen []float64
+		found := make(map[float64]bool)
+		for i := 0; i < r; i++ {
+			v := d.Features.At(i, j)
+			if !math.IsNaN(v) && !found[v] {
+				found[v] = true
+				seen = append(seen, v)
+			}
+		}
+		sort.Float64s(seen)
+
+		labels := make([]string, len(seen))
+		e.codes[k] = make(map[float64]int, len(seen))
+		e.positions[k] = make(map[string]int, len(seen))
+		for p, code := range seen {
+			labels[p] = categoryLabel(d.Categories[names[j]], code)
+			e.codes[k][code] = p
+			e.positions[k][labels[p]] = p
+		}
+		e.Categories[names[j]] = labels
+	}
+
+	e.encoded = encoded
+	e.names = names
+	_, e.nFeatures = d.Features.Dims()
+	return nil
+}
+
+// Transform 将类别特征展开为哑变量，其余列保持原有顺序；缺失值(NaN)编码为全0
+func (e *OneHotEncoder) Transform(d *TrainingData) (*TrainingData, error) {
+	if e.encoded == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != e.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", e.nFeatures, c),
+		}
+	}
+
+	encodedAt := make(map[int]int, len(e.encoded))
+	width := c
+	for k, j := range e.encoded {
+		encodedAt[j] = k
+		width += len(e.Categories[e.names[j]]) - 1
+	}
+
+	features := mat.NewDense(r, width, nil)
+	names := make([]string, 0, width)
+	offset := 0
+	for j := 0; j < c; j++ {
+		k, ok := encodedAt[j]
+		if !ok {
+			for i := 0; i < r; i++ {
+				features.Set(i, offset, d.Features.At(i, j))
+			}
+			names = append(names, e.names[j])
+			offset++
+			continue
+		}
+
+		labels := e.Categories[e.names[j]]
+		for i := 0; i < r; i++ {
+			v := d.Features.At(i, j)
+			if math.IsNaN(v) {
+				continue
+			}
+			p, known := e.position(k, d.Categories[e.names[j]], v)
+			if !known {
+				if e.HandleUnknown == HandleUnknownError {
+					return nil, &Error{
+						Code:    ErrInvalidData,
+						Message: fmt.Sprintf("unknown category %q in feature %q at row %d", categoryLabel(d.Categories[e.names[j]], v), e.names[j], i),
+					}
+				}
+				continue
+			}
+			features.Set(i, offset+p, 1)
+		}
+		for _, label := range labels {
+			names = append(names, e.names[j]+"="+label)
+		}
+		offset += len(labels)
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: names,
+		TargetName:   d.TargetName,
+		Categories:   categoriesFor(d.Categories, names),
+	}, nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (e *OneHotEncoder) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(e, d)
+}
+
+// position 返回值在第k个编码列中的哑变量位置：有类别表时按标签匹配，否则按类别码匹配
+func (e *OneHotEncoder) position(k int, labels []string, v float64) (int, bool) {
+	if labels != nil {
+		p, ok := e.positions[k][categoryLabel(labels, v)]
+		return p, ok
+	}
+	p, ok := e.codes[k][v]
+	return p, ok
+}
+
+// categoricalColumns 确定要编码的列：优先使用指定的列名，否则使用数据中记录了类别表的列
+func categoricalColumns(d *TrainingData, names, columns []string) ([]int, error) {
+	position := make(map[string]int, len(names))
+	for j, name := range names {
+		position[name] = j
+	}
+
+	var indices []int
+	if len(columns) > 0 {
+		for _, column := range columns {
+			j, ok := position[column]
+			if !ok {
+				return nil, &Error{
+					Code:    ErrInvalidData,
+					Message: fmt.Sprintf("column %q not found", column),
+				}
+			}
+			indices = append(indices, j)
+		}
+		sort.Ints(indices)
+		return indices, nil
+	}
+
+	for j, name := range names {
+		if _, ok := d.Categories[name]; ok {
+			indices = append(indices, j)
+		}
+	}
+	if len(indices) == 0 {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "no categorical columns found; specify columns or load data with LoadFromCSVCategorical",
+		}
+	}
+	return indices, nil
+}
+
+// categoryLabel 返回类别码对应的标签，没有类别表或类别码超出范围时使用数值本身
+func categoryLabel(labels []string, code float64) string {
+	if i := int(code); float64(i) == code && i >= 0 && i < len(labels) {
+		return labels[i]
+	}
+	return strconv.FormatFloat(code, 'g', -1, 64)
+}
+
+// LabelEncoder 将分类目标的类别标签编码为0到n-1的类别码，并可将预测结果还原为标签。
+// Classes按字典序排列，即学到的映射；它可随模型一起序列化，使单独加载的预测数据得到相同的编码
+type LabelEncoder struct {
+	Classes []string `json:"classes"`
+	index   map[string]int
+}
+
+// NewLabelEncoder 创建标签编码器
+func NewLabelEncoder() *LabelEncoder {
+	return &LabelEncoder{}
+}
+
+// Fit 学习类别集合
+func (e *LabelEncoder) Fit(labels []string) error {
+	if len(labels) == 0 {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "labels cannot be empty",
+		}
+	}
+	seen := make(map[string]bool)
+	classes := make([]string, 0)
+	for _, label := range labels {
+		if !seen[label] {
+			seen[label] = true
+			classes = append(classes, label)
+		}
+	}
+	sort.Strings(classes)
+	e.Classes = classes
+	e.index = nil
+	return nil
+}
+
+// Transform 将标签编码为类别码，遇到未见过的标签时返回错误
+func (e *LabelEncoder) Transform(labels []string) ([]float64, error) {
+	if len(e.Classes) == 0 {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "label encoder is not fitted",
+		}
+	}
+	if e.index == nil {
+		e.index = make(map[string]int, len(e.Classes))
+		for code, class := range e.Classes {
+			e.index[class] = code
+		}
+	}
+
+	codes := make([]float64, len(labels))
+	for i, label := range labels {
+		code, ok := e.index[label]
+		if !ok {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("unknown label %q at row %d", label, i),
+			}
+		}
+		codes[i] = float64(code)
+	}
+	return codes, nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (e *LabelEncoder) FitTransform(labels []string) ([]float64, error) {
+	if err := e.Fit(labels); err != nil {
+		return nil, err
+	}
+	return e.Transform(labels)
+}
+
+// InverseTransform 将类别码（如分类器的预测结果）还原为标签，非整数的值四舍五入
+func (e *LabelEncoder) InverseTransform(codes []float64) ([]string, error) {
+	if len(e.Classes) == 0 {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "label encoder is not fitted",
+		}
+	}
+	labels := make([]string, len(codes))
+	for i, v := range codes {
+		code := int(math.Round(v))
+		if math.IsNaN(v) || code < 0 || code >= len(e.Classes) {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("code %v at row %d is out of range", v, i),
+			}
+		}
+		labels[i] = e.Classes[code]
+	}
+	return labels, nil
+}
+
+// FitTarget 在训练数据的目标标签上学习类别集合，目标标签由Categories中TargetName对应的类别表还原
+func (e *LabelEncoder) FitTarget(d *TrainingData) error {
+	labels, err := targetLabels(d)
+	if err != nil {
+		return err
+	}
+	return e.Fit(labels)
+}
+
+// TransformTarget 按学到的类别重新编码目标变量，返回的数据中目标类别表更新为Classes
+func (e *LabelEncoder) TransformTarget(d *TrainingData) (*TrainingData, error) {
+	labels, err := targetLabels(d)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := e.Transform(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string][]string, len(d.Categories))
+	for name, values := range d.Categories {
+		categories[name] = values
+	}
+	categories[d.TargetName] = e.Classes
+
+	return &TrainingData{
+		Features:     d.Features,
+		Target:       mat.NewVecDense(len(codes), codes),
+		Weights:      d.Weights,
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   categories,
+	}, nil
+}
+
+// targetLabels 将目标变量的类别码还原为标签
+func targetLabels(d *TrainingData) ([]string, error) {
+	if d == nil || d.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "target cannot be nil",
+		}
+	}
+	classes, ok := d.Categories[d.TargetName]
+	if !ok {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("target %q has no category labels; load data with LoadFromCSVCategorical", d.TargetName),
+		}
+	}
+	labels := make([]string, d.Target.Len())
+	for i := range labels {
+		labels[i] = categoryLabel(classes, d.Target.AtVec(i))
+	}
+	return labels, nil
+}
+
+// OrdinalEncoder 按用户指定的类别顺序将类别特征编码为0到n-1的序数，可作为流水线步骤。
+// 未指定顺序的列在Fit时按类别表的顺序补全到Orders中，Orders即学到的映射，可随模型一起序列化
+type OrdinalEncoder struct {
+	Columns       []string            `json:"columns"`        // 要编码的列，为空时使用Orders中的列，二者都为空时编码全部类别特征
+	Orders        map[string][]string `json:"orders"`         // 每列类别由低到高的顺序
+	HandleUnknown string              `json:"handle_unknown"` // ignore（默认）时未见过的类别编码为NaN，error时返回错误
+	encoded       []int
+	ranks         []map[string]int
+	names         []string
+	nFeatures     int
+}
+
+// NewOrdinalEncoder 创建序数编码器，orders为每列类别由低到高的顺序
+func NewOrdinalEncoder(orders map[string][]string) *OrdinalEncoder {
+	return &OrdinalEncoder{
+		Orders:        orders,
+		HandleUnknown: HandleUnknownIgnore,
+	}
+}
+
+// Fit 确定要编码的列及每列的类别顺序
+func (e *OrdinalEncoder) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if e.HandleUnknown == "" {
+		e.HandleUnknown = HandleUnknownIgnore
+	}
+	if e.HandleUnknown != HandleUnknownIgnore && e.HandleUnknown != HandleUnknownError {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported handle_unknown: %s", e.HandleUnknown),
+		}
+	}
+
+	names := columnNames(d)
+	columns := e.Columns
+	if len(columns) == 0 {
+		for name := range e.Orders {
+			columns = append(columns, name)
+		}
+	}
+	encoded, err := categoricalColumns(d, names, columns)
+	if err != nil {
+		return err
+	}
+
+	orders := make(map[string][]string, len(encoded))
+	for name, order := range e.Orders {
+		orders[name] = order
+	}
+	r, _ := d.Features.Dims()
+	ranks := make([]map[string]int, len(encoded))
+	for k, j := range encoded {
+		name := names[j]
+		order, ok := orders[name]
+		if !ok {
+			if labels, hasLabels := d.Categories[name]; hasLabels {
+				order = append([]string(nil), labels...)
+			} else {
+				var values []float64
+				seen := make(map[float64]bool)
+				for i := 0; i < r; i++ {
+					if v := d.Features.At(i, j); !math.IsNaN(v) && !seen[v] {
+						seen[v] = true
+						values = append(values, v)
+					}
+				}
+				sort.Float64s(values)
+				for _, v := range values {
+					order = append(order, categoryLabel(nil, v))
+				}
+			}
+			orders[name] = order
+		}
+
+		ranks[k] = make(map[string]int, len(order))
+		for rank, label := range order {
+			if _, duplicate := ranks[k][label]; duplicate {
+				return &Error{
+					Code:    ErrInvalidParameters,
+					Message: fmt.Sprintf("duplicate category %q in order of feature %q", label, name),
+				}
+			}
+			ranks[k][label] = rank
+		}
+	}
+
+	e.Orders = orders
+	e.encoded = encoded
+	e.ranks = ranks
+	e.names = names
+	_, e.nFeatures = d.Features.Dims()
+	return nil
+}
+
+// Transform 将类别替换为其在顺序中的位置，缺失值(NaN)保持不变
+func (e *OrdinalEncoder) Transform(d *TrainingData) (*TrainingData, error) {
+	if e.encoded == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != e.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", e.nFeatures, c),
+		}
+	}
+
+	features := mat.DenseCopyOf(d.Features)
+	categories := make(map[string][]string, len(d.Categories))
+	for name, labels := range d.Categories {
+		categories[name] = labels
+	}
+	for k, j := range e.encoded {
+		name := e.names[j]
+		labels := d.Categories[name]
+		for i := 0; i < r; i++ {
+			v := features.At(i, j)
+			if math.IsNaN(v) {
+				continue
+			}
+			label := categoryLabel(labels, v)
+			rank, ok := e.ranks[k][label]
+			if !ok {
+				if e.HandleUnknown == HandleUnknownError {
+					return nil, &Error{
+						Code:    ErrInvalidData,
+						Message: fmt.Sprintf("unknown category %q in feature %q at row %d", label, name, i),
+					}
+				}
+				features.Set(i, j, math.NaN())
+				continue
+			}
+			features.Set(i, j, float64(rank))
+		}
+		delete(categories, name)
+	}
+	if len(categories) == 0 {
+		categories = nil
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   categories,
+	}, nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (e *OrdinalEncoder) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(e, d)
+}
+
+// TargetEncoder 将类别特征替换为该类别目标均值的平滑估计，适用于独热编码会产生过多列的高基数类别特征，
+// 可作为流水线步骤。编码值为(n*类别均值+Smoothing*全局均值)/(n+Smoothing)，样本少的类别向全局均值收缩。
+// FitTransform（流水线训练时调用）对训练数据使用K折外的均值编码，每行的编码值不包含该行自身的目标值，
+// 避免目标泄露；Transform使用在全部训练数据上计算的Encodings。分类目标只适用于二分类（编码值为正类比例）
+type TargetEncoder struct {
+	Columns    []string                      `json:"columns"`     // 要编码的列，为空时编码数据Categories中记录的全部类别特征
+	Smoothing  float64                       `json:"smoothing"`   // 平滑强度，0表示不平滑
+	Folds      int                           `json:"folds"`       // FitTransform时的折数
+	RandomSeed int64                         `json:"random_seed"` // 划分折的随机种子
+	Encodings  map[string]map[string]float64 `json:"encodings"`   // 每列类别标签到编码值
+	TargetMean float64                       `json:"target_mean"` // 全局目标均值，用于缺失值和未见过的类别
+	encoded    []int
+	names      []string
+	nFeatures  int
+}
+
+// NewTargetEncoder 创建目标编码器，默认平滑强度为10、5折，columns为空时自动使用数据中的类别特征
+func NewTargetEncoder(columns ...string) *TargetEncoder {
+	return &TargetEncoder{
+		Columns:    columns,
+		Smoothing:  10,
+		Folds:      5,
+		RandomSeed: 42,
+	}
+}
+
+// Fit 在全部训练数据上计算每个类别的编码值
+func (e *TargetEncoder) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	if e.Smoothing < 0 || math.IsNaN(e.Smoothing) {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "smoothing must be non-negative",
+		}
+	}
+
+	names := columnNames(d)
+	encoded, err := categoricalColumns(d, names, e.Columns)
+	if err != nil {
+		return err
+	}
+	e.encoded = encoded
+	e.names = names
+	_, e.nFeatures = d.Features.Dims()
+
+	rows := make([]int, d.Target.Len())
+	for i := range rows {
+		rows[i] = i
+	}
+	means, prior := e.targetMeans(d, rows)
+	e.Encodings = make(map[string]map[string]float64, len(encoded))
+	for k, j := range encoded {
+		e.Encodings[names[j]] = means[k]
+	}
+	e.TargetMean = prior
+	return nil
+}
+
+// Transform 使用Fit学到的编码值替换类别特征，缺失值(NaN)和未见过的类别编码为全局目标均值
+func (e *TargetEncoder) Transform(d *TrainingData) (*TrainingData, error) {
+	if e.encoded == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != e.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", e.nFeatures, c),
+		}
+	}
+
+	means := make([]map[string]float64, len(e.encoded))
+	for k, j := range e.encoded {
+		means[k] = e.Encodings[e.names[j]]
+	}
+	rows := make([]int, r)
+	for i := range rows {
+		rows[i] = i
+	}
+	features := mat.DenseCopyOf(d.Features)
+	e.apply(d, features, rows, means, e.TargetMean)
+	return e.result(d, features), nil
+}
+
+// FitTransform 拟合后对训练数据做K折外编码：每折的行使用其余折计算的编码值
+func (e *TargetEncoder) FitTransform(d *TrainingData) (*TrainingData, error) {
+	if err := e.Fit(d); err != nil {
+		return nil, err
+	}
+	n := d.Target.Len()
+	if e.Folds < 2 || e.Folds > n {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("folds must be between 2 and %d", n),
+		}
+	}
+
+	features := mat.DenseCopyOf(d.Features)
+	perm := rand.New(rand.NewSource(e.RandomSeed)).Perm(n)
+	for k := 0; k < e.Folds; k++ {
+		start, end := k*n/e.Folds, (k+1)*n/e.Folds
+		fitRows := append(append([]int(nil), perm[:start]...), perm[end:]...)
+		means, prior := e.targetMeans(d, fitRows)
+		e.apply(d, features, perm[start:end], means, prior)
+	}
+	return e.result(d, features), nil
+}
+
+// targetMeans 在指定的行上计算每个编码列各类别的平滑目标均值，并返回这些行的目标均值
+func (e *TargetEncoder) targetMeans(d *TrainingData, rows []int) ([]map[string]float64, float64) {
+	prior := 0.0
+	for _, i := range rows {
+		prior += d.Target.AtVec(i)
+	}
+	prior /= float64(len(rows))
+
+	means := make([]map[string]float64, len(e.encoded))
+	for k, j := range e.encoded {
+		labels := d.Categories[e.names[j]]
+		sums := make(map[string]float64)
+		counts := make(map[string]float64)
+		for _, i := range rows {
+			v := d.Features.At(i, j)
+			if math.IsNaN(v) {
+				continue
+			}
+			label := categoryLabel(labels, v)
+			sums[label] += d.Target.AtVec(i)
+			counts[label]++
+		}
+		means[k] = make(map[string]float64, len(sums))
+		for label, sum := range sums {
+			means[k][label] = (sum + e.Smoothing*prior) / (counts[label] + e.Smoothing)
+		}
+	}
+	return means, prior
+}
+
+// apply 将指定行的编码列替换为编码值
+func (e *TargetEncoder) apply(d *TrainingData, features *mat.Dense, rows []int, means []map[string]float64, prior float64) {
+	for k, j := range e.encoded {
+		labels := d.Categories[e.names[j]]
+		for _, i := range rows {
+			value := prior
+			if v := d.Features.At(i, j); !math.IsNaN(v) {
+				if encoded, ok := means[k][categoryLabel(labels, v)]; ok {
+					value = encoded
+				}
+			}
+			features.Set(i, j, value)
+		}
+	}
+}
+
+// result 组装编码后的数据，被编码列不再是类别特征
+func (e *TargetEncoder) result(d *TrainingData, features *mat.Dense) *TrainingData {
+	categories := make(map[string][]string, len(d.Categories))
+	for name, labels := range d.Categories {
+		categories[name] = labels
+	}
+	for _, j := range e.encoded {
+		delete(categories, e.names[j])
+	}
+	if len(categories) == 0 {
+		categories = nil
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   categories,
+	}
+}
+
+// FeatureHasher 用哈希技巧将类别特征映射到固定数量的列，无需保存类别表，适用于类别数极多或随时间增长的特征，
+// 可作为流水线步骤。每个"特征名=类别"经FNV-1a哈希后落入NFeatures个桶之一，
+// AlternateSign为true时按哈希的另一位取±1，使碰撞的值在期望上相互抵消。
+// 被哈希的列替换为hash_0到hash_{NFeatures-1}列，追加在其余列之后
+type FeatureHasher struct {
+	Columns       []string `json:"columns"`        // 要哈希的列，为空时使用数据Categories中记录的全部类别特征
+	NFeatures     int      `json:"n_features"`     // 输出的哈希列数
+	AlternateSign bool     `json:"alternate_sign"` // 是否按哈希值交替符号
+	hashed        []int
+	names         []string
+	nFeatures     int
+}
+
+// NewFeatureHasher 创建特征哈希器，nFeatures为输出的哈希列数
+func NewFeatureHasher(nFeatures int, columns ...string) (*FeatureHasher, error) {
+	if nFeatures < 1 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "n_features must be positive",
+		}
+	}
+	return &FeatureHasher{
+		Columns:       columns,
+		NFeatures:     nFeatures,
+		AlternateSign: true,
+	}, nil
+}
+
+// Fit 确定要哈希的列，哈希本身不需要从数据中学习
+func (h *FeatureHasher) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if h.NFeatures < 1 {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "n_features must be positive",
+		}
+	}
+
+	names := columnNames(d)
+	hashed, err := categoricalColumns(d, names, h.Columns)
+	if err != nil {
+		return err
+	}
+	h.hashed = hashed
+	h.names = names
+	_, h.nFeatures = d.Features.Dims()
+	return nil
+}
+
+// Transform 将被哈希的列替换为哈希列，缺失值(NaN)不计入任何桶
+func (h *FeatureHasher) Transform(d *TrainingData) (*TrainingData, error) {
+	if h.hashed == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != h.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", h.nFeatures, c),
+		}
+	}
+
+	isHashed := make(map[int]bool, len(h.hashed))
+	for _, j := range h.hashed {
+		isHashed[j] = true
+	}
+	var kept []int
+	var names []string
+	for j := 0; j < c; j++ {
+		if !isHashed[j] {
+			kept = append(kept, j)
+			names = append(names, h.names[j])
+		}
+	}
+	offset := len(kept)
+	for b := 0; b < h.NFeatures; b++ {
+		names = append(names, fmt.Sprintf("hash_%d", b))
+	}
+
+	features := mat.NewDense(r, offset+h.NFeatures, nil)
+	for i := 0; i < r; i++ {
+		for p, j := range kept {
+			features.Set(i, p, d.Features.At(i, j))
+		}
+		for _, j := range h.hashed {
+			v := d.Features.At(i, j)
+			if math.IsNaN(v) {
+				continue
+			}
+			bucket, sign := h.bucket(h.names[j] + "=" + categoryLabel(d.Categories[h.names[j]], v))
+			features.Set(i, offset+bucket, features.At(i, offset+bucket)+sign)
+		}
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: names,
+		TargetName:   d.TargetName,
+		Categories:   categoriesFor(d.Categories, names),
+	}, nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (h *FeatureHasher) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(h, d)
+}
+
+// TransformTokens 将每个样本的字符串记号（如分词后的文本或"键=值"形式的特征）哈希为NFeatures列的计数矩阵，
+// 重复出现的记号累加计数
+func (h *FeatureHasher) TransformTokens(samples [][]string) (*mat.Dense, error) {
+	if h.NFeatures < 1 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "n_features must be positive",
+		}
+	}
+	if len(samples) == 0 {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "samples cannot be empty",
+		}
+	}
+
+	features := mat.NewDense(len(samples), h.NFeatures, nil)
+	for i, tokens := range samples {
+		for _, token := range tokens {
+			bucket, sign := h.bucket(token)
+			features.Set(i, bucket, features.At(i, bucket)+sign)
+		}
+	}
+	return features, nil
+}
+
+// bucket 返回记号的哈希桶和符号
+func (h *FeatureHasher) bucket(token string) (int, float64) {
+	hash := fnv.New32a()
+	hash.Write([]byte(token))
+	sum := hash.Sum32()
+	sign := 1.0
+	if h.AlternateSign && sum&(1<<31) != 0 {
+		sign = -1
+	}
+	return int(sum % uint32(h.NFeatures)), sign
+}
+
+// TransformTokensSparse 与TransformTokens相同，但返回稀疏矩阵，适合NFeatures很大的情况
+func (h *FeatureHasher) TransformTokensSparse(samples [][]string) (*SparseMatrix, error) {
+	if h.NFeatures < 1 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "n_features must be positive",
+		}
+	}
+	if len(samples) == 0 {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "samples cannot be empty",
+		}
+	}
+
+	indptr := make([]int, len(samples)+1)
+	var indices []int
+	var values []float64
+	for i, tokens := range samples {
+		counts := make(map[int]float64)
+		for _, token := range tokens {
+			bucket, sign := h.bucket(token)
+			counts[bucket] += sign
+		}
+		buckets := make([]int, 0, len(counts))
+		for bucket, count := range counts {
+			if count != 0 {
+				buckets = append(buckets, bucket)
+			}
+		}
+		sort.Ints(buckets)
+		for _, bucket := range buckets {
+			indices = append(indices, bucket)
+			values = append(values, counts[bucket])
+		}
+		indptr[i+1] = len(values)
+	}
+	return NewSparseMatrix(len(samples), h.NFeatures, indptr, indices, values)
+}
@@ -0,0 +1,137 @@
+package gomodel
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/feiyuluoye/Go-Model/internal/export"
+	"github.com/feiyuluoye/Go-Model/internal/models"
+)
+
+// ExportONNX 将已训练的线性模型导出为ONNX模型写入w，以便在onnxruntime等非Go运行时中部署。
+// OLS、Ridge、Lasso、RidgeCV、LassoCV和PLS导出为ai.onnx.ml的LinearRegressor，输入为float张量"X" [N, 特征数]，
+// 输出"Y" [N, 1]；Logistic导出为LinearClassifier，输出int64类别"label" [N]和类别0、1的概率"probabilities" [N, 2]。
+// 导出的系数为float32精度
+func (c *Client) ExportONNX(modelID string, w io.Writer) error {
+	return exportModel(c.manager, modelID, "ONNX", func(model models.Model) error {
+		return export.ONNX(model, w)
+	})
+}
+
+// ExportONNXFile 将已训练的线性模型导出为ONNX文件，格式见ExportONNX
+func (c *Client) ExportONNXFile(modelID, filePath string) error {
+	return exportFile(filePath, func(w io.Writer) error {
+		return c.ExportONNX(modelID, w)
+	})
+}
+
+// ExportONNX 将已训练的线性模型导出为ONNX模型写入w，格式见Client.ExportONNX
+func (mm *ModelManager) ExportONNX(modelID string, w io.Writer) error {
+	return exportModel(mm.internalManager, modelID, "ONNX", func(model models.Model) error {
+		return export.ONNX(model, w)
+	})
+}
+
+// ExportPMML 将已训练的模型导出为PMML 4.4的RegressionModel写入w，供企业评分引擎等PMML消费者部署。
+// featureNames为输入字段名，需与训练时的特征列一一对应，为nil时依次为x1、x2…；目标字段名为targetName，为空时为y。
+// 支持OLS、Ridge、Lasso、RidgeCV、LassoCV、PLS、Polynomial、Exponential、Logarithmic和Power回归模型，
+// 以及Logistic二分类模型（类别0和1，输出字段probability_0、probability_1和predicted_<目标字段名>）。
+// 系数以完整的float64精度写出
+func (c *Client) ExportPMML(modelID string, featureNames []string, targetName string, w io.Writer) error {
+	return exportModel(c.manager, modelID, "PMML", func(model models.Model) error {
+		return export.PMML(model, featureNames, targetName, w)
+	})
+}
+
+// ExportPMMLFile 将已训练的模型导出为PMML文件，参数见ExportPMML
+func (c *Client) ExportPMMLFile(modelID string, featureNames []string, targetName, filePath string) error {
+	return exportFile(filePath, func(w io.Writer) error {
+		return c.ExportPMML(modelID, featureNames, targetName, w)
+	})
+}
+
+// ExportPMML 将已训练的模型导出为PMML 4.4文档写入w，参数见Client.ExportPMML
+func (mm *ModelManager) ExportPMML(modelID string, featureNames []string, targetName string, w io.Writer) error {
+	return exportModel(mm.internalManager, modelID, "PMML", func(model models.Model) error {
+		return export.PMML(model, featureNames, targetName, w)
+	})
+}
+
+// ExportGoSource 为已训练的模型生成只依赖标准库的Go源文件写入w，其中包含拟合得到的系数和预测函数，
+// 其他服务复制该文件即可预测而无需引入本库。生成的包名为packageName（为空时为model），
+// 导出NumFeatures和Predict(x []float64) float64；Logistic的Predict返回正类概率，另有PredictClass返回0或1。
+// 支持的模型与ExportPMML相同，系数以完整的float64精度写出，PLS以外的模型预测结果与Predict完全一致
+func (c *Client) ExportGoSource(modelID, packageName string, w io.Writer) error {
+	return exportModel(c.manager, modelID, "Go source", func(model models.Model) error {
+		return export.GoSource(model, packageName, w)
+	})
+}
+
+// ExportGoSourceFile 为已训练的模型生成Go源文件，参数见ExportGoSource
+func (c *Client) ExportGoSourceFile(modelID, packageName, filePath string) error {
+	return exportFile(filePath, func(w io.Writer) error {
+		return c.ExportGoSource(modelID, packageName, w)
+	})
+}
+
+// ExportGoSource 为已训练的模型生成只依赖标准库的Go源文件写入w，参数见Client.ExportGoSource
+func (mm *ModelManager) ExportGoSource(modelID, packageName string, w io.Writer) error {
+	return exportModel(mm.internalManager, modelID, "Go source", func(model models.Model) error {
+		return export.GoSource(model, packageName, w)
+	})
+}
+
+// exportModel 查找模型并调用导出函数，将错误包装为*Error
+func exportModel(manager *models.ModelManager, modelID, format string, write func(models.Model) error) error {
+	model, err := manager.GetModel(modelID)
+	if err != nil {
+		return &Error{
+			Code:    ErrModelNotTrained,
+			Message: "model not found",
+			Details: err.Error(),
+		}
+	}
+	if err := write(model); err != nil {
+		code := ErrInvalidParameters
+		switch {
+		case errors.Is(err, export.ErrUnsupportedModel):
+			code = ErrInvalidAlgorithm
+		case errors.Is(err, export.ErrNotTrained):
+			code = ErrModelNotTrained
+		}
+		return &Error{
+			Code:    code,
+			Message: fmt.Sprintf("failed to export %s model as %s", model.GetModelType(), format),
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// exportFile 创建文件并写入导出结果，写入失败时删除不完整的文件
+func exportFile(filePath string, write func(io.Writer) error) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to create export file",
+			Details: err.Error(),
+		}
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(filePath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(filePath)
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to write export file",
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
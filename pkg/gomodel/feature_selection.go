@@ -0,0 +1,470 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// RFEConfig 递归特征消除的配置
+type RFEConfig struct {
+	Step        int               `json:"step"`         // 每轮剔除的特征数，默认1
+	MinFeatures int               `json:"min_features"` // 保留的最少特征数，默认1
+	Validation  *ValidationConfig `json:"validation"`   // 评估每个特征子集的验证配置，为nil时使用客户端默认配置
+}
+
+// RFEStep 消除过程中一个特征子集的评估结果
+type RFEStep struct {
+	NFeatures int       `json:"n_features"`
+	Features  []string  `json:"features"`
+	Scores    []float64 `json:"scores"`
+	MeanScore float64   `json:"mean_score"`
+	StdScore  float64   `json:"std_score"`
+}
+
+// RFEResult 递归特征消除结果
+type RFEResult struct {
+	Ranking          []int      `json:"ranking"`           // 各特征的排名，最优子集中的特征为1，越早被剔除排名越大
+	Support          []bool     `json:"support"`           // 各特征是否属于最优子集
+	SelectedFeatures []string   `json:"selected_features"` // 最优子集的特征名
+	NFeatures        int        `json:"n_features"`
+	BestScore        float64    `json:"best_score"`
+	Steps            []*RFEStep `json:"steps"` // 按特征数从多到少排列
+}
+
+// RFE 递归特征消除：在当前特征子集上交叉验证并拟合模型，剔除系数绝对值最小的特征，
+// 重复直到剩余MinFeatures个特征，最后选出平均得分最高的子集（得分相同时取特征更少者）。
+// 模型必须在参数中提供coefficients（如ols、ridge、lasso、logistic）；
+// 系数大小受特征量纲影响，建议先标准化特征
+func (c *Client) RFE(data *TrainingData, config *ModelConfig, rfeConfig *RFEConfig) (*RFEResult, error) {
+	if err := c.validateSearchInput(data, config); err != nil {
+		return nil, err
+	}
+	if rfeConfig == nil {
+		rfeConfig = &RFEConfig{}
+	}
+	step := rfeConfig.Step
+	if step <= 0 {
+		step = 1
+	}
+	minFeatures := rfeConfig.MinFeatures
+	if minFeatures <= 0 {
+		minFeatures = 1
+	}
+	_, p := data.Features.Dims()
+	if minFeatures > p {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("min_features (%d) exceeds the number of features (%d)", minFeatures, p),
+		}
+	}
+	validation := c.searchValidation(rfeConfig.Validation)
+	names := columnNames(data)
+
+	active := make([]int, p)
+	for j := range active {
+		active[j] = j
+	}
+	// eliminated[k] 为第k轮剔除的特征
+	var eliminated [][]int
+	var steps []*RFEStep
+	for {
+		subset := selectColumns(data, active, names)
+		scores, err := c.validationScores(subset, config, validation)
+		if err != nil {
+			return nil, &Error{
+				Code:    ErrValidationFailed,
+				Message: fmt.Sprintf("failed to evaluate subset of %d features", len(active)),
+				Details: err.Error(),
+			}
+		}
+		mean, std := c.calculateStats(scores)
+		steps = append(steps, &RFEStep{
+			NFeatures: len(active),
+			Features:  subset.FeatureNames,
+			Scores:    scores,
+			MeanScore: mean,
+			StdScore:  std,
+		})
+		if len(active) == minFeatures {
+			break
+		}
+
+		importances, err := c.featureImportances(subset, config)
+		if err != nil {
+			return nil, err
+		}
+		order := make([]int, len(active))
+		for k := range order {
+			order[k] = k
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return importances[order[a]] < importances[order[b]]
+		})
+
+		drop := step
+		if len(active)-drop < minFeatures {
+			drop = len(active) - minFeatures
+		}
+		removed := make(map[int]bool, drop)
+		dropped := make([]int, 0, drop)
+		for _, k := range order[:drop] {
+			removed[k] = true
+			dropped = append(dropped, active[k])
+		}
+		eliminated = append(eliminated, dropped)
+
+		remaining := make([]int, 0, len(active)-drop)
+		for k, j := range active {
+			if !removed[k] {
+				remaining = append(remaining, j)
+			}
+		}
+		active = remaining
+	}
+
+	// 选出得分最高的子集，得分相同时优先特征更少的子集
+	best := 0
+	for k, s := range steps {
+		if s.MeanScore >= steps[best].MeanScore {
+			best = k
+		}
+	}
+
+	// 最优子集之前被剔除的特征，越晚剔除排名越靠前
+	ranking := make([]int, p)
+	support := make([]bool, p)
+	for j := range ranking {
+		ranking[j] = 1
+		support[j] = true
+	}
+	for k := 0; k < best; k++ {
+		for _, j := range eliminated[k] {
+			ranking[j] = best - k + 1
+			support[j] = false
+		}
+	}
+
+	return &RFEResult{
+		Ranking:          ranking,
+		Support:          support,
+		SelectedFeatures: steps[best].Features,
+		NFeatures:        steps[best].NFeatures,
+		BestScore:        steps[best].MeanScore,
+		Steps:            steps,
+	}, nil
+}
+
+// featureImportances 在全部数据上拟合模型，返回各特征系数的绝对值
+func (c *Client) featureImportances(data *TrainingData, config *ModelConfig) ([]float64, error) {
+	model, err := c.manager.CreateModel(c.internalConfig(config))
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to create model",
+			Details: err.Error(),
+		}
+	}
+	if err := model.FitWeighted(data.Features, data.Target, data.Weights); err != nil {
+		return nil, &Error{
+			Code:    ErrTrainingFailed,
+			Message: "failed to train model",
+			Details: err.Error(),
+		}
+	}
+
+	_, p := data.Features.Dims()
+	coefficients, ok := model.GetParameters()["coefficients"].([]float64)
+	if !ok || len(coefficients) != p {
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("algorithm %s does not provide per-feature coefficients", config.Algorithm),
+		}
+	}
+
+	importances := make([]float64, p)
+	for j, coef := range coefficients {
+		importances[j] = math.Abs(coef)
+	}
+	return importances, nil
+}
+
+// 单变量特征评分函数
+const (
+	ScoreFRegression = "f_regression" // 单变量线性回归的F统计量，适用于连续目标
+	ScoreFClassif    = "f_classif"    // 方差分析(ANOVA)的F统计量，适用于分类目标
+	ScoreMutualInfo  = "mutual_info"  // 离散化后估计的互信息（单位nat），可捕捉非线性关系
+	ScoreCorrelation = "correlation"  // 与目标的皮尔逊相关系数的绝对值
+)
+
+// mutualInfoBins 估计互信息时连续变量的等频分箱数
+const mutualInfoBins = 10
+
+// SelectKBest 单变量特征选择：按评分函数对每个特征与目标的关系单独打分，保留得分最高的K个特征，
+// 可作为流水线步骤。选择只在Fit时根据训练数据确定，Transform时按相同的列提取特征
+type SelectKBest struct {
+	ScoreFunc string    `json:"score_func"`
+	K         int       `json:"k"`                  // 保留的特征数，不小于特征总数时保留全部特征
+	Scores    []float64 `json:"scores"`             // 各特征的得分
+	PValues   []float64 `json:"p_values,omitempty"` // F检验的p值，仅f_regression和f_classif提供
+	selected  []int
+	names     []string
+	nFeatures int
+}
+
+// NewSelectKBest 创建新的单变量特征选择器
+func NewSelectKBest(scoreFunc string, k int) (*SelectKBest, error) {
+	switch scoreFunc {
+	case ScoreFRegression, ScoreFClassif, ScoreMutualInfo, ScoreCorrelation:
+	default:
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported score function: %s", scoreFunc),
+		}
+	}
+	if k < 1 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "k must be at least 1",
+		}
+	}
+	return &SelectKBest{ScoreFunc: scoreFunc, K: k}, nil
+}
+
+// Fit 计算各特征的得分并选出得分最高的K个特征
+func (s *SelectKBest) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	r, c := d.Features.Dims()
+	if d.Target.Len() != r {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("features have %d rows but target has %d", r, d.Target.Len()),
+		}
+	}
+	if r < 3 {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "at least 3 samples are required for feature scoring",
+		}
+	}
+
+	y := mat.Col(nil, 0, d.Target)
+	s.Scores = make([]float64, c)
+	s.PValues = nil
+	if s.ScoreFunc == ScoreFRegression || s.ScoreFunc == ScoreFClassif {
+		s.PValues = make([]float64, c)
+	}
+	for j := 0; j < c; j++ {
+		x := mat.Col(nil, j, d.Features)
+		switch s.ScoreFunc {
+		case ScoreFRegression:
+			s.Scores[j], s.PValues[j] = fRegression(x, y)
+		case ScoreFClassif:
+			s.Scores[j], s.PValues[j] = fClassif(x, y)
+		case ScoreMutualInfo:
+			s.Scores[j] = mutualInformation(x, y)
+		case ScoreCorrelation:
+			s.Scores[j] = math.Abs(pearson(x, y))
+		default:
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: fmt.Sprintf("unsupported score function: %s", s.ScoreFunc),
+			}
+		}
+	}
+
+	order := make([]int, c)
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return s.Scores[order[a]] > s.Scores[order[b]]
+	})
+	k := s.K
+	if k > c {
+		k = c
+	}
+	s.selected = append([]int(nil), order[:k]...)
+	sort.Ints(s.selected)
+	s.names = columnNames(d)
+	s.nFeatures = c
+	return nil
+}
+
+// Transform 提取Fit时选出的特征列
+func (s *SelectKBest) Transform(d *TrainingData) (*TrainingData, error) {
+	if s.nFeatures == 0 {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	if _, c := d.Features.Dims(); c != s.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", s.nFeatures, c),
+		}
+	}
+	return selectColumns(d, s.selected, s.names), nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (s *SelectKBest) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(s, d)
+}
+
+// Support 返回各特征是否被选中
+func (s *SelectKBest) Support() []bool {
+	support := make([]bool, s.nFeatures)
+	for _, j := range s.selected {
+		support[j] = true
+	}
+	return support
+}
+
+// SelectedFeatures 返回被选中的特征名
+func (s *SelectKBest) SelectedFeatures() []string {
+	names := make([]string, len(s.selected))
+	for k, j := range s.selected {
+		names[k] = s.names[j]
+	}
+	return names
+}
+
+// pearson 计算皮尔逊相关系数，任一变量为常数时返回0
+func pearson(x, y []float64) float64 {
+	n := float64(len(x))
+	var meanX, meanY float64
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// fRegression 单变量回归的F统计量 F = r²/(1-r²)·(n-2) 及其p值
+func fRegression(x, y []float64) (float64, float64) {
+	r := pearson(x, y)
+	dof := float64(len(x) - 2)
+	if r*r >= 1 {
+		return math.Inf(1), 0
+	}
+	f := r * r / (1 - r*r) * dof
+	return f, distuv.F{D1: 1, D2: dof}.Survival(f)
+}
+
+// fClassif 以目标取值为分组的单因素方差分析F统计量及其p值，只有一个类别时得分为0
+func fClassif(x, y []float64) (float64, float64) {
+	sums := make(map[float64]float64)
+	counts := make(map[float64]float64)
+	total := 0.0
+	for i := range x {
+		sums[y[i]] += x[i]
+		counts[y[i]]++
+		total += x[i]
+	}
+	n := float64(len(x))
+	k := float64(len(counts))
+	if k < 2 || n <= k {
+		return 0, 1
+	}
+	grandMean := total / n
+
+	var between, within float64
+	for label, sum := range sums {
+		mean := sum / counts[label]
+		between += counts[label] * (mean - grandMean) * (mean - grandMean)
+	}
+	for i := range x {
+		mean := sums[y[i]] / counts[y[i]]
+		within += (x[i] - mean) * (x[i] - mean)
+	}
+	if between == 0 {
+		return 0, 1
+	}
+	if within == 0 {
+		return math.Inf(1), 0
+	}
+
+	f := (between / (k - 1)) / (within / (n - k))
+	return f, distuv.F{D1: k - 1, D2: n - k}.Survival(f)
+}
+
+// mutualInformation 将变量离散化后按联合频率估计互信息；
+// 取值个数不超过mutualInfoBins的变量视为离散变量直接使用，否则按等频分箱
+func mutualInformation(x, y []float64) float64 {
+	xBins := discretize(x, mutualInfoBins)
+	yBins := discretize(y, mutualInfoBins)
+	n := float64(len(x))
+
+	joint := make(map[[2]int]float64)
+	px := make(map[int]float64)
+	py := make(map[int]float64)
+	for i := range xBins {
+		joint[[2]int{xBins[i], yBins[i]}]++
+		px[xBins[i]]++
+		py[yBins[i]]++
+	}
+
+	mi := 0.0
+	for key, count := range joint {
+		pxy := count / n
+		mi += pxy * math.Log(pxy/(px[key[0]]/n*py[key[1]]/n))
+	}
+	return math.Max(mi, 0)
+}
+
+// discretize 将变量映射为离散编号；相同取值总是落在同一个箱中
+func discretize(values []float64, bins int) []int {
+	codes := make([]int, len(values))
+	distinct := make(map[float64]int)
+	for _, v := range values {
+		if _, ok := distinct[v]; !ok {
+			distinct[v] = len(distinct)
+		}
+	}
+	if len(distinct) <= bins {
+		for i, v := range values {
+			codes[i] = distinct[v]
+		}
+		return codes
+	}
+
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return values[order[a]] < values[order[b]]
+	})
+	n := len(values)
+	for rank, i := range order {
+		if rank > 0 && values[i] == values[order[rank-1]] {
+			codes[i] = codes[order[rank-1]]
+			continue
+		}
+		codes[i] = rank * bins / n
+	}
+	return codes
+}
@@ -0,0 +1,95 @@
+package gomodel
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"math"
+	"sort"
+)
+
+// fingerprintVersion 指纹格式的版本，格式变化时递增，使旧指纹不会与新格式的指纹混淆
+const fingerprintVersion = "gomodel-data-v1"
+
+// Fingerprint 返回训练数据内容的SHA-256十六进制摘要，覆盖形状、特征、目标变量、样本权重、列名、目标名和类别表。
+// 摘要与内存布局无关：内容相同的数据总是得到相同的指纹，任一取值改变指纹也会改变；
+// 所有NaN视为同一个值，-0与0视为相同。数据为nil时返回空字符串
+func (d *TrainingData) Fingerprint() string {
+	if d == nil || d.Features == nil {
+		return ""
+	}
+	h := sha256.New()
+	writeString(h, fingerprintVersion)
+
+	r, c := d.Features.Dims()
+	writeInt(h, r)
+	writeInt(h, c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			writeFloat(h, d.Features.At(i, j))
+		}
+	}
+	if d.Target == nil {
+		writeInt(h, -1)
+	} else {
+		writeInt(h, d.Target.Len())
+		for i := 0; i < d.Target.Len(); i++ {
+			writeFloat(h, d.Target.AtVec(i))
+		}
+	}
+	if d.Weights == nil {
+		writeInt(h, -1)
+	} else {
+		writeInt(h, d.Weights.Len())
+		for i := 0; i < d.Weights.Len(); i++ {
+			writeFloat(h, d.Weights.AtVec(i))
+		}
+	}
+
+	writeInt(h, len(d.FeatureNames))
+	for _, name := range d.FeatureNames {
+		writeString(h, name)
+	}
+	writeString(h, d.TargetName)
+	names := make([]string, 0, len(d.Categories))
+	for name := range d.Categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	writeInt(h, len(names))
+	for _, name := range names {
+		writeString(h, name)
+		writeInt(h, len(d.Categories[name]))
+		for _, label := range d.Categories[name] {
+			writeString(h, label)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeInt 以定长小端序写入整数
+func writeInt(h hash.Hash, v int) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(int64(v)))
+	h.Write(buf[:])
+}
+
+// writeFloat 写入浮点数的位模式，NaN统一为同一位模式，-0写为0
+func writeFloat(h hash.Hash, v float64) {
+	bits := math.Float64bits(v)
+	if math.IsNaN(v) {
+		bits = math.Float64bits(math.NaN())
+	} else if v == 0 {
+		bits = 0
+	}
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], bits)
+	h.Write(buf[:])
+}
+
+// writeString 写入带长度前缀的字符串，避免相邻字符串拼接产生歧义
+func writeString(h hash.Hash, s string) {
+	writeInt(h, len(s))
+	h.Write([]byte(s))
+}
@@ -0,0 +1,128 @@
+package gomodel
+
+import (
+	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/data"
+	"github.com/feiyuluoye/Go-Model/internal/types"
+)
+
+// Frame 列式存储的类型化数据集，各列保留原始类型，支持按列访问和Select/Drop/Rename，
+// 建模前通过DataUtils.FrameToTrainingData转换为TrainingData
+type Frame = types.Frame
+
+// Column Frame中的类型化数据列
+type Column = types.Column
+
+// ColumnType 列的数据类型
+type ColumnType = types.ColumnType
+
+// 支持的列类型
+const (
+	FloatColumn  = types.FloatColumn
+	IntColumn    = types.IntColumn
+	StringColumn = types.StringColumn
+	BoolColumn   = types.BoolColumn
+	TimeColumn   = types.TimeColumn
+)
+
+// NewFrame 由等长且名称唯一的列创建数据集
+func NewFrame(columns ...*Column) (*Frame, error) {
+	frame, err := types.NewFrame(columns...)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to create frame",
+			Details: err.Error(),
+		}
+	}
+	return frame, nil
+}
+
+// NewFloatColumn 创建浮点列
+func NewFloatColumn(name string, values []float64) *Column {
+	return types.NewFloatColumn(name, values)
+}
+
+// NewIntColumn 创建整数列
+func NewIntColumn(name string, values []int64) *Column {
+	return types.NewIntColumn(name, values)
+}
+
+// NewStringColumn 创建字符串列
+func NewStringColumn(name string, values []string) *Column {
+	return types.NewStringColumn(name, values)
+}
+
+// NewBoolColumn 创建布尔列
+func NewBoolColumn(name string, values []bool) *Column {
+	return types.NewBoolColumn(name, values)
+}
+
+// NewTimeColumn 创建时间列
+func NewTimeColumn(name string, values []time.Time) *Column {
+	return types.NewTimeColumn(name, values)
+}
+
+// LoadFrameFromCSV 从CSV文件加载类型化的列式数据集，逐列推断整数、浮点、布尔、时间或字符串类型
+func (du *DataUtils) LoadFrameFromCSV(filePath string, hasHeader bool) (*Frame, error) {
+	frame, err := data.LoadCSVFrame(filePath, hasHeader)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load CSV data",
+			Details: err.Error(),
+		}
+	}
+	return frame, nil
+}
+
+// LoadFrameFromParquet 从Parquet文件加载类型化的列式数据集，保留整数、浮点、布尔、字符串和时间戳列的类型，空值记为缺失
+func (du *DataUtils) LoadFrameFromParquet(filePath string) (*Frame, error) {
+	frame, err := data.LoadParquetFrame(filePath)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to load Parquet data",
+			Details: err.Error(),
+		}
+	}
+	return frame, nil
+}
+
+// SaveFrameToParquet 将Frame保存为Parquet文件，各列按原始类型写出（INT64、DOUBLE、BOOLEAN、UTF8字符串、TIMESTAMP_MICROS），缺失值写为空值
+func (du *DataUtils) SaveFrameToParquet(frame *Frame, filePath string) error {
+	if err := data.SaveParquetFrame(frame, filePath); err != nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to save Parquet data",
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// FrameToTrainingData 以targetColumn为目标变量、其余列为特征将Frame转换为训练数据。
+// 字符串列编码为类别码并记录在Categories中；布尔值转换为0/1，时间转换为Unix秒，缺失值记为NaN
+func (du *DataUtils) FrameToTrainingData(frame *Frame, targetColumn string) (*TrainingData, error) {
+	if frame == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "frame cannot be nil",
+		}
+	}
+	dataset, categories, err := frame.ToDataset(targetColumn)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to convert frame",
+			Details: err.Error(),
+		}
+	}
+
+	trainingData := du.convertToTrainingData(dataset)
+	if len(categories) > 0 {
+		trainingData.Categories = categories
+	}
+	return trainingData, nil
+}
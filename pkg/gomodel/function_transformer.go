@@ -0,0 +1,198 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 预定义的函数变换
+const (
+	FunctionLog1p      = "log1p"      // log(1+x)，逆变换为exp(x)-1
+	FunctionSqrt       = "sqrt"       // 平方根，逆变换为平方
+	FunctionReciprocal = "reciprocal" // 倒数，逆变换为倒数
+)
+
+// FunctionTransformer 对特征逐元素应用固定函数的无参数变换器，可作为流水线步骤。
+// 也实现了TargetTransformer，设置为Pipeline.TargetTransformer时预测值通过Inverse自动还原为原始尺度。
+// 有限的输入经变换得到NaN或无穷大（如负数开方、0取倒数）时返回错误，NaN保持不变
+type FunctionTransformer struct {
+	Name      string                `json:"name"`    // 预定义函数名；使用自定义函数时仅作描述
+	Columns   []string              `json:"columns"` // 要变换的列，为空时变换Categories中未记录的全部数值特征
+	Func      func(float64) float64 `json:"-"`       // 变换函数，为nil时按Name使用预定义函数
+	Inverse   func(float64) float64 `json:"-"`       // 逆变换函数，为nil时不支持逆变换
+	columns   []int
+	names     []string
+	nFeatures int
+	fitted    bool
+}
+
+// NewFunctionTransformer 创建使用预定义函数（log1p、sqrt、reciprocal）的变换器，columns为空时变换全部数值特征
+func NewFunctionTransformer(name string, columns ...string) (*FunctionTransformer, error) {
+	ft := &FunctionTransformer{Name: name, Columns: columns}
+	if err := ft.resolve(); err != nil {
+		return nil, err
+	}
+	return ft, nil
+}
+
+// NewCustomFunctionTransformer 创建使用自定义函数的变换器，inverse可以为nil
+func NewCustomFunctionTransformer(name string, fn, inverse func(float64) float64, columns ...string) *FunctionTransformer {
+	return &FunctionTransformer{Name: name, Columns: columns, Func: fn, Inverse: inverse}
+}
+
+// Fit 确定要变换的列，变换本身不需要学习参数
+func (ft *FunctionTransformer) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if err := ft.resolve(); err != nil {
+		return err
+	}
+
+	names := columnNames(d)
+	columns, err := numericColumns(d, names, ft.Columns)
+	if err != nil {
+		return err
+	}
+	ft.columns = columns
+	ft.names = names
+	_, ft.nFeatures = d.Features.Dims()
+	ft.fitted = true
+	return nil
+}
+
+// Transform 对选定的列应用变换函数
+func (ft *FunctionTransformer) Transform(d *TrainingData) (*TrainingData, error) {
+	return ft.apply(d, ft.Func)
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (ft *FunctionTransformer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(ft, d)
+}
+
+// InverseTransform 对选定的列应用逆变换函数
+func (ft *FunctionTransformer) InverseTransform(d *TrainingData) (*TrainingData, error) {
+	if ft.Inverse == nil {
+		return nil, ft.noInverse()
+	}
+	return ft.apply(d, ft.Inverse)
+}
+
+// FitTarget 检查变换函数，目标变量变换不需要学习参数
+func (ft *FunctionTransformer) FitTarget(y []float64) error {
+	return ft.resolve()
+}
+
+// TransformTarget 对目标变量应用变换函数
+func (ft *FunctionTransformer) TransformTarget(y []float64) ([]float64, error) {
+	if err := ft.resolve(); err != nil {
+		return nil, err
+	}
+	return ft.applyValues(y, ft.Func, "target")
+}
+
+// InverseTransformTarget 对变换尺度上的值应用逆变换函数
+func (ft *FunctionTransformer) InverseTransformTarget(y []float64) ([]float64, error) {
+	if err := ft.resolve(); err != nil {
+		return nil, err
+	}
+	if ft.Inverse == nil {
+		return nil, ft.noInverse()
+	}
+	return ft.applyValues(y, ft.Inverse, "target")
+}
+
+// apply 对选定的列逐元素应用fn
+func (ft *FunctionTransformer) apply(d *TrainingData, fn func(float64) float64) (*TrainingData, error) {
+	if !ft.fitted {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != ft.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", ft.nFeatures, c),
+		}
+	}
+
+	features := mat.DenseCopyOf(d.Features)
+	values := make([]float64, r)
+	for _, j := range ft.columns {
+		for i := range values {
+			values[i] = features.At(i, j)
+		}
+		transformed, err := ft.applyValues(values, fn, fmt.Sprintf("feature %q", ft.names[j]))
+		if err != nil {
+			return nil, err
+		}
+		features.SetCol(j, transformed)
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   d.Categories,
+	}, nil
+}
+
+// applyValues 逐元素应用fn，有限的输入得到非有限的结果时返回错误
+func (ft *FunctionTransformer) applyValues(values []float64, fn func(float64) float64, what string) ([]float64, error) {
+	result := make([]float64, len(values))
+	for i, v := range values {
+		if math.IsNaN(v) {
+			result[i] = v
+			continue
+		}
+		t := fn(v)
+		if !math.IsInf(v, 0) && (math.IsNaN(t) || math.IsInf(t, 0)) {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("function %q is undefined for %s value %g at row %d", ft.Name, what, v, i),
+			}
+		}
+		result[i] = t
+	}
+	return result, nil
+}
+
+// resolve 未设置Func时按Name选择预定义函数及其逆函数
+func (ft *FunctionTransformer) resolve() error {
+	if ft.Func != nil {
+		return nil
+	}
+	switch ft.Name {
+	case FunctionLog1p:
+		ft.Func, ft.Inverse = math.Log1p, math.Expm1
+	case FunctionSqrt:
+		ft.Func, ft.Inverse = math.Sqrt, func(x float64) float64 { return x * x }
+	case FunctionReciprocal:
+		reciprocal := func(x float64) float64 { return 1 / x }
+		ft.Func, ft.Inverse = reciprocal, reciprocal
+	default:
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported function %q; use NewCustomFunctionTransformer for custom functions", ft.Name),
+		}
+	}
+	return nil
+}
+
+// noInverse 未提供逆变换函数时的错误
+func (ft *FunctionTransformer) noInverse() error {
+	return &Error{
+		Code:    ErrInvalidParameters,
+		Message: fmt.Sprintf("function %q has no inverse", ft.Name),
+	}
+}
@@ -51,7 +51,15 @@ func GetDefaultConfig(algorithm AlgorithmType) *ModelConfig {
 		config.Parameters["lambda"] = 1.0
 		config.Parameters["max_iterations"] = 1000
 		config.Parameters["tolerance"] = 1e-6
+	case RidgeCV:
+		config.Parameters["cv_folds"] = 5
+	case LassoCV:
+		config.Parameters["cv_folds"] = 5
+		config.Parameters["num_lambdas"] = 50
+		config.Parameters["max_iterations"] = 1000
+		config.Parameters["tolerance"] = 1e-4
 	case Logistic:
+		config.Parameters["solver"] = "lbfgs"
 		config.Parameters["learning_rate"] = 0.01
 		config.Parameters["max_iterations"] = 1000
 		config.Parameters["tolerance"] = 1e-6
@@ -69,6 +77,23 @@ func GetDefaultConfig(algorithm AlgorithmType) *ModelConfig {
 	case Power:
 		config.Parameters["max_iterations"] = 1000
 		config.Parameters["tolerance"] = 1e-6
+	case Stacking:
+		config.Parameters["base_models"] = []*ModelConfig{GetDefaultConfig(Ridge), GetDefaultConfig(Lasso)}
+		config.Parameters["meta_model"] = GetDefaultConfig(Ridge)
+		config.Parameters["cv_folds"] = 5
+	case Voting:
+		config.Parameters["models"] = []*ModelConfig{GetDefaultConfig(Ridge), GetDefaultConfig(Lasso)}
+		config.Parameters["voting"] = "average"
+	case Bagging:
+		config.Parameters["base_model"] = GetDefaultConfig(OLS)
+		config.Parameters["n_estimators"] = 10
+		config.Parameters["max_samples"] = 1.0
+		config.Parameters["max_features"] = 1.0
+	case Calibrated:
+		config.Parameters["base_model"] = GetDefaultConfig(Logistic)
+		config.Parameters["method"] = "sigmoid"
+		config.Parameters["calibration_size"] = 0.2
+		config.LossFunction = Accuracy
 	}
 
 	return config
@@ -150,8 +175,8 @@ func QuickPredict(trainFeatures [][]float64, trainTarget []float64, testFeatures
 // ValidateAlgorithm checks if an algorithm is supported
 func ValidateAlgorithm(algorithm AlgorithmType) error {
 	supportedAlgorithms := []AlgorithmType{
-		OLS, Ridge, Lasso, Logistic, PLS,
-		Polynomial, Exponential, Logarithmic, Power,
+		OLS, Ridge, Lasso, RidgeCV, LassoCV, Logistic, PLS,
+		Polynomial, Exponential, Logarithmic, Power, Stacking, Voting, Bagging, Calibrated,
 	}
 	
 	for _, supported := range supportedAlgorithms {
@@ -191,10 +216,20 @@ func GetAlgorithmInfo(algorithm AlgorithmType) map[string]interface{} {
 		info["description"] = "Lasso regression with L1 regularization"
 		info["parameters"] = []string{"lambda", "max_iterations", "tolerance"}
 		
+	case RidgeCV:
+		info["type"] = "linear_regression"
+		info["description"] = "Ridge regression with lambda selected by k-fold cross-validation"
+		info["parameters"] = []string{"lambdas", "cv_folds", "random_seed"}
+		
+	case LassoCV:
+		info["type"] = "linear_regression"
+		info["description"] = "Lasso regression with lambda selected by k-fold cross-validation"
+		info["parameters"] = []string{"lambdas", "num_lambdas", "cv_folds", "random_seed", "max_iterations", "tolerance"}
+		
 	case Logistic:
 		info["type"] = "classification"
 		info["description"] = "Logistic regression for binary classification"
-		info["parameters"] = []string{"learning_rate", "max_iterations", "tolerance"}
+		info["parameters"] = []string{"learning_rate", "max_iterations", "tolerance", "class_weight", "solver"}
 		
 	case PLS:
 		info["type"] = "linear_regression"
@@ -220,6 +255,26 @@ func GetAlgorithmInfo(algorithm AlgorithmType) map[string]interface{} {
 		info["type"] = "nonlinear_regression"
 		info["description"] = "Power regression"
 		info["parameters"] = []string{"max_iterations", "tolerance"}
+		
+	case Stacking:
+		info["type"] = "ensemble"
+		info["description"] = "Stacking ensemble with a meta-model trained on out-of-fold base predictions"
+		info["parameters"] = []string{"base_models", "meta_model", "cv_folds", "random_seed", "passthrough"}
+		
+	case Voting:
+		info["type"] = "ensemble"
+		info["description"] = "Weighted averaging (regression) or majority voting (classification) ensemble"
+		info["parameters"] = []string{"models", "weights", "voting"}
+		
+	case Bagging:
+		info["type"] = "ensemble"
+		info["description"] = "Bootstrap aggregating of a base model with out-of-bag error estimation"
+		info["parameters"] = []string{"base_model", "n_estimators", "max_samples", "max_features", "bootstrap_features", "random_seed", "n_jobs"}
+		
+	case Calibrated:
+		info["type"] = "classification"
+		info["description"] = "Probability calibration of a classifier with Platt scaling or isotonic regression on held-out data"
+		info["parameters"] = []string{"base_model", "method", "calibration_size", "random_seed"}
 	}
 	
 	return info
@@ -228,8 +283,8 @@ func GetAlgorithmInfo(algorithm AlgorithmType) map[string]interface{} {
 // GetAllAlgorithmsInfo returns information about all supported algorithms
 func GetAllAlgorithmsInfo() map[AlgorithmType]map[string]interface{} {
 	algorithms := []AlgorithmType{
-		OLS, Ridge, Lasso, Logistic, PLS,
-		Polynomial, Exponential, Logarithmic, Power,
+		OLS, Ridge, Lasso, RidgeCV, LassoCV, Logistic, PLS,
+		Polynomial, Exponential, Logarithmic, Power, Stacking, Voting, Bagging, Calibrated,
 	}
 	
 	info := make(map[AlgorithmType]map[string]interface{})
@@ -0,0 +1,225 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// HalvingConfig 逐次减半搜索的配置，资源指参与评估的训练样本数
+type HalvingConfig struct {
+	Factor       int               `json:"factor"`        // 每轮保留1/Factor的候选，同时资源扩大Factor倍，默认3
+	MinResources int               `json:"min_resources"` // 第一轮使用的样本数，为0时根据候选数量和Factor自动确定
+	MaxResources int               `json:"max_resources"` // 最后一轮使用的样本数，为0时使用全部样本
+	Validation   *ValidationConfig `json:"validation"`    // 每轮评估使用的验证配置，为nil时使用客户端默认配置
+}
+
+// HalvingRound 逐次减半中一轮的评估结果
+type HalvingRound struct {
+	Bracket   int             `json:"bracket"` // Hyperband的分组编号，逐次减半搜索时为0
+	Round     int             `json:"round"`
+	Resources int             `json:"resources"`
+	Results   []*SearchResult `json:"results"` // 本轮所有候选的结果，按得分排名
+}
+
+// HalvingResult 逐次减半/Hyperband搜索结果，TuningResult中的排名只包含在最大资源上评估过的候选
+type HalvingResult struct {
+	*TuningResult
+	Rounds      []*HalvingRound `json:"rounds"`
+	Evaluations int             `json:"evaluations"` // 候选评估的总次数
+}
+
+// HalvingGridSearch 对参数网格中的全部组合执行逐次减半搜索：先在少量样本上评估所有候选，
+// 每轮只保留得分最高的1/Factor并将样本数扩大Factor倍，直到在全部样本上评估剩余候选。
+// 与GridSearch相比，大部分候选只在小样本上被评估，适合大数据集
+func (c *Client) HalvingGridSearch(data *TrainingData, config *ModelConfig, grid ParamGrid, halving *HalvingConfig) (*HalvingResult, error) {
+	if err := c.validateSearchInput(data, config); err != nil {
+		return nil, err
+	}
+	candidates, err := expandGrid(grid)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.runHalving(data, config, halving, func(rng *rand.Rand, minResources, maxResources, factor int) []halvingBracket {
+		return []halvingBracket{{candidates: candidates}}
+	})
+}
+
+// HalvingRandomSearch 从参数分布中采样nCandidates组参数后执行逐次减半搜索
+func (c *Client) HalvingRandomSearch(data *TrainingData, config *ModelConfig, distributions ParamDistributions, nCandidates int, halving *HalvingConfig) (*HalvingResult, error) {
+	if err := c.validateSearchInput(data, config); err != nil {
+		return nil, err
+	}
+	if nCandidates <= 0 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "n_candidates must be positive",
+		}
+	}
+	if err := validateDistributions(distributions); err != nil {
+		return nil, err
+	}
+
+	return c.runHalving(data, config, halving, func(rng *rand.Rand, minResources, maxResources, factor int) []halvingBracket {
+		candidates := make([]map[string]interface{}, nCandidates)
+		for i := range candidates {
+			candidates[i] = sampleDistributions(distributions, rng)
+		}
+		return []halvingBracket{{candidates: candidates}}
+	})
+}
+
+// Hyperband 运行多组(bracket)逐次减半：各组在候选数量与初始资源之间取不同的折中，
+// 从"大量候选、极少样本"到"少量候选、全部样本"，避免单次逐次减半因初始资源过少而误删好的候选。
+// 每组的候选从参数分布中独立采样；MinResources为0时取最大资源的1/Factor³（不少于最小可评估样本数）
+func (c *Client) Hyperband(data *TrainingData, config *ModelConfig, distributions ParamDistributions, halving *HalvingConfig) (*HalvingResult, error) {
+	if err := c.validateSearchInput(data, config); err != nil {
+		return nil, err
+	}
+	if err := validateDistributions(distributions); err != nil {
+		return nil, err
+	}
+
+	return c.runHalving(data, config, halving, func(rng *rand.Rand, minResources, maxResources, factor int) []halvingBracket {
+		if halving == nil || halving.MinResources <= 0 {
+			minResources = max(minResources, maxResources/(factor*factor*factor))
+		}
+
+		// sMax为最激进分组的减半轮数，第s组从maxResources/Factor^s个样本开始
+		sMax := 0
+		for r := minResources * factor; r <= maxResources; r *= factor {
+			sMax++
+		}
+		brackets := make([]halvingBracket, 0, sMax+1)
+		for s := sMax; s >= 0; s-- {
+			n := int(math.Ceil(float64(sMax+1) / float64(s+1) * math.Pow(float64(factor), float64(s))))
+			candidates := make([]map[string]interface{}, n)
+			for i := range candidates {
+				candidates[i] = sampleDistributions(distributions, rng)
+			}
+			brackets = append(brackets, halvingBracket{
+				candidates: candidates,
+				resources:  maxResources / int(math.Pow(float64(factor), float64(s))),
+			})
+		}
+		return brackets
+	})
+}
+
+// halvingBracket 一组参与逐次减半的候选及其初始资源，resources为0时自动确定
+type halvingBracket struct {
+	candidates []map[string]interface{}
+	resources  int
+}
+
+// runHalving 对propose给出的每组候选执行逐次减半，并汇总在最大资源上评估的结果；
+// propose接收最少可用资源、最大资源和减半因子
+func (c *Client) runHalving(data *TrainingData, config *ModelConfig, halving *HalvingConfig, propose func(rng *rand.Rand, minResources, maxResources, factor int) []halvingBracket) (*HalvingResult, error) {
+	if halving == nil {
+		halving = &HalvingConfig{}
+	}
+	factor := halving.Factor
+	if factor == 0 {
+		factor = 3
+	}
+	if factor < 2 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "halving factor must be at least 2",
+		}
+	}
+	validation := c.searchValidation(halving.Validation)
+
+	n, _ := data.Features.Dims()
+	maxResources := halving.MaxResources
+	if maxResources <= 0 || maxResources > n {
+		maxResources = n
+	}
+	minimum := max(halving.MinResources, minHalvingResources(data, validation))
+	if minimum > maxResources {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("min_resources exceeds max_resources (%d)", maxResources),
+		}
+	}
+
+	// 所有轮次使用同一个随机排列的前r个样本，同一轮内的候选在相同的子集上比较
+	rng := rand.New(rand.NewSource(c.config.RandomSeed))
+	permutation := rng.Perm(n)
+	brackets := propose(rng, minimum, maxResources, factor)
+
+	result := &HalvingResult{}
+	var final []*SearchResult
+	for b, bracket := range brackets {
+		candidates, resources := bracket.candidates, bracket.resources
+		if resources <= 0 && halving.MinResources > 0 {
+			resources = halving.MinResources
+		} else if resources <= 0 {
+			// 初始资源使候选减少到一个时恰好用满maxResources
+			rounds := 0
+			for remaining := len(candidates); remaining > 1; remaining = (remaining + factor - 1) / factor {
+				rounds++
+			}
+			resources = maxResources / int(math.Pow(float64(factor), float64(rounds)))
+		}
+		resources = min(max(resources, minimum), maxResources)
+
+		// 第k轮用满maxResources，中间各轮的资源按Factor的幂从maxResources倒推，避免整除误差累积
+		k := 0
+		for d := 1; maxResources/d > resources; d *= factor {
+			k++
+		}
+		for round := 0; round <= k; round++ {
+			if round > 0 {
+				resources = maxResources / int(math.Pow(float64(factor), float64(k-round)))
+			}
+			subset := subsetTrainingData(data, permutation[:resources])
+
+			results := make([]*SearchResult, 0, len(candidates))
+			for _, candidate := range candidates {
+				r, err := c.evaluateCandidate(subset, config, candidate, validation)
+				if err != nil {
+					return nil, err
+				}
+				results = append(results, r)
+			}
+			result.Evaluations += len(results)
+			ranked := rankResults(config, results).Results
+			result.Rounds = append(result.Rounds, &HalvingRound{
+				Bracket:   b,
+				Round:     round,
+				Resources: resources,
+				Results:   ranked,
+			})
+
+			if round == k {
+				// 复制结果，汇总排名不影响各轮内的排名
+				for _, r := range ranked {
+					copied := *r
+					final = append(final, &copied)
+				}
+				break
+			}
+
+			keep := max(1, (len(ranked)+factor-1)/factor)
+			candidates = make([]map[string]interface{}, keep)
+			for i := range candidates {
+				candidates[i] = ranked[i].Parameters
+			}
+		}
+	}
+
+	result.TuningResult = rankResults(config, final)
+	return result, nil
+}
+
+// minHalvingResources 返回能够完成一次验证的最少样本数
+func minHalvingResources(data *TrainingData, validation *ValidationConfig) int {
+	_, p := data.Features.Dims()
+	minimum := 2 * (p + 1)
+	if validation.Method == "kfold" {
+		minimum = max(minimum, 2*validation.KFolds)
+	}
+	return minimum
+}
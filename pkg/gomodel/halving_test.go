@@ -0,0 +1,180 @@
+package gomodel
+
+import (
+	"reflect"
+	"testing"
+)
+
+// checkHalvingRounds 检查每个分组内相邻两轮：下一轮恰好保留上一轮排名前⌈n/factor⌉的候选，资源扩大factor倍，
+// 最后一轮使用maxResources个样本
+func checkHalvingRounds(t *testing.T, result *HalvingResult, factor, maxResources int) {
+	t.Helper()
+	evaluations := 0
+	for i, round := range result.Rounds {
+		evaluations += len(round.Results)
+		for j, r := range round.Results {
+			if r.Rank != j+1 {
+				t.Errorf("bracket %d round %d: result %d has rank %d", round.Bracket, round.Round, j, r.Rank)
+			}
+		}
+
+		last := i+1 == len(result.Rounds) || result.Rounds[i+1].Bracket != round.Bracket
+		if last {
+			if round.Resources != maxResources {
+				t.Errorf("bracket %d: last round uses %d samples, want %d", round.Bracket, round.Resources, maxResources)
+			}
+			continue
+		}
+
+		next := result.Rounds[i+1]
+		if next.Round != round.Round+1 {
+			t.Errorf("bracket %d: round %d followed by round %d", round.Bracket, round.Round, next.Round)
+		}
+		if next.Resources != round.Resources*factor {
+			t.Errorf("bracket %d round %d: resources %d -> %d, want a factor of %d",
+				round.Bracket, round.Round, round.Resources, next.Resources, factor)
+		}
+		keep := (len(round.Results) + factor - 1) / factor
+		if len(next.Results) != keep {
+			t.Fatalf("bracket %d round %d: kept %d of %d candidates, want %d",
+				round.Bracket, round.Round, len(next.Results), len(round.Results), keep)
+		}
+		promoted := make([]float64, keep)
+		for j := range promoted {
+			promoted[j] = round.Results[j].Parameters["lambda"].(float64)
+		}
+		if got := sortedLambdas(next.Results); !reflect.DeepEqual(got, sortedFloats(promoted)) {
+			t.Errorf("bracket %d round %d: promoted %v, want the top %d %v",
+				round.Bracket, round.Round, got, keep, sortedFloats(promoted))
+		}
+	}
+	if result.Evaluations != evaluations {
+		t.Errorf("evaluations = %d, want %d", result.Evaluations, evaluations)
+	}
+}
+
+func TestHalvingGridSearch(t *testing.T) {
+	data := ridgeData(270)
+	config := &ModelConfig{Algorithm: Ridge}
+	grid := ParamGrid{"lambda": {1e5, 3e4, 1e4, 1000.0, 100.0, 10.0, 1.0, 0.1, 0.01}}
+
+	result, err := searchClient(1).HalvingGridSearch(data, config, grid, &HalvingConfig{Factor: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 9个候选：30个样本上评估9个，90个样本上评估3个，270个样本上评估1个
+	wantSizes := [][2]int{{30, 9}, {90, 3}, {270, 1}}
+	if len(result.Rounds) != len(wantSizes) {
+		t.Fatalf("got %d rounds, want %d", len(result.Rounds), len(wantSizes))
+	}
+	for i, want := range wantSizes {
+		if result.Rounds[i].Resources != want[0] || len(result.Rounds[i].Results) != want[1] {
+			t.Errorf("round %d: %d samples, %d candidates, want %d, %d",
+				i, result.Rounds[i].Resources, len(result.Rounds[i].Results), want[0], want[1])
+		}
+	}
+	checkHalvingRounds(t, result, 3, 270)
+	if result.BestParameters["lambda"] != 0.01 || len(result.Results) != 1 {
+		t.Errorf("best lambda = %v from %d finalists, want 0.01 from 1", result.BestParameters["lambda"], len(result.Results))
+	}
+	for _, round := range result.Rounds {
+		if round.Results[0].Parameters["lambda"] != 0.01 {
+			t.Errorf("round %d: best lambda = %v, want 0.01", round.Round, round.Results[0].Parameters["lambda"])
+		}
+	}
+
+	same, err := searchClient(1).HalvingGridSearch(data, config, grid, &HalvingConfig{Factor: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Rounds, same.Rounds) {
+		t.Error("halving search with the same seed returned different rounds")
+	}
+}
+
+func TestHalvingRandomSearch(t *testing.T) {
+	data := ridgeData(200)
+	config := &ModelConfig{Algorithm: Ridge}
+	distributions := ParamDistributions{"lambda": LogUniform{Low: 1e-3, High: 1e4}}
+	halving := &HalvingConfig{Factor: 2, MinResources: 25}
+
+	result, err := searchClient(4).HalvingRandomSearch(data, config, distributions, 8, halving)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rounds[0].Resources != 25 || len(result.Rounds[0].Results) != 8 {
+		t.Errorf("first round: %d samples, %d candidates, want 25, 8", result.Rounds[0].Resources, len(result.Rounds[0].Results))
+	}
+	checkHalvingRounds(t, result, 2, 200)
+	checkRanking(t, result.TuningResult)
+	if want := sortedLambdas(result.Rounds[0].Results)[0]; result.BestParameters["lambda"] != want {
+		t.Errorf("best lambda = %v, want the smallest sampled lambda %v", result.BestParameters["lambda"], want)
+	}
+}
+
+func TestHyperband(t *testing.T) {
+	data := ridgeData(270)
+	config := &ModelConfig{Algorithm: Ridge}
+	distributions := ParamDistributions{"lambda": LogUniform{Low: 1e-3, High: 1e4}}
+	halving := &HalvingConfig{Factor: 3, MinResources: 10}
+
+	result, err := searchClient(2).Hyperband(data, config, distributions, halving)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 资源从10到270共有3次减半，分组按从激进到保守排列，第s组从270/3^s个样本开始
+	brackets := make(map[int]int)
+	for _, round := range result.Rounds {
+		if round.Round == 0 {
+			brackets[round.Bracket] = round.Resources
+		}
+	}
+	wantStart := map[int]int{0: 10, 1: 30, 2: 90, 3: 270}
+	if !reflect.DeepEqual(brackets, wantStart) {
+		t.Errorf("bracket start resources = %v, want %v", brackets, wantStart)
+	}
+	checkHalvingRounds(t, result, 3, 270)
+
+	// 汇总排名只包含在全部样本上评估过的候选
+	finalists := 0
+	for i, round := range result.Rounds {
+		if i+1 == len(result.Rounds) || result.Rounds[i+1].Bracket != round.Bracket {
+			finalists += len(round.Results)
+		}
+	}
+	if len(result.Results) != finalists {
+		t.Errorf("got %d ranked results, want %d finalists", len(result.Results), finalists)
+	}
+	checkRanking(t, result.TuningResult)
+
+	same, err := searchClient(2).Hyperband(data, config, distributions, halving)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Rounds, same.Rounds) || !reflect.DeepEqual(result.Results, same.Results) {
+		t.Error("hyperband with the same seed returned different results")
+	}
+}
+
+func TestHalvingInvalidConfig(t *testing.T) {
+	client := searchClient(1)
+	data := ridgeData(60)
+	config := &ModelConfig{Algorithm: Ridge}
+	grid := ParamGrid{"lambda": {0.1, 1.0}}
+
+	tests := []struct {
+		name    string
+		halving *HalvingConfig
+	}{
+		{"factor below 2", &HalvingConfig{Factor: 1}},
+		{"min above max", &HalvingConfig{MinResources: 50, MaxResources: 40}},
+	}
+	for _, tc := range tests {
+		if _, err := client.HalvingGridSearch(data, config, grid, tc.halving); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+	if _, err := client.HalvingRandomSearch(data, config, ParamDistributions{"lambda": Uniform{Low: 0, High: 1}}, 0, nil); err == nil {
+		t.Error("expected an error for zero candidates")
+	}
+}
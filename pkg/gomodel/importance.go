@@ -0,0 +1,130 @@
+package gomodel
+
+import (
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+	"gonum.org/v1/gonum/mat"
+)
+
+// PermutationImportance 置换特征重要性
+type PermutationImportance struct {
+	FeatureNames  []string    `json:"feature_names,omitempty"`
+	Metric        string      `json:"metric"`         // 使用的评分方式，得分越大越好（误差类指标取负值）
+	BaselineScore float64     `json:"baseline_score"` // 未打乱时的得分
+	Importances   []float64   `json:"importances"`    // 各特征打乱后得分下降量的均值，越大越重要
+	Std           []float64   `json:"std"`            // 得分下降量在重复之间的标准差
+	Drops         [][]float64 `json:"drops"`          // Drops[j][r] 为第r次打乱第j个特征时的得分下降量
+}
+
+// PermutationImportance 计算已训练模型在数据上的置换特征重要性：依次随机打乱每个特征并重复nRepeats次
+// （不大于0时为5），以得分的平均下降量作为重要性。只依赖模型的预测，适用于所有算法；应在验证集上计算。
+// metric为空时使用模型的Score（回归为R²，分类为准确率），MSE、RMSE、MAE、LogLoss取负值使得分越大越好。
+// randomSeed为0时使用当前时间
+func (c *Client) PermutationImportance(modelID string, data *TrainingData, metric LossFunction, nRepeats int, randomSeed int64) (*PermutationImportance, error) {
+	if data == nil || data.Features == nil || data.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	scoring, ok := permutationScoring[metric]
+	if !ok {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported metric: %s", metric),
+		}
+	}
+	if nRepeats <= 0 {
+		nRepeats = 5
+	}
+
+	model, err := c.manager.GetModel(modelID)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "model not found",
+			Details: err.Error(),
+		}
+	}
+	result, err := evaluation.PermutationImportance(model, data.Features, data.Target, scoring, nRepeats, NewDataUtils(randomSeed).newRand())
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrValidationFailed,
+			Message: "failed to compute permutation importance",
+			Details: err.Error(),
+		}
+	}
+
+	return &PermutationImportance{
+		FeatureNames:  data.FeatureNames,
+		Metric:        scoring,
+		BaselineScore: result.BaselineScore,
+		Importances:   result.Importances,
+		Std:           result.Std,
+		Drops:         result.Drops,
+	}, nil
+}
+
+// permutationScoring 损失函数对应的评分方式
+var permutationScoring = map[LossFunction]string{
+	"":       evaluation.ScoringDefault,
+	R2:       evaluation.ScoringR2,
+	MSE:      evaluation.ScoringNegMSE,
+	RMSE:     evaluation.ScoringNegRMSE,
+	MAE:      evaluation.ScoringNegMAE,
+	Accuracy: evaluation.ScoringAccuracy,
+	LogLoss:  evaluation.ScoringNegLogLoss,
+}
+
+// PartialDependence 单个特征的部分依赖
+type PartialDependence struct {
+	Feature int       `json:"feature"` // 特征的列下标
+	Grid    []float64 `json:"grid"`    // 特征的取值
+	Average []float64 `json:"average"` // 所有样本的该特征都取Grid[k]时预测值的均值
+}
+
+// PartialDependence 计算已训练模型对第featureIndex个特征的部分依赖，用于观察该特征的取值如何影响预测：
+// 对grid中的每个取值，将features中所有样本的该特征替换为这个值并取预测值的均值（分类模型为正类概率的均值）。
+// grid为空时在该特征的5%和95%分位数之间取20个等距点，不同取值不超过20个时直接使用这些取值
+func (c *Client) PartialDependence(modelID string, features *mat.Dense, featureIndex int, grid []float64) (*PartialDependence, error) {
+	if features == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	model, err := c.manager.GetModel(modelID)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "model not found",
+			Details: err.Error(),
+		}
+	}
+
+	if len(grid) == 0 {
+		grid, err = evaluation.PartialDependenceGrid(features, featureIndex, 20, 0.05, 0.95)
+		if err != nil {
+			return nil, &Error{
+				Code:    ErrInvalidParameters,
+				Message: "failed to build partial dependence grid",
+				Details: err.Error(),
+			}
+		}
+	}
+	result, err := evaluation.PartialDependence(model, features, featureIndex, grid)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to compute partial dependence",
+			Details: err.Error(),
+		}
+	}
+
+	return &PartialDependence{
+		Feature: result.Feature,
+		Grid:    result.Grid,
+		Average: result.Average,
+	}, nil
+}
@@ -0,0 +1,419 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 缺失值填充策略
+const (
+	ImputeMean     = "mean"
+	ImputeMedian   = "median"
+	ImputeMode     = "mode"
+	ImputeConstant = "constant"
+)
+
+// ImputedCell 一个被填充的单元格
+type ImputedCell struct {
+	Row     int     `json:"row"`
+	Column  int     `json:"column"`
+	Feature string  `json:"feature"`
+	Value   float64 `json:"value"`
+}
+
+// Imputer 缺失值填充变换器，将特征中的NaN替换为按列学到的统计量，可作为流水线步骤。
+// 配合LoadFromCSVWithMissing使用，使空白或非数值的单元格不再被静默替换为0
+type Imputer struct {
+	Strategy   string             `json:"strategy"`    // 默认填充策略
+	FillValue  float64            `json:"fill_value"`  // constant策略的默认填充值
+	Strategies map[string]string  `json:"strategies"`  // 按特征名覆盖填充策略
+	FillValues map[string]float64 `json:"fill_values"` // 按特征名指定constant策略的填充值
+	Statistics []float64          `json:"statistics"`  // 每列学到的填充值
+	Imputed    []ImputedCell      `json:"imputed"`     // 最近一次Transform填充的单元格
+	names      []string
+}
+
+// NewImputer 创建使用指定默认策略的填充变换器
+func NewImputer(strategy string) (*Imputer, error) {
+	if err := validateImputeStrategy(strategy); err != nil {
+		return nil, err
+	}
+	return &Imputer{Strategy: strategy}, nil
+}
+
+// Fit 按每列的策略从非缺失值中计算填充值
+func (im *Imputer) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if err := validateImputeStrategy(im.Strategy); err != nil {
+		return err
+	}
+
+	names := columnNames(d)
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+	for name, strategy := range im.Strategies {
+		if !known[name] {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: fmt.Sprintf("imputation strategy given for unknown feature %q", name),
+			}
+		}
+		if err := validateImputeStrategy(strategy); err != nil {
+			return err
+		}
+	}
+
+	r, c := d.Features.Dims()
+	statistics := make([]float64, c)
+	observed := make([]float64, 0, r)
+	for j := 0; j < c; j++ {
+		strategy := im.Strategy
+		if s, ok := im.Strategies[names[j]]; ok {
+			strategy = s
+		}
+		if strategy == ImputeConstant {
+			statistics[j] = im.FillValue
+			if v, ok := im.FillValues[names[j]]; ok {
+				statistics[j] = v
+			}
+			continue
+		}
+
+		observed = observed[:0]
+		for i := 0; i < r; i++ {
+			if v := d.Features.At(i, j); !math.IsNaN(v) {
+				observed = append(observed, v)
+			}
+		}
+		if len(observed) == 0 {
+			return &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("feature %q has no observed values to compute the %s", names[j], strategy),
+			}
+		}
+
+		switch strategy {
+		case ImputeMean:
+			sum := 0.0
+			for _, v := range observed {
+				sum += v
+			}
+			statistics[j] = sum / float64(len(observed))
+		case ImputeMedian:
+			sort.Float64s(observed)
+			mid := len(observed) / 2
+			if len(observed)%2 == 0 {
+				statistics[j] = (observed[mid-1] + observed[mid]) / 2
+			} else {
+				statistics[j] = observed[mid]
+			}
+		case ImputeMode:
+			statistics[j] = mostFrequent(observed)
+		}
+	}
+
+	im.Statistics = statistics
+	im.names = names
+	return nil
+}
+
+// Transform 将NaN替换为学到的填充值，并在Imputed中记录被填充的单元格
+func (im *Imputer) Transform(d *TrainingData) (*TrainingData, error) {
+	if im.Statistics == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != len(im.Statistics) {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", len(im.Statistics), c),
+		}
+	}
+
+	features := mat.DenseCopyOf(d.Features)
+	im.Imputed = nil
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if !math.IsNaN(features.At(i, j)) {
+				continue
+			}
+			features.Set(i, j, im.Statistics[j])
+			im.Imputed = append(im.Imputed, ImputedCell{
+				Row:     i,
+				Column:  j,
+				Feature: im.names[j],
+				Value:   im.Statistics[j],
+			})
+		}
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   d.Categories,
+	}, nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (im *Imputer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(im, d)
+}
+
+// validateImputeStrategy 检查填充策略是否受支持
+func validateImputeStrategy(strategy string) error {
+	switch strategy {
+	case ImputeMean, ImputeMedian, ImputeMode, ImputeConstant:
+		return nil
+	}
+	return &Error{
+		Code:    ErrInvalidParameters,
+		Message: fmt.Sprintf("unsupported imputation strategy: %s", strategy),
+	}
+}
+
+// mostFrequent 返回出现次数最多的值，次数相同时取较小的值
+func mostFrequent(values []float64) float64 {
+	counts := make(map[float64]int, len(values))
+	for _, v := range values {
+		counts[v]++
+	}
+	best, bestCount := 0.0, 0
+	for v, count := range counts {
+		if count > bestCount || (count == bestCount && v < best) {
+			best, bestCount = v, count
+		}
+	}
+	return best
+}
+
+// KNN填充的邻居加权方式
+const (
+	KNNWeightsUniform  = "uniform"
+	KNNWeightsDistance = "distance"
+)
+
+// KNNImputer 用K个最近的完整样本填充缺失值（NaN），可作为流水线步骤。
+// 距离只在待填充样本的非缺失特征上计算，并按完整样本的均值和标准差标准化，
+// 避免量纲较大的特征主导距离；相比均值填充能保留特征之间的关系
+type KNNImputer struct {
+	K         int           `json:"k"`       // 邻居数量，默认5
+	Weights   string        `json:"weights"` // uniform为简单平均，distance按距离倒数加权
+	Imputed   []ImputedCell `json:"imputed"` // 最近一次Transform填充的单元格
+	donors    *mat.Dense    // 训练数据中不含缺失值的样本
+	means     []float64
+	scales    []float64
+	names     []string
+	nFeatures int
+}
+
+// NewKNNImputer 创建使用k个邻居、简单平均的KNN填充变换器
+func NewKNNImputer(k int) (*KNNImputer, error) {
+	if k < 1 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "k must be at least 1",
+		}
+	}
+	return &KNNImputer{K: k, Weights: KNNWeightsUniform}, nil
+}
+
+// Fit 保存训练数据中的完整样本作为候选邻居，并计算用于距离标准化的均值和标准差
+func (ki *KNNImputer) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if ki.K == 0 {
+		ki.K = 5
+	}
+	if ki.K < 1 {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "k must be at least 1",
+		}
+	}
+	if ki.Weights == "" {
+		ki.Weights = KNNWeightsUniform
+	}
+	if ki.Weights != KNNWeightsUniform && ki.Weights != KNNWeightsDistance {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported weights: %s", ki.Weights),
+		}
+	}
+
+	r, c := d.Features.Dims()
+	var complete []int
+	for i := 0; i < r; i++ {
+		if !hasNaN(d.Features.RawRowView(i)) {
+			complete = append(complete, i)
+		}
+	}
+	if len(complete) == 0 {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "no complete rows available as neighbors",
+		}
+	}
+
+	donors := mat.NewDense(len(complete), c, nil)
+	for k, i := range complete {
+		donors.SetRow(k, d.Features.RawRowView(i))
+	}
+	means := make([]float64, c)
+	scales := make([]float64, c)
+	for j := 0; j < c; j++ {
+		column := mat.Col(nil, j, donors)
+		for _, v := range column {
+			means[j] += v
+		}
+		means[j] /= float64(len(column))
+		for _, v := range column {
+			scales[j] += (v - means[j]) * (v - means[j])
+		}
+		scales[j] = math.Sqrt(scales[j] / float64(len(column)))
+		if scales[j] == 0 {
+			scales[j] = 1
+		}
+	}
+
+	ki.donors = donors
+	ki.means = means
+	ki.scales = scales
+	ki.names = columnNames(d)
+	ki.nFeatures = c
+	return nil
+}
+
+// Transform 对每个含缺失值的样本找出K个最近的完整样本，用它们在缺失特征上的（加权）平均值填充
+func (ki *KNNImputer) Transform(d *TrainingData) (*TrainingData, error) {
+	if ki.donors == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != ki.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", ki.nFeatures, c),
+		}
+	}
+
+	features := mat.DenseCopyOf(d.Features)
+	nDonors, _ := ki.donors.Dims()
+	k := min(ki.K, nDonors)
+	distances := make([]float64, nDonors)
+	order := make([]int, nDonors)
+	ki.Imputed = nil
+
+	for i := 0; i < r; i++ {
+		row := features.RawRowView(i)
+		if !hasNaN(row) {
+			continue
+		}
+		observed := false
+		for _, v := range row {
+			observed = observed || !math.IsNaN(v)
+		}
+		if !observed {
+			// 没有可用于计算距离的特征时，使用全部完整样本的均值
+			for j := range row {
+				row[j] = ki.means[j]
+				ki.Imputed = append(ki.Imputed, ImputedCell{Row: i, Column: j, Feature: ki.names[j], Value: ki.means[j]})
+			}
+			continue
+		}
+
+		for n := 0; n < nDonors; n++ {
+			donor := ki.donors.RawRowView(n)
+			sum := 0.0
+			for j, v := range row {
+				if math.IsNaN(v) {
+					continue
+				}
+				diff := (v - donor[j]) / ki.scales[j]
+				sum += diff * diff
+			}
+			distances[n] = math.Sqrt(sum)
+			order[n] = n
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return distances[order[a]] < distances[order[b]]
+		})
+		neighbors := order[:k]
+
+		// 存在完全相同的邻居时，distance加权只使用这些邻居
+		exact := distances[neighbors[0]] == 0
+		for j, v := range row {
+			if !math.IsNaN(v) {
+				continue
+			}
+			total, weightSum := 0.0, 0.0
+			for _, n := range neighbors {
+				weight := 1.0
+				if ki.Weights == KNNWeightsDistance {
+					switch {
+					case exact && distances[n] > 0:
+						continue
+					case !exact:
+						weight = 1 / distances[n]
+					}
+				}
+				total += weight * ki.donors.At(n, j)
+				weightSum += weight
+			}
+			value := total / weightSum
+			row[j] = value
+			ki.Imputed = append(ki.Imputed, ImputedCell{
+				Row:     i,
+				Column:  j,
+				Feature: ki.names[j],
+				Value:   value,
+			})
+		}
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   d.Categories,
+	}, nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (ki *KNNImputer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(ki, d)
+}
+
+// hasNaN 判断一行中是否存在缺失值
+func hasNaN(values []float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
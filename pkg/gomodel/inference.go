@@ -0,0 +1,99 @@
+package gomodel
+
+import (
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/models/linear"
+	"gonum.org/v1/gonum/mat"
+)
+
+// InferenceResult 线性模型（OLS、Ridge、Logistic）系数的统计推断结果。Coefficients、StdErrors、
+// Statistics和PValues的第0个元素为截距，之后依次为各特征的系数；Covariance按同样的顺序排列
+type InferenceResult struct {
+	Coefficients []float64   `json:"coefficients"`
+	StdErrors    []float64   `json:"std_errors"`
+	Statistics   []float64   `json:"statistics"` // OLS/Ridge为t统计量，Logistic为z统计量
+	PValues      []float64   `json:"p_values"`   // 双侧检验的p值，原假设为系数等于0
+	Covariance   [][]float64 `json:"covariance"` // 系数的协方差矩阵
+	Test         string      `json:"test"`       // "t"或"z"
+	DFModel      float64     `json:"df_model"`   // 模型自由度（不含截距），Ridge为有效自由度
+	DFResidual   float64     `json:"df_residual"`
+	// 以下为OLS/Ridge的整体F检验，原假设为除截距外的系数全为0
+	Sigma2     float64 `json:"sigma2"` // 残差方差的估计
+	FStatistic float64 `json:"f_statistic"`
+	FPValue    float64 `json:"f_p_value"`
+	// 以下为Logistic相对仅含截距模型的似然比检验
+	LLRStatistic float64 `json:"llr_statistic"`
+	LLRPValue    float64 `json:"llr_p_value"`
+	PseudoR2     float64 `json:"pseudo_r2"` // McFadden伪R²
+}
+
+// inferenceInfo 从模型参数中提取系数的推断结果，模型不支持推断时返回nil
+func inferenceInfo(params map[string]interface{}) *InferenceResult {
+	inference, ok := params["inference"].(*linear.InferenceResult)
+	if !ok || inference == nil {
+		return nil
+	}
+	result := InferenceResult(*inference)
+	return &result
+}
+
+// PredictionInterval 单个样本预测值的区间估计
+type PredictionInterval struct {
+	StdError        float64 `json:"std_error"`        // 均值预测的标准误，Logistic为线性预测值的标准误
+	Lower           float64 `json:"lower"`            // 均值（期望）的置信区间下限，Logistic为正类概率的区间
+	Upper           float64 `json:"upper"`            // 均值（期望）的置信区间上限
+	PredictionLower float64 `json:"prediction_lower"` // 单个新观测的预测区间下限，包含残差的波动，比置信区间宽
+	PredictionUpper float64 `json:"prediction_upper"` // 单个新观测的预测区间上限
+}
+
+// PredictWithIntervals 预测并在PredictionResult.Confidence中给出每个样本在置信水平level下的置信区间和预测区间，
+// level不大于0时使用0.95。OLS的区间是精确的；Ridge按有效自由度近似；Logistic按线性预测值的正态近似
+// 变换为概率区间，预测区间与置信区间相同。只支持训练结果带有Inference的模型
+func (c *Client) PredictWithIntervals(modelID string, features *mat.Dense, level float64) (*PredictionResult, error) {
+	if level <= 0 {
+		level = 0.95
+	}
+	if level >= 1 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("confidence level must be in (0, 1), got %v", level),
+		}
+	}
+
+	info, err := c.manager.GetModelInfo(modelID)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "model not found",
+			Details: err.Error(),
+		}
+	}
+	inference, ok := info.Parameters["inference"].(*linear.InferenceResult)
+	if !ok || inference == nil {
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("prediction intervals are not available for %s models", info.ModelType),
+			Details: "intervals require OLS, Ridge or Logistic with a non-singular coefficient covariance",
+		}
+	}
+
+	result, err := c.Predict(modelID, features)
+	if err != nil {
+		return nil, err
+	}
+	intervals, err := inference.Intervals(features, level)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrPredictionFailed,
+			Message: "failed to compute prediction intervals",
+			Details: err.Error(),
+		}
+	}
+	result.Confidence = make([]PredictionInterval, len(intervals))
+	for i, interval := range intervals {
+		result.Confidence[i] = PredictionInterval(interval)
+	}
+	result.Metadata["confidence_level"] = level
+	return result, nil
+}
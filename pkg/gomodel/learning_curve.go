@@ -0,0 +1,159 @@
+package gomodel
+
+import (
+	"fmt"
+)
+
+// LearningCurve 学习曲线，得分按 [训练样本数][折] 排列，越大越好
+type LearningCurve struct {
+	TrainSizes       []int       `json:"train_sizes"`       // 实际使用的训练样本数
+	TrainScores      [][]float64 `json:"train_scores"`      // 在所用训练样本上的得分
+	ValidationScores [][]float64 `json:"validation_scores"` // 在验证集上的得分
+	TrainMean        []float64   `json:"train_mean"`
+	TrainStd         []float64   `json:"train_std"`
+	ValidationMean   []float64   `json:"validation_mean"`
+	ValidationStd    []float64   `json:"validation_std"`
+}
+
+// LearningCurve 在逐渐增大的训练集上交叉验证模型配置，返回每个训练样本数下的训练得分和验证得分。
+// 两条曲线在高处汇合、验证得分仍在上升说明增加数据有帮助；训练得分远高于验证得分说明过拟合，
+// 需要更强的正则化；两者都偏低说明模型欠拟合。trainSizes为训练集比例（取值(0, 1]，相对各折训练集），
+// 为空时为0.1、0.325、0.55、0.775和1.0。划分使用validation的KFolds（不大于0时为5）、RandomSeed和Splitter，
+// 忽略Method；validation为nil时为5折。评分方式由validation的Scoring或ScoreFunc指定，默认与kfold验证相同
+func (c *Client) LearningCurve(data *TrainingData, config *ModelConfig, trainSizes []float64, validation *ValidationConfig) (*LearningCurve, error) {
+	if data == nil || config == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "training data and model config cannot be nil",
+		}
+	}
+	if !c.isValidAlgorithm(config.Algorithm) {
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("unsupported algorithm: %s", config.Algorithm),
+		}
+	}
+	if err := c.validateData(data); err != nil {
+		return nil, err
+	}
+	if len(trainSizes) == 0 {
+		trainSizes = []float64{0.1, 0.325, 0.55, 0.775, 1.0}
+	}
+	for _, size := range trainSizes {
+		if size <= 0 || size > 1 {
+			return nil, &Error{
+				Code:    ErrInvalidParameters,
+				Message: fmt.Sprintf("train size fraction must be in (0, 1], got %v", size),
+			}
+		}
+	}
+	cv, dataset, folds, err := c.crossValidationFolds(data, config, curveValidation(validation))
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrValidationFailed,
+			Message: "failed to split data",
+			Details: err.Error(),
+		}
+	}
+	result, err := cv.LearningCurve(dataset, string(config.Algorithm), internalParameters(config.Parameters), folds, trainSizes)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrValidationFailed,
+			Message: "failed to compute learning curve",
+			Details: err.Error(),
+		}
+	}
+
+	curve := &LearningCurve{
+		TrainSizes:       result.TrainSizes,
+		TrainScores:      result.TrainScores,
+		ValidationScores: result.ValidationScores,
+	}
+	for k := range result.TrainSizes {
+		mean, std := c.calculateStats(result.TrainScores[k])
+		curve.TrainMean = append(curve.TrainMean, mean)
+		curve.TrainStd = append(curve.TrainStd, std)
+		mean, std = c.calculateStats(result.ValidationScores[k])
+		curve.ValidationMean = append(curve.ValidationMean, mean)
+		curve.ValidationStd = append(curve.ValidationStd, std)
+	}
+	return curve, nil
+}
+
+// ValidationCurve 验证曲线，得分按 [参数取值][折] 排列，越大越好
+type ValidationCurve struct {
+	ParamName        string        `json:"param_name"`
+	Values           []interface{} `json:"values"`
+	TrainScores      [][]float64   `json:"train_scores"`      // 在各折训练集上的得分
+	ValidationScores [][]float64   `json:"validation_scores"` // 在各折验证集上的得分
+	TrainMean        []float64     `json:"train_mean"`
+	TrainStd         []float64     `json:"train_std"`
+	ValidationMean   []float64     `json:"validation_mean"`
+	ValidationStd    []float64     `json:"validation_std"`
+	BestIndex        int           `json:"best_index"` // 平均验证得分最高的取值在Values中的下标
+}
+
+// ValidationCurve 将模型配置中的参数paramName依次设为values中的每个取值并交叉验证，返回各取值下的训练得分和验证得分，
+// 用于观察单个超参数（如lambda）从欠拟合到过拟合的变化：训练得分和验证得分都低为欠拟合，训练得分高而验证得分低为过拟合。
+// 所有取值使用相同的划分；划分和评分方式与LearningCurve相同
+func (c *Client) ValidationCurve(data *TrainingData, config *ModelConfig, paramName string, values []interface{}, validation *ValidationConfig) (*ValidationCurve, error) {
+	if err := c.validateSearchInput(data, config); err != nil {
+		return nil, err
+	}
+	if paramName == "" || len(values) == 0 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "parameter name and values cannot be empty",
+		}
+	}
+
+	cv, dataset, folds, err := c.crossValidationFolds(data, config, curveValidation(validation))
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrValidationFailed,
+			Message: "failed to split data",
+			Details: err.Error(),
+		}
+	}
+
+	curve := &ValidationCurve{ParamName: paramName, Values: values}
+	for k, value := range values {
+		candidate := withParameters(config, map[string]interface{}{paramName: value})
+		if err := c.validateAlgorithmParameters(candidate.Algorithm, candidate.Parameters); err != nil {
+			return nil, err
+		}
+		trainScores, validationScores, err := cv.TrainValidationScores(dataset, string(candidate.Algorithm), internalParameters(candidate.Parameters), folds)
+		if err != nil {
+			return nil, &Error{
+				Code:    ErrValidationFailed,
+				Message: fmt.Sprintf("failed to evaluate %s=%v", paramName, value),
+				Details: err.Error(),
+			}
+		}
+
+		curve.TrainScores = append(curve.TrainScores, trainScores)
+		curve.ValidationScores = append(curve.ValidationScores, validationScores)
+		mean, std := c.calculateStats(trainScores)
+		curve.TrainMean = append(curve.TrainMean, mean)
+		curve.TrainStd = append(curve.TrainStd, std)
+		mean, std = c.calculateStats(validationScores)
+		curve.ValidationMean = append(curve.ValidationMean, mean)
+		curve.ValidationStd = append(curve.ValidationStd, std)
+		if mean > curve.ValidationMean[curve.BestIndex] {
+			curve.BestIndex = k
+		}
+	}
+	return curve, nil
+}
+
+// curveValidation 返回学习曲线和验证曲线的划分配置，validation为nil或KFolds不大于0时为5折
+func curveValidation(validation *ValidationConfig) *ValidationConfig {
+	settings := ValidationConfig{}
+	if validation != nil {
+		settings = *validation
+	}
+	if settings.KFolds <= 0 {
+		settings.KFolds = 5
+	}
+	return &settings
+}
@@ -2,32 +2,49 @@ package gomodel
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/feiyuluoye/Go-Model/internal/evaluation"
 	"github.com/feiyuluoye/Go-Model/internal/models"
 	"github.com/feiyuluoye/Go-Model/internal/types"
+	"gonum.org/v1/gonum/mat"
 )
 
 // ModelManager 扩展的模型管理器，提供更高级的功能
 type ModelManager struct {
 	internalManager *models.ModelManager
 	trainedModels   map[string]*TrainedModel
+	registry        *ModelRegistry
+	validator       *Validator // 训练前的数据质量检查，为nil时使用NewValidator()
 	mutex           sync.RWMutex
 }
 
+// 模型的任务类型
+const (
+	TaskRegression     = "regression"
+	TaskClassification = "classification" // 二分类，模型预测正类概率
+)
+
 // TrainedModel 训练好的模型信息
 type TrainedModel struct {
 	ID          string                 `json:"id"`
 	Algorithm   AlgorithmType          `json:"algorithm"`
 	Parameters  map[string]interface{} `json:"parameters"`
+	Task        string                 `json:"task"` // TaskRegression或TaskClassification
 	TrainedAt   time.Time              `json:"trained_at"`
 	Performance map[string]float64     `json:"performance"`
 	DataShape   []int                  `json:"data_shape"`
+	DataHash    string                 `json:"data_hash,omitempty"` // 训练数据的指纹（见TrainingData.Fingerprint）
 	Summary     *ModelSummary          `json:"summary"`
 }
 
+// TrainedOn 判断模型是否在与data内容相同的数据上训练；未记录训练数据指纹时返回false
+func (m *TrainedModel) TrainedOn(data *TrainingData) bool {
+	return m.DataHash != "" && m.DataHash == data.Fingerprint()
+}
+
 // NewModelManager 创建新的模型管理器
 func NewModelManager() *ModelManager {
 	return &ModelManager{
@@ -37,28 +54,25 @@ func NewModelManager() *ModelManager {
 }
 
 // TrainModel 训练模型并保存信息
+// 训练过程不持有锁，多个模型可以并发训练（见TrainAll）。
+// 训练前与Client.Train一样检查数据维度和数据质量，存在致命问题时拒绝训练
 func (mm *ModelManager) TrainModel(config *ModelConfig, data *TrainingData) (*TrainedModel, error) {
-	mm.mutex.Lock()
-	defer mm.mutex.Unlock()
-
-	// 生成模型ID
-	modelID := fmt.Sprintf("%s_%d", config.Algorithm, time.Now().UnixNano())
-
-	// 创建内部模型
-	err := mm.internalManager.CreateModel(modelID, string(config.Algorithm), config.Parameters)
-	if err != nil {
-		return nil, &Error{
-			Code:    ErrTrainingFailed,
-			Message: "failed to create internal model",
-			Details: err.Error(),
-		}
+	if err := validateTrainingData(data); err != nil {
+		return nil, err
+	}
+	mm.mutex.RLock()
+	validator := mm.validator
+	mm.mutex.RUnlock()
+	if err := checkQuality(validator, data); err != nil {
+		return nil, err
 	}
 
 	// 准备训练数据
 	X, y := mm.prepareData(data)
 
 	// 训练模型
-	err = mm.internalManager.TrainModel(modelID, X, y)
+	internalConfig := toInternalConfig(config)
+	trainingResult, err := mm.internalManager.TrainModelWeighted(internalConfig, data.Features, data.Target, data.Weights)
 	if err != nil {
 		return nil, &Error{
 			Code:    ErrTrainingFailed,
@@ -66,36 +80,89 @@ func (mm *ModelManager) TrainModel(config *ModelConfig, data *TrainingData) (*Tr
 			Details: err.Error(),
 		}
 	}
-
-	// 评估模型
-	score, err := mm.internalManager.EvaluateModel(modelID, X, y)
-	if err != nil {
-		return nil, &Error{
-			Code:    ErrTrainingFailed,
-			Message: "failed to evaluate model",
-			Details: err.Error(),
-		}
-	}
+	modelID := trainingResult.ModelID
+	score := trainingResult.TrainingScore
 
 	// 创建训练好的模型记录
 	trainedModel := &TrainedModel{
 		ID:         modelID,
 		Algorithm:  config.Algorithm,
 		Parameters: config.Parameters,
+		Task:       configTask(config),
 		TrainedAt:  time.Now(),
 		Performance: map[string]float64{
 			"training_score": score,
 		},
 		DataShape: []int{len(X), len(X[0])},
+		DataHash:  data.Fingerprint(),
 	}
 
 	// 计算额外的性能指标
-	mm.calculatePerformanceMetrics(trainedModel, modelID, X, y)
+	mm.calculatePerformanceMetrics(trainedModel, modelID, data.Features, y, trainedModel.Task == TaskClassification)
 
 	// 生成模型摘要
 	trainedModel.Summary = mm.generateModelSummary(trainedModel, data)
 
 	// 保存模型记录
+	mm.mutex.Lock()
+	mm.trainedModels[modelID] = trainedModel
+	mm.mutex.Unlock()
+
+	return trainedModel, nil
+}
+
+// CreateEnsemble 将已训练的模型组合为投票集成并保存记录
+// voting为"average"时对预测加权平均（回归），为"majority"时加权多数投票（分类）；weights为空时等权
+func (mm *ModelManager) CreateEnsemble(modelIDs []string, weights []float64, voting string) (*TrainedModel, error) {
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	if len(modelIDs) == 0 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "ensemble requires at least one member model",
+		}
+	}
+	for _, modelID := range modelIDs {
+		if _, exists := mm.trainedModels[modelID]; !exists {
+			return nil, &Error{
+				Code:    ErrModelNotTrained,
+				Message: fmt.Sprintf("model %s not found", modelID),
+			}
+		}
+	}
+
+	modelID, err := mm.internalManager.CreateVotingEnsemble(modelIDs, weights, voting)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to create ensemble",
+			Details: err.Error(),
+		}
+	}
+
+	first := mm.trainedModels[modelIDs[0]]
+	trainedModel := &TrainedModel{
+		ID:        modelID,
+		Algorithm: Voting,
+		Parameters: map[string]interface{}{
+			"members": modelIDs,
+			"weights": weights,
+			"voting":  voting,
+		},
+		Task:        TaskRegression,
+		TrainedAt:   time.Now(),
+		Performance: make(map[string]float64),
+		DataShape:   first.DataShape,
+	}
+	if voting == "majority" {
+		trainedModel.Task = TaskClassification
+	}
+	var featureNames []string
+	if first.Summary != nil {
+		featureNames = first.Summary.FeatureNames
+	}
+	trainedModel.Summary = mm.generateModelSummary(trainedModel, &TrainingData{FeatureNames: featureNames})
 	mm.trainedModels[modelID] = trainedModel
 
 	return trainedModel, nil
@@ -115,7 +182,7 @@ func (mm *ModelManager) PredictWithModel(modelID string, features [][]float64) (
 	}
 
 	// 使用内部管理器进行预测
-	predictions, err := mm.internalManager.PredictModel(modelID, features)
+	predictions, err := mm.predict(modelID, NewDenseFromArrays(features))
 	if err != nil {
 		return nil, &Error{
 			Code:    ErrPredictionFailed,
@@ -180,11 +247,130 @@ func (mm *ModelManager) DeleteModel(modelID string) error {
 	}
 
 	delete(mm.trainedModels, modelID)
+	mm.internalManager.RemoveModel(modelID)
+	return nil
+}
+
+// SetValidator 设置训练前使用的数据质量检查器，为nil时使用NewValidator()
+func (mm *ModelManager) SetValidator(validator *Validator) {
+	mm.mutex.Lock()
+	mm.validator = validator
+	mm.mutex.Unlock()
+}
+
+// SetRegistry 关联模型仓库，之后可以用SaveToRegistry保存模型、用LoadFromRegistry加载模型。
+// 仓库中已有的模型ID会被预留，之后训练的模型不会与之重复
+func (mm *ModelManager) SetRegistry(registry *ModelRegistry) error {
+	entries, err := registry.List()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		mm.internalManager.ReserveModelID(entry.ID)
+	}
+	mm.mutex.Lock()
+	mm.registry = registry
+	mm.mutex.Unlock()
 	return nil
 }
 
-// CompareModels 比较多个模型的性能
+// SaveToRegistry 将已训练的模型连同参数、指标、训练数据指纹和时间保存到关联的模型仓库，已保存过时覆盖。
+// 集成模型和概率校准模型不支持保存
+func (mm *ModelManager) SaveToRegistry(modelID string) (*RegistryEntry, error) {
+	registry, err := mm.attachedRegistry()
+	if err != nil {
+		return nil, err
+	}
+	mm.mutex.RLock()
+	record, exists := mm.trainedModels[modelID]
+	mm.mutex.RUnlock()
+	if !exists {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: fmt.Sprintf("model %s not found", modelID),
+		}
+	}
+	model, err := mm.internalManager.GetModel(modelID)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: fmt.Sprintf("model %s not found", modelID),
+			Details: err.Error(),
+		}
+	}
+	return registry.save(record, model)
+}
+
+// LoadFromRegistry 从关联的模型仓库加载模型，之后可以像训练得到的模型一样按ID预测、评估和比较。
+// 模型已在内存中时直接返回已有记录
+func (mm *ModelManager) LoadFromRegistry(modelID string) (*TrainedModel, error) {
+	registry, err := mm.attachedRegistry()
+	if err != nil {
+		return nil, err
+	}
+	mm.mutex.RLock()
+	record, exists := mm.trainedModels[modelID]
+	mm.mutex.RUnlock()
+	if exists {
+		return record, nil
+	}
+
+	loaded, err := registry.Load(modelID)
+	if err != nil {
+		return nil, err
+	}
+	entry := loaded.Entry
+	record = &TrainedModel{
+		ID:          entry.ID,
+		Algorithm:   entry.Algorithm,
+		Parameters:  entry.Parameters,
+		Task:        entry.Task,
+		TrainedAt:   entry.TrainedAt,
+		Performance: entry.Performance,
+		DataShape:   entry.DataShape,
+		DataHash:    entry.DataHash,
+	}
+	record.Summary = mm.generateModelSummary(record, &TrainingData{FeatureNames: entry.FeatureNames})
+
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+	if existing, exists := mm.trainedModels[modelID]; exists {
+		return existing, nil
+	}
+	if err := mm.internalManager.RegisterModel(modelID, loaded.model); err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("model id %s is already in use", modelID),
+			Details: err.Error(),
+		}
+	}
+	mm.trainedModels[modelID] = record
+	return record, nil
+}
+
+// attachedRegistry 返回关联的模型仓库，未关联时返回错误
+func (mm *ModelManager) attachedRegistry() (*ModelRegistry, error) {
+	mm.mutex.RLock()
+	defer mm.mutex.RUnlock()
+	if mm.registry == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "no model registry attached, call SetRegistry first",
+		}
+	}
+	return mm.registry, nil
+}
+
+// CompareModels 比较多个模型的性能，metric为"training_score"或已注册的指标名称（见RegisterScorer）
 func (mm *ModelManager) CompareModels(modelIDs []string, metric string) (map[string]float64, error) {
+	if _, err := evaluation.GetScorer(metric); err != nil && metric != "training_score" {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unknown metric: %s", metric),
+			Details: err.Error(),
+		}
+	}
+
 	mm.mutex.RLock()
 	defer mm.mutex.RUnlock()
 
@@ -221,13 +407,24 @@ func (mm *ModelManager) CrossValidateModel(config *ModelConfig, data *TrainingDa
 	dataset := &types.Dataset{
 		Features: X,
 		Target:   y,
+		Weights:  weightsSlice(data.Weights),
 	}
 
 	// 创建交叉验证器
-	cv := evaluation.NewCrossValidator(folds, time.Now().UnixNano())
+	seed := time.Now().UnixNano()
+	if config.Validation != nil && config.Validation.RandomSeed != 0 {
+		seed = config.Validation.RandomSeed
+	}
+	cv := evaluation.NewCrossValidator(folds, seed)
+	cv.Factory = mm.internalManager // 每折由内部管理器创建新的未训练模型
+	cv.Scoring, cv.ScoreFunc = validationScoring(config, config.Validation)
+	cv.Stratify = isClassifier(config) || (config.Validation != nil && config.Validation.Stratify)
+	if config.Validation != nil {
+		cv.Workers = config.Validation.Workers
+	}
 
 	// 执行交叉验证
-	scores, err := cv.Validate(dataset, string(config.Algorithm), config.Parameters)
+	scores, err := cv.Validate(dataset, string(config.Algorithm), internalParameters(config.Parameters))
 	if err != nil {
 		return nil, &Error{
 			Code:    ErrValidationFailed,
@@ -248,7 +445,7 @@ func (mm *ModelManager) CrossValidateModel(config *ModelConfig, data *TrainingDa
 }
 
 // BatchPredict 批量预测多个数据集
-func (mm *ModelManager) BatchPredict(modelID string, datasets [][]float64) ([]*PredictionResult, error) {
+func (mm *ModelManager) BatchPredict(modelID string, datasets [][][]float64) ([]*PredictionResult, error) {
 	results := make([]*PredictionResult, len(datasets))
 
 	for i, dataset := range datasets {
@@ -262,10 +459,28 @@ func (mm *ModelManager) BatchPredict(modelID string, datasets [][]float64) ([]*P
 	return results, nil
 }
 
-// EvaluateModelOnTestData 在测试数据上评估模型
+// TestEvaluation 模型在测试数据上的评估结果
+type TestEvaluation struct {
+	Task            string             `json:"task"`
+	Metrics         map[string]float64 `json:"metrics"`
+	ConfusionMatrix *ConfusionMatrix   `json:"confusion_matrix,omitempty"` // 仅分类任务，正类概率按0.5的阈值转换为类别
+}
+
+// EvaluateModelOnTestData 在测试数据上评估模型，按训练时记录的任务类型返回指标：回归模型为r2_score、mse、mae、rmse等，
+// 分类模型为accuracy、f1、roc_auc、logloss等。需要混淆矩阵或指定任务类型时使用EvaluateOnTestData
 func (mm *ModelManager) EvaluateModelOnTestData(modelID string, testData *TrainingData) (map[string]float64, error) {
+	result, err := mm.EvaluateOnTestData(modelID, testData, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.Metrics, nil
+}
+
+// EvaluateOnTestData 在测试数据上评估模型。task为TaskRegression或TaskClassification，为空时使用训练时记录的任务类型。
+// 回归任务计算所有已注册的回归指标（r2另记为r2_score）；分类任务计算所有已注册的分类指标，并给出混淆矩阵
+func (mm *ModelManager) EvaluateOnTestData(modelID string, testData *TrainingData, task string) (*TestEvaluation, error) {
 	mm.mutex.RLock()
-	_, exists := mm.trainedModels[modelID]
+	model, exists := mm.trainedModels[modelID]
 	mm.mutex.RUnlock()
 
 	if !exists {
@@ -274,34 +489,62 @@ func (mm *ModelManager) EvaluateModelOnTestData(modelID string, testData *Traini
 			Message: fmt.Sprintf("model %s not found", modelID),
 		}
 	}
+	if testData == nil || testData.Features == nil || testData.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	if task == "" {
+		task = model.Task
+	}
+	if task != TaskRegression && task != TaskClassification {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported task: %s", task),
+		}
+	}
 
-	// 准备测试数据
-	X, y := mm.prepareData(testData)
-
-	// 评估模型
-	score, err := mm.internalManager.EvaluateModel(modelID, X, y)
+	predictions, err := mm.predict(modelID, testData.Features)
 	if err != nil {
 		return nil, &Error{
-			Code:    ErrValidationFailed,
+			Code:    ErrPredictionFailed,
 			Message: "evaluation failed",
 			Details: err.Error(),
 		}
 	}
+	y := mat.Col(nil, 0, testData.Target)
+
+	result := &TestEvaluation{
+		Task:    task,
+		Metrics: evaluation.ComputeMetrics(evaluation.MetricNames(task == TaskClassification), y, predictions),
+	}
+	if task == TaskRegression {
+		if r2, ok := result.Metrics["r2"]; ok {
+			result.Metrics["r2_score"] = r2
+		}
+		return result, nil
+	}
 
-	// 获取预测值计算更多指标
-	predictions, err := mm.internalManager.PredictModel(modelID, X)
+	classes := make([]float64, len(predictions))
+	for i, p := range predictions {
+		if p >= 0.5 {
+			classes[i] = 1
+		}
+	}
+	result.ConfusionMatrix, err = NewConfusionMatrix(y, classes)
 	if err != nil {
 		return nil, err
 	}
+	return result, nil
+}
 
-	metrics := map[string]float64{
-		"r2_score": score,
-		"mse":      mm.calculateMSE(y, predictions),
-		"mae":      mm.calculateMAE(y, predictions),
-		"rmse":     mm.calculateRMSE(y, predictions),
+// configTask 返回模型配置的任务类型
+func configTask(config *ModelConfig) string {
+	if isClassifier(config) {
+		return TaskClassification
 	}
-
-	return metrics, nil
+	return TaskRegression
 }
 
 // 辅助方法
@@ -327,17 +570,26 @@ func (mm *ModelManager) prepareData(data *TrainingData) ([][]float64, []float64)
 	return X, y
 }
 
-func (mm *ModelManager) calculatePerformanceMetrics(model *TrainedModel, modelID string, X [][]float64, y []float64) {
+// predict 使用内部管理器预测并返回切片形式的结果
+func (mm *ModelManager) predict(modelID string, X *mat.Dense) ([]float64, error) {
+	result, err := mm.internalManager.Predict(modelID, X)
+	if err != nil {
+		return nil, err
+	}
+	return result.Predictions, nil
+}
+
+// calculatePerformanceMetrics 在训练数据上计算所有适用的已注册指标（分类模型为分类指标，回归模型为回归指标）
+func (mm *ModelManager) calculatePerformanceMetrics(model *TrainedModel, modelID string, X *mat.Dense, y []float64, classifier bool) {
 	// 获取预测值
-	predictions, err := mm.internalManager.PredictModel(modelID, X)
+	predictions, err := mm.predict(modelID, X)
 	if err != nil {
 		return
 	}
 
-	// 计算各种指标
-	model.Performance["mse"] = mm.calculateMSE(y, predictions)
-	model.Performance["mae"] = mm.calculateMAE(y, predictions)
-	model.Performance["rmse"] = mm.calculateRMSE(y, predictions)
+	for name, value := range evaluation.ComputeMetrics(evaluation.MetricNames(classifier), y, predictions) {
+		model.Performance[name] = value
+	}
 }
 
 func (mm *ModelManager) generateModelSummary(model *TrainedModel, data *TrainingData) *ModelSummary {
@@ -351,41 +603,6 @@ func (mm *ModelManager) generateModelSummary(model *TrainedModel, data *Training
 	}
 }
 
-func (mm *ModelManager) calculateMSE(actual, predicted []float64) float64 {
-	if len(actual) != len(predicted) {
-		return 0
-	}
-
-	sum := 0.0
-	for i := range actual {
-		diff := actual[i] - predicted[i]
-		sum += diff * diff
-	}
-	return sum / float64(len(actual))
-}
-
-func (mm *ModelManager) calculateMAE(actual, predicted []float64) float64 {
-	if len(actual) != len(predicted) {
-		return 0
-	}
-
-	sum := 0.0
-	for i := range actual {
-		diff := actual[i] - predicted[i]
-		if diff < 0 {
-			diff = -diff
-		}
-		sum += diff
-	}
-	return sum / float64(len(actual))
-}
-
-func (mm *ModelManager) calculateRMSE(actual, predicted []float64) float64 {
-	mse := mm.calculateMSE(actual, predicted)
-	return fmt.Sprintf("%.6f", mse*mse)[0:6] // 简化的平方根计算
-	// 实际应该使用 math.Sqrt(mse)
-}
-
 func (mm *ModelManager) calculateStats(values []float64) (mean, std float64) {
 	if len(values) == 0 {
 		return 0, 0
@@ -404,7 +621,7 @@ func (mm *ModelManager) calculateStats(values []float64) (mean, std float64) {
 		diff := v - mean
 		sumSquares += diff * diff
 	}
-	std = sumSquares / float64(len(values)) // 简化的标准差计算
+	std = math.Sqrt(sumSquares / float64(len(values)))
 
 	return mean, std
 }
@@ -0,0 +1,389 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 异常值检测方法
+const (
+	OutlierIQR    = "iqr"    // 四分位距：超出[Q1-k*IQR, Q3+k*IQR]的值为异常值
+	OutlierZScore = "zscore" // z分数：|x-均值|>k*标准差的值为异常值
+)
+
+// 异常值检查的范围
+const (
+	OutlierScopeFeatures = "features" // 只检查特征列（默认）
+	OutlierScopeTarget   = "target"   // 只检查目标变量
+	OutlierScopeAll      = "all"      // 检查特征列和目标变量
+)
+
+// 判定一行是否为异常行的策略
+const (
+	OutlierPolicyAny = "any" // 任一被检查的列为异常值即移除该行（默认）
+	OutlierPolicyAll = "all" // 所有被检查的列均为异常值时才移除该行
+)
+
+// OutlierConfig 异常值移除配置
+type OutlierConfig struct {
+	Method    string   `json:"method"`            // 检测方法
+	Threshold float64  `json:"threshold"`         // iqr为四分位距的倍数，<=0时为1.5；zscore为标准差的倍数，<=0时为3
+	Scope     string   `json:"scope"`             // 检查范围，为空时为features
+	Policy    string   `json:"policy"`            // 整行判定策略，为空时为any
+	Columns   []string `json:"columns,omitempty"` // 要检查的特征列，为空时检查全部特征
+}
+
+// OutlierBounds 一列的正常值范围
+type OutlierBounds struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// OutlierRow 被移除的一行及其超出范围的列
+type OutlierRow struct {
+	Index   int      `json:"index"`   // 在原数据中的行号
+	Columns []string `json:"columns"` // 超出范围的列（目标变量以TargetName表示，为空时为"target"）
+}
+
+// OutlierReport 异常值移除报告
+type OutlierReport struct {
+	Method         string                   `json:"method"`
+	Threshold      float64                  `json:"threshold"`
+	Policy         string                   `json:"policy"`
+	TotalRows      int                      `json:"total_rows"`
+	RemovedRows    int                      `json:"removed_rows"`
+	RemovedIndices []int                    `json:"removed_indices"` // 被移除行在原数据中的行号
+	Rows           []OutlierRow             `json:"rows"`            // 每个被移除行的原因
+	ColumnCounts   map[string]int           `json:"column_counts"`   // 每列超出范围的行数
+	Bounds         map[string]OutlierBounds `json:"bounds"`          // 每列的正常值范围
+}
+
+// RemoveOutliersWithReport 按配置移除异常值所在的行，并报告移除了哪些行及原因。
+// 缺失值(NaN)不参与统计，也不会被视为异常值
+func (du *DataUtils) RemoveOutliersWithReport(data *TrainingData, config *OutlierConfig) (*TrainingData, *OutlierReport, error) {
+	if data == nil || data.Features == nil || data.Target == nil {
+		return nil, nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	if config == nil {
+		config = &OutlierConfig{Method: OutlierIQR}
+	}
+	threshold := config.Threshold
+	var boundsFor func([]float64, float64) OutlierBounds
+	switch config.Method {
+	case OutlierIQR:
+		if threshold <= 0 {
+			threshold = 1.5
+		}
+		boundsFor = iqrBounds
+	case OutlierZScore:
+		if threshold <= 0 {
+			threshold = 3
+		}
+		boundsFor = zScoreBounds
+	default:
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported outlier removal method: %s", config.Method),
+		}
+	}
+
+	policy := config.Policy
+	if policy == "" {
+		policy = OutlierPolicyAny
+	}
+	if policy != OutlierPolicyAny && policy != OutlierPolicyAll {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported outlier policy: %s", policy),
+		}
+	}
+
+	names, columns, err := outlierColumns(data, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, _ := data.Features.Dims()
+	report := &OutlierReport{
+		Method:       config.Method,
+		Threshold:    threshold,
+		Policy:       policy,
+		TotalRows:    r,
+		ColumnCounts: make(map[string]int, len(names)),
+		Bounds:       make(map[string]OutlierBounds, len(names)),
+	}
+	flagged := make([][]string, r)
+	for k, name := range names {
+		values := columns[k]
+		bounds := boundsFor(values, threshold)
+		report.Bounds[name] = bounds
+		for i, v := range values {
+			if v < bounds.Lower || v > bounds.Upper {
+				flagged[i] = append(flagged[i], name)
+				report.ColumnCounts[name]++
+			}
+		}
+	}
+
+	var kept []int
+	for i, columns := range flagged {
+		if len(columns) == 0 || (policy == OutlierPolicyAll && len(columns) < len(names)) {
+			kept = append(kept, i)
+			continue
+		}
+		report.RemovedIndices = append(report.RemovedIndices, i)
+		report.Rows = append(report.Rows, OutlierRow{Index: i, Columns: columns})
+	}
+	report.RemovedRows = len(report.RemovedIndices)
+	if len(kept) == 0 {
+		return nil, report, &Error{
+			Code:    ErrInvalidData,
+			Message: "all rows were identified as outliers",
+		}
+	}
+
+	return subsetTrainingData(data, kept), report, nil
+}
+
+// outlierColumns 按检查范围返回要检查的列名及其取值
+func outlierColumns(data *TrainingData, config *OutlierConfig) ([]string, [][]float64, error) {
+	scope := config.Scope
+	if scope == "" {
+		scope = OutlierScopeFeatures
+	}
+	if scope != OutlierScopeFeatures && scope != OutlierScopeTarget && scope != OutlierScopeAll {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported outlier scope: %s", scope),
+		}
+	}
+
+	var names []string
+	var columns [][]float64
+	if scope != OutlierScopeTarget {
+		featureNames := columnNames(data)
+		indices := make([]int, 0, len(featureNames))
+		if len(config.Columns) == 0 {
+			for j := range featureNames {
+				indices = append(indices, j)
+			}
+		} else {
+			position := make(map[string]int, len(featureNames))
+			for j, name := range featureNames {
+				position[name] = j
+			}
+			for _, column := range config.Columns {
+				j, ok := position[column]
+				if !ok {
+					return nil, nil, &Error{
+						Code:    ErrInvalidData,
+						Message: fmt.Sprintf("column %q not found", column),
+					}
+				}
+				indices = append(indices, j)
+			}
+		}
+		r, _ := data.Features.Dims()
+		for _, j := range indices {
+			values := make([]float64, r)
+			for i := range values {
+				values[i] = data.Features.At(i, j)
+			}
+			names = append(names, featureNames[j])
+			columns = append(columns, values)
+		}
+	}
+	if scope != OutlierScopeFeatures {
+		targetName := data.TargetName
+		if targetName == "" {
+			targetName = "target"
+		}
+		names = append(names, targetName)
+		columns = append(columns, append([]float64(nil), data.Target.RawVector().Data...))
+	}
+	return names, columns, nil
+}
+
+// iqrBounds 计算[Q1-k*IQR, Q3+k*IQR]，四分位数按线性插值计算，忽略NaN
+func iqrBounds(values []float64, k float64) OutlierBounds {
+	observed := observedValues(values)
+	if len(observed) == 0 {
+		return OutlierBounds{Lower: math.Inf(-1), Upper: math.Inf(1)}
+	}
+	sort.Float64s(observed)
+	q1, q3 := quantile(observed, 0.25), quantile(observed, 0.75)
+	iqr := q3 - q1
+	return OutlierBounds{Lower: q1 - k*iqr, Upper: q3 + k*iqr}
+}
+
+// zScoreBounds 计算[均值-k*标准差, 均值+k*标准差]，标准差为样本标准差，忽略NaN
+func zScoreBounds(values []float64, k float64) OutlierBounds {
+	observed := observedValues(values)
+	if len(observed) < 2 {
+		return OutlierBounds{Lower: math.Inf(-1), Upper: math.Inf(1)}
+	}
+	mean := 0.0
+	for _, v := range observed {
+		mean += v
+	}
+	mean /= float64(len(observed))
+	variance := 0.0
+	for _, v := range observed {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(variance / float64(len(observed)-1))
+	return OutlierBounds{Lower: mean - k*std, Upper: mean + k*std}
+}
+
+// observedValues 返回去掉NaN后的取值
+func observedValues(values []float64) []float64 {
+	observed := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			observed = append(observed, v)
+		}
+	}
+	return observed
+}
+
+// quantile 返回已排序数据的q分位数，在相邻两个值之间线性插值
+func quantile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	fraction := position - float64(lower)
+	return sorted[lower] + fraction*(sorted[lower+1]-sorted[lower])
+}
+
+// Winsorizer 缩尾变换器：将特征值截断到训练数据的指定分位数之间，而不是移除整行，
+// 适用于必须保留全部样本的场景，可作为流水线步骤。分位数按线性插值计算，NaN保持不变
+type Winsorizer struct {
+	Lower     float64                  `json:"lower"`   // 下分位数，取值[0, 1)，如0.05
+	Upper     float64                  `json:"upper"`   // 上分位数，取值(0, 1]，如0.95
+	Columns   []string                 `json:"columns"` // 要截断的列，为空时截断Categories中未记录的全部数值特征
+	Bounds    map[string]OutlierBounds `json:"bounds"`  // 每列学到的截断范围
+	Clipped   map[string]int           `json:"clipped"` // 最近一次Transform中每列被截断的值的个数
+	columns   []int
+	names     []string
+	nFeatures int
+}
+
+// NewWinsorizer 创建缩尾变换器，将columns（为空时为全部数值特征）截断到[lower, upper]分位数之间
+func NewWinsorizer(lower, upper float64, columns ...string) (*Winsorizer, error) {
+	w := &Winsorizer{Lower: lower, Upper: upper, Columns: columns}
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Fit 计算每列的截断范围
+func (w *Winsorizer) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if err := w.validate(); err != nil {
+		return err
+	}
+
+	names := columnNames(d)
+	columns, err := numericColumns(d, names, w.Columns)
+	if err != nil {
+		return err
+	}
+
+	r, c := d.Features.Dims()
+	bounds := make(map[string]OutlierBounds, len(columns))
+	values := make([]float64, r)
+	for _, j := range columns {
+		for i := range values {
+			values[i] = d.Features.At(i, j)
+		}
+		observed := observedValues(values)
+		if len(observed) == 0 {
+			return &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("feature %q has no observed values to compute percentiles", names[j]),
+			}
+		}
+		sort.Float64s(observed)
+		bounds[names[j]] = OutlierBounds{Lower: quantile(observed, w.Lower), Upper: quantile(observed, w.Upper)}
+	}
+
+	w.Bounds = bounds
+	w.columns = columns
+	w.names = names
+	w.nFeatures = c
+	return nil
+}
+
+// Transform 将特征值截断到学到的范围内，并在Clipped中记录每列被截断的值的个数
+func (w *Winsorizer) Transform(d *TrainingData) (*TrainingData, error) {
+	if w.Bounds == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != w.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", w.nFeatures, c),
+		}
+	}
+
+	features := mat.DenseCopyOf(d.Features)
+	w.Clipped = make(map[string]int, len(w.columns))
+	for _, j := range w.columns {
+		name := w.names[j]
+		bounds := w.Bounds[name]
+		for i := 0; i < r; i++ {
+			v := features.At(i, j)
+			switch {
+			case v < bounds.Lower:
+				features.Set(i, j, bounds.Lower)
+				w.Clipped[name]++
+			case v > bounds.Upper:
+				features.Set(i, j, bounds.Upper)
+				w.Clipped[name]++
+			}
+		}
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   d.Categories,
+	}, nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (w *Winsorizer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(w, d)
+}
+
+// validate 检查分位数范围
+func (w *Winsorizer) validate() error {
+	if w.Lower < 0 || w.Upper > 1 || w.Lower >= w.Upper {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("percentiles must satisfy 0 <= lower < upper <= 1, got lower=%g upper=%g", w.Lower, w.Upper),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package gomodel
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// TrainAllResult 并发训练中单个模型配置的结果
+type TrainAllResult struct {
+	Index   int           `json:"index"` // 配置在输入列表中的位置
+	Config  *ModelConfig  `json:"config"`
+	Result  *ModelResult  `json:"result,omitempty"` // Client.TrainAll的训练结果
+	Model   *TrainedModel `json:"model,omitempty"`  // ModelManager.TrainAll保存的模型记录
+	Err     error         `json:"-"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// TrainAll 使用有界的工作池并发训练多个模型配置，每个配置完成后立即将结果发送到返回的channel，
+// 全部完成后channel关闭。workers不大于0时使用CPU核数。
+// channel的缓冲区可容纳全部结果，调用方提前停止读取也不会阻塞训练
+func (c *Client) TrainAll(data *TrainingData, configs []*ModelConfig, workers int) <-chan *TrainAllResult {
+	return trainAll(configs, workers, func(config *ModelConfig, result *TrainAllResult) {
+		result.Result, result.Err = c.Train(data, config)
+	})
+}
+
+// TrainAll 使用有界的工作池并发训练多个模型配置并保存记录，用法与Client.TrainAll相同
+func (mm *ModelManager) TrainAll(data *TrainingData, configs []*ModelConfig, workers int) <-chan *TrainAllResult {
+	return trainAll(configs, workers, func(config *ModelConfig, result *TrainAllResult) {
+		result.Model, result.Err = mm.TrainModel(config, data)
+	})
+}
+
+// trainAll 启动工作池对每个配置调用train，并按完成顺序发送结果
+func trainAll(configs []*ModelConfig, workers int, train func(config *ModelConfig, result *TrainAllResult)) <-chan *TrainAllResult {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(configs) {
+		workers = len(configs)
+	}
+
+	results := make(chan *TrainAllResult, len(configs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := &TrainAllResult{Index: i, Config: configs[i]}
+				start := time.Now()
+				if configs[i] == nil {
+					result.Err = &Error{
+						Code:    ErrInvalidParameters,
+						Message: "model config cannot be nil",
+					}
+				} else {
+					train(configs[i], result)
+				}
+				result.Elapsed = time.Since(start)
+				results <- result
+			}
+		}()
+	}
+
+	go func() {
+		for i := range configs {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
@@ -0,0 +1,354 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"gonum.org/v1/gonum/mat"
+)
+
+// PipelineStep 流水线中的一个命名变换步骤
+type PipelineStep struct {
+	Name        string      `json:"name"`
+	Transformer Transformer `json:"-"`
+}
+
+// Pipeline 由若干变换步骤和末端模型组成的流水线。
+// 训练时各步骤依次在训练数据上拟合，预测时使用训练时学到的参数变换新数据，
+// 避免手动维护缩放器与模型的对应关系，也避免交叉验证时验证折的信息泄露到预处理中
+type Pipeline struct {
+	Steps             []PipelineStep    `json:"steps"`
+	Config            *ModelConfig      `json:"config"`
+	TargetTransformer TargetTransformer `json:"-"` // 可选的目标变量变换，如对偏态目标使用PowerTransformer
+	model             models.Model
+	fitted            bool
+}
+
+// NewPipeline 创建新的流水线，config为末端模型的配置
+func NewPipeline(config *ModelConfig, steps ...PipelineStep) *Pipeline {
+	return &Pipeline{
+		Steps:  steps,
+		Config: config,
+	}
+}
+
+// Fit 依次拟合并应用各变换步骤，然后在变换后的数据上训练模型
+func (p *Pipeline) Fit(data *TrainingData) error {
+	if data == nil || data.Features == nil || data.Target == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	if p.Config == nil {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "model config cannot be nil",
+		}
+	}
+
+	p.fitted = false
+	transformed := data
+	if p.TargetTransformer != nil {
+		var err error
+		if transformed, err = p.fitTarget(data); err != nil {
+			return err
+		}
+	}
+	for _, step := range p.Steps {
+		var err error
+		if transformed, err = step.Transformer.FitTransform(transformed); err != nil {
+			return p.stepError(step, "fit", err)
+		}
+	}
+
+	model, err := models.NewModelManager().CreateModel(toInternalConfig(p.Config))
+	if err != nil {
+		return &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: "failed to create model",
+			Details: err.Error(),
+		}
+	}
+	if err := model.FitWeighted(transformed.Features, transformed.Target, transformed.Weights); err != nil {
+		return &Error{
+			Code:    ErrTrainingFailed,
+			Message: "failed to train model",
+			Details: err.Error(),
+		}
+	}
+
+	p.model = model
+	p.fitted = true
+	return nil
+}
+
+// Predict 使用训练时学到的变换参数处理特征并进行预测，设置了TargetTransformer时预测值还原为原始尺度
+func (p *Pipeline) Predict(features *mat.Dense) ([]float64, error) {
+	transformed, err := p.transform(&TrainingData{Features: features})
+	if err != nil {
+		return nil, err
+	}
+	return p.predictTransformed(transformed)
+}
+
+// Score 在给定数据上计算模型评分（回归为R²，分类为准确率）；
+// 设置了TargetTransformer时在原始尺度上计算R²
+func (p *Pipeline) Score(data *TrainingData) (float64, error) {
+	if data == nil || data.Target == nil {
+		return 0, &Error{
+			Code:    ErrInvalidData,
+			Message: "target cannot be nil",
+		}
+	}
+
+	transformed, err := p.transform(data)
+	if err != nil {
+		return 0, err
+	}
+	if p.TargetTransformer == nil {
+		return p.model.Score(transformed.Features, data.Target), nil
+	}
+
+	predictions, err := p.predictTransformed(transformed)
+	if err != nil {
+		return 0, err
+	}
+	score, err := evaluation.R2Score(data.Target.RawVector().Data, predictions)
+	if err != nil {
+		return 0, &Error{
+			Code:    ErrValidationFailed,
+			Message: "failed to score pipeline",
+			Details: err.Error(),
+		}
+	}
+	return score, nil
+}
+
+// predictTransformed 对已变换的特征进行预测，并按TargetTransformer逆变换预测值
+func (p *Pipeline) predictTransformed(transformed *TrainingData) ([]float64, error) {
+	predictions := p.model.Predict(transformed.Features)
+	result := make([]float64, predictions.Len())
+	for i := range result {
+		result[i] = predictions.AtVec(i)
+	}
+	if p.TargetTransformer == nil {
+		return result, nil
+	}
+
+	result, err := p.TargetTransformer.InverseTransformTarget(result)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrPredictionFailed,
+			Message: "target transformer failed to inverse transform predictions",
+			Details: err.Error(),
+		}
+	}
+	return result, nil
+}
+
+// fitTarget 拟合目标变量变换器，返回目标变量被替换为变换后取值的训练数据
+func (p *Pipeline) fitTarget(data *TrainingData) (*TrainingData, error) {
+	y := data.Target.RawVector().Data
+	if err := p.TargetTransformer.FitTarget(y); err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "target transformer failed to fit",
+			Details: err.Error(),
+		}
+	}
+	transformed, err := p.TargetTransformer.TransformTarget(y)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "target transformer failed to transform",
+			Details: err.Error(),
+		}
+	}
+
+	return &TrainingData{
+		Features:     data.Features,
+		Target:       mat.NewVecDense(len(transformed), transformed),
+		Weights:      data.Weights,
+		FeatureNames: data.FeatureNames,
+		TargetName:   data.TargetName,
+		Categories:   data.Categories,
+	}, nil
+}
+
+// CrossValidate 执行K折交叉验证，每折的变换步骤只在该折的训练部分上拟合；
+// 完成后流水线在全部数据上重新拟合
+func (p *Pipeline) CrossValidate(data *TrainingData, folds int, randomSeed int64) (*CVResult, error) {
+	if data == nil || data.Features == nil || data.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	n, _ := data.Features.Dims()
+	if folds < 2 || folds > n {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("folds must be between 2 and %d", n),
+		}
+	}
+
+	scores, err := p.foldScores(data, randomFolds(n, folds, randomSeed))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.Fit(data); err != nil {
+		return nil, err
+	}
+	return cvResult(scores), nil
+}
+
+// CrossValidateSplits 按划分器（如TimeSeriesSplit）给出的各折执行交叉验证，每折的变换步骤只在该折的训练部分上拟合；
+// 完成后流水线在全部数据上重新拟合
+func (p *Pipeline) CrossValidateSplits(data *TrainingData, splitter Splitter) (*CVResult, error) {
+	if data == nil || data.Features == nil || data.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	if splitter == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "splitter cannot be nil",
+		}
+	}
+	folds, err := splitter.Split(data)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := p.foldScores(data, folds)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Fit(data); err != nil {
+		return nil, err
+	}
+	return cvResult(scores), nil
+}
+
+// cvResult 汇总各折得分
+func cvResult(scores []float64) *CVResult {
+	mean := 0.0
+	for _, s := range scores {
+		mean += s
+	}
+	mean /= float64(len(scores))
+	variance := 0.0
+	for _, s := range scores {
+		variance += (s - mean) * (s - mean)
+	}
+
+	return &CVResult{
+		Scores:    scores,
+		MeanScore: mean,
+		StdScore:  math.Sqrt(variance / float64(len(scores))),
+		FoldCount: len(scores),
+	}
+}
+
+// randomFolds 将n个样本随机划分为K折
+func randomFolds(n, folds int, randomSeed int64) []Fold {
+	perm := rand.New(rand.NewSource(randomSeed)).Perm(n)
+	result := make([]Fold, folds)
+	for k := 0; k < folds; k++ {
+		start, end := k*n/folds, (k+1)*n/folds
+		result[k] = Fold{
+			Train: append(append([]int(nil), perm[:start]...), perm[end:]...),
+			Test:  perm[start:end],
+		}
+	}
+	return result
+}
+
+// foldScores 依次在每折的训练部分上拟合流水线，返回每折验证部分的得分
+func (p *Pipeline) foldScores(data *TrainingData, folds []Fold) ([]float64, error) {
+	scores := make([]float64, len(folds))
+	for k, fold := range folds {
+		if err := p.Fit(subsetTrainingData(data, fold.Train)); err != nil {
+			return nil, err
+		}
+		score, err := p.Score(subsetTrainingData(data, fold.Test))
+		if err != nil {
+			return nil, err
+		}
+		scores[k] = score
+	}
+	return scores, nil
+}
+
+// IsFitted 返回流水线是否已训练
+func (p *Pipeline) IsFitted() bool {
+	return p.fitted
+}
+
+// transform 依次应用各变换步骤（不重新拟合）
+func (p *Pipeline) transform(data *TrainingData) (*TrainingData, error) {
+	if !p.fitted {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "pipeline is not fitted",
+		}
+	}
+	if data == nil || data.Features == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+
+	transformed := data
+	for _, step := range p.Steps {
+		var err error
+		if transformed, err = step.Transformer.Transform(transformed); err != nil {
+			return nil, p.stepError(step, "transform", err)
+		}
+	}
+	return transformed, nil
+}
+
+// stepError 包装变换步骤返回的错误
+func (p *Pipeline) stepError(step PipelineStep, action string, err error) error {
+	return &Error{
+		Code:    ErrInvalidData,
+		Message: fmt.Sprintf("pipeline step %q failed to %s", step.Name, action),
+		Details: err.Error(),
+	}
+}
+
+// subsetTrainingData 按样本索引提取训练数据子集
+func subsetTrainingData(data *TrainingData, indices []int) *TrainingData {
+	_, cols := data.Features.Dims()
+	features := mat.NewDense(len(indices), cols, nil)
+	target := mat.NewVecDense(len(indices), nil)
+	var weights *mat.VecDense
+	if data.Weights != nil {
+		weights = mat.NewVecDense(len(indices), nil)
+	}
+	for i, idx := range indices {
+		features.SetRow(i, mat.Row(nil, idx, data.Features))
+		target.SetVec(i, data.Target.AtVec(idx))
+		if weights != nil {
+			weights.SetVec(i, data.Weights.AtVec(idx))
+		}
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       target,
+		Weights:      weights,
+		FeatureNames: data.FeatureNames,
+		TargetName:   data.TargetName,
+		Categories:   data.Categories,
+	}
+}
@@ -0,0 +1,154 @@
+package gomodel
+
+import (
+	"fmt"
+)
+
+// PipelineSearchResult 流水线网格搜索结果
+type PipelineSearchResult struct {
+	*TuningResult
+	BestPipeline *Pipeline `json:"best_pipeline"` // 使用最佳参数组合并在全部数据上重新拟合的流水线
+}
+
+// PipelineGridSearch 对流水线的变换步骤和末端模型参数联合执行网格搜索，
+// 使预处理的超参数（如缩放方式、多项式次数）与模型参数（如lambda）一起调优。
+// 网格中与某个步骤同名的键表示替换该步骤，取值为Transformer，nil表示跳过该步骤；
+// 其余键作为末端模型的参数。每个参数组合都在交叉验证的每一折上重新拟合整个流水线，
+// 预处理不会看到验证折的数据。validation为nil时使用客户端的默认验证配置
+func (c *Client) PipelineGridSearch(data *TrainingData, pipeline *Pipeline, grid ParamGrid, validation *ValidationConfig) (*PipelineSearchResult, error) {
+	if pipeline == nil || pipeline.Config == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "pipeline and its model config cannot be nil",
+		}
+	}
+	if err := c.validateSearchInput(data, pipeline.Config); err != nil {
+		return nil, err
+	}
+
+	candidates, err := expandGrid(grid)
+	if err != nil {
+		return nil, err
+	}
+	validation = c.searchValidation(validation)
+
+	results := make([]*SearchResult, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidatePipeline, err := pipeline.withCandidate(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.validateAlgorithmParameters(candidatePipeline.Config.Algorithm, candidatePipeline.Config.Parameters); err != nil {
+			return nil, err
+		}
+
+		scores, err := pipelineScores(candidatePipeline, data, validation)
+		if err != nil {
+			return nil, &Error{
+				Code:    ErrValidationFailed,
+				Message: fmt.Sprintf("failed to evaluate parameters %v", candidate),
+				Details: err.Error(),
+			}
+		}
+
+		parameters := make(map[string]interface{}, len(candidatePipeline.Config.Parameters)+len(candidate))
+		for key, value := range candidatePipeline.Config.Parameters {
+			parameters[key] = value
+		}
+		for key, value := range candidate {
+			parameters[key] = value
+		}
+		mean, std := c.calculateStats(scores)
+		results = append(results, &SearchResult{
+			Parameters: parameters,
+			Scores:     scores,
+			MeanScore:  mean,
+			StdScore:   std,
+		})
+	}
+
+	tuning := rankResults(pipeline.Config, results)
+	best, err := pipeline.withCandidate(tuning.BestParameters)
+	if err != nil {
+		return nil, err
+	}
+	tuning.BestConfig = best.Config
+	if err := best.Fit(data); err != nil {
+		return nil, err
+	}
+
+	return &PipelineSearchResult{
+		TuningResult: tuning,
+		BestPipeline: best,
+	}, nil
+}
+
+// withCandidate 按参数组合构造新的流水线：与步骤同名的参数替换该步骤（nil表示移除），其余参数覆盖模型参数
+func (p *Pipeline) withCandidate(candidate map[string]interface{}) (*Pipeline, error) {
+	steps := make([]PipelineStep, 0, len(p.Steps))
+	isStep := make(map[string]bool, len(p.Steps))
+	for _, step := range p.Steps {
+		isStep[step.Name] = true
+		value, ok := candidate[step.Name]
+		if !ok {
+			steps = append(steps, step)
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		transformer, ok := value.(Transformer)
+		if !ok {
+			return nil, &Error{
+				Code:    ErrInvalidParameters,
+				Message: fmt.Sprintf("value for pipeline step %q must be a Transformer or nil, got %T", step.Name, value),
+			}
+		}
+		steps = append(steps, PipelineStep{Name: step.Name, Transformer: transformer})
+	}
+
+	modelParams := make(map[string]interface{}, len(candidate))
+	for key, value := range candidate {
+		if !isStep[key] {
+			modelParams[key] = value
+		}
+	}
+
+	candidatePipeline := NewPipeline(withParameters(p.Config, modelParams), steps...)
+	candidatePipeline.TargetTransformer = p.TargetTransformer
+	return candidatePipeline, nil
+}
+
+// pipelineScores 按验证配置评估流水线，holdout返回单个得分，kfold返回每折得分
+func pipelineScores(p *Pipeline, data *TrainingData, validation *ValidationConfig) ([]float64, error) {
+	switch validation.Method {
+	case "holdout":
+		trainData, testData, err := splitHoldout(data, validation)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.Fit(trainData); err != nil {
+			return nil, err
+		}
+		score, err := p.Score(testData)
+		if err != nil {
+			return nil, err
+		}
+		return []float64{score}, nil
+	case "kfold":
+		if validation.Splitter != nil {
+			folds, err := validation.Splitter.Split(data)
+			if err != nil {
+				return nil, err
+			}
+			return p.foldScores(data, folds)
+		}
+		n, _ := data.Features.Dims()
+		if validation.KFolds < 2 || validation.KFolds > n {
+			return nil, fmt.Errorf("folds must be between 2 and %d", n)
+		}
+		return p.foldScores(data, randomFolds(n, validation.KFolds, validation.RandomSeed))
+	default:
+		return nil, fmt.Errorf("unsupported validation method: %s", validation.Method)
+	}
+}
@@ -0,0 +1,91 @@
+package gomodel
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPipelineGridSearch(t *testing.T) {
+	data := ridgeData(120)
+	pipeline := NewPipeline(&ModelConfig{Algorithm: Ridge}, PipelineStep{Name: "scale", Transformer: NewMinMaxScaler()})
+	// 第三个特征的尺度很小，不标准化时正则化几乎把它的系数压为0，最优组合为标准化加最小的lambda
+	grid := ParamGrid{
+		"scale":  {NewStandardScaler(), nil},
+		"lambda": {10.0, 0.01, 1000.0},
+	}
+	validation := &ValidationConfig{Method: "kfold", KFolds: 3, RandomSeed: 1}
+
+	result, err := searchClient(1).PipelineGridSearch(data, pipeline, grid, validation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Results) != 6 {
+		t.Fatalf("got %d results, want 6", len(result.Results))
+	}
+	if _, scaled := result.BestParameters["scale"].(*StandardScaler); !scaled || result.BestParameters["lambda"] != 0.01 {
+		t.Errorf("best parameters = %v, want the standard scaler with lambda 0.01", result.BestParameters)
+	}
+	if result.BestConfig.Parameters["lambda"] != 0.01 {
+		t.Errorf("best config lambda = %v, want 0.01", result.BestConfig.Parameters["lambda"])
+	}
+
+	// 同一lambda下标准化总是优于跳过该步骤
+	scores := make(map[float64]map[bool]float64)
+	for i, r := range result.Results {
+		if r.Rank != i+1 || (i > 0 && r.MeanScore > result.Results[i-1].MeanScore) {
+			t.Errorf("result %d: rank %d, score %g out of order", i, r.Rank, r.MeanScore)
+		}
+		lambda := r.Parameters["lambda"].(float64)
+		if scores[lambda] == nil {
+			scores[lambda] = make(map[bool]float64)
+		}
+		scores[lambda][r.Parameters["scale"] != nil] = r.MeanScore
+	}
+	for lambda, s := range scores {
+		if s[true] <= s[false] {
+			t.Errorf("lambda %g: scaled score %g, unscaled %g, want scaling to help", lambda, s[true], s[false])
+		}
+	}
+
+	// 最佳流水线已在全部数据上拟合，且使用网格中的变换步骤
+	if !result.BestPipeline.IsFitted() || len(result.BestPipeline.Steps) != 1 {
+		t.Fatalf("best pipeline fitted = %v with %d steps", result.BestPipeline.IsFitted(), len(result.BestPipeline.Steps))
+	}
+	if _, ok := result.BestPipeline.Steps[0].Transformer.(*StandardScaler); !ok {
+		t.Errorf("best pipeline step = %T, want *StandardScaler", result.BestPipeline.Steps[0].Transformer)
+	}
+	predictions, err := result.BestPipeline.Predict(data.Features)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range predictions {
+		if math.Abs(p-data.Target.AtVec(i)) > 0.5 {
+			t.Fatalf("prediction %d = %g, target %g", i, p, data.Target.AtVec(i))
+		}
+	}
+	// 原流水线不被修改
+	if _, ok := pipeline.Steps[0].Transformer.(*MinMaxScaler); !ok || pipeline.IsFitted() {
+		t.Error("the input pipeline was modified")
+	}
+
+	same, err := searchClient(1).PipelineGridSearch(data, pipeline, grid, validation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range result.Results {
+		if result.Results[i].MeanScore != same.Results[i].MeanScore {
+			t.Errorf("result %d: score %g, then %g with the same seed", i, result.Results[i].MeanScore, same.Results[i].MeanScore)
+		}
+	}
+}
+
+func TestPipelineGridSearchInvalidStep(t *testing.T) {
+	data := ridgeData(60)
+	pipeline := NewPipeline(&ModelConfig{Algorithm: Ridge}, PipelineStep{Name: "scale", Transformer: NewStandardScaler()})
+	if _, err := searchClient(1).PipelineGridSearch(data, pipeline, ParamGrid{"scale": {"standard"}}, nil); err == nil {
+		t.Error("expected an error for a step value that is not a Transformer")
+	}
+	if _, err := searchClient(1).PipelineGridSearch(data, nil, ParamGrid{"lambda": {1.0}}, nil); err == nil {
+		t.Error("expected an error for a nil pipeline")
+	}
+}
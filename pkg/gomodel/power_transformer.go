@@ -0,0 +1,394 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// 幂变换方法
+const (
+	PowerYeoJohnson = "yeo-johnson" // 适用于任意实数
+	PowerBoxCox     = "box-cox"     // 只适用于正数
+)
+
+// powerLambdaRange 估计lambda时的搜索区间
+const powerLambdaRange = 5.0
+
+// PowerParams 一列的幂变换参数
+type PowerParams struct {
+	Lambda float64 `json:"lambda"`
+	Mean   float64 `json:"mean"` // 变换后的均值，Standardize为false时为0
+	Std    float64 `json:"std"`  // 变换后的标准差，Standardize为false时为1
+}
+
+// PowerTransformer 幂变换器，按列以最大似然估计最优lambda，使偏态分布的特征更接近正态分布，可作为流水线步骤。
+// 也实现了TargetTransformer，可设置为Pipeline.TargetTransformer变换偏态的目标变量，预测值自动逆变换回原始尺度。
+// NaN不参与估计，变换后仍为NaN
+type PowerTransformer struct {
+	Method      string                 `json:"method"`      // yeo-johnson（默认）或box-cox
+	Standardize bool                   `json:"standardize"` // 变换后是否标准化为零均值、单位方差
+	Columns     []string               `json:"columns"`     // 要变换的列，为空时变换Categories中未记录的全部数值特征
+	Params      map[string]PowerParams `json:"params"`      // 每列学到的参数
+	Target      *PowerParams           `json:"target"`      // 目标变量的参数，由FitTarget学习
+	columns     []int
+	names       []string
+	nFeatures   int
+}
+
+// NewPowerTransformer 创建幂变换器，变换后标准化，columns为空时变换全部数值特征
+func NewPowerTransformer(method string, columns ...string) (*PowerTransformer, error) {
+	if err := validatePowerMethod(method); err != nil {
+		return nil, err
+	}
+	return &PowerTransformer{Method: method, Standardize: true, Columns: columns}, nil
+}
+
+// Fit 为每列估计lambda及变换后的均值和标准差
+func (pt *PowerTransformer) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if err := validatePowerMethod(pt.method()); err != nil {
+		return err
+	}
+
+	names := columnNames(d)
+	columns, err := numericColumns(d, names, pt.Columns)
+	if err != nil {
+		return err
+	}
+
+	r, c := d.Features.Dims()
+	params := make(map[string]PowerParams, len(columns))
+	values := make([]float64, r)
+	for _, j := range columns {
+		for i := range values {
+			values[i] = d.Features.At(i, j)
+		}
+		p, err := fitPowerParams(values, pt.method(), pt.Standardize)
+		if err != nil {
+			return &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("failed to fit power transform for feature %q", names[j]),
+				Details: err.Error(),
+			}
+		}
+		params[names[j]] = p
+	}
+
+	pt.Params = params
+	pt.columns = columns
+	pt.names = names
+	pt.nFeatures = c
+	return nil
+}
+
+// Transform 使用学到的lambda变换特征
+func (pt *PowerTransformer) Transform(d *TrainingData) (*TrainingData, error) {
+	return pt.apply(d, false)
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (pt *PowerTransformer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(pt, d)
+}
+
+// InverseTransform 将变换后的特征还原为原始尺度
+func (pt *PowerTransformer) InverseTransform(d *TrainingData) (*TrainingData, error) {
+	return pt.apply(d, true)
+}
+
+// FitTarget 为目标变量估计lambda
+func (pt *PowerTransformer) FitTarget(y []float64) error {
+	if err := validatePowerMethod(pt.method()); err != nil {
+		return err
+	}
+	p, err := fitPowerParams(y, pt.method(), pt.Standardize)
+	if err != nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to fit power transform for target",
+			Details: err.Error(),
+		}
+	}
+	pt.Target = &p
+	return nil
+}
+
+// TransformTarget 使用学到的lambda变换目标变量
+func (pt *PowerTransformer) TransformTarget(y []float64) ([]float64, error) {
+	if pt.Target == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "target transformer is not fitted",
+		}
+	}
+	result := make([]float64, len(y))
+	for i, v := range y {
+		t, err := powerTransform(v, pt.Target.Lambda, pt.method())
+		if err != nil {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("cannot transform target at row %d", i),
+				Details: err.Error(),
+			}
+		}
+		result[i] = (t - pt.Target.Mean) / pt.Target.Std
+	}
+	return result, nil
+}
+
+// InverseTransformTarget 将变换尺度上的预测值还原为目标变量的原始尺度
+func (pt *PowerTransformer) InverseTransformTarget(y []float64) ([]float64, error) {
+	if pt.Target == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "target transformer is not fitted",
+		}
+	}
+	result := make([]float64, len(y))
+	for i, v := range y {
+		result[i] = inversePowerTransform(v*pt.Target.Std+pt.Target.Mean, pt.Target.Lambda, pt.method())
+	}
+	return result, nil
+}
+
+// apply 对各列执行正向或逆向变换
+func (pt *PowerTransformer) apply(d *TrainingData, inverse bool) (*TrainingData, error) {
+	if pt.Params == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != pt.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", pt.nFeatures, c),
+		}
+	}
+
+	features := mat.DenseCopyOf(d.Features)
+	for _, j := range pt.columns {
+		p := pt.Params[pt.names[j]]
+		for i := 0; i < r; i++ {
+			v := features.At(i, j)
+			if inverse {
+				features.Set(i, j, inversePowerTransform(v*p.Std+p.Mean, p.Lambda, pt.method()))
+				continue
+			}
+			t, err := powerTransform(v, p.Lambda, pt.method())
+			if err != nil {
+				return nil, &Error{
+					Code:    ErrInvalidData,
+					Message: fmt.Sprintf("cannot transform feature %q at row %d", pt.names[j], i),
+					Details: err.Error(),
+				}
+			}
+			features.Set(i, j, (t-p.Mean)/p.Std)
+		}
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   d.Categories,
+	}, nil
+}
+
+// method 返回变换方法，为空时为yeo-johnson
+func (pt *PowerTransformer) method() string {
+	if pt.Method == "" {
+		return PowerYeoJohnson
+	}
+	return pt.Method
+}
+
+// validatePowerMethod 检查幂变换方法是否受支持
+func validatePowerMethod(method string) error {
+	switch method {
+	case "", PowerYeoJohnson, PowerBoxCox:
+		return nil
+	}
+	return &Error{
+		Code:    ErrInvalidParameters,
+		Message: fmt.Sprintf("unsupported power transform method: %s", method),
+	}
+}
+
+// numericColumns 返回要处理的列索引，columns为空时返回Categories中未记录的全部列
+func numericColumns(d *TrainingData, names, columns []string) ([]int, error) {
+	var indices []int
+	if len(columns) == 0 {
+		for j, name := range names {
+			if _, categorical := d.Categories[name]; !categorical {
+				indices = append(indices, j)
+			}
+		}
+		return indices, nil
+	}
+
+	position := make(map[string]int, len(names))
+	for j, name := range names {
+		position[name] = j
+	}
+	for _, column := range columns {
+		j, ok := position[column]
+		if !ok {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("column %q not found", column),
+			}
+		}
+		indices = append(indices, j)
+	}
+	return indices, nil
+}
+
+// fitPowerParams 以最大似然估计lambda，并计算变换后的均值和标准差
+func fitPowerParams(values []float64, method string, standardize bool) (PowerParams, error) {
+	observed := observedValues(values)
+	if len(observed) < 2 {
+		return PowerParams{}, fmt.Errorf("at least 2 observed values are required, got %d", len(observed))
+	}
+	if method == PowerBoxCox {
+		for _, v := range observed {
+			if v <= 0 {
+				return PowerParams{}, fmt.Errorf("box-cox requires strictly positive values, got %g", v)
+			}
+		}
+	}
+
+	lambda := goldenSectionMax(func(lambda float64) float64 {
+		return powerLogLikelihood(observed, lambda, method)
+	}, -powerLambdaRange, powerLambdaRange)
+
+	params := PowerParams{Lambda: lambda, Mean: 0, Std: 1}
+	if standardize {
+		transformed := make([]float64, len(observed))
+		for i, v := range observed {
+			transformed[i], _ = powerTransform(v, lambda, method)
+		}
+		mean, variance := meanVariance(transformed)
+		params.Mean = mean
+		if variance > 0 {
+			params.Std = math.Sqrt(variance)
+		}
+	}
+	return params, nil
+}
+
+// powerLogLikelihood 变换后服从正态分布时关于lambda的对数似然（省略常数项）
+func powerLogLikelihood(values []float64, lambda float64, method string) float64 {
+	transformed := make([]float64, len(values))
+	jacobian := 0.0
+	for i, v := range values {
+		transformed[i], _ = powerTransform(v, lambda, method)
+		if method == PowerBoxCox {
+			jacobian += math.Log(v)
+		} else {
+			jacobian += math.Copysign(math.Log1p(math.Abs(v)), v)
+		}
+	}
+	_, variance := meanVariance(transformed)
+	if variance <= 0 || math.IsNaN(variance) || math.IsInf(variance, 0) {
+		return math.Inf(-1)
+	}
+	n := float64(len(values))
+	return -n/2*math.Log(variance) + (lambda-1)*jacobian
+}
+
+// powerTransform 对单个值执行幂变换，NaN保持不变
+func powerTransform(x, lambda float64, method string) (float64, error) {
+	if math.IsNaN(x) {
+		return x, nil
+	}
+	if method == PowerBoxCox {
+		if x <= 0 {
+			return 0, fmt.Errorf("box-cox requires strictly positive values, got %g", x)
+		}
+		if math.Abs(lambda) < 1e-8 {
+			return math.Log(x), nil
+		}
+		return (math.Pow(x, lambda) - 1) / lambda, nil
+	}
+
+	if x >= 0 {
+		if math.Abs(lambda) < 1e-8 {
+			return math.Log1p(x), nil
+		}
+		return (math.Pow(x+1, lambda) - 1) / lambda, nil
+	}
+	if math.Abs(lambda-2) < 1e-8 {
+		return -math.Log1p(-x), nil
+	}
+	return -(math.Pow(1-x, 2-lambda) - 1) / (2 - lambda), nil
+}
+
+// inversePowerTransform powerTransform的逆变换
+func inversePowerTransform(y, lambda float64, method string) float64 {
+	if math.IsNaN(y) {
+		return y
+	}
+	if method == PowerBoxCox {
+		if math.Abs(lambda) < 1e-8 {
+			return math.Exp(y)
+		}
+		return math.Pow(lambda*y+1, 1/lambda)
+	}
+
+	if y >= 0 {
+		if math.Abs(lambda) < 1e-8 {
+			return math.Expm1(y)
+		}
+		return math.Pow(lambda*y+1, 1/lambda) - 1
+	}
+	if math.Abs(lambda-2) < 1e-8 {
+		return -math.Expm1(-y)
+	}
+	return 1 - math.Pow(1-(2-lambda)*y, 1/(2-lambda))
+}
+
+// meanVariance 返回均值和总体方差
+func meanVariance(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, variance / float64(len(values))
+}
+
+// goldenSectionMax 用黄金分割法在[lower, upper]上求单峰函数的最大值点
+func goldenSectionMax(f func(float64) float64, lower, upper float64) float64 {
+	ratio := (math.Sqrt(5) - 1) / 2
+	a, b := lower, upper
+	c := b - ratio*(b-a)
+	d := a + ratio*(b-a)
+	fc, fd := f(c), f(d)
+	for b-a > 1e-9 {
+		if fc > fd {
+			b, d, fd = d, c, fc
+			c = b - ratio*(b-a)
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + ratio*(b-a)
+			fd = f(d)
+		}
+	}
+	return (a + b) / 2
+}
@@ -0,0 +1,197 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// 分位数变换的输出分布
+const (
+	QuantileUniform = "uniform" // 映射到[0, 1]上的均匀分布
+	QuantileNormal  = "normal"  // 映射到标准正态分布
+)
+
+// quantileClip 输出为正态分布时累积概率的截断范围，避免0和1映射为无穷大
+const quantileClip = 1e-7
+
+// QuantileTransformer 分位数变换器：用训练数据的经验分位数将每个特征映射到均匀分布或标准正态分布，
+// 可作为流水线步骤。非线性的单调变换能消除异常值的影响并使任意分布的特征可比。
+// 学到的分位数保存在Quantiles中，测试数据按相同的分位数映射，超出训练范围的值映射到分布的边界。NaN保持不变
+type QuantileTransformer struct {
+	NQuantiles int                  `json:"n_quantiles"` // 分位数个数，默认1000，不超过训练样本数
+	Output     string               `json:"output"`      // uniform（为空时）或normal
+	Columns    []string             `json:"columns"`     // 要变换的列，为空时变换Categories中未记录的全部数值特征
+	Quantiles  map[string][]float64 `json:"quantiles"`   // 每列在等间隔累积概率上的分位数
+	columns    []int
+	names      []string
+	nFeatures  int
+}
+
+// NewQuantileTransformer 创建分位数变换器，使用1000个分位数，columns为空时变换全部数值特征
+func NewQuantileTransformer(output string, columns ...string) (*QuantileTransformer, error) {
+	if err := validateQuantileOutput(output); err != nil {
+		return nil, err
+	}
+	return &QuantileTransformer{NQuantiles: 1000, Output: output, Columns: columns}, nil
+}
+
+// Fit 计算每列的经验分位数
+func (qt *QuantileTransformer) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if err := validateQuantileOutput(qt.Output); err != nil {
+		return err
+	}
+	if qt.NQuantiles < 0 || qt.NQuantiles == 1 {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("n_quantiles must be at least 2, got %d", qt.NQuantiles),
+		}
+	}
+
+	names := columnNames(d)
+	columns, err := numericColumns(d, names, qt.Columns)
+	if err != nil {
+		return err
+	}
+
+	r, c := d.Features.Dims()
+	quantiles := make(map[string][]float64, len(columns))
+	values := make([]float64, r)
+	for _, j := range columns {
+		for i := range values {
+			values[i] = d.Features.At(i, j)
+		}
+		observed := observedValues(values)
+		if len(observed) < 2 {
+			return &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("feature %q needs at least 2 observed values to compute quantiles", names[j]),
+			}
+		}
+		sort.Float64s(observed)
+
+		n := qt.NQuantiles
+		if n == 0 {
+			n = 1000
+		}
+		n = min(n, len(observed))
+		references := make([]float64, n)
+		for k := range references {
+			references[k] = quantile(observed, float64(k)/float64(n-1))
+		}
+		quantiles[names[j]] = references
+	}
+
+	qt.Quantiles = quantiles
+	qt.columns = columns
+	qt.names = names
+	qt.nFeatures = c
+	return nil
+}
+
+// Transform 将特征映射到目标分布
+func (qt *QuantileTransformer) Transform(d *TrainingData) (*TrainingData, error) {
+	return qt.apply(d, false)
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (qt *QuantileTransformer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(qt, d)
+}
+
+// InverseTransform 将目标分布上的值按分位数还原为原始尺度
+func (qt *QuantileTransformer) InverseTransform(d *TrainingData) (*TrainingData, error) {
+	return qt.apply(d, true)
+}
+
+// apply 对各列执行正向或逆向变换
+func (qt *QuantileTransformer) apply(d *TrainingData, inverse bool) (*TrainingData, error) {
+	if qt.Quantiles == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	r, c := d.Features.Dims()
+	if c != qt.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", qt.nFeatures, c),
+		}
+	}
+
+	normal := distuv.UnitNormal
+	features := mat.DenseCopyOf(d.Features)
+	for _, j := range qt.columns {
+		references := qt.Quantiles[qt.names[j]]
+		for i := 0; i < r; i++ {
+			v := features.At(i, j)
+			if math.IsNaN(v) {
+				continue
+			}
+			if inverse {
+				p := v
+				if qt.Output == QuantileNormal {
+					p = normal.CDF(v)
+				}
+				features.Set(i, j, quantile(references, math.Max(0, math.Min(1, p))))
+				continue
+			}
+			p := empiricalCDF(references, v)
+			if qt.Output == QuantileNormal {
+				p = normal.Quantile(math.Max(quantileClip, math.Min(1-quantileClip, p)))
+			}
+			features.Set(i, j, p)
+		}
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       d.Target,
+		Weights:      d.Weights,
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   d.Categories,
+	}, nil
+}
+
+// empiricalCDF 按分位数表在相邻分位数之间线性插值得到x的累积概率；
+// x等于若干个相同的分位数时取这些位置的中点，使离散特征的重复值映射到同一概率
+func empiricalCDF(references []float64, x float64) float64 {
+	n := len(references)
+	if x < references[0] {
+		return 0
+	}
+	if x > references[n-1] {
+		return 1
+	}
+
+	lower := sort.SearchFloat64s(references, x)
+	if references[lower] == x {
+		upper := sort.Search(n, func(k int) bool { return references[k] > x }) - 1
+		return float64(lower+upper) / 2 / float64(n-1)
+	}
+	fraction := (x - references[lower-1]) / (references[lower] - references[lower-1])
+	return (float64(lower-1) + fraction) / float64(n-1)
+}
+
+// validateQuantileOutput 检查输出分布是否受支持
+func validateQuantileOutput(output string) error {
+	switch output {
+	case "", QuantileUniform, QuantileNormal:
+		return nil
+	}
+	return &Error{
+		Code:    ErrInvalidParameters,
+		Message: fmt.Sprintf("unsupported quantile output distribution: %s", output),
+	}
+}
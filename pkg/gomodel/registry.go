@@ -0,0 +1,377 @@
+package gomodel
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"gonum.org/v1/gonum/mat"
+)
+
+// 模型仓库中每个模型目录下的文件
+const (
+	registryMetadataFile = "metadata.json"
+	registryArtifactFile = "model.bin"
+)
+
+// RegistryEntry 模型仓库中一个模型的元数据
+type RegistryEntry struct {
+	ID           string                 `json:"id"`
+	Algorithm    AlgorithmType          `json:"algorithm"`
+	ModelType    string                 `json:"model_type"` // 模型实现的类型名，如"Ridge"
+	Parameters   map[string]interface{} `json:"parameters"`
+	Task         string                 `json:"task"`
+	Performance  map[string]float64     `json:"performance"` // 不含NaN和Inf
+	DataShape    []int                  `json:"data_shape"`
+	DataHash     string                 `json:"data_hash,omitempty"` // 训练数据的指纹（见TrainingData.Fingerprint）
+	FeatureNames []string               `json:"feature_names,omitempty"`
+	TrainedAt    time.Time              `json:"trained_at"`
+	SavedAt      time.Time              `json:"saved_at"`
+	ArtifactHash string                 `json:"artifact_hash"` // 模型文件的SHA-256，加载时校验
+	ArtifactSize int64                  `json:"artifact_size"`
+}
+
+// ModelRegistry 保存训练好的模型的仓库，进程重启后模型仍可加载。每个模型以其ID为前缀保存两个对象：
+// 元数据metadata.json和二进制模型文件model.bin（格式见evaluation.SaveModelBinary）。
+// 存储后端可以是本地目录（NewModelRegistry）或S3兼容的对象存储（NewModelRegistryWithStorage和NewS3Storage），
+// 例如CI中训练的模型保存到对象存储，线上服务从同一位置加载。元数据最后写入，因此写入中断的模型不会出现在List中。
+// 同一进程内的并发访问是安全的；多个进程共享同一存储时，同一ID的并发写入以最后一次为准
+type ModelRegistry struct {
+	storage RegistryStorage
+	factory *models.ModelManager
+	mu      sync.RWMutex
+}
+
+// NewModelRegistry 打开或创建以dir为根目录的模型仓库，每个模型保存在以其ID命名的子目录中
+func NewModelRegistry(dir string) (*ModelRegistry, error) {
+	storage, err := NewFileStorage(dir)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to create registry directory",
+			Details: err.Error(),
+		}
+	}
+	return NewModelRegistryWithStorage(storage)
+}
+
+// NewModelRegistryWithStorage 创建使用指定存储后端的模型仓库
+func NewModelRegistryWithStorage(storage RegistryStorage) (*ModelRegistry, error) {
+	if storage == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "registry storage cannot be nil",
+		}
+	}
+	return &ModelRegistry{storage: storage, factory: models.NewModelManager()}, nil
+}
+
+// Dir 返回仓库的根目录，存储后端不是本地目录时返回空字符串
+func (r *ModelRegistry) Dir() string {
+	if storage, ok := r.storage.(*FileStorage); ok {
+		return storage.Dir()
+	}
+	return ""
+}
+
+// Storage 返回仓库的存储后端
+func (r *ModelRegistry) Storage() RegistryStorage {
+	return r.storage
+}
+
+// List 返回仓库中所有模型的元数据，按保存时间排序
+func (r *ModelRegistry) List() ([]*RegistryEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys, err := r.storage.List("")
+	if err != nil {
+		return nil, registryError("failed to list registry", err)
+	}
+	// 只有元数据已写入的模型才计入，写入未完成的模型没有元数据
+	var entries []*RegistryEntry
+	for _, key := range keys {
+		modelID, file := path.Split(key)
+		modelID = strings.TrimSuffix(modelID, "/")
+		if file != registryMetadataFile || validateRegistryID(modelID) != nil {
+			continue
+		}
+		entry, err := r.readEntry(modelID)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // 列出后被删除的模型
+		}
+		if err != nil {
+			return nil, registryError(fmt.Sprintf("failed to read model %s", modelID), err)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].SavedAt.Equal(entries[j].SavedAt) {
+			return entries[i].SavedAt.Before(entries[j].SavedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+// Get 返回模型的元数据
+func (r *ModelRegistry) Get(modelID string) (*RegistryEntry, error) {
+	if err := validateRegistryID(modelID); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.getEntry(modelID)
+}
+
+// Delete 从仓库中删除模型
+func (r *ModelRegistry) Delete(modelID string) error {
+	if err := validateRegistryID(modelID); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.getEntry(modelID); err != nil {
+		return err
+	}
+	// 先删除元数据，删除中断时模型不会以缺少模型文件的状态出现
+	for _, file := range []string{registryMetadataFile, registryArtifactFile} {
+		if err := r.storage.Delete(registryKey(modelID, file)); err != nil {
+			return registryError(fmt.Sprintf("failed to delete model %s", modelID), err)
+		}
+	}
+	return nil
+}
+
+// Load 加载模型，校验模型文件的摘要后恢复已训练的模型，返回的模型可以直接预测
+func (r *ModelRegistry) Load(modelID string) (*RegisteredModel, error) {
+	if err := validateRegistryID(modelID); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, err := r.getEntry(modelID)
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := r.storage.Get(registryKey(modelID, registryArtifactFile))
+	if err != nil {
+		return nil, registryError(fmt.Sprintf("failed to read model %s", modelID), err)
+	}
+	if sum := sha256.Sum256(artifact); hex.EncodeToString(sum[:]) != entry.ArtifactHash {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("model %s is corrupted", modelID),
+			Details: "artifact checksum does not match metadata",
+		}
+	}
+
+	model, err := r.factory.CreateModel(&models.ModelConfig{ModelType: string(entry.Algorithm)})
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("cannot restore %s model %s", entry.Algorithm, modelID),
+			Details: err.Error(),
+		}
+	}
+	serializer, ok := model.(evaluation.ModelSerializer)
+	if !ok {
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("%s models cannot be restored", entry.Algorithm),
+		}
+	}
+	if err := evaluation.ReadModelBinary(bytes.NewReader(artifact), serializer); err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("failed to restore model %s", modelID),
+			Details: err.Error(),
+		}
+	}
+	return &RegisteredModel{Entry: entry, model: model}, nil
+}
+
+// save 保存模型文件和元数据，ID已存在时覆盖
+func (r *ModelRegistry) save(record *TrainedModel, model models.Model) (*RegistryEntry, error) {
+	if err := validateRegistryID(record.ID); err != nil {
+		return nil, err
+	}
+	serializer, ok := model.(evaluation.ModelSerializer)
+	if !ok {
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("%s models cannot be saved to a registry", record.Algorithm),
+		}
+	}
+	var artifact bytes.Buffer
+	if err := evaluation.WriteModelBinary(&artifact, serializer, nil); err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("failed to serialize model %s", record.ID),
+			Details: err.Error(),
+		}
+	}
+	sum := sha256.Sum256(artifact.Bytes())
+
+	entry := &RegistryEntry{
+		ID:           record.ID,
+		Algorithm:    record.Algorithm,
+		ModelType:    model.GetModelType(),
+		Parameters:   evaluation.SerializeParameters(record.Parameters),
+		Task:         record.Task,
+		Performance:  make(map[string]float64, len(record.Performance)),
+		DataShape:    record.DataShape,
+		DataHash:     record.DataHash,
+		TrainedAt:    record.TrainedAt,
+		SavedAt:      time.Now(),
+		ArtifactHash: hex.EncodeToString(sum[:]),
+		ArtifactSize: int64(artifact.Len()),
+	}
+	if record.Summary != nil {
+		entry.FeatureNames = record.Summary.FeatureNames
+	}
+	for name, value := range record.Performance {
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			entry.Performance[name] = value
+		}
+	}
+	metadata, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("failed to encode metadata of model %s", record.ID),
+			Details: err.Error(),
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// 先删除旧的元数据，写入中断时该模型不会以新旧混合的状态出现
+	if err := r.storage.Delete(registryKey(record.ID, registryMetadataFile)); err != nil {
+		return nil, registryError(fmt.Sprintf("failed to save model %s", record.ID), err)
+	}
+	if err := r.storage.Put(registryKey(record.ID, registryArtifactFile), artifact.Bytes()); err != nil {
+		return nil, registryError(fmt.Sprintf("failed to save model %s", record.ID), err)
+	}
+	if err := r.storage.Put(registryKey(record.ID, registryMetadataFile), metadata); err != nil {
+		return nil, registryError(fmt.Sprintf("failed to save model %s", record.ID), err)
+	}
+	return entry, nil
+}
+
+// getEntry 读取元数据，模型不存在时返回ErrModelNotTrained，调用方需持有锁
+func (r *ModelRegistry) getEntry(modelID string) (*RegistryEntry, error) {
+	entry, err := r.readEntry(modelID)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: fmt.Sprintf("model %s not found in registry", modelID),
+		}
+	}
+	if err != nil {
+		return nil, registryError(fmt.Sprintf("failed to read model %s", modelID), err)
+	}
+	return entry, nil
+}
+
+func (r *ModelRegistry) readEntry(modelID string) (*RegistryEntry, error) {
+	data, err := r.storage.Get(registryKey(modelID, registryMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	var entry RegistryEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RegisteredModel 从模型仓库加载的模型
+type RegisteredModel struct {
+	Entry *RegistryEntry
+	model models.Model
+}
+
+// Predict 使用加载的模型预测，特征列数必须与训练数据相同
+func (m *RegisteredModel) Predict(features *mat.Dense) ([]float64, error) {
+	if features == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if _, cols := features.Dims(); len(m.Entry.DataShape) == 2 && cols != m.Entry.DataShape[1] {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("model %s expects %d features, got %d", m.Entry.ID, m.Entry.DataShape[1], cols),
+		}
+	}
+	return mat.Col(nil, 0, m.model.Predict(features)), nil
+}
+
+// registryKey 返回模型的对象在存储中的键
+func registryKey(modelID, file string) string {
+	return modelID + "/" + file
+}
+
+// validateRegistryID 模型ID用作目录名和对象键的前缀，不能为空、不能包含路径分隔符，也不能为"."或".."
+func validateRegistryID(modelID string) error {
+	if modelID == "" || modelID == "." || modelID == ".." || strings.ContainsAny(modelID, `/\`) {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("invalid registry model id %q", modelID),
+		}
+	}
+	return nil
+}
+
+func registryError(message string, err error) *Error {
+	return &Error{
+		Code:    ErrInvalidData,
+		Message: message,
+		Details: err.Error(),
+	}
+}
+
+// writeFileAtomic 先写入同目录下的临时文件再重命名，读取方不会看到写了一半的文件
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package gomodel
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config S3兼容对象存储的连接参数
+type S3Config struct {
+	// Endpoint 服务地址，如"https://s3.us-east-1.amazonaws.com"、"https://storage.googleapis.com"或
+	// MinIO的"http://localhost:9000"；为空时为AWS S3在Region的地址
+	Endpoint string
+	// Region 签名使用的区域，为空时读取环境变量AWS_REGION，仍为空时为us-east-1（GCS和MinIO使用us-east-1或auto即可）
+	Region string
+	Bucket string
+	// Prefix 对象键的前缀，如"models/prod"，多个仓库可以共用一个存储桶
+	Prefix string
+	// AccessKeyID、SecretAccessKey和SessionToken为访问凭证，AccessKeyID为空时读取环境变量
+	// AWS_ACCESS_KEY_ID、AWS_SECRET_ACCESS_KEY和AWS_SESSION_TOKEN。GCS需使用HMAC密钥
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// PathStyle 为true时以"<Endpoint>/<Bucket>/<键>"访问对象，MinIO等自建服务通常需要；
+	// 否则以"<Bucket>.<Endpoint主机>/<键>"访问
+	PathStyle bool
+	// HTTPClient 发送请求的客户端，为nil时使用超时为5分钟的客户端
+	HTTPClient *http.Client
+}
+
+// S3Storage 以S3兼容对象存储（AWS S3、通过XML API访问的GCS、MinIO等）保存对象的存储后端，
+// 请求使用AWS Signature Version 4签名。PutObject整体替换对象，读取方不会看到写了一半的对象
+type S3Storage struct {
+	config   S3Config
+	endpoint *url.URL
+	prefix   string
+	client   *http.Client
+}
+
+// NewS3Storage 根据连接参数创建存储，只校验参数，不访问服务
+func NewS3Storage(config S3Config) (*S3Storage, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket cannot be empty")
+	}
+	if config.Region == "" {
+		config.Region = os.Getenv("AWS_REGION")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.AccessKeyID == "" {
+		config.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		config.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		config.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("S3 credentials are missing")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", config.Endpoint)
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	prefix := strings.Trim(config.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3Storage{config: config, endpoint: endpoint, prefix: prefix, client: client}, nil
+}
+
+// Get 读取对象，对象不存在时返回的错误满足errors.Is(err, fs.ErrNotExist)
+func (s *S3Storage) Get(key string) ([]byte, error) {
+	return s.do(http.MethodGet, s.prefix+key, nil, nil)
+}
+
+// Put 上传对象，已存在时覆盖
+func (s *S3Storage) Put(key string, data []byte) error {
+	_, err := s.do(http.MethodPut, s.prefix+key, nil, data)
+	return err
+}
+
+// Delete 删除对象，对象不存在时不报错
+func (s *S3Storage) Delete(key string) error {
+	_, err := s.do(http.MethodDelete, s.prefix+key, nil, nil)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List 使用ListObjectsV2分页列出以prefix开头的全部键，返回的键不含Prefix
+func (s *S3Storage) List(prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.prefix + prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		body, err := s.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("invalid ListObjectsV2 response: %w", err)
+		}
+		for _, object := range result.Contents {
+			keys = append(keys, strings.TrimPrefix(object.Key, s.prefix))
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// do 发送签名后的请求，key为空时访问存储桶本身，返回响应体；状态码不是2xx时返回*S3Error
+func (s *S3Storage) do(method, key string, query url.Values, body []byte) ([]byte, error) {
+	u := *s.endpoint
+	escapedPath := strings.TrimSuffix(u.EscapedPath(), "/")
+	if s.config.PathStyle {
+		escapedPath += "/" + s3Escape(s.config.Bucket, false)
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+	}
+	escapedPath += "/" + s3Escape(key, true)
+	decodedPath, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		return nil, err
+	}
+	u.Path, u.RawPath = decodedPath, escapedPath
+	u.RawQuery = s3CanonicalQuery(query)
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	s.sign(req, escapedPath, body, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		s3Err := &S3Error{StatusCode: resp.StatusCode, Key: key}
+		xml.Unmarshal(data, s3Err) // 响应体不是XML错误文档时只保留状态码
+		return nil, s3Err
+	}
+	return data, nil
+}
+
+// sign 按AWS Signature Version 4为请求添加Authorization等请求头
+func (s *S3Storage) sign(req *http.Request, escapedPath string, body []byte, now time.Time) {
+	payloadHash := sha256.Sum256(body)
+	amzDate := now.Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	if s.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if s.config.SessionToken != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapedPath,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// S3Error 对象存储返回的错误响应
+type S3Error struct {
+	StatusCode int    `xml:"-"`
+	Key        string `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *S3Error) Error() string {
+	message := fmt.Sprintf("S3 request failed with status %d", e.StatusCode)
+	if e.Code != "" {
+		message += ": " + e.Code
+	}
+	if e.Message != "" {
+		message += " (" + e.Message + ")"
+	}
+	if e.Key != "" {
+		message += ", key " + e.Key
+	}
+	return message
+}
+
+// Is 使对象不存在的错误满足errors.Is(err, fs.ErrNotExist)
+func (e *S3Error) Is(target error) bool {
+	return target == fs.ErrNotExist && (e.StatusCode == http.StatusNotFound || e.Code == "NoSuchKey")
+}
+
+// s3Escape 按SigV4的规则转义，只保留RFC 3986的非保留字符；keepSlash为true时不转义"/"
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (keepSlash && c == '/') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3CanonicalQuery 按键排序并转义查询参数，结果同时用作请求的查询串和签名中的规范查询串
+func s3CanonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, s3Escape(name, false)+"="+s3Escape(value, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,360 @@
+package gomodel
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 在内存中实现PutObject、GetObject、DeleteObject和ListObjectsV2的测试服务，
+// 独立按SigV4规则重新计算签名并拒绝签名不符的请求，列表每页只返回两个键以覆盖分页
+type fakeS3 struct {
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	pages   int
+}
+
+func newFakeS3(pathStyle bool) *fakeS3 {
+	return &fakeS3{
+		bucket:    "models-bucket",
+		region:    "eu-west-1",
+		accessKey: "AKIDEXAMPLE",
+		secretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		pathStyle: pathStyle,
+		objects:   make(map[string][]byte),
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		f.fail(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+	if err := f.verifySignature(r, body); err != nil {
+		f.fail(w, http.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if f.pathStyle {
+		if key != f.bucket && !strings.HasPrefix(key, f.bucket+"/") {
+			f.fail(w, http.StatusNotFound, "NoSuchBucket", "")
+			return
+		}
+		key = strings.TrimPrefix(strings.TrimPrefix(key, f.bucket), "/")
+	} else if !strings.HasPrefix(r.Host, f.bucket+".") {
+		f.fail(w, http.StatusNotFound, "NoSuchBucket", "")
+		return
+	}
+
+	switch {
+	case key == "" && r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		f.list(w, r)
+	case key == "":
+		f.fail(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "")
+	case r.Method == http.MethodPut:
+		if r.Header.Get("Content-Length") != strconv.Itoa(len(body)) {
+			f.fail(w, http.StatusBadRequest, "MissingContentLength", "")
+			return
+		}
+		f.objects[key] = body
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+		w.Write(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.fail(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "")
+	}
+}
+
+// list 按键排序返回以prefix开头的对象，continuation-token为下一页起始位置
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	f.pages++
+	prefix := r.URL.Query().Get("prefix")
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	start := 0
+	if token := r.URL.Query().Get("continuation-token"); token != "" {
+		start, _ = strconv.Atoi(strings.TrimPrefix(token, "page-"))
+	}
+	type object struct {
+		Key  string `xml:"Key"`
+		Size int    `xml:"Size"`
+	}
+	result := struct {
+		XMLName               xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+		Name                  string   `xml:"Name"`
+		Prefix                string   `xml:"Prefix"`
+		KeyCount              int      `xml:"KeyCount"`
+		IsTruncated           bool     `xml:"IsTruncated"`
+		Contents              []object `xml:"Contents"`
+		NextContinuationToken string   `xml:"NextContinuationToken,omitempty"`
+	}{Name: f.bucket, Prefix: prefix}
+	end := start + 2
+	if end < len(keys) {
+		result.IsTruncated = true
+		result.NextContinuationToken = fmt.Sprintf("page-%d", end)
+	} else {
+		end = len(keys)
+	}
+	for _, key := range keys[start:end] {
+		result.Contents = append(result.Contents, object{Key: key, Size: len(f.objects[key])})
+	}
+	result.KeyCount = len(result.Contents)
+	w.Header().Set("Content-Type", "application/xml")
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeS3) fail(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "%s<Error><Code>%s</Code><Message>%s</Message></Error>", xml.Header, code, message)
+}
+
+// verifySignature 由服务端收到的请求重新计算SigV4签名，与Authorization请求头比较
+func (f *fakeS3) verifySignature(r *http.Request, body []byte) error {
+	payloadHash := sha256.Sum256(body)
+	if got := r.Header.Get("X-Amz-Content-Sha256"); got != hex.EncodeToString(payloadHash[:]) {
+		return fmt.Errorf("payload hash %q does not match the body", got)
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	signedAt, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil || time.Since(signedAt) > 15*time.Minute || time.Until(signedAt) > 15*time.Minute {
+		return fmt.Errorf("invalid request time %q", amzDate)
+	}
+
+	var credential, signedHeaders, signature string
+	authorization, ok := strings.CutPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	if !ok {
+		return fmt.Errorf("unsupported authorization %q", r.Header.Get("Authorization"))
+	}
+	for _, part := range strings.Split(authorization, ", ") {
+		name, value, _ := strings.Cut(part, "=")
+		switch name {
+		case "Credential":
+			credential = value
+		case "SignedHeaders":
+			signedHeaders = value
+		case "Signature":
+			signature = value
+		}
+	}
+	scope := amzDate[:8] + "/" + f.region + "/s3/aws4_request"
+	if credential != f.accessKey+"/"+scope {
+		return fmt.Errorf("credential %q, want scope %s", credential, scope)
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	mac := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	key := mac([]byte("AWS4"+f.secretKey), amzDate[:8])
+	key = mac(key, f.region)
+	key = mac(key, "s3")
+	key = mac(key, "aws4_request")
+	if want := hex.EncodeToString(mac(key, stringToSign)); signature != want {
+		return fmt.Errorf("signature %q, want %q", signature, want)
+	}
+	return nil
+}
+
+// newFakeS3Storage 启动测试服务并返回连接它的存储。虚拟主机风格下请求发往"<桶>.127.0.0.1"，
+// 由自定义的拨号函数连接到测试服务
+func newFakeS3Storage(t *testing.T, f *fakeS3, prefix string) *S3Storage {
+	t.Helper()
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	address := server.Listener.Addr().String()
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		},
+	}}
+	storage, err := NewS3Storage(S3Config{
+		Endpoint:        server.URL,
+		Region:          f.region,
+		Bucket:          f.bucket,
+		Prefix:          prefix,
+		AccessKeyID:     f.accessKey,
+		SecretAccessKey: f.secretKey,
+		PathStyle:       f.pathStyle,
+		HTTPClient:      client,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return storage
+}
+
+func TestModelRegistryS3RoundTrip(t *testing.T) {
+	for _, pathStyle := range []bool{true, false} {
+		t.Run(fmt.Sprintf("pathStyle=%v", pathStyle), func(t *testing.T) {
+			f := newFakeS3(pathStyle)
+			testRegistryRoundTrip(t, newFakeS3Storage(t, f, "/ci/models/"))
+
+			// 所有对象都写在前缀下，列表跨越了多页
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			for key := range f.objects {
+				if !strings.HasPrefix(key, "ci/models/") {
+					t.Errorf("object %q written outside the prefix", key)
+				}
+			}
+			if len(f.objects) == 0 || f.pages < 2 {
+				t.Errorf("%d objects listed in %d pages, want several pages", len(f.objects), f.pages)
+			}
+		})
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	f := newFakeS3(true)
+	storage := newFakeS3Storage(t, f, "team a")
+	// 前缀外的同名对象不会被列出
+	f.objects["m9/outside.bin"] = []byte("{}")
+
+	// 需要转义的键在签名和请求路径中保持一致
+	keys := []string{"m 1/model.bin", "m+2/model.bin", "m~3/a=b&c.json", "m4/模型.bin", "m5/x.bin"}
+	for i, key := range keys {
+		if err := storage.Put(key, []byte(strings.Repeat("x", i))); err != nil {
+			t.Fatalf("put %q: %v", key, err)
+		}
+	}
+	if _, ok := f.objects["team a/m 1/model.bin"]; !ok {
+		t.Errorf("objects = %v, want keys under %q", f.objects, "team a/")
+	}
+	for i, key := range keys {
+		data, err := storage.Get(key)
+		if err != nil || len(data) != i {
+			t.Errorf("get %q = %d bytes, %v, want %d bytes", key, len(data), err, i)
+		}
+	}
+	if err := storage.Put("empty", nil); err != nil {
+		t.Errorf("put empty object: %v", err)
+	}
+
+	listed, err := storage.List("m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]string(nil), keys...)
+	sort.Strings(want)
+	if strings.Join(listed, "|") != strings.Join(want, "|") {
+		t.Errorf("List(\"m\") = %q, want %q", listed, want)
+	}
+
+	_, err = storage.Get("missing")
+	var s3Err *S3Error
+	if !errors.Is(err, fs.ErrNotExist) || !errors.As(err, &s3Err) || s3Err.Code != "NoSuchKey" || s3Err.StatusCode != http.StatusNotFound {
+		t.Errorf("missing object: error = %v, want NoSuchKey satisfying fs.ErrNotExist", err)
+	}
+	if err := storage.Delete(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Delete(keys[0]); err != nil {
+		t.Errorf("deleting a missing object: %v", err)
+	}
+
+	// 错误的密钥被服务拒绝，错误不被当作对象不存在
+	other := newFakeS3(true)
+	unauthorized := newFakeS3Storage(t, other, "")
+	other.secretKey = "another-secret"
+	_, err = unauthorized.Get("anything")
+	if !errors.As(err, &s3Err) || s3Err.Code != "SignatureDoesNotMatch" || errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("wrong secret: error = %v, want SignatureDoesNotMatch", err)
+	}
+}
+
+func TestNewS3StorageValidation(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_REGION", "")
+
+	tests := []struct {
+		name   string
+		config S3Config
+	}{
+		{"missing bucket", S3Config{AccessKeyID: "a", SecretAccessKey: "s"}},
+		{"missing credentials", S3Config{Bucket: "b"}},
+		{"missing secret", S3Config{Bucket: "b", AccessKeyID: "a"}},
+		{"unsupported scheme", S3Config{Bucket: "b", AccessKeyID: "a", SecretAccessKey: "s", Endpoint: "ftp://example.com"}},
+		{"missing host", S3Config{Bucket: "b", AccessKeyID: "a", SecretAccessKey: "s", Endpoint: "http://"}},
+	}
+	for _, tc := range tests {
+		if _, err := NewS3Storage(tc.config); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+
+	// 凭证和区域可以来自环境变量，未指定地址时使用该区域的AWS地址
+	t.Setenv("AWS_ACCESS_KEY_ID", "env-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+	t.Setenv("AWS_REGION", "ap-south-1")
+	storage, err := NewS3Storage(S3Config{Bucket: "b", Prefix: "/models/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage.config.AccessKeyID != "env-key" || storage.config.Region != "ap-south-1" ||
+		storage.endpoint.String() != "https://s3.ap-south-1.amazonaws.com" || storage.prefix != "models/" {
+		t.Errorf("config = %+v, endpoint %s, prefix %q", storage.config, storage.endpoint, storage.prefix)
+	}
+}
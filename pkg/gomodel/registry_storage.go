@@ -0,0 +1,113 @@
+package gomodel
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RegistryStorage 模型仓库的存储后端，以"/"分隔的键保存对象，模型仓库使用的键为"<模型ID>/metadata.json"
+// 和"<模型ID>/model.bin"。实现需可并发调用：Get在对象不存在时返回的错误满足errors.Is(err, fs.ErrNotExist)；
+// Put整体替换对象，读取方不能看到写了一半的对象；Delete删除不存在的对象不报错；List返回以prefix开头的全部键
+type RegistryStorage interface {
+	Get(key string) ([]byte, error)
+	Put(key string, data []byte) error
+	Delete(key string) error
+	List(prefix string) ([]string, error)
+}
+
+// FileStorage 以本地目录保存对象的存储后端，键对应目录下的相对路径
+type FileStorage struct {
+	dir string
+}
+
+// NewFileStorage 打开或创建以dir为根目录的存储
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+// Dir 返回存储的根目录
+func (s *FileStorage) Dir() string {
+	return s.dir
+}
+
+// Get 读取对象
+func (s *FileStorage) Get(key string) ([]byte, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filePath)
+}
+
+// Put 先写入同目录下的临时文件再重命名
+func (s *FileStorage) Put(key string, data []byte) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(filePath, data)
+}
+
+// Delete 删除对象，并删除因此变为空的上级目录
+func (s *FileStorage) Delete(key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	root := filepath.Clean(s.dir)
+	for dir := filepath.Dir(filePath); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break // 目录非空或已不存在
+		}
+	}
+	return nil
+}
+
+// List 返回以prefix开头的全部键，按字典序排列，写入中的临时文件不计入
+func (s *FileStorage) List(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(s.dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, filePath)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// path 将键转换为文件路径，键不能为空、不能是绝对路径，也不能指向根目录之外
+func (s *FileStorage) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
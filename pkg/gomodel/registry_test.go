@@ -0,0 +1,211 @@
+package gomodel
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// registryClassificationData 生成两个特征的二分类数据
+func registryClassificationData(n int) *TrainingData {
+	rng := rand.New(rand.NewSource(3))
+	X := mat.NewDense(n, 2, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		a, b := rng.NormFloat64(), rng.NormFloat64()
+		X.SetRow(i, []float64{a, b})
+		if a-b+0.5*rng.NormFloat64() > 0 {
+			y.SetVec(i, 1)
+		}
+	}
+	return &TrainingData{Features: X, Target: y, FeatureNames: []string{"a", "b"}}
+}
+
+// rows 将矩阵转换为按行排列的切片
+func rows(X *mat.Dense) [][]float64 {
+	n, _ := X.Dims()
+	result := make([][]float64, n)
+	for i := range result {
+		result[i] = mat.Row(nil, i, X)
+	}
+	return result
+}
+
+// errorCode 返回*Error的错误码，其他错误返回空字符串
+func errorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// testRegistryRoundTrip 在storage上保存两个模型，再由另一个ModelManager从同一存储加载，
+// 检查元数据和预测值不变，并检查删除、损坏检测和ID预留
+func testRegistryRoundTrip(t *testing.T, storage RegistryStorage) {
+	t.Helper()
+	regression := ridgeData(60)
+	classification := registryClassificationData(80)
+
+	source := NewModelManager()
+	registry, err := NewModelRegistryWithStorage(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := source.SetRegistry(registry); err != nil {
+		t.Fatal(err)
+	}
+	ridge, err := source.TrainModel(&ModelConfig{Algorithm: Ridge, Parameters: map[string]interface{}{"lambda": 0.5}}, regression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logistic, err := source.TrainModel(&ModelConfig{Algorithm: Logistic}, classification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{ridge.ID, logistic.ID} {
+		entry, err := source.SaveToRegistry(id)
+		if err != nil {
+			t.Fatalf("save %s: %v", id, err)
+		}
+		if entry.ArtifactHash == "" || entry.ArtifactSize == 0 {
+			t.Errorf("%s: artifact hash %q, size %d", id, entry.ArtifactHash, entry.ArtifactSize)
+		}
+	}
+
+	reopened, err := NewModelRegistryWithStorage(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := reopened.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != ridge.ID || entries[1].ID != logistic.ID {
+		t.Fatalf("listed %d entries, want %s and %s in save order", len(entries), ridge.ID, logistic.ID)
+	}
+
+	target := NewModelManager()
+	if err := target.SetRegistry(reopened); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		original *TrainedModel
+		data     *TrainingData
+		task     string
+	}{
+		{ridge, regression, TaskRegression},
+		{logistic, classification, TaskClassification},
+	}
+	for _, c := range cases {
+		loaded, err := target.LoadFromRegistry(c.original.ID)
+		if err != nil {
+			t.Fatalf("load %s: %v", c.original.ID, err)
+		}
+		if loaded.Algorithm != c.original.Algorithm || loaded.Task != c.task ||
+			!reflect.DeepEqual(loaded.Performance, c.original.Performance) ||
+			!reflect.DeepEqual(loaded.DataShape, c.original.DataShape) ||
+			!loaded.TrainedAt.Equal(c.original.TrainedAt) || !loaded.TrainedOn(c.data) {
+			t.Errorf("%s: loaded record %+v differs from the saved one %+v", c.original.ID, loaded, c.original)
+		}
+
+		want, err := source.PredictWithModel(c.original.ID, rows(c.data.Features))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := target.PredictWithModel(c.original.ID, rows(c.data.Features))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got.Predictions, want.Predictions) {
+			t.Errorf("%s: predictions changed after the round trip", c.original.ID)
+		}
+	}
+	if lambda := target.trainedModels[ridge.ID].Parameters["lambda"]; lambda != 0.5 {
+		t.Errorf("loaded lambda = %v, want 0.5", lambda)
+	}
+
+	// 仓库中已有的ID被预留，新训练的模型不会覆盖它们
+	fresh, err := target.TrainModel(&ModelConfig{Algorithm: OLS}, regression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh.ID == ridge.ID || fresh.ID == logistic.ID {
+		t.Errorf("new model reused registry id %s", fresh.ID)
+	}
+
+	// 模型文件被篡改时校验失败
+	artifact, err := storage.Get(registryKey(ridge.ID, registryArtifactFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifact[len(artifact)-1] ^= 0xff
+	if err := storage.Put(registryKey(ridge.ID, registryArtifactFile), artifact); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopened.Load(ridge.ID); errorCode(err) != ErrInvalidData {
+		t.Errorf("corrupted artifact: error = %v, want %s", err, ErrInvalidData)
+	}
+
+	if err := reopened.Delete(logistic.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopened.Get(logistic.ID); errorCode(err) != ErrModelNotTrained {
+		t.Errorf("deleted model: error = %v, want %s", err, ErrModelNotTrained)
+	}
+	if err := reopened.Delete(logistic.ID); errorCode(err) != ErrModelNotTrained {
+		t.Errorf("second delete: error = %v, want %s", err, ErrModelNotTrained)
+	}
+	if entries, err := reopened.List(); err != nil || len(entries) != 1 {
+		t.Errorf("after delete: %d entries, error %v, want 1", len(entries), err)
+	}
+}
+
+func TestModelRegistryDirectoryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	registry, err := NewModelRegistry(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registry.Dir() != dir {
+		t.Errorf("Dir() = %q, want %q", registry.Dir(), dir)
+	}
+	testRegistryRoundTrip(t, registry.Storage())
+
+	// 写入中断、只有模型文件的目录不会出现在列表中
+	if err := os.MkdirAll(filepath.Join(dir, "partial"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "partial", registryArtifactFile), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := registry.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.ID == "partial" {
+			t.Error("a model without metadata was listed")
+		}
+	}
+}
+
+func TestModelRegistryInvalidID(t *testing.T) {
+	registry, err := NewModelRegistry(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"", ".", "..", "a/b", `a\b`} {
+		if _, err := registry.Load(id); errorCode(err) != ErrInvalidParameters {
+			t.Errorf("id %q: error = %v, want %s", id, err, ErrInvalidParameters)
+		}
+	}
+	if _, err := NewModelManager().SaveToRegistry("model_1"); err == nil {
+		t.Error("expected an error without an attached registry")
+	}
+}
@@ -0,0 +1,75 @@
+package gomodel
+
+import (
+	"github.com/feiyuluoye/Go-Model/internal/models/linear"
+)
+
+// RegularizationPath Lasso/弹性网的正则化路径，lambda按从大到小排列
+type RegularizationPath struct {
+	Lambdas      []float64   `json:"lambdas"`
+	Coefficients [][]float64 `json:"coefficients"` // Coefficients[k][j] 为第k个lambda下第j个特征的系数
+	Intercepts   []float64   `json:"intercepts"`
+	Iterations   []int       `json:"iterations"`
+	L1Ratio      float64     `json:"l1_ratio"`
+	FeatureNames []string    `json:"feature_names,omitempty"`
+}
+
+// PathConfig 正则化路径配置
+type PathConfig struct {
+	Lambdas       []float64 `json:"lambdas,omitempty"` // 指定的lambda序列，为空时自动生成
+	NumLambdas    int       `json:"num_lambdas"`       // 自动生成的lambda个数
+	Eps           float64   `json:"eps"`               // 最小lambda与lambda_max的比值
+	MaxIterations int       `json:"max_iterations"`
+	Tolerance     float64   `json:"tolerance"`
+}
+
+// LassoPath 沿递减的lambda序列拟合Lasso（热启动），返回完整的系数路径，
+// 可用于绘制收缩曲线并直观地选择lambda；config为nil时使用默认配置
+func LassoPath(data *TrainingData, config *PathConfig) (*RegularizationPath, error) {
+	return ElasticNetPath(data, 1.0, config)
+}
+
+// ElasticNetPath 沿递减的lambda序列拟合弹性网，l1Ratio取值(0, 1]
+func ElasticNetPath(data *TrainingData, l1Ratio float64, config *PathConfig) (*RegularizationPath, error) {
+	if data == nil || data.Features == nil || data.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+
+	opts := linear.DefaultPathOptions()
+	if config != nil {
+		opts.Lambdas = config.Lambdas
+		if config.NumLambdas > 0 {
+			opts.NumLambdas = config.NumLambdas
+		}
+		if config.Eps > 0 {
+			opts.Eps = config.Eps
+		}
+		if config.MaxIterations > 0 {
+			opts.MaxIter = config.MaxIterations
+		}
+		if config.Tolerance > 0 {
+			opts.Tol = config.Tolerance
+		}
+	}
+
+	path, err := linear.ElasticNetPath(data.Features, data.Target, l1Ratio, opts)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrTrainingFailed,
+			Message: "failed to compute regularization path",
+			Details: err.Error(),
+		}
+	}
+
+	return &RegularizationPath{
+		Lambdas:      path.Lambdas,
+		Coefficients: path.Coefficients,
+		Intercepts:   path.Intercepts,
+		Iterations:   path.Iterations,
+		L1Ratio:      path.L1Ratio,
+		FeatureNames: data.FeatureNames,
+	}, nil
+}
@@ -0,0 +1,354 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/feiyuluoye/Go-Model/internal/data"
+	"github.com/feiyuluoye/Go-Model/internal/types"
+	"gonum.org/v1/gonum/mat"
+)
+
+// 类别重采样方法
+const (
+	ResampleNone        = "none"
+	ResampleOversample  = "oversample"  // 随机复制少数类样本
+	ResampleSMOTE       = "smote"       // 插值合成少数类样本
+	ResampleUndersample = "undersample" // 随机删除多数类样本
+	ResampleNearMiss    = "nearmiss"    // 保留最靠近少数类的多数类样本
+)
+
+// RandomOverSample 随机复制少数类样本，使每个类别的样本数至少达到最多类别的ratio倍（ratio<=0时为1，即完全平衡）。
+// 只应在训练集上调用，复制的样本追加在原样本之后
+func (du *DataUtils) RandomOverSample(d *TrainingData, ratio float64) (*TrainingData, error) {
+	dataset, err := resampleInput(d)
+	if err != nil {
+		return nil, err
+	}
+	if ratio <= 0 {
+		ratio = 1
+	}
+	resampled, err := data.RandomOverSample(dataset, ratio, du.newRand())
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to oversample",
+			Details: err.Error(),
+		}
+	}
+	return resampledTrainingData(resampled, d), nil
+}
+
+// SMOTE 以SMOTE合成少数类样本：每个新样本是随机选取的少数类样本与其k个同类最近邻之一的线性插值，
+// 使每个类别的样本数至少达到最多类别的ratio倍（ratio<=0时为1；k<=0时为5）。
+// Categories中记录的类别特征不参与插值，取所选样本的值。特征不能含缺失值，只应在训练集上调用
+func (du *DataUtils) SMOTE(d *TrainingData, ratio float64, k int) (*TrainingData, error) {
+	dataset, err := resampleInput(d)
+	if err != nil {
+		return nil, err
+	}
+	if ratio <= 0 {
+		ratio = 1
+	}
+	if k <= 0 {
+		k = 5
+	}
+	resampled, err := data.SMOTE(dataset, ratio, k, nominalColumns(d), du.newRand())
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to apply SMOTE",
+			Details: err.Error(),
+		}
+	}
+	return resampledTrainingData(resampled, d), nil
+}
+
+// RandomUnderSample 随机删除多数类样本，使每个类别的样本数不超过最少类别的1/ratio倍（ratio<=0时为1，即完全平衡），
+// 如ratio=0.5时多数类最多保留少数类的2倍。只应在训练集上调用，保留的样本维持原有顺序
+func (du *DataUtils) RandomUnderSample(d *TrainingData, ratio float64) (*TrainingData, error) {
+	dataset, err := resampleInput(d)
+	if err != nil {
+		return nil, err
+	}
+	if ratio <= 0 {
+		ratio = 1
+	}
+	resampled, err := data.RandomUnderSample(dataset, ratio, du.newRand())
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to undersample",
+			Details: err.Error(),
+		}
+	}
+	return resampledTrainingData(resampled, d), nil
+}
+
+// NearMiss 以NearMiss-1欠采样多数类：优先保留到最少类别的k个最近样本平均距离最小的样本，
+// 即最靠近类别边界、对决策面最有信息量的样本，使每个类别的样本数不超过最少类别的1/ratio倍
+// （ratio<=0时为1；k<=0时为3）。Categories中记录的类别特征不参与距离计算，特征不能含缺失值
+func (du *DataUtils) NearMiss(d *TrainingData, ratio float64, k int) (*TrainingData, error) {
+	dataset, err := resampleInput(d)
+	if err != nil {
+		return nil, err
+	}
+	if ratio <= 0 {
+		ratio = 1
+	}
+	if k <= 0 {
+		k = 3
+	}
+	resampled, err := data.NearMiss(dataset, ratio, k, nominalColumns(d))
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to apply NearMiss",
+			Details: err.Error(),
+		}
+	}
+	return resampledTrainingData(resampled, d), nil
+}
+
+// Preprocess 按配置依次移除重复行、处理缺失值、移除异常值、重采样类别，最后标准化或缩放特征
+func (du *DataUtils) Preprocess(d *TrainingData, config *DataPreprocessConfig) (*TrainingData, error) {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	if config == nil {
+		return d, nil
+	}
+
+	result := d
+	var err error
+	if config.Deduplicate {
+		if result, _, err = du.Deduplicate(result, &DeduplicateConfig{Tolerance: config.DuplicateTolerance}); err != nil {
+			return nil, err
+		}
+	}
+
+	switch config.HandleMissing {
+	case "", "none":
+	case "drop":
+		result = dropMissingRows(result)
+	case ImputeMean, ImputeMedian, ImputeMode:
+		imputer, _ := NewImputer(config.HandleMissing)
+		if result, err = imputer.FitTransform(result); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported missing value handling: %s", config.HandleMissing),
+		}
+	}
+
+	if config.OutlierMethod != "" && config.OutlierMethod != "none" {
+		if result, err = du.RemoveOutliers(result, config.OutlierMethod, config.OutlierThreshold); err != nil {
+			return nil, err
+		}
+	}
+
+	switch config.Resample {
+	case "", ResampleNone:
+	case ResampleOversample:
+		result, err = du.RandomOverSample(result, config.ResampleRatio)
+	case ResampleSMOTE:
+		result, err = du.SMOTE(result, config.ResampleRatio, config.SMOTENeighbors)
+	case ResampleUndersample:
+		result, err = du.RandomUnderSample(result, config.ResampleRatio)
+	case ResampleNearMiss:
+		result, err = du.NearMiss(result, config.ResampleRatio, 0)
+	default:
+		err = &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported resampling method: %s", config.Resample),
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Normalize {
+		return du.Normalize(result)
+	}
+	if config.Scale {
+		return du.Scale(result)
+	}
+	return result, nil
+}
+
+// Resampler 组合过采样与欠采样的流水线步骤，先将少数类过采样到多数类的OverRatio倍，
+// 再将多数类欠采样到少数类的1/UnderRatio倍，如SMOTE到0.1后随机欠采样到0.5。
+// 只在流水线训练（FitTransform）时重采样；Transform原样返回数据，预测和评分时不改变样本
+type Resampler struct {
+	Over       string  `json:"over"`        // 过采样方法："", oversample 或 smote
+	OverRatio  float64 `json:"over_ratio"`  // 过采样后少数类相对多数类的比例，<=0时为1
+	Under      string  `json:"under"`       // 欠采样方法："", undersample 或 nearmiss
+	UnderRatio float64 `json:"under_ratio"` // 欠采样后少数类相对多数类的比例，<=0时为1
+	Neighbors  int     `json:"neighbors"`   // SMOTE或NearMiss的近邻数，<=0时使用各自的默认值
+	RandomSeed int64   `json:"random_seed"`
+	nFeatures  int
+}
+
+// NewResampler 创建组合重采样步骤，over或under为空字符串时跳过对应阶段
+func NewResampler(over string, overRatio float64, under string, underRatio float64) (*Resampler, error) {
+	r := &Resampler{Over: over, OverRatio: overRatio, Under: under, UnderRatio: underRatio, RandomSeed: 42}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Fit 检查参数并记录输入特征数量，重采样只在FitTransform中进行
+func (r *Resampler) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	if err := r.validate(); err != nil {
+		return err
+	}
+	_, r.nFeatures = d.Features.Dims()
+	return nil
+}
+
+// Transform 原样返回数据，保证预测和评分时使用全部样本
+func (r *Resampler) Transform(d *TrainingData) (*TrainingData, error) {
+	if r.nFeatures == 0 {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	if _, c := d.Features.Dims(); c != r.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", r.nFeatures, c),
+		}
+	}
+	return d, nil
+}
+
+// FitTransform 依次过采样和欠采样训练数据
+func (r *Resampler) FitTransform(d *TrainingData) (*TrainingData, error) {
+	if err := r.Fit(d); err != nil {
+		return nil, err
+	}
+
+	du := NewDataUtils(r.RandomSeed)
+	result := d
+	var err error
+	switch r.Over {
+	case ResampleOversample:
+		result, err = du.RandomOverSample(result, r.OverRatio)
+	case ResampleSMOTE:
+		result, err = du.SMOTE(result, r.OverRatio, r.Neighbors)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch r.Under {
+	case ResampleUndersample:
+		result, err = du.RandomUnderSample(result, r.UnderRatio)
+	case ResampleNearMiss:
+		result, err = du.NearMiss(result, r.UnderRatio, r.Neighbors)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validate 检查重采样方法和比例
+func (r *Resampler) validate() error {
+	switch r.Over {
+	case "", ResampleNone, ResampleOversample, ResampleSMOTE:
+	default:
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported oversampling method: %s", r.Over),
+		}
+	}
+	switch r.Under {
+	case "", ResampleNone, ResampleUndersample, ResampleNearMiss:
+	default:
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported undersampling method: %s", r.Under),
+		}
+	}
+	if r.OverRatio > 1 || r.UnderRatio > 1 {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("resampling ratios must be at most 1, got over=%g under=%g", r.OverRatio, r.UnderRatio),
+		}
+	}
+	return nil
+}
+
+// resampleInput 检查训练数据并转换为带权重的数据集
+func resampleInput(d *TrainingData) (*types.Dataset, error) {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+	dataset := toDataset(d)
+	if d.Weights != nil {
+		dataset.Weights = mat.Col(nil, 0, d.Weights)
+	}
+	return dataset, nil
+}
+
+// resampledTrainingData 将重采样后的数据集转换为训练数据，保留原数据的名称和类别表
+func resampledTrainingData(dataset *types.Dataset, original *TrainingData) *TrainingData {
+	features := mat.NewDense(dataset.NumSamples(), dataset.NumFeatures(), nil)
+	for i, row := range dataset.Features {
+		features.SetRow(i, row)
+	}
+	result := &TrainingData{
+		Features:     features,
+		Target:       mat.NewVecDense(len(dataset.Target), dataset.Target),
+		FeatureNames: original.FeatureNames,
+		TargetName:   original.TargetName,
+		Categories:   original.Categories,
+	}
+	if len(dataset.Weights) > 0 {
+		result.Weights = mat.NewVecDense(len(dataset.Weights), dataset.Weights)
+	}
+	return result
+}
+
+// nominalColumns 返回Categories中记录的类别特征的列号
+func nominalColumns(d *TrainingData) []int {
+	var nominal []int
+	for j, name := range columnNames(d) {
+		if _, categorical := d.Categories[name]; categorical {
+			nominal = append(nominal, j)
+		}
+	}
+	return nominal
+}
+
+// dropMissingRows 删除特征含缺失值(NaN)的行
+func dropMissingRows(d *TrainingData) *TrainingData {
+	r, c := d.Features.Dims()
+	kept := make([]int, 0, r)
+	for i := 0; i < r; i++ {
+		complete := true
+		for j := 0; j < c && complete; j++ {
+			complete = !math.IsNaN(d.Features.At(i, j))
+		}
+		if complete {
+			kept = append(kept, i)
+		}
+	}
+	return subsetTrainingData(d, kept)
+}
@@ -0,0 +1,42 @@
+package gomodel
+
+import (
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+)
+
+// Scorer 评估指标，由真实值和模型的预测值计算指标值；误差类指标GreaterIsBetter为false
+type Scorer = evaluation.Scorer
+
+// ClassificationScorer 二分类指标，NeedsProbabilities为true时接收正类概率，否则接收按0.5的阈值转换得到的类别
+type ClassificationScorer = evaluation.ClassificationScorer
+
+// NewScorer 由指标函数构造回归指标
+func NewScorer(metric func(yTrue, yPred []float64) (float64, error), greaterIsBetter bool) Scorer {
+	return evaluation.NewScorer(metric, greaterIsBetter)
+}
+
+// NewClassificationScorer 由指标函数构造分类指标，needsProbabilities为true时指标函数接收正类概率，否则接收类别
+func NewClassificationScorer(metric func(yTrue, yPred []float64) (float64, error), greaterIsBetter, needsProbabilities bool) Scorer {
+	return evaluation.NewClassificationScorer(metric, greaterIsBetter, needsProbabilities)
+}
+
+// RegisterScorer 按名称注册评估指标。注册后训练结果的Metrics和ModelManager记录的指标中会包含它
+// （分类指标只用于分类模型，其余只用于回归模型），ModelManager.CompareModels可以按它比较模型，
+// 也可以作为ValidationConfig.Scoring用于验证和参数搜索：越大越好的指标为name，误差类指标为"neg_"+name。
+// 内置指标有r2、mse、rmse、mae、mape、smape、median_ae，accuracy、balanced_accuracy、precision、recall、f1、
+// specificity、mcc、cohen_kappa，以及按概率计算的roc_auc、average_precision、logloss和brier_score，不能重复注册
+func RegisterScorer(name string, scorer Scorer) error {
+	if err := evaluation.RegisterScorer(name, scorer); err != nil {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to register scorer",
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// MetricNames 返回适用于分类模型（classifier为true）或回归模型的已注册指标名称
+func MetricNames(classifier bool) []string {
+	return evaluation.MetricNames(classifier)
+}
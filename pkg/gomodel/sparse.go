@@ -0,0 +1,99 @@
+package gomodel
+
+import (
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/models"
+	"github.com/feiyuluoye/Go-Model/internal/types"
+	"gonum.org/v1/gonum/mat"
+)
+
+// SparseMatrix 压缩稀疏行(CSR)格式的矩阵，用于独热编码、TF-IDF等绝大多数元素为0的特征，
+// Ridge、Lasso和Logistic可直接在其上训练而无需分配稠密的n×p矩阵
+type SparseMatrix = types.CSRMatrix
+
+// NewSparseMatrix 由CSR三元组创建稀疏矩阵：第i行的非零元素为data[indptr[i]:indptr[i+1]]，
+// 列号为indices的同一区间且严格递增
+func NewSparseMatrix(rows, cols int, indptr, indices []int, data []float64) (*SparseMatrix, error) {
+	matrix, err := types.NewCSRMatrix(rows, cols, indptr, indices, data)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "invalid sparse matrix",
+			Details: err.Error(),
+		}
+	}
+	return matrix, nil
+}
+
+// SparseFromDense 将稠密矩阵转换为稀疏矩阵
+func SparseFromDense(m mat.Matrix) *SparseMatrix {
+	return types.CSRFromDense(m)
+}
+
+// SparseModel 在稀疏特征上训练的线性模型
+type SparseModel struct {
+	Algorithm AlgorithmType
+	model     models.Model
+	nFeatures int
+}
+
+// TrainSparse 在稀疏特征上训练模型，支持Ridge、Lasso和Logistic，参数与Client.Train相同；
+// weights为nil时所有样本等权。Logistic不支持newton求解器
+func TrainSparse(X *SparseMatrix, y *mat.VecDense, weights *mat.VecDense, config *ModelConfig) (*SparseModel, error) {
+	if X == nil || y == nil || config == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "features, target and model config cannot be nil",
+		}
+	}
+	switch config.Algorithm {
+	case Ridge, Lasso, Logistic:
+	default:
+		return nil, &Error{
+			Code:    ErrInvalidAlgorithm,
+			Message: fmt.Sprintf("algorithm %s does not support sparse features", config.Algorithm),
+		}
+	}
+
+	model, err := models.NewModelManager().CreateModel(toInternalConfig(config))
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to create model",
+			Details: err.Error(),
+		}
+	}
+	if err := model.(models.SparseModel).FitSparse(X, y, weights); err != nil {
+		return nil, &Error{
+			Code:    ErrTrainingFailed,
+			Message: "failed to train model",
+			Details: err.Error(),
+		}
+	}
+
+	_, p := X.Dims()
+	return &SparseModel{Algorithm: config.Algorithm, model: model, nFeatures: p}, nil
+}
+
+// Predict 在稀疏特征上预测，Logistic返回正类概率
+func (m *SparseModel) Predict(X *SparseMatrix) (*mat.VecDense, error) {
+	if X == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	if _, p := X.Dims(); p != m.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", m.nFeatures, p),
+		}
+	}
+	return m.model.(models.SparseModel).PredictSparse(X), nil
+}
+
+// GetParameters 返回模型参数（系数、截距等）
+func (m *SparseModel) GetParameters() map[string]interface{} {
+	return m.model.GetParameters()
+}
@@ -0,0 +1,230 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/feiyuluoye/Go-Model/internal/evaluation"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Fold 交叉验证中一折的训练集和验证集样本索引
+type Fold = evaluation.Fold
+
+// Splitter 交叉验证划分器，返回每折的训练集和验证集样本索引。
+// 可设置在ValidationConfig.Splitter中代替随机K折划分，或传给Pipeline.CrossValidateSplits
+type Splitter interface {
+	Split(data *TrainingData) ([]Fold, error)
+}
+
+// TimeSeriesSplit 时间序列交叉验证划分器，要求样本按时间顺序排列，划分时不打乱：
+// 每折的验证集紧随训练集之后，训练集只包含更早的样本，预测模型不会用未来数据训练
+type TimeSeriesSplit struct {
+	NSplits      int `json:"n_splits"`       // 折数
+	TestSize     int `json:"test_size"`      // 每折验证集的样本数，为0时为 样本数/(NSplits+1)
+	Gap          int `json:"gap"`            // 训练集末尾与验证集开头之间跳过的样本数
+	MaxTrainSize int `json:"max_train_size"` // 训练集的最大样本数，大于0时为滚动窗口，否则为扩展窗口
+}
+
+// NewTimeSeriesSplit 创建扩展窗口、无间隔的时间序列划分器
+func NewTimeSeriesSplit(nSplits int) *TimeSeriesSplit {
+	return &TimeSeriesSplit{NSplits: nSplits}
+}
+
+// Split 按时间顺序划分样本
+func (s *TimeSeriesSplit) Split(data *TrainingData) ([]Fold, error) {
+	if data == nil || data.Features == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	n, _ := data.Features.Dims()
+	folds, err := (&evaluation.TimeSeriesSplit{
+		NSplits:      s.NSplits,
+		TestSize:     s.TestSize,
+		Gap:          s.Gap,
+		MaxTrainSize: s.MaxTrainSize,
+	}).Split(n)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to split time series",
+			Details: err.Error(),
+		}
+	}
+	return folds, nil
+}
+
+// StratifiedKFold 分层K折划分器：按目标变量的类别分层，使每折的类别比例与整体一致。
+// 分类模型的kfold验证默认即按此划分；也可以传给Pipeline.CrossValidateSplits
+type StratifiedKFold struct {
+	NSplits    int   `json:"n_splits"`    // 折数
+	RandomSeed int64 `json:"random_seed"` // 随机种子
+}
+
+// NewStratifiedKFold 创建分层K折划分器
+func NewStratifiedKFold(nSplits int, randomSeed int64) *StratifiedKFold {
+	return &StratifiedKFold{NSplits: nSplits, RandomSeed: randomSeed}
+}
+
+// Split 按目标变量的类别划分样本
+func (s *StratifiedKFold) Split(data *TrainingData) ([]Fold, error) {
+	if data == nil || data.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "target cannot be nil",
+		}
+	}
+	folds, err := evaluation.NewStratifiedKFold(s.NSplits, s.RandomSeed).Split(mat.Col(nil, 0, data.Target))
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to split classes",
+			Details: err.Error(),
+		}
+	}
+	return folds, nil
+}
+
+// RepeatedKFold 重复K折划分器：以不同的随机打乱重复NRepeats次K折划分，共NSplits×NRepeats折，
+// 在小数据集上得到更稳定的估计。可设置在ValidationConfig.Splitter中，或使用Client.RepeatedCrossValidate
+type RepeatedKFold struct {
+	NSplits    int   `json:"n_splits"`    // 每次重复的折数
+	NRepeats   int   `json:"n_repeats"`   // 重复次数
+	RandomSeed int64 `json:"random_seed"` // 随机种子
+	Stratify   bool  `json:"stratify"`    // 按目标变量的类别分层划分（用于分类）
+}
+
+// NewRepeatedKFold 创建不分层的重复K折划分器
+func NewRepeatedKFold(nSplits, nRepeats int, randomSeed int64) *RepeatedKFold {
+	return &RepeatedKFold{NSplits: nSplits, NRepeats: nRepeats, RandomSeed: randomSeed}
+}
+
+// Split 划分样本，第r次重复的各折位于结果的 [r×NSplits, (r+1)×NSplits) 区间
+func (s *RepeatedKFold) Split(data *TrainingData) ([]Fold, error) {
+	if data == nil || data.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "target cannot be nil",
+		}
+	}
+	folds, err := (&evaluation.RepeatedKFold{
+		NSplits:    s.NSplits,
+		NRepeats:   s.NRepeats,
+		RandomSeed: s.RandomSeed,
+		Stratify:   s.Stratify,
+	}).Split(mat.Col(nil, 0, data.Target))
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to split data",
+			Details: err.Error(),
+		}
+	}
+	return folds, nil
+}
+
+// GroupKFold 按分组的K折划分器：同一组的样本（如同一受试者的多次测量）总在同一折中，
+// 不会同时出现在训练集和验证集里，各折大小尽量均衡
+type GroupKFold struct {
+	NSplits     int      `json:"n_splits"`     // 折数
+	GroupColumn string   `json:"group_column"` // 组标签所在的特征列，该列仍作为特征参与训练
+	Groups      []string `json:"-"`            // 每个样本的组标签，设置后忽略GroupColumn
+}
+
+// NewGroupKFold 创建按groupColumn列分组的K折划分器
+func NewGroupKFold(nSplits int, groupColumn string) *GroupKFold {
+	return &GroupKFold{NSplits: nSplits, GroupColumn: groupColumn}
+}
+
+// Split 按组划分样本
+func (s *GroupKFold) Split(data *TrainingData) ([]Fold, error) {
+	groups, err := groupLabels(data, s.GroupColumn, s.Groups)
+	if err != nil {
+		return nil, err
+	}
+	folds, err := evaluation.NewGroupKFold(s.NSplits).Split(groups)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to split groups",
+			Details: err.Error(),
+		}
+	}
+	return folds, nil
+}
+
+// LeaveOneGroupOut 留一组交叉验证划分器：每折以一个组的全部样本为验证集，折数等于组数
+type LeaveOneGroupOut struct {
+	GroupColumn string   `json:"group_column"` // 组标签所在的特征列，该列仍作为特征参与训练
+	Groups      []string `json:"-"`            // 每个样本的组标签，设置后忽略GroupColumn
+}
+
+// NewLeaveOneGroupOut 创建按groupColumn列分组的留一组划分器
+func NewLeaveOneGroupOut(groupColumn string) *LeaveOneGroupOut {
+	return &LeaveOneGroupOut{GroupColumn: groupColumn}
+}
+
+// Split 按组划分样本
+func (s *LeaveOneGroupOut) Split(data *TrainingData) ([]Fold, error) {
+	groups, err := groupLabels(data, s.GroupColumn, s.Groups)
+	if err != nil {
+		return nil, err
+	}
+	folds, err := evaluation.LeaveOneGroupOut{}.Split(groups)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "failed to split groups",
+			Details: err.Error(),
+		}
+	}
+	return folds, nil
+}
+
+// groupLabels 返回每个样本的组标签：优先使用给定的groups，否则取column列的值（类别列取其标签）
+func groupLabels(data *TrainingData, column string, groups []string) ([]string, error) {
+	if data == nil || data.Features == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	n, _ := data.Features.Dims()
+	if groups != nil {
+		if len(groups) != n {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("expected %d group labels, got %d", n, len(groups)),
+			}
+		}
+		return groups, nil
+	}
+
+	j := -1
+	for k, name := range columnNames(data) {
+		if name == column {
+			j = k
+			break
+		}
+	}
+	if j < 0 {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("group column %q not found", column),
+		}
+	}
+	labels := make([]string, n)
+	for i := range labels {
+		v := data.Features.At(i, j)
+		if math.IsNaN(v) {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("missing group label at row %d", i),
+			}
+		}
+		labels[i] = categoryLabel(data.Categories[column], v)
+	}
+	return labels, nil
+}
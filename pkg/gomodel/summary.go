@@ -0,0 +1,164 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// maxCategoricalCardinality 数值列被视为疑似类别特征时允许的最多不同取值数
+const maxCategoricalCardinality = 20
+
+// ColumnSummary 单列的描述性统计，缺失值(NaN)不参与统计
+type ColumnSummary struct {
+	Name              string  `json:"name"`
+	Count             int     `json:"count"`   // 非缺失值个数
+	Missing           int     `json:"missing"` // 缺失值个数
+	Mean              float64 `json:"mean"`
+	Std               float64 `json:"std"` // 总体标准差
+	Min               float64 `json:"min"`
+	P5                float64 `json:"p5"`
+	Q1                float64 `json:"q1"`
+	Median            float64 `json:"median"`
+	Q3                float64 `json:"q3"`
+	P95               float64 `json:"p95"`
+	Max               float64 `json:"max"`
+	Skewness          float64 `json:"skewness"`           // 偏度，对称分布为0
+	Kurtosis          float64 `json:"kurtosis"`           // 超额峰度，正态分布为0
+	Cardinality       int     `json:"cardinality"`        // 不同取值的个数
+	Categorical       bool    `json:"categorical"`        // 疑似类别特征
+	TargetCorrelation float64 `json:"target_correlation"` // 与目标变量的皮尔逊相关系数，没有目标变量或对目标变量自身为NaN
+}
+
+// DataSummary 数据集的摘要统计
+type DataSummary struct {
+	Samples  int             `json:"samples"`
+	Features int             `json:"features"`
+	Columns  []ColumnSummary `json:"columns"` // 按列顺序排列的特征统计
+	Target   ColumnSummary   `json:"target"`
+}
+
+// Column 按名称查找特征的统计，不存在时返回nil
+func (s *DataSummary) Column(name string) *ColumnSummary {
+	for i := range s.Columns {
+		if s.Columns[i].Name == name {
+			return &s.Columns[i]
+		}
+	}
+	return nil
+}
+
+// GetDataSummary 获取数据摘要统计：每个特征和目标变量的缺失值个数、均值、标准差、分位数、偏度、峰度和不同取值个数，
+// 以及每个特征与目标变量的皮尔逊相关系数（只使用两者都不缺失的行）。
+// Categories中记录的特征，或取值均为整数且不同取值不超过20个、不超过非缺失值一半的特征被标记为疑似类别特征；
+// 常数列与目标变量的相关系数为0，不足以计算的统计量（如全为缺失值的列的均值、常数列的偏度）为NaN
+func (du *DataUtils) GetDataSummary(data *TrainingData) (*DataSummary, error) {
+	if data == nil || data.Features == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	r, c := data.Features.Dims()
+	var target []float64
+	if data.Target != nil {
+		if data.Target.Len() != r {
+			return nil, &Error{
+				Code:    ErrInvalidData,
+				Message: fmt.Sprintf("feature rows (%d) must match target length (%d)", r, data.Target.Len()),
+			}
+		}
+		target = mat.Col(nil, 0, data.Target)
+	}
+
+	summary := &DataSummary{
+		Samples:  r,
+		Features: c,
+		Columns:  make([]ColumnSummary, c),
+	}
+	for j, name := range columnNames(data) {
+		values := mat.Col(nil, j, data.Features)
+		column := summarizeColumn(name, values)
+		if _, categorical := data.Categories[name]; categorical {
+			column.Categorical = true
+		}
+		if target != nil {
+			column.TargetCorrelation = completeCorrelation(values, target, CorrelationPearson)
+		}
+		summary.Columns[j] = column
+	}
+
+	if target != nil {
+		name := data.TargetName
+		if name == "" {
+			name = "target"
+		}
+		summary.Target = summarizeColumn(name, target)
+	}
+	return summary, nil
+}
+
+// summarizeColumn 计算一列的描述性统计，与目标变量的相关系数由调用方填写，默认为NaN
+func summarizeColumn(name string, values []float64) ColumnSummary {
+	observed := observedValues(values)
+	sort.Float64s(observed)
+	nan := math.NaN()
+	s := ColumnSummary{
+		Name:              name,
+		Count:             len(observed),
+		Missing:           len(values) - len(observed),
+		Mean:              nan,
+		Std:               nan,
+		Min:               nan,
+		P5:                nan,
+		Q1:                nan,
+		Median:            nan,
+		Q3:                nan,
+		P95:               nan,
+		Max:               nan,
+		Skewness:          nan,
+		Kurtosis:          nan,
+		TargetCorrelation: nan,
+	}
+	if len(observed) == 0 {
+		return s
+	}
+
+	n := float64(len(observed))
+	sum := 0.0
+	integral := true
+	s.Cardinality = 1
+	for i, v := range observed {
+		sum += v
+		integral = integral && v == math.Trunc(v)
+		if i > 0 && v != observed[i-1] {
+			s.Cardinality++
+		}
+	}
+	s.Mean = sum / n
+
+	var m2, m3, m4 float64
+	for _, v := range observed {
+		d := v - s.Mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	m2, m3, m4 = m2/n, m3/n, m4/n
+	s.Std = math.Sqrt(m2)
+	if m2 > 0 {
+		s.Skewness = m3 / math.Pow(m2, 1.5)
+		s.Kurtosis = m4/(m2*m2) - 3
+	}
+
+	s.Min, s.Max = observed[0], observed[len(observed)-1]
+	s.P5 = quantile(observed, 0.05)
+	s.Q1 = quantile(observed, 0.25)
+	s.Median = quantile(observed, 0.5)
+	s.Q3 = quantile(observed, 0.75)
+	s.P95 = quantile(observed, 0.95)
+	s.Categorical = integral && s.Cardinality <= maxCategoricalCardinality && 2*s.Cardinality <= s.Count
+	return s
+}
@@ -0,0 +1,345 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// 合成数据的类型
+const (
+	SyntheticLinear         = "linear"         // 线性回归
+	SyntheticPolynomial     = "polynomial"     // 二次多项式回归
+	SyntheticClassification = "classification" // 线性决策边界的二分类
+	SyntheticFriedman1      = "friedman1"      // Friedman #1非线性回归，只有前5个特征有效
+	SyntheticSparse         = "sparse"         // 稀疏系数的线性回归
+	SyntheticBlobs          = "blobs"          // 各向同性高斯簇的多分类
+	SyntheticMoons          = "moons"          // 两个交错半月形的二分类
+	SyntheticImbalanced     = "imbalanced"     // 类别比例不平衡的分类
+)
+
+// SyntheticConfig 合成数据配置，未设置的字段使用各类型的默认值
+type SyntheticConfig struct {
+	Type        string    `json:"type"`
+	Samples     int       `json:"samples"`
+	Features    int       `json:"features"`              // friedman1至少为5；moons固定为2，为0时使用2
+	Noise       float64   `json:"noise"`                 // 回归为目标噪声的标准差，moons为坐标噪声的标准差，其他分类类型为标签翻转的比例
+	Informative int       `json:"informative,omitempty"` // sparse中非零系数的个数，默认为特征数的1/5（至少1个）
+	Centers     int       `json:"centers,omitempty"`     // blobs的簇数，默认3
+	ClusterStd  float64   `json:"cluster_std,omitempty"` // blobs和imbalanced中簇的标准差，默认1
+	Weights     []float64 `json:"weights,omitempty"`     // imbalanced中各类别的比例，默认[0.9, 0.1]
+}
+
+// SyntheticTruth 合成数据的真实生成参数，用于检验模型能否还原它们
+type SyntheticTruth struct {
+	Type          string      `json:"type"`
+	Coefficients  []float64   `json:"coefficients,omitempty"`   // sparse的真实系数，未生效的特征为0
+	Informative   []int       `json:"informative,omitempty"`    // 影响目标变量的特征下标，按升序排列
+	Centers       [][]float64 `json:"centers,omitempty"`        // blobs和imbalanced中各类别的簇中心，下标即类别
+	ClassCounts   []int       `json:"class_counts,omitempty"`   // 分类数据中各类别的样本数（标签翻转之前）
+	FlippedLabels []int       `json:"flipped_labels,omitempty"` // 被翻转标签的样本行号
+	NoiseStd      float64     `json:"noise_std"`                // 回归目标噪声的标准差，理想模型的RMSE约等于该值
+}
+
+// GenerateSynthetic 按配置生成合成数据并返回真实生成参数。
+// linear、polynomial和classification与GenerateSyntheticData的同名类型相同，只返回类型信息；
+// 同一个DataUtils（相同随机种子）总是生成相同的数据
+func (du *DataUtils) GenerateSynthetic(config *SyntheticConfig) (*TrainingData, *SyntheticTruth, error) {
+	if config == nil || config.Samples <= 0 {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "number of samples must be positive",
+		}
+	}
+	if config.Noise < 0 || math.IsNaN(config.Noise) {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("noise cannot be negative, got %g", config.Noise),
+		}
+	}
+	if config.Type != SyntheticMoons && config.Features <= 0 {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "number of features must be positive",
+		}
+	}
+
+	rng := du.newRand()
+	switch config.Type {
+	case SyntheticLinear, SyntheticPolynomial, SyntheticClassification:
+		d, err := du.GenerateSyntheticData(config.Samples, config.Features, config.Noise, config.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		return d, &SyntheticTruth{Type: config.Type}, nil
+	case SyntheticFriedman1:
+		return du.generateFriedman1(config, rng)
+	case SyntheticSparse:
+		return du.generateSparse(config, rng)
+	case SyntheticBlobs:
+		return du.generateBlobs(config, rng)
+	case SyntheticMoons:
+		return du.generateMoons(config, rng)
+	case SyntheticImbalanced:
+		return du.generateImbalanced(config, rng)
+	default:
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported synthetic data type: %s", config.Type),
+		}
+	}
+}
+
+// generateFriedman1 生成Friedman #1数据：特征服从[0,1]均匀分布，
+// y = 10sin(πx0x1) + 20(x2-0.5)² + 10x3 + 5x4 + 噪声，其余特征与目标无关
+func (du *DataUtils) generateFriedman1(config *SyntheticConfig, rng *rand.Rand) (*TrainingData, *SyntheticTruth, error) {
+	if config.Features < 5 {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("friedman1 requires at least 5 features, got %d", config.Features),
+		}
+	}
+	X := make([][]float64, config.Samples)
+	y := make([]float64, config.Samples)
+	for i := range X {
+		X[i] = make([]float64, config.Features)
+		for j := range X[i] {
+			X[i][j] = rng.Float64()
+		}
+		x := X[i]
+		y[i] = 10*math.Sin(math.Pi*x[0]*x[1]) + 20*(x[2]-0.5)*(x[2]-0.5) + 10*x[3] + 5*x[4] + rng.NormFloat64()*config.Noise
+	}
+	d, err := du.CreateFromArrays(X, y, nil, "target")
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, &SyntheticTruth{Type: SyntheticFriedman1, Informative: []int{0, 1, 2, 3, 4}, NoiseStd: config.Noise}, nil
+}
+
+// generateSparse 生成稀疏线性回归数据：特征服从标准正态分布，随机选出的Informative个特征的系数
+// 绝对值在[1,3]之间、符号随机，其余系数为0
+func (du *DataUtils) generateSparse(config *SyntheticConfig, rng *rand.Rand) (*TrainingData, *SyntheticTruth, error) {
+	informative := config.Informative
+	if informative == 0 {
+		informative = max(1, config.Features/5)
+	}
+	if informative < 0 || informative > config.Features {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("informative features must be between 1 and %d, got %d", config.Features, informative),
+		}
+	}
+
+	active := rng.Perm(config.Features)[:informative]
+	sort.Ints(active)
+	coefficients := make([]float64, config.Features)
+	for _, j := range active {
+		coefficients[j] = 1 + 2*rng.Float64()
+		if rng.Intn(2) == 0 {
+			coefficients[j] = -coefficients[j]
+		}
+	}
+
+	X := make([][]float64, config.Samples)
+	y := make([]float64, config.Samples)
+	for i := range X {
+		X[i] = make([]float64, config.Features)
+		for j := range X[i] {
+			X[i][j] = rng.NormFloat64()
+			y[i] += coefficients[j] * X[i][j]
+		}
+		y[i] += rng.NormFloat64() * config.Noise
+	}
+	d, err := du.CreateFromArrays(X, y, nil, "target")
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, &SyntheticTruth{Type: SyntheticSparse, Coefficients: coefficients, Informative: active, NoiseStd: config.Noise}, nil
+}
+
+// generateBlobs 生成高斯簇数据：簇中心在[-10,10]内均匀分布，样本轮流分配给各簇，类别标签为簇的下标
+func (du *DataUtils) generateBlobs(config *SyntheticConfig, rng *rand.Rand) (*TrainingData, *SyntheticTruth, error) {
+	centers := config.Centers
+	if centers == 0 {
+		centers = 3
+	}
+	if centers < 2 || centers > config.Samples {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("number of centers must be between 2 and %d, got %d", config.Samples, centers),
+		}
+	}
+
+	truth := &SyntheticTruth{Type: SyntheticBlobs, Centers: make([][]float64, centers), ClassCounts: make([]int, centers)}
+	for k := range truth.Centers {
+		truth.Centers[k] = make([]float64, config.Features)
+		for j := range truth.Centers[k] {
+			truth.Centers[k][j] = rng.Float64()*20 - 10
+		}
+	}
+	labels := make([]int, config.Samples)
+	for i := range labels {
+		labels[i] = i % centers
+	}
+	X, y, err := gaussianClusters(truth, labels, config, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	d, err := du.CreateFromArrays(X, y, nil, "class")
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, truth, nil
+}
+
+// generateMoons 生成两个交错的半月形：类别0在上半圆，类别1为向右下平移的下半圆，坐标加上标准差为Noise的高斯噪声
+func (du *DataUtils) generateMoons(config *SyntheticConfig, rng *rand.Rand) (*TrainingData, *SyntheticTruth, error) {
+	if config.Features != 0 && config.Features != 2 {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("moons data has exactly 2 features, got %d", config.Features),
+		}
+	}
+	if config.Samples < 2 {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "moons data requires at least 2 samples",
+		}
+	}
+
+	outer := (config.Samples + 1) / 2
+	inner := config.Samples - outer
+	X := make([][]float64, 0, config.Samples)
+	y := make([]float64, 0, config.Samples)
+	for i := 0; i < outer; i++ {
+		t := math.Pi * float64(i) / float64(max(outer-1, 1))
+		X = append(X, []float64{math.Cos(t), math.Sin(t)})
+		y = append(y, 0)
+	}
+	for i := 0; i < inner; i++ {
+		t := math.Pi * float64(i) / float64(max(inner-1, 1))
+		X = append(X, []float64{1 - math.Cos(t), 0.5 - math.Sin(t)})
+		y = append(y, 1)
+	}
+	// 打乱顺序，使按顺序划分的训练集和测试集都包含两个类别
+	rng.Shuffle(len(X), func(a, b int) {
+		X[a], X[b] = X[b], X[a]
+		y[a], y[b] = y[b], y[a]
+	})
+	for _, row := range X {
+		for j := range row {
+			row[j] += rng.NormFloat64() * config.Noise
+		}
+	}
+	d, err := du.CreateFromArrays(X, y, nil, "class")
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, &SyntheticTruth{Type: SyntheticMoons, ClassCounts: []int{outer, inner}}, nil
+}
+
+// generateImbalanced 生成类别不平衡的分类数据：各类别的样本数按Weights的比例分配，
+// 每个类别是以[-2,2]内随机中心为均值的高斯簇，类别之间有一定重叠
+func (du *DataUtils) generateImbalanced(config *SyntheticConfig, rng *rand.Rand) (*TrainingData, *SyntheticTruth, error) {
+	weights := config.Weights
+	if len(weights) == 0 {
+		weights = []float64{0.9, 0.1}
+	}
+	if len(weights) < 2 {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "imbalanced data requires at least 2 class weights",
+		}
+	}
+	total := 0.0
+	for _, w := range weights {
+		if w <= 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, nil, &Error{
+				Code:    ErrInvalidParameters,
+				Message: fmt.Sprintf("class weights must be positive, got %v", weights),
+			}
+		}
+		total += w
+	}
+
+	// 按比例分配样本数，每个类别至少1个，舍入误差计入最多的类别
+	counts := make([]int, len(weights))
+	assigned, largest := 0, 0
+	for k, w := range weights {
+		counts[k] = max(1, int(math.Round(float64(config.Samples)*w/total)))
+		assigned += counts[k]
+		if counts[k] > counts[largest] {
+			largest = k
+		}
+	}
+	counts[largest] += config.Samples - assigned
+	if counts[largest] < 1 {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("%d samples are too few for %d classes", config.Samples, len(weights)),
+		}
+	}
+
+	truth := &SyntheticTruth{Type: SyntheticImbalanced, Centers: make([][]float64, len(weights)), ClassCounts: make([]int, len(weights))}
+	for k := range truth.Centers {
+		truth.Centers[k] = make([]float64, config.Features)
+		for j := range truth.Centers[k] {
+			truth.Centers[k][j] = rng.Float64()*4 - 2
+		}
+	}
+	labels := make([]int, 0, config.Samples)
+	for k, count := range counts {
+		for i := 0; i < count; i++ {
+			labels = append(labels, k)
+		}
+	}
+	rng.Shuffle(len(labels), func(a, b int) { labels[a], labels[b] = labels[b], labels[a] })
+	X, y, err := gaussianClusters(truth, labels, config, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	d, err := du.CreateFromArrays(X, y, nil, "class")
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, truth, nil
+}
+
+// gaussianClusters 按labels在truth.Centers周围生成高斯样本并统计ClassCounts；
+// Noise为标签翻转的比例，被翻转的样本随机改为其他类别并记录在FlippedLabels中
+func gaussianClusters(truth *SyntheticTruth, labels []int, config *SyntheticConfig, rng *rand.Rand) ([][]float64, []float64, error) {
+	std := config.ClusterStd
+	if std == 0 {
+		std = 1
+	}
+	if std < 0 || math.IsNaN(std) {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("cluster standard deviation cannot be negative, got %g", std),
+		}
+	}
+	if config.Noise > 1 {
+		return nil, nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("label noise must be between 0 and 1, got %g", config.Noise),
+		}
+	}
+
+	classes := len(truth.Centers)
+	X := make([][]float64, len(labels))
+	y := make([]float64, len(labels))
+	for i, label := range labels {
+		truth.ClassCounts[label]++
+		X[i] = make([]float64, len(truth.Centers[label]))
+		for j, center := range truth.Centers[label] {
+			X[i][j] = center + rng.NormFloat64()*std
+		}
+		y[i] = float64(label)
+		if rng.Float64() < config.Noise {
+			y[i] = float64((label + 1 + rng.Intn(classes-1)) % classes)
+			truth.FlippedLabels = append(truth.FlippedLabels, i)
+		}
+	}
+	return X, y, nil
+}
@@ -0,0 +1,311 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Tokenizer 将一段文本切分为记号
+type Tokenizer func(text string) []string
+
+// DefaultTokenizer 按非字母、非数字的字符切分文本，丢弃只有一个字符的记号
+func DefaultTokenizer(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	tokens := fields[:0]
+	for _, field := range fields {
+		if len([]rune(field)) > 1 {
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
+
+// CountVectorizer 词袋模型：在训练文本上建立词表，将每篇文本转换为各词条出现次数的稀疏向量，
+// 结果可直接用于TrainSparse训练Logistic或Lasso。词条为NGramMin到NGramMax个连续记号（以空格连接），
+// 词表按字母顺序排列，训练时未出现的词条在转换时被忽略
+type CountVectorizer struct {
+	Lowercase   bool           `json:"lowercase"`    // 分词前是否转换为小写
+	NGramMin    int            `json:"ngram_min"`    // 最短的n元组，默认1
+	NGramMax    int            `json:"ngram_max"`    // 最长的n元组，默认1
+	MinDF       int            `json:"min_df"`       // 词条至少出现在多少篇文本中，默认1
+	MaxDF       float64        `json:"max_df"`       // 词条最多出现在多大比例的文本中，默认1，用于过滤过于常见的词
+	MaxFeatures int            `json:"max_features"` // 只保留总出现次数最多的若干词条，0表示不限制
+	StopWords   []string       `json:"stop_words"`   // 分词后移除的停用词
+	Binary      bool           `json:"binary"`       // 为true时只记录是否出现（0/1）而非次数
+	Tokenizer   Tokenizer      `json:"-"`            // 分词函数，为nil时使用DefaultTokenizer
+	Vocabulary  map[string]int `json:"vocabulary"`   // 学到的词条到列号的映射
+	Terms       []string       `json:"terms"`        // 按列号排列的词条
+}
+
+// NewCountVectorizer 创建只使用单个词、转换为小写的词袋向量化器
+func NewCountVectorizer() *CountVectorizer {
+	return &CountVectorizer{Lowercase: true, NGramMin: 1, NGramMax: 1, MinDF: 1, MaxDF: 1}
+}
+
+// Fit 在训练文本上建立词表
+func (v *CountVectorizer) Fit(documents []string) error {
+	if len(documents) == 0 {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "documents cannot be empty",
+		}
+	}
+	if err := v.validate(); err != nil {
+		return err
+	}
+
+	documentFrequency := make(map[string]int)
+	totalCount := make(map[string]int)
+	for _, document := range documents {
+		seen := make(map[string]bool)
+		for _, term := range v.terms(document) {
+			totalCount[term]++
+			if !seen[term] {
+				seen[term] = true
+				documentFrequency[term]++
+			}
+		}
+	}
+
+	minDF := max(v.MinDF, 1)
+	maxDF := len(documents)
+	if v.MaxDF > 0 {
+		maxDF = int(math.Floor(v.MaxDF * float64(len(documents))))
+	}
+	var terms []string
+	for term, df := range documentFrequency {
+		if df >= minDF && df <= maxDF {
+			terms = append(terms, term)
+		}
+	}
+	if v.MaxFeatures > 0 && len(terms) > v.MaxFeatures {
+		sort.Slice(terms, func(a, b int) bool {
+			if totalCount[terms[a]] != totalCount[terms[b]] {
+				return totalCount[terms[a]] > totalCount[terms[b]]
+			}
+			return terms[a] < terms[b]
+		})
+		terms = terms[:v.MaxFeatures]
+	}
+	if len(terms) == 0 {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "empty vocabulary; documents may contain only stop words or min_df/max_df are too strict",
+		}
+	}
+	sort.Strings(terms)
+
+	v.Terms = terms
+	v.Vocabulary = make(map[string]int, len(terms))
+	for j, term := range terms {
+		v.Vocabulary[term] = j
+	}
+	return nil
+}
+
+// Transform 将文本转换为词条计数的稀疏矩阵，每行一篇文本
+func (v *CountVectorizer) Transform(documents []string) (*SparseMatrix, error) {
+	if v.Vocabulary == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "vectorizer is not fitted",
+		}
+	}
+	if len(documents) == 0 {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "documents cannot be empty",
+		}
+	}
+
+	indptr := make([]int, len(documents)+1)
+	var indices []int
+	var values []float64
+	for i, document := range documents {
+		counts := make(map[int]float64)
+		for _, term := range v.terms(document) {
+			if j, ok := v.Vocabulary[term]; ok {
+				counts[j]++
+			}
+		}
+		columns := make([]int, 0, len(counts))
+		for j := range counts {
+			columns = append(columns, j)
+		}
+		sort.Ints(columns)
+		for _, j := range columns {
+			indices = append(indices, j)
+			if v.Binary {
+				values = append(values, 1)
+			} else {
+				values = append(values, counts[j])
+			}
+		}
+		indptr[i+1] = len(values)
+	}
+	return NewSparseMatrix(len(documents), len(v.Terms), indptr, indices, values)
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (v *CountVectorizer) FitTransform(documents []string) (*SparseMatrix, error) {
+	if err := v.Fit(documents); err != nil {
+		return nil, err
+	}
+	return v.Transform(documents)
+}
+
+// terms 对文本分词、移除停用词并生成n元组词条
+func (v *CountVectorizer) terms(document string) []string {
+	if v.Lowercase {
+		document = strings.ToLower(document)
+	}
+	tokenize := v.Tokenizer
+	if tokenize == nil {
+		tokenize = DefaultTokenizer
+	}
+	tokens := tokenize(document)
+	if len(v.StopWords) > 0 {
+		stop := make(map[string]bool, len(v.StopWords))
+		for _, word := range v.StopWords {
+			if v.Lowercase {
+				word = strings.ToLower(word)
+			}
+			stop[word] = true
+		}
+		kept := tokens[:0]
+		for _, token := range tokens {
+			if !stop[token] {
+				kept = append(kept, token)
+			}
+		}
+		tokens = kept
+	}
+
+	minN, maxN := max(v.NGramMin, 1), max(v.NGramMax, 1)
+	var terms []string
+	for n := minN; n <= maxN; n++ {
+		for start := 0; start+n <= len(tokens); start++ {
+			terms = append(terms, strings.Join(tokens[start:start+n], " "))
+		}
+	}
+	return terms
+}
+
+// validate 检查n元组范围和文档频率阈值
+func (v *CountVectorizer) validate() error {
+	if v.NGramMin < 0 || v.NGramMax < 0 || max(v.NGramMin, 1) > max(v.NGramMax, 1) {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("invalid n-gram range [%d, %d]", v.NGramMin, v.NGramMax),
+		}
+	}
+	if v.MaxDF < 0 || v.MaxDF > 1 || v.MinDF < 0 || v.MaxFeatures < 0 {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "max_df must be in [0, 1], and min_df and max_features cannot be negative",
+		}
+	}
+	return nil
+}
+
+// TfidfVectorizer TF-IDF向量化器：在词条计数上乘以逆文档频率，降低在大多数文本中都出现的词条的权重，
+// 每行再按Norm归一化。IDF在训练文本上计算并保存在IDF中，转换新文本时使用相同的权重
+type TfidfVectorizer struct {
+	CountVectorizer
+	Norm        string    `json:"norm"`         // 行归一化方式：l2（默认）、l1或none
+	SmoothIDF   bool      `json:"smooth_idf"`   // 为true时IDF=ln((1+n)/(1+df))+1，否则为ln(n/df)+1
+	SublinearTF bool      `json:"sublinear_tf"` // 为true时词频取1+ln(tf)
+	IDF         []float64 `json:"idf"`          // 每个词条的逆文档频率
+}
+
+// NewTfidfVectorizer 创建使用平滑IDF和l2归一化的TF-IDF向量化器
+func NewTfidfVectorizer() *TfidfVectorizer {
+	return &TfidfVectorizer{CountVectorizer: *NewCountVectorizer(), Norm: "l2", SmoothIDF: true}
+}
+
+// Fit 在训练文本上建立词表并计算每个词条的逆文档频率
+func (v *TfidfVectorizer) Fit(documents []string) error {
+	switch v.Norm {
+	case "", "l2", "l1", "none":
+	default:
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("unsupported norm: %s", v.Norm),
+		}
+	}
+	counts, err := v.CountVectorizer.FitTransform(documents)
+	if err != nil {
+		return err
+	}
+
+	documentFrequency := make([]float64, len(v.Terms))
+	for _, j := range counts.Indices {
+		documentFrequency[j]++
+	}
+	n := float64(len(documents))
+	v.IDF = make([]float64, len(v.Terms))
+	for j, df := range documentFrequency {
+		if v.SmoothIDF {
+			v.IDF[j] = math.Log((1+n)/(1+df)) + 1
+		} else {
+			v.IDF[j] = math.Log(n/df) + 1
+		}
+	}
+	return nil
+}
+
+// Transform 将文本转换为TF-IDF权重的稀疏矩阵，每行一篇文本
+func (v *TfidfVectorizer) Transform(documents []string) (*SparseMatrix, error) {
+	if v.IDF == nil {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "vectorizer is not fitted",
+		}
+	}
+	counts, err := v.CountVectorizer.Transform(documents)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _ := counts.Dims()
+	weights := make([]float64, len(counts.Data))
+	for i := 0; i < rows; i++ {
+		start, end := counts.Indptr[i], counts.Indptr[i+1]
+		norm := 0.0
+		for k := start; k < end; k++ {
+			tf := counts.Data[k]
+			if v.SublinearTF {
+				tf = 1 + math.Log(tf)
+			}
+			weights[k] = tf * v.IDF[counts.Indices[k]]
+			switch v.Norm {
+			case "l1":
+				norm += math.Abs(weights[k])
+			case "", "l2":
+				norm += weights[k] * weights[k]
+			}
+		}
+		if v.Norm == "" || v.Norm == "l2" {
+			norm = math.Sqrt(norm)
+		}
+		if norm > 0 {
+			for k := start; k < end; k++ {
+				weights[k] /= norm
+			}
+		}
+	}
+	return NewSparseMatrix(rows, len(v.Terms), counts.Indptr, counts.Indices, weights)
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (v *TfidfVectorizer) FitTransform(documents []string) (*SparseMatrix, error) {
+	if err := v.Fit(documents); err != nil {
+		return nil, err
+	}
+	return v.Transform(documents)
+}
@@ -0,0 +1,308 @@
+package gomodel
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// TransformCache 变换结果的缓存，键由CachedTransformer根据变换器参数和数据指纹生成。
+// 实现必须可以被并发调用；返回的数据会被多个调用方共享，不应修改
+type TransformCache interface {
+	// Get 返回键对应的缓存数据
+	Get(key string) (*TrainingData, bool)
+	// Put 保存键对应的数据
+	Put(key string, data *TrainingData) error
+}
+
+// MemoryCache 内存中的LRU缓存
+type MemoryCache struct {
+	MaxEntries int // 最多保存的条目数，超过时淘汰最久未使用的条目，<=0表示不限制
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+// memoryEntry LRU链表中的一个条目
+type memoryEntry struct {
+	key  string
+	data *TrainingData
+}
+
+// NewMemoryCache 创建最多保存maxEntries个条目的内存缓存
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		MaxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get 返回键对应的缓存数据，并将其标记为最近使用
+func (c *MemoryCache) Get(key string) (*TrainingData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*memoryEntry).data, true
+}
+
+// Put 保存键对应的数据，必要时淘汰最久未使用的条目
+func (c *MemoryCache) Put(key string, data *TrainingData) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*memoryEntry).data = data
+		c.order.MoveToFront(element)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, data: data})
+	for c.MaxEntries > 0 && c.order.Len() > c.MaxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Len 返回缓存的条目数
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// DiskCache 磁盘缓存，每个条目保存为目录下的一个gob文件，可在多次运行之间复用
+type DiskCache struct {
+	Dir string
+}
+
+// cachedData 磁盘缓存中训练数据的序列化形式
+type cachedData struct {
+	Rows, Cols   int
+	Features     []float64
+	Target       []float64
+	Weights      []float64
+	HasTarget    bool
+	HasWeights   bool
+	FeatureNames []string
+	TargetName   string
+	Categories   map[string][]string
+}
+
+// NewDiskCache 创建保存在dir目录下的磁盘缓存，目录不存在时自动创建
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("failed to create cache directory %s", dir),
+			Details: err.Error(),
+		}
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+// Get 读取键对应的缓存文件，文件不存在或无法解码时视为未命中
+func (c *DiskCache) Get(key string) (*TrainingData, bool) {
+	file, err := os.Open(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	var cached cachedData
+	if err := gob.NewDecoder(file).Decode(&cached); err != nil || cached.Rows*cached.Cols != len(cached.Features) {
+		return nil, false
+	}
+	d := &TrainingData{
+		FeatureNames: cached.FeatureNames,
+		TargetName:   cached.TargetName,
+		Categories:   cached.Categories,
+	}
+	if cached.Rows > 0 && cached.Cols > 0 {
+		d.Features = mat.NewDense(cached.Rows, cached.Cols, cached.Features)
+	}
+	if cached.HasTarget {
+		d.Target = mat.NewVecDense(len(cached.Target), cached.Target)
+	}
+	if cached.HasWeights {
+		d.Weights = mat.NewVecDense(len(cached.Weights), cached.Weights)
+	}
+	return d, true
+}
+
+// Put 将数据写入键对应的缓存文件；先写入临时文件再重命名，避免并发读取到不完整的文件
+func (c *DiskCache) Put(key string, d *TrainingData) error {
+	cached := cachedData{
+		FeatureNames: d.FeatureNames,
+		TargetName:   d.TargetName,
+		Categories:   d.Categories,
+	}
+	if d.Features != nil {
+		cached.Rows, cached.Cols = d.Features.Dims()
+		cached.Features = mat.DenseCopyOf(d.Features).RawMatrix().Data
+	}
+	if d.Target != nil {
+		cached.HasTarget = true
+		cached.Target = mat.Col(nil, 0, d.Target)
+	}
+	if d.Weights != nil {
+		cached.HasWeights = true
+		cached.Weights = mat.Col(nil, 0, d.Weights)
+	}
+
+	file, err := os.CreateTemp(c.Dir, "tmp-*")
+	if err != nil {
+		return cacheWriteError(err)
+	}
+	if err := gob.NewEncoder(file).Encode(&cached); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return cacheWriteError(err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return cacheWriteError(err)
+	}
+	if err := os.Rename(file.Name(), c.path(key)); err != nil {
+		os.Remove(file.Name())
+		return cacheWriteError(err)
+	}
+	return nil
+}
+
+// path 返回键对应的缓存文件路径
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".gob")
+}
+
+// cacheWriteError 包装写入缓存文件时的错误
+func cacheWriteError(err error) error {
+	return &Error{
+		Code:    ErrInvalidData,
+		Message: "failed to write transform cache",
+		Details: err.Error(),
+	}
+}
+
+// CachedTransformer 为计算量大的变换器（如多项式展开）缓存变换结果，可作为流水线步骤。
+// 缓存键由变换器的类型和参数（包装时的JSON序列化结果）以及数据指纹（见TrainingData.Fingerprint）组成，
+// 网格搜索中同一折数据在不同候选参数下反复拟合同一个变换时直接使用缓存结果。
+// 命中缓存时不会拟合被包装的变换器，直到Transform未命中缓存时才在当时的训练数据上补做拟合。
+// 只适用于结果完全由参数和数据决定的变换器；包装之后不应再修改被包装变换器的参数
+type CachedTransformer struct {
+	Transformer Transformer    `json:"-"`
+	Cache       TransformCache `json:"-"`
+	Hits        int            `json:"hits"`   // 命中缓存的次数
+	Misses      int            `json:"misses"` // 未命中缓存的次数
+	params      string
+	fitHash     string
+	pending     *TrainingData // 命中缓存后尚未真正拟合的训练数据
+}
+
+// NewCachedTransformer 使用cache缓存transformer的变换结果
+func NewCachedTransformer(transformer Transformer, cache TransformCache) (*CachedTransformer, error) {
+	if transformer == nil || cache == nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "transformer and cache cannot be nil",
+		}
+	}
+	params, err := json.Marshal(transformer)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("cannot derive cache key from transformer %T", transformer),
+			Details: err.Error(),
+		}
+	}
+	return &CachedTransformer{
+		Transformer: transformer,
+		Cache:       cache,
+		params:      fmt.Sprintf("%T%s", transformer, params),
+	}, nil
+}
+
+// Fit 拟合被包装的变换器
+func (c *CachedTransformer) Fit(d *TrainingData) error {
+	if err := c.Transformer.Fit(d); err != nil {
+		return err
+	}
+	c.fitHash = d.Fingerprint()
+	c.pending = nil
+	return nil
+}
+
+// Transform 返回缓存的变换结果，未命中时使用被包装的变换器变换并写入缓存
+func (c *CachedTransformer) Transform(d *TrainingData) (*TrainingData, error) {
+	if c.fitHash == "" {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	key := c.key("transform", c.fitHash, d.Fingerprint())
+	if cached, ok := c.Cache.Get(key); ok {
+		c.Hits++
+		return cached, nil
+	}
+	c.Misses++
+	if c.pending != nil {
+		if err := c.Transformer.Fit(c.pending); err != nil {
+			return nil, err
+		}
+		c.pending = nil
+	}
+	result, err := c.Transformer.Transform(d)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Cache.Put(key, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FitTransform 返回缓存的拟合变换结果，未命中时拟合并变换数据后写入缓存
+func (c *CachedTransformer) FitTransform(d *TrainingData) (*TrainingData, error) {
+	fitHash := d.Fingerprint()
+	key := c.key("fit", fitHash)
+	if cached, ok := c.Cache.Get(key); ok {
+		c.Hits++
+		c.fitHash = fitHash
+		c.pending = d
+		return cached, nil
+	}
+	c.Misses++
+	result, err := c.Transformer.FitTransform(d)
+	if err != nil {
+		return nil, err
+	}
+	c.fitHash = fitHash
+	c.pending = nil
+	if err := c.Cache.Put(key, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// key 由变换器参数和各部分拼接后取SHA-256得到缓存键
+func (c *CachedTransformer) key(parts ...string) string {
+	h := sha256.New()
+	writeString(h, c.params)
+	for _, part := range parts {
+		writeString(h, part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,309 @@
+package gomodel
+
+import (
+	"fmt"
+
+	"github.com/feiyuluoye/Go-Model/internal/data"
+	"github.com/feiyuluoye/Go-Model/internal/types"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Transformer 特征变换器接口，所有预处理步骤都实现该接口以便在流水线中统一组合
+type Transformer interface {
+	// Fit 在训练数据上学习变换参数
+	Fit(data *TrainingData) error
+	// Transform 使用已学到的参数变换数据，目标变量保持不变
+	Transform(data *TrainingData) (*TrainingData, error)
+	// FitTransform 结合Fit和Transform一步完成
+	FitTransform(data *TrainingData) (*TrainingData, error)
+}
+
+// TargetTransformer 目标变量变换器接口，设置为Pipeline.TargetTransformer后，
+// 流水线在变换后的目标变量上训练模型，预测时将预测值逆变换回原始尺度
+type TargetTransformer interface {
+	// FitTarget 在训练数据的目标变量上学习变换参数
+	FitTarget(y []float64) error
+	// TransformTarget 使用已学到的参数变换目标变量
+	TransformTarget(y []float64) ([]float64, error)
+	// InverseTransformTarget 将变换尺度上的值还原为原始尺度
+	InverseTransformTarget(y []float64) ([]float64, error)
+}
+
+// StandardScaler 特征标准化（z-score），可作为流水线步骤
+type StandardScaler struct {
+	scaler *data.StandardScaler
+}
+
+// NewStandardScaler 创建新的标准化变换器
+func NewStandardScaler() *StandardScaler {
+	return &StandardScaler{scaler: data.NewStandardScaler()}
+}
+
+// Fit 计算各特征的均值和标准差
+func (s *StandardScaler) Fit(d *TrainingData) error {
+	return s.scaler.Fit(toDataset(d))
+}
+
+// Transform 使用学到的均值和标准差标准化特征
+func (s *StandardScaler) Transform(d *TrainingData) (*TrainingData, error) {
+	dataset, err := s.scaler.Transform(toDataset(d))
+	if err != nil {
+		return nil, err
+	}
+	return fromDataset(dataset, d), nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (s *StandardScaler) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(s, d)
+}
+
+// MinMaxScaler 特征归一化到[0, 1]，可作为流水线步骤
+type MinMaxScaler struct {
+	scaler *data.MinMaxScaler
+}
+
+// NewMinMaxScaler 创建新的归一化变换器
+func NewMinMaxScaler() *MinMaxScaler {
+	return &MinMaxScaler{scaler: data.NewMinMaxScaler()}
+}
+
+// Fit 计算各特征的最小值和最大值
+func (s *MinMaxScaler) Fit(d *TrainingData) error {
+	return s.scaler.Fit(toDataset(d))
+}
+
+// Transform 使用学到的最小值和最大值归一化特征
+func (s *MinMaxScaler) Transform(d *TrainingData) (*TrainingData, error) {
+	dataset, err := s.scaler.Transform(toDataset(d))
+	if err != nil {
+		return nil, err
+	}
+	return fromDataset(dataset, d), nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (s *MinMaxScaler) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(s, d)
+}
+
+// RobustScaler 按中位数中心化、按四分位距缩放特征，可作为流水线步骤。
+// 含异常值时StandardScaler的均值和标准差会被拉偏，RobustScaler的统计量则不受影响
+type RobustScaler struct {
+	scaler *data.RobustScaler
+}
+
+// NewRobustScaler 创建新的稳健缩放变换器，按第25到75百分位数的四分位距缩放
+func NewRobustScaler() *RobustScaler {
+	return &RobustScaler{scaler: data.NewRobustScaler()}
+}
+
+// NewRobustScalerWithRange 创建按[lower, upper]分位距缩放的稳健缩放变换器，如(0.1, 0.9)
+func NewRobustScalerWithRange(lower, upper float64) (*RobustScaler, error) {
+	if lower < 0 || upper > 1 || lower >= upper {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: fmt.Sprintf("quantile range must satisfy 0 <= lower < upper <= 1, got lower=%g upper=%g", lower, upper),
+		}
+	}
+	scaler := data.NewRobustScaler()
+	scaler.QuantileLower, scaler.QuantileUpper = lower, upper
+	return &RobustScaler{scaler: scaler}, nil
+}
+
+// Fit 计算各特征的中位数和分位距
+func (s *RobustScaler) Fit(d *TrainingData) error {
+	return s.scaler.Fit(toDataset(d))
+}
+
+// Transform 使用学到的中位数和分位距缩放特征
+func (s *RobustScaler) Transform(d *TrainingData) (*TrainingData, error) {
+	dataset, err := s.scaler.Transform(toDataset(d))
+	if err != nil {
+		return nil, err
+	}
+	return fromDataset(dataset, d), nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (s *RobustScaler) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(s, d)
+}
+
+// MaxAbsScaler 将每个特征除以其最大绝对值，缩放到[-1, 1]，可作为流水线步骤。
+// 不平移数据，零值仍为零，因此也可以直接缩放SparseMatrix而不破坏稀疏性
+type MaxAbsScaler struct {
+	scaler *data.MaxAbsScaler
+}
+
+// NewMaxAbsScaler 创建新的最大绝对值缩放变换器
+func NewMaxAbsScaler() *MaxAbsScaler {
+	return &MaxAbsScaler{scaler: data.NewMaxAbsScaler()}
+}
+
+// Fit 计算各特征的最大绝对值
+func (s *MaxAbsScaler) Fit(d *TrainingData) error {
+	return s.scaler.Fit(toDataset(d))
+}
+
+// Transform 使用学到的最大绝对值缩放特征
+func (s *MaxAbsScaler) Transform(d *TrainingData) (*TrainingData, error) {
+	dataset, err := s.scaler.Transform(toDataset(d))
+	if err != nil {
+		return nil, err
+	}
+	return fromDataset(dataset, d), nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (s *MaxAbsScaler) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(s, d)
+}
+
+// FitSparse 计算稀疏矩阵各列的最大绝对值
+func (s *MaxAbsScaler) FitSparse(X *SparseMatrix) error {
+	if err := s.scaler.FitSparse(X); err != nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to fit scaler on sparse matrix",
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// TransformSparse 缩放稀疏矩阵的非零元素，返回非零结构相同的新矩阵
+func (s *MaxAbsScaler) TransformSparse(X *SparseMatrix) (*SparseMatrix, error) {
+	scaled, err := s.scaler.TransformSparse(X)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to scale sparse matrix",
+			Details: err.Error(),
+		}
+	}
+	return scaled, nil
+}
+
+// FitTransformSparse 结合FitSparse和TransformSparse一步完成
+func (s *MaxAbsScaler) FitTransformSparse(X *SparseMatrix) (*SparseMatrix, error) {
+	if err := s.FitSparse(X); err != nil {
+		return nil, err
+	}
+	return s.TransformSparse(X)
+}
+
+// PolynomialFeatures 生成原始特征及其2到Degree次的单项式特征，可作为流水线步骤。
+// n个特征共生成C(n+Degree, Degree)-1个特征（IncludeBias时再加常数项），列名如"x1^2*x2"
+type PolynomialFeatures struct {
+	Degree          int
+	InteractionOnly bool // 只生成不同特征的乘积，不含x1^2等幂次项
+	IncludeBias     bool // 在首列添加常数项1
+	nFeatures       int
+}
+
+// NewPolynomialFeatures 创建新的多项式特征变换器
+func NewPolynomialFeatures(degree int) (*PolynomialFeatures, error) {
+	if degree < 1 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "polynomial degree must be at least 1",
+		}
+	}
+	return &PolynomialFeatures{Degree: degree}, nil
+}
+
+// Fit 记录输入特征数量，变换本身不需要学习参数
+func (pf *PolynomialFeatures) Fit(d *TrainingData) error {
+	if d == nil || d.Features == nil {
+		return &Error{
+			Code:    ErrInvalidData,
+			Message: "features cannot be nil",
+		}
+	}
+	_, pf.nFeatures = d.Features.Dims()
+	return nil
+}
+
+// Transform 生成多项式特征
+func (pf *PolynomialFeatures) Transform(d *TrainingData) (*TrainingData, error) {
+	if pf.nFeatures == 0 {
+		return nil, &Error{
+			Code:    ErrModelNotTrained,
+			Message: "transformer is not fitted",
+		}
+	}
+	if _, c := d.Features.Dims(); c != pf.nFeatures {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: fmt.Sprintf("expected %d features, got %d", pf.nFeatures, c),
+		}
+	}
+
+	poly, err := data.NewPolynomialFeatures(pf.Degree)
+	if err != nil {
+		return nil, err
+	}
+	poly.InteractionOnly = pf.InteractionOnly
+	poly.IncludeBias = pf.IncludeBias
+	dataset, err := poly.Transform(toDataset(d))
+	if err != nil {
+		return nil, err
+	}
+	return fromDataset(dataset, d), nil
+}
+
+// FitTransform 结合Fit和Transform一步完成
+func (pf *PolynomialFeatures) FitTransform(d *TrainingData) (*TrainingData, error) {
+	return fitTransform(pf, d)
+}
+
+// fitTransform 依次调用Fit和Transform，供各变换器实现FitTransform
+func fitTransform(t Transformer, d *TrainingData) (*TrainingData, error) {
+	if err := t.Fit(d); err != nil {
+		return nil, err
+	}
+	return t.Transform(d)
+}
+
+// toDataset 将训练数据转换为internal包使用的数据集；目标变量为空时（如预测阶段）以0填充
+func toDataset(d *TrainingData) *types.Dataset {
+	if d == nil || d.Features == nil {
+		return nil
+	}
+
+	r, c := d.Features.Dims()
+	features := make([][]float64, r)
+	for i := 0; i < r; i++ {
+		features[i] = make([]float64, c)
+		for j := 0; j < c; j++ {
+			features[i][j] = d.Features.At(i, j)
+		}
+	}
+
+	target := make([]float64, r)
+	if d.Target != nil {
+		for i := 0; i < d.Target.Len() && i < r; i++ {
+			target[i] = d.Target.AtVec(i)
+		}
+	}
+
+	return types.NewDataset(features, target, columnNames(d))
+}
+
+// fromDataset 将变换后的数据集转换回训练数据，保留原始的目标变量
+func fromDataset(dataset *types.Dataset, original *TrainingData) *TrainingData {
+	r, c := dataset.NumSamples(), dataset.NumFeatures()
+	features := mat.NewDense(r, c, nil)
+	for i, row := range dataset.Features {
+		features.SetRow(i, row)
+	}
+
+	return &TrainingData{
+		Features:     features,
+		Target:       original.Target,
+		Weights:      original.Weights,
+		FeatureNames: dataset.FeatureNames,
+		TargetName:   original.TargetName,
+	}
+}
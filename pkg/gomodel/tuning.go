@@ -0,0 +1,313 @@
+package gomodel
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// ParamGrid 参数网格，键为参数名，值为该参数的候选取值列表
+type ParamGrid map[string][]interface{}
+
+// ParamDistribution 参数分布，随机搜索从中采样参数取值
+type ParamDistribution interface {
+	// Sample 使用给定随机源采样一个取值
+	Sample(rng *rand.Rand) interface{}
+}
+
+// ParamDistributions 参数分布集合，键为参数名
+type ParamDistributions map[string]ParamDistribution
+
+// Uniform 区间[Low, High)上的连续均匀分布
+type Uniform struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// Sample 实现ParamDistribution接口
+func (u Uniform) Sample(rng *rand.Rand) interface{} {
+	return u.Low + rng.Float64()*(u.High-u.Low)
+}
+
+// LogUniform 对数均匀分布，适合跨多个数量级的参数（如正则化强度），要求0 < Low < High
+type LogUniform struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// Sample 实现ParamDistribution接口
+func (l LogUniform) Sample(rng *rand.Rand) interface{} {
+	logLow, logHigh := math.Log(l.Low), math.Log(l.High)
+	return math.Exp(logLow + rng.Float64()*(logHigh-logLow))
+}
+
+// IntUniform 闭区间[Low, High]上的整数均匀分布
+type IntUniform struct {
+	Low  int `json:"low"`
+	High int `json:"high"`
+}
+
+// Sample 实现ParamDistribution接口
+func (u IntUniform) Sample(rng *rand.Rand) interface{} {
+	return u.Low + rng.Intn(u.High-u.Low+1)
+}
+
+// Choice 从候选取值中等概率选择
+type Choice []interface{}
+
+// Sample 实现ParamDistribution接口
+func (c Choice) Sample(rng *rand.Rand) interface{} {
+	return c[rng.Intn(len(c))]
+}
+
+// SearchResult 单个参数组合的交叉验证结果
+type SearchResult struct {
+	Parameters map[string]interface{} `json:"parameters"`
+	Scores     []float64              `json:"scores"`
+	MeanScore  float64                `json:"mean_score"`
+	StdScore   float64                `json:"std_score"`
+	Rank       int                    `json:"rank"`
+}
+
+// TuningResult 超参数搜索结果
+type TuningResult struct {
+	BestConfig     *ModelConfig           `json:"best_config"`
+	BestParameters map[string]interface{} `json:"best_parameters"`
+	BestScore      float64                `json:"best_score"`
+	Results        []*SearchResult        `json:"results"`
+}
+
+// GridSearch 对参数网格中的每一种组合执行交叉验证，返回最佳配置和完整结果表
+// config中的参数作为基础参数，网格中的参数会覆盖同名的基础参数；
+// validation为nil时使用客户端的默认验证配置
+func (c *Client) GridSearch(data *TrainingData, config *ModelConfig, grid ParamGrid, validation *ValidationConfig) (*TuningResult, error) {
+	if err := c.validateSearchInput(data, config); err != nil {
+		return nil, err
+	}
+
+	candidates, err := expandGrid(grid)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.searchCandidates(data, config, candidates, validation)
+}
+
+// RandomizedSearch 从参数分布中随机采样nIter组参数并执行交叉验证，
+// 适用于参数空间较大、无法穷举网格的场景；采样使用客户端的随机种子，结果可复现
+func (c *Client) RandomizedSearch(data *TrainingData, config *ModelConfig, distributions ParamDistributions, nIter int, validation *ValidationConfig) (*TuningResult, error) {
+	if err := c.validateSearchInput(data, config); err != nil {
+		return nil, err
+	}
+	if nIter <= 0 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "n_iter must be positive",
+		}
+	}
+	if err := validateDistributions(distributions); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(c.config.RandomSeed))
+	candidates := make([]map[string]interface{}, nIter)
+	for i := range candidates {
+		candidates[i] = sampleDistributions(distributions, rng)
+	}
+
+	return c.searchCandidates(data, config, candidates, validation)
+}
+
+// validateDistributions 检查参数分布定义是否有效
+func validateDistributions(distributions ParamDistributions) error {
+	if len(distributions) == 0 {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "parameter distributions cannot be empty",
+		}
+	}
+
+	for name, distribution := range distributions {
+		valid := true
+		switch d := distribution.(type) {
+		case nil:
+			valid = false
+		case Uniform:
+			valid = d.Low < d.High
+		case LogUniform:
+			valid = d.Low > 0 && d.Low < d.High
+		case IntUniform:
+			valid = d.Low <= d.High
+		case Choice:
+			valid = len(d) > 0
+		}
+		if !valid {
+			return &Error{
+				Code:    ErrInvalidParameters,
+				Message: fmt.Sprintf("invalid distribution for parameter %s", name),
+			}
+		}
+	}
+
+	return nil
+}
+
+// sampleDistributions 按参数名字典序依次采样，保证相同种子下结果稳定
+func sampleDistributions(distributions ParamDistributions, rng *rand.Rand) map[string]interface{} {
+	names := make([]string, 0, len(distributions))
+	for name := range distributions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	candidate := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		candidate[name] = distributions[name].Sample(rng)
+	}
+	return candidate
+}
+
+// validateSearchInput 校验搜索的数据和基础模型配置
+func (c *Client) validateSearchInput(data *TrainingData, config *ModelConfig) error {
+	if data == nil || config == nil {
+		return &Error{
+			Code:    ErrInvalidParameters,
+			Message: "training data and model config cannot be nil",
+		}
+	}
+
+	if err := c.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	return c.validateData(data)
+}
+
+// searchCandidates 依次评估候选参数组合，并按平均得分从高到低排名
+func (c *Client) searchCandidates(data *TrainingData, config *ModelConfig, candidates []map[string]interface{}, validation *ValidationConfig) (*TuningResult, error) {
+	validation = c.searchValidation(validation)
+
+	results := make([]*SearchResult, 0, len(candidates))
+	for _, candidate := range candidates {
+		result, err := c.evaluateCandidate(data, config, candidate, validation)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return rankResults(config, results), nil
+}
+
+// searchValidation 返回搜索使用的验证配置，未指定时回退到客户端默认配置
+func (c *Client) searchValidation(validation *ValidationConfig) *ValidationConfig {
+	if validation == nil {
+		validation = c.config.DefaultValidation
+	}
+	if validation == nil {
+		validation = GetDefaultValidationConfig()
+	}
+	return validation
+}
+
+// evaluateCandidate 使用候选参数覆盖基础配置并执行交叉验证
+func (c *Client) evaluateCandidate(data *TrainingData, config *ModelConfig, candidate map[string]interface{}, validation *ValidationConfig) (*SearchResult, error) {
+	candidateConfig := withParameters(config, candidate)
+	if err := c.validateAlgorithmParameters(candidateConfig.Algorithm, candidateConfig.Parameters); err != nil {
+		return nil, err
+	}
+
+	scores, err := c.validationScores(data, candidateConfig, validation)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrValidationFailed,
+			Message: fmt.Sprintf("failed to evaluate parameters %v", candidate),
+			Details: err.Error(),
+		}
+	}
+
+	mean, std := c.calculateStats(scores)
+	return &SearchResult{
+		Parameters: candidateConfig.Parameters,
+		Scores:     scores,
+		MeanScore:  mean,
+		StdScore:   std,
+	}, nil
+}
+
+// rankResults 按平均得分排序并填充排名，得分越高越好
+func rankResults(config *ModelConfig, results []*SearchResult) *TuningResult {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].MeanScore > results[j].MeanScore
+	})
+	for i, result := range results {
+		result.Rank = i + 1
+	}
+
+	best := results[0]
+	return &TuningResult{
+		BestConfig:     withParameters(config, best.Parameters),
+		BestParameters: best.Parameters,
+		BestScore:      best.MeanScore,
+		Results:        results,
+	}
+}
+
+// withParameters 复制模型配置，并用给定参数覆盖基础参数
+func withParameters(config *ModelConfig, overrides map[string]interface{}) *ModelConfig {
+	params := make(map[string]interface{}, len(config.Parameters)+len(overrides))
+	for key, value := range config.Parameters {
+		params[key] = value
+	}
+	for key, value := range overrides {
+		params[key] = value
+	}
+
+	return &ModelConfig{
+		Algorithm:    config.Algorithm,
+		Parameters:   params,
+		LossFunction: config.LossFunction,
+		Validation:   config.Validation,
+	}
+}
+
+// expandGrid 展开参数网格为所有参数组合（笛卡尔积），参数名按字典序遍历以保证结果稳定
+func expandGrid(grid ParamGrid) ([]map[string]interface{}, error) {
+	if len(grid) == 0 {
+		return nil, &Error{
+			Code:    ErrInvalidParameters,
+			Message: "parameter grid cannot be empty",
+		}
+	}
+
+	names := make([]string, 0, len(grid))
+	for name, values := range grid {
+		if len(values) == 0 {
+			return nil, &Error{
+				Code:    ErrInvalidParameters,
+				Message: fmt.Sprintf("parameter %s has no candidate values", name),
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combinations := []map[string]interface{}{{}}
+	for _, name := range names {
+		next := make([]map[string]interface{}, 0, len(combinations)*len(grid[name]))
+		for _, combination := range combinations {
+			for _, value := range grid[name] {
+				candidate := make(map[string]interface{}, len(combination)+1)
+				for key, v := range combination {
+					candidate[key] = v
+				}
+				candidate[name] = value
+				next = append(next, candidate)
+			}
+		}
+		combinations = next
+	}
+
+	return combinations, nil
+}
@@ -0,0 +1,223 @@
+package gomodel
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ridgeData 生成几乎无噪声的线性回归数据，第三个特征的尺度只有0.01、系数很大。
+// 正则化只会使系数偏离真实值，lambda越小验证得分越高，最优参数是已知的
+func ridgeData(n int) *TrainingData {
+	rng := rand.New(rand.NewSource(7))
+	X := mat.NewDense(n, 3, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		a, b, c := rng.NormFloat64(), rng.NormFloat64(), 0.01*rng.NormFloat64()
+		X.SetRow(i, []float64{a, b, c})
+		y.SetVec(i, 1+3*a-2*b+100*c+0.05*rng.NormFloat64())
+	}
+	return &TrainingData{Features: X, Target: y}
+}
+
+// searchClient 返回使用固定随机种子和3折交叉验证的客户端
+func searchClient(seed int64) *Client {
+	return NewClient(&ClientConfig{
+		DefaultValidation: &ValidationConfig{Method: "kfold", KFolds: 3, RandomSeed: 1, Workers: 1},
+		RandomSeed:        seed,
+	})
+}
+
+// checkRanking 检查结果按平均得分从高到低排名，且得分随lambda增大而降低
+func checkRanking(t *testing.T, result *TuningResult) {
+	t.Helper()
+	for i, r := range result.Results {
+		if r.Rank != i+1 {
+			t.Errorf("result %d has rank %d", i, r.Rank)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := result.Results[i-1]
+		if r.MeanScore > previous.MeanScore {
+			t.Errorf("rank %d score %g is higher than rank %d score %g", r.Rank, r.MeanScore, previous.Rank, previous.MeanScore)
+		}
+		if r.Parameters["lambda"].(float64) < previous.Parameters["lambda"].(float64) {
+			t.Errorf("lambda %v ranked below lambda %v", r.Parameters["lambda"], previous.Parameters["lambda"])
+		}
+	}
+	best := result.Results[0]
+	if !reflect.DeepEqual(result.BestParameters, best.Parameters) || result.BestScore != best.MeanScore {
+		t.Errorf("best = %v (%g), want the first ranked result %v (%g)", result.BestParameters, result.BestScore, best.Parameters, best.MeanScore)
+	}
+	if result.BestConfig.Parameters["lambda"] != best.Parameters["lambda"] {
+		t.Errorf("best config lambda = %v, want %v", result.BestConfig.Parameters["lambda"], best.Parameters["lambda"])
+	}
+}
+
+func TestGridSearchRanksKnownOptimum(t *testing.T) {
+	data := ridgeData(120)
+	config := &ModelConfig{Algorithm: Ridge, Parameters: map[string]interface{}{"lambda": 1.0}}
+	grid := ParamGrid{"lambda": {1000.0, 0.01, 1e5, 10.0}}
+
+	result, err := searchClient(1).GridSearch(data, config, grid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Results) != 4 {
+		t.Fatalf("got %d results, want 4", len(result.Results))
+	}
+	checkRanking(t, result)
+	if result.BestParameters["lambda"] != 0.01 {
+		t.Errorf("best lambda = %v, want 0.01", result.BestParameters["lambda"])
+	}
+	for _, r := range result.Results {
+		if len(r.Scores) != 3 {
+			t.Errorf("lambda %v: %d fold scores, want 3", r.Parameters["lambda"], len(r.Scores))
+		}
+	}
+	// 基础配置不被修改
+	if config.Parameters["lambda"] != 1.0 {
+		t.Errorf("base config lambda changed to %v", config.Parameters["lambda"])
+	}
+}
+
+func TestGridSearchDeterministic(t *testing.T) {
+	data := ridgeData(90)
+	config := &ModelConfig{Algorithm: Ridge}
+	grid := ParamGrid{"lambda": {0.1, 1.0, 10.0}}
+
+	first, err := searchClient(3).GridSearch(data, config, grid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := searchClient(3).GridSearch(data, config, grid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first.Results, second.Results) {
+		t.Error("grid search with the same seed returned different results")
+	}
+}
+
+func TestExpandGrid(t *testing.T) {
+	combinations, err := expandGrid(ParamGrid{"b": {1, 2, 3}, "a": {"x", "y"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, c := range combinations {
+		keys = append(keys, c["a"].(string)+string(rune('0'+c["b"].(int))))
+	}
+	// 参数名按字典序展开，后面的参数变化最快
+	want := []string{"x1", "x2", "x3", "y1", "y2", "y3"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("combinations = %v, want %v", keys, want)
+	}
+
+	for _, grid := range []ParamGrid{{}, {"lambda": {}}} {
+		if _, err := expandGrid(grid); err == nil {
+			t.Errorf("grid %v: expected an error", grid)
+		}
+	}
+}
+
+func TestSearchInvalidInput(t *testing.T) {
+	client := searchClient(1)
+	data := ridgeData(30)
+	config := &ModelConfig{Algorithm: Ridge}
+
+	if _, err := client.GridSearch(nil, config, ParamGrid{"lambda": {1.0}}, nil); err == nil {
+		t.Error("expected an error for nil data")
+	}
+	if _, err := client.GridSearch(data, config, ParamGrid{"lambda": {-1.0}}, nil); err == nil {
+		t.Error("expected an error for a negative lambda")
+	}
+}
+
+func TestRandomizedSearch(t *testing.T) {
+	data := ridgeData(120)
+	config := &ModelConfig{Algorithm: Ridge}
+	distributions := ParamDistributions{"lambda": LogUniform{Low: 1e-3, High: 1e4}}
+
+	result, err := searchClient(5).RandomizedSearch(data, config, distributions, 8, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Results) != 8 {
+		t.Fatalf("got %d results, want 8", len(result.Results))
+	}
+	checkRanking(t, result)
+
+	// 候选按客户端种子依次采样，最佳参数是其中最小的lambda
+	rng := rand.New(rand.NewSource(5))
+	var sampled []float64
+	for i := 0; i < 8; i++ {
+		lambda := sampleDistributions(distributions, rng)["lambda"].(float64)
+		if lambda < 1e-3 || lambda > 1e4 {
+			t.Errorf("sampled lambda %g outside [1e-3, 1e4]", lambda)
+		}
+		sampled = append(sampled, lambda)
+	}
+	if got := sortedLambdas(result.Results); !reflect.DeepEqual(got, sortedFloats(sampled)) {
+		t.Errorf("evaluated lambdas = %v, want the seeded samples %v", got, sortedFloats(sampled))
+	}
+
+	same, err := searchClient(5).RandomizedSearch(data, config, distributions, 8, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Results, same.Results) {
+		t.Error("randomized search with the same seed returned different results")
+	}
+	other, err := searchClient(6).RandomizedSearch(data, config, distributions, 8, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(sortedLambdas(result.Results), sortedLambdas(other.Results)) {
+		t.Error("randomized search with a different seed sampled the same parameters")
+	}
+}
+
+func TestRandomizedSearchInvalidInput(t *testing.T) {
+	client := searchClient(1)
+	data := ridgeData(30)
+	config := &ModelConfig{Algorithm: Ridge}
+
+	tests := []struct {
+		name          string
+		distributions ParamDistributions
+		nIter         int
+	}{
+		{"zero iterations", ParamDistributions{"lambda": Uniform{Low: 0, High: 1}}, 0},
+		{"empty distributions", ParamDistributions{}, 5},
+		{"empty range", ParamDistributions{"lambda": Uniform{Low: 1, High: 1}}, 5},
+		{"non-positive log range", ParamDistributions{"lambda": LogUniform{Low: 0, High: 1}}, 5},
+		{"empty choice", ParamDistributions{"lambda": Choice{}}, 5},
+		{"nil distribution", ParamDistributions{"lambda": nil}, 5},
+	}
+	for _, tc := range tests {
+		if _, err := client.RandomizedSearch(data, config, tc.distributions, tc.nIter, nil); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+// sortedLambdas 返回结果中出现的lambda，从小到大排列
+func sortedLambdas(results []*SearchResult) []float64 {
+	lambdas := make([]float64, len(results))
+	for i, r := range results {
+		lambdas[i] = r.Parameters["lambda"].(float64)
+	}
+	return sortedFloats(lambdas)
+}
+
+// sortedFloats 返回排序后的副本
+func sortedFloats(values []float64) []float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted
+}
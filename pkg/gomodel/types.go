@@ -12,6 +12,8 @@ const (
 	OLS       AlgorithmType = "ols"
 	Ridge     AlgorithmType = "ridge"
 	Lasso     AlgorithmType = "lasso"
+	RidgeCV   AlgorithmType = "ridge_cv" // 交叉验证自动选择lambda的岭回归
+	LassoCV   AlgorithmType = "lasso_cv" // 交叉验证自动选择lambda的Lasso回归
 	Logistic  AlgorithmType = "logistic"
 	PLS       AlgorithmType = "pls"
 	
@@ -20,6 +22,14 @@ const (
 	Exponential AlgorithmType = "exponential"
 	Logarithmic AlgorithmType = "logarithmic"
 	Power       AlgorithmType = "power"
+
+	// 集成模型
+	Stacking AlgorithmType = "stacking" // 参数base_models为[]*ModelConfig，meta_model为*ModelConfig
+	Voting   AlgorithmType = "voting"   // 参数models为[]*ModelConfig，weights为[]float64
+	Bagging  AlgorithmType = "bagging"  // 参数base_model为*ModelConfig
+
+	// 概率校准
+	Calibrated AlgorithmType = "calibrated" // 参数base_model为*ModelConfig，method为"sigmoid"或"isotonic"
 )
 
 // LossFunction 定义损失函数类型
@@ -48,26 +58,34 @@ type ValidationConfig struct {
 	TestSize   float64 `json:"test_size"`   // 测试集比例 (0-1)
 	KFolds     int     `json:"k_folds"`     // K折交叉验证的K值
 	RandomSeed int64   `json:"random_seed"` // 随机种子
+	Stratify   bool    `json:"stratify"`    // 按目标类别分层划分（用于分类）；分类模型的kfold总是分层
+	Splitter   Splitter `json:"-"`          // 自定义划分器（如TimeSeriesSplit），设置后kfold按其划分，忽略KFolds
+	Workers    int     `json:"workers"`     // kfold并发训练和评估的折数，不大于0时使用CPU核数，为1时串行
+	Scoring    string  `json:"scoring,omitempty"` // 验证得分的评分方式（Scoring*常量），为空时按LossFunction选择
+	ScoreFunc  ScoreFunc `json:"-"`        // 自定义评分函数，设置后忽略Scoring
 }
 
 // TrainingData 训练数据结构
 type TrainingData struct {
 	Features *mat.Dense `json:"-"`        // 特征矩阵
 	Target   *mat.VecDense `json:"-"`     // 目标变量
+	Weights  *mat.VecDense `json:"-"`     // 样本权重（可选），为nil时所有样本等权
 	FeatureNames []string `json:"feature_names,omitempty"`
 	TargetName   string   `json:"target_name,omitempty"`
+	Categories   map[string][]string `json:"categories,omitempty"` // 类别特征的类别表，这些列的值为标签在表中的下标
 }
 
 // PredictionResult 预测结果
 type PredictionResult struct {
 	Predictions    []float64              `json:"predictions"`
 	Probabilities  [][]float64            `json:"probabilities,omitempty"` // 分类概率
-	Confidence     []float64              `json:"confidence,omitempty"`    // 置信度
+	Confidence     []PredictionInterval   `json:"confidence,omitempty"`    // 每个样本的置信区间和预测区间，由PredictWithIntervals填充
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // ModelResult 模型训练和评估结果
 type ModelResult struct {
+	ModelID        string                 `json:"model_id"`
 	Algorithm      AlgorithmType          `json:"algorithm"`
 	Parameters     map[string]interface{} `json:"parameters"`
 	TrainingScore  float64                `json:"training_score"`
@@ -76,6 +94,16 @@ type ModelResult struct {
 	Metrics        map[string]float64     `json:"metrics"`
 	ModelInfo      map[string]interface{} `json:"model_info"`
 	CrossValidation *CVResult             `json:"cross_validation,omitempty"`
+	Convergence    *ConvergenceInfo       `json:"convergence,omitempty"` // 迭代求解器的收敛信息
+	Inference      *InferenceResult       `json:"inference,omitempty"`   // OLS、Ridge和Logistic系数的标准误、检验统计量和p值
+}
+
+// ConvergenceInfo 迭代求解器（如逻辑回归的L-BFGS）的收敛信息
+type ConvergenceInfo struct {
+	Solver       string  `json:"solver"`
+	Iterations   int     `json:"iterations"`
+	GradientNorm float64 `json:"gradient_norm"` // 训练结束时梯度的无穷范数
+	Converged    bool    `json:"converged"`
 }
 
 // CVResult 交叉验证结果
@@ -103,6 +131,11 @@ type DataPreprocessConfig struct {
 	HandleMissing string  `json:"handle_missing"` // "drop", "mean", "median", "mode"
 	OutlierMethod string  `json:"outlier_method"` // "iqr", "zscore", "none"
 	OutlierThreshold float64 `json:"outlier_threshold"`
+	Deduplicate        bool    `json:"deduplicate"`         // 移除重复行
+	DuplicateTolerance float64 `json:"duplicate_tolerance"` // 近似重复的容差，0表示完全相同
+	Resample       string  `json:"resample"`        // "none", "oversample", "smote", "undersample", "nearmiss"
+	ResampleRatio  float64 `json:"resample_ratio"`  // 少数类样本数相对最多类别的目标比例，<=0时为1
+	SMOTENeighbors int     `json:"smote_neighbors"` // SMOTE的近邻数，<=0时为5
 }
 
 // Error 自定义错误类型
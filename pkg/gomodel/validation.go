@@ -0,0 +1,119 @@
+package gomodel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/feiyuluoye/Go-Model/internal/data"
+	"github.com/feiyuluoye/Go-Model/internal/types"
+	"gonum.org/v1/gonum/mat"
+)
+
+// 数据质量问题的类型
+const (
+	IssueRowMismatch   = data.IssueRowMismatch   // 特征行数、目标变量或样本权重的长度不一致
+	IssueMissing       = data.IssueMissing       // 含缺失值(NaN)
+	IssueInfinite      = data.IssueInfinite      // 含无穷值(±Inf)
+	IssueConstant      = data.IssueConstant      // 特征为常数
+	IssueDuplicate     = data.IssueDuplicate     // 特征与另一列完全相同
+	IssueTargetLeakage = data.IssueTargetLeakage // 特征与目标变量完全相同
+)
+
+// QualityIssue 数据中发现的一个问题
+type QualityIssue struct {
+	Type    string `json:"type"`
+	Column  string `json:"column,omitempty"`  // 相关的特征，为空时表示目标变量或整个数据集
+	Related string `json:"related,omitempty"` // 重复列问题中与之相同的另一列
+	Count   int    `json:"count,omitempty"`   // 缺失值或无穷值的个数
+	Fatal   bool   `json:"fatal"`             // 为true时拒绝在该数据上训练
+	Message string `json:"message"`
+}
+
+// QualityReport 数据质量检查结果
+type QualityReport struct {
+	Samples  int            `json:"samples"`
+	Features int            `json:"features"`
+	Issues   []QualityIssue `json:"issues"`
+	Valid    bool           `json:"valid"` // 没有致命问题
+}
+
+// Err 数据存在致命问题时返回汇总了这些问题的错误，否则返回nil
+func (r *QualityReport) Err() error {
+	if r.Valid {
+		return nil
+	}
+	var messages []string
+	for _, issue := range r.Issues {
+		if issue.Fatal {
+			messages = append(messages, issue.Message)
+		}
+	}
+	return &Error{
+		Code:    ErrInvalidData,
+		Message: fmt.Sprintf("data failed validation with %d fatal issue(s)", len(messages)),
+		Details: strings.Join(messages, "; "),
+	}
+}
+
+// Validator 训练前的数据质量检查器。行数不一致、无穷值、目标变量缺失和目标泄漏（特征与目标变量完全相同）
+// 总是致命问题；特征缺失值默认致命，常数列和重复列默认只作为告警
+type Validator struct {
+	AllowMissing    bool `json:"allow_missing"`    // 特征缺失值不致命，如流水线中会先填充
+	RejectConstant  bool `json:"reject_constant"`  // 常数列为致命问题
+	RejectDuplicate bool `json:"reject_duplicate"` // 重复列为致命问题
+}
+
+// NewValidator 创建默认的数据质量检查器
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// Validate 检查训练数据的缺失值、无穷值、常数列、重复列、目标泄漏以及行数是否一致，返回质量报告。
+// 报告中存在致命问题时可以通过Err得到对应的错误
+func (v *Validator) Validate(d *TrainingData) (*QualityReport, error) {
+	if d == nil || d.Features == nil || d.Target == nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "features and target cannot be nil",
+		}
+	}
+
+	r, _ := d.Features.Dims()
+	features := make([][]float64, r)
+	for i := range features {
+		features[i] = mat.Row(nil, i, d.Features)
+	}
+	dataset := &types.Dataset{
+		Features:     features,
+		Target:       mat.Col(nil, 0, d.Target),
+		FeatureNames: columnNames(d),
+	}
+	if d.Weights != nil {
+		dataset.Weights = mat.Col(nil, 0, d.Weights)
+	}
+
+	validator := &data.Validator{
+		AllowMissing:    v.AllowMissing,
+		RejectConstant:  v.RejectConstant,
+		RejectDuplicate: v.RejectDuplicate,
+	}
+	report, err := validator.Validate(dataset)
+	if err != nil {
+		return nil, &Error{
+			Code:    ErrInvalidData,
+			Message: "failed to validate data",
+			Details: err.Error(),
+		}
+	}
+
+	issues := make([]QualityIssue, len(report.Issues))
+	for i, issue := range report.Issues {
+		issues[i] = QualityIssue(issue)
+	}
+	return &QualityReport{
+		Samples:  report.Samples,
+		Features: report.Features,
+		Issues:   issues,
+		Valid:    report.Valid,
+	}, nil
+}